@@ -183,6 +183,8 @@ type Config struct {
 	// 1. there is a network partition problem between TiDB and PD leader.
 	// 2. there is a network partition problem between TiDB and TiKV leader.
 	EnableForwarding bool `toml:"enable-forwarding" json:"enable-forwarding"`
+	// ProtocolCompression is the config for the compression of the MySQL client/server protocol.
+	ProtocolCompression ProtocolCompression `toml:"protocol-compression" json:"protocol-compression"`
 }
 
 // UpdateTempStoragePath is to update the `TempStoragePath` if port/statusPort was changed
@@ -367,6 +369,11 @@ type Security struct {
 	SpilledFileEncryptionMethod string `toml:"spilled-file-encryption-method" json:"spilled-file-encryption-method"`
 	// EnableSEM prevents SUPER users from having full access.
 	EnableSEM bool `toml:"enable-sem" json:"enable-sem"`
+	// DefaultAuthPlugin is the authentication plugin advertised to clients and used by CREATE USER if not specified.
+	DefaultAuthPlugin string `toml:"default-authentication-plugin" json:"default-authentication-plugin"`
+	// CachingSha2PrivateKey is the path of the RSA private key used by caching_sha2_password to exchange
+	// the password over insecure connections, a temporary key is generated if it's empty.
+	CachingSha2PrivateKey string `toml:"caching-sha2-password-private-key" json:"caching-sha2-password-private-key"`
 }
 
 // The ErrConfigValidationFailed error is used so that external callers can do a type assertion
@@ -480,6 +487,25 @@ type ProxyProtocol struct {
 	HeaderTimeout uint `toml:"header-timeout" json:"header-timeout"`
 }
 
+// The compression algorithms of the MySQL client/server protocol.
+const (
+	// CompressionZlib compresses the packets with zlib, it's negotiated by the `CLIENT_COMPRESS` capability.
+	CompressionZlib = "zlib"
+	// CompressionZstd compresses the packets with zstd, it's negotiated by the `CLIENT_ZSTD_COMPRESSION_ALGORITHM` capability.
+	CompressionZstd = "zstd"
+)
+
+// ProtocolCompression is the MySQL protocol compression section of the config.
+type ProtocolCompression struct {
+	// Algorithms are the compression algorithms the clients are allowed to use.
+	// Empty means the compression is disabled.
+	Algorithms []string `toml:"algorithms" json:"algorithms"`
+	// ZlibLevel is the compression level of zlib, 1 is the fastest and 9 is the best compression.
+	ZlibLevel int `toml:"zlib-level" json:"zlib-level"`
+	// ZstdLevel is the compression level of zstd, it's used when the client doesn't request a valid level.
+	ZstdLevel int `toml:"zstd-level" json:"zstd-level"`
+}
+
 // Binlog is the config for binlog.
 type Binlog struct {
 	Enable bool `toml:"enable" json:"enable"`
@@ -639,6 +665,11 @@ var defaultConf = Config{
 		Networks:      "",
 		HeaderTimeout: 5,
 	},
+	ProtocolCompression: ProtocolCompression{
+		Algorithms: []string{CompressionZlib, CompressionZstd},
+		ZlibLevel:  6,
+		ZstdLevel:  3,
+	},
 	PreparedPlanCache: PreparedPlanCache{
 		Enabled:          false,
 		Capacity:         100,
@@ -686,6 +717,7 @@ var defaultConf = Config{
 	Security: Security{
 		SpilledFileEncryptionMethod: SpilledFileEncryptionMethodPlaintext,
 		EnableSEM:                   false,
+		DefaultAuthPlugin:           mysql.AuthNativePassword,
 	},
 	DeprecateIntegerDisplayWidth: false,
 	EnableEnumLengthLimit:        true,
@@ -905,6 +937,18 @@ func (c *Config) Valid() error {
 		return fmt.Errorf("refresh-interval in [stmt-summary] should be greater than 0")
 	}
 
+	for _, algorithm := range c.ProtocolCompression.Algorithms {
+		if algorithm != CompressionZlib && algorithm != CompressionZstd {
+			return fmt.Errorf("algorithms in [protocol-compression] can't be %v should be one of %v or %v", algorithm, CompressionZlib, CompressionZstd)
+		}
+	}
+	if c.ProtocolCompression.ZlibLevel < 1 || c.ProtocolCompression.ZlibLevel > 9 {
+		return fmt.Errorf("zlib-level in [protocol-compression] should be [1, 9]")
+	}
+	if c.ProtocolCompression.ZstdLevel < 1 || c.ProtocolCompression.ZstdLevel > 22 {
+		return fmt.Errorf("zstd-level in [protocol-compression] should be [1, 22]")
+	}
+
 	if c.PreparedPlanCache.Capacity < 1 {
 		return fmt.Errorf("capacity in [prepared-plan-cache] should be at least 1")
 	}
@@ -928,6 +972,13 @@ func (c *Config) Valid() error {
 		return fmt.Errorf("unsupported [security]spilled-file-encryption-method %v, TiDB only supports [%v, %v]",
 			c.Security.SpilledFileEncryptionMethod, SpilledFileEncryptionMethodPlaintext, SpilledFileEncryptionMethodAES128CTR)
 	}
+	c.Security.DefaultAuthPlugin = strings.ToLower(c.Security.DefaultAuthPlugin)
+	switch c.Security.DefaultAuthPlugin {
+	case mysql.AuthNativePassword, mysql.AuthCachingSha2Password:
+	default:
+		return fmt.Errorf("unsupported [security]default-authentication-plugin %v, TiDB only supports [%v, %v]",
+			c.Security.DefaultAuthPlugin, mysql.AuthNativePassword, mysql.AuthCachingSha2Password)
+	}
 
 	// test log level
 	l := zap.NewAtomicLevel()
@@ -598,6 +598,21 @@ func (s *testConfigSuite) TestSecurityValid(c *C) {
 		c1.Security.SpilledFileEncryptionMethod = tt.spilledFileEncryptionMethod
 		c.Assert(c1.Valid() == nil, Equals, tt.valid)
 	}
+
+	c1 = NewConfig()
+	authPluginTests := []struct {
+		defaultAuthPlugin string
+		valid             bool
+	}{
+		{"", false},
+		{"mysql_native_password", true},
+		{"Caching_Sha2_Password", true},
+		{"sha256_password", false},
+	}
+	for _, tt := range authPluginTests {
+		c1.Security.DefaultAuthPlugin = tt.defaultAuthPlugin
+		c.Assert(c1.Valid() == nil, Equals, tt.valid)
+	}
 }
 
 func (s *testConfigSuite) TestTcpNoDelay(c *C) {
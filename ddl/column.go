@@ -66,6 +66,8 @@ func adjustColumnInfoInAddColumn(tblInfo *model.TableInfo, offset int) {
 		newCols[i].Offset = i
 	}
 	newCols[offset].Offset = offset
+	// The index which is added together with the column refers to the last offset.
+	offsetChanged[len(oldCols)-1] = offset
 	// Update index column offset info.
 	// TODO: There may be some corner cases for index column offsets, we may check this later.
 	for _, idx := range tblInfo.Indices {
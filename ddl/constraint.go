@@ -0,0 +1,364 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+func allocateConstraintID(tblInfo *model.TableInfo) int64 {
+	tblInfo.MaxConstraintID++
+	return tblInfo.MaxConstraintID
+}
+
+// buildCheckConstraints builds the check constraints of a new table, the columns of the table must be built in advance.
+func buildCheckConstraints(ctx sessionctx.Context, tblInfo *model.TableInfo, constrs []*ast.Constraint) error {
+	constrNames := make(map[string]bool, len(constrs))
+	for _, constr := range constrs {
+		if constr.Name == "" {
+			continue
+		}
+		name := strings.ToLower(constr.Name)
+		if constrNames[name] {
+			return ErrCheckConstraintDupName.GenWithStackByArgs(constr.Name)
+		}
+		constrNames[name] = true
+	}
+	setEmptyCheckConstraintNames(tblInfo.Name, constrNames, constrs)
+
+	for _, constr := range constrs {
+		if err := checkCheckConstraint(ctx, tblInfo, constr); err != nil {
+			return errors.Trace(err)
+		}
+		constrInfo, err := buildConstraintInfo(tblInfo, constr, model.StatePublic)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		constrInfo.ID = allocateConstraintID(tblInfo)
+		tblInfo.Constraints = append(tblInfo.Constraints, constrInfo)
+	}
+	return nil
+}
+
+// setEmptyCheckConstraintNames names the check constraints without names like MySQL does, that is `<table>_chk_<n>`.
+func setEmptyCheckConstraintNames(tblName model.CIStr, constrNames map[string]bool, constrs []*ast.Constraint) {
+	cnt := 1
+	for _, constr := range constrs {
+		if constr.Name != "" {
+			continue
+		}
+		constrName := fmt.Sprintf("%s_chk_%d", tblName.L, cnt)
+		for constrNames[constrName] {
+			cnt++
+			constrName = fmt.Sprintf("%s_chk_%d", tblName.L, cnt)
+		}
+		constr.Name = constrName
+		constrNames[constrName] = true
+		cnt++
+	}
+}
+
+// checkCheckConstraint checks whether the expression of the check constraint is valid on the table.
+func checkCheckConstraint(ctx sessionctx.Context, tblInfo *model.TableInfo, constr *ast.Constraint) error {
+	if err := checkIllegalFn4Generated(constr.Name, typeCheckConstraint, constr.Expr); err != nil {
+		return errors.Trace(err)
+	}
+	for _, colName := range findColumnNamesInExpr(constr.Expr) {
+		if constr.InColumn && colName.Name.L != strings.ToLower(constr.InColumnName) {
+			return ErrColumnCheckConstraintReferencesOtherColumn.GenWithStackByArgs(constr.Name)
+		}
+		col := model.FindColumnInfo(tblInfo.Columns, colName.Name.L)
+		if col == nil || col.State != model.StatePublic {
+			return ErrTableCheckConstraintReferUnknown.GenWithStackByArgs(constr.Name, colName.Name.O)
+		}
+		if mysql.HasAutoIncrementFlag(col.Flag) {
+			return ErrCheckConstraintRefersAutoIncrementColumn.GenWithStackByArgs(constr.Name)
+		}
+	}
+	// Make sure the expression can be built on the table.
+	_, err := expression.RewriteSimpleExprWithTableInfo(ctx, tblInfo, constr.Expr)
+	return errors.Trace(err)
+}
+
+func buildConstraintInfo(tblInfo *model.TableInfo, constr *ast.Constraint, state model.SchemaState) (*model.ConstraintInfo, error) {
+	var sb strings.Builder
+	restoreFlags := format.RestoreStringSingleQuotes | format.RestoreKeyWordLowercase | format.RestoreNameBackQuotes |
+		format.RestoreSpacesAroundBinaryOperation
+	if err := constr.Expr.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	colNames := make(map[string]struct{})
+	constrCols := make([]model.CIStr, 0, 1)
+	for _, colName := range findColumnNamesInExpr(constr.Expr) {
+		if _, ok := colNames[colName.Name.L]; ok {
+			continue
+		}
+		colNames[colName.Name.L] = struct{}{}
+		col := model.FindColumnInfo(tblInfo.Columns, colName.Name.L)
+		constrCols = append(constrCols, col.Name)
+	}
+
+	return &model.ConstraintInfo{
+		Name:           model.NewCIStr(constr.Name),
+		Table:          tblInfo.Name,
+		ConstraintCols: constrCols,
+		Enforced:       constr.Enforced,
+		InColumn:       constr.InColumn,
+		ExprString:     sb.String(),
+		State:          state,
+	}, nil
+}
+
+// checkColumnNotUsedByCheckConstraints checks whether the column is used by any check constraint,
+// such column can't be dropped or renamed.
+func checkColumnNotUsedByCheckConstraints(tblInfo *model.TableInfo, colName model.CIStr) error {
+	for _, constrInfo := range tblInfo.Constraints {
+		for _, col := range constrInfo.ConstraintCols {
+			if col.L == colName.L {
+				return ErrDependentByCheckConstraint.GenWithStackByArgs(constrInfo.Name.O, colName.O)
+			}
+		}
+	}
+	return nil
+}
+
+func removeCheckConstraint(tblInfo *model.TableInfo, constrName model.CIStr) {
+	for i, constrInfo := range tblInfo.Constraints {
+		if constrInfo.Name.L == constrName.L {
+			tblInfo.Constraints = append(tblInfo.Constraints[:i], tblInfo.Constraints[i+1:]...)
+			return
+		}
+	}
+}
+
+func (w *worker) onAddCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	if job.IsRollingback() {
+		return rollingbackAddCheckConstraint(t, job)
+	}
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	constrInfo := &model.ConstraintInfo{}
+	if err = job.DecodeArgs(constrInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	constrInfoInMeta := tblInfo.FindConstraintInfoByName(constrInfo.Name.L)
+	if constrInfoInMeta == nil {
+		// The constraint is added for the first time.
+		constrInfo.ID = allocateConstraintID(tblInfo)
+		constrInfo.State = model.StateNone
+		tblInfo.Constraints = append(tblInfo.Constraints, constrInfo)
+		constrInfoInMeta = constrInfo
+	} else if constrInfoInMeta.State == model.StatePublic {
+		job.State = model.JobStateCancelled
+		return ver, ErrCheckConstraintDupName.GenWithStackByArgs(constrInfo.Name.O)
+	}
+
+	originalState := constrInfoInMeta.State
+	switch constrInfoInMeta.State {
+	case model.StateNone:
+		// The new rows are checked since the write only state.
+		job.SchemaState = model.StateWriteOnly
+		constrInfoInMeta.State = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != constrInfoInMeta.State)
+	case model.StateWriteOnly:
+		if constrInfoInMeta.Enforced {
+			if err = w.verifyRemainRecordsForCheckConstraint(dbInfo, tblInfo, constrInfoInMeta); err != nil {
+				if table.ErrCheckConstraintViolated.Equal(err) {
+					job.State = model.JobStateRollingback
+				}
+				return ver, errors.Trace(err)
+			}
+		}
+		constrInfoInMeta.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != constrInfoInMeta.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("constraint", constrInfoInMeta.State)
+	}
+	return ver, errors.Trace(err)
+}
+
+// rollingbackAddCheckConstraint removes the adding check constraint when the existing rows violate it,
+// or the job is cancelled.
+func rollingbackAddCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	constrInfo := &model.ConstraintInfo{}
+	if err = job.DecodeArgs(constrInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	constrInfoInMeta := tblInfo.FindConstraintInfoByName(constrInfo.Name.L)
+	if constrInfoInMeta == nil || constrInfoInMeta.State == model.StatePublic {
+		// The constraint isn't added yet, or it's added by another job.
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	}
+	removeCheckConstraint(tblInfo, constrInfoInMeta.Name)
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	return ver, nil
+}
+
+func onDropCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	var constrName model.CIStr
+	if err = job.DecodeArgs(&constrName); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	if tblInfo.FindConstraintInfoByName(constrName.L) == nil {
+		job.State = model.JobStateCancelled
+		return ver, ErrConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	// The constraint only restricts the new rows, so it can be dropped in one step.
+	removeCheckConstraint(tblInfo, constrName)
+	ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+	return ver, nil
+}
+
+func (w *worker) onAlterCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	var (
+		constrName model.CIStr
+		enforced   bool
+	)
+	if err = job.DecodeArgs(&constrName, &enforced); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	constrInfo := tblInfo.FindConstraintInfoByName(constrName.L)
+	if constrInfo == nil {
+		job.State = model.JobStateCancelled
+		return ver, ErrConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	if job.IsRollingback() {
+		// The existing rows violate the constraint, so it can't be enforced.
+		constrInfo.Enforced = false
+		constrInfo.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateRollbackDone, model.StatePublic, ver, tblInfo)
+		return ver, nil
+	}
+
+	if !enforced {
+		constrInfo.Enforced = false
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		return ver, nil
+	}
+
+	// Enforcing a constraint is like adding it: check the new rows first, then verify the existing rows.
+	switch constrInfo.State {
+	case model.StatePublic:
+		job.SchemaState = model.StateWriteOnly
+		constrInfo.State = model.StateWriteOnly
+		constrInfo.Enforced = true
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
+	case model.StateWriteOnly:
+		if err = w.verifyRemainRecordsForCheckConstraint(dbInfo, tblInfo, constrInfo); err != nil {
+			if table.ErrCheckConstraintViolated.Equal(err) {
+				job.State = model.JobStateRollingback
+			}
+			return ver, errors.Trace(err)
+		}
+		constrInfo.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("constraint", constrInfo.State)
+	}
+	return ver, errors.Trace(err)
+}
+
+// verifyRemainRecordsForCheckConstraint checks whether the existing rows of the table satisfy the check constraint.
+func (w *worker) verifyRemainRecordsForCheckConstraint(dbInfo *model.DBInfo, tblInfo *model.TableInfo, constrInfo *model.ConstraintInfo) error {
+	var ctx sessionctx.Context
+	ctx, err := w.sessPool.get()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer w.sessPool.put(ctx)
+
+	// Since the expression may contain the identifiers, which couldn't be escaped in ParseWithParams(...),
+	// it's written to the origin sql string here, with the '%' in it escaped.
+	sql := "select 1 from %n.%n where not (" + strings.ReplaceAll(constrInfo.ExprString, "%", "%%") + ") limit 1"
+	stmt, err := ctx.(sqlexec.RestrictedSQLExecutor).ParseWithParams(context.Background(), sql, dbInfo.Name.L, tblInfo.Name.L)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rows, _, err := ctx.(sqlexec.RestrictedSQLExecutor).ExecRestrictedStmt(context.Background(), stmt)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rows) > 0 {
+		return table.ErrCheckConstraintViolated.GenWithStackByArgs(constrInfo.Name.O)
+	}
+	return nil
+}
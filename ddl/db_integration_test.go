@@ -592,7 +592,7 @@ func (s *testIntegrationSuite5) TestErrnoErrorCode(c *C) {
 	tk.MustExec("alter table test_drop_columns add column c2 int first, add column c3 int after c1")
 	sql = "alter table test_drop_columns drop column c1, drop column c2, drop column c3;"
 	tk.MustGetErrCode(sql, errno.ErrCantRemoveAllFields)
-	sql = "alter table test_drop_columns drop column c1, add column c2 int;"
+	sql = "alter table test_drop_columns drop column c1, modify column c2 bigint;"
 	tk.MustGetErrCode(sql, errno.ErrUnsupportedDDLOperation)
 	sql = "alter table test_drop_columns drop column c1, drop column c1;"
 	tk.MustGetErrCode(sql, errno.ErrCantDropFieldOrKey)
@@ -2377,6 +2377,69 @@ func (s *testSerialDBSuite1) TestAddExpressionIndexOnPartition(c *C) {
 	tk.MustQuery("select * from t order by a;").Check(testkit.Rows("1 test 2", "12 test 3", "15 test 10", "20 test 20"))
 }
 
+func (s *testSerialDBSuite1) TestMultiValuedIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, j json, key mvi ((cast(j->'$.a' as signed array))))")
+	tk.MustExec(`insert into t values (1, '{"a": [1, 2, 2]}'), (2, '{"a": [2, 3]}'), (3, '{"a": 4}'), (4, '{"a": []}'), (5, '{"b": 1}')`)
+	tk.MustExec("alter table t add index mvi2 ((cast(j->'$.a' as unsigned array)))")
+	tk.MustExec("admin check table t")
+
+	tblInfo, err := s.dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	c.Assert(tblInfo.Meta().FindIndexByName("mvi").MVIndex, IsTrue)
+	c.Assert(tblInfo.Meta().FindIndexByName("mvi2").MVIndex, IsTrue)
+
+	// The index has an entry for each distinct element of the array.
+	tk.MustQuery("select count(*) from t use index(mvi) where 2 member of (j->'$.a')").Check(testkit.Rows("2"))
+	c.Assert(tk.MustUseIndex("select id from t where 2 member of (j->'$.a')", "mvi"), IsTrue)
+	tk.MustQuery("select id from t where 2 member of (j->'$.a') order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id from t where 4 member of (j->'$.a')").Check(testkit.Rows("3"))
+	tk.MustQuery("select id from t where '2' member of (j->'$.a')").Check(testkit.Rows())
+	c.Assert(tk.MustUseIndex("select id from t where json_contains(j->'$.a', '[1, 2]')", "mvi"), IsTrue)
+	tk.MustQuery("select id from t where json_contains(j->'$.a', '[1, 2]')").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where json_contains(j->'$.a', '[]') order by id").Check(testkit.Rows("1", "2", "4"))
+	c.Assert(tk.HasPlan("select id from t where json_overlaps(j->'$.a', '[1, 3, 4]')", "IndexMerge"), IsTrue)
+	tk.MustQuery("select id from t where json_overlaps('[1, 3, 4]', j->'$.a') order by id").Check(testkit.Rows("1", "2", "3"))
+
+	// The entries are maintained with the rows.
+	tk.MustExec(`update t set j = '{"a": [5, 6]}' where id = 1`)
+	tk.MustExec("delete from t where id = 2")
+	tk.MustExec(`insert into t values (6, '{"a": [6]}')`)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select id from t where 2 member of (j->'$.a')").Check(testkit.Rows())
+	tk.MustQuery("select id from t where json_overlaps(j->'$.a', '[3, 6]') order by id").Check(testkit.Rows("1", "6"))
+	tk.MustExec("begin")
+	tk.MustExec(`insert into t values (7, '{"a": [6, 7]}')`)
+	tk.MustQuery("select id from t where 6 member of (j->'$.a') order by id").Check(testkit.Rows("1", "6", "7"))
+	tk.MustExec("rollback")
+
+	// The values are converted to the element type.
+	tk.MustGetErrCode(`insert into t values (8, '{"a": [-1]}')`, errno.ErrJSONValueOutOfRangeForFuncIndex)
+	tk.MustGetErrCode(`insert into t values (8, '{"a": ["x"]}')`, errno.ErrInvalidJSONValueForFuncIndex)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (j json, unique key mvi ((cast(j as char(3) array))))")
+	tk.MustExec(`insert into t values ('["a", "b"]'), ('["c"]')`)
+	tk.MustGetErrMsg(`insert into t values ('["d", "b"]')`, "[kv:1062]Duplicate entry 'b' for key 'mvi'")
+	tk.MustGetErrCode(`insert into t values ('["abcd"]')`, errno.ErrFunctionalIndexDataIsTooLong)
+	tk.MustQuery(`select j from t where "b" member of (j)`).Check(testkit.Rows(`["a", "b"]`))
+
+	// The key parts before the array are accessed by the points.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, j json, key mvi (a, (cast(j as signed array))))")
+	tk.MustExec(`insert into t values (1, '[1, 2]'), (2, '[1]'), (3, '[2]')`)
+	c.Assert(tk.MustUseIndex("select a from t where a in (1, 2) and 1 member of (j)", "mvi"), IsTrue)
+	tk.MustQuery("select a from t where a in (1, 2) and 1 member of (j) order by a").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select a from t where a > 1 and 2 member of (j)").Check(testkit.Rows("3"))
+
+	tk.MustGetErrCode("create table t1 (j json, key ((cast(j as signed array)), (cast(j as signed array))))", errno.ErrNotSupportedYet)
+	tk.MustGetErrCode("create table t1 (j json, key ((cast(j as decimal array))))", errno.ErrNotSupportedYet)
+	tk.MustGetErrCode("create table t1 (j json, k json as (cast(j as signed array)))", errno.ErrNotSupportedYet)
+	tk.MustGetErrCode("select cast(j as signed array) from t", errno.ErrNotSupportedYet)
+	tk.MustGetErrCode("admin check index t mvi", errno.ErrNotSupportedYet)
+}
+
 // TestCreateTableWithAutoIdCache test the auto_id_cache table option.
 // `auto_id_cache` take effects on handle too when `PKIshandle` is false,
 // or even there is no auto_increment column at all.
@@ -2786,10 +2849,14 @@ func (s *testIntegrationSuite3) TestCreateTemporaryTable(c *C) {
 	tk.MustGetErrCode("create global temporary table t (id int) on commit preserve rows", errno.ErrUnsupportedDDLOperation)
 	// Engine type can only be 'memory' or empty for now.
 	tk.MustGetErrCode("create global temporary table t (id int) engine = 'innodb' on commit delete rows", errno.ErrUnsupportedDDLOperation)
-	// Follow the behaviour of the old version TiDB: parse and ignore the 'temporary' keyword.
-	tk.MustGetErrCode("create temporary table t(id int)", errno.ErrNotSupportedYet)
-
-	tk.MustExec("set @@tidb_enable_noop_functions = 1")
+	// Local temporary tables are only visible to the session.
 	tk.MustExec("create temporary table t (id int)")
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning 1105 local TEMPORARY TABLE is not supported yet, TEMPORARY will be parsed but ignored"))
+	tk.MustExec("create temporary table if not exists t (id int)")
+	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Note 1050 Table 'test.t' already exists"))
+	tk.MustGetErrCode("create temporary table t (id int)", errno.ErrTableExists)
+	tk.MustGetErrCode("create temporary table t1 (id bigint auto_random primary key)", errno.ErrOptOnTemporaryTable)
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use test")
+	tk1.MustGetErrCode("select * from t", errno.ErrNoSuchTable)
+	tk.MustExec("drop temporary table t")
 }
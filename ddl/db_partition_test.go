@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -37,6 +38,7 @@ import (
 	tmysql "github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
@@ -1607,6 +1609,369 @@ func (s *testIntegrationSuite5) TestMultiPartitionDropAndTruncate(c *C) {
 	result.Check(testkit.Rows(`2010`))
 }
 
+func (s *testIntegrationSuite5) TestAlterTableReorganizePartition(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec(`create table t (a int, b varchar(20), key idx_b(b))
+	partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition pmax values less than maxvalue
+	);`)
+	tk.MustExec(`insert into t values (1, "a"), (5, "b"), (12, "c"), (18, "d"), (25, "e"), (35, "f")`)
+
+	// Split the MAXVALUE partition.
+	tk.MustExec(`alter table t reorganize partition pmax into (
+		partition p2 values less than (30),
+		partition pmax values less than maxvalue)`)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t partition (p2)").Check(testkit.Rows("25"))
+	tk.MustQuery("select a from t partition (pmax)").Check(testkit.Rows("35"))
+	tk.MustQuery("select a from t use index(idx_b) where b = 'e'").Check(testkit.Rows("25"))
+
+	// Merge two consecutive partitions.
+	tk.MustExec("alter table t reorganize partition p0, p1 into (partition p01 values less than (20))")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t partition (p01) order by a").Check(testkit.Rows("1", "5", "12", "18"))
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` varchar(20) DEFAULT NULL,\n" +
+		"  KEY `idx_b` (`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY RANGE ( `a` ) (\n" +
+		"  PARTITION `p01` VALUES LESS THAN (20),\n" +
+		"  PARTITION `p2` VALUES LESS THAN (30),\n" +
+		"  PARTITION `pmax` VALUES LESS THAN (MAXVALUE)\n" +
+		")"))
+
+	// The range values are compared after evaluation, and the range of the last partition can't shrink.
+	tk.MustExec("alter table t reorganize partition p01 into (partition p0 values less than (10), partition p1 values less than (10 + 10))")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t partition (p1) order by a").Check(testkit.Rows("12", "18"))
+	tk.MustGetErrCode("alter table t reorganize partition p2, pmax into (partition p2 values less than (30))", tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode("alter table t reorganize partition pmax into (partition p3 values less than (40))", tmysql.ErrReorgOutsideRange)
+	tk.MustExec("alter table t reorganize partition p0, p1 into (partition p01 values less than (20))")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("6"))
+
+	tk.MustGetErrCode("alter table t reorganize partition p01, pmax into (partition p3 values less than maxvalue)", tmysql.ErrConsecutiveReorgPartitions)
+	tk.MustGetErrCode("alter table t reorganize partition p01 into (partition p3 values less than (15))", tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode("alter table t reorganize partition p3 into (partition p3 values less than (15))", tmysql.ErrDropPartitionNonExistent)
+	tk.MustGetErrCode("alter table t reorganize partition p01 into (partition p2 values less than (20))", tmysql.ErrSameNamePartition)
+
+	// Range columns partitioning.
+	tk.MustExec("drop table if exists tc")
+	tk.MustExec(`create table tc (d date)
+	partition by range columns (d) (
+		partition p0 values less than ('2020-01-01'),
+		partition p1 values less than ('2021-01-01'),
+		partition p2 values less than ('2022-01-01')
+	);`)
+	tk.MustExec("insert into tc values ('2019-06-01'), ('2020-06-01'), ('2021-06-01')")
+	tk.MustExec("alter table tc reorganize partition p0, p1 into (partition p0 values less than ('2020-07-01'), partition p1 values less than ('20210101'))")
+	tk.MustExec("admin check table tc")
+	tk.MustQuery("select d from tc partition (p0) order by d").Check(testkit.Rows("2019-06-01", "2020-06-01"))
+	tk.MustGetErrCode("alter table tc reorganize partition p1 into (partition p1 values less than ('2020-12-31'))", tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode("alter table tc reorganize partition p2 into (partition p2 values less than ('2021-12-31'))", tmysql.ErrReorgOutsideRange)
+	tk.MustExec("alter table tc reorganize partition p2 into (partition p2 values less than ('2023-01-01'))")
+	tk.MustExec("admin check table tc")
+
+	// List partitioning.
+	tk.MustExec("set @@session.tidb_enable_list_partition = ON")
+	tk.MustExec("drop table if exists tl")
+	tk.MustExec(`create table tl (a int, b int, unique key (a))
+	partition by list (a) (
+		partition p0 values in (1, 2, 3),
+		partition p1 values in (4, 5, 6)
+	);`)
+	tk.MustExec("insert into tl values (1, 1), (2, 2), (4, 4), (6, 6)")
+	tk.MustExec(`alter table tl reorganize partition p0, p1 into (
+		partition p0 values in (1, 4),
+		partition p1 values in (2, 3, 5, 6))`)
+	tk.MustExec("admin check table tl")
+	tk.MustQuery("select a from tl partition (p0) order by a").Check(testkit.Rows("1", "4"))
+	tk.MustQuery("select a from tl partition (p1) order by a").Check(testkit.Rows("2", "6"))
+	tk.MustGetErrCode("alter table tl reorganize partition p0 into (partition p0 values in (1))", tmysql.ErrNoPartitionForGivenValue)
+	tk.MustQuery("select a from tl partition (p0) order by a").Check(testkit.Rows("1", "4"))
+
+	tk.MustExec("drop table if exists th")
+	tk.MustExec("create table th (a int) partition by hash(a) partitions 4")
+	tk.MustGetErrCode("alter table th reorganize partition p0 into (partition p0)", tmysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("drop table if exists tn")
+	tk.MustExec("create table tn (a int)")
+	tk.MustGetErrCode("alter table tn reorganize partition p0 into (partition p0 values less than (10))", tmysql.ErrPartitionMgmtOnNonpartitioned)
+}
+
+func (s *testIntegrationSuite5) TestReorganizePartitionWithConcurrentDML(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec(`create table t (a int, b int, unique key idx_a(a), key idx_b(b))
+	partition by range (a) (
+		partition p0 values less than (10),
+		partition pmax values less than maxvalue
+	);`)
+	tk.MustExec("insert into t values (1, 1), (11, 11), (21, 21), (31, 31)")
+
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use test")
+	var checkErr error
+	d := s.dom.DDL()
+	originHook := d.GetHook()
+	defer d.(ddl.DDLForTest).SetHook(originHook)
+	hook := &ddl.TestDDLCallback{Do: s.dom}
+	hook.OnJobUpdatedExported = func(job *model.Job) {
+		if checkErr != nil || job.Type != model.ActionReorganizePartition {
+			return
+		}
+		switch job.SchemaState {
+		case model.StateDeleteOnly:
+			_, checkErr = tk1.Exec("delete from t where a = 11")
+		case model.StateWriteOnly:
+			_, checkErr = tk1.Exec("insert into t values (12, 12), (22, 22)")
+		case model.StateWriteReorganization:
+			_, checkErr = tk1.Exec("update t set a = 23, b = 23 where a = 21")
+		case model.StateDeleteReorganization:
+			_, checkErr = tk1.Exec("insert into t values (32, 32)")
+		}
+	}
+	d.(ddl.DDLForTest).SetHook(hook)
+	tk.MustExec(`alter table t reorganize partition pmax into (
+		partition p1 values less than (20),
+		partition p2 values less than (30),
+		partition pmax values less than maxvalue)`)
+	c.Assert(checkErr, IsNil)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t partition (p1)").Check(testkit.Rows("12"))
+	tk.MustQuery("select a from t partition (p2) order by a").Check(testkit.Rows("22", "23"))
+	tk.MustQuery("select a from t partition (pmax) order by a").Check(testkit.Rows("31", "32"))
+	tk.MustQuery("select b from t use index(idx_b) where b > 10 order by b").Check(testkit.Rows("12", "22", "23", "31", "32"))
+}
+
+func (s *testIntegrationSuite5) TestAlterTableResizeHashPartition(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, primary key (a) nonclustered, key idx_b(b)) partition by hash(a) partitions 2")
+	for i := 0; i < 12; i++ {
+		tk.MustExec("insert into t values (?, ?)", i, i)
+	}
+
+	tk.MustExec("alter table t add partition partitions 2")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) NOT NULL,\n" +
+		"  `b` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`a`) /*T![clustered_index] NONCLUSTERED */,\n" +
+		"  KEY `idx_b` (`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY HASH( `a` )\n" +
+		"PARTITIONS 4"))
+	tk.MustQuery("select a from t partition (p3) order by a").Check(testkit.Rows("3", "7", "11"))
+	tk.MustQuery("select b from t where a = 6").Check(testkit.Rows("6"))
+	tk.MustQuery("select a from t where a in (5, 10) order by a").Check(testkit.Rows("5", "10"))
+
+	tk.MustExec("alter table t add partition (partition p4, partition p5)")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t partition (p5) order by a").Check(testkit.Rows("5", "11"))
+
+	tk.MustExec("alter table t coalesce partition 3")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t partition (p2) order by a").Check(testkit.Rows("2", "5", "8", "11"))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("12"))
+	tk.MustQuery("select b from t use index(idx_b) where b = 8").Check(testkit.Rows("8"))
+	tbl := testGetTableByName(c, tk.Se, "test", "t")
+	c.Assert(tbl.Meta().Partition.Num, Equals, uint64(3))
+	c.Assert(tbl.Meta().Partition.Definitions, HasLen, 3)
+
+	tk.MustGetErrCode("alter table t add partition (partition p1)", tmysql.ErrSameNamePartition)
+	tk.MustExec("alter table t add partition if not exists (partition p1)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1517 Duplicate partition name p1"))
+	tk.MustGetErrCode("alter table t coalesce partition 3", tmysql.ErrDropLastPartition)
+	tk.MustGetErrCode("alter table t reorganize partition p0 into (partition p0)", tmysql.ErrUnsupportedDDLOperation)
+}
+
+func (s *testIntegrationSuite5) TestResizeHashPartitionWithConcurrentDML(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, unique key idx_a(a), key idx_b(b)) partition by hash(a) partitions 3")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5)")
+
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use test")
+	var checkErr error
+	d := s.dom.DDL()
+	originHook := d.GetHook()
+	defer d.(ddl.DDLForTest).SetHook(originHook)
+	hook := &ddl.TestDDLCallback{Do: s.dom}
+	next := 10
+	hook.OnJobUpdatedExported = func(job *model.Job) {
+		if checkErr != nil || job.Type != model.ActionReorganizePartition || job.SchemaState == model.StateNone {
+			return
+		}
+		// The rows must be found with the pruned partitions in all the states.
+		for _, a := range []int{1, 2, 3, 4, 5} {
+			rs, err := tk1.Exec(fmt.Sprintf("select b from t where a = %d", a))
+			if err != nil {
+				checkErr = err
+				return
+			}
+			rows, err := session.GetRows4Test(context.Background(), tk1.Se, rs)
+			terror.Log(rs.Close())
+			if err == nil && len(rows) != 1 {
+				err = errors.Errorf("row %d isn't found in state %s", a, job.SchemaState)
+			}
+			if err != nil {
+				checkErr = err
+				return
+			}
+		}
+		_, checkErr = tk1.Exec(fmt.Sprintf("insert into t values (%d, %d)", next, next))
+		if checkErr == nil {
+			_, checkErr = tk1.Exec(fmt.Sprintf("update t set b = b + 100 where a = %d", next-5))
+		}
+		next++
+	}
+	d.(ddl.DDLForTest).SetHook(hook)
+	tk.MustExec("alter table t coalesce partition 1")
+	c.Assert(checkErr, IsNil)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows(strconv.Itoa(5 + next - 10)))
+	tk.MustQuery("select a from t partition (p1) where a < 10 order by a").Check(testkit.Rows("1", "3", "5"))
+	tk.MustQuery("select b from t where a = 5").Check(testkit.Rows("105"))
+
+	next = 20
+	tk.MustExec("alter table t add partition partitions 3")
+	c.Assert(checkErr, IsNil)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t partition (p4) where a < 10 order by a").Check(testkit.Rows("4"))
+	tk.MustQuery("select count(*) from t where a >= 20").Check(testkit.Rows(strconv.Itoa(next - 20)))
+}
+
+func (s *testIntegrationSuite5) TestAlterTablePartitionByAndRemovePartitioning(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b varchar(10), c int, unique key idx_a(a), key idx_b(b))")
+	tk.MustExec(`insert into t values (1, "a", 1), (12, "b", 2), (25, "c", 3), (35, "d", 4)`)
+
+	tk.MustGetErrCode("alter table t remove partitioning", tmysql.ErrPartitionMgmtOnNonpartitioned)
+	tk.MustGetErrCode("alter table t partition by hash(c) partitions 2", tmysql.ErrUniqueKeyNeedAllFieldsInPf)
+	tk.MustExec(`alter table t partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than (maxvalue))`)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t partition (p1)").Check(testkit.Rows("12"))
+	tk.MustQuery("select a from t partition (p2) order by a").Check(testkit.Rows("25", "35"))
+	tk.MustQuery("select c from t use index(idx_b) where b = 'c'").Check(testkit.Rows("3"))
+	tk.MustQuery("show create table t").Check(testkit.Rows("t CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` varchar(10) DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  UNIQUE KEY `idx_a` (`a`),\n" +
+		"  KEY `idx_b` (`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin\n" +
+		"PARTITION BY RANGE ( `a` ) (\n" +
+		"  PARTITION `p0` VALUES LESS THAN (10),\n" +
+		"  PARTITION `p1` VALUES LESS THAN (20),\n" +
+		"  PARTITION `p2` VALUES LESS THAN (MAXVALUE)\n" +
+		")"))
+	_, err := tk.Exec("alter table t partition by hash(a) partitions 4")
+	c.Assert(err, ErrorMatches, ".*alter table partition is unsupported")
+	tk.MustExec("insert into t values (5, 'e', 5)")
+	tk.MustGetErrCode("insert into t values (5, 'f', 6)", tmysql.ErrDupEntry)
+
+	tk.MustExec("alter table t remove partitioning")
+	tk.MustExec("admin check table t")
+	tbl := testGetTableByName(c, tk.Se, "test", "t")
+	c.Assert(tbl.Meta().Partition, IsNil)
+	tk.MustQuery("select a from t order by a").Check(testkit.Rows("1", "5", "12", "25", "35"))
+	tk.MustQuery("select c from t use index(idx_b) where b = 'e'").Check(testkit.Rows("5"))
+	tk.MustGetErrCode("insert into t values (12, 'g', 7)", tmysql.ErrDupEntry)
+	_, err = tk.Exec("select * from t partition (p0)")
+	c.Assert(err, NotNil)
+
+	// The job is rolled back if some rows can't be located in the new partitions.
+	tk.MustGetErrCode(`alter table t partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20))`, tmysql.ErrNoPartitionForGivenValue)
+	tk.MustExec("admin check table t")
+	c.Assert(testGetTableByName(c, tk.Se, "test", "t").Meta().Partition, IsNil)
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("5"))
+
+	// The handles of the rows are kept.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	rows := tk.MustQuery("select _tidb_rowid, a from t order by a").Rows()
+	tk.MustExec("alter table t partition by hash(a) partitions 2")
+	tk.MustQuery("select _tidb_rowid, a from t order by a").Check(rows)
+	tk.MustExec("insert into t (a, b) values (4, 4)")
+	tk.MustExec("alter table t remove partitioning")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t order by a").Check(testkit.Rows("1", "2", "3", "4"))
+	tk.MustQuery("select count(distinct _tidb_rowid) from t").Check(testkit.Rows("4"))
+}
+
+func (s *testIntegrationSuite5) TestAlterTablePartitioningWithConcurrentDML(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, primary key (a) nonclustered, key idx_b(b))")
+	tk.MustExec("insert into t values (1, 1), (11, 11), (21, 21)")
+
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use test")
+	var checkErr error
+	d := s.dom.DDL()
+	originHook := d.GetHook()
+	defer d.(ddl.DDLForTest).SetHook(originHook)
+	hook := &ddl.TestDDLCallback{Do: s.dom}
+	next := 100
+	hook.OnJobUpdatedExported = func(job *model.Job) {
+		if checkErr != nil || job.SchemaState == model.StateNone ||
+			(job.Type != model.ActionAlterTablePartitioning && job.Type != model.ActionRemovePartitioning) {
+			return
+		}
+		_, checkErr = tk1.Exec(fmt.Sprintf("insert into t values (%d, %d)", next, next))
+		if checkErr == nil {
+			_, checkErr = tk1.Exec(fmt.Sprintf("update t set a = a + 1000, b = b + 1000 where a = %d", next-1))
+		}
+		if checkErr == nil {
+			_, checkErr = tk1.Exec("delete from t where b = 1")
+		}
+		if checkErr == nil && job.Type == model.ActionAlterTablePartitioning && job.SchemaState == model.StateWriteOnly {
+			// The table isn't partitioned until the new partitions are public.
+			_, checkErr = tk1.Exec("split partition table t between (0) and (100000) regions 2")
+			if !core.ErrPartitionClauseOnNonpartitioned.Equal(checkErr) {
+				checkErr = errors.Errorf("split the pending partition: %v", checkErr)
+				return
+			}
+			checkErr = nil
+		}
+		next++
+	}
+	d.(ddl.DDLForTest).SetHook(hook)
+	tk.MustExec(`alter table t partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (1000),
+		partition p2 values less than (maxvalue))`)
+	c.Assert(checkErr, IsNil)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select a from t partition (p0)").Check(testkit.Rows())
+	tk.MustQuery("select count(*) from t partition (p2)").Check(testkit.Rows(strconv.Itoa(next - 101)))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows(strconv.Itoa(2 + next - 100)))
+
+	tk.MustExec("insert into t values (1, 1)")
+	tk.MustExec("alter table t remove partitioning")
+	c.Assert(checkErr, IsNil)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select count(*) from t where b = 1").Check(testkit.Rows("0"))
+	tk.MustQuery("select count(*) from t where a >= 1000").Check(testkit.Rows(strconv.Itoa(next - 101)))
+}
+
 func (s *testSerialDBSuite1) TestDropPartitionWithGlobalIndex(c *C) {
 	config.UpdateGlobal(func(conf *config.Config) {
 		conf.EnableGlobalIndex = true
@@ -3022,11 +3387,9 @@ func (s *testIntegrationSuite3) TestPartitionErrorCode(c *C) {
 	)
 	partition by hash(store_id)
 	partitions 4;`)
-	_, err := tk.Exec("alter table employees add partition partitions 8;")
-	c.Assert(ddl.ErrUnsupportedAddPartition.Equal(err), IsTrue)
-
-	_, err = tk.Exec("alter table employees add partition (partition p5 values less than (42));")
-	c.Assert(ddl.ErrUnsupportedAddPartition.Equal(err), IsTrue)
+	tk.MustGetErrCode("alter table employees add partition partitions 0;", tmysql.ErrAddPartitionNoNewPartition)
+	tk.MustGetErrCode("alter table employees add partition (partition p5 values less than (42));", tmysql.ErrPartitionWrongValues)
+	tk.MustGetErrCode("alter table employees add partition (partition p3);", tmysql.ErrSameNamePartition)
 
 	// coalesce partition
 	tk.MustExec(`create table clients (
@@ -3037,24 +3400,23 @@ func (s *testIntegrationSuite3) TestPartitionErrorCode(c *C) {
 	)
 	partition by hash( month(signed) )
 	partitions 12;`)
-	_, err = tk.Exec("alter table clients coalesce partition 4;")
-	c.Assert(ddl.ErrUnsupportedCoalescePartition.Equal(err), IsTrue)
+	tk.MustGetErrCode("alter table clients coalesce partition 0;", tmysql.ErrCoalescePartitionNoPartition)
+	tk.MustGetErrCode("alter table clients coalesce partition 12;", tmysql.ErrDropLastPartition)
 
 	tk.MustExec(`create table t_part (a int key)
 		partition by range(a) (
 		partition p0 values less than (10),
 		partition p1 values less than (20)
 		);`)
-	_, err = tk.Exec("alter table t_part coalesce partition 4;")
+	_, err := tk.Exec("alter table t_part coalesce partition 4;")
 	c.Assert(ddl.ErrCoalesceOnlyOnHashPartition.Equal(err), IsTrue)
 
-	tk.MustGetErrCode(`alter table t_part reorganize partition p0, p1 into (
+	tk.MustGetErrCode(`alter table clients reorganize partition p0, p1 into (
 			partition p0 values less than (1980));`, tmysql.ErrUnsupportedDDLOperation)
 
 	tk.MustGetErrCode("alter table t_part check partition p0, p1;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part optimize partition p0,p1;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part rebuild partition p0,p1;", tmysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t_part remove partitioning;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part repair partition p1;", tmysql.ErrUnsupportedDDLOperation)
 
 	// Reduce the impact on DML when executing partition DDL
@@ -3027,7 +3027,16 @@ func (s *testDBSuite2) TestTableForeignKey(c *C) {
 	tk.MustQuery("select count(*) from information_schema.KEY_COLUMN_USAGE;")
 	tk.MustExec("alter table t4 drop foreign key d")
 	tk.MustExec("alter table t4 modify column d bigint;")
-	tk.MustExec("drop table if exists t1,t2,t3,t4;")
+	// Test an index is created for the foreign key if there is none.
+	tk.MustExec("create table t5 (a int, b int, key(b, a), constraint fk_a foreign key (a) references t1 (a), constraint fk_b foreign key (b) references t1 (b));")
+	tk.MustQuery("select index_name, column_name from information_schema.statistics where table_name = 't5' order by index_name, seq_in_index").Check(
+		testkit.Rows("b b", "b a", "fk_a a"))
+	tk.MustExec("alter table t3 add index fk_c (b);")
+	tk.MustExec("alter table t3 add constraint fk_c foreign key (a) references t1 (a);")
+	tk.MustExec("alter table t3 add constraint fk_d foreign key (b) references t1 (b);")
+	tk.MustQuery("select index_name, column_name from information_schema.statistics where table_name = 't3' order by index_name").Check(
+		testkit.Rows("a a", "fk_c b"))
+	tk.MustExec("drop table if exists t1,t2,t3,t4,t5;")
 }
 
 func (s *testDBSuite8) TestFKOnGeneratedColumns(c *C) {
@@ -6008,30 +6017,77 @@ func (s *testDBSuite4) TestColumnCheck(c *C) {
 	tk.MustExec("drop table if exists column_check")
 	tk.MustExec("create table column_check (pk int primary key, a int check (a > 1))")
 	defer tk.MustExec("drop table if exists column_check")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|CONSTRAINT CHECK is not supported"))
+	tk.MustQuery("show create table column_check").Check(testutil.RowsWithSep("|", ""+
+		"column_check CREATE TABLE `column_check` (\n"+
+		"  `pk` int(11) NOT NULL,\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`pk`) /*T![clustered_index] CLUSTERED */,\n"+
+		"  CONSTRAINT `column_check_chk_1` CHECK (`a` > 1)\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("insert into column_check values (1, 2), (2, null)")
+	tk.MustGetErrCode("insert into column_check values (3, 1)", errno.ErrCheckConstraintViolated)
+
+	// A column check constraint can only refer to its own column.
+	tk.MustGetErrCode("create table t_column_check (a int check (b > 0), b int)", errno.ErrColumnCheckConstraintReferencesOtherColumn)
+	tk.MustGetErrCode("create table t_column_check (a int, b int check (c > 0))", errno.ErrColumnCheckConstraintReferencesOtherColumn)
+	tk.MustGetErrCode("create table t_column_check (a int auto_increment primary key, check (a > 0))", errno.ErrCheckConstraintRefersAutoIncrementColumn)
+	tk.MustGetErrCode("create table t_column_check (a int, check (a > rand()))", errno.ErrCheckConstraintFunctionIsNotAllowed)
+	tk.MustGetErrCode("create table t_column_check (a int, check (a > @a))", errno.ErrCheckConstraintVariables)
+	tk.MustGetErrCode("create table t_column_check (a int, check (b > 0))", errno.ErrTableCheckConstraintReferUnknown)
+	tk.MustGetErrCode("create table t_column_check (a int, constraint c1 check (a > 0), constraint c1 check (a < 10))", errno.ErrCheckConstraintDupName)
+	tk.MustGetErrCode("alter table column_check add column b int check (b > 0)", errno.ErrUnsupportedDDLOperation)
 }
 
 func (s *testDBSuite5) TestAlterCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists alter_check")
-	tk.MustExec("create table alter_check (pk int primary key)")
+	tk.MustExec("create table alter_check (pk int primary key, a int, constraint crcn check (a > 1) not enforced)")
 	defer tk.MustExec("drop table if exists alter_check")
-	tk.MustExec("alter table alter_check alter check crcn ENFORCED")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|ALTER CHECK is not supported"))
+	tk.MustGetErrCode("alter table alter_check alter check not_exist enforced", errno.ErrConstraintNotFound)
+
+	// The not enforced constraint isn't checked.
+	tk.MustExec("insert into alter_check values (1, 1)")
+	tk.MustQuery("show create table alter_check").Check(testutil.RowsWithSep("|", ""+
+		"alter_check CREATE TABLE `alter_check` (\n"+
+		"  `pk` int(11) NOT NULL,\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`pk`) /*T![clustered_index] CLUSTERED */,\n"+
+		"  CONSTRAINT `crcn` CHECK (`a` > 1) /*!80016 NOT ENFORCED */\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+
+	// The existing rows are verified when the constraint becomes enforced.
+	tk.MustGetErrCode("alter table alter_check alter check crcn enforced", errno.ErrCheckConstraintViolated)
+	tk.MustExec("update alter_check set a = 2")
+	tk.MustExec("alter table alter_check alter check crcn enforced")
+	tk.MustGetErrCode("insert into alter_check values (2, 1)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("update alter_check set a = 0", errno.ErrCheckConstraintViolated)
+
+	tk.MustExec("alter table alter_check alter check crcn not enforced")
+	tk.MustExec("insert into alter_check values (2, 1)")
+	tk.MustQuery("select * from alter_check").Check(testkit.Rows("1 2", "2 1"))
 }
 
 func (s *testDBSuite6) TestDropCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists drop_check")
-	tk.MustExec("create table drop_check (pk int primary key)")
+	tk.MustExec("create table drop_check (pk int primary key, a int, b int, constraint crcn check (a > 1), check (b > a))")
 	defer tk.MustExec("drop table if exists drop_check")
+	tk.MustGetErrCode("alter table drop_check drop check not_exist", errno.ErrConstraintNotFound)
+
+	// The columns used by check constraints can't be dropped or renamed.
+	tk.MustGetErrCode("alter table drop_check drop column a", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table drop_check rename column b to c", errno.ErrDependentByCheckConstraint)
+
+	tk.MustGetErrCode("insert into drop_check values (1, 1, 2)", errno.ErrCheckConstraintViolated)
 	tk.MustExec("alter table drop_check drop check crcn")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|DROP CHECK is not supported"))
+	tk.MustExec("insert into drop_check values (1, 1, 2)")
+	tk.MustGetErrCode("insert into drop_check values (2, 3, 2)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("alter table drop_check drop check drop_check_chk_1")
+	tk.MustExec("insert into drop_check values (2, 3, 2)")
+	tk.MustExec("alter table drop_check drop column a")
+	tk.MustQuery("select * from drop_check").Check(testkit.Rows("1 2", "2 2"))
 }
 
 func (s *testDBSuite7) TestAddConstraintCheck(c *C) {
@@ -6040,22 +6096,80 @@ func (s *testDBSuite7) TestAddConstraintCheck(c *C) {
 	tk.MustExec("drop table if exists add_constraint_check")
 	tk.MustExec("create table add_constraint_check (pk int primary key, a int)")
 	defer tk.MustExec("drop table if exists add_constraint_check")
-	tk.MustExec("alter table add_constraint_check add constraint crn check (a > 1)")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|ADD CONSTRAINT CHECK is not supported"))
+	tk.MustExec("insert into add_constraint_check values (1, 1), (2, 2)")
+
+	// The existing rows are verified when a constraint is added.
+	tk.MustGetErrCode("alter table add_constraint_check add constraint crn check (a > 1)", errno.ErrCheckConstraintViolated)
+	tk.MustQuery("show create table add_constraint_check").Check(testutil.RowsWithSep("|", ""+
+		"add_constraint_check CREATE TABLE `add_constraint_check` (\n"+
+		"  `pk` int(11) NOT NULL,\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`pk`) /*T![clustered_index] CLUSTERED */\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+
+	tk.MustExec("alter table add_constraint_check add constraint crn check (a > 0)")
+	tk.MustGetErrCode("alter table add_constraint_check add constraint crn check (a < 10)", errno.ErrCheckConstraintDupName)
+	tk.MustExec("alter table add_constraint_check add check (a < 10)")
+	tk.MustQuery("show create table add_constraint_check").Check(testutil.RowsWithSep("|", ""+
+		"add_constraint_check CREATE TABLE `add_constraint_check` (\n"+
+		"  `pk` int(11) NOT NULL,\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`pk`) /*T![clustered_index] CLUSTERED */,\n"+
+		"  CONSTRAINT `crn` CHECK (`a` > 0),\n"+
+		"  CONSTRAINT `add_constraint_check_chk_1` CHECK (`a` < 10)\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustGetErrCode("insert into add_constraint_check values (3, 0)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("insert into add_constraint_check values (3, 10)", errno.ErrCheckConstraintViolated)
 }
 
-func (s *testDBSuite7) TestCreateTableIngoreCheckConstraint(c *C) {
+func (s *testDBSuite7) TestCreateTableWithCheckConstraint(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
-	tk.MustExec("drop table if exists table_constraint_check")
+	tk.MustExec("drop table if exists admin_user")
 	tk.MustExec("CREATE TABLE admin_user (enable bool, CHECK (enable IN (0, 1)));")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|CONSTRAINT CHECK is not supported"))
+	defer tk.MustExec("drop table if exists admin_user")
 	tk.MustQuery("show create table admin_user").Check(testutil.RowsWithSep("|", ""+
 		"admin_user CREATE TABLE `admin_user` (\n"+
-		"  `enable` tinyint(1) DEFAULT NULL\n"+
+		"  `enable` tinyint(1) DEFAULT NULL,\n"+
+		"  CONSTRAINT `admin_user_chk_1` CHECK (`enable` in (0,1))\n"+
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("insert into admin_user values (1), (null)")
+	tk.MustGetErrCode("insert into admin_user values (2)", errno.ErrCheckConstraintViolated)
+}
+
+func (s *testDBSuite7) TestCreateDropRowPolicy(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use " + s.schemaName)
+	tk.MustExec("drop table if exists row_policy, row_policy_view")
+	tk.MustExec("create table row_policy (pk int primary key, a int, b int)")
+	tk.MustExec("create view row_policy_view as select * from row_policy")
+	defer tk.MustExec("drop table if exists row_policy")
+	defer tk.MustExec("drop view if exists row_policy_view")
+
+	tk.MustGetErrCode("create policy p on row_policy using (c > 1)", errno.ErrBadField)
+	tk.MustGetErrCode("create policy p on row_policy using (a > 1) with check (other.a > 1)", errno.ErrBadField)
+	tk.MustGetErrCode("create policy p on row_policy_view using (a > 1)", errno.ErrWrongObject)
+	tk.MustGetErrCode("create policy p on not_exist using (a > 1)", errno.ErrNoSuchTable)
+	tk.MustExec("create policy p on row_policy using (row_policy.a > 1) with check (b > a)")
+	tk.MustGetErrCode("create policy p on row_policy using (a > 2)", errno.ErrRowPolicyExists)
+	tk.MustExec("create policy if not exists p on row_policy using (a > 2)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 8241 Row-level security policy 'p' already exists on table 'row_policy'"))
+	tblInfo := testGetTableByName(c, tk.Se, s.schemaName, "row_policy").Meta()
+	c.Assert(tblInfo.RowPolicies, HasLen, 1)
+	c.Assert(tblInfo.RowPolicies[0].Using, Equals, "`row_policy`.`a` > 1")
+	c.Assert(tblInfo.RowPolicies[0].Check, Equals, "`b` > `a`")
+
+	// The columns used by the policies can't be dropped or renamed.
+	tk.MustGetErrCode("alter table row_policy drop column a", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table row_policy rename column b to c", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table row_policy change column b c int", errno.ErrDependentByCheckConstraint)
+
+	tk.MustGetErrCode("drop policy not_exist on row_policy", errno.ErrRowPolicyNotExists)
+	tk.MustExec("drop policy if exists not_exist on row_policy")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 8242 Row-level security policy 'not_exist' doesn't exist on table 'row_policy'"))
+	tk.MustExec("drop policy p on row_policy")
+	c.Assert(testGetTableByName(c, tk.Se, s.schemaName, "row_policy").Meta().RowPolicies, HasLen, 0)
+	tk.MustExec("alter table row_policy drop column a")
 }
 
 func (s *testDBSuite6) TestAlterOrderBy(c *C) {
@@ -21,6 +21,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -685,7 +686,15 @@ func columnDefToCol(ctx sessionctx.Context, offset int, colDef *ast.ColumnDef, o
 			case ast.ColumnOptionFulltext:
 				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrTableCantHandleFt.GenWithStackByArgs())
 			case ast.ColumnOptionCheck:
-				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+				constraint := &ast.Constraint{
+					Tp:           ast.ConstraintCheck,
+					Name:         v.ConstraintName,
+					Expr:         v.Expr,
+					Enforced:     v.Enforced,
+					InColumn:     true,
+					InColumnName: colDef.Name.Name.O,
+				}
+				constraints = append(constraints, constraint)
 			}
 		}
 	}
@@ -1212,7 +1221,11 @@ func checkConstraintNames(constraints []*ast.Constraint) error {
 	fkNames := map[string]bool{}
 
 	// Check not empty constraint name whether is duplicated.
+	// The names of check constraints are in their own namespace, see buildCheckConstraints.
 	for _, constr := range constraints {
+		if constr.Tp == ast.ConstraintCheck {
+			continue
+		}
 		if constr.Tp == ast.ConstraintForeignKey {
 			err := checkDuplicateConstraint(fkNames, constr.Name, true)
 			if err != nil {
@@ -1228,6 +1241,9 @@ func checkConstraintNames(constraints []*ast.Constraint) error {
 
 	// Set empty constraint names.
 	for _, constr := range constraints {
+		if constr.Tp == ast.ConstraintCheck {
+			continue
+		}
 		if constr.Tp == ast.ConstraintForeignKey {
 			setEmptyConstraintName(fkNames, constr, true)
 		} else {
@@ -1374,6 +1390,7 @@ func buildTableInfo(
 		Collate: collate,
 	}
 	tblColumns := make([]*table.Column, 0, len(cols))
+	var checkConstraints []*ast.Constraint
 	for _, v := range cols {
 		v.ID = allocateColumnID(tbInfo)
 		tbInfo.Columns = append(tbInfo.Columns, v.ToInfo())
@@ -1443,7 +1460,8 @@ func buildTableInfo(
 			continue
 		}
 		if constr.Tp == ast.ConstraintCheck {
-			ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+			// Check constraints are built after all the columns are built.
+			checkConstraints = append(checkConstraints, constr)
 			continue
 		}
 		// build index info.
@@ -1485,6 +1503,28 @@ func buildTableInfo(
 		idxInfo.ID = allocateIndexID(tbInfo)
 		tbInfo.Indices = append(tbInfo.Indices, idxInfo)
 	}
+	// The column check constraints are collected after the table ones, put them ahead so that
+	// the generated names follow the order in which the constraints are defined.
+	sort.SliceStable(checkConstraints, func(i, j int) bool {
+		return checkConstraints[i].InColumn && !checkConstraints[j].InColumn
+	})
+	if err = buildCheckConstraints(ctx, tbInfo, checkConstraints); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Like MySQL, an index is created for the foreign key if there is none, so the rows referring to a
+	// parent row can be found without scanning the table when the foreign keys are enforced.
+	for _, fk := range tbInfo.ForeignKeys {
+		if hasForeignKeyIndex(tbInfo, fk.Cols) {
+			continue
+		}
+		idxInfo, err := buildIndexInfo(tbInfo, foreignKeyIndexName(tbInfo, fk), foreignKeyIndexPartSpecifications(fk), model.StatePublic)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		idxInfo.Tp = model.IndexTypeBtree
+		idxInfo.ID = allocateIndexID(tbInfo)
+		tbInfo.Indices = append(tbInfo.Indices, idxInfo)
+	}
 	if tbInfo.IsCommonHandle {
 		// Ensure tblInfo's each non-unique secondary-index's len + primary-key's len <= MaxIndexLength for clustered index table.
 		var pkLen, idxLen int
@@ -1604,7 +1644,7 @@ func checkTableInfoValidWithStmt(ctx sessionctx.Context, tbInfo *model.TableInfo
 		if err := checkPartitionFuncType(ctx, s.Partition.Expr, tbInfo); err != nil {
 			return errors.Trace(err)
 		}
-		if err := checkPartitioningKeysConstraints(ctx, s, tbInfo); err != nil {
+		if err := checkPartitioningKeysConstraints(ctx, s.Partition, tbInfo); err != nil {
 			return errors.Trace(err)
 		}
 	}
@@ -1669,11 +1709,16 @@ func buildTableInfoWithLike(ident ast.Ident, referTblInfo *model.TableInfo) (*mo
 		replica.Available = false
 		tblInfo.TiFlashReplica = &replica
 	}
-	if referTblInfo.Partition != nil {
-		pi := *referTblInfo.Partition
-		pi.Definitions = make([]model.PartitionDefinition, len(referTblInfo.Partition.Definitions))
-		copy(pi.Definitions, referTblInfo.Partition.Definitions)
+	// The partitions which are being reorganized aren't copied, and a table which is being partitioned
+	// is copied as a non-partitioned table.
+	if referPi := referTblInfo.GetPartitionInfo(); referPi != nil {
+		pi := *referPi
+		pi.Definitions = make([]model.PartitionDefinition, len(referPi.Definitions))
+		copy(pi.Definitions, referPi.Definitions)
+		pi.AddingDefinitions, pi.DroppingDefinitions, pi.States = nil, nil, nil
 		tblInfo.Partition = &pi
+	} else {
+		tblInfo.Partition = nil
 	}
 	return &tblInfo, nil
 }
@@ -1741,9 +1786,7 @@ func buildTableInfoWithStmt(ctx sessionctx.Context, s *ast.CreateTableStmt, dbCh
 			return nil, errors.Trace(errUnsupportedOnCommitPreserve)
 		}
 	case ast.TemporaryLocal:
-		// TODO: set "tbInfo.TempTableType = model.TempTableLocal" after local temporary table is supported.
-		tbInfo.TempTableType = model.TempTableNone
-		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.New("local TEMPORARY TABLE is not supported yet, TEMPORARY will be parsed but ignored"))
+		tbInfo.TempTableType = model.TempTableLocal
 	case ast.TemporaryNone:
 		tbInfo.TempTableType = model.TempTableNone
 	}
@@ -1827,6 +1870,47 @@ func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err e
 	return d.CreateTableWithInfo(ctx, schema.Name, tbInfo, onExist, false /*tryRetainID*/)
 }
 
+// BuildSessionTemporaryTableInfo builds model.TableInfo for a local temporary table.
+// The table is only visible to the session, so no DDL job is needed, and the table ID
+// is allocated directly from the global ID generator.
+func BuildSessionTemporaryTableInfo(ctx sessionctx.Context, is infoschema.InfoSchema, s *ast.CreateTableStmt, dbCharset, dbCollate string) (*model.TableInfo, error) {
+	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
+	var tbInfo *model.TableInfo
+	var err error
+	if s.ReferTable != nil {
+		referIdent := ast.Ident{Schema: s.ReferTable.Schema, Name: s.ReferTable.Name}
+		referTbl, err := is.TableByName(referIdent.Schema, referIdent.Name)
+		if err != nil {
+			return nil, infoschema.ErrTableNotExists.GenWithStackByArgs(referIdent.Schema, referIdent.Name)
+		}
+		tbInfo, err = buildTableInfoWithLike(ident, referTbl.Meta())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		tbInfo.TempTableType = model.TempTableLocal
+		tbInfo.TiFlashReplica = nil
+	} else {
+		tbInfo, err = buildTableInfoWithCheck(ctx, s, dbCharset, dbCollate)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if err = checkAddPartitionOnTemporaryMode(tbInfo); err != nil {
+		return nil, err
+	}
+	if tbInfo.AutoRandomBits > 0 {
+		return nil, ErrOptOnTemporaryTable.GenWithStackByArgs("auto_random")
+	}
+
+	ids, err := genGlobalIDs(ctx.GetStore(), 1)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tbInfo.ID = ids[0]
+	tbInfo.State = model.StatePublic
+	return tbInfo, nil
+}
+
 func (d *ddl) CreateTableWithInfo(
 	ctx sessionctx.Context,
 	dbName model.CIStr,
@@ -1868,8 +1952,8 @@ func (d *ddl) CreateTableWithInfo(
 		return errors.Trace(err)
 	}
 
-	if tbInfo.Partition != nil {
-		if err := d.assignPartitionIDs(tbInfo.Partition.Definitions); err != nil {
+	if pi := tbInfo.GetPartitionInfo(); pi != nil {
+		if err := d.assignPartitionIDs(pi.Definitions); err != nil {
 			return errors.Trace(err)
 		}
 	}
@@ -2379,6 +2463,217 @@ func isSameTypeMultiSpecs(specs []*ast.AlterTableSpec) bool {
 	return true
 }
 
+// multiSchemaChange runs the specs of different types as one job, so that the intermediate schemas are not exposed.
+// Only adding or dropping columns and non-expression secondary indexes are supported now.
+func (d *ddl) multiSchemaChange(ctx sessionctx.Context, ti ast.Ident, specs []*ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tblInfo := t.Meta()
+
+	var addColumnSpecs, dropColumnSpecs, addIndexSpecs, dropIndexSpecs []*ast.AlterTableSpec
+	for _, spec := range specs {
+		switch spec.Tp {
+		case ast.AlterTableAddColumns:
+			addColumnSpecs = append(addColumnSpecs, spec)
+		case ast.AlterTableDropColumn:
+			dropColumnSpecs = append(dropColumnSpecs, spec)
+		case ast.AlterTableAddConstraint:
+			switch spec.Constraint.Tp {
+			case ast.ConstraintKey, ast.ConstraintIndex, ast.ConstraintUniq, ast.ConstraintUniqIndex, ast.ConstraintUniqKey:
+			default:
+				return errRunMultiSchemaChanges
+			}
+			for _, key := range spec.Constraint.Keys {
+				if key.Expr != nil {
+					return errRunMultiSchemaChanges
+				}
+			}
+			addIndexSpecs = append(addIndexSpecs, spec)
+		case ast.AlterTableDropIndex:
+			dropIndexSpecs = append(dropIndexSpecs, spec)
+		default:
+			return errRunMultiSchemaChanges
+		}
+	}
+
+	jobs := make([]*model.Job, 0, len(specs))
+	// The indexes are built on the table with the adding columns.
+	virtualTblInfo := tblInfo.Clone()
+	addingColumns := make(map[string]*model.ColumnInfo)
+	if len(addColumnSpecs) > 0 {
+		job, err := newAddColumnsJob(ctx, ti, schema, t, addColumnSpecs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if job != nil {
+			for _, col := range job.Args[0].([]*table.Column) {
+				colInfo := col.ColumnInfo.Clone()
+				colInfo.Offset = len(virtualTblInfo.Columns)
+				colInfo.State = model.StatePublic
+				virtualTblInfo.Columns = append(virtualTblInfo.Columns, colInfo)
+				addingColumns[colInfo.Name.L] = colInfo
+			}
+			jobs = append(jobs, job)
+		}
+	}
+	virtualTbl, err := tables.TableFromMeta(t.Allocators(ctx), virtualTblInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	addingIndexes := make(map[string]*model.IndexInfo)
+	for _, spec := range addIndexSpecs {
+		constr := spec.Constraint
+		keyType, ifNotExists := ast.IndexKeyTypeNone, constr.IfNotExists
+		if constr.Tp != ast.ConstraintKey && constr.Tp != ast.ConstraintIndex {
+			// IfNotExists should be not applied.
+			keyType, ifNotExists = ast.IndexKeyTypeUnique, false
+		}
+		job, err := newCreateIndexJob(ctx, schema, virtualTbl, keyType, model.NewCIStr(constr.Name), constr.Keys, constr.Option, ifNotExists)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if job == nil {
+			continue
+		}
+		indexName := job.Args[1].(model.CIStr)
+		indexInfo, err := buildIndexInfo(virtualTblInfo, indexName, constr.Keys, model.StatePublic)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		virtualTblInfo.Indices = append(virtualTblInfo.Indices, indexInfo)
+		if virtualTbl, err = tables.TableFromMeta(t.Allocators(ctx), virtualTblInfo); err != nil {
+			return errors.Trace(err)
+		}
+		addingIndexes[indexName.L] = indexInfo
+		jobs = append(jobs, job)
+	}
+
+	droppingIndexes := make(map[string]struct{})
+	for _, spec := range dropIndexSpecs {
+		indexName := model.NewCIStr(spec.Name)
+		if _, ok := addingIndexes[indexName.L]; ok {
+			return ErrOperateSameIndex.GenWithStackByArgs(indexName.O)
+		}
+		if _, ok := droppingIndexes[indexName.L]; ok {
+			return ErrOperateSameIndex.GenWithStackByArgs(indexName.O)
+		}
+		job, err := newDropIndexJob(ctx, schema, t, indexName, spec.IfExists)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if job == nil {
+			continue
+		}
+		if job.Type != model.ActionDropIndex {
+			return errRunMultiSchemaChanges
+		}
+		// Dropping the index with hidden columns changes the column offsets.
+		for _, idxCol := range tblInfo.FindIndexByName(indexName.L).Columns {
+			if tblInfo.Columns[idxCol.Offset].Hidden {
+				return errRunMultiSchemaChanges
+			}
+		}
+		droppingIndexes[indexName.L] = struct{}{}
+		jobs = append(jobs, job)
+	}
+
+	for _, spec := range dropColumnSpecs {
+		if _, ok := addingColumns[spec.OldColumnName.Name.L]; ok {
+			return ErrOperateSameColumn.GenWithStackByArgs(spec.OldColumnName.Name.O)
+		}
+	}
+	if len(dropColumnSpecs) > 0 {
+		job, err := newDropColumnsJob(ctx, schema, t, dropColumnSpecs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if job != nil {
+			if err = checkMultiSchemaChangeDropColumns(tblInfo, addingColumns, addingIndexes, droppingIndexes,
+				addColumnSpecs, job.Args[0].([]model.CIStr)); err != nil {
+				return errors.Trace(err)
+			}
+			jobs = append(jobs, job)
+		}
+	}
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	info := &multiSchemaInfo{Revertible: true}
+	for _, job := range jobs {
+		sub, err := newSubJob(job)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		info.SubJobs = append(info.SubJobs, sub)
+	}
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionMultiSchemaChange,
+		BinlogInfo: &model.HistoryInfo{},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Args:     []interface{}{info},
+		Priority: ctx.GetSessionVars().DDLReorgPriority,
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// checkMultiSchemaChangeDropColumns checks the dropping columns don't conflict with the other specs.
+func checkMultiSchemaChangeDropColumns(tblInfo *model.TableInfo, addingColumns map[string]*model.ColumnInfo,
+	addingIndexes map[string]*model.IndexInfo, droppingIndexes map[string]struct{}, addColumnSpecs []*ast.AlterTableSpec,
+	colNames []model.CIStr) error {
+	droppingColumns := make(map[string]struct{}, len(colNames))
+	for _, colName := range colNames {
+		droppingColumns[colName.L] = struct{}{}
+	}
+	for _, colName := range colNames {
+		for _, col := range addingColumns {
+			if _, ok := col.Dependences[colName.L]; ok {
+				return errDependentByGeneratedColumn.GenWithStackByArgs(colName.O)
+			}
+		}
+		for _, indexInfo := range addingIndexes {
+			for _, idxCol := range indexInfo.Columns {
+				if idxCol.Name.L == colName.L {
+					return errKeyColumnDoesNotExits.GenWithStackByArgs(colName.O)
+				}
+			}
+		}
+	}
+	for _, spec := range addColumnSpecs {
+		if spec.Position != nil && spec.Position.Tp == ast.ColumnPositionAfter {
+			if _, ok := droppingColumns[spec.Position.RelativeColumn.Name.L]; ok {
+				return infoschema.ErrColumnNotExists.GenWithStackByArgs(spec.Position.RelativeColumn.Name, tblInfo.Name)
+			}
+		}
+	}
+	// The single-column indexes on the dropping columns are dropped together.
+	for _, indexInfo := range tblInfo.Indices {
+		if len(indexInfo.Columns) != 1 {
+			continue
+		}
+		if _, ok := droppingColumns[indexInfo.Columns[0].Name.L]; !ok {
+			continue
+		}
+		if _, ok := droppingIndexes[indexInfo.Name.L]; ok {
+			return ErrOperateSameIndex.GenWithStackByArgs(indexInfo.Name.O)
+		}
+	}
+	return nil
+}
+
 func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.AlterTableSpec) (err error) {
 	validSpecs, err := resolveAlterTableSpec(ctx, specs)
 	if err != nil {
@@ -2401,14 +2696,14 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 			case ast.AlterTableDropColumn:
 				err = d.DropColumns(ctx, ident, validSpecs)
 			default:
-				return errRunMultiSchemaChanges
+				err = d.multiSchemaChange(ctx, ident, validSpecs)
 			}
 			if err != nil {
 				return errors.Trace(err)
 			}
 			return nil
 		}
-		return errRunMultiSchemaChanges
+		return d.multiSchemaChange(ctx, ident, validSpecs)
 	}
 
 	for _, spec := range validSpecs {
@@ -2425,7 +2720,7 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 		case ast.AlterTableCoalescePartitions:
 			err = d.CoalescePartitions(ctx, ident, spec)
 		case ast.AlterTableReorganizePartition:
-			err = errors.Trace(errUnsupportedReorganizePartition)
+			err = d.ReorganizePartitions(ctx, ident, spec)
 		case ast.AlterTableCheckPartitions:
 			err = errors.Trace(errUnsupportedCheckPartition)
 		case ast.AlterTableRebuildPartition:
@@ -2433,7 +2728,7 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 		case ast.AlterTableOptimizePartition:
 			err = errors.Trace(errUnsupportedOptimizePartition)
 		case ast.AlterTableRemovePartitioning:
-			err = errors.Trace(errUnsupportedRemovePartition)
+			err = d.RemovePartitioning(ctx, ident, spec)
 		case ast.AlterTableRepairPartition:
 			err = errors.Trace(errUnsupportedRepairPartition)
 		case ast.AlterTableDropColumn:
@@ -2486,7 +2781,7 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 			case ast.ConstraintFulltext:
 				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrTableCantHandleFt)
 			case ast.ConstraintCheck:
-				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD CONSTRAINT CHECK"))
+				err = d.CreateCheckConstraint(ctx, ident, spec.Constraint)
 			default:
 				// Nothing to do now.
 			}
@@ -2512,8 +2807,7 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 				err = errors.New("alter partition alter placement is experimental and it is switched off by tidb_enable_alter_placement")
 			}
 		case ast.AlterTablePartition:
-			// Prevent silent succeed if user executes ALTER TABLE x PARTITION BY ...
-			err = errors.New("alter table partition is unsupported")
+			err = d.AlterTablePartitioning(ctx, ident, spec)
 		case ast.AlterTableOption:
 			for i, opt := range spec.Options {
 				switch opt.Tp {
@@ -2564,9 +2858,9 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 		case ast.AlterTableIndexInvisible:
 			err = d.AlterIndexVisibility(ctx, ident, spec.IndexName, spec.Visibility)
 		case ast.AlterTableAlterCheck:
-			ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ALTER CHECK"))
+			err = d.AlterCheckConstraint(ctx, ident, model.NewCIStr(spec.Constraint.Name), spec.Constraint.Enforced)
 		case ast.AlterTableDropCheck:
-			ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("DROP CHECK"))
+			err = d.DropCheckConstraint(ctx, ident, model.NewCIStr(spec.Constraint.Name))
 		case ast.AlterTableWithValidation:
 			ctx.GetSessionVars().StmtCtx.AppendWarning(errUnsupportedAlterTableWithValidation)
 		case ast.AlterTableWithoutValidation:
@@ -2696,6 +2990,8 @@ func checkUnsupportedColumnConstraint(col *ast.ColumnDef, ti ast.Ident) error {
 		case ast.ColumnOptionAutoRandom:
 			errMsg := fmt.Sprintf(autoid.AutoRandomAlterAddColumn, col.Name, ti.Schema, ti.Name)
 			return ErrInvalidAutoRandom.GenWithStackByArgs(errMsg)
+		case ast.ColumnOptionCheck:
+			return errUnsupportedAddColumn.GenWithStack("unsupported add column '%s' constraint CHECK when altering '%s.%s'", col.Name, ti.Schema, ti.Name)
 		}
 	}
 
@@ -2846,7 +3142,22 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 	if err != nil {
 		return errors.Trace(err)
 	}
+	job, err := newAddColumnsJob(ctx, ti, schema, t, specs)
+	if err != nil || job == nil {
+		return errors.Trace(err)
+	}
+
+	err = d.doDDLJob(ctx, job)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
 
+// newAddColumnsJob builds the job to add the new columns of specs, it returns nil if there is nothing to add.
+func newAddColumnsJob(ctx sessionctx.Context, ti ast.Ident, schema *model.DBInfo, t table.Table, specs []*ast.AlterTableSpec) (*model.Job, error) {
+	var err error
 	// Check all the columns at once.
 	addingColumnNames := make(map[string]bool)
 	dupColumnNames := make(map[string]bool)
@@ -2857,7 +3168,7 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 				continue
 			}
 			if !spec.IfNotExists {
-				return errors.Trace(infoschema.ErrColumnExists.GenWithStackByArgs(specNewColumn.Name.Name.O))
+				return nil, errors.Trace(infoschema.ErrColumnExists.GenWithStackByArgs(specNewColumn.Name.Name.O))
 			}
 			dupColumnNames[specNewColumn.Name.Name.L] = true
 		}
@@ -2877,7 +3188,7 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 			}
 			col, err := checkAndCreateNewColumn(ctx, ti, schema, spec, t, specNewColumn)
 			if err != nil {
-				return errors.Trace(err)
+				return nil, errors.Trace(err)
 			}
 			// Added column has existed and if_not_exists flag is true.
 			if col == nil && spec.IfNotExists {
@@ -2891,10 +3202,10 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 		}
 	}
 	if newColumnsCount == 0 {
-		return nil
+		return nil, nil
 	}
 	if err = checkAddColumnTooManyColumns(len(t.Cols()) + newColumnsCount); err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	job := &model.Job{
@@ -2905,13 +3216,7 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 		BinlogInfo: &model.HistoryInfo{},
 		Args:       []interface{}{columns, positions, offsets, ifNotExists},
 	}
-
-	err = d.doDDLJob(ctx, job)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	err = d.callHookOnChanged(err)
-	return errors.Trace(err)
+	return job, nil
 }
 
 // AddTablePartitions will add a new partition to the table.
@@ -2936,6 +3241,21 @@ func (d *ddl) AddTablePartitions(ctx sessionctx.Context, ident ast.Ident, spec *
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if pi.Type == model.PartitionTypeHash {
+		// The global indexes and the TiFlash replicas of the new partitions are not maintained yet.
+		if hasGlobalIndex(meta) || meta.TiFlashReplica != nil {
+			return errors.Trace(ErrUnsupportedAddPartition)
+		}
+		defs := make([]model.PartitionDefinition, 0, len(pi.Definitions)+len(partInfo.Definitions))
+		defs = append(defs, pi.Definitions...)
+		defs = append(defs, partInfo.Definitions...)
+		err = d.resizeHashPartitions(ctx, schema, meta, defs)
+		if ErrSameNamePartition.Equal(err) && spec.IfNotExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return errors.Trace(err)
+	}
 	if err := d.assignPartitionIDs(partInfo.Definitions); err != nil {
 		return errors.Trace(err)
 	}
@@ -2988,17 +3308,31 @@ func (d *ddl) CoalescePartitions(ctx sessionctx.Context, ident ast.Ident, spec *
 	}
 
 	meta := t.Meta()
-	if meta.GetPartitionInfo() == nil {
+	pi := meta.GetPartitionInfo()
+	if pi == nil {
 		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
 	}
 
-	switch meta.Partition.Type {
-	// We don't support coalesce partitions hash type partition now.
+	switch pi.Type {
 	case model.PartitionTypeHash:
-		return errors.Trace(ErrUnsupportedCoalescePartition)
+		if spec.Num == 0 {
+			return errors.Trace(ErrCoalescePartitionNoPartition)
+		}
+		if spec.Num >= uint64(len(pi.Definitions)) {
+			return errors.Trace(ErrDropLastPartition)
+		}
+		// The global indexes and the TiFlash replicas of the new partitions are not maintained yet.
+		if hasGlobalIndex(meta) || meta.TiFlashReplica != nil {
+			return errors.Trace(ErrUnsupportedCoalescePartition)
+		}
+		// The last partitions are removed, like MySQL does.
+		defs := make([]model.PartitionDefinition, len(pi.Definitions)-int(spec.Num))
+		copy(defs, pi.Definitions)
+		err = d.resizeHashPartitions(ctx, schema, meta, defs)
 
-	// Key type partition cannot be constructed currently, ignoring it for now.
+	// Key type partition cannot be constructed currently.
 	case model.PartitionTypeKey:
+		return errors.Trace(ErrUnsupportedCoalescePartition)
 
 	// Coalesce partition can only be used on hash/key partitions.
 	default:
@@ -3008,6 +3342,244 @@ func (d *ddl) CoalescePartitions(ctx sessionctx.Context, ident ast.Ident, spec *
 	return errors.Trace(err)
 }
 
+// resizeHashPartitions changes the number of the hash partitions to the number of defs.
+// The rows are redistributed by the new modulus, so all the partitions are reorganized into the new ones.
+func (d *ddl) resizeHashPartitions(ctx sessionctx.Context, schema *model.DBInfo, meta *model.TableInfo, defs []model.PartitionDefinition) error {
+	pi := meta.Partition
+	newDefs := make([]model.PartitionDefinition, len(defs))
+	for i, def := range defs {
+		newDefs[i] = model.PartitionDefinition{Name: def.Name, Comment: def.Comment}
+	}
+	if err := d.assignPartitionIDs(newDefs); err != nil {
+		return errors.Trace(err)
+	}
+	partInfo := &model.PartitionInfo{
+		Type:        pi.Type,
+		Expr:        pi.Expr,
+		Columns:     pi.Columns,
+		Enable:      pi.Enable,
+		Num:         uint64(len(newDefs)),
+		Definitions: newDefs,
+	}
+	partNames := make([]string, len(pi.Definitions))
+	for i, def := range pi.Definitions {
+		partNames[i] = def.Name.L
+	}
+
+	clonedMeta := meta.Clone()
+	tmp := *pi
+	tmp.Num = partInfo.Num
+	tmp.Definitions = newDefs
+	clonedMeta.Partition = &tmp
+	if err := checkPartitionDefinitionConstraints(ctx, clonedMeta); err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionReorganizePartition,
+		BinlogInfo: &model.HistoryInfo{},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Args: []interface{}{partNames, partInfo},
+	}
+
+	err := d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// ReorganizePartitions reorganizes the given range or list partitions into the new partitions.
+func (d *ddl) ReorganizePartitions(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	is := d.infoCache.GetLatest()
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return errors.Trace(infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema))
+	}
+	t, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
+	}
+
+	meta := t.Meta()
+	pi := meta.GetPartitionInfo()
+	if pi == nil {
+		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	if pi.Type != model.PartitionTypeRange && pi.Type != model.PartitionTypeList {
+		return errors.Trace(errUnsupportedReorganizePartition)
+	}
+	if spec.OnAllPartitions || len(spec.PartitionNames) == 0 {
+		return errors.Trace(ErrReorgNoParam)
+	}
+	// The global indexes and the TiFlash replicas of the new partitions are not maintained yet.
+	if hasGlobalIndex(meta) || meta.TiFlashReplica != nil {
+		return errors.Trace(errUnsupportedReorganizePartition)
+	}
+
+	partInfo, err := buildAddedPartitionInfo(ctx, meta, spec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+	partNames := make([]string, len(spec.PartitionNames))
+	for i, partCIName := range spec.PartitionNames {
+		partNames[i] = partCIName.L
+	}
+	droppingDefs, err := checkReorganizePartition(ctx, meta, partNames, partInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// The new partitions replace the reorganized ones, we have to combine them with the
+	// other partitions to check all partitions are valid.
+	clonedMeta := meta.Clone()
+	tmp := *pi
+	tmp.Definitions = tables.ReplacePartitionDefinitions(pi.Definitions, droppingDefs, partInfo.Definitions)
+	clonedMeta.Partition = &tmp
+	if err := checkPartitionDefinitionConstraints(ctx, clonedMeta); err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionReorganizePartition,
+		BinlogInfo: &model.HistoryInfo{},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Args: []interface{}{partNames, partInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// AlterTablePartitioning partitions a non-partitioned table, the rows are copied into the new partitions online.
+func (d *ddl) AlterTablePartitioning(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	is := d.infoCache.GetLatest()
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return errors.Trace(infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema))
+	}
+	t, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
+	}
+
+	meta := t.Meta()
+	if meta.Partition != nil {
+		// Prevent silent succeed if user executes ALTER TABLE x PARTITION BY ... on a partitioned table.
+		return errors.New("alter table partition is unsupported")
+	}
+	// The TiFlash replicas of the new partitions are not maintained yet.
+	if meta.TiFlashReplica != nil {
+		return errors.Trace(errUnsupportedPartitionBy)
+	}
+	newMeta := meta.Clone()
+	if err = buildTablePartitionInfo(ctx, spec.Partition, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if newMeta.Partition == nil {
+		// The partitioning isn't supported, a warning has been appended like creating the table.
+		return nil
+	}
+	if err = d.assignPartitionIDs(newMeta.Partition.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkPartitionDefinitionConstraints(ctx, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkPartitionFuncType(ctx, spec.Partition.Expr, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkPartitioningKeysConstraints(ctx, spec.Partition, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	// The existing unique indexes can't be turned into global indexes.
+	for _, idx := range newMeta.Indices {
+		if !idx.Unique {
+			continue
+		}
+		ok, err := checkPartitionKeysConstraint(newMeta.Partition, idx.Columns, newMeta)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !ok {
+			return errors.Trace(ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX"))
+		}
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterTablePartitioning,
+		BinlogInfo: &model.HistoryInfo{},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Args: []interface{}{newMeta.Partition},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// RemovePartitioning removes the partitioning of a partitioned table, the rows are copied into the table online.
+func (d *ddl) RemovePartitioning(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	is := d.infoCache.GetLatest()
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return errors.Trace(infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema))
+	}
+	t, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
+	}
+
+	meta := t.Meta()
+	if meta.GetPartitionInfo() == nil {
+		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	// The global indexes and the TiFlash replicas are not maintained yet.
+	if hasGlobalIndex(meta) || meta.TiFlashReplica != nil {
+		return errors.Trace(errUnsupportedRemovePartition)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionRemovePartitioning,
+		BinlogInfo: &model.HistoryInfo{},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) TruncateTablePartition(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
 	is := d.infoCache.GetLatest()
 	schema, ok := is.SchemaByName(ident.Schema)
@@ -3341,6 +3913,22 @@ func (d *ddl) DropColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alt
 	if err != nil {
 		return errors.Trace(err)
 	}
+	job, err := newDropColumnsJob(ctx, schema, t, specs)
+	if err != nil || job == nil {
+		return errors.Trace(err)
+	}
+
+	err = d.doDDLJob(ctx, job)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// newDropColumnsJob builds the job to drop the columns of specs, it returns nil if there is nothing to drop.
+func newDropColumnsJob(ctx sessionctx.Context, schema *model.DBInfo, t table.Table, specs []*ast.AlterTableSpec) (*model.Job, error) {
+	var err error
 	tblInfo := t.Meta()
 
 	dropingColumnNames := make(map[string]bool)
@@ -3353,7 +3941,7 @@ func (d *ddl) DropColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alt
 				dupColumnNames[spec.OldColumnName.Name.L] = true
 				continue
 			}
-			return errors.Trace(ErrCantDropFieldOrKey.GenWithStack("column %s doesn't exist", spec.OldColumnName.Name.O))
+			return nil, errors.Trace(ErrCantDropFieldOrKey.GenWithStack("column %s doesn't exist", spec.OldColumnName.Name.O))
 		}
 	}
 
@@ -3367,7 +3955,7 @@ func (d *ddl) DropColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alt
 		}
 		isDropable, err := checkIsDroppableColumn(ctx, t, spec)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		// Column can't drop and if_exists flag is true.
 		if !isDropable && spec.IfExists {
@@ -3377,15 +3965,15 @@ func (d *ddl) DropColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alt
 		ifExists = append(ifExists, spec.IfExists)
 	}
 	if len(colNames) == 0 {
-		return nil
+		return nil, nil
 	}
 	if len(tblInfo.Columns) == len(colNames) {
-		return ErrCantRemoveAllFields.GenWithStack("can't drop all columns in table %s",
+		return nil, ErrCantRemoveAllFields.GenWithStack("can't drop all columns in table %s",
 			tblInfo.Name)
 	}
 	err = checkDropVisibleColumnCnt(t, len(colNames))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	job := &model.Job{
@@ -3396,13 +3984,7 @@ func (d *ddl) DropColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alt
 		BinlogInfo: &model.HistoryInfo{},
 		Args:       []interface{}{colNames, ifExists},
 	}
-
-	err = d.doDDLJob(ctx, job)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	err = d.callHookOnChanged(err)
-	return errors.Trace(err)
+	return job, nil
 }
 
 func checkIsDroppableColumn(ctx sessionctx.Context, t table.Table, spec *ast.AlterTableSpec) (isDrapable bool, err error) {
@@ -3794,6 +4376,12 @@ func (d *ddl) getModifiableColumnJob(ctx sessionctx.Context, ident ast.Ident, or
 		if c != nil {
 			return nil, infoschema.ErrColumnExists.GenWithStackByArgs(newColName)
 		}
+		if err = checkColumnNotUsedByCheckConstraints(t.Meta(), originalColName); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err = checkColumnNotUsedByRowPolicies(t.Meta(), originalColName); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
 	// Constraints in the new column means adding new constraints. Errors should thrown,
@@ -3887,6 +4475,7 @@ func (d *ddl) getModifiableColumnJob(ctx sessionctx.Context, ident ast.Ident, or
 		if err = isGeneratedRelatedColumn(t.Meta(), newCol.ColumnInfo, col.ColumnInfo); err != nil {
 			return nil, errors.Trace(err)
 		}
+		// The table which is being partitioned or whose partitioning is being removed isn't supported either.
 		if t.Meta().Partition != nil {
 			return nil, errUnsupportedModifyColumn.GenWithStackByArgs("tidb_enable_change_column_type is true, table is partition table")
 		}
@@ -4154,6 +4743,12 @@ func (d *ddl) RenameColumn(ctx sessionctx.Context, ident ast.Ident, spec *ast.Al
 	if fkInfo := getColumnForeignKeyInfo(oldColName.L, tbl.Meta().ForeignKeys); fkInfo != nil {
 		return errFKIncompatibleColumns.GenWithStackByArgs(oldColName, fkInfo.Name)
 	}
+	if err = checkColumnNotUsedByCheckConstraints(tbl.Meta(), oldColName); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkColumnNotUsedByRowPolicies(tbl.Meta(), oldColName); err != nil {
+		return errors.Trace(err)
+	}
 
 	// Check generated expression.
 	for _, col := range allCols {
@@ -4433,10 +5028,6 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if !ctx.GetSessionVars().EnableExtendedStats {
 		return errors.New("Extended statistics feature is not generally available now, and tidb_enable_extended_stats is OFF")
 	}
-	// Not support Cardinality and Dependency statistics type for now.
-	if stats.StatsType == ast.StatsTypeCardinality || stats.StatsType == ast.StatsTypeDependency {
-		return errors.New("Cardinality and Dependency statistics types are not supported now")
-	}
 	_, tbl, err := d.getSchemaAndTableByIdent(ctx, ident)
 	if err != nil {
 		return err
@@ -4466,7 +5057,7 @@ func (d *ddl) AlterTableAddStatistics(ctx sessionctx.Context, ident ast.Ident, s
 	if len(colIDs) != 2 && (stats.StatsType == ast.StatsTypeCorrelation || stats.StatsType == ast.StatsTypeDependency) {
 		return errors.New("Only support Correlation and Dependency statistics types on 2 columns")
 	}
-	if len(colIDs) < 1 && stats.StatsType == ast.StatsTypeCardinality {
+	if len(colIDs) < 2 && stats.StatsType == ast.StatsTypeCardinality {
 		return errors.New("Only support Cardinality statistics type on at least 2 columns")
 	}
 	// TODO: check whether covering index exists for cardinality / dependency types.
@@ -4988,6 +5579,7 @@ func (d *ddl) CreatePrimaryKey(ctx sessionctx.Context, ti ast.Ident, indexName m
 
 func buildHiddenColumnInfo(ctx sessionctx.Context, indexPartSpecifications []*ast.IndexPartSpecification, indexName model.CIStr, tblInfo *model.TableInfo, existCols []*table.Column) ([]*model.ColumnInfo, error) {
 	hiddenCols := make([]*model.ColumnInfo, 0, len(indexPartSpecifications))
+	arrayCnt := 0
 	for i, idxPart := range indexPartSpecifications {
 		if idxPart.Expr == nil {
 			continue
@@ -5005,8 +5597,19 @@ func buildHiddenColumnInfo(ctx sessionctx.Context, indexPartSpecifications []*as
 			// TODO: Refine the error message.
 			return nil, ErrTooLongIdent.GenWithStackByArgs("hidden column")
 		}
+		// The key part of a multi-valued index is `CAST(... AS ... ARRAY)`, the array can't be used elsewhere.
+		checkedExpr := idxPart.Expr
+		if castExpr, ok := idxPart.Expr.(*ast.FuncCastExpr); ok && castExpr.Tp.ArrayElem != nil {
+			if arrayCnt++; arrayCnt > 1 {
+				return nil, ErrNotSupportedYet.GenWithStackByArgs("more than one multi-valued key part per index")
+			}
+			if err := checkArrayElemType(castExpr.Tp.ArrayElem); err != nil {
+				return nil, errors.Trace(err)
+			}
+			checkedExpr = castExpr.Expr
+		}
 		// TODO: refine the error message.
-		if err := checkIllegalFn4Generated(indexName.L, typeIndex, idxPart.Expr); err != nil {
+		if err := checkIllegalFn4Generated(indexName.L, typeIndex, checkedExpr); err != nil {
 			return nil, errors.Trace(err)
 		}
 
@@ -5051,24 +5654,56 @@ func buildHiddenColumnInfo(ctx sessionctx.Context, indexPartSpecifications []*as
 		idxPart.Expr = nil
 		hiddenCols = append(hiddenCols, colInfo)
 	}
-	if len(hiddenCols) > 0 && !config.GetGlobalConfig().Experimental.AllowsExpressionIndex {
+	// The multi-valued indexes don't need allow-expression-index.
+	if len(hiddenCols) > arrayCnt && !config.GetGlobalConfig().Experimental.AllowsExpressionIndex {
 		return nil, ErrUnsupportedExpressionIndex
 	}
 	return hiddenCols, nil
 }
 
+// checkArrayElemType checks whether the elements of the array of a multi-valued index can be of the type.
+func checkArrayElemType(elemTp *types.FieldType) error {
+	switch elemTp.Tp {
+	case mysql.TypeLonglong, mysql.TypeDouble:
+		return nil
+	case mysql.TypeVarString:
+		if elemTp.Flen > 0 {
+			return nil
+		}
+	}
+	return ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("CAST-ing data to array of %s", elemTp.String()))
+}
+
 func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.IndexKeyType, indexName model.CIStr,
 	indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption, ifNotExists bool) error {
 	// not support Spatial and FullText index
 	if keyType == ast.IndexKeyTypeFullText || keyType == ast.IndexKeyTypeSpatial {
 		return errUnsupportedIndexType.GenWithStack("FULLTEXT and SPATIAL index is not supported")
 	}
-	unique := keyType == ast.IndexKeyTypeUnique
 	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	job, err := newCreateIndexJob(ctx, schema, t, keyType, indexName, indexPartSpecifications, indexOption, ifNotExists)
+	if err != nil || job == nil {
+		return errors.Trace(err)
+	}
+
+	err = d.doDDLJob(ctx, job)
+	// key exists, but if_not_exists flags is true, so we ignore this error.
+	if ErrDupKeyName.Equal(err) && ifNotExists {
+		ctx.GetSessionVars().StmtCtx.AppendNote(err)
+		return nil
+	}
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
 
+// newCreateIndexJob builds the job to add the index, it returns nil if the index exists and ifNotExists is true.
+func newCreateIndexJob(ctx sessionctx.Context, schema *model.DBInfo, t table.Table, keyType ast.IndexKeyType, indexName model.CIStr,
+	indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption, ifNotExists bool) (*model.Job, error) {
+	var err error
+	unique := keyType == ast.IndexKeyTypeUnique
 	// Deal with anonymous index.
 	if len(indexName.L) == 0 {
 		colName := model.NewCIStr("expression_index")
@@ -5089,13 +5724,13 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 		}
 		if ifNotExists {
 			ctx.GetSessionVars().StmtCtx.AppendNote(err)
-			return nil
+			return nil, nil
 		}
-		return err
+		return nil, err
 	}
 
 	if err = checkTooLongIndex(indexName); err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	tblInfo := t.Meta()
@@ -5103,10 +5738,10 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	// Build hidden columns if necessary.
 	hiddenCols, err := buildHiddenColumnInfo(ctx, indexPartSpecifications, indexName, t.Meta(), t.Cols())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if err = checkAddColumnTooManyColumns(len(t.Cols()) + len(hiddenCols)); err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	// Check before the job is put to the queue.
@@ -5117,7 +5752,7 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	// For same reason, decide whether index is global here.
 	indexColumns, err := buildIndexColumns(append(tblInfo.Columns, hiddenCols...), indexPartSpecifications)
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	if !unique && tblInfo.IsCommonHandle {
@@ -5125,14 +5760,14 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 		var pkLen, idxLen int
 		pkLen, err = indexColumnsLen(tblInfo.Columns, tables.FindPrimaryIndex(tblInfo).Columns)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		idxLen, err = indexColumnsLen(tblInfo.Columns, indexColumns)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if pkLen+idxLen > config.GetGlobalConfig().MaxIndexLength {
-			return errTooLongKey.GenWithStackByArgs(config.GetGlobalConfig().MaxIndexLength)
+			return nil, errTooLongKey.GenWithStackByArgs(config.GetGlobalConfig().MaxIndexLength)
 		}
 	}
 
@@ -5140,11 +5775,11 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	if unique && tblInfo.GetPartitionInfo() != nil {
 		ck, err := checkPartitionKeysConstraint(tblInfo.GetPartitionInfo(), indexColumns, tblInfo)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if !ck {
 			if !config.GetGlobalConfig().EnableGlobalIndex {
-				return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
+				return nil, ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
 			}
 			// index columns does not contain all partition columns, must set global
 			global = true
@@ -5152,7 +5787,7 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	}
 	// May be truncate comment here, when index comment too long and sql_mode is't strict.
 	if _, err = validateCommentLength(ctx.GetSessionVars(), indexName.String(), indexOption); err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -5168,15 +5803,7 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 		Args:     []interface{}{unique, indexName, indexPartSpecifications, indexOption, hiddenCols, global},
 		Priority: ctx.GetSessionVars().DDLReorgPriority,
 	}
-
-	err = d.doDDLJob(ctx, job)
-	// key exists, but if_not_exists flags is true, so we ignore this error.
-	if ErrDupKeyName.Equal(err) && ifNotExists {
-		ctx.GetSessionVars().StmtCtx.AppendNote(err)
-		return nil
-	}
-	err = d.callHookOnChanged(err)
-	return errors.Trace(err)
+	return job, nil
 }
 
 func buildFKInfo(fkName model.CIStr, keys []*ast.IndexPartSpecification, refer *ast.ReferenceDef, cols []*table.Column, tbInfo *model.TableInfo) (*model.FKInfo, error) {
@@ -5269,6 +5896,14 @@ func (d *ddl) CreateForeignKey(ctx sessionctx.Context, ti ast.Ident, fkName mode
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// Like MySQL, an index is created for the foreign key if there is none, see buildTableInfo.
+	if !hasForeignKeyIndex(t.Meta(), fkInfo.Cols) {
+		indexName := foreignKeyIndexName(t.Meta(), fkInfo)
+		err = d.CreateIndex(ctx, ti, ast.IndexKeyTypeNone, indexName, foreignKeyIndexPartSpecifications(fkInfo), nil, false)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -5311,6 +5946,165 @@ func (d *ddl) DropForeignKey(ctx sessionctx.Context, ti ast.Ident, fkName model.
 	return errors.Trace(err)
 }
 
+// CreateCheckConstraint adds a check constraint to the table, the existing rows are verified before it's public.
+func (d *ddl) CreateCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constr *ast.Constraint) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tblInfo := t.Meta()
+	if constr.Name == "" {
+		constrNames := make(map[string]bool, len(tblInfo.Constraints))
+		for _, constrInfo := range tblInfo.Constraints {
+			constrNames[constrInfo.Name.L] = true
+		}
+		setEmptyCheckConstraintNames(tblInfo.Name, constrNames, []*ast.Constraint{constr})
+	} else if tblInfo.FindConstraintInfoByName(constr.Name) != nil {
+		return ErrCheckConstraintDupName.GenWithStackByArgs(constr.Name)
+	}
+	if err = checkCheckConstraint(ctx, tblInfo, constr); err != nil {
+		return errors.Trace(err)
+	}
+	constrInfo, err := buildConstraintInfo(tblInfo, constr, model.StateNone)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAddCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constrInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// DropCheckConstraint drops a check constraint of the table.
+func (d *ddl) DropCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constrName model.CIStr) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if t.Meta().FindConstraintInfoByName(constrName.L) == nil {
+		return ErrConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionDropCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constrName},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// AlterCheckConstraint changes whether a check constraint is enforced.
+// The existing rows are verified when a constraint is changed to be enforced.
+func (d *ddl) AlterCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constrName model.CIStr, enforced bool) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	constrInfo := t.Meta().FindConstraintInfoByName(constrName.L)
+	if constrInfo == nil {
+		return ErrConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+	if constrInfo.Enforced == enforced {
+		return nil
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constrName, enforced},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// CreateRowPolicy creates a row-level security policy on the table.
+func (d *ddl) CreateRowPolicy(ctx sessionctx.Context, stmt *ast.CreatePolicyStmt) error {
+	ident := ast.Ident{Schema: stmt.Table.Schema, Name: stmt.Table.Name}
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tblInfo := t.Meta()
+	if tblInfo.IsView() || tblInfo.IsSequence() {
+		return ErrWrongObject.GenWithStackByArgs(ident.Schema, ident.Name, "BASE TABLE")
+	}
+	if tblInfo.FindRowPolicyByName(stmt.PolicyName.L) != nil {
+		err = ErrRowPolicyExists.GenWithStackByArgs(stmt.PolicyName.O, tblInfo.Name.O)
+		if stmt.IfNotExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	policyInfo, err := buildRowPolicyInfo(tblInfo, stmt)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionCreateRowPolicy,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{policyInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// DropRowPolicy drops a row-level security policy of the table.
+func (d *ddl) DropRowPolicy(ctx sessionctx.Context, stmt *ast.DropPolicyStmt) error {
+	ident := ast.Ident{Schema: stmt.Table.Schema, Name: stmt.Table.Name}
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if t.Meta().FindRowPolicyByName(stmt.PolicyName.L) == nil {
+		err = ErrRowPolicyNotExists.GenWithStackByArgs(stmt.PolicyName.O, t.Meta().Name.O)
+		if stmt.IfExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionDropRowPolicy,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{stmt.PolicyName},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CIStr, ifExists bool) error {
 	is := d.infoCache.GetLatest()
 	schema, ok := is.SchemaByName(ti.Schema)
@@ -5322,6 +6116,24 @@ func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CI
 		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ti.Schema, ti.Name))
 	}
 
+	job, err := newDropIndexJob(ctx, schema, t, indexName, ifExists)
+	if err != nil || job == nil {
+		return errors.Trace(err)
+	}
+
+	err = d.doDDLJob(ctx, job)
+	// index not exists, but if_exists flags is true, so we ignore this error.
+	if ErrCantDropFieldOrKey.Equal(err) && ifExists {
+		ctx.GetSessionVars().StmtCtx.AppendNote(err)
+		return nil
+	}
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// newDropIndexJob builds the job to drop the index, it returns nil if the index doesn't exist and ifExists is true.
+func newDropIndexJob(ctx sessionctx.Context, schema *model.DBInfo, t table.Table, indexName model.CIStr, ifExists bool) (*model.Job, error) {
+	var err error
 	indexInfo := t.Meta().FindIndexByName(indexName.L)
 	var isPK bool
 	if indexName.L == strings.ToLower(mysql.PrimaryKeyName) &&
@@ -5332,28 +6144,28 @@ func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CI
 	if isPK {
 		// If the table's PKIsHandle is true, we can't find the index from the table. So we check the value of PKIsHandle.
 		if indexInfo == nil && !t.Meta().PKIsHandle {
-			return ErrCantDropFieldOrKey.GenWithStack("Can't DROP 'PRIMARY'; check that column/key exists")
+			return nil, ErrCantDropFieldOrKey.GenWithStack("Can't DROP 'PRIMARY'; check that column/key exists")
 		}
 		if t.Meta().PKIsHandle {
-			return ErrUnsupportedModifyPrimaryKey.GenWithStack("Unsupported drop primary key when the table's pkIsHandle is true")
+			return nil, ErrUnsupportedModifyPrimaryKey.GenWithStack("Unsupported drop primary key when the table's pkIsHandle is true")
 		}
 		if t.Meta().IsCommonHandle {
-			return ErrUnsupportedModifyPrimaryKey.GenWithStack("Unsupported drop primary key when the table is using clustered index")
+			return nil, ErrUnsupportedModifyPrimaryKey.GenWithStack("Unsupported drop primary key when the table is using clustered index")
 		}
 	}
 	if indexInfo == nil {
 		err = ErrCantDropFieldOrKey.GenWithStack("index %s doesn't exist", indexName)
 		if ifExists {
 			ctx.GetSessionVars().StmtCtx.AppendNote(err)
-			return nil
+			return nil, nil
 		}
-		return err
+		return nil, err
 	}
 
 	// Check for drop index on auto_increment column.
 	err = checkDropIndexOnAutoIncrementColumn(t.Meta(), indexInfo)
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	jobTp := model.ActionDropIndex
@@ -5369,15 +6181,7 @@ func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CI
 		BinlogInfo: &model.HistoryInfo{},
 		Args:       []interface{}{indexName},
 	}
-
-	err = d.doDDLJob(ctx, job)
-	// index not exists, but if_exists flags is true, so we ignore this error.
-	if ErrCantDropFieldOrKey.Equal(err) && ifExists {
-		ctx.GetSessionVars().StmtCtx.AppendNote(err)
-		return nil
-	}
-	err = d.callHookOnChanged(err)
-	return errors.Trace(err)
+	return job, nil
 }
 
 func isDroppableColumn(tblInfo *model.TableInfo, colName model.CIStr) error {
@@ -5397,7 +6201,10 @@ func isDroppableColumn(tblInfo *model.TableInfo, colName model.CIStr) error {
 	if fkInfo := getColumnForeignKeyInfo(colName.L, tblInfo.ForeignKeys); fkInfo != nil {
 		return errFkColumnCannotDrop.GenWithStackByArgs(colName, fkInfo.Name)
 	}
-	return nil
+	if err := checkColumnNotUsedByCheckConstraints(tblInfo, colName); err != nil {
+		return err
+	}
+	return checkColumnNotUsedByRowPolicies(tblInfo, colName)
 }
 
 // validateCommentLength checks comment length of table, column, index and partition.
@@ -5427,6 +6234,8 @@ func buildAddedPartitionInfo(ctx sessionctx.Context, meta *model.TableInfo, spec
 		if len(spec.PartDefinitions) == 0 {
 			return nil, ast.ErrPartitionsMustBeDefined.GenWithStackByArgs(meta.Partition.Type)
 		}
+	case model.PartitionTypeHash:
+		return buildAddedHashPartitionInfo(meta, spec)
 	default:
 		// we don't support ADD PARTITION for all other partition types yet.
 		return nil, errors.Trace(ErrUnsupportedAddPartition)
@@ -5448,6 +6257,43 @@ func buildAddedPartitionInfo(ctx sessionctx.Context, meta *model.TableInfo, spec
 	return part, nil
 }
 
+// buildAddedHashPartitionInfo builds the info of the partitions added to a hash partitioned table,
+// the new partitions are named p<N>, p<N+1>... if they are added by ADD PARTITION PARTITIONS N.
+func buildAddedHashPartitionInfo(meta *model.TableInfo, spec *ast.AlterTableSpec) (*model.PartitionInfo, error) {
+	pi := meta.Partition
+	num := spec.Num
+	if len(spec.PartDefinitions) > 0 {
+		num = uint64(len(spec.PartDefinitions))
+	}
+	if num == 0 {
+		return nil, errors.Trace(ErrAddPartitionNoNewPartition)
+	}
+	if err := checkAddPartitionTooManyPartitions(uint64(len(pi.Definitions)) + num); err != nil {
+		return nil, err
+	}
+
+	defs := make([]model.PartitionDefinition, num)
+	for i := range defs {
+		if len(spec.PartDefinitions) == 0 {
+			defs[i].Name = model.NewCIStr(fmt.Sprintf("p%v", len(pi.Definitions)+i))
+			continue
+		}
+		def := spec.PartDefinitions[i]
+		if err := def.Clause.Validate(model.PartitionTypeHash, len(pi.Columns)); err != nil {
+			return nil, errors.Trace(err)
+		}
+		defs[i].Name = def.Name
+		defs[i].Comment, _ = def.Comment()
+	}
+	return &model.PartitionInfo{
+		Type:        pi.Type,
+		Expr:        pi.Expr,
+		Columns:     pi.Columns,
+		Enable:      pi.Enable,
+		Definitions: defs,
+	}, nil
+}
+
 func checkColumnsTypeAndValuesMatch(ctx sessionctx.Context, meta *model.TableInfo, exprs []ast.ExprNode) error {
 	// Validate() has already checked len(colNames) = len(exprs)
 	// create table ... partition by range columns (cols)
@@ -288,7 +288,8 @@ func insertJobIntoDeleteRangeTable(ctx sessionctx.Context, job *model.Job) error
 		startKey = tablecodec.EncodeTablePrefix(tableID)
 		endKey := tablecodec.EncodeTablePrefix(tableID + 1)
 		return doInsert(s, job.ID, tableID, startKey, endKey, now)
-	case model.ActionDropTablePartition, model.ActionTruncateTablePartition:
+	case model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionReorganizePartition,
+		model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
 		var physicalTableIDs []int64
 		if err := job.DecodeArgs(&physicalTableIDs); err != nil {
 			return errors.Trace(err)
@@ -379,6 +380,19 @@ func insertJobIntoDeleteRangeTable(ctx sessionctx.Context, job *model.Job) error
 				return doBatchDeleteIndiceRange(s, job.ID, job.TableID, indexIDs, now)
 			}
 		}
+	case model.ActionMultiSchemaChange:
+		info := &multiSchemaInfo{}
+		if err := job.DecodeArgs(info); err != nil {
+			return errors.Trace(err)
+		}
+		for _, sub := range info.SubJobs {
+			if !sub.needDeleteRange() {
+				continue
+			}
+			if err := insertJobIntoDeleteRangeTable(ctx, sub.toProxyJob(job)); err != nil {
+				return errors.Trace(err)
+			}
+		}
 	case model.ActionModifyColumn:
 		var indexIDs []int64
 		var partitionIDs []int64
@@ -95,6 +95,7 @@ var (
 	errUnsupportedCheckPartition      = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "check partition"), nil))
 	errUnsupportedOptimizePartition   = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "optimize partition"), nil))
 	errUnsupportedRebuildPartition    = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "rebuild partition"), nil))
+	errUnsupportedPartitionBy         = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "partition by on a table with TiFlash replicas"), nil))
 	errUnsupportedRemovePartition     = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "remove partitioning"), nil))
 	errUnsupportedRepairPartition     = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "repair partition"), nil))
 	// ErrGeneratedColumnFunctionIsNotAllowed returns for unsupported functions for generated columns.
@@ -191,6 +192,16 @@ var (
 	ErrWarnDataTruncated = dbterror.ClassDDL.NewStd(mysql.WarnDataTruncated)
 	// ErrCoalesceOnlyOnHashPartition returns coalesce partition can only be used on hash/key partitions.
 	ErrCoalesceOnlyOnHashPartition = dbterror.ClassDDL.NewStd(mysql.ErrCoalesceOnlyOnHashPartition)
+	// ErrAddPartitionNoNewPartition returns at least one partition must be added.
+	ErrAddPartitionNoNewPartition = dbterror.ClassDDL.NewStd(mysql.ErrAddPartitionNoNewPartition)
+	// ErrCoalescePartitionNoPartition returns at least one partition must be coalesced.
+	ErrCoalescePartitionNoPartition = dbterror.ClassDDL.NewStd(mysql.ErrCoalescePartitionNoPartition)
+	// ErrReorgNoParam returns REORGANIZE PARTITION without parameters can only be used on hash partitions.
+	ErrReorgNoParam = dbterror.ClassDDL.NewStd(mysql.ErrReorgNoParam)
+	// ErrConsecutiveReorgPartitions returns the reorganized range partitions must be consecutive.
+	ErrConsecutiveReorgPartitions = dbterror.ClassDDL.NewStd(mysql.ErrConsecutiveReorgPartitions)
+	// ErrReorgOutsideRange returns the reorganized range partitions can't change the total range except for the last partition.
+	ErrReorgOutsideRange = dbterror.ClassDDL.NewStd(mysql.ErrReorgOutsideRange)
 	// ErrViewWrongList returns create view must include all columns in the select clause
 	ErrViewWrongList = dbterror.ClassDDL.NewStd(mysql.ErrViewWrongList)
 	// ErrAlterOperationNotSupported returns when alter operations is not supported.
@@ -209,6 +220,8 @@ var (
 	ErrFunctionalIndexPrimaryKey = dbterror.ClassDDL.NewStd(mysql.ErrFunctionalIndexPrimaryKey)
 	// ErrFunctionalIndexOnField returns 'Functional index on a column is not supported. Consider using a regular index instead'
 	ErrFunctionalIndexOnField = dbterror.ClassDDL.NewStd(mysql.ErrFunctionalIndexOnField)
+	// ErrNotSupportedYet returns when the feature isn't supported yet, like the unsupported usages of the multi-valued indexes.
+	ErrNotSupportedYet = dbterror.ClassDDL.NewStd(mysql.ErrNotSupportedYet)
 	// ErrInvalidAutoRandom returns when auto_random is used incorrectly.
 	ErrInvalidAutoRandom = dbterror.ClassDDL.NewStd(mysql.ErrInvalidAutoRandom)
 	// ErrUnsupportedConstraintCheck returns when use ADD CONSTRAINT CHECK
@@ -258,6 +271,35 @@ var (
 	// ErrInvalidPlacementPolicyCheck is returned when txn_scope and commit data changing do not meet the placement policy
 	ErrInvalidPlacementPolicyCheck = dbterror.ClassDDL.NewStd(mysql.ErrPlacementPolicyCheck)
 
+	// ErrOperateSameColumn is returned when a multi-schema change operates the same column more than once.
+	ErrOperateSameColumn = dbterror.ClassDDL.NewStd(mysql.ErrOperateSameColumn)
+	// ErrOperateSameIndex is returned when a multi-schema change operates the same index more than once.
+	ErrOperateSameIndex = dbterror.ClassDDL.NewStd(mysql.ErrOperateSameIndex)
+
+	// ErrColumnCheckConstraintReferencesOtherColumn is returned when a column check constraint references other columns.
+	ErrColumnCheckConstraintReferencesOtherColumn = dbterror.ClassDDL.NewStd(mysql.ErrColumnCheckConstraintReferencesOtherColumn)
+	// ErrCheckConstraintFunctionIsNotAllowed is returned when a check constraint uses disallowed functions.
+	ErrCheckConstraintFunctionIsNotAllowed = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintFunctionIsNotAllowed)
+	// ErrCheckConstraintVariables is returned when a check constraint refers to user or system variables.
+	ErrCheckConstraintVariables = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintVariables)
+	// ErrCheckConstraintRefersAutoIncrementColumn is returned when a check constraint refers to an auto-increment column.
+	ErrCheckConstraintRefersAutoIncrementColumn = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintRefersAutoIncrementColumn)
+	// ErrTableCheckConstraintReferUnknown is returned when a check constraint refers to a non-existing column.
+	ErrTableCheckConstraintReferUnknown = dbterror.ClassDDL.NewStd(mysql.ErrTableCheckConstraintReferUnknown)
+	// ErrCheckConstraintDupName is returned when the name of a check constraint is duplicated.
+	ErrCheckConstraintDupName = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintDupName)
+	// ErrConstraintNotFound is returned when the check constraint to alter or drop does not exist.
+	ErrConstraintNotFound = dbterror.ClassDDL.NewStd(mysql.ErrConstraintNotFound)
+	// ErrDependentByCheckConstraint is returned when a column used by a check constraint is dropped or renamed.
+	ErrDependentByCheckConstraint = dbterror.ClassDDL.NewStd(mysql.ErrDependentByCheckConstraint)
+	// ErrRowPolicyExists is returned when the row-level security policy to create already exists on the table.
+	ErrRowPolicyExists = dbterror.ClassDDL.NewStd(mysql.ErrRowPolicyExists)
+	// ErrRowPolicyNotExists is returned when the row-level security policy to drop doesn't exist on the table.
+	ErrRowPolicyNotExists = dbterror.ClassDDL.NewStd(mysql.ErrRowPolicyNotExists)
+	// ErrDependentByRowPolicy is returned when a column used by a row-level security policy is dropped or renamed.
+	ErrDependentByRowPolicy = dbterror.ClassDDL.NewStdErr(mysql.ErrDependentByCheckConstraint,
+		parser_mysql.Message("Row-level security policy '%s' uses column '%s', hence column cannot be dropped or renamed.", nil))
+
 	// ErrMultipleDefConstInListPart returns multiple definition of same constant in list partitioning.
 	ErrMultipleDefConstInListPart = dbterror.ClassDDL.NewStd(mysql.ErrMultipleDefConstInListPart)
 
@@ -14,10 +14,14 @@
 package ddl
 
 import (
+	"fmt"
+
 	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/types"
 )
 
 func onCreateForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
@@ -110,3 +114,52 @@ func onDropForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	}
 
 }
+
+// hasForeignKeyIndex returns whether the columns of the foreign key are the integer primary key or the leading
+// columns of an index, which is needed to find the rows of a foreign key without scanning the table.
+func hasForeignKeyIndex(tblInfo *model.TableInfo, cols []model.CIStr) bool {
+	if len(cols) == 1 && tblInfo.PKIsHandle {
+		if pkCol := tblInfo.GetPkColInfo(); pkCol != nil && pkCol.Name.L == cols[0].L {
+			return true
+		}
+	}
+	for _, idxInfo := range tblInfo.Indices {
+		if len(idxInfo.Columns) < len(cols) {
+			continue
+		}
+		matched := true
+		for i, col := range cols {
+			idxCol := idxInfo.Columns[i]
+			if idxCol.Name.L != col.L || idxCol.Length != types.UnspecifiedLength {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// foreignKeyIndexName returns the name of the index created for the foreign key, it's the name of the foreign
+// key like MySQL, or the name of its first column (with a suffix) if the name is used by another index.
+func foreignKeyIndexName(tblInfo *model.TableInfo, fk *model.FKInfo) model.CIStr {
+	name := fk.Name
+	if name.L == "" || tblInfo.FindIndexByName(name.L) != nil {
+		name = fk.Cols[0]
+	}
+	for i := 2; tblInfo.FindIndexByName(name.L) != nil; i++ {
+		name = model.NewCIStr(fmt.Sprintf("%s_%d", fk.Cols[0].O, i))
+	}
+	return name
+}
+
+// foreignKeyIndexPartSpecifications returns the columns of the index created for the foreign key.
+func foreignKeyIndexPartSpecifications(fk *model.FKInfo) []*ast.IndexPartSpecification {
+	keys := make([]*ast.IndexPartSpecification, 0, len(fk.Cols))
+	for _, col := range fk.Cols {
+		keys = append(keys, &ast.IndexPartSpecification{Column: &ast.ColumnName{Name: col}, Length: types.UnspecifiedLength})
+	}
+	return keys
+}
@@ -265,6 +265,8 @@ type illegalFunctionChecker struct {
 	hasAggFunc     bool
 	hasRowVal      bool // hasRowVal checks whether the functional index refers to a row value
 	hasWindowFunc  bool
+	hasVariable    bool // hasVariable checks whether the expression refers to a user or system variable
+	hasCastArray   bool // hasCastArray checks whether the expression has `CAST(... AS ... ARRAY)`
 	otherErr       error
 }
 
@@ -282,10 +284,15 @@ func (c *illegalFunctionChecker) Enter(inNode ast.Node) (outNode ast.Node, skipC
 			c.otherErr = err
 			return inNode, true
 		}
-	case *ast.SubqueryExpr, *ast.ValuesExpr, *ast.VariableExpr:
-		// Subquery & `values(x)` & variable is not allowed
+	case *ast.SubqueryExpr, *ast.ValuesExpr:
+		// Subquery & `values(x)` is not allowed
 		c.hasIllegalFunc = true
 		return inNode, true
+	case *ast.VariableExpr:
+		// Variable is not allowed
+		c.hasIllegalFunc = true
+		c.hasVariable = true
+		return inNode, true
 	case *ast.AggregateFuncExpr:
 		// Aggregate function is not allowed
 		c.hasAggFunc = true
@@ -296,6 +303,11 @@ func (c *illegalFunctionChecker) Enter(inNode ast.Node) (outNode ast.Node, skipC
 	case *ast.WindowFuncExpr:
 		c.hasWindowFunc = true
 		return inNode, true
+	case *ast.FuncCastExpr:
+		if node.Tp.ArrayElem != nil {
+			c.hasCastArray = true
+			return inNode, true
+		}
 	}
 	return inNode, false
 }
@@ -307,6 +319,7 @@ func (c *illegalFunctionChecker) Leave(inNode ast.Node) (node ast.Node, ok bool)
 const (
 	typeColumn = iota
 	typeIndex
+	typeCheckConstraint
 )
 
 func checkIllegalFn4Generated(name string, genType int, expr ast.ExprNode) error {
@@ -315,12 +328,17 @@ func checkIllegalFn4Generated(name string, genType int, expr ast.ExprNode) error
 	}
 	var c illegalFunctionChecker
 	expr.Accept(&c)
+	if c.hasVariable && genType == typeCheckConstraint {
+		return ErrCheckConstraintVariables.GenWithStackByArgs(name)
+	}
 	if c.hasIllegalFunc {
 		switch genType {
 		case typeColumn:
 			return ErrGeneratedColumnFunctionIsNotAllowed.GenWithStackByArgs(name)
 		case typeIndex:
 			return ErrFunctionalIndexFunctionIsNotAllowed.GenWithStackByArgs(name)
+		case typeCheckConstraint:
+			return ErrCheckConstraintFunctionIsNotAllowed.GenWithStackByArgs(name)
 		}
 	}
 	if c.hasAggFunc {
@@ -332,11 +350,17 @@ func checkIllegalFn4Generated(name string, genType int, expr ast.ExprNode) error
 			return ErrGeneratedColumnRowValueIsNotAllowed.GenWithStackByArgs(name)
 		case typeIndex:
 			return ErrFunctionalIndexRowValueIsNotAllowed.GenWithStackByArgs(name)
+		case typeCheckConstraint:
+			return ErrCheckConstraintFunctionIsNotAllowed.GenWithStackByArgs(name)
 		}
 	}
 	if c.hasWindowFunc {
 		return errWindowInvalidWindowFuncUse.GenWithStackByArgs(name)
 	}
+	if c.hasCastArray {
+		// The array of a multi-valued index is checked by the caller, see buildHiddenColumnInfo.
+		return ErrNotSupportedYet.GenWithStackByArgs("Use of CAST( .. AS .. ARRAY) outside of functional index in CREATE(non-SELECT)/ALTER TABLE or in general expressions")
+	}
 	if c.otherErr != nil {
 		return c.otherErr
 	}
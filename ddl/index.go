@@ -127,8 +127,8 @@ func checkIndexColumn(col *model.ColumnInfo, indexColumnLen int) error {
 		return errors.Trace(errWrongKeyColumn.GenWithStackByArgs(col.Name))
 	}
 
-	// JSON column cannot index.
-	if col.FieldType.Tp == mysql.TypeJSON {
+	// JSON column cannot index, but the array of a multi-valued index can.
+	if col.FieldType.Tp == mysql.TypeJSON && col.FieldType.ArrayElem == nil {
 		return errors.Trace(errJSONUsedAsKey.GenWithStackByArgs(col.Name.O))
 	}
 
@@ -168,6 +168,12 @@ func checkIndexColumn(col *model.ColumnInfo, indexColumnLen int) error {
 
 // getIndexColumnLength calculate the bytes number required in an index column.
 func getIndexColumnLength(col *model.ColumnInfo, colLen int) (int, error) {
+	if col.FieldType.ArrayElem != nil {
+		// The entries of a multi-valued index are the elements of the array.
+		elemCol := col.Clone()
+		elemCol.FieldType = *col.FieldType.ArrayElem
+		return getIndexColumnLength(elemCol, colLen)
+	}
 	length := types.UnspecifiedLength
 	if colLen != types.UnspecifiedLength {
 		length = colLen
@@ -224,6 +230,11 @@ func buildIndexInfo(tblInfo *model.TableInfo, indexName model.CIStr, indexPartSp
 		Columns: idxColumns,
 		State:   state,
 	}
+	for _, idxCol := range idxColumns {
+		if tblInfo.Columns[idxCol.Offset].FieldType.ArrayElem != nil {
+			idxInfo.MVIndex = true
+		}
+	}
 	return idxInfo, nil
 }
 
@@ -533,54 +544,17 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 	case model.StateWriteReorganization:
 		// reorganization -> public
 		updateHiddenColumns(tblInfo, indexInfo, model.StatePublic)
-		tbl, err := getTable(d.store, schemaID, tblInfo)
+		var tbl table.Table
+		tbl, err = getTable(d.store, schemaID, tblInfo)
 		if err != nil {
 			return ver, errors.Trace(err)
 		}
-
-		elements := []*meta.Element{{ID: indexInfo.ID, TypeKey: meta.IndexElementKey}}
-		reorgInfo, err := getReorgInfo(d, t, job, tbl, elements)
-		if err != nil || reorgInfo.first {
-			// If we run reorg firstly, we should update the job snapshot version
-			// and then run the reorg next time.
-			return ver, errors.Trace(err)
-		}
-
-		err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (addIndexErr error) {
-			defer util.Recover(metrics.LabelDDL, "onCreateIndex",
-				func() {
-					addIndexErr = errCancelledDDLJob.GenWithStack("add table `%v` index `%v` panic", tblInfo.Name, indexInfo.Name)
-				}, false)
-			return w.addTableIndex(tbl, indexInfo, reorgInfo)
-		})
-		if err != nil {
-			if errWaitReorgTimeout.Equal(err) {
-				// if timeout, we should return, check for the owner and re-wait job done.
-				return ver, nil
-			}
-			if kv.ErrKeyExists.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) {
-				logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
-				ver, err = convertAddIdxJob2RollbackJob(t, job, tblInfo, indexInfo, err)
-				if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
-					logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
-				}
-			}
-			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-			w.reorgCtx.cleanNotifyReorgCancel()
+		var done bool
+		done, ver, err = w.runAddIndexReorg(d, t, job, tbl, tblInfo, indexInfo)
+		if !done {
 			return ver, errors.Trace(err)
 		}
-		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-		w.reorgCtx.cleanNotifyReorgCancel()
-
-		indexInfo.State = model.StatePublic
-		// Set column index flag.
-		addIndexColumnFlag(tblInfo, indexInfo)
-		if isPK {
-			if err = updateColsNull2NotNull(tblInfo, indexInfo); err != nil {
-				return ver, errors.Trace(err)
-			}
-		}
-		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != indexInfo.State)
+		ver, err = publishAddingIndex(t, job, tblInfo, indexInfo, isPK)
 		if err != nil {
 			return ver, errors.Trace(err)
 		}
@@ -593,6 +567,60 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 	return ver, errors.Trace(err)
 }
 
+// runAddIndexReorg backfills the index in the write reorganization state.
+// It returns done when all the index records have been added, the job is converted to a
+// rolling back job if the backfill meets an unrecoverable error.
+func (w *worker) runAddIndexReorg(d *ddlCtx, t *meta.Meta, job *model.Job, tbl table.Table, tblInfo *model.TableInfo, indexInfo *model.IndexInfo) (done bool, ver int64, err error) {
+	elements := []*meta.Element{{ID: indexInfo.ID, TypeKey: meta.IndexElementKey}}
+	reorgInfo, err := getReorgInfo(d, t, job, tbl, elements)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, ver, errors.Trace(err)
+	}
+
+	err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (addIndexErr error) {
+		defer util.Recover(metrics.LabelDDL, "onCreateIndex",
+			func() {
+				addIndexErr = errCancelledDDLJob.GenWithStack("add table `%v` index `%v` panic", tblInfo.Name, indexInfo.Name)
+			}, false)
+		return w.addTableIndex(tbl, indexInfo, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// if timeout, we should return, check for the owner and re-wait job done.
+			return false, ver, nil
+		}
+		if kv.ErrKeyExists.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) {
+			logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+			ver, err = convertAddIdxJob2RollbackJob(t, job, tblInfo, indexInfo, err)
+			if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+				logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
+			}
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, ver, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, ver, nil
+}
+
+// publishAddingIndex changes the backfilled index to the public state.
+func publishAddingIndex(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, indexInfo *model.IndexInfo, isPK bool) (ver int64, err error) {
+	originalState := indexInfo.State
+	indexInfo.State = model.StatePublic
+	// Set column index flag.
+	addIndexColumnFlag(tblInfo, indexInfo)
+	if isPK {
+		if err = updateColsNull2NotNull(tblInfo, indexInfo); err != nil {
+			return ver, errors.Trace(err)
+		}
+	}
+	return updateVersionAndTableInfo(t, job, tblInfo, originalState != indexInfo.State)
+}
+
 func onDropIndex(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	tblInfo, indexInfo, err := checkDropIndex(t, job)
 	if err != nil {
@@ -1054,6 +1082,10 @@ func (w *addIndexWorker) batchCheckUniqueKey(txn kv.Transaction, idxRecords []*i
 		// because in most case, backfilling indices is not exists.
 		return nil
 	}
+	if idxInfo.MVIndex {
+		// A row has several keys in a multi-valued index, they are checked when the index is created.
+		return nil
+	}
 
 	w.initBatchCheckBufs(len(idxRecords))
 	stmtCtx := w.sessCtx.GetSessionVars().StmtCtx
@@ -1398,8 +1430,9 @@ func (w *worker) updateReorgInfoForPartitions(t table.PartitionedTable, reorg *r
 			if i == len(partitionIDs)-1 {
 				return true, nil
 			}
+			pid = partitionIDs[i+1]
+			break
 		}
-		pid = partitionIDs[i+1]
 	}
 
 	currentVer, err := getValidCurrentVersion(reorg.d.store)
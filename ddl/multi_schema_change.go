@@ -0,0 +1,504 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// subJob is a schema change of a multi-schema change job.
+type subJob struct {
+	Type        model.ActionType  `json:"type"`
+	RawArgs     json.RawMessage   `json:"raw_args"`
+	SchemaState model.SchemaState `json:"schema_state"`
+	SnapshotVer uint64            `json:"snapshot_ver"`
+	RowCount    int64             `json:"row_count"`
+	State       model.JobState    `json:"state"`
+	// ReorgDone indicates the backfill of the adding index has been done.
+	ReorgDone bool `json:"reorg_done"`
+}
+
+// multiSchemaInfo is the argument of a multi-schema change job.
+//
+// The sub-jobs run in the following order:
+//   1. The adding columns and indexes run in lockstep, one state per round, and the backfills run one by one.
+//      After all of them are ready, they are published in one round. Before that, all the sub-jobs can be rolled back.
+//   2. The dropping indexes and columns run in lockstep, they can't be rolled back.
+type multiSchemaInfo struct {
+	SubJobs []*subJob `json:"sub_jobs"`
+	// Revertible indicates the adding sub-jobs are not published yet, so the job can be rolled back.
+	Revertible bool `json:"revertible"`
+}
+
+func newSubJob(job *model.Job) (*subJob, error) {
+	rawArgs, err := json.Marshal(job.Args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &subJob{
+		Type:        job.Type,
+		RawArgs:     rawArgs,
+		SchemaState: model.StateNone,
+		State:       model.JobStateNone,
+	}, nil
+}
+
+// toProxyJob converts the sub-job to a job, so that it can be run by the handler of its type.
+func (sub *subJob) toProxyJob(parent *model.Job) *model.Job {
+	state := sub.State
+	if state == model.JobStateNone {
+		state = model.JobStateRunning
+	}
+	proxy := &model.Job{
+		ID:          parent.ID,
+		Type:        sub.Type,
+		SchemaID:    parent.SchemaID,
+		TableID:     parent.TableID,
+		SchemaName:  parent.SchemaName,
+		State:       state,
+		SchemaState: sub.SchemaState,
+		SnapshotVer: sub.SnapshotVer,
+		RawArgs:     sub.RawArgs,
+		StartTS:     parent.StartTS,
+		Query:       parent.Query,
+		BinlogInfo:  &model.HistoryInfo{},
+		Version:     parent.Version,
+		ReorgMeta:   parent.ReorgMeta,
+		Priority:    parent.Priority,
+	}
+	proxy.SetRowCount(sub.RowCount)
+	return proxy
+}
+
+// updateFromProxy saves the progress of the proxy job to the sub-job.
+func (sub *subJob) updateFromProxy(proxy *model.Job) error {
+	if proxy.Args != nil {
+		rawArgs, err := json.Marshal(proxy.Args)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		sub.RawArgs = rawArgs
+	}
+	sub.State = proxy.State
+	sub.SchemaState = proxy.SchemaState
+	sub.SnapshotVer = proxy.SnapshotVer
+	sub.RowCount = proxy.GetRowCount()
+	return nil
+}
+
+func (sub *subJob) isFinished() bool {
+	return sub.State == model.JobStateDone || sub.State == model.JobStateRollbackDone ||
+		sub.State == model.JobStateCancelled
+}
+
+func (sub *subJob) isStarted() bool {
+	return sub.SchemaState != model.StateNone || sub.State == model.JobStateRollingback
+}
+
+// isRevertible indicates the sub-job is an adding one, which is published before the dropping ones run.
+func (sub *subJob) isRevertible() bool {
+	return sub.Type == model.ActionAddColumns || sub.Type == model.ActionAddIndex
+}
+
+// readyToPublish indicates the adding sub-job only needs to change its state to public.
+func (sub *subJob) readyToPublish() bool {
+	switch sub.Type {
+	case model.ActionAddColumns:
+		return sub.SchemaState == model.StateWriteReorganization
+	case model.ActionAddIndex:
+		return sub.SchemaState == model.StateWriteReorganization && sub.ReorgDone
+	}
+	return false
+}
+
+// needBackfill indicates the next step of the sub-job is to backfill the index.
+func (sub *subJob) needBackfill() bool {
+	return sub.Type == model.ActionAddIndex && sub.SchemaState == model.StateWriteReorganization &&
+		sub.State != model.JobStateRollingback && !sub.ReorgDone
+}
+
+// needDeleteRange indicates the finished sub-job leaves some index data to be deleted.
+func (sub *subJob) needDeleteRange() bool {
+	switch sub.Type {
+	case model.ActionAddIndex:
+		return sub.State == model.JobStateRollbackDone
+	case model.ActionDropIndex, model.ActionDropColumns:
+		return sub.State == model.JobStateDone
+	}
+	return false
+}
+
+func (info *multiSchemaInfo) allFinished() bool {
+	for _, sub := range info.SubJobs {
+		if !sub.isFinished() {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *worker) onMultiSchemaChange(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	info := &multiSchemaInfo{}
+	if err = job.DecodeArgs(info); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	if job.IsRollingback() {
+		return w.rollbackMultiSchemaChange(d, t, job, info)
+	}
+
+	if info.Revertible {
+		var stepped bool
+		ver, stepped, err = w.runRevertibleSubJobs(d, t, job, info)
+		if err != nil {
+			if !job.IsRollingback() {
+				// Discard the progress of the sub-jobs in this round, since the kv modification is discarded.
+				job.Args = nil
+			}
+			return ver, errors.Trace(err)
+		}
+		if stepped {
+			return ver, nil
+		}
+
+		// All the adding sub-jobs are ready, publish them together.
+		info.Revertible = false
+		var published bool
+		ver, published, err = publishSubJobs(d, t, job, info)
+		if err != nil {
+			job.Args = nil
+			return ver, errors.Trace(err)
+		}
+		if published {
+			if info.allFinished() {
+				return finishMultiSchemaChange(t, job, ver)
+			}
+			job.SchemaState = model.StatePublic
+			return ver, nil
+		}
+	}
+
+	// Run the dropping sub-jobs in lockstep.
+	for _, sub := range info.SubJobs {
+		if sub.isFinished() {
+			continue
+		}
+		proxy := sub.toProxyJob(job)
+		subVer, err := w.runSubJob(d, t, proxy)
+		if proxy.IsCancelled() {
+			// The job can't be rolled back any more, and skipping the sub-job leaves the ALTER half-applied.
+			// Keep the job running, so the sub-job is retried.
+			logutil.BgLogger().Warn("[ddl] run multi-schema change sub-job cancelled, retry it", zap.String("job", job.String()),
+				zap.String("subJobType", sub.Type.String()), zap.Error(err))
+			if err == nil {
+				err = errCancelledDDLJob
+			}
+		}
+		if err != nil {
+			job.Args = nil
+			return ver, errors.Trace(err)
+		}
+		if err = sub.updateFromProxy(proxy); err != nil {
+			job.Args = nil
+			return ver, errors.Trace(err)
+		}
+		if subVer > ver {
+			ver = subVer
+		}
+		job.SchemaState = proxy.SchemaState
+	}
+	if info.allFinished() {
+		return finishMultiSchemaChange(t, job, ver)
+	}
+	return ver, nil
+}
+
+// runRevertibleSubJobs runs one step of every adding sub-job which isn't ready to publish.
+// Since a worker can only run one reorganization at the same time, the backfill step runs alone.
+func (w *worker) runRevertibleSubJobs(d *ddlCtx, t *meta.Meta, job *model.Job, info *multiSchemaInfo) (ver int64, stepped bool, err error) {
+	var rowCount int64
+	defer func() {
+		job.SetRowCount(rowCount)
+	}()
+	for _, sub := range info.SubJobs {
+		if !sub.isRevertible() || sub.readyToPublish() {
+			rowCount += sub.RowCount
+			continue
+		}
+		backfill := sub.needBackfill()
+		if backfill && stepped {
+			break
+		}
+		var subVer int64
+		proxy := sub.toProxyJob(job)
+		if backfill {
+			subVer, err = w.runSubJobAddIndexReorg(d, t, proxy, sub)
+		} else {
+			subVer, err = w.runSubJob(d, t, proxy)
+		}
+		if err1 := sub.updateFromProxy(proxy); err1 != nil && err == nil {
+			err = err1
+		}
+		rowCount += sub.RowCount
+		stepped = true
+		if subVer > ver {
+			ver = subVer
+		}
+		job.SchemaState = proxy.SchemaState
+		if proxy.IsCancelled() || proxy.IsRollingback() {
+			// Roll back the whole job.
+			logutil.BgLogger().Info("[ddl] run multi-schema change sub-job failed, convert job to rollback",
+				zap.String("job", job.String()), zap.String("subJobType", sub.Type.String()), zap.Error(err))
+			job.State = model.JobStateRollingback
+			if err == nil {
+				err = errCancelledDDLJob
+			}
+			return ver, stepped, errors.Trace(err)
+		}
+		if err != nil {
+			return ver, stepped, errors.Trace(err)
+		}
+		if backfill {
+			break
+		}
+	}
+	return ver, stepped, nil
+}
+
+// publishSubJobs changes the states of all the adding sub-jobs to public.
+func publishSubJobs(d *ddlCtx, t *meta.Meta, job *model.Job, info *multiSchemaInfo) (ver int64, published bool, err error) {
+	for _, sub := range info.SubJobs {
+		if !sub.isRevertible() || sub.isFinished() {
+			continue
+		}
+		var subVer int64
+		proxy := sub.toProxyJob(job)
+		switch sub.Type {
+		case model.ActionAddColumns:
+			subVer, err = onAddColumns(d, t, proxy)
+		case model.ActionAddIndex:
+			subVer, err = publishSubJobAddIndex(t, proxy)
+		}
+		if err != nil {
+			return ver, false, errors.Trace(err)
+		}
+		if err = sub.updateFromProxy(proxy); err != nil {
+			return ver, false, errors.Trace(err)
+		}
+		if subVer > ver {
+			ver = subVer
+		}
+		published = true
+	}
+	return ver, published, nil
+}
+
+func publishSubJobAddIndex(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	tblInfo, indexInfo, err := getAddingIndexInfo(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	updateHiddenColumns(tblInfo, indexInfo, model.StatePublic)
+	ver, err = publishAddingIndex(t, job, tblInfo, indexInfo, false)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+func finishMultiSchemaChange(t *meta.Meta, job *model.Job, ver int64) (int64, error) {
+	tblInfo, err := getTableInfo(t, job.TableID, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+// runSubJob runs one step of the sub-job by the handler of its type.
+func (w *worker) runSubJob(d *ddlCtx, t *meta.Meta, proxy *model.Job) (ver int64, err error) {
+	switch proxy.Type {
+	case model.ActionAddColumns:
+		ver, err = onAddColumns(d, t, proxy)
+	case model.ActionAddIndex:
+		ver, err = w.onCreateIndex(d, t, proxy, false)
+	case model.ActionDropColumns:
+		ver, err = onDropColumns(t, proxy)
+	case model.ActionDropIndex:
+		ver, err = onDropIndex(t, proxy)
+	default:
+		proxy.State = model.JobStateCancelled
+		err = errInvalidDDLJob.GenWithStack("invalid multi-schema change sub-job type: %v", proxy.Type)
+	}
+	return ver, errors.Trace(err)
+}
+
+// runSubJobAddIndexReorg backfills the adding index of the sub-job.
+func (w *worker) runSubJobAddIndexReorg(d *ddlCtx, t *meta.Meta, proxy *model.Job, sub *subJob) (ver int64, err error) {
+	tblInfo, indexInfo, err := getAddingIndexInfo(t, proxy)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// The adding columns of the same job are not public yet, the backfill should see their original default values.
+	backfillTblInfo := tblInfo.Clone()
+	for _, col := range backfillTblInfo.Columns {
+		if col.State == model.StateWriteReorganization {
+			col.State = model.StatePublic
+		}
+	}
+	tbl, err := getTable(d.store, proxy.SchemaID, backfillTblInfo)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	var done bool
+	done, ver, err = w.runAddIndexReorg(d, t, proxy, tbl, tblInfo, indexInfo)
+	if done {
+		sub.ReorgDone = true
+	}
+	return ver, errors.Trace(err)
+}
+
+// getAddingIndexInfo gets the table info and the adding index info of the add index job.
+func getAddingIndexInfo(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.IndexInfo, error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	var (
+		unique                  bool
+		global                  bool
+		indexName               model.CIStr
+		indexPartSpecifications []*ast.IndexPartSpecification
+		indexOption             *ast.IndexOption
+		hiddenCols              []*model.ColumnInfo
+	)
+	err = job.DecodeArgs(&unique, &indexName, &indexPartSpecifications, &indexOption, &hiddenCols, &global)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return nil, nil, errors.Trace(err)
+	}
+	indexInfo := tblInfo.FindIndexByName(indexName.L)
+	if indexInfo == nil {
+		job.State = model.JobStateCancelled
+		return nil, nil, errors.Trace(errCancelledDDLJob)
+	}
+	return tblInfo, indexInfo, nil
+}
+
+// rollbackMultiSchemaChange rolls back the started sub-jobs one by one in the reverse order.
+// Rolling back a dropping column drops the single-column indexes on it together, so the sub-jobs can't be
+// rolled back in lockstep.
+func (w *worker) rollbackMultiSchemaChange(d *ddlCtx, t *meta.Meta, job *model.Job, info *multiSchemaInfo) (ver int64, err error) {
+	for _, sub := range info.SubJobs {
+		if !sub.isFinished() && !sub.isStarted() {
+			sub.State = model.JobStateCancelled
+		}
+	}
+
+	// The backfilling index must be rolled back first, since the worker can't run another reorganization.
+	var target *subJob
+	for i := len(info.SubJobs) - 1; i >= 0; i-- {
+		sub := info.SubJobs[i]
+		if sub.isFinished() {
+			continue
+		}
+		if target == nil || (sub.needBackfill() && sub.SnapshotVer != 0) {
+			target = sub
+		}
+	}
+
+	if target != nil {
+		proxy := target.toProxyJob(job)
+		if proxy.IsRollingback() {
+			ver, err = w.runSubJob(d, t, proxy)
+		} else {
+			ver, err = w.convertSubJob2RollbackJob(d, t, proxy, target)
+		}
+		if err1 := target.updateFromProxy(proxy); err1 != nil && err == nil {
+			err = err1
+		}
+		if err != nil && !errCancelledDDLJob.Equal(err) {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = proxy.SchemaState
+		if !info.allFinished() {
+			return ver, nil
+		}
+	}
+
+	allCancelled := true
+	for _, sub := range info.SubJobs {
+		if sub.State != model.JobStateCancelled {
+			allCancelled = false
+			break
+		}
+	}
+	if allCancelled {
+		job.State = model.JobStateCancelled
+		if job.Error != nil {
+			return ver, nil
+		}
+		return ver, errCancelledDDLJob
+	}
+	tblInfo, err := getTableInfo(t, job.TableID, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	return ver, nil
+}
+
+// convertSubJob2RollbackJob converts the started adding sub-job to a rolling back one.
+func (w *worker) convertSubJob2RollbackJob(d *ddlCtx, t *meta.Meta, proxy *model.Job, sub *subJob) (ver int64, err error) {
+	switch sub.Type {
+	case model.ActionAddColumns:
+		return rollingbackAddColumns(t, proxy)
+	case model.ActionAddIndex:
+		if sub.needBackfill() && sub.SnapshotVer != 0 {
+			// The backfill workers may be running, ask them to exit.
+			w.reorgCtx.notifyReorgCancel()
+			ver, err = w.runSubJobAddIndexReorg(d, t, proxy, sub)
+			if !sub.ReorgDone || proxy.IsRollingback() || proxy.IsCancelled() {
+				return ver, errors.Trace(err)
+			}
+		}
+		return convertNotStartAddIdxJob2RollbackJob(t, proxy, errCancelledDDLJob)
+	}
+	proxy.State = model.JobStateCancelled
+	return ver, errCancelledDDLJob
+}
+
+// rollingbackMultiSchemaChange changes the multi-schema change job into rolling back state.
+func rollingbackMultiSchemaChange(job *model.Job) (ver int64, err error) {
+	info := &multiSchemaInfo{}
+	if err = job.DecodeArgs(info); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	if !info.Revertible {
+		// The adding sub-jobs are published, the job can't be rolled back any more.
+		job.State = model.JobStateRunning
+		return ver, nil
+	}
+	job.State = model.JobStateRollingback
+	return ver, errCancelledDDLJob
+}
@@ -0,0 +1,155 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"context"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/util/admin"
+	"github.com/pingcap/tidb/util/mock"
+	"github.com/pingcap/tidb/util/testkit"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testIntegrationSuite7) TestMultiSchemaChange(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t_msc")
+	tk.MustExec("create table t_msc (a int, b int, c int, index ib(b))")
+	tk.MustExec("insert into t_msc values (1, 2, 3), (4, 5, 6)")
+
+	tk.MustExec("alter table t_msc add column d int default 7 after a, add index idx_d(d), add unique index (a), drop column c, drop index ib")
+	tk.MustQuery("show create table t_msc").Check(testkit.Rows("t_msc CREATE TABLE `t_msc` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `d` int(11) DEFAULT '7',\n" +
+		"  `b` int(11) DEFAULT NULL,\n" +
+		"  KEY `idx_d` (`d`),\n" +
+		"  UNIQUE KEY `a` (`a`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustQuery("select * from t_msc use index(idx_d) where d = 7 order by a").Check(testkit.Rows("1 7 2", "4 7 5"))
+	tk.MustExec("admin check table t_msc")
+	tk.MustQuery("admin show ddl jobs 1").CheckAt([]int{3, 4}, testutil.RowsWithSep("|", "alter table multi-schema change|public"))
+
+	// Index on the column which is added in the same statement.
+	tk.MustExec("alter table t_msc add column e int, add column f int first, add index idx_ef(e, f), drop column b")
+	tk.MustExec("insert into t_msc values (7, 8, 9, 10)")
+	tk.MustQuery("select f, a, d, e from t_msc use index(idx_ef) where e = 10").Check(testkit.Rows("7 8 9 10"))
+	tk.MustExec("admin check table t_msc")
+
+	// The conflicting specs.
+	tk.MustGetErrCode("alter table t_msc add column g int, drop column g", errno.ErrOperateSameColumn)
+	tk.MustGetErrCode("alter table t_msc add index idx_a(a), drop index idx_a", errno.ErrOperateSameIndex)
+	tk.MustGetErrCode("alter table t_msc drop index idx_d, drop index idx_d", errno.ErrOperateSameIndex)
+	tk.MustGetErrCode("alter table t_msc add index idx_a(a), drop column a", errno.ErrKeyColumnDoesNotExits)
+	tk.MustGetErrCode("alter table t_msc add column g int after d, drop column d", errno.ErrBadField)
+	tk.MustGetErrCode("alter table t_msc add column g int as (d + 1), drop column d", errno.ErrDependentByGeneratedColumn)
+	tk.MustGetErrCode("alter table t_msc drop index idx_d, drop column d", errno.ErrOperateSameIndex)
+	tk.MustGetErrCode("alter table t_msc add index idx_a(a), add index idx_a(d)", errno.ErrDupKeyName)
+	// The unsupported specs.
+	tk.MustGetErrCode("alter table t_msc add column g int, add index idx_expr((a + 1))", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t_msc add column g int, modify column a bigint", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t_msc add column g int, add primary key (a)", errno.ErrUnsupportedDDLOperation)
+	tk.MustQuery("select count(*) from information_schema.columns where table_name = 't_msc' and column_name = 'g'").Check(testkit.Rows("0"))
+
+	// The duplicate entry rolls back all the sub-jobs.
+	tk.MustExec("insert into t_msc values (10, 11, 7, 12)")
+	tk.MustGetErrCode("alter table t_msc add column g int, add index idx_g(g), add unique index idx_ud(d), drop index idx_d", errno.ErrDupEntry)
+	tk.MustQuery("show create table t_msc").Check(testkit.Rows("t_msc CREATE TABLE `t_msc` (\n" +
+		"  `f` int(11) DEFAULT NULL,\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `d` int(11) DEFAULT '7',\n" +
+		"  `e` int(11) DEFAULT NULL,\n" +
+		"  KEY `idx_d` (`d`),\n" +
+		"  UNIQUE KEY `a` (`a`),\n" +
+		"  KEY `idx_ef` (`e`,`f`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("admin check table t_msc")
+	tk.MustExec("drop table t_msc")
+}
+
+func (s *testIntegrationSuite7) TestCancelMultiSchemaChange(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t_msc")
+	tk.MustExec("create table t_msc (a int, b int, c int)")
+	tk.MustExec("insert into t_msc values (1, 2, 3), (4, 5, 6)")
+
+	var (
+		cancelState model.SchemaState
+		cancelled   bool
+		checkErr    error
+	)
+	hook := &ddl.TestDDLCallback{Do: s.dom}
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type != model.ActionMultiSchemaChange || job.SchemaState != cancelState || cancelled {
+			return
+		}
+		cancelled = true
+		hookCtx := mock.NewContext()
+		hookCtx.Store = s.store
+		if err := hookCtx.NewTxn(context.Background()); err != nil {
+			checkErr = errors.Trace(err)
+			return
+		}
+		txn, err := hookCtx.Txn(true)
+		if err != nil {
+			checkErr = errors.Trace(err)
+			return
+		}
+		errs, err := admin.CancelJobs(txn, []int64{job.ID})
+		if err != nil {
+			checkErr = errors.Trace(err)
+			return
+		}
+		if errs[0] != nil {
+			checkErr = errors.Trace(errs[0])
+			return
+		}
+		checkErr = txn.Commit(context.Background())
+	}
+	originalHook := s.dom.DDL().GetHook()
+	s.dom.DDL().(ddl.DDLForTest).SetHook(hook)
+	defer s.dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+
+	// The job is rolled back before the adding sub-jobs are published.
+	cancelState = model.StateWriteOnly
+	_, err := tk.Exec("alter table t_msc add column d int, add index idx_d(d), add index idx_b(b), drop column c")
+	c.Assert(checkErr, IsNil)
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[ddl:8214]Cancelled DDL job")
+	tk.MustQuery("show create table t_msc").Check(testkit.Rows("t_msc CREATE TABLE `t_msc` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` int(11) DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("admin check table t_msc")
+
+	// The job can't be cancelled after the adding sub-jobs are published.
+	cancelState, cancelled = model.StateDeleteReorganization, false
+	tk.MustExec("alter table t_msc add column d int, add index idx_d(d), drop column c")
+	c.Assert(admin.ErrCannotCancelDDLJob.Equal(checkErr), IsTrue, Commentf("err: %v", checkErr))
+	tk.MustQuery("show create table t_msc").Check(testkit.Rows("t_msc CREATE TABLE `t_msc` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` int(11) DEFAULT NULL,\n" +
+		"  `d` int(11) DEFAULT NULL,\n" +
+		"  KEY `idx_d` (`d`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("admin check table t_msc")
+	tk.MustExec("drop table t_msc")
+}
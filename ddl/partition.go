@@ -554,8 +554,8 @@ func checkPartitionNameUnique(pi *model.PartitionInfo) error {
 
 func checkAddPartitionNameUnique(tbInfo *model.TableInfo, pi *model.PartitionInfo) error {
 	partNames := make(map[string]struct{})
-	if tbInfo.Partition != nil {
-		oldPars := tbInfo.Partition.Definitions
+	if oldPi := tbInfo.GetPartitionInfo(); oldPi != nil {
+		oldPars := oldPi.Definitions
 		for _, oldPar := range oldPars {
 			partNames[oldPar.Name.L] = struct{}{}
 		}
@@ -1470,7 +1470,7 @@ func checkAddPartitionTooManyPartitions(piDefs uint64) error {
 }
 
 func checkAddPartitionOnTemporaryMode(tbInfo *model.TableInfo) error {
-	if tbInfo.Partition != nil && tbInfo.TempTableType != model.TempTableNone {
+	if tbInfo.GetPartitionInfo() != nil && tbInfo.TempTableType != model.TempTableNone {
 		return ErrPartitionNoTemporary
 	}
 	return nil
@@ -1495,23 +1495,23 @@ func getPartitionIDs(table *model.TableInfo) []int64 {
 }
 
 // checkPartitioningKeysConstraints checks that the range partitioning key is included in the table constraint.
-func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.CreateTableStmt, tblInfo *model.TableInfo) error {
+func checkPartitioningKeysConstraints(sctx sessionctx.Context, partOptions *ast.PartitionOptions, tblInfo *model.TableInfo) error {
 	// Returns directly if there are no unique keys in the table.
 	if len(tblInfo.Indices) == 0 && !tblInfo.PKIsHandle {
 		return nil
 	}
 
 	var partCols stringSlice
-	if s.Partition.Expr != nil {
+	if partOptions.Expr != nil {
 		extractCols := newPartitionExprChecker(sctx, tblInfo)
-		s.Partition.Expr.Accept(extractCols)
+		partOptions.Expr.Accept(extractCols)
 		partColumns, err := extractCols.columns, extractCols.err
 		if err != nil {
 			return err
 		}
 		partCols = columnInfoSlice(partColumns)
-	} else if len(s.Partition.ColumnNames) > 0 {
-		partCols = columnNameSlice(s.Partition.ColumnNames)
+	} else if len(partOptions.ColumnNames) > 0 {
+		partCols = columnNameSlice(partOptions.ColumnNames)
 	} else {
 		// TODO: Check keys constraints for list, key partition type and so on.
 		return nil
@@ -18,6 +18,7 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/kv"
@@ -265,3 +266,32 @@ func buildTableInfoWithReplicaInfo(c *C, d *ddl) *model.TableInfo {
 	tbl.Partition = partInfo
 	return tbl
 }
+
+func (s *testPartitionSuite) TestBuildTableInfoWithLikeReorganizingPartitions(c *C) {
+	tblDef := model.PartitionDefinition{ID: 1, Name: model.NewCIStr("t")}
+	defs := []model.PartitionDefinition{{ID: 2, Name: model.NewCIStr("p0")}, {ID: 3, Name: model.NewCIStr("p1")}}
+	tbl := &model.TableInfo{ID: 1, Name: model.NewCIStr("t")}
+	ident := ast.Ident{Schema: model.NewCIStr("test"), Name: model.NewCIStr("t_like")}
+
+	// The table is being partitioned, it's copied as a non-partitioned table.
+	tbl.Partition = &model.PartitionInfo{
+		Type:                model.PartitionTypeHash,
+		AddingDefinitions:   defs,
+		DroppingDefinitions: []model.PartitionDefinition{tblDef},
+		States:              []model.PartitionState{{ID: 2, State: model.StateWriteOnly}, {ID: 3, State: model.StateWriteOnly}},
+	}
+	newTbl, err := buildTableInfoWithLike(ident, tbl)
+	c.Assert(err, IsNil)
+	c.Assert(newTbl.Partition, IsNil)
+
+	// The new partitions are public, only they are copied.
+	tbl.Partition.Enable = true
+	tbl.Partition.Definitions, tbl.Partition.AddingDefinitions = defs, nil
+	tbl.Partition.States = []model.PartitionState{{ID: 2, State: model.StatePublic}, {ID: 3, State: model.StatePublic}, {ID: 1, State: model.StateDeleteReorganization}}
+	newTbl, err = buildTableInfoWithLike(ident, tbl)
+	c.Assert(err, IsNil)
+	c.Assert(newTbl.Partition, NotNil)
+	c.Assert(newTbl.Partition.Definitions, DeepEquals, defs)
+	c.Assert(newTbl.Partition.DroppingDefinitions, IsNil)
+	c.Assert(newTbl.Partition.States, IsNil)
+}
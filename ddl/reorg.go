@@ -635,6 +635,15 @@ func getReorgInfo(d *ddlCtx, t *meta.Meta, job *model.Job, tbl table.Table, elem
 	return &info, nil
 }
 
+// getReorgPhysicalTable returns the physical table of tbl with the physical table ID,
+// tbl is the physical table itself if it isn't partitioned.
+func getReorgPhysicalTable(tbl table.Table, physicalTableID int64) table.PhysicalTable {
+	if pt, ok := tbl.(table.PartitionedTable); ok {
+		return pt.GetPartition(physicalTableID)
+	}
+	return tbl.(table.PhysicalTable)
+}
+
 func getReorgInfoFromPartitions(d *ddlCtx, t *meta.Meta, job *model.Job, tbl table.Table, partitionIDs []int64, elements []*meta.Element) (*reorgInfo, error) {
 	var (
 		element *meta.Element
@@ -651,7 +660,7 @@ func getReorgInfoFromPartitions(d *ddlCtx, t *meta.Meta, job *model.Job, tbl tab
 			return nil, errors.Trace(err)
 		}
 		pid = partitionIDs[0]
-		tb := tbl.(table.PartitionedTable).GetPartition(pid)
+		tb := getReorgPhysicalTable(tbl, pid)
 		start, end, err = getTableRange(d, tb, ver.Ver, job.Priority)
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -0,0 +1,654 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/ddl/util"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	tidbutil "github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	decoder "github.com/pingcap/tidb/util/rowDecoder"
+	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// checkReorganizePartition checks whether the partitions can be reorganized into the new partitions,
+// and returns the definitions of the reorganized partitions.
+func checkReorganizePartition(ctx sessionctx.Context, tblInfo *model.TableInfo, partNames []string, partInfo *model.PartitionInfo) ([]model.PartitionDefinition, error) {
+	pi := tblInfo.Partition
+	switch pi.Type {
+	case model.PartitionTypeRange, model.PartitionTypeList:
+	case model.PartitionTypeHash:
+		// The rows of a hash partitioned table are redistributed by the new modulus,
+		// so all the partitions have to be reorganized.
+		if len(partNames) != len(pi.Definitions) {
+			return nil, errors.Trace(errUnsupportedReorganizePartition)
+		}
+	default:
+		return nil, errors.Trace(errUnsupportedReorganizePartition)
+	}
+	if len(pi.AddingDefinitions) > 0 || len(pi.DroppingDefinitions) > 0 {
+		return nil, errors.Trace(errUnsupportedReorganizePartition)
+	}
+	if len(partNames) == 0 || len(partInfo.Definitions) == 0 {
+		return nil, errors.Trace(ErrReorgNoParam)
+	}
+
+	positions := make([]int, 0, len(partNames))
+	for _, name := range partNames {
+		pos := -1
+		for i, def := range pi.Definitions {
+			if def.Name.L == name {
+				pos = i
+				break
+			}
+		}
+		if pos < 0 {
+			return nil, errors.Trace(ErrDropPartitionNonExistent.GenWithStackByArgs("REORGANIZE"))
+		}
+		for _, p := range positions {
+			if p == pos {
+				return nil, errors.Trace(ErrDropPartitionNonExistent.GenWithStackByArgs("REORGANIZE"))
+			}
+		}
+		positions = append(positions, pos)
+	}
+	defs := make([]model.PartitionDefinition, 0, len(positions))
+	for i, def := range pi.Definitions {
+		for _, pos := range positions {
+			if pos == i {
+				defs = append(defs, def)
+				break
+			}
+		}
+	}
+
+	if pi.Type == model.PartitionTypeRange {
+		first, last := len(pi.Definitions), -1
+		for _, pos := range positions {
+			if pos < first {
+				first = pos
+			}
+			if pos > last {
+				last = pos
+			}
+		}
+		if last-first+1 != len(positions) {
+			return nil, errors.Trace(ErrConsecutiveReorgPartitions)
+		}
+		// The range covered by the reorganized partitions can't shrink, otherwise some rows may belong to
+		// no partition. It can only be extended if the last partition is reorganized, otherwise the rows
+		// may be moved into the partitions which are not reorganized.
+		oldHigh, newHigh := defs[len(defs)-1].LessThan, partInfo.Definitions[len(partInfo.Definitions)-1].LessThan
+		cmp, err := compareRangeValue(ctx, tblInfo, newHigh, oldHigh)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if cmp < 0 || (cmp > 0 && last != len(pi.Definitions)-1) {
+			return nil, errors.Trace(ErrReorgOutsideRange)
+		}
+	}
+	return defs, nil
+}
+
+// compareRangeValue compares the `VALUES LESS THAN` values of two range partitions. The values are
+// evaluated rather than compared as text, e.g. `10` equals to `5 + 5`.
+func compareRangeValue(ctx sessionctx.Context, tblInfo *model.TableInfo, a, b []string) (int, error) {
+	pi := tblInfo.Partition
+	if len(a) != len(b) {
+		return 0, errors.Trace(ast.ErrPartitionColumnList)
+	}
+	for i := range a {
+		aMax, bMax := strings.EqualFold(a[i], partitionMaxValue), strings.EqualFold(b[i], partitionMaxValue)
+		switch {
+		case aMax && bMax:
+			continue
+		case aMax:
+			return 1, nil
+		case bMax:
+			return -1, nil
+		}
+		if len(pi.Columns) == 0 {
+			unsigned := isColUnsigned(tblInfo.Columns, pi)
+			aValue, _, err := getRangeValue(ctx, a[i], unsigned)
+			if err != nil {
+				return 0, errors.Trace(err)
+			}
+			bValue, _, err := getRangeValue(ctx, b[i], unsigned)
+			if err != nil {
+				return 0, errors.Trace(err)
+			}
+			if unsigned {
+				return types.CompareUint64(aValue.(uint64), bValue.(uint64)), nil
+			}
+			return types.CompareInt64(aValue.(int64), bValue.(int64)), nil
+		}
+		colInfo := findColumnByName(pi.Columns[i].L, tblInfo)
+		greater, err := parseAndEvalBoolExpr(ctx, a[i], b[i], colInfo, tblInfo)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if greater {
+			return 1, nil
+		}
+		less, err := parseAndEvalBoolExpr(ctx, b[i], a[i], colInfo, tblInfo)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if less {
+			return -1, nil
+		}
+	}
+	return 0, nil
+}
+
+// getReorganizedPartitionTable returns the table whose reorganized partitions are replaced by the new ones.
+// It isn't a partitioned table if the partitioning is being removed.
+func getReorganizedPartitionTable(tblInfo *model.TableInfo) (table.Table, error) {
+	tbl, err := tables.TableFromMeta(nil, tables.ReorganizedTableInfo(tblInfo))
+	return tbl, errors.Trace(err)
+}
+
+// prepareReorganizePartition sets the partitions which are reorganized and the new partitions.
+// When a table is partitioned or its partitioning is removed, the table itself is the only physical
+// table on the non-partitioned side.
+func prepareReorganizePartition(ctx sessionctx.Context, tblInfo *model.TableInfo, job *model.Job, partNames []string, partInfo *model.PartitionInfo) error {
+	tblDef := model.PartitionDefinition{ID: tblInfo.ID, Name: tblInfo.Name}
+	switch job.Type {
+	case model.ActionAlterTablePartitioning:
+		if tblInfo.Partition != nil {
+			return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+		}
+		// The table isn't partitioned until the new partitions are public.
+		partInfo.Enable = false
+		partInfo.AddingDefinitions = partInfo.Definitions
+		partInfo.Definitions = nil
+		partInfo.DroppingDefinitions = []model.PartitionDefinition{tblDef}
+		tblInfo.Partition = partInfo
+	case model.ActionRemovePartitioning:
+		pi := tblInfo.GetPartitionInfo()
+		if pi == nil {
+			return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+		}
+		pi.AddingDefinitions = []model.PartitionDefinition{tblDef}
+		pi.DroppingDefinitions = pi.Definitions
+	default:
+		pi := tblInfo.GetPartitionInfo()
+		if pi == nil {
+			return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+		}
+		droppingDefs, err := checkReorganizePartition(ctx, tblInfo, partNames, partInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		pi.AddingDefinitions = partInfo.Definitions
+		pi.DroppingDefinitions = droppingDefs
+	}
+	return nil
+}
+
+// setPartitionStates records the states of the partitions, which are used to decide the writes to the partitions
+// on the other side of the reorganization.
+func setPartitionStates(pi *model.PartitionInfo, defs []model.PartitionDefinition, state model.SchemaState) {
+	for _, def := range defs {
+		found := false
+		for i := range pi.States {
+			if pi.States[i].ID == def.ID {
+				pi.States[i].State = state
+				found = true
+				break
+			}
+		}
+		if !found {
+			pi.States = append(pi.States, model.PartitionState{ID: def.ID, State: state})
+		}
+	}
+}
+
+// onReorganizePartition reorganizes the partitions into the new partitions. The job runs in the following states:
+//   none -> delete only -> write only -> write reorganization -> delete reorganization -> none
+// Like adding an index, the new partitions go through the delete-only and write-only states, so all the writes
+// are applied to them too, and then the rows of the reorganized partitions are backfilled into them.
+// After that, the new partitions replace the reorganized partitions, which still get the writes in the
+// delete-reorganization state, since some servers may read them until the new partitions are seen.
+// Partitioning a table and removing the partitioning of a table run in the same way, the table itself is the
+// only physical table on the non-partitioned side, and PartitionInfo.Enable is false when that side is public.
+func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var partNames []string
+	partInfo := &model.PartitionInfo{}
+	var err error
+	switch job.Type {
+	case model.ActionReorganizePartition:
+		err = job.DecodeArgs(&partNames, &partInfo)
+	case model.ActionAlterTablePartitioning:
+		err = job.DecodeArgs(&partInfo)
+	}
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if job.IsRollingback() {
+		return rollbackReorganizePartition(t, job, tblInfo)
+	}
+
+	switch job.SchemaState {
+	case model.StateNone:
+		err = prepareReorganizePartition(newContext(d.store), tblInfo, job, partNames, partInfo)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		pi := tblInfo.Partition
+		setPartitionStates(pi, pi.AddingDefinitions, model.StateDeleteOnly)
+		// none -> delete only
+		job.SchemaState = model.StateDeleteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
+	case model.StateDeleteOnly:
+		// delete only -> write only
+		pi := tblInfo.Partition
+		setPartitionStates(pi, pi.AddingDefinitions, model.StateWriteOnly)
+		job.SchemaState = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	case model.StateWriteOnly:
+		// write only -> reorganization
+		pi := tblInfo.Partition
+		setPartitionStates(pi, pi.AddingDefinitions, model.StateWriteReorganization)
+		job.SchemaState = model.StateWriteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	case model.StateWriteReorganization:
+		var tbl table.Table
+		tbl, err = getTable(d.store, job.SchemaID, tblInfo)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		var done bool
+		done, ver, err = w.runReorgPartition(d, t, job, tbl, tblInfo)
+		if !done {
+			return ver, err
+		}
+		// reorganization -> delete reorganization, the new partitions replace the reorganized ones.
+		pi := tblInfo.Partition
+		addingDefs := pi.AddingDefinitions
+		switch job.Type {
+		case model.ActionAlterTablePartitioning:
+			pi.Definitions = addingDefs
+			pi.Enable = true
+		case model.ActionRemovePartitioning:
+			pi.Definitions = nil
+			pi.Enable = false
+		default:
+			pi.Definitions = tables.ReplacePartitionDefinitions(pi.Definitions, pi.DroppingDefinitions, addingDefs)
+		}
+		if pi.Type == model.PartitionTypeHash && pi.Enable {
+			// The modulus changes with the partitions in the same schema version, so the rows are
+			// always located and pruned with the partitions they are stored in.
+			pi.Num = uint64(len(pi.Definitions))
+		}
+		pi.AddingDefinitions = nil
+		pi.States = nil
+		setPartitionStates(pi, addingDefs, model.StatePublic)
+		setPartitionStates(pi, pi.DroppingDefinitions, model.StateDeleteReorganization)
+		job.SchemaState = model.StateDeleteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	case model.StateDeleteReorganization:
+		pi := tblInfo.Partition
+		physicalTableIDs := getPartitionIDsFromDefinitions(pi.DroppingDefinitions)
+		newDefs := make([]model.PartitionDefinition, 0, len(pi.States))
+		for _, def := range pi.Definitions {
+			for _, st := range pi.States {
+				if st.ID == def.ID && st.State == model.StatePublic {
+					newDefs = append(newDefs, def)
+					break
+				}
+			}
+		}
+		pi.DroppingDefinitions = nil
+		pi.States = nil
+		if !pi.Enable {
+			tblInfo.Partition = nil
+		}
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Finish this job.
+		job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+		if tblInfo.Partition == nil {
+			// The table is the new physical table, like a truncated table.
+			asyncNotifyEvent(d, &util.Event{Tp: model.ActionTruncateTable, TableInfo: tblInfo})
+		} else {
+			asyncNotifyEvent(d, &util.Event{Tp: model.ActionAddTablePartition, TableInfo: tblInfo, PartInfo: &model.PartitionInfo{Definitions: newDefs}})
+		}
+		// A background job will be created to delete the data of the reorganized partitions.
+		job.Args = []interface{}{physicalTableIDs}
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("partition", job.SchemaState)
+	}
+	return ver, errors.Trace(err)
+}
+
+func (w *worker) runReorgPartition(d *ddlCtx, t *meta.Meta, job *model.Job, tbl table.Table, tblInfo *model.TableInfo) (done bool, ver int64, err error) {
+	physicalTableIDs := getPartitionIDsFromDefinitions(tblInfo.Partition.DroppingDefinitions)
+	// Build elements for compatible with the reorg handle. elements will not be used when reorganizing.
+	elements := []*meta.Element{{ID: tblInfo.Partition.AddingDefinitions[0].ID, TypeKey: meta.ColumnElementKey}}
+	reorgInfo, err := getReorgInfoFromPartitions(d, t, job, tbl, physicalTableIDs, elements)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, ver, errors.Trace(err)
+	}
+
+	err = w.runReorgJob(t, reorgInfo, tblInfo, d.lease, func() (reorgErr error) {
+		defer tidbutil.Recover(metrics.LabelDDL, "onReorganizePartition",
+			func() {
+				reorgErr = errCancelledDDLJob.GenWithStack("reorganize table `%v` partitions panic", tblInfo.Name)
+			}, false)
+		return w.reorgPartitionData(tbl, physicalTableIDs, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// if timeout, we should return, check for the owner and re-wait job done.
+			return false, ver, nil
+		}
+		if kv.ErrKeyExists.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) ||
+			table.ErrNoPartitionForGivenValue.Equal(err) || ErrReorgOutsideRange.Equal(err) {
+			logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+			job.State = model.JobStateRollingback
+			if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+				logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
+			}
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, ver, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, ver, nil
+}
+
+// reorgPartitionData copies the rows of the reorganized partitions into the new partitions one partition by one partition.
+func (w *worker) reorgPartitionData(tbl table.Table, physicalTableIDs []int64, reorgInfo *reorgInfo) error {
+	for {
+		p := getReorgPhysicalTable(tbl, reorgInfo.PhysicalTableID)
+		if p == nil {
+			return errCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", reorgInfo.PhysicalTableID, tbl.Meta().ID)
+		}
+		logutil.BgLogger().Info("[ddl] start to reorganize partition", zap.String("job", reorgInfo.Job.String()), zap.String("reorgInfo", reorgInfo.String()))
+		err := w.writePhysicalTableRecord(p, typeReorgPartitionWorker, nil, nil, nil, reorgInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		pt, ok := tbl.(table.PartitionedTable)
+		if !ok {
+			// The non-partitioned table is the only physical table to reorganize.
+			return nil
+		}
+		finish, err := w.updateReorgInfoForPartitions(pt, reorgInfo, physicalTableIDs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if finish {
+			return nil
+		}
+	}
+}
+
+// rollbackReorganizePartition removes the new partitions, the reorganized partitions are kept.
+func rollbackReorganizePartition(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo) (ver int64, err error) {
+	pi := tblInfo.Partition
+	physicalTableIDs := getPartitionIDsFromDefinitions(pi.AddingDefinitions)
+	pi.AddingDefinitions = nil
+	pi.DroppingDefinitions = nil
+	pi.States = nil
+	if !pi.Enable {
+		// The table isn't partitioned yet.
+		tblInfo.Partition = nil
+	}
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	// A background job will be created to delete the data of the new partitions.
+	job.Args = []interface{}{physicalTableIDs}
+	return ver, nil
+}
+
+func rollingbackReorganizePartition(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	switch job.SchemaState {
+	case model.StateNone:
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(errCancelledDDLJob)
+	case model.StateWriteReorganization:
+		// The backfill workers may be running, ask them to exit.
+		logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+		w.reorgCtx.notifyReorgCancel()
+		return w.onReorganizePartition(d, t, job)
+	case model.StateDeleteReorganization:
+		// The new partitions have replaced the reorganized ones, the job can't be rolled back.
+		job.State = model.JobStateRunning
+		return ver, nil
+	default:
+		job.State = model.JobStateRollingback
+		return ver, errors.Trace(errCancelledDDLJob)
+	}
+}
+
+type reorgPartitionWorker struct {
+	*backfillWorker
+	metricCounter prometheus.Counter
+
+	// reorgTable is the table with the new partitions, the rows are copied into them.
+	reorgTable  table.Table
+	addingPIDs  map[int64]struct{}
+	rowDecoder  *decoder.RowDecoder
+	rowMap      map[int64]types.Datum
+	defaultVals []types.Datum
+	records     []*reorgPartitionRecord
+}
+
+type reorgPartitionRecord struct {
+	handle    kv.Handle
+	key       kv.Key
+	vals      []byte
+	partition table.PhysicalTable
+	row       []types.Datum
+	rsData    [][]types.Datum
+}
+
+func newReorgPartitionWorker(sessCtx sessionctx.Context, worker *worker, id int, t table.PhysicalTable, decodeColMap map[int64]decoder.Column) (*reorgPartitionWorker, error) {
+	reorgTable, err := getReorganizedPartitionTable(t.Meta())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	addingPIDs := make(map[int64]struct{}, len(t.Meta().Partition.AddingDefinitions))
+	for _, def := range t.Meta().Partition.AddingDefinitions {
+		addingPIDs[def.ID] = struct{}{}
+	}
+	return &reorgPartitionWorker{
+		backfillWorker: newBackfillWorker(sessCtx, worker, id, t),
+		metricCounter:  metrics.BackfillTotalCounter.WithLabelValues("reorg_partition_speed"),
+		reorgTable:     reorgTable,
+		addingPIDs:     addingPIDs,
+		rowDecoder:     decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap),
+		rowMap:         make(map[int64]types.Datum, len(decodeColMap)),
+		defaultVals:    make([]types.Datum, len(t.WritableCols())),
+	}, nil
+}
+
+func (w *reorgPartitionWorker) AddMetricInfo(cnt float64) {
+	w.metricCounter.Add(cnt)
+}
+
+func (w *reorgPartitionWorker) fetchRowColVals(txn kv.Transaction, taskRange reorgBackfillTask) ([]*reorgPartitionRecord, kv.Key, bool, error) {
+	w.records = w.records[:0]
+	startTime := time.Now()
+
+	// taskDone means that the added handle is out of taskRange.endHandle.
+	taskDone := false
+	var lastAccessedHandle kv.Key
+	oprStartTime := startTime
+	err := iterateSnapshotRows(w.sessCtx.GetStore(), w.priority, w.table, txn.StartTS(), taskRange.startKey, taskRange.endKey,
+		func(handle kv.Handle, recordKey kv.Key, rawRow []byte) (bool, error) {
+			oprEndTime := time.Now()
+			logSlowOperations(oprEndTime.Sub(oprStartTime), "iterateSnapshotRows in reorgPartitionWorker fetchRowColVals", 0)
+			oprStartTime = oprEndTime
+
+			taskDone = recordKey.Cmp(taskRange.endKey) > 0
+
+			if taskDone || len(w.records) >= w.batchCnt {
+				return false, nil
+			}
+
+			if err1 := w.getRecord(handle, rawRow); err1 != nil {
+				return false, errors.Trace(err1)
+			}
+			lastAccessedHandle = recordKey
+			if recordKey.Cmp(taskRange.endKey) == 0 {
+				// If taskRange.endIncluded == false, we will not reach here when handle == taskRange.endHandle.
+				taskDone = true
+				return false, nil
+			}
+			return true, nil
+		})
+
+	if len(w.records) == 0 {
+		taskDone = true
+	}
+
+	logutil.BgLogger().Debug("[ddl] txn fetches handle info", zap.Uint64("txnStartTS", txn.StartTS()), zap.String("taskRange", taskRange.String()), zap.Duration("takeTime", time.Since(startTime)))
+	nextKey := taskRange.endKey.Next()
+	if !taskDone {
+		nextKey = lastAccessedHandle.Next()
+	}
+	return w.records, nextKey, taskDone, errors.Trace(err)
+}
+
+// getRecord decodes the row and locates the new partition of it.
+func (w *reorgPartitionWorker) getRecord(handle kv.Handle, rawRow []byte) error {
+	_, err := w.rowDecoder.DecodeAndEvalRowWithMap(w.sessCtx, handle, rawRow, time.UTC, timeutil.SystemLocation(), w.rowMap)
+	if err != nil {
+		return errors.Trace(errCantDecodeRecord.GenWithStackByArgs("partition", err))
+	}
+	defer w.cleanRowMap()
+
+	// The row is arranged by the column offsets, which are used to locate the partition and build the index values.
+	row := make([]types.Datum, len(w.table.Meta().Columns))
+	for _, col := range w.table.WritableCols() {
+		val, ok := w.rowMap[col.ID]
+		if !ok {
+			val, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		row[col.Offset] = val
+	}
+	var p table.PhysicalTable
+	if pt, ok := w.reorgTable.(table.PartitionedTable); ok {
+		p, err = pt.GetPartitionByRow(w.sessCtx, row)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	} else {
+		// The partitioning is being removed, the table itself is the new physical table.
+		p = w.reorgTable.(table.PhysicalTable)
+	}
+	if _, ok := w.addingPIDs[p.GetPhysicalID()]; !ok {
+		return errors.Trace(ErrReorgOutsideRange)
+	}
+	record := &reorgPartitionRecord{
+		handle:    handle,
+		key:       tablecodec.EncodeRecordKey(p.RecordPrefix(), handle),
+		vals:      rawRow,
+		partition: p,
+		row:       row,
+		rsData:    make([][]types.Datum, 0, len(p.Indices())),
+	}
+	for _, idx := range p.Indices() {
+		record.rsData = append(record.rsData, tables.TryGetHandleRestoredDataWrapper(w.table, nil, w.rowMap, idx.Meta()))
+	}
+	w.records = append(w.records, record)
+	return nil
+}
+
+func (w *reorgPartitionWorker) cleanRowMap() {
+	for id := range w.rowMap {
+		delete(w.rowMap, id)
+	}
+}
+
+// BackfillDataInTxn will copy the rows of the task range into the new partitions in a transaction.
+func (w *reorgPartitionWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, errInTxn error) {
+	oprStartTime := time.Now()
+	errInTxn = kv.RunInNewTxn(context.Background(), w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
+		taskCtx.addedCount = 0
+		taskCtx.scanCount = 0
+		txn.SetOption(kv.Priority, w.priority)
+
+		records, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		taskCtx.nextKey = nextKey
+		taskCtx.done = taskDone
+
+		for _, record := range records {
+			taskCtx.scanCount++
+			err = txn.Set(record.key, record.vals)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			for i, idx := range record.partition.Indices() {
+				vals, err := idx.FetchValues(record.row, nil)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				h, err := idx.Create(w.sessCtx, txn, vals, record.handle, record.rsData[i])
+				if err != nil {
+					// The row may be written into the new partition by the DML already.
+					if kv.ErrKeyExists.Equal(err) && h != nil && h.Equal(record.handle) {
+						continue
+					}
+					return errors.Trace(err)
+				}
+			}
+			taskCtx.addedCount++
+		}
+		return nil
+	})
+	logSlowOperations(time.Since(oprStartTime), "BackfillDataInTxn", 3000)
+
+	return
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/meta"
+)
+
+// rowPolicyColumnCollector collects the columns referred by the predicate of a row-level security policy.
+// The subqueries are skipped, their columns are resolved when the predicate is built by the planner.
+type rowPolicyColumnCollector struct {
+	cols []*ast.ColumnName
+}
+
+func (c *rowPolicyColumnCollector) Enter(inNode ast.Node) (ast.Node, bool) {
+	switch x := inNode.(type) {
+	case *ast.SubqueryExpr:
+		return inNode, true
+	case *ast.ColumnNameExpr:
+		c.cols = append(c.cols, x.Name)
+	}
+	return inNode, false
+}
+
+func (c *rowPolicyColumnCollector) Leave(inNode ast.Node) (ast.Node, bool) {
+	return inNode, true
+}
+
+// buildRowPolicyInfo checks the predicates of the policy and builds its meta data.
+func buildRowPolicyInfo(tblInfo *model.TableInfo, stmt *ast.CreatePolicyStmt) (*model.RowPolicyInfo, error) {
+	policyInfo := &model.RowPolicyInfo{Name: stmt.PolicyName}
+	colNames := make(map[string]struct{})
+	for _, expr := range []ast.ExprNode{stmt.Using, stmt.Check} {
+		if expr == nil {
+			continue
+		}
+		var c rowPolicyColumnCollector
+		expr.Accept(&c)
+		for _, colName := range c.cols {
+			col := model.FindColumnInfo(tblInfo.Columns, colName.Name.L)
+			if (colName.Table.L != "" && colName.Table.L != tblInfo.Name.L) || col == nil || col.State != model.StatePublic {
+				return nil, ErrBadField.GenWithStackByArgs(colName.OrigColName(), "policy")
+			}
+			if _, ok := colNames[col.Name.L]; !ok {
+				colNames[col.Name.L] = struct{}{}
+				policyInfo.Cols = append(policyInfo.Cols, col.Name)
+			}
+		}
+	}
+
+	var err error
+	if policyInfo.Using, err = restoreRowPolicyExpr(stmt.Using); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if stmt.Check != nil {
+		if policyInfo.Check, err = restoreRowPolicyExpr(stmt.Check); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return policyInfo, nil
+}
+
+func restoreRowPolicyExpr(expr ast.ExprNode) (string, error) {
+	var sb strings.Builder
+	restoreFlags := format.RestoreStringSingleQuotes | format.RestoreKeyWordLowercase | format.RestoreNameBackQuotes |
+		format.RestoreSpacesAroundBinaryOperation
+	if err := expr.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return "", errors.Trace(err)
+	}
+	return sb.String(), nil
+}
+
+// checkColumnNotUsedByRowPolicies checks whether the column is used by any row-level security policy,
+// such column can't be dropped or renamed.
+func checkColumnNotUsedByRowPolicies(tblInfo *model.TableInfo, colName model.CIStr) error {
+	for _, policy := range tblInfo.RowPolicies {
+		for _, col := range policy.Cols {
+			if col.L == colName.L {
+				return ErrDependentByRowPolicy.GenWithStackByArgs(policy.Name.O, colName.O)
+			}
+		}
+	}
+	return nil
+}
+
+func onCreateRowPolicy(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	policyInfo := &model.RowPolicyInfo{}
+	if err = job.DecodeArgs(policyInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	if tblInfo.FindRowPolicyByName(policyInfo.Name.L) != nil {
+		job.State = model.JobStateCancelled
+		return ver, ErrRowPolicyExists.GenWithStackByArgs(policyInfo.Name.O, tblInfo.Name.O)
+	}
+
+	// The policy only filters the rows read and written by the statements, so it can be created in one step.
+	tblInfo.RowPolicies = append(tblInfo.RowPolicies, policyInfo)
+	ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+func onDropRowPolicy(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	var policyName model.CIStr
+	if err = job.DecodeArgs(&policyName); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	for i, policy := range tblInfo.RowPolicies {
+		if policy.Name.L == policyName.L {
+			tblInfo.RowPolicies = append(tblInfo.RowPolicies[:i], tblInfo.RowPolicies[i+1:]...)
+			ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
+			if err != nil {
+				return ver, errors.Trace(err)
+			}
+			job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+			return ver, nil
+		}
+	}
+	job.State = model.JobStateCancelled
+	return ver, ErrRowPolicyNotExists.GenWithStackByArgs(policyName.O, tblInfo.Name.O)
+}
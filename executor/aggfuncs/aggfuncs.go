@@ -103,6 +103,11 @@ var (
 	// All the AggFunc implementations for "BIT_AND" are listed here.
 	_ AggFunc = (*bitAndUint64)(nil)
 
+	// All the AggFunc implementations for "JSON_ARRAYAGG" are listed here
+	_ AggFunc              = (*jsonArrayagg)(nil)
+	_ AggFunc              = (*jsonArrayagg4Final)(nil)
+	_ SlidingWindowAggFunc = (*jsonArrayagg)(nil)
+
 	// All the AggFunc implementations for "JSON_OBJECTAGG" are listed here
 	_ AggFunc = (*jsonObjectAgg)(nil)
 )
@@ -200,8 +205,9 @@ type SlidingWindowAggFunc interface {
 	// shiftStart, shiftEnd mean the sliding window offset. Note that the input
 	// PartialResult stores the intermediate result which will be used in the next
 	// sliding window, ensure call ResetPartialResult after a frame are evaluated
-	// completely.
-	Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error
+	// completely. The rows of the sliding window are read back from the input
+	// rows by their offsets in the partition.
+	Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error
 }
 
 // MaxMinSlidingWindowAggFunc is the interface to evaluate the max/min agg function using sliding window
@@ -209,3 +215,58 @@ type MaxMinSlidingWindowAggFunc interface {
 	// SetWindowStart sets the start position of window
 	SetWindowStart(start uint64)
 }
+
+// WindowRows is the rows of a window partition, which can be read back by their
+// offsets in the partition. The window executor may spill the rows to disk, so
+// reading a row may fail.
+type WindowRows interface {
+	// NumRows returns the number of rows in the partition.
+	NumRows() uint64
+	// GetRow returns the row at the offset idx of the partition.
+	GetRow(idx uint64) (chunk.Row, error)
+}
+
+// WindowRowsAggFunc is the interface for the window functions which evaluate the
+// current row with the other rows of the partition, e.g. LEAD and RANK.
+type WindowRowsAggFunc interface {
+	// SetWindowRows sets the rows of the current partition, the rows are read back
+	// by index instead of being buffered in the PartialResult. It's used instead
+	// of UpdatePartialResult, ensure call ResetPartialResult after the partition
+	// is evaluated completely.
+	SetWindowRows(rows WindowRows, pr PartialResult)
+}
+
+// partitionRows is the rows of the partition used by WindowRowsAggFunc. The rows
+// are either buffered by UpdatePartialResult or set by SetWindowRows.
+type partitionRows struct {
+	buffered []chunk.Row
+	rows     WindowRows
+}
+
+func (p *partitionRows) reset() {
+	p.buffered = p.buffered[:0]
+	p.rows = nil
+}
+
+func (p *partitionRows) append(rows []chunk.Row) {
+	p.buffered = append(p.buffered, rows...)
+}
+
+func (p *partitionRows) set(rows WindowRows) {
+	p.buffered = p.buffered[:0]
+	p.rows = rows
+}
+
+func (p *partitionRows) numRows() uint64 {
+	if p.rows != nil {
+		return p.rows.NumRows()
+	}
+	return uint64(len(p.buffered))
+}
+
+func (p *partitionRows) getRow(idx uint64) (chunk.Row, error) {
+	if p.rows != nil {
+		return p.rows.GetRow(idx)
+	}
+	return p.buffered[idx], nil
+}
@@ -58,6 +58,8 @@ func Build(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal
 		return buildVarPop(aggFuncDesc, ordinal)
 	case ast.AggFuncStddevPop:
 		return buildStdDevPop(aggFuncDesc, ordinal)
+	case ast.AggFuncJsonArrayagg:
+		return buildJSONArrayagg(aggFuncDesc, ordinal)
 	case ast.AggFuncJsonObjectAgg:
 		return buildJSONObjectAgg(aggFuncDesc, ordinal)
 	case ast.AggFuncApproxCountDistinct:
@@ -73,7 +75,8 @@ func Build(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal
 }
 
 // BuildWindowFunctions builds specific window function according to function description and order by columns.
-func BuildWindowFunctions(ctx sessionctx.Context, windowFuncDesc *aggregation.AggFuncDesc, ordinal int, orderByCols []*expression.Column) AggFunc {
+// ignoreNull and fromLast are the `IGNORE NULLS` and `FROM LAST` options of the window function.
+func BuildWindowFunctions(ctx sessionctx.Context, windowFuncDesc *aggregation.AggFuncDesc, ordinal int, orderByCols []*expression.Column, ignoreNull, fromLast bool) AggFunc {
 	switch windowFuncDesc.Name {
 	case ast.WindowFuncRank:
 		return buildRank(ordinal, orderByCols, false)
@@ -82,26 +85,42 @@ func BuildWindowFunctions(ctx sessionctx.Context, windowFuncDesc *aggregation.Ag
 	case ast.WindowFuncRowNumber:
 		return buildRowNumber(windowFuncDesc, ordinal)
 	case ast.WindowFuncFirstValue:
+		if ignoreNull {
+			return buildNthValueWithNullTreatment(windowFuncDesc, ordinal, 1, false, true)
+		}
 		return buildFirstValue(windowFuncDesc, ordinal)
 	case ast.WindowFuncLastValue:
+		if ignoreNull {
+			return buildNthValueWithNullTreatment(windowFuncDesc, ordinal, 1, true, true)
+		}
 		return buildLastValue(windowFuncDesc, ordinal)
 	case ast.WindowFuncCumeDist:
 		return buildCumeDist(ordinal, orderByCols)
 	case ast.WindowFuncNthValue:
+		if ignoreNull || fromLast {
+			// Already checked when building the function description.
+			nth, _, _ := expression.GetUint64FromConstant(windowFuncDesc.Args[1])
+			return buildNthValueWithNullTreatment(windowFuncDesc, ordinal, nth, fromLast, ignoreNull)
+		}
 		return buildNthValue(windowFuncDesc, ordinal)
 	case ast.WindowFuncNtile:
 		return buildNtile(windowFuncDesc, ordinal)
 	case ast.WindowFuncPercentRank:
 		return buildPercentRank(ordinal, orderByCols)
 	case ast.WindowFuncLead:
-		return buildLead(ctx, windowFuncDesc, ordinal)
+		return buildLead(ctx, windowFuncDesc, ordinal, ignoreNull)
 	case ast.WindowFuncLag:
-		return buildLag(ctx, windowFuncDesc, ordinal)
+		return buildLag(ctx, windowFuncDesc, ordinal, ignoreNull)
 	case ast.AggFuncMax:
 		// The max/min aggFunc using in the window function will using the sliding window algo.
 		return buildMaxMinInWindowFunction(windowFuncDesc, ordinal, true)
 	case ast.AggFuncMin:
 		return buildMaxMinInWindowFunction(windowFuncDesc, ordinal, false)
+	case ast.AggFuncCount, ast.AggFuncSum, ast.AggFuncAvg:
+		if windowFuncDesc.HasDistinct {
+			return buildDistinctInWindowFunction(ctx, windowFuncDesc, ordinal)
+		}
+		return Build(ctx, windowFuncDesc, ordinal)
 	default:
 		return Build(ctx, windowFuncDesc, ordinal)
 	}
@@ -465,6 +484,19 @@ func buildMaxMinInWindowFunction(aggFuncDesc *aggregation.AggFuncDesc, ordinal i
 	return base
 }
 
+// buildDistinctInWindowFunction builds the aggregate function with DISTINCT for window function,
+// it uses the sliding window algo if the function without DISTINCT supports it.
+func buildDistinctInWindowFunction(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	if len(aggFuncDesc.Args) == 1 {
+		nonDistinctDesc := aggFuncDesc.Clone()
+		nonDistinctDesc.HasDistinct = false
+		if f, ok := Build(ctx, nonDistinctDesc, ordinal).(slidingAggFunc); ok {
+			return &slidingWindowDistinct{baseAggFunc: baseAggFunc{args: aggFuncDesc.Args, ordinal: ordinal}, slidingAggFunc: f}
+		}
+	}
+	return Build(ctx, aggFuncDesc, ordinal)
+}
+
 // buildGroupConcat builds the AggFunc implementation for function "GROUP_CONCAT".
 func buildGroupConcat(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	switch aggFuncDesc.Mode {
@@ -615,6 +647,22 @@ func buildStddevSamp(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc
 	}
 }
 
+// buildJSONArrayagg builds the AggFunc implementation for function "json_arrayagg".
+func buildJSONArrayagg(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	switch aggFuncDesc.Mode {
+	case aggregation.DedupMode:
+		return nil
+	case aggregation.FinalMode, aggregation.Partial2Mode:
+		return &jsonArrayagg4Final{jsonArrayagg{base}}
+	default:
+		return &jsonArrayagg{base}
+	}
+}
+
 // buildJSONObjectAgg builds the AggFunc implementation for function "json_objectagg".
 func buildJSONObjectAgg(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	base := baseAggFunc{
@@ -680,6 +728,14 @@ func buildNthValue(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	return &nthValue{baseAggFunc: base, tp: aggFuncDesc.RetTp, nth: nth}
 }
 
+func buildNthValueWithNullTreatment(aggFuncDesc *aggregation.AggFuncDesc, ordinal int, nth uint64, fromLast, ignoreNull bool) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &nthValueWithNullTreatment{baseAggFunc: base, tp: aggFuncDesc.RetTp, nth: nth, fromLast: fromLast, ignoreNull: ignoreNull}
+}
+
 func buildNtile(aggFuncDes *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	base := baseAggFunc{
 		args:    aggFuncDes.Args,
@@ -696,7 +752,7 @@ func buildPercentRank(ordinal int, orderByCols []*expression.Column) AggFunc {
 	return &percentRank{baseAggFunc: base, rowComparer: buildRowComparer(orderByCols)}
 }
 
-func buildLeadLag(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) baseLeadLag {
+func buildLeadLag(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int, ignoreNull bool) baseLeadLag {
 	offset := uint64(1)
 	if len(aggFuncDesc.Args) >= 2 {
 		offset, _, _ = expression.GetUint64FromConstant(aggFuncDesc.Args[1])
@@ -718,13 +774,13 @@ func buildLeadLag(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc,
 		ordinal: ordinal,
 	}
 	ve, _ := buildValueEvaluator(aggFuncDesc.RetTp)
-	return baseLeadLag{baseAggFunc: base, offset: offset, defaultExpr: defaultExpr, valueEvaluator: ve}
+	return baseLeadLag{baseAggFunc: base, offset: offset, defaultExpr: defaultExpr, valueEvaluator: ve, ignoreNull: ignoreNull}
 }
 
-func buildLead(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
-	return &lead{buildLeadLag(ctx, aggFuncDesc, ordinal)}
+func buildLead(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int, ignoreNull bool) AggFunc {
+	return &lead{buildLeadLag(ctx, aggFuncDesc, ordinal, ignoreNull)}
 }
 
-func buildLag(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
-	return &lag{buildLeadLag(ctx, aggFuncDesc, ordinal)}
+func buildLag(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int, ignoreNull bool) AggFunc {
+	return &lag{buildLeadLag(ctx, aggFuncDesc, ordinal, ignoreNull)}
 }
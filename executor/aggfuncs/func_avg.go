@@ -105,10 +105,14 @@ func (e *avgOriginal4Decimal) UpdatePartialResult(sctx sessionctx.Context, rowsI
 	return 0, nil
 }
 
-func (e *avgOriginal4Decimal) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *avgOriginal4Decimal) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4AvgDecimal)(pr)
 	for i := uint64(0); i < shiftEnd; i++ {
-		input, isNull, err := e.args[0].EvalDecimal(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		input, isNull, err := e.args[0].EvalDecimal(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -124,7 +128,11 @@ func (e *avgOriginal4Decimal) Slide(sctx sessionctx.Context, rows []chunk.Row, l
 		p.count++
 	}
 	for i := uint64(0); i < shiftStart; i++ {
-		input, isNull, err := e.args[0].EvalDecimal(sctx, rows[lastStart+i])
+		row, err := rows.GetRow(lastStart + i)
+		if err != nil {
+			return err
+		}
+		input, isNull, err := e.args[0].EvalDecimal(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -325,10 +333,14 @@ type avgOriginal4Float64 struct {
 	avgOriginal4Float64HighPrecision
 }
 
-func (e *avgOriginal4Float64) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *avgOriginal4Float64) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4AvgFloat64)(pr)
 	for i := uint64(0); i < shiftEnd; i++ {
-		input, isNull, err := e.args[0].EvalReal(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		input, isNull, err := e.args[0].EvalReal(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -339,7 +351,11 @@ func (e *avgOriginal4Float64) Slide(sctx sessionctx.Context, rows []chunk.Row, l
 		p.count++
 	}
 	for i := uint64(0); i < shiftStart; i++ {
-		input, isNull, err := e.args[0].EvalReal(sctx, rows[lastStart+i])
+		row, err := rows.GetRow(lastStart + i)
+		if err != nil {
+			return err
+		}
+		input, isNull, err := e.args[0].EvalReal(sctx, row)
 		if err != nil {
 			return err
 		}
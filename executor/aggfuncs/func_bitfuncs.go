@@ -91,10 +91,14 @@ func (e *bitXorUint64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup
 	return memDelta, nil
 }
 
-func (e *bitXorUint64) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *bitXorUint64) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4BitFunc)(pr)
 	for i := uint64(0); i < shiftStart; i++ {
-		inputValue, isNull, err := e.args[0].EvalInt(sctx, rows[lastStart+i])
+		row, err := rows.GetRow(lastStart + i)
+		if err != nil {
+			return err
+		}
+		inputValue, isNull, err := e.args[0].EvalInt(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -104,7 +108,11 @@ func (e *bitXorUint64) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStar
 		*p ^= uint64(inputValue)
 	}
 	for i := uint64(0); i < shiftEnd; i++ {
-		inputValue, isNull, err := e.args[0].EvalInt(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		inputValue, isNull, err := e.args[0].EvalInt(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -68,10 +68,14 @@ func (e *countOriginal4Int) UpdatePartialResult(sctx sessionctx.Context, rowsInG
 	return 0, nil
 }
 
-func (e *countOriginal4Int) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *countOriginal4Int) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4Count)(pr)
 	for i := uint64(0); i < shiftStart; i++ {
-		_, isNull, err := e.args[0].EvalInt(sctx, rows[lastStart+i])
+		row, err := rows.GetRow(lastStart + i)
+		if err != nil {
+			return err
+		}
+		_, isNull, err := e.args[0].EvalInt(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -81,7 +85,11 @@ func (e *countOriginal4Int) Slide(sctx sessionctx.Context, rows []chunk.Row, las
 		*p--
 	}
 	for i := uint64(0); i < shiftEnd; i++ {
-		_, isNull, err := e.args[0].EvalInt(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		_, isNull, err := e.args[0].EvalInt(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -115,10 +123,14 @@ func (e *countOriginal4Real) UpdatePartialResult(sctx sessionctx.Context, rowsIn
 	return 0, nil
 }
 
-func (e *countOriginal4Real) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *countOriginal4Real) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4Count)(pr)
 	for i := uint64(0); i < shiftStart; i++ {
-		_, isNull, err := e.args[0].EvalReal(sctx, rows[lastStart+i])
+		row, err := rows.GetRow(lastStart + i)
+		if err != nil {
+			return err
+		}
+		_, isNull, err := e.args[0].EvalReal(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -128,7 +140,11 @@ func (e *countOriginal4Real) Slide(sctx sessionctx.Context, rows []chunk.Row, la
 		*p--
 	}
 	for i := uint64(0); i < shiftEnd; i++ {
-		_, isNull, err := e.args[0].EvalReal(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		_, isNull, err := e.args[0].EvalReal(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -162,10 +178,14 @@ func (e *countOriginal4Decimal) UpdatePartialResult(sctx sessionctx.Context, row
 	return 0, nil
 }
 
-func (e *countOriginal4Decimal) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *countOriginal4Decimal) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4Count)(pr)
 	for i := uint64(0); i < shiftStart; i++ {
-		_, isNull, err := e.args[0].EvalDecimal(sctx, rows[lastStart+i])
+		row, err := rows.GetRow(lastStart + i)
+		if err != nil {
+			return err
+		}
+		_, isNull, err := e.args[0].EvalDecimal(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -175,7 +195,11 @@ func (e *countOriginal4Decimal) Slide(sctx sessionctx.Context, rows []chunk.Row,
 		*p--
 	}
 	for i := uint64(0); i < shiftEnd; i++ {
-		_, isNull, err := e.args[0].EvalDecimal(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		_, isNull, err := e.args[0].EvalDecimal(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -209,10 +233,14 @@ func (e *countOriginal4Time) UpdatePartialResult(sctx sessionctx.Context, rowsIn
 	return 0, nil
 }
 
-func (e *countOriginal4Time) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *countOriginal4Time) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4Count)(pr)
 	for i := uint64(0); i < shiftStart; i++ {
-		_, isNull, err := e.args[0].EvalTime(sctx, rows[lastStart+i])
+		row, err := rows.GetRow(lastStart + i)
+		if err != nil {
+			return err
+		}
+		_, isNull, err := e.args[0].EvalTime(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -222,7 +250,11 @@ func (e *countOriginal4Time) Slide(sctx sessionctx.Context, rows []chunk.Row, la
 		*p--
 	}
 	for i := uint64(0); i < shiftEnd; i++ {
-		_, isNull, err := e.args[0].EvalTime(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		_, isNull, err := e.args[0].EvalTime(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -256,10 +288,14 @@ func (e *countOriginal4Duration) UpdatePartialResult(sctx sessionctx.Context, ro
 	return 0, nil
 }
 
-func (e *countOriginal4Duration) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *countOriginal4Duration) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4Count)(pr)
 	for i := uint64(0); i < shiftStart; i++ {
-		_, isNull, err := e.args[0].EvalDuration(sctx, rows[lastStart+i])
+		row, err := rows.GetRow(lastStart + i)
+		if err != nil {
+			return err
+		}
+		_, isNull, err := e.args[0].EvalDuration(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -269,7 +305,11 @@ func (e *countOriginal4Duration) Slide(sctx sessionctx.Context, rows []chunk.Row
 		*p--
 	}
 	for i := uint64(0); i < shiftEnd; i++ {
-		_, isNull, err := e.args[0].EvalDuration(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		_, isNull, err := e.args[0].EvalDuration(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -303,10 +343,14 @@ func (e *countOriginal4JSON) UpdatePartialResult(sctx sessionctx.Context, rowsIn
 	return 0, nil
 }
 
-func (e *countOriginal4JSON) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *countOriginal4JSON) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4Count)(pr)
 	for i := uint64(0); i < shiftStart; i++ {
-		_, isNull, err := e.args[0].EvalJSON(sctx, rows[lastStart+i])
+		row, err := rows.GetRow(lastStart + i)
+		if err != nil {
+			return err
+		}
+		_, isNull, err := e.args[0].EvalJSON(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -316,7 +360,11 @@ func (e *countOriginal4JSON) Slide(sctx sessionctx.Context, rows []chunk.Row, la
 		*p--
 	}
 	for i := uint64(0); i < shiftEnd; i++ {
-		_, isNull, err := e.args[0].EvalJSON(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		_, isNull, err := e.args[0].EvalJSON(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -350,10 +398,14 @@ func (e *countOriginal4String) UpdatePartialResult(sctx sessionctx.Context, rows
 	return 0, nil
 }
 
-func (e *countOriginal4String) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *countOriginal4String) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4Count)(pr)
 	for i := uint64(0); i < shiftStart; i++ {
-		_, isNull, err := e.args[0].EvalString(sctx, rows[lastStart+i])
+		row, err := rows.GetRow(lastStart + i)
+		if err != nil {
+			return err
+		}
+		_, isNull, err := e.args[0].EvalString(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -363,7 +415,11 @@ func (e *countOriginal4String) Slide(sctx sessionctx.Context, rows []chunk.Row,
 		*p--
 	}
 	for i := uint64(0); i < shiftEnd; i++ {
-		_, isNull, err := e.args[0].EvalString(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		_, isNull, err := e.args[0].EvalString(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -33,7 +33,7 @@ type cumeDist struct {
 type partialResult4CumeDist struct {
 	curIdx   int
 	lastRank int
-	rows     []chunk.Row
+	rows     partitionRows
 }
 
 func (r *cumeDist) AllocPartialResult() (pr PartialResult, memDelta int64) {
@@ -44,20 +44,32 @@ func (r *cumeDist) ResetPartialResult(pr PartialResult) {
 	p := (*partialResult4CumeDist)(pr)
 	p.curIdx = 0
 	p.lastRank = 0
-	p.rows = p.rows[:0]
+	p.rows.reset()
 }
 
 func (r *cumeDist) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4CumeDist)(pr)
-	p.rows = append(p.rows, rowsInGroup...)
+	p.rows.append(rowsInGroup)
 	memDelta += int64(len(rowsInGroup)) * DefRowSize
 	return memDelta, nil
 }
 
+func (r *cumeDist) SetWindowRows(rows WindowRows, pr PartialResult) {
+	p := (*partialResult4CumeDist)(pr)
+	p.rows.set(rows)
+}
+
 func (r *cumeDist) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
 	p := (*partialResult4CumeDist)(pr)
-	numRows := len(p.rows)
-	for p.lastRank < numRows && r.compareRows(p.rows[p.curIdx], p.rows[p.lastRank]) == 0 {
+	numRows := int(p.rows.numRows())
+	for p.lastRank < numRows {
+		cmp, err := r.compareRowsAt(&p.rows, uint64(p.curIdx), uint64(p.lastRank))
+		if err != nil {
+			return err
+		}
+		if cmp != 0 {
+			break
+		}
 		p.lastRank++
 	}
 	p.curIdx++
@@ -0,0 +1,170 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"unsafe"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+const (
+	// DefPartialResult4JsonArrayagg is the size of partialResult4JsonArrayagg
+	DefPartialResult4JsonArrayagg = int64(unsafe.Sizeof(partialResult4JsonArrayagg{}))
+)
+
+type jsonArrayagg struct {
+	baseAggFunc
+}
+
+type partialResult4JsonArrayagg struct {
+	entries []interface{}
+}
+
+func (e *jsonArrayagg) AllocPartialResult() (pr PartialResult, memDelta int64) {
+	p := partialResult4JsonArrayagg{}
+	p.entries = make([]interface{}, 0)
+	return PartialResult(&p), DefPartialResult4JsonArrayagg
+}
+
+func (e *jsonArrayagg) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4JsonArrayagg)(pr)
+	p.entries = p.entries[:0]
+}
+
+func (e *jsonArrayagg) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4JsonArrayagg)(pr)
+	if len(p.entries) == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendJSON(e.ordinal, json.CreateBinary(p.entries))
+	return nil
+}
+
+func (e *jsonArrayagg) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*partialResult4JsonArrayagg)(pr)
+	for _, row := range rowsInGroup {
+		item, err := e.args[0].Eval(row)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		realItem, err := jsonArrayaggEntry(item)
+		if err != nil {
+			return 0, err
+		}
+		memDelta += e.appendEntry(p, realItem)
+	}
+	return memDelta, nil
+}
+
+// appendEntry appends the entry to the partial result and returns the memory delta.
+func (e *jsonArrayagg) appendEntry(p *partialResult4JsonArrayagg, entry interface{}) (memDelta int64) {
+	if len(p.entries) == cap(p.entries) {
+		// The slice would be grown, count the memory of the new allocated slots.
+		newCap := int64(cap(append(p.entries, nil)[:0]))
+		memDelta += (newCap - int64(cap(p.entries))) * DefInterfaceSize
+	}
+	p.entries = append(p.entries, entry)
+	return memDelta + getValMemDelta(entry) - DefInterfaceSize
+}
+
+func (e *jsonArrayagg) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
+	p1, p2 := (*partialResult4JsonArrayagg)(src), (*partialResult4JsonArrayagg)(dst)
+	for _, entry := range p1.entries {
+		memDelta += e.appendEntry(p2, entry)
+	}
+	return memDelta, nil
+}
+
+func (e *jsonArrayagg) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+	p := (*partialResult4JsonArrayagg)(pr)
+	for i := uint64(0); i < shiftEnd; i++ {
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		item, err := e.args[0].Eval(row)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		realItem, err := jsonArrayaggEntry(item)
+		if err != nil {
+			return err
+		}
+		p.entries = append(p.entries, realItem)
+	}
+	// The entries are in the order of the rows, so the first shiftStart entries belong to the rows
+	// which slide out of the window.
+	p.entries = p.entries[shiftStart:]
+	return nil
+}
+
+// jsonArrayaggEntry converts the datum to a value which could be appended to a binary json array.
+func jsonArrayaggEntry(item types.Datum) (interface{}, error) {
+	// appendBinary does not support some type such as uint8、types.time，so convert is needed here
+	switch x := item.Clone().GetValue().(type) {
+	case nil, bool, int64, uint64, float64, string, json.BinaryJSON:
+		return x, nil
+	case *types.MyDecimal:
+		float64Val, err := x.ToFloat64()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return float64Val, nil
+	case []uint8, types.Time, types.Duration:
+		strVal, err := types.ToString(x)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return strVal, nil
+	case float32:
+		return float64(x), nil
+	default:
+		strVal, err := item.ToString()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return strVal, nil
+	}
+}
+
+// jsonArrayagg4Final merges the partial json arrays produced by the json_arrayagg in partial mode.
+type jsonArrayagg4Final struct {
+	jsonArrayagg
+}
+
+func (e *jsonArrayagg4Final) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*partialResult4JsonArrayagg)(pr)
+	for _, row := range rowsInGroup {
+		partial, isNull, err := e.args[0].EvalJSON(sctx, row)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if isNull {
+			continue
+		}
+		if partial.TypeCode != json.TypeCodeArray {
+			return 0, errors.Errorf("unexpected partial result of json_arrayagg: %s", partial.String())
+		}
+		for i := 0; i < partial.GetElemCount(); i++ {
+			memDelta += e.appendEntry(p, partial.ArrayGetElem(i).Copy())
+		}
+	}
+	return memDelta, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs_test
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// jsonArrayaggEntries returns the expected entries of json_arrayagg on the rows [start, numRows) and a NULL row.
+func jsonArrayaggEntries(ft *types.FieldType, start, numRows int) []interface{} {
+	genFunc := getDataGenFunc(ft)
+	entries := make([]interface{}, 0, numRows-start+1)
+	for m := start; m < numRows; m++ {
+		// appendBinary does not support some type such as uint8、types.time，so convert is needed here
+		d := genFunc(m)
+		switch x := d.GetValue().(type) {
+		case *types.MyDecimal:
+			float64Val, _ := x.ToFloat64()
+			entries = append(entries, float64Val)
+		case []uint8, types.Time, types.Duration:
+			strVal, _ := types.ToString(x)
+			entries = append(entries, strVal)
+		default:
+			entries = append(entries, x)
+		}
+	}
+	return append(entries, nil)
+}
+
+func (s *testSuite) TestMergePartialResult4JsonArrayagg(c *C) {
+	typeList := []byte{mysql.TypeLonglong, mysql.TypeDouble, mysql.TypeString, mysql.TypeJSON, mysql.TypeNewDecimal, mysql.TypeDate}
+	numRows := 5
+	for _, tp := range typeList {
+		ft := types.NewFieldType(tp)
+		entries1 := jsonArrayaggEntries(ft, 0, numRows)
+		entries2 := jsonArrayaggEntries(ft, 2, numRows)
+		aggTest := buildMultiArgsAggTester(ast.AggFuncJsonArrayagg, []byte{tp}, mysql.TypeJSON, numRows,
+			json.CreateBinary(entries1), json.CreateBinary(entries2), json.CreateBinary(append(entries1, entries2...)))
+		s.testMultiArgsMergePartialResult(c, aggTest)
+	}
+}
+
+func (s *testSuite) TestJsonArrayagg(c *C) {
+	typeList := []byte{mysql.TypeLonglong, mysql.TypeDouble, mysql.TypeString, mysql.TypeJSON, mysql.TypeDuration, mysql.TypeNewDecimal, mysql.TypeDate}
+	numRows := 5
+	for _, tp := range typeList {
+		ft := types.NewFieldType(tp)
+		aggTest := buildMultiArgsAggTester(ast.AggFuncJsonArrayagg, []byte{tp}, mysql.TypeJSON, numRows, nil, json.CreateBinary(jsonArrayaggEntries(ft, 0, numRows)))
+		srcChk := aggTest.genSrcChk()
+
+		desc, err := aggregation.NewAggFuncDesc(s.ctx, ast.AggFuncJsonArrayagg, []expression.Expression{&expression.Column{RetType: ft, Index: 0}}, false)
+		c.Assert(err, IsNil)
+		finalFunc := aggfuncs.Build(s.ctx, desc, 0)
+		finalPr, _ := finalFunc.AllocPartialResult()
+		resultChk := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, 1)
+
+		iter := chunk.NewIterator4Chunk(srcChk)
+		for row := iter.Begin(); row != iter.End(); row = iter.Next() {
+			_, err = finalFunc.UpdatePartialResult(s.ctx, []chunk.Row{row}, finalPr)
+			c.Assert(err, IsNil)
+		}
+		aggTest.messUpChunk(srcChk)
+		err = finalFunc.AppendFinalResult2Chunk(s.ctx, finalPr, resultChk)
+		c.Assert(err, IsNil)
+		dt := resultChk.GetRow(0).GetDatum(0, desc.RetTp)
+		result, err := dt.CompareDatum(s.ctx.GetSessionVars().StmtCtx, &aggTest.results[1])
+		c.Assert(err, IsNil)
+		c.Assert(result, Equals, 0, Commentf("%v != %v", dt.String(), aggTest.results[1]))
+
+		// test the empty input
+		resultChk.Reset()
+		finalFunc.ResetPartialResult(finalPr)
+		err = finalFunc.AppendFinalResult2Chunk(s.ctx, finalPr, resultChk)
+		c.Assert(err, IsNil)
+		c.Assert(resultChk.GetRow(0).IsNull(0), IsTrue)
+	}
+}
+
+func jsonArrayaggMemDeltaGens(srcChk *chunk.Chunk, dataTypes []*types.FieldType, byItems []*util.ByItems) (memDeltas []int64, err error) {
+	memDeltas = make([]int64, 0)
+	entries := make([]interface{}, 0)
+	for i := 0; i < srcChk.NumRows(); i++ {
+		memDelta := int64(0)
+		if len(entries) == cap(entries) {
+			newCap := int64(cap(append(entries, nil)[:0]))
+			memDelta += (newCap - int64(cap(entries))) * aggfuncs.DefInterfaceSize
+		}
+		entries = append(entries, nil)
+		row := srcChk.GetRow(i)
+		if row.IsNull(0) {
+			memDeltas = append(memDeltas, memDelta)
+			continue
+		}
+		switch dataTypes[0].Tp {
+		case mysql.TypeLonglong:
+			memDelta += aggfuncs.DefInt64Size
+		case mysql.TypeDouble, mysql.TypeNewDecimal:
+			memDelta += aggfuncs.DefFloat64Size
+		case mysql.TypeString:
+			memDelta += int64(len(row.GetString(0)))
+		case mysql.TypeJSON:
+			// +1 for the memory usage of the TypeCode of json
+			memDelta += int64(len(row.GetJSON(0).Value) + 1)
+		case mysql.TypeDuration, mysql.TypeDate:
+			d := row.GetDatum(0, dataTypes[0])
+			str, err := d.ToString()
+			if err != nil {
+				return memDeltas, err
+			}
+			memDelta += int64(len(str))
+		default:
+			return memDeltas, errors.Errorf("unsupported type - %v", dataTypes[0].Tp)
+		}
+		memDeltas = append(memDeltas, memDelta)
+	}
+	return memDeltas, nil
+}
+
+func (s *testSuite) TestMemJsonArrayagg(c *C) {
+	typeList := []byte{mysql.TypeLonglong, mysql.TypeDouble, mysql.TypeString, mysql.TypeJSON, mysql.TypeDuration, mysql.TypeNewDecimal, mysql.TypeDate}
+	numRows := 5
+	for _, tp := range typeList {
+		test := buildMultiArgsAggMemTester(ast.AggFuncJsonArrayagg, []byte{tp}, mysql.TypeJSON, numRows, aggfuncs.DefPartialResult4JsonArrayagg, jsonArrayaggMemDeltaGens, false)
+		s.testMultiArgsAggMemFunc(c, test)
+	}
+}
@@ -14,6 +14,7 @@
 package aggfuncs
 
 import (
+	"sort"
 	"unsafe"
 
 	"github.com/pingcap/tidb/expression"
@@ -32,11 +33,17 @@ type baseLeadLag struct {
 
 	defaultExpr expression.Expression
 	offset      uint64
+	// ignoreNull indicates the offset is counted by the rows whose values are not null.
+	ignoreNull bool
 }
 
 type partialResult4LeadLag struct {
-	rows   []chunk.Row
+	rows   partitionRows
 	curIdx uint64
+	// nonNullIdxs is the offsets of the rows whose values are not null, it's only
+	// collected for `IGNORE NULLS`.
+	nonNullIdxs      []uint64
+	nonNullCollected bool
 }
 
 func (v *baseLeadLag) AllocPartialResult() (pr PartialResult, memDelta int64) {
@@ -45,17 +52,74 @@ func (v *baseLeadLag) AllocPartialResult() (pr PartialResult, memDelta int64) {
 
 func (v *baseLeadLag) ResetPartialResult(pr PartialResult) {
 	p := (*partialResult4LeadLag)(pr)
-	p.rows = p.rows[:0]
+	p.rows.reset()
 	p.curIdx = 0
+	p.nonNullIdxs = p.nonNullIdxs[:0]
+	p.nonNullCollected = false
 }
 
 func (v *baseLeadLag) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4LeadLag)(pr)
-	p.rows = append(p.rows, rowsInGroup...)
+	p.rows.append(rowsInGroup)
 	memDelta += int64(len(rowsInGroup)) * DefRowSize
 	return memDelta, nil
 }
 
+func (v *baseLeadLag) SetWindowRows(rows WindowRows, pr PartialResult) {
+	p := (*partialResult4LeadLag)(pr)
+	p.rows.set(rows)
+}
+
+func (v *baseLeadLag) evaluateRowAt(sctx sessionctx.Context, expr expression.Expression, p *partialResult4LeadLag, idx uint64) error {
+	row, err := p.rows.getRow(idx)
+	if err != nil {
+		return err
+	}
+	_, err = v.evaluateRow(sctx, expr, row)
+	return err
+}
+
+// collectNonNullRows collects the offsets of the rows whose values are not null.
+func (v *baseLeadLag) collectNonNullRows(sctx sessionctx.Context, p *partialResult4LeadLag) error {
+	numRows := p.rows.numRows()
+	for i := uint64(0); i < numRows; i++ {
+		if err := v.evaluateRowAt(sctx, v.args[0], p, i); err != nil {
+			return err
+		}
+		if !v.null() {
+			p.nonNullIdxs = append(p.nonNullIdxs, i)
+		}
+	}
+	p.nonNullCollected = true
+	return nil
+}
+
+// evaluateIgnoringNulls evaluates the value of the row which is `offset` non-null rows
+// following or preceding the current row.
+func (v *baseLeadLag) evaluateIgnoringNulls(sctx sessionctx.Context, p *partialResult4LeadLag, following bool) error {
+	if v.offset == 0 {
+		return v.evaluateRowAt(sctx, v.args[0], p, p.curIdx)
+	}
+	if !p.nonNullCollected {
+		if err := v.collectNonNullRows(sctx, p); err != nil {
+			return err
+		}
+	}
+	idxs := p.nonNullIdxs
+	if following {
+		pos := sort.Search(len(idxs), func(i int) bool { return idxs[i] > p.curIdx })
+		if uint64(len(idxs)-pos) >= v.offset {
+			return v.evaluateRowAt(sctx, v.args[0], p, idxs[uint64(pos)+v.offset-1])
+		}
+	} else {
+		pos := sort.Search(len(idxs), func(i int) bool { return idxs[i] >= p.curIdx })
+		if uint64(pos) >= v.offset {
+			return v.evaluateRowAt(sctx, v.args[0], p, idxs[uint64(pos)-v.offset])
+		}
+	}
+	return v.evaluateRowAt(sctx, v.defaultExpr, p, p.curIdx)
+}
+
 type lead struct {
 	baseLeadLag
 }
@@ -63,10 +127,12 @@ type lead struct {
 func (v *lead) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
 	p := (*partialResult4LeadLag)(pr)
 	var err error
-	if p.curIdx+v.offset < uint64(len(p.rows)) {
-		_, err = v.evaluateRow(sctx, v.args[0], p.rows[p.curIdx+v.offset])
+	if v.ignoreNull {
+		err = v.evaluateIgnoringNulls(sctx, p, true)
+	} else if p.curIdx+v.offset < p.rows.numRows() {
+		err = v.evaluateRowAt(sctx, v.args[0], p, p.curIdx+v.offset)
 	} else {
-		_, err = v.evaluateRow(sctx, v.defaultExpr, p.rows[p.curIdx])
+		err = v.evaluateRowAt(sctx, v.defaultExpr, p, p.curIdx)
 	}
 	if err != nil {
 		return err
@@ -83,10 +149,12 @@ type lag struct {
 func (v *lag) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
 	p := (*partialResult4LeadLag)(pr)
 	var err error
-	if p.curIdx >= v.offset {
-		_, err = v.evaluateRow(sctx, v.args[0], p.rows[p.curIdx-v.offset])
+	if v.ignoreNull {
+		err = v.evaluateIgnoringNulls(sctx, p, false)
+	} else if p.curIdx >= v.offset {
+		err = v.evaluateRowAt(sctx, v.args[0], p, p.curIdx-v.offset)
 	} else {
-		_, err = v.evaluateRow(sctx, v.defaultExpr, p.rows[p.curIdx])
+		err = v.evaluateRowAt(sctx, v.defaultExpr, p, p.curIdx)
 	}
 	if err != nil {
 		return err
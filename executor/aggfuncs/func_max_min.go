@@ -343,10 +343,14 @@ func (e *maxMin4IntSliding) UpdatePartialResult(sctx sessionctx.Context, rowsInG
 	return 0, nil
 }
 
-func (e *maxMin4IntSliding) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *maxMin4IntSliding) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4MaxMinInt)(pr)
 	for i := uint64(0); i < shiftEnd; i++ {
-		input, isNull, err := e.args[0].EvalInt(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		input, isNull, err := e.args[0].EvalInt(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -480,10 +484,14 @@ func (e *maxMin4UintSliding) UpdatePartialResult(sctx sessionctx.Context, rowsIn
 	return 0, nil
 }
 
-func (e *maxMin4UintSliding) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *maxMin4UintSliding) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4MaxMinUint)(pr)
 	for i := uint64(0); i < shiftEnd; i++ {
-		input, isNull, err := e.args[0].EvalInt(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		input, isNull, err := e.args[0].EvalInt(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -617,10 +625,14 @@ func (e *maxMin4Float32Sliding) UpdatePartialResult(sctx sessionctx.Context, row
 	return 0, nil
 }
 
-func (e *maxMin4Float32Sliding) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *maxMin4Float32Sliding) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4MaxMinFloat32)(pr)
 	for i := uint64(0); i < shiftEnd; i++ {
-		input, isNull, err := e.args[0].EvalReal(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		input, isNull, err := e.args[0].EvalReal(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -752,10 +764,14 @@ func (e *maxMin4Float64Sliding) UpdatePartialResult(sctx sessionctx.Context, row
 	return 0, nil
 }
 
-func (e *maxMin4Float64Sliding) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *maxMin4Float64Sliding) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4MaxMinFloat64)(pr)
 	for i := uint64(0); i < shiftEnd; i++ {
-		input, isNull, err := e.args[0].EvalReal(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		input, isNull, err := e.args[0].EvalReal(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -904,10 +920,14 @@ func (e *maxMin4DecimalSliding) UpdatePartialResult(sctx sessionctx.Context, row
 	return 0, nil
 }
 
-func (e *maxMin4DecimalSliding) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *maxMin4DecimalSliding) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4MaxMinDecimal)(pr)
 	for i := uint64(0); i < shiftEnd; i++ {
-		input, isNull, err := e.args[0].EvalDecimal(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		input, isNull, err := e.args[0].EvalDecimal(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -1052,10 +1072,14 @@ func (e *maxMin4StringSliding) UpdatePartialResult(sctx sessionctx.Context, rows
 	return 0, nil
 }
 
-func (e *maxMin4StringSliding) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *maxMin4StringSliding) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4MaxMinString)(pr)
 	for i := uint64(0); i < shiftEnd; i++ {
-		input, isNull, err := e.args[0].EvalString(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		input, isNull, err := e.args[0].EvalString(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -1190,10 +1214,14 @@ func (e *maxMin4TimeSliding) UpdatePartialResult(sctx sessionctx.Context, rowsIn
 	return 0, nil
 }
 
-func (e *maxMin4TimeSliding) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *maxMin4TimeSliding) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4MaxMinTime)(pr)
 	for i := uint64(0); i < shiftEnd; i++ {
-		input, isNull, err := e.args[0].EvalTime(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		input, isNull, err := e.args[0].EvalTime(sctx, row)
 		if err != nil {
 			return err
 		}
@@ -1328,10 +1356,14 @@ func (e *maxMin4DurationSliding) UpdatePartialResult(sctx sessionctx.Context, ro
 	return 0, nil
 }
 
-func (e *maxMin4DurationSliding) Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+func (e *maxMin4DurationSliding) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
 	p := (*partialResult4MaxMinDuration)(pr)
 	for i := uint64(0); i < shiftEnd; i++ {
-		input, isNull, err := e.args[0].EvalDuration(sctx, rows[lastEnd+i])
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		input, isNull, err := e.args[0].EvalDuration(sctx, row)
 		if err != nil {
 			return err
 		}
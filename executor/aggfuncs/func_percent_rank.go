@@ -33,26 +33,35 @@ func (pr *percentRank) ResetPartialResult(partial PartialResult) {
 	p := (*partialResult4Rank)(partial)
 	p.curIdx = 0
 	p.lastRank = 0
-	p.rows = p.rows[:0]
+	p.rows.reset()
 }
 
 func (pr *percentRank) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, partial PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4Rank)(partial)
-	p.rows = append(p.rows, rowsInGroup...)
+	p.rows.append(rowsInGroup)
 	memDelta += int64(len(rowsInGroup)) * DefRowSize
 	return memDelta, nil
 }
 
+func (pr *percentRank) SetWindowRows(rows WindowRows, partial PartialResult) {
+	p := (*partialResult4Rank)(partial)
+	p.rows.set(rows)
+}
+
 func (pr *percentRank) AppendFinalResult2Chunk(sctx sessionctx.Context, partial PartialResult, chk *chunk.Chunk) error {
 	p := (*partialResult4Rank)(partial)
-	numRows := int64(len(p.rows))
+	numRows := int64(p.rows.numRows())
 	p.curIdx++
 	if p.curIdx == 1 {
 		p.lastRank = 1
 		chk.AppendFloat64(pr.ordinal, 0)
 		return nil
 	}
-	if pr.compareRows(p.rows[p.curIdx-2], p.rows[p.curIdx-1]) == 0 {
+	cmp, err := pr.compareRowsAt(&p.rows, uint64(p.curIdx-2), uint64(p.curIdx-1))
+	if err != nil {
+		return err
+	}
+	if cmp == 0 {
 		chk.AppendFloat64(pr.ordinal, float64(p.lastRank-1)/float64(numRows-1))
 		return nil
 	}
@@ -35,7 +35,7 @@ type rank struct {
 type partialResult4Rank struct {
 	curIdx   int64
 	lastRank int64
-	rows     []chunk.Row
+	rows     partitionRows
 }
 
 func (r *rank) AllocPartialResult() (pr PartialResult, memDelta int64) {
@@ -46,16 +46,21 @@ func (r *rank) ResetPartialResult(pr PartialResult) {
 	p := (*partialResult4Rank)(pr)
 	p.curIdx = 0
 	p.lastRank = 0
-	p.rows = p.rows[:0]
+	p.rows.reset()
 }
 
 func (r *rank) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4Rank)(pr)
-	p.rows = append(p.rows, rowsInGroup...)
+	p.rows.append(rowsInGroup)
 	memDelta += int64(len(rowsInGroup)) * DefRowSize
 	return memDelta, nil
 }
 
+func (r *rank) SetWindowRows(rows WindowRows, pr PartialResult) {
+	p := (*partialResult4Rank)(pr)
+	p.rows.set(rows)
+}
+
 func (r *rank) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
 	p := (*partialResult4Rank)(pr)
 	p.curIdx++
@@ -64,7 +69,11 @@ func (r *rank) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult
 		chk.AppendInt64(r.ordinal, p.lastRank)
 		return nil
 	}
-	if r.compareRows(p.rows[p.curIdx-2], p.rows[p.curIdx-1]) == 0 {
+	cmp, err := r.compareRowsAt(&p.rows, uint64(p.curIdx-2), uint64(p.curIdx-1))
+	if err != nil {
+		return err
+	}
+	if cmp == 0 {
 		chk.AppendInt64(r.ordinal, p.lastRank)
 		return nil
 	}
@@ -106,3 +115,15 @@ func (rc *rowComparer) compareRows(prev, curr chunk.Row) int {
 	}
 	return 0
 }
+
+func (rc *rowComparer) compareRowsAt(rows *partitionRows, prevIdx, currIdx uint64) (int, error) {
+	prev, err := rows.getRow(prevIdx)
+	if err != nil {
+		return 0, err
+	}
+	curr, err := rows.getRow(currIdx)
+	if err != nil {
+		return 0, err
+	}
+	return rc.compareRows(prev, curr), nil
+}
@@ -0,0 +1,199 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"unsafe"
+
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/collate"
+)
+
+const (
+	// DefPartialResult4SlidingWindowDistinctSize is the size of partialResult4SlidingWindowDistinct
+	DefPartialResult4SlidingWindowDistinctSize = int64(unsafe.Sizeof(partialResult4SlidingWindowDistinct{}))
+)
+
+// slidingAggFunc is the aggregate function which can be evaluated using sliding window.
+type slidingAggFunc interface {
+	AggFunc
+	SlidingWindowAggFunc
+}
+
+// slidingWindowDistinct evaluates the aggregate function with DISTINCT using sliding window.
+// It counts the occurrences of the values in the frame, and only the rows whose values
+// enter or leave the frame are passed to the wrapped function without DISTINCT.
+type slidingWindowDistinct struct {
+	baseAggFunc
+	slidingAggFunc
+}
+
+type partialResult4SlidingWindowDistinct struct {
+	inner PartialResult
+	// counts is the occurrences of the encoded values in the frame.
+	counts map[string]int64
+	// rows buffers the rows passed to the wrapped function.
+	rows    []chunk.Row
+	encoded []byte
+	buf     []byte
+}
+
+// rowsInMemory is the WindowRows whose rows are all in memory.
+type rowsInMemory []chunk.Row
+
+// NumRows implements the WindowRows interface.
+func (rows rowsInMemory) NumRows() uint64 {
+	return uint64(len(rows))
+}
+
+// GetRow implements the WindowRows interface.
+func (rows rowsInMemory) GetRow(idx uint64) (chunk.Row, error) {
+	return rows[idx], nil
+}
+
+func (e *slidingWindowDistinct) AllocPartialResult() (pr PartialResult, memDelta int64) {
+	inner, memDelta := e.slidingAggFunc.AllocPartialResult()
+	p := &partialResult4SlidingWindowDistinct{
+		inner:  inner,
+		counts: make(map[string]int64),
+		// Decimal struct is the biggest type we will use.
+		buf: make([]byte, types.MyDecimalStructSize),
+	}
+	return PartialResult(p), DefPartialResult4SlidingWindowDistinctSize + memDelta
+}
+
+func (e *slidingWindowDistinct) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4SlidingWindowDistinct)(pr)
+	e.slidingAggFunc.ResetPartialResult(p.inner)
+	p.counts = make(map[string]int64)
+}
+
+// encodeRow encodes the value of the row, it returns false if the value is null.
+func (e *slidingWindowDistinct) encodeRow(sctx sessionctx.Context, p *partialResult4SlidingWindowDistinct, row chunk.Row) (string, bool, error) {
+	var err error
+	var isNull bool
+	p.encoded = p.encoded[:0]
+	if arg := e.args[0]; arg.GetType().EvalType() == types.ETString {
+		var val string
+		val, isNull, err = arg.EvalString(sctx, row)
+		if err != nil || isNull {
+			return "", false, err
+		}
+		collator := collate.GetCollator(arg.GetType().Collate)
+		p.encoded = codec.EncodeCompactBytes(p.encoded, collator.Key(val))
+	} else {
+		p.encoded, isNull, err = evalAndEncode(sctx, arg, row, p.buf, p.encoded)
+		if err != nil || isNull {
+			return "", false, err
+		}
+	}
+	return string(p.encoded), true, nil
+}
+
+// addRow adds the row into the frame, it returns true if the value of the row is
+// not in the frame yet.
+func (e *slidingWindowDistinct) addRow(sctx sessionctx.Context, p *partialResult4SlidingWindowDistinct, row chunk.Row) (bool, int64, error) {
+	key, ok, err := e.encodeRow(sctx, p, row)
+	if err != nil || !ok {
+		return false, 0, err
+	}
+	cnt := p.counts[key]
+	p.counts[key] = cnt + 1
+	if cnt > 0 {
+		return false, 0, nil
+	}
+	return true, int64(len(key)), nil
+}
+
+// removeRow removes the row from the frame, it returns true if the value of the row
+// is no longer in the frame.
+func (e *slidingWindowDistinct) removeRow(sctx sessionctx.Context, p *partialResult4SlidingWindowDistinct, row chunk.Row) (bool, error) {
+	key, ok, err := e.encodeRow(sctx, p, row)
+	if err != nil || !ok {
+		return false, err
+	}
+	cnt := p.counts[key]
+	if cnt > 1 {
+		p.counts[key] = cnt - 1
+		return false, nil
+	}
+	delete(p.counts, key)
+	return true, nil
+}
+
+func (e *slidingWindowDistinct) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*partialResult4SlidingWindowDistinct)(pr)
+	p.rows = p.rows[:0]
+	for _, row := range rowsInGroup {
+		added, delta, err := e.addRow(sctx, p, row)
+		if err != nil {
+			return memDelta, err
+		}
+		if added {
+			p.rows = append(p.rows, row)
+			memDelta += delta
+		}
+	}
+	delta, err := e.slidingAggFunc.UpdatePartialResult(sctx, p.rows, p.inner)
+	return memDelta + delta, err
+}
+
+func (e *slidingWindowDistinct) Slide(sctx sessionctx.Context, rows WindowRows, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error {
+	p := (*partialResult4SlidingWindowDistinct)(pr)
+	// The values are added before removed, so the values both entering and leaving
+	// the frame are not passed to the wrapped function.
+	var added []chunk.Row
+	for i := uint64(0); i < shiftEnd; i++ {
+		row, err := rows.GetRow(lastEnd + i)
+		if err != nil {
+			return err
+		}
+		ok, _, err := e.addRow(sctx, p, row)
+		if err != nil {
+			return err
+		}
+		if ok {
+			added = append(added, row)
+		}
+	}
+	p.rows = p.rows[:0]
+	for i := uint64(0); i < shiftStart; i++ {
+		row, err := rows.GetRow(lastStart + i)
+		if err != nil {
+			return err
+		}
+		ok, err := e.removeRow(sctx, p, row)
+		if err != nil {
+			return err
+		}
+		if ok {
+			p.rows = append(p.rows, row)
+		}
+	}
+	numRemoved := uint64(len(p.rows))
+	p.rows = append(p.rows, added...)
+	return e.slidingAggFunc.Slide(sctx, rowsInMemory(p.rows), 0, numRemoved, numRemoved, uint64(len(added)), p.inner)
+}
+
+func (e *slidingWindowDistinct) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4SlidingWindowDistinct)(pr)
+	return e.slidingAggFunc.AppendFinalResult2Chunk(sctx, p.inner, chk)
+}
+
+func (e *slidingWindowDistinct) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
+	return 0, nil
+}
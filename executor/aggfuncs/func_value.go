@@ -31,6 +31,8 @@ const (
 	DefPartialResult4LastValueSize = int64(unsafe.Sizeof(partialResult4LastValue{}))
 	// DefPartialResult4NthValueSize is the size of partialResult4NthValue
 	DefPartialResult4NthValueSize = int64(unsafe.Sizeof(partialResult4NthValue{}))
+	// DefPartialResult4NthValueWithNullTreatmentSize is the size of partialResult4NthValueWithNullTreatment
+	DefPartialResult4NthValueWithNullTreatmentSize = int64(unsafe.Sizeof(partialResult4NthValueWithNullTreatment{}))
 
 	// DefValue4IntSize is the size of value4Int
 	DefValue4IntSize = int64(unsafe.Sizeof(value4Int{}))
@@ -57,6 +59,8 @@ type valueEvaluator interface {
 	evaluateRow(ctx sessionctx.Context, expr expression.Expression, row chunk.Row) (memDelta int64, err error)
 	// appendResult appends the result to chunk.
 	appendResult(chk *chunk.Chunk, colIdx int)
+	// null returns whether the evaluated result is null.
+	null() bool
 }
 
 type value4Int struct {
@@ -77,6 +81,10 @@ func (v *value4Int) appendResult(chk *chunk.Chunk, colIdx int) {
 	}
 }
 
+func (v *value4Int) null() bool {
+	return v.isNull
+}
+
 type value4Float32 struct {
 	val    float32
 	isNull bool
@@ -97,6 +105,10 @@ func (v *value4Float32) appendResult(chk *chunk.Chunk, colIdx int) {
 	}
 }
 
+func (v *value4Float32) null() bool {
+	return v.isNull
+}
+
 type value4Decimal struct {
 	val    *types.MyDecimal
 	isNull bool
@@ -115,6 +127,10 @@ func (v *value4Decimal) appendResult(chk *chunk.Chunk, colIdx int) {
 	}
 }
 
+func (v *value4Decimal) null() bool {
+	return v.isNull
+}
+
 type value4Float64 struct {
 	val    float64
 	isNull bool
@@ -133,6 +149,10 @@ func (v *value4Float64) appendResult(chk *chunk.Chunk, colIdx int) {
 	}
 }
 
+func (v *value4Float64) null() bool {
+	return v.isNull
+}
+
 type value4String struct {
 	val    string
 	isNull bool
@@ -152,6 +172,10 @@ func (v *value4String) appendResult(chk *chunk.Chunk, colIdx int) {
 	}
 }
 
+func (v *value4String) null() bool {
+	return v.isNull
+}
+
 type value4Time struct {
 	val    types.Time
 	isNull bool
@@ -170,6 +194,10 @@ func (v *value4Time) appendResult(chk *chunk.Chunk, colIdx int) {
 	}
 }
 
+func (v *value4Time) null() bool {
+	return v.isNull
+}
+
 type value4Duration struct {
 	val    types.Duration
 	isNull bool
@@ -188,6 +216,10 @@ func (v *value4Duration) appendResult(chk *chunk.Chunk, colIdx int) {
 	}
 }
 
+func (v *value4Duration) null() bool {
+	return v.isNull
+}
+
 type value4JSON struct {
 	val    json.BinaryJSON
 	isNull bool
@@ -208,6 +240,10 @@ func (v *value4JSON) appendResult(chk *chunk.Chunk, colIdx int) {
 	}
 }
 
+func (v *value4JSON) null() bool {
+	return v.isNull
+}
+
 func buildValueEvaluator(tp *types.FieldType) (ve valueEvaluator, memDelta int64) {
 	evalType := tp.EvalType()
 	if tp.Tp == mysql.TypeBit {
@@ -376,3 +412,96 @@ func (v *nthValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialRe
 	}
 	return nil
 }
+
+// nthValueWithNullTreatment is used to evaluate `first_value`, `last_value` and `nth_value`
+// with `IGNORE NULLS` or `FROM LAST`. It keeps the rows of the frame and finds the
+// nth row when the result is appended.
+type nthValueWithNullTreatment struct {
+	baseAggFunc
+
+	tp         *types.FieldType
+	nth        uint64
+	fromLast   bool
+	ignoreNull bool
+}
+
+type partialResult4NthValueWithNullTreatment struct {
+	rows      partitionRows
+	evaluated bool
+	found     bool
+	evaluator valueEvaluator
+}
+
+func (v *nthValueWithNullTreatment) AllocPartialResult() (pr PartialResult, memDelta int64) {
+	ve, veMemDelta := buildValueEvaluator(v.tp)
+	p := &partialResult4NthValueWithNullTreatment{evaluator: ve}
+	return PartialResult(p), DefPartialResult4NthValueWithNullTreatmentSize + veMemDelta
+}
+
+func (v *nthValueWithNullTreatment) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4NthValueWithNullTreatment)(pr)
+	p.rows.reset()
+	p.evaluated = false
+	p.found = false
+}
+
+func (v *nthValueWithNullTreatment) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*partialResult4NthValueWithNullTreatment)(pr)
+	p.rows.append(rowsInGroup)
+	p.evaluated = false
+	return int64(len(rowsInGroup)) * DefRowSize, nil
+}
+
+func (v *nthValueWithNullTreatment) SetWindowRows(rows WindowRows, pr PartialResult) {
+	p := (*partialResult4NthValueWithNullTreatment)(pr)
+	p.rows.set(rows)
+	p.evaluated = false
+}
+
+// evaluate finds the nth row of the frame, the rows are counted from the last one
+// if fromLast is set, and the rows whose values are null are skipped if ignoreNull is set.
+func (v *nthValueWithNullTreatment) evaluate(sctx sessionctx.Context, p *partialResult4NthValueWithNullTreatment) (found bool, err error) {
+	if v.nth == 0 {
+		return false, nil
+	}
+	numRows := p.rows.numRows()
+	var seenRows uint64
+	for i := uint64(0); i < numRows; i++ {
+		idx := i
+		if v.fromLast {
+			idx = numRows - 1 - i
+		}
+		row, err := p.rows.getRow(idx)
+		if err != nil {
+			return false, err
+		}
+		if _, err = p.evaluator.evaluateRow(sctx, v.args[0], row); err != nil {
+			return false, err
+		}
+		if v.ignoreNull && p.evaluator.null() {
+			continue
+		}
+		seenRows++
+		if seenRows == v.nth {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (v *nthValueWithNullTreatment) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4NthValueWithNullTreatment)(pr)
+	if !p.evaluated {
+		found, err := v.evaluate(sctx, p)
+		if err != nil {
+			return err
+		}
+		p.found, p.evaluated = found, true
+	}
+	if !p.found {
+		chk.AppendNull(v.ordinal)
+	} else {
+		p.evaluator.appendResult(chk, v.ordinal)
+	}
+	return nil
+}
@@ -58,7 +58,7 @@ func (s *testSuite) testWindowFunc(c *C, p windowTest) {
 
 	desc, err := aggregation.NewAggFuncDesc(s.ctx, p.funcName, p.args, false)
 	c.Assert(err, IsNil)
-	finalFunc := aggfuncs.BuildWindowFunctions(s.ctx, desc, 0, p.orderByCols)
+	finalFunc := aggfuncs.BuildWindowFunctions(s.ctx, desc, 0, p.orderByCols, false, false)
 	finalPr, _ := finalFunc.AllocPartialResult()
 	resultChk := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, 1)
 
@@ -86,7 +86,7 @@ func (s *testSuite) testWindowAggMemFunc(c *C, p windowMemTest) {
 
 	desc, err := aggregation.NewAggFuncDesc(s.ctx, p.windowTest.funcName, p.windowTest.args, false)
 	c.Assert(err, IsNil)
-	finalFunc := aggfuncs.BuildWindowFunctions(s.ctx, desc, 0, p.windowTest.orderByCols)
+	finalFunc := aggfuncs.BuildWindowFunctions(s.ctx, desc, 0, p.windowTest.orderByCols, false, false)
 	finalPr, memDelta := finalFunc.AllocPartialResult()
 	c.Assert(memDelta, Equals, p.allocMemDelta)
 
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/sessionctx"
@@ -34,6 +35,7 @@ import (
 	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
@@ -93,6 +95,15 @@ type HashAggPartialWorker struct {
 	// chk stores the input data from child,
 	// and is reused by childExec and partial worker.
 	chk *chunk.Chunk
+
+	// inSpillMode points to HashAggExec.inSpillMode. When it is set, the partial
+	// worker stops creating new groups and spills the rows of unseen groups to disk.
+	inSpillMode *uint32
+	// spilledPartitions are the disk partitions owned by the final workers,
+	// they are nil if spilling is disabled.
+	spilledPartitions []*aggSpilledPartition
+	// spillChks buffer the rows to be spilled to each partition.
+	spillChks []*chunk.Chunk
 }
 
 // HashAggFinalWorker indicates the final workers of parallel hash agg execution,
@@ -108,6 +119,74 @@ type HashAggFinalWorker struct {
 	outputCh            chan *AfFinalResult
 	finalResultHolderCh chan *chunk.Chunk
 	groupKeys           [][]byte
+
+	// The following fields are only used when spilling is enabled.
+	// spilledPartition stores the input rows whose groups are owned by this
+	// final worker but were not aggregated by the partial workers.
+	spilledPartition *aggSpilledPartition
+	// inSpillMode indicates whether the final worker is in `spill mode`, it
+	// works like HashAggExec.inSpillMode but only for the spilled partition.
+	inSpillMode uint32
+	// intermDataConsumed is called after the worker has merged all the
+	// intermediate data of the partial workers.
+	intermDataConsumed func()
+	// intermDataDrained indicates whether all the intermediate data has been received.
+	intermDataDrained bool
+	partialAggFuncs   []aggfuncs.AggFunc
+	groupByItems      []expression.Expression
+	spillGroupKey     [][]byte
+}
+
+// aggSpilledPartition stores the spilled input rows of a HashAggExec partition.
+type aggSpilledPartition struct {
+	sync.Mutex
+	listInDisk *chunk.ListInDisk
+	// numOfSpilledChks indicates the number of chunks spilled before the
+	// current round of processing. In each round of processing, we need to
+	// re-fetch all the chunks spilled in the last one.
+	numOfSpilledChks int
+	// offsetOfSpilledChks indicates the offset of the chunk to be read from the disk.
+	offsetOfSpilledChks int
+}
+
+func newAggSpilledPartition(fieldTypes []*types.FieldType, diskTracker *disk.Tracker) *aggSpilledPartition {
+	p := &aggSpilledPartition{listInDisk: chunk.NewListInDisk(fieldTypes)}
+	p.listInDisk.GetDiskTracker().AttachTo(diskTracker)
+	return p
+}
+
+func (p *aggSpilledPartition) add(chk *chunk.Chunk) error {
+	p.Lock()
+	defer p.Unlock()
+	return p.listInDisk.Add(chk)
+}
+
+// nextRound starts a new round of processing, it returns false if no data
+// was spilled in the last round.
+func (p *aggSpilledPartition) nextRound() bool {
+	if p.numOfSpilledChks == p.listInDisk.NumChunks() {
+		return false
+	}
+	p.numOfSpilledChks = p.listInDisk.NumChunks()
+	return true
+}
+
+// next returns the next chunk spilled in the last round, nil is returned if
+// all of them have been fetched.
+func (p *aggSpilledPartition) next() (*chunk.Chunk, error) {
+	if p.offsetOfSpilledChks >= p.numOfSpilledChks {
+		return nil, nil
+	}
+	chk, err := p.listInDisk.GetChunk(p.offsetOfSpilledChks)
+	if err != nil {
+		return nil, err
+	}
+	p.offsetOfSpilledChks++
+	return chk, nil
+}
+
+func (p *aggSpilledPartition) close() error {
+	return p.listInDisk.Close()
 }
 
 // AfFinalResult indicates aggregation functions final result.
@@ -191,7 +270,23 @@ type HashAggExec struct {
 	prepared                bool
 	executed                bool
 
-	memTracker *memory.Tracker // track memory usage.
+	memTracker  *memory.Tracker // track memory usage.
+	diskTracker *disk.Tracker   // track disk usage.
+
+	// spilledData stores the input rows spilled by the unparallel execution.
+	// The HashAggExec may be set to `spill mode` multiple times, and all spilled
+	// data will be appended to it.
+	spilledData *aggSpilledPartition
+	// inSpillMode indicates whether HashAgg is in `spill mode`.
+	// When HashAgg is in `spill mode`, the size of the partial result maps is no
+	// longer growing and the rows of the groups not in memory are spilled to disk.
+	inSpillMode uint32
+	// tmpChkForSpill is the temp chunk for spilling.
+	tmpChkForSpill *chunk.Chunk
+	// spillAction save the Action for spilling.
+	spillAction *AggSpillDiskAction
+	// isChildDrained indicates whether the all data from child has been taken out.
+	isChildDrained bool
 
 	stats *HashAggRuntimeStats
 }
@@ -227,13 +322,22 @@ func (d *HashAggIntermData) getPartialResultBatch(sc *stmtctx.StatementContext,
 // Close implements the Executor Close interface.
 func (e *HashAggExec) Close() error {
 	if e.isUnparallelExec {
+		var firstErr error
 		e.childResult = nil
 		e.groupSet, _ = set.NewStringSetWithMemoryUsage()
 		e.partialResultMap = nil
 		if e.memTracker != nil {
 			e.memTracker.ReplaceBytesUsed(0)
 		}
-		return e.baseExecutor.Close()
+		if e.spilledData != nil {
+			firstErr = e.spilledData.close()
+			e.spilledData = nil
+		}
+		e.tmpChkForSpill = nil
+		if err := e.baseExecutor.Close(); firstErr == nil {
+			firstErr = err
+		}
+		return firstErr
 	}
 	if e.parallelExecInitialized {
 		// `Close` may be called after `Open` without calling `Next` in test.
@@ -262,6 +366,18 @@ func (e *HashAggExec) Close() error {
 		if e.memTracker != nil {
 			e.memTracker.ReplaceBytesUsed(0)
 		}
+		var firstErr error
+		for i := range e.finalWorkers {
+			if p := e.finalWorkers[i].spilledPartition; p != nil {
+				if err := p.close(); err != nil && firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+		if err := e.baseExecutor.Close(); firstErr == nil {
+			firstErr = err
+		}
+		return firstErr
 	}
 	return e.baseExecutor.Close()
 }
@@ -282,12 +398,22 @@ func (e *HashAggExec) Open(ctx context.Context) error {
 	if e.ctx.GetSessionVars().TrackAggregateMemoryUsage {
 		e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
 	}
+	e.diskTracker = nil
+	e.spillAction = nil
+	atomic.StoreUint32(&e.inSpillMode, 0)
+	if e.ctx.GetSessionVars().TrackAggregateMemoryUsage && config.GetGlobalConfig().OOMUseTmpStorage {
+		e.diskTracker = disk.NewTracker(e.id, -1)
+		e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+	}
 
 	if e.isUnparallelExec {
 		e.initForUnparallelExec()
-		return nil
+	} else {
+		e.initForParallelExec(e.ctx)
+	}
+	if e.diskTracker != nil {
+		e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(e.ActionSpill())
 	}
-	e.initForParallelExec(e.ctx)
 	return nil
 }
 
@@ -301,6 +427,13 @@ func (e *HashAggExec) initForUnparallelExec() {
 	e.groupKeyBuffer = make([][]byte, 0, 8)
 	e.childResult = newFirstChunk(e.children[0])
 	e.memTracker.Consume(e.childResult.MemoryUsage())
+
+	e.executed, e.isChildDrained = false, false
+	e.groupKeys, e.cursor4GroupKey = e.groupKeys[:0], 0
+	if e.diskTracker != nil {
+		e.spilledData = newAggSpilledPartition(retTypes(e.children[0]), e.diskTracker)
+		e.tmpChkForSpill = newFirstChunk(e.children[0])
+	}
 }
 
 func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
@@ -325,10 +458,39 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 	e.finalWorkers = make([]HashAggFinalWorker, finalConcurrency)
 	e.initRuntimeStats()
 
+	// When spilling is enabled, every worker tracks its memory usage separately,
+	// so that the memory can be released once the data it holds is not needed.
+	var spilledPartitions []*aggSpilledPartition
+	var intermDataConsumed func()
+	newWorkerMemTracker := func() *memory.Tracker { return e.memTracker }
+	if e.diskTracker != nil {
+		spilledPartitions = make([]*aggSpilledPartition, finalConcurrency)
+		for i := range spilledPartitions {
+			spilledPartitions[i] = newAggSpilledPartition(retTypes(e.children[0]), e.diskTracker)
+		}
+		// The partial results are useless after being merged by all the final workers,
+		// release them before processing the spilled data.
+		remainingFinalWorkers := int32(finalConcurrency)
+		intermDataConsumed = func() {
+			if atomic.AddInt32(&remainingFinalWorkers, -1) > 0 {
+				return
+			}
+			for i := range e.partialWorkers {
+				e.partialWorkers[i].partialResultsMap = nil
+				e.partialWorkers[i].memTracker.ReplaceBytesUsed(0)
+			}
+		}
+		newWorkerMemTracker = func() *memory.Tracker {
+			t := memory.NewTracker(memory.LabelForHashAggWorker, -1)
+			t.AttachTo(e.memTracker)
+			return t
+		}
+	}
+
 	// Init partial workers.
 	for i := 0; i < partialConcurrency; i++ {
 		w := HashAggPartialWorker{
-			baseHashAggWorker: newBaseHashAggWorker(e.ctx, e.finishCh, e.PartialAggFuncs, e.maxChunkSize, e.memTracker),
+			baseHashAggWorker: newBaseHashAggWorker(e.ctx, e.finishCh, e.PartialAggFuncs, e.maxChunkSize, newWorkerMemTracker()),
 			inputCh:           e.partialInputChs[i],
 			outputChs:         e.partialOutputChs,
 			giveBackCh:        e.inputCh,
@@ -337,15 +499,20 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 			groupByItems:      e.GroupByItems,
 			chk:               newFirstChunk(e.children[0]),
 			groupKey:          make([][]byte, 0, 8),
+			inSpillMode:       &e.inSpillMode,
+			spilledPartitions: spilledPartitions,
+		}
+		if spilledPartitions != nil {
+			w.spillChks = make([]*chunk.Chunk, finalConcurrency)
 		}
 		// There is a bucket in the empty partialResultsMap.
 		failpoint.Inject("ConsumeRandomPanic", nil)
-		e.memTracker.Consume(defBucketMemoryUsage * (1 << w.BInMap))
+		w.memTracker.Consume(defBucketMemoryUsage * (1 << w.BInMap))
 		if e.stats != nil {
 			w.stats = &AggWorkerStat{}
 			e.stats.PartialStats = append(e.stats.PartialStats, w.stats)
 		}
-		e.memTracker.Consume(w.chk.MemoryUsage())
+		w.memTracker.Consume(w.chk.MemoryUsage())
 		e.partialWorkers[i] = w
 		input := &HashAggInput{
 			chk:        newFirstChunk(e.children[0]),
@@ -359,7 +526,7 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 	for i := 0; i < finalConcurrency; i++ {
 		groupSet, setSize := set.NewStringSetWithMemoryUsage()
 		w := HashAggFinalWorker{
-			baseHashAggWorker:   newBaseHashAggWorker(e.ctx, e.finishCh, e.FinalAggFuncs, e.maxChunkSize, e.memTracker),
+			baseHashAggWorker:   newBaseHashAggWorker(e.ctx, e.finishCh, e.FinalAggFuncs, e.maxChunkSize, newWorkerMemTracker()),
 			partialResultMap:    make(aggPartialResultMapper),
 			groupSet:            groupSet,
 			inputCh:             e.partialOutputChs[i],
@@ -368,9 +535,15 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 			rowBuffer:           make([]types.Datum, 0, e.Schema().Len()),
 			mutableRow:          chunk.MutRowFromTypes(retTypes(e)),
 			groupKeys:           make([][]byte, 0, 8),
+			intermDataConsumed:  intermDataConsumed,
+			partialAggFuncs:     e.PartialAggFuncs,
+			groupByItems:        e.GroupByItems,
+		}
+		if spilledPartitions != nil {
+			w.spilledPartition = spilledPartitions[i]
 		}
 		// There is a bucket in the empty partialResultsMap.
-		e.memTracker.Consume(defBucketMemoryUsage*(1<<w.BInMap) + setSize)
+		w.memTracker.Consume(defBucketMemoryUsage*(1<<w.BInMap) + setSize)
 		if e.stats != nil {
 			w.stats = &AggWorkerStat{}
 			e.stats.FinalStats = append(e.stats.FinalStats, w.stats)
@@ -412,6 +585,9 @@ func (w *HashAggPartialWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitG
 		if r := recover(); r != nil {
 			recoveryHashAgg(w.globalOutputCh, r)
 		}
+		if err := w.flushSpillChks(); err != nil {
+			w.globalOutputCh <- &AfFinalResult{err: err}
+		}
 		if needShuffle {
 			w.shuffleIntermData(sc, finalConcurrency)
 		}
@@ -463,13 +639,27 @@ func (w *HashAggPartialWorker) updatePartialResult(ctx sessionctx.Context, sc *s
 		return err
 	}
 
-	partialResults := w.getPartialResult(sc, w.groupKey, w.partialResultsMap)
+	groupKey, sel := w.groupKey, []int(nil)
+	if w.spilledPartitions != nil && atomic.LoadUint32(w.inSpillMode) == 1 {
+		groupKey, sel, err = w.spillUnprocessedRows(chk)
+		if err != nil {
+			return err
+		}
+	}
+	partialResults := w.getPartialResult(sc, groupKey, w.partialResultsMap)
 	numRows := chk.NumRows()
+	if sel != nil {
+		numRows = len(sel)
+	}
 	rows := make([]chunk.Row, 1)
 	allMemDelta := int64(0)
 	for i := 0; i < numRows; i++ {
 		for j, af := range w.aggFuncs {
-			rows[0] = chk.GetRow(i)
+			if sel != nil {
+				rows[0] = chk.GetRow(sel[i])
+			} else {
+				rows[0] = chk.GetRow(i)
+			}
 			memDelta, err := af.UpdatePartialResult(ctx, rows, partialResults[i][j])
 			if err != nil {
 				return err
@@ -481,6 +671,51 @@ func (w *HashAggPartialWorker) updatePartialResult(ctx sessionctx.Context, sc *s
 	return nil
 }
 
+// spillUnprocessedRows spills the rows whose groups are not in the partial result map to
+// the partitions of the final workers owning these groups. It returns the group keys and
+// the indices of the rows to be processed in memory.
+func (w *HashAggPartialWorker) spillUnprocessedRows(chk *chunk.Chunk) ([][]byte, []int, error) {
+	numRows := chk.NumRows()
+	sel := make([]int, 0, numRows)
+	for i := 0; i < numRows; i++ {
+		if _, ok := w.partialResultsMap[string(w.groupKey[i])]; ok {
+			// Move the group keys of the processed rows ahead, and swap rather than overwrite
+			// to keep the buffers of w.groupKey reusable.
+			w.groupKey[len(sel)], w.groupKey[i] = w.groupKey[i], w.groupKey[len(sel)]
+			sel = append(sel, i)
+			continue
+		}
+		// Keep consistent with shuffleIntermData.
+		partIdx := int(murmur3.Sum32(w.groupKey[i])) % len(w.spilledPartitions)
+		if w.spillChks[partIdx] == nil {
+			w.spillChks[partIdx] = chunk.Renew(chk, w.maxChunkSize)
+		}
+		spillChk := w.spillChks[partIdx]
+		spillChk.AppendRow(chk.GetRow(i))
+		if spillChk.IsFull() {
+			if err := w.spilledPartitions[partIdx].add(spillChk); err != nil {
+				return nil, nil, err
+			}
+			spillChk.Reset()
+		}
+	}
+	return w.groupKey[:len(sel)], sel, nil
+}
+
+// flushSpillChks spills the rows buffered in spillChks to disk.
+func (w *HashAggPartialWorker) flushSpillChks() error {
+	for i, chk := range w.spillChks {
+		if chk == nil || chk.NumRows() == 0 {
+			continue
+		}
+		if err := w.spilledPartitions[i].add(chk); err != nil {
+			return err
+		}
+		chk.Reset()
+	}
+	return nil
+}
+
 // shuffleIntermData shuffles the intermediate data of partial workers to corresponded final workers.
 // We only support parallel execution for single-machine, so process of encode and decode can be skipped.
 func (w *HashAggPartialWorker) shuffleIntermData(sc *stmtctx.StatementContext, finalConcurrency int) {
@@ -575,6 +810,7 @@ func (w *HashAggFinalWorker) getPartialInput() (input *HashAggIntermData, ok boo
 		return nil, false
 	case input, ok = <-w.inputCh:
 		if !ok {
+			w.intermDataDrained = true
 			return nil, false
 		}
 	}
@@ -637,14 +873,16 @@ func (w *HashAggFinalWorker) consumeIntermData(sctx sessionctx.Context) (err err
 	}
 }
 
-func (w *HashAggFinalWorker) getFinalResult(sctx sessionctx.Context) {
+// getFinalResult sends the final results of the groups in memory to the main thread,
+// it returns true if the execution is finished in advance.
+func (w *HashAggFinalWorker) getFinalResult(sctx sessionctx.Context) (finished bool) {
 	waitStart := time.Now()
 	result, finished := w.receiveFinalResultHolder()
 	if w.stats != nil {
 		w.stats.WaitTime += int64(time.Since(waitStart))
 	}
 	if finished {
-		return
+		return true
 	}
 	execStart := time.Now()
 	memSize := getGroupKeyMemUsage(w.groupKeys)
@@ -668,7 +906,7 @@ func (w *HashAggFinalWorker) getFinalResult(sctx sessionctx.Context) {
 			w.outputCh <- &AfFinalResult{chk: result, giveBackCh: w.finalResultHolderCh}
 			result, finished = w.receiveFinalResultHolder()
 			if finished {
-				return
+				return true
 			}
 		}
 	}
@@ -676,6 +914,7 @@ func (w *HashAggFinalWorker) getFinalResult(sctx sessionctx.Context) {
 	if w.stats != nil {
 		w.stats.ExecTime += int64(time.Since(execStart))
 	}
+	return false
 }
 
 func (w *HashAggFinalWorker) receiveFinalResultHolder() (*chunk.Chunk, bool) {
@@ -700,8 +939,131 @@ func (w *HashAggFinalWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitGro
 	}()
 	if err := w.consumeIntermData(ctx); err != nil {
 		w.outputCh <- &AfFinalResult{err: err}
+		if w.spilledPartition != nil {
+			return
+		}
+	}
+	if w.spilledPartition == nil {
+		w.getFinalResult(ctx)
+		return
+	}
+	if w.intermDataDrained {
+		w.intermDataConsumed()
+	}
+	if err := w.processSpilledData(ctx); err != nil {
+		w.outputCh <- &AfFinalResult{err: err}
+	}
+}
+
+// processSpilledData aggregates the rows spilled to the partition of the final worker
+// round by round. In each round, the spilled rows of the groups in memory are merged into
+// them, and the rows of the other groups are spilled again if the worker is in spill mode,
+// then the results of the groups in memory are sent to the main thread.
+func (w *HashAggFinalWorker) processSpilledData(sctx sessionctx.Context) error {
+	p := w.spilledPartition
+	for round := 0; ; round++ {
+		hasSpilledData := p.nextRound()
+		if !hasSpilledData && round > 0 {
+			return nil
+		}
+		for hasSpilledData {
+			chk, err := p.next()
+			if err != nil {
+				return err
+			}
+			if chk == nil {
+				break
+			}
+			if err = w.mergeSpilledRows(sctx, chk); err != nil {
+				return err
+			}
+		}
+		if finished := w.getFinalResult(sctx); finished || !hasSpilledData {
+			return nil
+		}
+		w.resetForNextRound()
+	}
+}
+
+// mergeSpilledRows aggregates the spilled rows by the partial aggregate functions and
+// merges the partial results into the final results.
+func (w *HashAggFinalWorker) mergeSpilledRows(sctx sessionctx.Context, chk *chunk.Chunk) (err error) {
+	memSize := getGroupKeyMemUsage(w.spillGroupKey)
+	w.spillGroupKey, err = getGroupKey(sctx, chk, w.spillGroupKey, w.groupByItems)
+	w.memTracker.Consume(getGroupKeyMemUsage(w.spillGroupKey) - memSize)
+	if err != nil {
+		return err
+	}
+	inSpillMode := atomic.LoadUint32(&w.inSpillMode) == 1
+	var spillChk *chunk.Chunk
+	partialResultMap := make(aggPartialResultMapper)
+	rows := make([]chunk.Row, 1)
+	allMemDelta := int64(0)
+	for i := 0; i < chk.NumRows(); i++ {
+		groupKey := string(w.spillGroupKey[i])
+		if !w.groupSet.Exist(groupKey) {
+			// Make sure at least one group is processed in each round.
+			if inSpillMode && len(w.groupSet.StringSet) > 0 {
+				if spillChk == nil {
+					spillChk = chunk.Renew(chk, w.maxChunkSize)
+				}
+				spillChk.AppendRow(chk.GetRow(i))
+				continue
+			}
+			allMemDelta += w.groupSet.Insert(groupKey)
+		}
+		partialResults, ok := partialResultMap[groupKey]
+		if !ok {
+			partialResults = make([]aggfuncs.PartialResult, 0, len(w.partialAggFuncs))
+			for _, af := range w.partialAggFuncs {
+				pr, _ := af.AllocPartialResult()
+				partialResults = append(partialResults, pr)
+			}
+			partialResultMap[groupKey] = partialResults
+		}
+		rows[0] = chk.GetRow(i)
+		for j, af := range w.partialAggFuncs {
+			if _, err = af.UpdatePartialResult(sctx, rows, partialResults[j]); err != nil {
+				return err
+			}
+		}
+	}
+	if spillChk != nil {
+		if err = w.spilledPartition.add(spillChk); err != nil {
+			return err
+		}
 	}
-	w.getFinalResult(ctx)
+
+	memSize = getGroupKeyMemUsage(w.groupKeys)
+	w.groupKeys = w.groupKeys[:0]
+	for groupKey := range partialResultMap {
+		w.groupKeys = append(w.groupKeys, []byte(groupKey))
+	}
+	w.memTracker.Consume(getGroupKeyMemUsage(w.groupKeys) - memSize)
+	finalPartialResults := w.getPartialResult(sctx.GetSessionVars().StmtCtx, w.groupKeys, w.partialResultMap)
+	for i, groupKey := range w.groupKeys {
+		prs := partialResultMap[string(groupKey)]
+		for j, af := range w.aggFuncs {
+			memDelta, err := af.MergePartialResult(sctx, prs[j], finalPartialResults[i][j])
+			if err != nil {
+				return err
+			}
+			allMemDelta += memDelta
+		}
+	}
+	w.memTracker.Consume(allMemDelta)
+	return nil
+}
+
+// resetForNextRound releases the groups whose results have been sent.
+func (w *HashAggFinalWorker) resetForNextRound() {
+	var setSize int64
+	w.groupSet, setSize = set.NewStringSetWithMemoryUsage()
+	w.partialResultMap = make(aggPartialResultMapper)
+	w.BInMap = 0
+	w.memTracker.ReplaceBytesUsed(defBucketMemoryUsage*(1<<w.BInMap) + setSize +
+		getGroupKeyMemUsage(w.groupKeys) + getGroupKeyMemUsage(w.spillGroupKey))
+	atomic.StoreUint32(&w.inSpillMode, 0)
 }
 
 // Next implements the Executor Next interface.
@@ -814,6 +1176,9 @@ func (e *HashAggExec) prepare4ParallelExec(ctx context.Context) {
 // 1. input reader reads data from child executor and send them to partial workers.
 // 2. partial worker receives the input data, updates the partial results, and shuffle the partial results to the final workers.
 // 3. final worker receives partial results from all the partial workers, evaluates the final results and sends the final results to the main thread.
+// If the HashAggExec is set to `spill mode` by AggSpillDiskAction, the partial workers stop creating new groups,
+// and spill the rows of the other groups to the disk partitions of the final workers owning these groups.
+// After merging the partial results, every final worker aggregates the rows in its partition round by round.
 func (e *HashAggExec) parallelExec(ctx context.Context, chk *chunk.Chunk) error {
 	if !e.prepared {
 		e.prepare4ParallelExec(ctx)
@@ -852,11 +1217,36 @@ func (e *HashAggExec) parallelExec(ctx context.Context, chk *chunk.Chunk) error
 }
 
 // unparallelExec executes hash aggregation algorithm in single thread.
+// If the HashAggExec is set to `spill mode` during the execution, the rows of the
+// groups not in memory are spilled to disk, they are aggregated in the next round
+// after the results of the groups in memory have been returned.
 func (e *HashAggExec) unparallelExec(ctx context.Context, chk *chunk.Chunk) error {
-	// In this stage we consider all data from src as a single group.
-	if !e.prepared {
-		err := e.execute(ctx)
-		if err != nil {
+	chk.Reset()
+	for {
+		if e.prepared {
+			// Since we return e.maxChunkSize rows every time, so we should not traverse
+			// `groupSet` because of its randomness.
+			for ; e.cursor4GroupKey < len(e.groupKeys); e.cursor4GroupKey++ {
+				partialResults := e.getPartialResults(e.groupKeys[e.cursor4GroupKey])
+				if len(e.PartialAggFuncs) == 0 {
+					chk.SetNumVirtualRows(chk.NumRows() + 1)
+				}
+				for i, af := range e.PartialAggFuncs {
+					if err := af.AppendFinalResult2Chunk(e.ctx, partialResults[i], chk); err != nil {
+						return err
+					}
+				}
+				if chk.IsFull() {
+					e.cursor4GroupKey++
+					return nil
+				}
+			}
+			e.resetSpillMode()
+		}
+		if e.executed {
+			return nil
+		}
+		if err := e.execute(ctx); err != nil {
 			return err
 		}
 		if (len(e.groupSet.StringSet) == 0) && len(e.GroupByItems) == 0 {
@@ -869,33 +1259,34 @@ func (e *HashAggExec) unparallelExec(ctx context.Context, chk *chunk.Chunk) erro
 		}
 		e.prepared = true
 	}
-	chk.Reset()
+}
 
-	// Since we return e.maxChunkSize rows every time, so we should not traverse
-	// `groupSet` because of its randomness.
-	for ; e.cursor4GroupKey < len(e.groupKeys); e.cursor4GroupKey++ {
-		partialResults := e.getPartialResults(e.groupKeys[e.cursor4GroupKey])
-		if len(e.PartialAggFuncs) == 0 {
-			chk.SetNumVirtualRows(chk.NumRows() + 1)
-		}
-		for i, af := range e.PartialAggFuncs {
-			if err := af.AppendFinalResult2Chunk(e.ctx, partialResults[i], chk); err != nil {
-				return err
-			}
-		}
-		if chk.IsFull() {
-			e.cursor4GroupKey++
-			return nil
-		}
-	}
-	return nil
+// resetSpillMode releases the groups whose results have been returned, and prepares
+// for aggregating the rows spilled in the last round.
+func (e *HashAggExec) resetSpillMode() {
+	e.cursor4GroupKey, e.groupKeys = 0, e.groupKeys[:0]
+	var setSize int64
+	e.groupSet, setSize = set.NewStringSetWithMemoryUsage()
+	e.partialResultMap = make(aggPartialResultMapper)
+	e.bInMap = 0
+	e.prepared = false
+	// No data is spilled again, all data have been processed.
+	e.executed = e.spilledData == nil || !e.spilledData.nextRound()
+	e.memTracker.ReplaceBytesUsed(defBucketMemoryUsage*(1<<e.bInMap) + setSize + e.childResult.MemoryUsage())
+	atomic.StoreUint32(&e.inSpillMode, 0)
 }
 
 // execute fetches Chunks from src and update each aggregate function for each row in Chunk.
 func (e *HashAggExec) execute(ctx context.Context) (err error) {
+	defer func() {
+		if e.tmpChkForSpill != nil && e.tmpChkForSpill.NumRows() > 0 && err == nil {
+			err = e.spilledData.add(e.tmpChkForSpill)
+			e.tmpChkForSpill.Reset()
+		}
+	}()
 	for {
 		mSize := e.childResult.MemoryUsage()
-		err := Next(ctx, e.children[0], e.childResult)
+		err := e.getNextChunk(ctx)
 		failpoint.Inject("ConsumeRandomPanic", nil)
 		e.memTracker.Consume(e.childResult.MemoryUsage() - mSize)
 		if err != nil {
@@ -919,9 +1310,17 @@ func (e *HashAggExec) execute(ctx context.Context) (err error) {
 		}
 
 		allMemDelta := int64(0)
+		inSpillMode := e.spilledData != nil && atomic.LoadUint32(&e.inSpillMode) == 1
 		for j := 0; j < e.childResult.NumRows(); j++ {
 			groupKey := string(e.groupKeyBuffer[j]) // do memory copy here, because e.groupKeyBuffer may be reused.
 			if !e.groupSet.Exist(groupKey) {
+				// Make sure at least one group is processed in each round.
+				if inSpillMode && len(e.groupSet.StringSet) > 0 {
+					if err = e.spillUnprocessedRow(e.childResult.GetRow(j)); err != nil {
+						return err
+					}
+					continue
+				}
 				allMemDelta += e.groupSet.Insert(groupKey)
 				e.groupKeys = append(e.groupKeys, groupKey)
 			}
@@ -939,6 +1338,40 @@ func (e *HashAggExec) execute(ctx context.Context) (err error) {
 	}
 }
 
+// getNextChunk fetches the next chunk from the child executor, the chunks spilled in
+// the last round are fetched after the child executor is drained.
+func (e *HashAggExec) getNextChunk(ctx context.Context) error {
+	e.childResult.Reset()
+	if !e.isChildDrained {
+		if err := Next(ctx, e.children[0], e.childResult); err != nil {
+			return err
+		}
+		if e.childResult.NumRows() > 0 {
+			return nil
+		}
+		e.isChildDrained = true
+	}
+	if e.spilledData == nil {
+		return nil
+	}
+	chk, err := e.spilledData.next()
+	if err != nil || chk == nil {
+		return err
+	}
+	e.childResult.SwapColumns(chk)
+	return nil
+}
+
+func (e *HashAggExec) spillUnprocessedRow(row chunk.Row) error {
+	e.tmpChkForSpill.AppendRow(row)
+	if !e.tmpChkForSpill.IsFull() {
+		return nil
+	}
+	err := e.spilledData.add(e.tmpChkForSpill)
+	e.tmpChkForSpill.Reset()
+	return err
+}
+
 func (e *HashAggExec) getPartialResults(groupKey string) []aggfuncs.PartialResult {
 	partialResults, ok := e.partialResultMap[groupKey]
 	allMemDelta := int64(0)
@@ -962,6 +1395,64 @@ func (e *HashAggExec) getPartialResults(groupKey string) []aggfuncs.PartialResul
 	return partialResults
 }
 
+// maxSpillTimes indicates how many times the HashAggExec can be set to `spill mode`.
+const maxSpillTimes = 10
+
+// ActionSpill returns a AggSpillDiskAction for spilling intermediate data for HashAggExec.
+func (e *HashAggExec) ActionSpill() *AggSpillDiskAction {
+	if e.spillAction == nil {
+		e.spillAction = &AggSpillDiskAction{
+			e: e,
+		}
+	}
+	return e.spillAction
+}
+
+// AggSpillDiskAction implements memory.ActionOnExceed for HashAggExec.
+// If the memory quota of a query is exceeded, AggSpillDiskAction.Action is
+// triggered and the HashAggExec is set to `spill mode`.
+type AggSpillDiskAction struct {
+	memory.BaseOOMAction
+	e          *HashAggExec
+	spillTimes uint32
+}
+
+// Action set HashAggExec spill mode.
+func (a *AggSpillDiskAction) Action(t *memory.Tracker) {
+	// Guarantee that processed data is at least 20% of the threshold, to avoid spilling too frequently.
+	if a.spillTimes < maxSpillTimes && a.e.memTracker.BytesConsumed() >= t.GetBytesLimit()/5 && a.e.setSpillMode() {
+		a.spillTimes++
+		logutil.BgLogger().Info("memory exceeds quota, set aggregate mode to spill-mode",
+			zap.Uint32("spillTimes", a.spillTimes),
+			zap.Int64("consumed", t.BytesConsumed()),
+			zap.Int64("quota", t.GetBytesLimit()))
+		return
+	}
+	if fallback := a.GetFallback(); fallback != nil {
+		fallback.Action(t)
+	}
+}
+
+// GetPriority get the priority of the Action
+func (a *AggSpillDiskAction) GetPriority() int64 {
+	return memory.DefSpillPriority
+}
+
+// SetLogHook sets the hook, it does nothing just to form the memory.ActionOnExceed interface.
+func (a *AggSpillDiskAction) SetLogHook(hook func(uint64)) {}
+
+// setSpillMode sets the HashAggExec and its final workers to `spill mode`,
+// it returns false if all of them are in `spill mode` already.
+func (e *HashAggExec) setSpillMode() bool {
+	changed := atomic.CompareAndSwapUint32(&e.inSpillMode, 0, 1)
+	for i := range e.finalWorkers {
+		if atomic.CompareAndSwapUint32(&e.finalWorkers[i].inSpillMode, 0, 1) {
+			changed = true
+		}
+	}
+	return changed
+}
+
 func (e *HashAggExec) initRuntimeStats() {
 	if e.runtimeStats != nil && e.stats == nil {
 		stats := &HashAggRuntimeStats{
@@ -14,6 +14,7 @@
 package executor_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math"
@@ -27,6 +28,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/executor"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
@@ -1413,6 +1415,64 @@ func (s *testSerialSuite) TestRandomPanicAggConsume(c *C) {
 	}
 }
 
+func (s *testSerialSuite) TestAggInDisk(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+	})
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("set @@tidb_max_chunk_size=32")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	var buf bytes.Buffer
+	buf.WriteString("insert into t values ")
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(fmt.Sprintf("(%v, %v)", i%300, i))
+	}
+	tk.MustExec(buf.String())
+
+	sqls := []string{
+		"select /*+ HASH_AGG() */ a, count(*), sum(b), avg(b), max(b) from t group by a",
+		"select /*+ HASH_AGG() */ a, count(distinct b), group_concat(b order by b) from t group by a",
+		"select /*+ HASH_AGG() */ count(*), sum(b) from t",
+		"select /*+ HASH_AGG() */ count(*), sum(b) from t where a < 0",
+		"select /*+ HASH_AGG() */ a from t group by a",
+	}
+	concurrencies := [][2]int{{1, 1}, {5, 5}, {1, 4}}
+	for _, sql := range sqls {
+		tk.MustExec("set @@tidb_mem_quota_query=default")
+		expected := tk.MustQuery(sql).Sort().Rows()
+		tk.MustExec("set @@tidb_mem_quota_query=1")
+		for _, con := range concurrencies {
+			tk.MustExec(fmt.Sprintf("set @@tidb_hashagg_final_concurrency=%v", con[0]))
+			tk.MustExec(fmt.Sprintf("set @@tidb_hashagg_partial_concurrency=%v", con[1]))
+			tk.MustQuery(sql).Sort().Check(expected)
+		}
+	}
+
+	for _, con := range concurrencies {
+		tk.MustExec(fmt.Sprintf("set @@tidb_hashagg_final_concurrency=%v", con[0]))
+		tk.MustExec(fmt.Sprintf("set @@tidb_hashagg_partial_concurrency=%v", con[1]))
+		rows := tk.MustQuery("explain analyze select /*+ HASH_AGG() */ a, sum(b) from t group by a").Rows()
+		for _, row := range rows {
+			length := len(row)
+			id := fmt.Sprintf("%v", row[0])
+			disk := fmt.Sprintf("%v", row[length-1])
+			if strings.Contains(id, "HashAgg") && row[3] == "root" {
+				c.Assert(strings.Contains(disk, "0 Bytes"), IsFalse)
+				c.Assert(strings.Contains(disk, "MB") ||
+					strings.Contains(disk, "KB") ||
+					strings.Contains(disk, "Bytes"), IsTrue)
+			}
+		}
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.BytesConsumed(), Equals, int64(0))
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Greater, int64(0))
+	}
+}
+
 func (s *testSuiteAgg) TestIssue23277(c *C) {
 	tk := testkit.NewTestKitWithInit(c, s.store)
 	tk.MustExec("use test;")
@@ -0,0 +1,129 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/plugin"
+	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// isNativeAuthPlugin returns whether the authentication plugin is mysql_native_password.
+func isNativeAuthPlugin(authPlugin string) bool {
+	return authPlugin == "" || strings.EqualFold(authPlugin, mysql.AuthNativePassword)
+}
+
+// normalizeAuthPlugin returns the canonical name of the built-in authentication plugins, and the
+// default_authentication_plugin if the plugin is not specified.
+func normalizeAuthPlugin(authPlugin string) string {
+	switch {
+	case authPlugin == "":
+		return variable.GetSysVar(variable.DefaultAuthPlugin).Value
+	case isNativeAuthPlugin(authPlugin):
+		return mysql.AuthNativePassword
+	case strings.EqualFold(authPlugin, mysql.AuthCachingSha2Password):
+		return mysql.AuthCachingSha2Password
+	}
+	return authPlugin
+}
+
+// encodeAuthString encodes the authentication string stored in mysql.user for the authentication plugin.
+func encodeAuthString(authPlugin string, spec *ast.UserSpec) (string, error) {
+	if isNativeAuthPlugin(authPlugin) {
+		pwd, ok := spec.EncodedPassword()
+		if !ok {
+			return "", errors.Trace(ErrPasswordFormat)
+		}
+		return pwd, nil
+	}
+	if authPlugin == mysql.AuthCachingSha2Password {
+		opt := spec.AuthOpt
+		if isEmptyAuthOption(opt) {
+			return "", nil
+		}
+		if opt.ByAuthString {
+			return privileges.NewSha2Password(opt.AuthString)
+		}
+		if !privileges.IsValidSha2Password(opt.HashString) {
+			return "", errors.Trace(ErrPasswordFormat)
+		}
+		return opt.HashString, nil
+	}
+	authManifest := plugin.GetAuthentication(authPlugin)
+	if authManifest == nil {
+		return "", ErrPluginIsNotLoaded.GenWithStackByArgs(authPlugin)
+	}
+	opt := spec.AuthOpt
+	if isEmptyAuthOption(opt) {
+		return "", nil
+	}
+	if opt.ByAuthString {
+		if authManifest.GenerateAuthenticationString == nil {
+			return "", errors.Trace(ErrPasswordFormat)
+		}
+		return authManifest.GenerateAuthenticationString(opt.AuthString)
+	}
+	if authManifest.ValidateAuthenticationString == nil || !authManifest.ValidateAuthenticationString(opt.HashString) {
+		return "", errors.Trace(ErrPasswordFormat)
+	}
+	return opt.HashString, nil
+}
+
+// encodePasswordForUser encodes the password for `SET PASSWORD` with the authentication plugin of the user.
+func encodePasswordForUser(authPlugin, password string) (string, error) {
+	if isNativeAuthPlugin(authPlugin) {
+		return auth.EncodePassword(password), nil
+	}
+	return encodeAuthString(authPlugin, &ast.UserSpec{AuthOpt: &ast.AuthOption{ByAuthString: true, AuthString: password}})
+}
+
+// userAuthPlugin gets the authentication plugin of the user from mysql.user.
+func userAuthPlugin(ctx sessionctx.Context, name string, host string) (string, error) {
+	exec := ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.TODO(), `SELECT plugin FROM %n.%n WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, name, host)
+	if err != nil {
+		return "", err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(context.TODO(), stmt)
+	if err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return mysql.AuthNativePassword, nil
+	}
+	return rows[0].GetString(0), nil
+}
+
+// isEmptyAuthOption returns whether no password is specified, e.g. `IDENTIFIED WITH <plugin>`.
+func isEmptyAuthOption(opt *ast.AuthOption) bool {
+	return opt == nil || (!opt.ByAuthString && opt.HashString == "")
+}
+
+// authPluginOfUserSpec returns the authentication plugin specified by `IDENTIFIED WITH`,
+// an empty name means the plugin is not specified.
+func authPluginOfUserSpec(spec *ast.UserSpec) string {
+	if spec.AuthOpt == nil {
+		return ""
+	}
+	return spec.AuthOpt.AuthPlugin
+}
@@ -134,6 +134,8 @@ func (e *BatchPointGetExec) Open(context.Context) error {
 	// Avoid network requests for the temporary table.
 	if e.tblInfo.TempTableType == model.TempTableGlobal {
 		snapshot = globalTemporaryTableSnapshot{snapshot}
+	} else if e.tblInfo.TempTableType == model.TempTableLocal {
+		snapshot = localTemporaryTableSnapshot{snapshot, e.ctx.GetSessionVars().TemporaryTableData}
 	}
 	var batchGetter kv.BatchGetter = snapshot
 	if txn.Valid() {
@@ -161,6 +163,33 @@ func (s globalTemporaryTableSnapshot) BatchGet(ctx context.Context, keys []kv.Ke
 	return make(map[string][]byte), nil
 }
 
+// The data of local temporary table is kept in the session instead of TiKV.
+// localTemporaryTableSnapshot inherits kv.Snapshot and override the BatchGet methods to read from the session.
+type localTemporaryTableSnapshot struct {
+	kv.Snapshot
+	data kv.MemBuffer
+}
+
+func (s localTemporaryTableSnapshot) BatchGet(ctx context.Context, keys []kv.Key) (map[string][]byte, error) {
+	values := make(map[string][]byte)
+	if s.data == nil {
+		return values, nil
+	}
+	for _, key := range keys {
+		val, err := s.data.Get(ctx, key)
+		if kv.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(val) > 0 {
+			values[string(key)] = val
+		}
+	}
+	return values, nil
+}
+
 // Close implements the Executor interface.
 func (e *BatchPointGetExec) Close() error {
 	if e.runtimeStats != nil && e.snapshot != nil {
@@ -276,7 +305,7 @@ func (e *BatchPointGetExec) initialize(ctx context.Context) error {
 		}
 
 		e.handles = make([]kv.Handle, 0, len(toFetchIndexKeys))
-		if e.tblInfo.Partition != nil {
+		if e.tblInfo.GetPartitionInfo() != nil {
 			e.physIDs = make([]int64, 0, len(toFetchIndexKeys))
 		}
 		for _, key := range toFetchIndexKeys {
@@ -292,7 +321,7 @@ func (e *BatchPointGetExec) initialize(ctx context.Context) error {
 			if rc {
 				indexKeys = append(indexKeys, key)
 			}
-			if e.tblInfo.Partition != nil {
+			if e.tblInfo.GetPartitionInfo() != nil {
 				pid := tablecodec.DecodeTableID(key)
 				e.physIDs = append(e.physIDs, pid)
 				if e.lock {
@@ -425,10 +425,15 @@ func (gs *tidbGlueSession) CreateTable(ctx context.Context, dbName model.CIStr,
 
 	// Clone() does not clone partitions yet :(
 	table = table.Clone()
-	if table.Partition != nil {
-		newPartition := *table.Partition
-		newPartition.Definitions = append([]model.PartitionDefinition{}, table.Partition.Definitions...)
+	if pi := table.GetPartitionInfo(); pi != nil {
+		newPartition := *pi
+		newPartition.Definitions = append([]model.PartitionDefinition{}, pi.Definitions...)
+		// The partitions which are being reorganized aren't restored.
+		newPartition.AddingDefinitions, newPartition.DroppingDefinitions, newPartition.States = nil, nil, nil
 		table.Partition = &newPartition
+	} else {
+		// The table is being partitioned, it's restored without the pending partitions.
+		table.Partition = nil
 	}
 
 	return d.CreateTableWithInfo(gs.se, dbName, table, ddl.OnExistIgnore, true)
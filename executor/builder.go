@@ -16,6 +16,7 @@ package executor
 import (
 	"bytes"
 	"context"
+	"math/rand"
 	"sort"
 	"strings"
 	"sync"
@@ -203,6 +204,8 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildMemTable(v)
 	case *plannercore.PhysicalTableDual:
 		return b.buildTableDual(v)
+	case *plannercore.PhysicalJSONTable:
+		return b.buildJSONTable(v)
 	case *plannercore.PhysicalApply:
 		return b.buildApply(v)
 	case *plannercore.PhysicalMaxOneRow:
@@ -770,6 +773,27 @@ func (b *executorBuilder) buildInsert(v *plannercore.Insert) Executor {
 		b.err = err
 		return nil
 	}
+	ivs.checkConstraints, err = table.BuildWritableConstraints(b.ctx, v.Table.Meta())
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	ivs.fkChecker, err = buildForeignKeyChecker(b.ctx, b.is, v.Table)
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	if ivs.fkChecker != nil && v.SelectPlan == nil {
+		// The referenced rows are read by the forUpdateTS like the other DML statements.
+		if b.err = b.updateForUpdateTSIfNeeded(nil); b.err != nil {
+			return nil
+		}
+	}
+	ivs.rowPolicy, err = buildRowPolicyChecker(b.ctx, v.Table.Meta())
+	if err != nil {
+		b.err = err
+		return nil
+	}
 
 	if v.IsReplace {
 		return b.buildReplace(ivs)
@@ -813,6 +837,26 @@ func (b *executorBuilder) buildLoadData(v *plannercore.LoadData) Executor {
 		b.err = err
 		return nil
 	}
+	insertVal.checkConstraints, err = table.BuildWritableConstraints(b.ctx, tbl.Meta())
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	insertVal.fkChecker, err = buildForeignKeyChecker(b.ctx, b.is, tbl)
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	if insertVal.fkChecker != nil {
+		if b.err = b.updateForUpdateTSIfNeeded(nil); b.err != nil {
+			return nil
+		}
+	}
+	insertVal.rowPolicy, err = buildRowPolicyChecker(b.ctx, tbl.Meta())
+	if err != nil {
+		b.err = err
+		return nil
+	}
 	loadDataExec := &LoadDataExec{
 		baseExecutor: newBaseExecutor(b.ctx, nil, v.ID()),
 		IsLocal:      v.IsLocal,
@@ -839,11 +883,13 @@ func (b *executorBuilder) buildIndexAdvise(v *plannercore.IndexAdvise) Executor
 		baseExecutor: newBaseExecutor(b.ctx, nil, v.ID()),
 		IsLocal:      v.IsLocal,
 		indexAdviseInfo: &IndexAdviseInfo{
-			Path:        v.Path,
-			MaxMinutes:  v.MaxMinutes,
-			MaxIndexNum: v.MaxIndexNum,
-			LinesInfo:   v.LinesInfo,
-			Ctx:         b.ctx,
+			IsLocal:         v.IsLocal,
+			Path:            v.Path,
+			FromStmtSummary: v.FromStmtSummary,
+			MaxMinutes:      v.MaxMinutes,
+			MaxIndexNum:     v.MaxIndexNum,
+			LinesInfo:       v.LinesInfo,
+			Ctx:             b.ctx,
 		},
 	}
 	return e
@@ -1357,6 +1403,17 @@ func (b *executorBuilder) buildTableDual(v *plannercore.PhysicalTableDual) Execu
 	return e
 }
 
+func (b *executorBuilder) buildJSONTable(v *plannercore.PhysicalJSONTable) Executor {
+	offset := 0
+	e := &JSONTableExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		expr:         v.Expr,
+		info:         v.Info,
+		columns:      buildJSONTableColumns(v.Info.Columns, &offset),
+	}
+	return e
+}
+
 // `getSnapshotTS` returns the timestamp of the snapshot that a reader should read.
 func (b *executorBuilder) getSnapshotTS() (uint64, error) {
 	// `refreshForUpdateTSForRC` should always be invoked before returning the cached value to
@@ -1514,6 +1571,7 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 			strings.ToLower(infoschema.TableCharacterSets),
 			strings.ToLower(infoschema.TableKeyColumn),
 			strings.ToLower(infoschema.TableUserPrivileges),
+			strings.ToLower(infoschema.TableColumnPrivileges),
 			strings.ToLower(infoschema.TableMetricTables),
 			strings.ToLower(infoschema.TableCollationCharacterSetApplicability),
 			strings.ToLower(infoschema.TableProcesslist),
@@ -1811,6 +1869,9 @@ func (b *executorBuilder) buildSplitRegion(v *plannercore.SplitRegion) Executor
 
 func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
 	tblID2table := make(map[int64]table.Table, len(v.TblColPosInfos))
+	tblID2constraints := make(map[int64][]*table.Constraint, len(v.TblColPosInfos))
+	tblID2fkChecker := make(map[int64]*foreignKeyChecker, len(v.TblColPosInfos))
+	tblID2rowPolicy := make(map[int64]*rowPolicyChecker, len(v.TblColPosInfos))
 	multiUpdateOnSameTable := make(map[int64]bool)
 	for _, info := range v.TblColPosInfos {
 		tbl, _ := b.is.TableByID(info.TblID)
@@ -1818,6 +1879,15 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
 			multiUpdateOnSameTable[info.TblID] = true
 		}
 		tblID2table[info.TblID] = tbl
+		if tblID2constraints[info.TblID], b.err = table.BuildWritableConstraints(b.ctx, tbl.Meta()); b.err != nil {
+			return nil
+		}
+		if tblID2fkChecker[info.TblID], b.err = buildForeignKeyChecker(b.ctx, b.is, tbl); b.err != nil {
+			return nil
+		}
+		if tblID2rowPolicy[info.TblID], b.err = buildRowPolicyChecker(b.ctx, tbl.Meta()); b.err != nil {
+			return nil
+		}
 		if len(v.PartitionedTable) > 0 {
 			// The v.PartitionedTable collects the partitioned table.
 			// Replace the original table with the partitioned table to support partition selection.
@@ -1830,7 +1900,14 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
 			}
 		}
 	}
-	if b.err = b.updateForUpdateTSIfNeeded(v.SelectPlan); b.err != nil {
+	// The point get plan reads the locked row, but the rows referring to it are read by the forUpdateTS.
+	selectPlan := v.SelectPlan
+	for _, fkChecker := range tblID2fkChecker {
+		if fkChecker != nil {
+			selectPlan = nil
+		}
+	}
+	if b.err = b.updateForUpdateTSIfNeeded(selectPlan); b.err != nil {
 		return nil
 	}
 	b.snapshotTS = b.ctx.GetSessionVars().TxnCtx.GetForUpdateTS()
@@ -1856,6 +1933,9 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
 		virtualAssignmentsOffset:  v.VirtualAssignmentsOffset,
 		multiUpdateOnSameTable:    multiUpdateOnSameTable,
 		tblID2table:               tblID2table,
+		tblID2constraints:         tblID2constraints,
+		tblID2fkChecker:           tblID2fkChecker,
+		tblID2rowPolicy:           tblID2rowPolicy,
 		tblColPosInfos:            v.TblColPosInfos,
 		assignFlag:                assignFlag,
 	}
@@ -1882,10 +1962,21 @@ func getAssignFlag(ctx sessionctx.Context, v *plannercore.Update, schemaLen int)
 
 func (b *executorBuilder) buildDelete(v *plannercore.Delete) Executor {
 	tblID2table := make(map[int64]table.Table, len(v.TblColPosInfos))
+	tblID2fkChecker := make(map[int64]*foreignKeyChecker, len(v.TblColPosInfos))
 	for _, info := range v.TblColPosInfos {
 		tblID2table[info.TblID], _ = b.is.TableByID(info.TblID)
+		if tblID2fkChecker[info.TblID], b.err = buildForeignKeyChecker(b.ctx, b.is, tblID2table[info.TblID]); b.err != nil {
+			return nil
+		}
 	}
-	if b.err = b.updateForUpdateTSIfNeeded(v.SelectPlan); b.err != nil {
+	// The point get plan reads the locked row, but the rows referring to it are read by the forUpdateTS.
+	selectPlan := v.SelectPlan
+	for _, fkChecker := range tblID2fkChecker {
+		if fkChecker != nil {
+			selectPlan = nil
+		}
+	}
+	if b.err = b.updateForUpdateTSIfNeeded(selectPlan); b.err != nil {
 		return nil
 	}
 	b.snapshotTS = b.ctx.GetSessionVars().TxnCtx.GetForUpdateTS()
@@ -1896,10 +1987,11 @@ func (b *executorBuilder) buildDelete(v *plannercore.Delete) Executor {
 	base := newBaseExecutor(b.ctx, v.Schema(), v.ID(), selExec)
 	base.initCap = chunk.ZeroCapacity
 	deleteExec := &DeleteExec{
-		baseExecutor:   base,
-		tblID2Table:    tblID2table,
-		IsMultiTable:   v.IsMultiTable,
-		tblColPosInfos: v.TblColPosInfos,
+		baseExecutor:    base,
+		tblID2Table:     tblID2table,
+		tblID2fkChecker: tblID2fkChecker,
+		IsMultiTable:    v.IsMultiTable,
+		tblColPosInfos:  v.TblColPosInfos,
 	}
 	return deleteExec
 }
@@ -2658,7 +2750,7 @@ func buildNoRangeTableReader(b *executorBuilder, v *plannercore.PhysicalTableRea
 	}
 	collect := statistics.CollectFeedback(b.ctx.GetSessionVars().StmtCtx, e.feedback, len(ts.Ranges))
 	// Do not collect the feedback when the table is the partition table.
-	if collect && tbl.Meta().Partition != nil {
+	if collect && tbl.Meta().GetPartitionInfo() != nil {
 		collect = false
 	}
 	if !collect {
@@ -2911,7 +3003,7 @@ func buildNoRangeIndexReader(b *executorBuilder, v *plannercore.PhysicalIndexRea
 	}
 	collect := statistics.CollectFeedback(b.ctx.GetSessionVars().StmtCtx, e.feedback, len(is.Ranges))
 	// Do not collect the feedback when the table is the partition table.
-	if collect && tbl.Meta().Partition != nil {
+	if collect && tbl.Meta().GetPartitionInfo() != nil {
 		collect = false
 	}
 	if !collect {
@@ -3745,12 +3837,12 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) *WindowExec
 	partialResults := make([]aggfuncs.PartialResult, 0, len(v.WindowFuncDescs))
 	resultColIdx := v.Schema().Len() - len(v.WindowFuncDescs)
 	for _, desc := range v.WindowFuncDescs {
-		aggDesc, err := aggregation.NewAggFuncDesc(b.ctx, desc.Name, desc.Args, false)
+		aggDesc, err := aggregation.NewAggFuncDesc(b.ctx, desc.Name, desc.Args, desc.HasDistinct)
 		if err != nil {
 			b.err = err
 			return nil
 		}
-		agg := aggfuncs.BuildWindowFunctions(b.ctx, aggDesc, resultColIdx, orderByCols)
+		agg := aggfuncs.BuildWindowFunctions(b.ctx, aggDesc, resultColIdx, orderByCols, desc.IgnoreNull, desc.FromLast)
 		windowFuncs = append(windowFuncs, agg)
 		partialResult, _ := agg.AllocPartialResult()
 		partialResults = append(partialResults, partialResult)
@@ -3761,6 +3853,7 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) *WindowExec
 		processor = &aggWindowProcessor{
 			windowFuncs:    windowFuncs,
 			partialResults: partialResults,
+			batchSize:      b.ctx.GetSessionVars().MaxChunkSize,
 		}
 	} else if v.Frame.Type == ast.Rows {
 		processor = &rowFrameWindowProcessor{
@@ -3769,6 +3862,19 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) *WindowExec
 			start:          v.Frame.Start,
 			end:            v.Frame.End,
 		}
+	} else if v.Frame.Type == ast.Groups {
+		cmpFuncs := make([]expression.CompareFunc, 0, len(orderByCols))
+		for _, col := range orderByCols {
+			cmpFuncs = append(cmpFuncs, expression.GetCmpFunction(b.ctx, col, col))
+		}
+		processor = &groupsFrameWindowProcessor{
+			windowFuncs:    windowFuncs,
+			partialResults: partialResults,
+			start:          v.Frame.Start,
+			end:            v.Frame.End,
+			orderByCols:    orderByCols,
+			cmpFuncs:       cmpFuncs,
+		}
 	} else {
 		cmpResult := int64(-1)
 		if len(v.OrderBy) > 0 && v.OrderBy[0].Desc {
@@ -4063,10 +4169,28 @@ func (b *executorBuilder) buildTableSample(v *plannercore.PhysicalTableSample) *
 		table:        v.TableInfo,
 		startTS:      startTS,
 	}
-	if v.TableSampleInfo.AstNode.SampleMethod == ast.SampleMethodTypeTiDBRegion {
+	info := v.TableSampleInfo
+	seed := info.Seed
+	if !info.Repeatable {
+		seed = rand.Int63()
+	}
+	switch info.AstNode.SampleMethod {
+	case ast.SampleMethodTypeTiDBRegion:
 		e.sampler = newTableRegionSampler(
-			b.ctx, v.TableInfo, startTS, v.TableSampleInfo.Partitions, v.Schema(),
-			v.TableSampleInfo.FullSchema, e.retFieldTypes, v.Desc)
+			b.ctx, v.TableInfo, startTS, info.Partitions, v.Schema(),
+			info.FullSchema, e.retFieldTypes, v.Desc)
+	case ast.SampleMethodTypeSystem:
+		e.sampler = newTableSystemSampler(
+			b.ctx, v.TableInfo, startTS, info.Partitions, v.Schema(),
+			info.FullSchema, e.retFieldTypes, v.Desc, info.Percent, seed)
+	case ast.SampleMethodTypeBernoulli:
+		e.sampler, err = newTableBernoulliSampler(
+			b.ctx, v.TableInfo, startTS, info.Partitions, v.Schema(),
+			info.FullSchema, v.Desc, info.Percent, seed)
+		if err != nil {
+			b.err = err
+			return nil
+		}
 	}
 	return e
 }
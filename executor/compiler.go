@@ -190,6 +190,12 @@ func getStmtDbLabel(stmtNode ast.StmtNode) map[string]struct{} {
 	case *ast.DropIndexStmt:
 		dbLabel := x.Table.Schema.O
 		dbLabelSet[dbLabel] = struct{}{}
+	case *ast.CreatePolicyStmt:
+		dbLabel := x.Table.Schema.O
+		dbLabelSet[dbLabel] = struct{}{}
+	case *ast.DropPolicyStmt:
+		dbLabel := x.Table.Schema.O
+		dbLabelSet[dbLabel] = struct{}{}
 	case *ast.DropTableStmt:
 		tables := x.Tables
 		for _, table := range tables {
@@ -309,6 +315,8 @@ func GetStmtLabel(stmtNode ast.StmtNode) string {
 		return "CreateDatabase"
 	case *ast.CreateIndexStmt:
 		return "CreateIndex"
+	case *ast.CreatePolicyStmt:
+		return "CreatePolicy"
 	case *ast.CreateTableStmt:
 		return "CreateTable"
 	case *ast.CreateViewStmt:
@@ -321,6 +329,8 @@ func GetStmtLabel(stmtNode ast.StmtNode) string {
 		return "DropDatabase"
 	case *ast.DropIndexStmt:
 		return "DropIndex"
+	case *ast.DropPolicyStmt:
+		return "DropPolicy"
 	case *ast.DropTableStmt:
 		return "DropTable"
 	case *ast.ExplainStmt:
@@ -28,9 +28,15 @@ import (
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	driver "github.com/pingcap/tidb/store/driver/txn"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/util/admin"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/dbterror"
@@ -74,6 +80,19 @@ func (e *DDLExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	}
 	e.done = true
 
+	// CREATE/DROP TEMPORARY TABLE only changes the local temporary tables of the session,
+	// so it doesn't commit the current transaction.
+	switch x := e.stmt.(type) {
+	case *ast.CreateTableStmt:
+		if x.TemporaryKeyword == ast.TemporaryLocal {
+			return e.createSessionTemporaryTable(x)
+		}
+	case *ast.DropTableStmt:
+		if x.TemporaryKeyword == ast.TemporaryLocal {
+			return e.dropLocalTemporaryTables(x.Tables, x.IfExists)
+		}
+	}
+
 	// For each DDL, we should commit the previous transaction and create a new transaction.
 	if err = e.ctx.NewTxn(ctx); err != nil {
 		return err
@@ -95,6 +114,10 @@ func (e *DDLExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		err = e.executeCreateView(x)
 	case *ast.DropIndexStmt:
 		err = e.executeDropIndex(x)
+	case *ast.CreatePolicyStmt:
+		err = e.executeCreatePolicy(x)
+	case *ast.DropPolicyStmt:
+		err = e.executeDropPolicy(x)
 	case *ast.DropDatabaseStmt:
 		err = e.executeDropDatabase(x)
 	case *ast.DropTableStmt:
@@ -148,12 +171,20 @@ func (e *DDLExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 }
 
 func (e *DDLExec) executeTruncateTable(s *ast.TruncateTableStmt) error {
+	if tbl, ok := e.getLocalTemporaryTable(s.Table.Schema, s.Table.Name); ok {
+		return e.truncateLocalTemporaryTable(s.Table.Schema, tbl)
+	}
 	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
 	err := domain.GetDomain(e.ctx).DDL().TruncateTable(e.ctx, ident)
 	return err
 }
 
 func (e *DDLExec) executeRenameTable(s *ast.RenameTableStmt) error {
+	for _, tables := range s.TableToTables {
+		if _, ok := e.getLocalTemporaryTable(tables.OldTable.Schema, tables.OldTable.Name); ok {
+			return core.ErrOptOnTemporaryTable.GenWithStackByArgs("rename table")
+		}
+	}
 	isAlterTable := false
 	var err error
 	if len(s.TableToTables) == 1 {
@@ -206,18 +237,182 @@ func (e *DDLExec) executeCreateTable(s *ast.CreateTableStmt) error {
 	return err
 }
 
+// createSessionTemporaryTable creates a local temporary table, which is only visible to the session.
+func (e *DDLExec) createSessionTemporaryTable(s *ast.CreateTableStmt) error {
+	dbInfo, ok := e.is.SchemaByName(s.Table.Schema)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(s.Table.Schema.O)
+	}
+	sessVars := e.ctx.GetSessionVars()
+	localTempTables, ok := sessVars.LocalTemporaryTables.(*infoschema.LocalTemporaryTables)
+	if !ok {
+		localTempTables = infoschema.NewLocalTemporaryTables()
+	}
+	if localTempTables.TableExists(dbInfo.Name, s.Table.Name) {
+		err := infoschema.ErrTableExists.GenWithStackByArgs(ast.Ident{Schema: dbInfo.Name, Name: s.Table.Name})
+		if s.IfNotExists {
+			sessVars.StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+
+	tbInfo, err := ddl.BuildSessionTemporaryTableInfo(e.ctx, e.is, s, dbInfo.Charset, dbInfo.Collate)
+	if err != nil {
+		return err
+	}
+	tbl, err := newLocalTemporaryTable(tbInfo)
+	if err != nil {
+		return err
+	}
+	if err = localTempTables.AddTable(dbInfo, tbl); err != nil {
+		return err
+	}
+	sessVars.LocalTemporaryTables = localTempTables
+	if sessVars.TemporaryTableData == nil {
+		sessVars.TemporaryTableData = driver.NewMemBuffer()
+	}
+	return nil
+}
+
+// newLocalTemporaryTable builds a table object for the local temporary table, its auto ID allocator
+// lives in the memory of the session.
+func newLocalTemporaryTable(tbInfo *model.TableInfo) (table.Table, error) {
+	var allocs autoid.Allocators
+	if alloc := autoid.NewAllocatorFromTempTblInfo(tbInfo); alloc != nil {
+		allocs = autoid.Allocators{alloc}
+	}
+	return tables.TableFromMeta(allocs, tbInfo)
+}
+
+// getLocalTemporaryTable gets the local temporary table of the session by name.
+func (e *DDLExec) getLocalTemporaryTable(schema, tblName model.CIStr) (table.Table, bool) {
+	localTempTables, ok := e.ctx.GetSessionVars().LocalTemporaryTables.(*infoschema.LocalTemporaryTables)
+	if !ok {
+		return nil, false
+	}
+	return localTempTables.TableByName(schema, tblName)
+}
+
+// dropLocalTemporaryTables drops the local temporary tables, it's an error if some of them don't exist.
+func (e *DDLExec) dropLocalTemporaryTables(objects []*ast.TableName, ifExists bool) error {
+	var notExistTables []string
+	for _, tn := range objects {
+		dropped, err := e.dropLocalTemporaryTable(tn.Schema, tn.Name)
+		if err != nil {
+			return err
+		}
+		if !dropped {
+			notExistTables = append(notExistTables, ast.Ident{Schema: tn.Schema, Name: tn.Name}.String())
+		}
+	}
+	if len(notExistTables) > 0 && !ifExists {
+		return infoschema.ErrTableDropExists.GenWithStackByArgs(strings.Join(notExistTables, ","))
+	}
+	for _, table := range notExistTables {
+		e.ctx.GetSessionVars().StmtCtx.AppendNote(infoschema.ErrTableDropExists.GenWithStackByArgs(table))
+	}
+	return nil
+}
+
+func (e *DDLExec) dropLocalTemporaryTable(schema, tblName model.CIStr) (bool, error) {
+	localTempTables, ok := e.ctx.GetSessionVars().LocalTemporaryTables.(*infoschema.LocalTemporaryTables)
+	if !ok {
+		return false, nil
+	}
+	tbl, ok := localTempTables.RemoveTable(schema, tblName)
+	if !ok {
+		return false, nil
+	}
+	return true, e.clearLocalTemporaryTableData(tbl.Meta().ID)
+}
+
+func (e *DDLExec) truncateLocalTemporaryTable(schema model.CIStr, tbl table.Table) error {
+	if err := e.clearLocalTemporaryTableData(tbl.Meta().ID); err != nil {
+		return err
+	}
+	// Build a new table object to reset the auto ID allocator.
+	newTbl, err := newLocalTemporaryTable(tbl.Meta())
+	if err != nil {
+		return err
+	}
+	e.ctx.GetSessionVars().LocalTemporaryTables.(*infoschema.LocalTemporaryTables).ReplaceTable(schema, newTbl)
+	return nil
+}
+
+// clearLocalTemporaryTableData deletes the data of a local temporary table from the session
+// and the current transaction.
+func (e *DDLExec) clearLocalTemporaryTableData(tblID int64) error {
+	start := tablecodec.EncodeTablePrefix(tblID)
+	end := tablecodec.EncodeTablePrefix(tblID + 1)
+	if data := e.ctx.GetSessionVars().TemporaryTableData; data != nil {
+		if err := deleteMemBufferRange(data, start, end); err != nil {
+			return err
+		}
+	}
+	txn, err := e.ctx.Txn(false)
+	if err != nil {
+		return err
+	}
+	if !txn.Valid() {
+		return nil
+	}
+	return deleteMemBufferRange(txn.GetMemBuffer(), start, end)
+}
+
+func deleteMemBufferRange(memBuffer kv.MemBuffer, start, end kv.Key) error {
+	iter, err := memBuffer.Iter(start, end)
+	if err != nil {
+		return err
+	}
+	var keys []kv.Key
+	for ; iter.Valid(); err = iter.Next() {
+		if err != nil {
+			iter.Close()
+			return err
+		}
+		if len(iter.Value()) > 0 {
+			keys = append(keys, iter.Key().Clone())
+		}
+	}
+	iter.Close()
+	for _, k := range keys {
+		if err = memBuffer.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (e *DDLExec) executeCreateView(s *ast.CreateViewStmt) error {
 	err := domain.GetDomain(e.ctx).DDL().CreateView(e.ctx, s)
 	return err
 }
 
 func (e *DDLExec) executeCreateIndex(s *ast.CreateIndexStmt) error {
+	if _, ok := e.getLocalTemporaryTable(s.Table.Schema, s.Table.Name); ok {
+		return core.ErrOptOnTemporaryTable.GenWithStackByArgs("create index")
+	}
 	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
 	err := domain.GetDomain(e.ctx).DDL().CreateIndex(e.ctx, ident, s.KeyType, model.NewCIStr(s.IndexName),
 		s.IndexPartSpecifications, s.IndexOption, s.IfNotExists)
 	return err
 }
 
+func (e *DDLExec) executeCreatePolicy(s *ast.CreatePolicyStmt) error {
+	if _, ok := e.getLocalTemporaryTable(s.Table.Schema, s.Table.Name); ok {
+		return core.ErrOptOnTemporaryTable.GenWithStackByArgs("create policy")
+	}
+	return domain.GetDomain(e.ctx).DDL().CreateRowPolicy(e.ctx, s)
+}
+
+func (e *DDLExec) executeDropPolicy(s *ast.DropPolicyStmt) error {
+	if _, ok := e.getLocalTemporaryTable(s.Table.Schema, s.Table.Name); ok {
+		return core.ErrOptOnTemporaryTable.GenWithStackByArgs("drop policy")
+	}
+	return domain.GetDomain(e.ctx).DDL().DropRowPolicy(e.ctx, s)
+}
+
 func (e *DDLExec) executeDropDatabase(s *ast.DropDatabaseStmt) error {
 	dbName := model.NewCIStr(s.Name)
 
@@ -318,6 +513,12 @@ func (e *DDLExec) dropTableObject(objects []*ast.TableName, obt objectType, ifEx
 			return err
 		}
 		tempTableType := tableInfo.Meta().TempTableType
+		if obt == tableObject && tempTableType == model.TempTableLocal {
+			if _, err = e.dropLocalTemporaryTable(tn.Schema, tn.Name); err != nil {
+				return err
+			}
+			continue
+		}
 		if obt == tableObject && config.CheckTableBeforeDrop && tempTableType == model.TempTableNone {
 			logutil.BgLogger().Warn("admin check table before drop",
 				zap.String("database", fullti.Schema.O),
@@ -367,6 +568,9 @@ func (e *DDLExec) dropTableObject(objects []*ast.TableName, obt objectType, ifEx
 }
 
 func (e *DDLExec) executeDropIndex(s *ast.DropIndexStmt) error {
+	if _, ok := e.getLocalTemporaryTable(s.Table.Schema, s.Table.Name); ok {
+		return core.ErrOptOnTemporaryTable.GenWithStackByArgs("drop index")
+	}
 	ti := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
 	err := domain.GetDomain(e.ctx).DDL().DropIndex(e.ctx, ti, model.NewCIStr(s.IndexName), s.IfExists)
 	if (infoschema.ErrDatabaseNotExists.Equal(err) || infoschema.ErrTableNotExists.Equal(err)) && s.IfExists {
@@ -376,6 +580,9 @@ func (e *DDLExec) executeDropIndex(s *ast.DropIndexStmt) error {
 }
 
 func (e *DDLExec) executeAlterTable(s *ast.AlterTableStmt) error {
+	if _, ok := e.getLocalTemporaryTable(s.Table.Schema, s.Table.Name); ok {
+		return core.ErrOptOnTemporaryTable.GenWithStackByArgs("alter table")
+	}
 	ti := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
 	err := domain.GetDomain(e.ctx).DDL().AlterTable(e.ctx, ti, s.Specs)
 	return err
@@ -30,29 +30,43 @@ var (
 	ErrUnsupportedPs        = dbterror.ClassExecutor.NewStd(mysql.ErrUnsupportedPs)
 	ErrSubqueryMoreThan1Row = dbterror.ClassExecutor.NewStd(mysql.ErrSubqueryNo1Row)
 
-	ErrCantCreateUserWithGrant       = dbterror.ClassExecutor.NewStd(mysql.ErrCantCreateUserWithGrant)
-	ErrPasswordNoMatch               = dbterror.ClassExecutor.NewStd(mysql.ErrPasswordNoMatch)
-	ErrCannotUser                    = dbterror.ClassExecutor.NewStd(mysql.ErrCannotUser)
-	ErrGrantRole                     = dbterror.ClassExecutor.NewStd(mysql.ErrGrantRole)
-	ErrPasswordFormat                = dbterror.ClassExecutor.NewStd(mysql.ErrPasswordFormat)
-	ErrCantChangeTxCharacteristics   = dbterror.ClassExecutor.NewStd(mysql.ErrCantChangeTxCharacteristics)
-	ErrPsManyParam                   = dbterror.ClassExecutor.NewStd(mysql.ErrPsManyParam)
-	ErrAdminCheckTable               = dbterror.ClassExecutor.NewStd(mysql.ErrAdminCheckTable)
-	ErrDBaccessDenied                = dbterror.ClassExecutor.NewStd(mysql.ErrDBaccessDenied)
-	ErrTableaccessDenied             = dbterror.ClassExecutor.NewStd(mysql.ErrTableaccessDenied)
-	ErrBadDB                         = dbterror.ClassExecutor.NewStd(mysql.ErrBadDB)
-	ErrWrongObject                   = dbterror.ClassExecutor.NewStd(mysql.ErrWrongObject)
-	ErrWrongUsage                    = dbterror.ClassExecutor.NewStd(mysql.ErrWrongUsage)
-	ErrRoleNotGranted                = dbterror.ClassPrivilege.NewStd(mysql.ErrRoleNotGranted)
-	ErrDeadlock                      = dbterror.ClassExecutor.NewStd(mysql.ErrLockDeadlock)
-	ErrQueryInterrupted              = dbterror.ClassExecutor.NewStd(mysql.ErrQueryInterrupted)
-	ErrDynamicPrivilegeNotRegistered = dbterror.ClassExecutor.NewStd(mysql.ErrDynamicPrivilegeNotRegistered)
-	ErrIllegalPrivilegeLevel         = dbterror.ClassExecutor.NewStd(mysql.ErrIllegalPrivilegeLevel)
-	ErrInvalidSplitRegionRanges      = dbterror.ClassExecutor.NewStd(mysql.ErrInvalidSplitRegionRanges)
+	ErrCantCreateUserWithGrant        = dbterror.ClassExecutor.NewStd(mysql.ErrCantCreateUserWithGrant)
+	ErrPasswordNoMatch                = dbterror.ClassExecutor.NewStd(mysql.ErrPasswordNoMatch)
+	ErrCannotUser                     = dbterror.ClassExecutor.NewStd(mysql.ErrCannotUser)
+	ErrGrantRole                      = dbterror.ClassExecutor.NewStd(mysql.ErrGrantRole)
+	ErrPasswordFormat                 = dbterror.ClassExecutor.NewStd(mysql.ErrPasswordFormat)
+	ErrPluginIsNotLoaded              = dbterror.ClassExecutor.NewStd(mysql.ErrPluginIsNotLoaded)
+	ErrCantChangeTxCharacteristics    = dbterror.ClassExecutor.NewStd(mysql.ErrCantChangeTxCharacteristics)
+	ErrPsManyParam                    = dbterror.ClassExecutor.NewStd(mysql.ErrPsManyParam)
+	ErrAdminCheckTable                = dbterror.ClassExecutor.NewStd(mysql.ErrAdminCheckTable)
+	ErrDBaccessDenied                 = dbterror.ClassExecutor.NewStd(mysql.ErrDBaccessDenied)
+	ErrTableaccessDenied              = dbterror.ClassExecutor.NewStd(mysql.ErrTableaccessDenied)
+	ErrBadDB                          = dbterror.ClassExecutor.NewStd(mysql.ErrBadDB)
+	ErrWrongObject                    = dbterror.ClassExecutor.NewStd(mysql.ErrWrongObject)
+	ErrWrongUsage                     = dbterror.ClassExecutor.NewStd(mysql.ErrWrongUsage)
+	ErrRoleNotGranted                 = dbterror.ClassPrivilege.NewStd(mysql.ErrRoleNotGranted)
+	ErrDeadlock                       = dbterror.ClassExecutor.NewStd(mysql.ErrLockDeadlock)
+	ErrQueryInterrupted               = dbterror.ClassExecutor.NewStd(mysql.ErrQueryInterrupted)
+	ErrDynamicPrivilegeNotRegistered  = dbterror.ClassExecutor.NewStd(mysql.ErrDynamicPrivilegeNotRegistered)
+	ErrIllegalPrivilegeLevel          = dbterror.ClassExecutor.NewStd(mysql.ErrIllegalPrivilegeLevel)
+	ErrIllegalGrantForTable           = dbterror.ClassExecutor.NewStd(mysql.ErrIllegalGrantForTable)
+	ErrInvalidSplitRegionRanges       = dbterror.ClassExecutor.NewStd(mysql.ErrInvalidSplitRegionRanges)
+	ErrCredentialsContradictToHistory = dbterror.ClassExecutor.NewStd(mysql.ErrCredentialsContradictToHistory)
+	ErrMustChangePassword             = dbterror.ClassExecutor.NewStd(mysql.ErrMustChangePassword)
 
 	ErrBRIEBackupFailed     = dbterror.ClassExecutor.NewStd(mysql.ErrBRIEBackupFailed)
 	ErrBRIERestoreFailed    = dbterror.ClassExecutor.NewStd(mysql.ErrBRIERestoreFailed)
 	ErrBRIEImportFailed     = dbterror.ClassExecutor.NewStd(mysql.ErrBRIEImportFailed)
 	ErrBRIEExportFailed     = dbterror.ClassExecutor.NewStd(mysql.ErrBRIEExportFailed)
 	ErrCTEMaxRecursionDepth = dbterror.ClassExecutor.NewStd(mysql.ErrCTEMaxRecursionDepth)
+
+	ErrNoReferencedRow2               = dbterror.ClassExecutor.NewStd(mysql.ErrNoReferencedRow2)
+	ErrRowIsReferenced2               = dbterror.ClassExecutor.NewStd(mysql.ErrRowIsReferenced2)
+	ErrForeignKeyCascadeDepthExceeded = dbterror.ClassExecutor.NewStd(mysql.ErrForeignKeyCascadeDepthExceeded)
+	ErrFkNoIndexChild                 = dbterror.ClassExecutor.NewStd(mysql.ErrFkNoIndexChild)
+	ErrFkNoIndexParent                = dbterror.ClassExecutor.NewStd(mysql.ErrFkNoIndexParent)
+	ErrRowPolicyViolated              = dbterror.ClassExecutor.NewStd(mysql.ErrRowPolicyViolated)
+
+	ErrMissingJSONTableValue = dbterror.ClassExecutor.NewStd(mysql.ErrMissingJSONTableValue)
+	ErrWrongJSONTableValue   = dbterror.ClassExecutor.NewStd(mysql.ErrWrongJSONTableValue)
 )
@@ -1030,16 +1030,25 @@ func doLockKeys(ctx context.Context, se sessionctx.Context, lockCtx *tikvstore.L
 
 func filterTemporaryTableKeys(vars *variable.SessionVars, keys []kv.Key) []kv.Key {
 	txnCtx := vars.TxnCtx
-	if txnCtx == nil || txnCtx.GlobalTemporaryTables == nil {
+	localTempTables, _ := vars.LocalTemporaryTables.(*infoschema.LocalTemporaryTables)
+	if (txnCtx == nil || txnCtx.GlobalTemporaryTables == nil) && localTempTables == nil {
 		return keys
 	}
 
-	newKeys := keys[:]
+	newKeys := keys[:0]
 	for _, key := range keys {
 		tblID := tablecodec.DecodeTableID(key)
-		if _, ok := txnCtx.GlobalTemporaryTables[tblID]; !ok {
-			newKeys = append(newKeys, key)
+		if txnCtx != nil {
+			if _, ok := txnCtx.GlobalTemporaryTables[tblID]; ok {
+				continue
+			}
+		}
+		if localTempTables != nil {
+			if _, ok := localTempTables.TableByID(tblID); ok {
+				continue
+			}
 		}
+		newKeys = append(newKeys, key)
 	}
 	return newKeys
 }
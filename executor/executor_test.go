@@ -363,6 +363,7 @@ func (s *testSuiteP1) TestShow(c *C) {
 		"RESTRICTED_STATUS_ADMIN Server Admin ",
 		"RESTRICTED_VARIABLES_ADMIN Server Admin ",
 		"RESTRICTED_USER_ADMIN Server Admin ",
+		"ROW_POLICY_ADMIN Server Admin ",
 	))
 	c.Assert(len(tk.MustQuery("show table status").Rows()), Equals, 1)
 }
@@ -6986,6 +6987,38 @@ func (s *testSuiteP2) TestApplyCache(c *C) {
 	c.Assert(value[ind:], Equals, "cache:OFF")
 }
 
+func (s *testSuiteP2) TestJSONTableInFromClause(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustQuery("select * from json_table('[1, \"a\", 3]', '$[*]' columns (id for ordinality, v int path '$' default '0' on error)) as jt").
+		Check(testkit.Rows("1 1", "2 0", "3 3"))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int, doc json)")
+	tk.MustExec(`insert into t values (1, '{"a": [{"x": 1, "y": [10, 20]}, {"x": 2}]}'), (2, '{"a": []}'), (3, null)`)
+	tk.MustQuery(`select t.id, jt.* from t, json_table(t.doc, '$.a[*]' columns (
+		xid for ordinality,
+		x int path '$.x',
+		has_y int exists path '$.y',
+		nested path '$.y[*]' columns (y int path '$'))) as jt order by t.id, jt.xid, jt.y`).
+		Check(testkit.Rows("1 1 1 1 10", "1 1 1 1 20", "1 2 2 0 <nil>"))
+	tk.MustQuery(`select t.id, jt.x from t left join json_table(t.doc, '$.a[*]' columns (x int path '$.x')) as jt on jt.x > 1 order by t.id`).
+		Check(testkit.Rows("1 2", "2 <nil>", "3 <nil>"))
+	tk.MustQuery(`select t.id, jt.x from t join json_table(t.doc, '$.a[*]' columns (x int path '$.x')) as jt on jt.x = t.id`).
+		Check(testkit.Rows("1 1"))
+	tk.MustQuery(`select jt.v from json_table('[{"a": 1}, {}]', '$[*]' columns (v varchar(10) path '$.a' default '"none"' on empty)) as jt`).
+		Check(testkit.Rows("1", "none"))
+	tk.MustQuery(`select id, (select count(*) from json_table(t.doc, '$.a[*]' columns (x int path '$.x')) as jt) from t order by id`).
+		Check(testkit.Rows("1 2", "2 0", "3 0"))
+
+	_, err := tk.Exec(`select * from json_table('[{}]', '$[*]' columns (v int path '$.a' error on empty)) as jt`)
+	c.Assert(executor.ErrMissingJSONTableValue.Equal(err), IsTrue, Commentf("err: %v", err))
+	_, err = tk.Exec(`select * from t right join json_table(t.doc, '$' columns (v int path '$')) as jt on true`)
+	c.Assert(plannercore.ErrNotSupportedYet.Equal(err), IsTrue, Commentf("err: %v", err))
+	_, err = tk.Exec(`select * from json_table('[1]', '$' columns (v int path '$', V int path '$')) as jt`)
+	c.Assert(plannercore.ErrDupFieldName.Equal(err), IsTrue, Commentf("err: %v", err))
+}
+
 // For issue 17256
 func (s *testSuite) TestGenerateColumnReplace(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
@@ -8278,6 +8311,60 @@ func (s *testSuite1) TestTemporaryTableNoPessimisticLock(c *C) {
 	tk.MustExec("rollback")
 }
 
+func (s *testSuite1) TestLocalTemporaryTable(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t;")
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1)")
+
+	// The local temporary table shadows the normal table with the same name.
+	tk.MustExec("create temporary table t (a int primary key, b int, unique key uk(b))")
+	tk.MustQuery("select * from t").Check(testkit.Rows())
+	schema := tk.Se.GetInfoSchema().(infoschema.InfoSchema)
+	tbl, err := schema.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	c.Assert(tbl.Meta().TempTableType, Equals, model.TempTableLocal)
+
+	// The data survives across transactions.
+	tk.MustExec("insert into t values (2, 2), (3, 3)")
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (4, 4)")
+	tk.MustExec("update t set b = b + 10 where a = 2")
+	tk.MustExec("commit")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("2 12", "3 3", "4 4"))
+	tk.MustQuery("select * from t where a = 2").Check(testkit.Rows("2 12"))
+	tk.MustQuery("select * from t where a in (3, 4) order by a").Check(testkit.Rows("3 3", "4 4"))
+	tk.MustQuery("select b from t use index(uk) where b > 3 order by b").Check(testkit.Rows("4", "12"))
+	tk.MustGetErrCode("insert into t values (2, 5)", mysql.ErrDupEntry)
+	tk.MustGetErrCode("insert into t values (5, 3)", mysql.ErrDupEntry)
+
+	// The changes are discarded when the transaction is rolled back.
+	tk.MustExec("begin")
+	tk.MustExec("delete from t where a = 3")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("2 12", "4 4"))
+	tk.MustExec("rollback")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("2 12", "3 3", "4 4"))
+
+	// The table is invisible to other sessions.
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use test")
+	tk1.MustQuery("select * from t").Check(testkit.Rows("1 1"))
+
+	tk.MustExec("truncate table t")
+	tk.MustQuery("select * from t").Check(testkit.Rows())
+	tk.MustGetErrCode("alter table t add column c int", errno.ErrOptOnTemporaryTable)
+	tk.MustExec("drop temporary table t")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1"))
+	tk.MustGetErrCode("drop temporary table t", mysql.ErrBadTable)
+
+	// The local temporary tables are dropped when the session is closed.
+	tk.MustExec("create temporary table tmp (a int)")
+	tk.Se.Close()
+	tk1.MustGetErrCode("select * from tmp", mysql.ErrNoSuchTable)
+	tk1.MustExec("drop table t")
+}
+
 func (s testSerialSuite) TestExprBlackListForEnum(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 
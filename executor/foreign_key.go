@@ -0,0 +1,642 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+)
+
+// maxForeignKeyCascadeDepth is the max depth of the cascading foreign key actions, it's the same as MySQL.
+// It also bounds the cascading actions on the cyclic foreign keys.
+const maxForeignKeyCascadeDepth = 15
+
+// foreignKeyChecker enforces the foreign key constraints of a table when its rows are written.
+// The table is the child table of the foreign keys in fks: the referenced parent rows should exist.
+// The table is the parent table of the foreign keys in refFKs: the referential actions are run on
+// the child rows when the referenced rows are deleted or updated.
+// The referenced tables are looked up in the schema of the child table.
+type foreignKeyChecker struct {
+	is     infoschema.InfoSchema
+	dbName model.CIStr
+	tbl    table.Table
+	fks    []*fkParentRef
+	refFKs []*fkChildRef
+	// depth is the depth of the cascading actions which write the rows of the table.
+	depth int
+}
+
+// fkParentRef is a foreign key of the table, the parent table is nil if it doesn't exist.
+type fkParentRef struct {
+	fk         *model.FKInfo
+	cols       []*table.Column
+	parent     table.Table
+	parentCols []*table.Column
+}
+
+// fkChildRef is a foreign key referring to the table.
+type fkChildRef struct {
+	fk        *model.FKInfo
+	child     table.Table
+	childCols []*table.Column
+	refCols   []*table.Column
+}
+
+// fkRow is a row found by the foreign key columns.
+type fkRow struct {
+	handle kv.Handle
+	row    []types.Datum
+}
+
+// buildForeignKeyChecker builds the foreign key checker for writing the rows of tbl.
+// It returns nil if the foreign keys aren't enforced or there is nothing to check.
+func buildForeignKeyChecker(sctx sessionctx.Context, is infoschema.InfoSchema, tbl table.Table) (*foreignKeyChecker, error) {
+	if !sctx.GetSessionVars().ForeignKeyChecks {
+		return nil, nil
+	}
+	return newForeignKeyChecker(is, tbl, 0)
+}
+
+func newForeignKeyChecker(is infoschema.InfoSchema, tbl table.Table, depth int) (*foreignKeyChecker, error) {
+	tblInfo := tbl.Meta()
+	if tblInfo.TempTableType != model.TempTableNone {
+		return nil, nil
+	}
+	dbInfo, ok := is.SchemaByTable(tblInfo)
+	if !ok {
+		return nil, nil
+	}
+	c := &foreignKeyChecker{is: is, dbName: dbInfo.Name, tbl: tbl, depth: depth}
+	for _, fk := range tblInfo.ForeignKeys {
+		if fk.State != model.StatePublic {
+			continue
+		}
+		cols, err := findForeignKeyColumns(tbl, fk.Cols)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ref := &fkParentRef{fk: fk, cols: cols}
+		if parent, err := is.TableByName(dbInfo.Name, fk.RefTable); err == nil {
+			if parentCols, err := findForeignKeyColumns(parent, fk.RefCols); err == nil {
+				if !hasForeignKeyIndex(parent.Meta(), parentCols) {
+					return nil, ErrFkNoIndexParent.GenWithStackByArgs(fk.Name.O, parent.Meta().Name.O)
+				}
+				ref.parent, ref.parentCols = parent, parentCols
+			}
+		}
+		c.fks = append(c.fks, ref)
+	}
+	for _, child := range is.SchemaTables(dbInfo.Name) {
+		for _, fk := range child.Meta().ForeignKeys {
+			if fk.State != model.StatePublic || fk.RefTable.L != tblInfo.Name.L {
+				continue
+			}
+			childCols, err := findForeignKeyColumns(child, fk.Cols)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			refCols, err := findForeignKeyColumns(tbl, fk.RefCols)
+			if err != nil {
+				// The foreign key doesn't refer to the existing columns, no row can be referenced.
+				continue
+			}
+			if !hasForeignKeyIndex(child.Meta(), childCols) {
+				return nil, ErrFkNoIndexChild.GenWithStackByArgs(fk.Name.O, child.Meta().Name.O)
+			}
+			c.refFKs = append(c.refFKs, &fkChildRef{fk: fk, child: child, childCols: childCols, refCols: refCols})
+		}
+	}
+	if len(c.fks) == 0 && len(c.refFKs) == 0 {
+		return nil, nil
+	}
+	return c, nil
+}
+
+func findForeignKeyColumns(tbl table.Table, names []model.CIStr) ([]*table.Column, error) {
+	cols := make([]*table.Column, 0, len(names))
+	for _, name := range names {
+		col := table.FindCol(tbl.Cols(), name.L)
+		if col == nil {
+			return nil, errors.Errorf("column %s of foreign key doesn't exist in table %s", name.O, tbl.Meta().Name.O)
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// checkParentsExist checks whether the parent rows referenced by the row exist, only the foreign keys
+// whose columns are modified are checked if modified isn't nil. The parent rows are locked in the
+// pessimistic transactions, so that they can't be deleted before the transaction is committed.
+func (c *foreignKeyChecker) checkParentsExist(ctx context.Context, sctx sessionctx.Context, row []types.Datum, modified []bool) error {
+	if c == nil {
+		return nil
+	}
+	for _, ref := range c.fks {
+		if modified != nil && !isAnyColumnModified(ref.cols, modified) {
+			continue
+		}
+		vals, hasNull := fetchForeignKeyValues(row, ref.cols)
+		if hasNull {
+			// MATCH SIMPLE, the row with NULL values doesn't refer to any rows.
+			continue
+		}
+		if ref.parent == nil {
+			return ErrNoReferencedRow2.GenWithStackByArgs(c.foreignKeyString(c.tbl, ref.fk))
+		}
+		exists, err := lookupParentRow(ctx, sctx, ref.parent, ref.parentCols, vals)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrNoReferencedRow2.GenWithStackByArgs(c.foreignKeyString(c.tbl, ref.fk))
+		}
+	}
+	return nil
+}
+
+// onDelete runs the referential actions of the foreign keys referring to the deleted row.
+func (c *foreignKeyChecker) onDelete(ctx context.Context, sctx sessionctx.Context, row []types.Datum) error {
+	if c == nil {
+		return nil
+	}
+	for _, ref := range c.refFKs {
+		vals, hasNull := fetchForeignKeyValues(row, ref.refCols)
+		if hasNull {
+			continue
+		}
+		childRows, err := c.lookupChildRows(ctx, sctx, ref, vals)
+		if err != nil {
+			return err
+		}
+		if len(childRows) == 0 {
+			continue
+		}
+		switch ast.ReferOptionType(ref.fk.OnDelete) {
+		case ast.ReferOptionCascade:
+			err = c.cascadeDelete(ctx, sctx, ref, childRows)
+		case ast.ReferOptionSetNull:
+			err = c.cascadeUpdate(ctx, sctx, ref, childRows, nil)
+		default:
+			// RESTRICT, NO ACTION and SET DEFAULT which isn't supported by InnoDB either.
+			err = ErrRowIsReferenced2.GenWithStackByArgs(c.foreignKeyString(ref.child, ref.fk))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onUpdate runs the referential actions of the foreign keys referring to the updated row.
+func (c *foreignKeyChecker) onUpdate(ctx context.Context, sctx sessionctx.Context, oldRow, newRow []types.Datum, modified []bool) error {
+	if c == nil {
+		return nil
+	}
+	for _, ref := range c.refFKs {
+		if !isAnyColumnModified(ref.refCols, modified) {
+			continue
+		}
+		vals, hasNull := fetchForeignKeyValues(oldRow, ref.refCols)
+		if hasNull {
+			continue
+		}
+		childRows, err := c.lookupChildRows(ctx, sctx, ref, vals)
+		if err != nil {
+			return err
+		}
+		if len(childRows) == 0 {
+			continue
+		}
+		switch ast.ReferOptionType(ref.fk.OnUpdate) {
+		case ast.ReferOptionCascade:
+			newVals, _ := fetchForeignKeyValues(newRow, ref.refCols)
+			err = c.cascadeUpdate(ctx, sctx, ref, childRows, newVals)
+		case ast.ReferOptionSetNull:
+			err = c.cascadeUpdate(ctx, sctx, ref, childRows, nil)
+		default:
+			err = ErrRowIsReferenced2.GenWithStackByArgs(c.foreignKeyString(ref.child, ref.fk))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *foreignKeyChecker) childChecker(ref *fkChildRef) (*foreignKeyChecker, error) {
+	if c.depth+1 > maxForeignKeyCascadeDepth {
+		return nil, ErrForeignKeyCascadeDepthExceeded.GenWithStackByArgs(maxForeignKeyCascadeDepth)
+	}
+	return newForeignKeyChecker(c.is, ref.child, c.depth+1)
+}
+
+// cascadeDelete deletes the child rows, then runs the actions of the foreign keys referring to them.
+func (c *foreignKeyChecker) cascadeDelete(ctx context.Context, sctx sessionctx.Context, ref *fkChildRef, childRows []fkRow) error {
+	checker, err := c.childChecker(ref)
+	if err != nil {
+		return err
+	}
+	for _, r := range childRows {
+		if err = ref.child.RemoveRecord(sctx, r.handle, r.row); err != nil {
+			return err
+		}
+		if err = checker.onDelete(ctx, sctx, r.row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cascadeUpdate sets the foreign key columns of the child rows to newVals, or NULL if newVals is nil,
+// then runs the actions of the foreign keys referring to them.
+func (c *foreignKeyChecker) cascadeUpdate(ctx context.Context, sctx sessionctx.Context, ref *fkChildRef, childRows []fkRow, newVals []types.Datum) error {
+	checker, err := c.childChecker(ref)
+	if err != nil {
+		return err
+	}
+	cols := ref.child.Cols()
+	for _, r := range childRows {
+		newRow := make([]types.Datum, len(r.row))
+		copy(newRow, r.row)
+		modified := make([]bool, len(cols))
+		for i, col := range ref.childCols {
+			if newVals == nil {
+				if mysql.HasNotNullFlag(col.Flag) {
+					return table.ErrColumnCantNull.GenWithStackByArgs(col.Name.O)
+				}
+				newRow[col.Offset].SetNull()
+			} else {
+				v, err := table.CastValue(sctx, newVals[i], col.ToInfo(), false, false)
+				if err != nil {
+					return err
+				}
+				newRow[col.Offset] = v
+			}
+			modified[col.Offset] = true
+		}
+		if err = fillVirtualColumnValues(sctx, ref.child, newRow); err != nil {
+			return err
+		}
+		if err = updateChildRecord(ctx, sctx, ref.child, r.handle, r.row, newRow, modified); err != nil {
+			return err
+		}
+		if err = checker.onUpdate(ctx, sctx, r.row, newRow, modified); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateChildRecord updates the child row without counting it in the affected rows, like MySQL does.
+func updateChildRecord(ctx context.Context, sctx sessionctx.Context, t table.Table, h kv.Handle, oldRow, newRow []types.Datum, modified []bool) error {
+	tblInfo := t.Meta()
+	handleChanged := false
+	for i, col := range t.Cols() {
+		if modified[i] && (col.IsPKHandleColumn(tblInfo) || col.IsCommonHandleColumn(tblInfo)) {
+			handleChanged = true
+			break
+		}
+	}
+	if !handleChanged {
+		return t.UpdateRecord(ctx, sctx, h, oldRow, newRow, modified)
+	}
+	if err := t.RemoveRecord(sctx, h, oldRow); err != nil {
+		return err
+	}
+	_, err := t.AddRecord(sctx, newRow, table.IsUpdate, table.WithCtx(ctx))
+	return err
+}
+
+// lookupChildRows finds the child rows whose foreign key columns equal to vals.
+func (c *foreignKeyChecker) lookupChildRows(ctx context.Context, sctx sessionctx.Context, ref *fkChildRef, vals []types.Datum) ([]fkRow, error) {
+	vals, err := castForeignKeyValues(sctx, vals, ref.childCols)
+	if err != nil {
+		return nil, err
+	}
+	var rows []fkRow
+	for _, pt := range physicalTablesOf(ref.child) {
+		handles, err := lookupHandles(ctx, sctx, pt, ref.childCols, vals)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range handles {
+			row, err := tables.RowWithCols(pt, sctx, h, pt.Cols())
+			if kv.ErrNotExist.Equal(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if err = fillVirtualColumnValues(sctx, pt, row); err != nil {
+				return nil, err
+			}
+			rows = append(rows, fkRow{handle: h, row: row})
+		}
+	}
+	return rows, nil
+}
+
+// lookupParentRow checks whether the parent row whose referenced columns equal to vals exists.
+// The parent row is locked in the pessimistic transactions.
+func lookupParentRow(ctx context.Context, sctx sessionctx.Context, parent table.Table, cols []*table.Column, vals []types.Datum) (bool, error) {
+	vals, err := castForeignKeyValues(sctx, vals, cols)
+	if err != nil {
+		return false, err
+	}
+	for _, pt := range physicalTablesOf(parent) {
+		// The unique key can be locked directly, so the latest version is checked.
+		if key, ok, err := uniqueKeyOf(sctx, pt, cols, vals); err != nil {
+			return false, err
+		} else if ok {
+			exists, err := lockAndCheckKeyExists(ctx, sctx, key)
+			if err != nil || exists {
+				return exists, err
+			}
+			continue
+		}
+		handles, err := lookupHandles(ctx, sctx, pt, cols, vals)
+		if err != nil {
+			return false, err
+		}
+		for _, h := range handles {
+			exists, err := lockAndCheckKeyExists(ctx, sctx, tablecodec.EncodeRecordKey(pt.RecordPrefix(), h))
+			if err != nil || exists {
+				return exists, err
+			}
+		}
+	}
+	return false, nil
+}
+
+// uniqueKeyOf returns the row key or the unique index key of the row whose cols equal to vals, if cols
+// is the integer primary key or a unique index.
+func uniqueKeyOf(sctx sessionctx.Context, pt table.PhysicalTable, cols []*table.Column, vals []types.Datum) (kv.Key, bool, error) {
+	tblInfo := pt.Meta()
+	if len(cols) == 1 && cols[0].IsPKHandleColumn(tblInfo) {
+		handle := kv.IntHandle(vals[0].GetInt64())
+		return tablecodec.EncodeRecordKey(pt.RecordPrefix(), handle), true, nil
+	}
+	idxInfo := findForeignKeyIndex(tblInfo, cols)
+	if idxInfo == nil || !idxInfo.Unique || len(idxInfo.Columns) != len(cols) || (idxInfo.Primary && tblInfo.IsCommonHandle) {
+		return nil, false, nil
+	}
+	key, distinct, err := tablecodec.GenIndexKey(sctx.GetSessionVars().StmtCtx, tblInfo, idxInfo, pt.GetPhysicalID(), vals, nil, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	return key, distinct, nil
+}
+
+// lookupHandles finds the handles of the rows whose cols equal to vals in the physical table.
+// An index whose leading columns are cols is used, it reads the forUpdateTS snapshot in the pessimistic transactions.
+func lookupHandles(ctx context.Context, sctx sessionctx.Context, pt table.PhysicalTable, cols []*table.Column, vals []types.Datum) ([]kv.Handle, error) {
+	tblInfo := pt.Meta()
+	sc := sctx.GetSessionVars().StmtCtx
+	txn, err := sctx.Txn(true)
+	if err != nil {
+		return nil, err
+	}
+	if key, ok, err := uniqueKeyOf(sctx, pt, cols, vals); err != nil {
+		return nil, err
+	} else if ok {
+		val, err := txn.Get(ctx, key)
+		if kv.IsErrNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if tablecodec.IsRecordKey(key) {
+			h, err := tablecodec.DecodeRowKey(key)
+			return []kv.Handle{h}, err
+		}
+		h, err := tablecodec.DecodeHandleInUniqueIndexValue(val, tblInfo.IsCommonHandle)
+		return []kv.Handle{h}, err
+	}
+
+	var prefix kv.Key
+	isRecord := false
+	idxInfo := findForeignKeyIndex(tblInfo, cols)
+	switch {
+	case idxInfo == nil:
+		// It's checked when the foreign key checker is built.
+		return nil, errors.Errorf("there is no index on the foreign key columns of table %s", tblInfo.Name.O)
+	case idxInfo.Primary && tblInfo.IsCommonHandle:
+		pkVals := make([]types.Datum, len(vals))
+		copy(pkVals, vals)
+		tablecodec.TruncateIndexValues(tblInfo, idxInfo, pkVals)
+		encoded, err := codec.EncodeKey(sc, nil, pkVals...)
+		if err != nil {
+			return nil, err
+		}
+		prefix, isRecord = append(pt.RecordPrefix().Clone(), encoded...), true
+	default:
+		prefix, _, err = tablecodec.GenIndexKey(sc, tblInfo, idxInfo, pt.GetPhysicalID(), vals, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	it, err := txn.Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var handles []kv.Handle
+	for ; it.Valid() && it.Key().HasPrefix(prefix); err = it.Next() {
+		var h kv.Handle
+		if isRecord {
+			h, err = tablecodec.DecodeRowKey(it.Key())
+		} else {
+			h, err = tablecodec.DecodeIndexHandle(it.Key(), it.Value(), len(idxInfo.Columns))
+		}
+		if err != nil {
+			return nil, err
+		}
+		handles = append(handles, h)
+	}
+	return handles, err
+}
+
+// hasForeignKeyIndex returns whether the rows can be found by the columns without scanning the table.
+func hasForeignKeyIndex(tblInfo *model.TableInfo, cols []*table.Column) bool {
+	return (len(cols) == 1 && cols[0].IsPKHandleColumn(tblInfo)) || findForeignKeyIndex(tblInfo, cols) != nil
+}
+
+// findForeignKeyIndex finds the index whose leading columns are cols, the unique index is preferred.
+func findForeignKeyIndex(tblInfo *model.TableInfo, cols []*table.Column) *model.IndexInfo {
+	var found *model.IndexInfo
+	for _, idxInfo := range tblInfo.Indices {
+		if idxInfo.State != model.StatePublic || len(idxInfo.Columns) < len(cols) {
+			continue
+		}
+		matched := true
+		for i, col := range cols {
+			idxCol := idxInfo.Columns[i]
+			if idxCol.Name.L != col.Name.L || idxCol.Length != types.UnspecifiedLength {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		if idxInfo.Unique && len(idxInfo.Columns) == len(cols) {
+			return idxInfo
+		}
+		if found == nil {
+			found = idxInfo
+		}
+	}
+	return found
+}
+
+// lockAndCheckKeyExists locks the key in the pessimistic transactions and checks whether it exists.
+func lockAndCheckKeyExists(ctx context.Context, sctx sessionctx.Context, key kv.Key) (bool, error) {
+	txn, err := sctx.Txn(true)
+	if err != nil {
+		return false, err
+	}
+	vars := sctx.GetSessionVars()
+	if vars.TxnCtx.IsPessimistic {
+		lockCtx := newLockCtx(vars, vars.LockWaitTimeout)
+		lockCtx.InitReturnValues(1)
+		if err = doLockKeys(ctx, sctx, lockCtx, key); err != nil {
+			return false, err
+		}
+		lockCtx.IterateValuesNotLocked(func(k, v []byte) {
+			vars.TxnCtx.SetPessimisticLockCache(kv.Key(k), v)
+		})
+	}
+	val, err := txn.GetMemBuffer().Get(ctx, key)
+	if err == nil {
+		return len(val) > 0, nil
+	}
+	if !kv.IsErrNotFound(err) {
+		return false, err
+	}
+	if vars.TxnCtx.IsPessimistic {
+		if val, ok := vars.TxnCtx.GetKeyInPessimisticLockCache(key); ok {
+			return len(val) > 0, nil
+		}
+	}
+	_, err = txn.Get(ctx, key)
+	if kv.IsErrNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// fillVirtualColumnValues evaluates the virtual generated columns of the row read from the storage.
+func fillVirtualColumnValues(sctx sessionctx.Context, t table.Table, row []types.Datum) error {
+	var (
+		mutRow chunk.MutRow
+		inited bool
+	)
+	for _, col := range t.Cols() {
+		if !col.IsGenerated() || col.GeneratedStored {
+			continue
+		}
+		if !inited {
+			mutRow, inited = chunk.MutRowFromDatums(row), true
+		}
+		expr, err := expression.ParseSimpleExprWithTableInfo(sctx, col.GeneratedExprString, t.Meta())
+		if err != nil {
+			return err
+		}
+		val, err := expr.Eval(mutRow.ToRow())
+		if err != nil {
+			return err
+		}
+		row[col.Offset], err = table.CastValue(sctx, val, col.ToInfo(), false, false)
+		if err != nil {
+			return err
+		}
+		mutRow.SetDatum(col.Offset, row[col.Offset])
+	}
+	return nil
+}
+
+func castForeignKeyValues(sctx sessionctx.Context, vals []types.Datum, cols []*table.Column) ([]types.Datum, error) {
+	casted := make([]types.Datum, len(vals))
+	for i, col := range cols {
+		v, err := table.CastValue(sctx, vals[i], col.ToInfo(), false, false)
+		if err != nil {
+			return nil, err
+		}
+		casted[i] = v
+	}
+	return casted, nil
+}
+
+func fetchForeignKeyValues(row []types.Datum, cols []*table.Column) ([]types.Datum, bool) {
+	vals := make([]types.Datum, len(cols))
+	for i, col := range cols {
+		if row[col.Offset].IsNull() {
+			return nil, true
+		}
+		vals[i] = row[col.Offset]
+	}
+	return vals, false
+}
+
+func isAnyColumnModified(cols []*table.Column, modified []bool) bool {
+	for _, col := range cols {
+		if modified[col.Offset] {
+			return true
+		}
+	}
+	return false
+}
+
+func physicalTablesOf(t table.Table) []table.PhysicalTable {
+	if pt, ok := t.(table.PartitionedTable); ok {
+		ids := pt.GetAllPartitionIDs()
+		pts := make([]table.PhysicalTable, 0, len(ids))
+		for _, id := range ids {
+			pts = append(pts, pt.GetPartition(id))
+		}
+		return pts
+	}
+	return []table.PhysicalTable{t.(table.PhysicalTable)}
+}
+
+// foreignKeyString formats the foreign key in the error messages like MySQL does.
+func (c *foreignKeyChecker) foreignKeyString(child table.Table, fk *model.FKInfo) string {
+	quote := func(names []model.CIStr) string {
+		quoted := make([]string, 0, len(names))
+		for _, name := range names {
+			quoted = append(quoted, "`"+name.O+"`")
+		}
+		return strings.Join(quoted, ", ")
+	}
+	return fmt.Sprintf("`%s`.`%s`, CONSTRAINT `%s` FOREIGN KEY (%s) REFERENCES `%s` (%s)",
+		c.dbName.O, child.Meta().Name.O, fk.Name.O, quote(fk.Cols), fk.RefTable.O, quote(fk.RefCols))
+}
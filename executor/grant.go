@@ -189,6 +189,9 @@ func (e *GrantExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		// Grant each priv to the user.
 		for _, priv := range privs {
 			if len(priv.Cols) > 0 {
+				if err := checkColumnPrivType(priv.Priv); err != nil {
+					return err
+				}
 				// Check column scope privilege entry.
 				// TODO: Check validity before insert new entry.
 				err := e.checkAndInitColumnPriv(user.User.Username, user.User.Hostname, priv.Cols, internalSession)
@@ -601,7 +604,7 @@ func composeDBPrivUpdate(sql *strings.Builder, priv mysql.PrivilegeType, value s
 }
 
 func privUpdateForGrant(cur []string, priv mysql.PrivilegeType) ([]string, error) {
-	p, ok := mysql.Priv2SetStr[priv]
+	p, ok := privileges.PrivToSetStr(priv)
 	if !ok {
 		return nil, errors.Errorf("Unknown priv: %v", priv)
 	}
@@ -621,7 +624,7 @@ func composeTablePrivUpdateForGrant(ctx sessionctx.Context, sql *strings.Builder
 		newTablePriv = setFromString(currTablePriv)
 		newColumnPriv = setFromString(currColumnPriv)
 		tblPrivs = []mysql.PrivilegeType{priv}
-		for _, cp := range mysql.AllColumnPrivs {
+		for _, cp := range privileges.ColumnPrivs {
 			// in case it is not a column priv
 			if cp == priv {
 				colPrivs = []mysql.PrivilegeType{priv}
@@ -630,7 +633,7 @@ func composeTablePrivUpdateForGrant(ctx sessionctx.Context, sql *strings.Builder
 		}
 	} else {
 		tblPrivs = mysql.AllTablePrivs
-		colPrivs = mysql.AllColumnPrivs
+		colPrivs = privileges.ColumnPrivs
 	}
 
 	var err error
@@ -664,7 +667,7 @@ func composeColumnPrivUpdateForGrant(ctx sessionctx.Context, sql *strings.Builde
 		newColumnPriv = setFromString(currColumnPriv)
 		colPrivs = []mysql.PrivilegeType{priv}
 	} else {
-		colPrivs = mysql.AllColumnPrivs
+		colPrivs = privileges.ColumnPrivs
 	}
 
 	var err error
@@ -679,6 +682,19 @@ func composeColumnPrivUpdateForGrant(ctx sessionctx.Context, sql *strings.Builde
 	return nil
 }
 
+// checkColumnPrivType checks whether the privilege can be granted or revoked in column scope.
+func checkColumnPrivType(priv mysql.PrivilegeType) error {
+	if priv == mysql.AllPriv {
+		return nil
+	}
+	for _, p := range privileges.ColumnPrivs {
+		if p == priv {
+			return nil
+		}
+	}
+	return ErrIllegalGrantForTable.GenWithStackByArgs()
+}
+
 // recordExists is a helper function to check if the sql returns any row.
 func recordExists(ctx sessionctx.Context, sql string, args ...interface{}) (bool, error) {
 	rs, err := ctx.(sqlexec.SQLExecutor).ExecuteInternal(context.Background(), sql, args...)
@@ -15,14 +15,39 @@ package executor
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/opcode"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/infoschema"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/hint"
+	"github.com/pingcap/tidb/util/stmtsummary"
+	"github.com/pingcap/tidb/util/stringutil"
+)
+
+const (
+	// indexAdviseStmtSummaryLimit is the max number of statements taken from the statement summary.
+	indexAdviseStmtSummaryLimit = 100
+	// maxIndexAdviseColumns is the max number of columns in a candidate index.
+	maxIndexAdviseColumns = 3
+	// minIndexAdviseBenefitRatio is the min ratio of the cost reduction to the cost of the affected queries,
+	// a candidate index with less benefit is not worth creating.
+	minIndexAdviseBenefitRatio = 0.01
 )
 
 // IndexAdviseExec represents a index advise executor.
@@ -35,14 +60,16 @@ type IndexAdviseExec struct {
 
 // Next implements the Executor Next interface.
 func (e *IndexAdviseExec) Next(ctx context.Context, req *chunk.Chunk) error {
-	if !e.IsLocal {
-		return errors.New("Index Advise: don't support load file without local field")
-	}
-	if e.indexAdviseInfo.Path == "" {
-		return errors.New("Index Advise: infile path is empty")
-	}
-	if len(e.indexAdviseInfo.LinesInfo.Terminated) == 0 {
-		return errors.New("Index Advise: don't support advise index for SQL terminated by nil")
+	if !e.indexAdviseInfo.FromStmtSummary {
+		if !e.IsLocal {
+			return errors.New("Index Advise: don't support load file without local field")
+		}
+		if e.indexAdviseInfo.Path == "" {
+			return errors.New("Index Advise: infile path is empty")
+		}
+		if len(e.indexAdviseInfo.LinesInfo.Terminated) == 0 {
+			return errors.New("Index Advise: don't support advise index for SQL terminated by nil")
+		}
 	}
 
 	if val := e.ctx.Value(IndexAdviseVarKey); val != nil {
@@ -65,13 +92,19 @@ func (e *IndexAdviseExec) Open(ctx context.Context) error {
 
 // IndexAdviseInfo saves the information of index advise operation.
 type IndexAdviseInfo struct {
-	Path        string
-	MaxMinutes  uint64
-	MaxIndexNum *ast.MaxIndexNumClause
-	LinesInfo   *ast.LinesClause
-	Ctx         sessionctx.Context
-	StmtNodes   [][]ast.StmtNode
-	Result      *IndexAdvice
+	IsLocal bool
+	Path    string
+	// FromStmtSummary indicates the statements with the highest sum latency in the statement summary
+	// are used as the workload instead of reading a file.
+	FromStmtSummary bool
+	MaxMinutes      uint64
+	MaxIndexNum     *ast.MaxIndexNumClause
+	LinesInfo       *ast.LinesClause
+	Ctx             sessionctx.Context
+	StmtNodes       [][]ast.StmtNode
+	Result          *IndexAdvice
+
+	workload []*adviseQuery
 }
 
 func (e *IndexAdviseInfo) getStmtNodes(data []byte) error {
@@ -101,6 +134,44 @@ func (e *IndexAdviseInfo) getStmtNodes(data []byte) error {
 		curStmtNodes := make([]ast.StmtNode, len(stmtNodes))
 		copy(curStmtNodes, stmtNodes)
 		e.StmtNodes[i] = curStmtNodes
+		for _, stmtNode := range stmtNodes {
+			e.workload = append(e.workload, &adviseQuery{
+				schema: sv.CurrentDB,
+				sql:    strings.TrimSuffix(strings.TrimSpace(stmtNode.Text()), ";"),
+				stmt:   stmtNode,
+				weight: 1,
+			})
+		}
+	}
+	return nil
+}
+
+// getStmtSummaryNodes takes the statements with the highest sum latency in the statement summary as the
+// workload, every statement is weighted by its execution count.
+func (e *IndexAdviseInfo) getStmtSummaryNodes() error {
+	sv := e.Ctx.GetSessionVars()
+	isSuper := false
+	if pm := privilege.GetPrivilegeManager(e.Ctx); pm != nil {
+		isSuper = pm.RequestVerificationWithUser("", "", "", mysql.SuperPriv, sv.User)
+	}
+	stmts := stmtsummary.StmtSummaryByDigestMap.GetTopLatencyAdvisableStmt(indexAdviseStmtSummaryLimit, sv.User, isSuper)
+	sqlParser := parser.New()
+	for _, stmt := range stmts {
+		stmtNodes, _, err := sqlParser.Parse(stmt.Query, "", "")
+		if err != nil {
+			// The sample SQL may be truncated, just skip it.
+			sv.StmtCtx.AppendWarning(errors.Errorf("Index Advise: skip the statement of digest %s: %v", stmt.Digest, err))
+			continue
+		}
+		e.StmtNodes = append(e.StmtNodes, stmtNodes)
+		for _, stmtNode := range stmtNodes {
+			e.workload = append(e.workload, &adviseQuery{
+				schema: stmt.Schema,
+				sql:    stmt.Query,
+				stmt:   stmtNode,
+				weight: float64(stmt.ExecCount),
+			})
+		}
 	}
 	return nil
 }
@@ -114,21 +185,734 @@ func (e *IndexAdviseInfo) prepareInfo(data []byte) error {
 			return errors.New("Index Advise: the maximum number of indexes should be greater than 0")
 		}
 	}
+	e.StmtNodes, e.workload = nil, nil
+	if e.FromStmtSummary {
+		return e.getStmtSummaryNodes()
+	}
 	return e.getStmtNodes(data)
 }
 
-// GetIndexAdvice gets the index advice by workload file.
+// GetIndexAdvice gets the index advice by workload file, or by the statement summary for
+// INDEX ADVISE FROM STATEMENTS_SUMMARY, in which case data is ignored.
 func (e *IndexAdviseInfo) GetIndexAdvice(ctx context.Context, data []byte) error {
 	if err := e.prepareInfo(data); err != nil {
 		return err
 	}
-	// TODO: Finish the index advise process. It will be done in another PR.
+	advisor := &indexAdvisor{
+		info:      e,
+		sctx:      e.Ctx,
+		is:        domain.GetDomain(e.Ctx).InfoSchema(),
+		sc:        e.Ctx.GetSessionVars().StmtCtx,
+		hypoNames: make(map[int64]map[string]struct{}),
+		hypoIDs:   make(map[int64]int64),
+		keys:      make(map[string]*hypoIndex),
+	}
+	if e.MaxMinutes != ast.UnspecifiedSize {
+		advisor.deadline = time.Now().Add(time.Duration(e.MaxMinutes) * time.Minute)
+	}
+	result, err := advisor.advise(ctx)
+	if err != nil {
+		return err
+	}
+	e.Result = result
 	return nil
 }
 
-// IndexAdvice represents the index advice.
+// adviseQuery is a query of the workload.
+type adviseQuery struct {
+	schema string
+	sql    string
+	stmt   ast.StmtNode
+	// sel is the SELECT statement which is optimized to estimate the cost of the query.
+	sel ast.StmtNode
+	// weight is the number of times the query is executed.
+	weight float64
+	// cost is the estimated cost with the currently advised indexes.
+	cost         float64
+	originalCost float64
+}
+
+// hypoIndex is a hypothetical index, it exists only for the optimizer to estimate the cost
+// of the queries as if it were created.
+type hypoIndex struct {
+	dbName  model.CIStr
+	tblInfo *model.TableInfo
+	info    *model.IndexInfo
+	// queries are the queries which may benefit from the index.
+	queries []*adviseQuery
+}
+
+func (h *hypoIndex) createStmt(sqlMode mysql.SQLMode) string {
+	cols := make([]string, 0, len(h.info.Columns))
+	for _, col := range h.info.Columns {
+		cols = append(cols, stringutil.Escape(col.Name.O, sqlMode))
+	}
+	return fmt.Sprintf("CREATE INDEX %s ON %s.%s (%s)", stringutil.Escape(h.info.Name.O, sqlMode),
+		stringutil.Escape(h.dbName.O, sqlMode), stringutil.Escape(h.tblInfo.Name.O, sqlMode), strings.Join(cols, ", "))
+}
+
+// indexAdvisor advises indexes for the workload in a what-if way: the candidate indexes are added to the
+// optimizer as hypothetical indexes, and the ones which reduce the estimated cost of the workload most are
+// picked greedily.
+type indexAdvisor struct {
+	info     *IndexAdviseInfo
+	sctx     sessionctx.Context
+	is       infoschema.InfoSchema
+	deadline time.Time
+	// sc is the statement context of INDEX ADVISE.
+	sc *stmtctx.StatementContext
+
+	queries    []*adviseQuery
+	candidates []*hypoIndex
+	keys       map[string]*hypoIndex
+	hypoNames  map[int64]map[string]struct{}
+	hypoIDs    map[int64]int64
+}
+
+func (a *indexAdvisor) advise(ctx context.Context) (*IndexAdvice, error) {
+	sessVars := a.sctx.GetSessionVars()
+	sc := a.sc
+	originalDB := sessVars.CurrentDB
+	defer func() {
+		sessVars.StmtCtx = sc
+		sessVars.CurrentDB = originalDB
+		sessVars.HypoIndexes = nil
+	}()
+
+	for _, q := range a.info.workload {
+		if err := a.prepareQuery(ctx, q); err != nil {
+			sc.AppendWarning(errors.Errorf("Index Advise: skip the query '%s': %v", q.sql, err))
+		}
+	}
+
+	chosen := make(map[int64][]*model.IndexInfo)
+	var advised []*hypoIndex
+	tableIdxNum := make(map[int64]uint64)
+	dbIdxNum := make(map[string]uint64)
+	perTable, perDB := uint64(ast.UnspecifiedSize), uint64(ast.UnspecifiedSize)
+	if a.info.MaxIndexNum != nil {
+		perTable, perDB = a.info.MaxIndexNum.PerTable, a.info.MaxIndexNum.PerDB
+	}
+	for {
+		if !a.deadline.IsZero() && time.Now().After(a.deadline) {
+			sc.AppendWarning(errors.New("Index Advise: the maximum execution time is reached, the advice may be incomplete"))
+			break
+		}
+		var (
+			best        *hypoIndex
+			bestBenefit float64
+			bestCosts   []float64
+		)
+		for _, cand := range a.candidates {
+			if cand == nil || tableIdxNum[cand.tblInfo.ID] >= perTable || dbIdxNum[cand.dbName.L] >= perDB {
+				continue
+			}
+			hypoIndexes := cloneHypoIndexes(chosen)
+			hypoIndexes[cand.tblInfo.ID] = append(hypoIndexes[cand.tblInfo.ID], cand.info)
+			var benefit, totalCost float64
+			costs := make([]float64, len(cand.queries))
+			for i, q := range cand.queries {
+				_, cost, err := a.optimize(ctx, q, hypoIndexes)
+				if err != nil {
+					cost = q.cost
+				}
+				costs[i] = cost
+				benefit += q.weight * (q.cost - cost)
+				totalCost += q.weight * q.cost
+			}
+			if benefit > bestBenefit && benefit >= totalCost*minIndexAdviseBenefitRatio {
+				best, bestBenefit, bestCosts = cand, benefit, costs
+			}
+		}
+		if best == nil {
+			break
+		}
+		for i, q := range best.queries {
+			q.cost = bestCosts[i]
+		}
+		chosen[best.tblInfo.ID] = append(chosen[best.tblInfo.ID], best.info)
+		tableIdxNum[best.tblInfo.ID]++
+		dbIdxNum[best.dbName.L]++
+		advised = append(advised, best)
+		for i, cand := range a.candidates {
+			if cand == best {
+				a.candidates[i] = nil
+			}
+		}
+	}
+	return a.buildResult(ctx, advised, chosen)
+}
+
+// prepareQuery resolves the query, estimates its original cost and generates the candidate indexes for it.
+func (a *indexAdvisor) prepareQuery(ctx context.Context, q *adviseQuery) error {
+	switch x := q.stmt.(type) {
+	case *ast.SelectStmt, *ast.SetOprStmt:
+		q.sel = x
+	case *ast.DeleteStmt:
+		if x.IsMultiTable {
+			return errors.New("multiple-table delete is not supported")
+		}
+		q.sel = &ast.SelectStmt{
+			Fields:  &ast.FieldList{Fields: []*ast.SelectField{{WildCard: &ast.WildCardField{}}}},
+			From:    x.TableRefs,
+			Where:   x.Where,
+			OrderBy: x.Order,
+			Limit:   x.Limit,
+		}
+	case *ast.UpdateStmt:
+		q.sel = &ast.SelectStmt{
+			Fields:  &ast.FieldList{Fields: []*ast.SelectField{{WildCard: &ast.WildCardField{}}}},
+			From:    x.TableRefs,
+			Where:   x.Where,
+			OrderBy: x.Order,
+			Limit:   x.Limit,
+		}
+	default:
+		return errors.Errorf("unsupported statement type %T", q.stmt)
+	}
+	sessVars := a.sctx.GetSessionVars()
+	sessVars.CurrentDB = q.schema
+	sessVars.StmtCtx = a.newStmtCtx()
+	if err := plannercore.Preprocess(a.sctx, q.sel, a.is); err != nil {
+		return err
+	}
+	_, cost, err := a.optimize(ctx, q, nil)
+	if err != nil {
+		return err
+	}
+	q.cost, q.originalCost = cost, cost
+	a.queries = append(a.queries, q)
+
+	collector := &indexColumnCollector{}
+	q.sel.Accept(collector)
+	for _, src := range collector.sources {
+		a.genCandidates(q, src)
+	}
+	return nil
+}
+
+// optimize builds the physical plan of the query with the hypothetical indexes.
+func (a *indexAdvisor) optimize(ctx context.Context, q *adviseQuery, hypoIndexes map[int64][]*model.IndexInfo) (plannercore.PhysicalPlan, float64, error) {
+	sessVars := a.sctx.GetSessionVars()
+	sessVars.CurrentDB = q.schema
+	sessVars.StmtCtx = a.newStmtCtx()
+	sessVars.HypoIndexes = hypoIndexes
+	sessVars.PlanID = 0
+	sessVars.PlanColumnID = 0
+	hintProcessor := &hint.BlockHintProcessor{Ctx: a.sctx}
+	q.sel.Accept(hintProcessor)
+	builder, _ := plannercore.NewPlanBuilder(a.sctx, a.is, hintProcessor)
+	p, err := builder.Build(ctx, q.sel)
+	if err != nil {
+		return nil, 0, err
+	}
+	if pm := privilege.GetPrivilegeManager(a.sctx); pm != nil {
+		if err := plannercore.CheckPrivilege(sessVars.ActiveRoles, pm, builder.GetVisitInfo()); err != nil {
+			return nil, 0, err
+		}
+	}
+	logic, ok := p.(plannercore.LogicalPlan)
+	if !ok {
+		return nil, 0, errors.Errorf("unsupported plan type %T", p)
+	}
+	return plannercore.DoOptimize(ctx, a.sctx, builder.GetOptFlag(), logic)
+}
+
+// newStmtCtx creates a statement context for optimizing a query, so the warnings of the optimizer
+// are not mixed with the ones of INDEX ADVISE.
+func (a *indexAdvisor) newStmtCtx() *stmtctx.StatementContext {
+	sessVars := a.sctx.GetSessionVars()
+	return &stmtctx.StatementContext{
+		MemTracker:        a.sc.MemTracker,
+		DiskTracker:       a.sc.DiskTracker,
+		TimeZone:          sessVars.Location(),
+		InSelectStmt:      true,
+		TruncateAsWarning: true,
+		OverflowAsWarning: true,
+		IgnoreZeroInDate:  true,
+		AllowInvalidDate:  sessVars.SQLMode.HasAllowInvalidDatesMode(),
+	}
+}
+
+// genCandidates generates the single-column and multi-column candidate indexes for a table source of the query.
+// The multi-column candidates put the columns of equal conditions first, followed by a range column or the
+// ordering columns, which is how a composite index is able to be used.
+func (a *indexAdvisor) genCandidates(q *adviseQuery, src *adviseTableSource) {
+	var colsList [][]*model.ColumnInfo
+	for _, col := range mergeIndexColumns(src.eqCols, src.rangeCols, src.orderCols) {
+		colsList = append(colsList, []*model.ColumnInfo{col})
+	}
+	if len(src.eqCols) > 1 {
+		colsList = append(colsList, src.eqCols)
+	}
+	if len(src.eqCols) > 0 {
+		for _, col := range src.rangeCols {
+			colsList = append(colsList, mergeIndexColumns(src.eqCols, []*model.ColumnInfo{col}))
+		}
+		if len(src.orderCols) > 0 {
+			colsList = append(colsList, mergeIndexColumns(src.eqCols, src.orderCols))
+		}
+	}
+	if len(src.orderCols) > 1 {
+		colsList = append(colsList, src.orderCols)
+	}
+	for _, cols := range colsList {
+		if len(cols) > maxIndexAdviseColumns {
+			cols = cols[:maxIndexAdviseColumns]
+		}
+		a.addCandidate(q, src, cols)
+	}
+}
+
+func (a *indexAdvisor) addCandidate(q *adviseQuery, src *adviseTableSource, cols []*model.ColumnInfo) {
+	tblInfo := src.tblInfo
+	names := make([]string, 0, len(cols))
+	for _, col := range cols {
+		names = append(names, col.Name.L)
+	}
+	key := fmt.Sprintf("%d:%s", tblInfo.ID, strings.Join(names, ","))
+	if cand, ok := a.keys[key]; ok {
+		if cand != nil && cand.queries[len(cand.queries)-1] != q {
+			cand.queries = append(cand.queries, q)
+		}
+		return
+	}
+	if isCoveredByExistingIndex(tblInfo, cols) {
+		a.keys[key] = nil
+		return
+	}
+
+	idxCols := make([]*model.IndexColumn, 0, len(cols))
+	for _, col := range cols {
+		idxCols = append(idxCols, &model.IndexColumn{Name: col.Name, Offset: col.Offset, Length: types.UnspecifiedLength})
+	}
+	a.hypoIDs[tblInfo.ID]++
+	cand := &hypoIndex{
+		dbName:  src.dbName,
+		tblInfo: tblInfo,
+		info: &model.IndexInfo{
+			ID:      tblInfo.MaxIndexID + a.hypoIDs[tblInfo.ID],
+			Name:    model.NewCIStr(a.genIndexName(tblInfo, names)),
+			Table:   tblInfo.Name,
+			Columns: idxCols,
+			State:   model.StatePublic,
+			Tp:      model.IndexTypeBtree,
+		},
+		queries: []*adviseQuery{q},
+	}
+	a.keys[key] = cand
+	a.candidates = append(a.candidates, cand)
+}
+
+// genIndexName generates an index name like `idx_a_b` which doesn't conflict with other indexes of the table.
+func (a *indexAdvisor) genIndexName(tblInfo *model.TableInfo, colNames []string) string {
+	names, ok := a.hypoNames[tblInfo.ID]
+	if !ok {
+		names = make(map[string]struct{})
+		for _, idx := range tblInfo.Indices {
+			names[idx.Name.L] = struct{}{}
+		}
+		a.hypoNames[tblInfo.ID] = names
+	}
+	base := "idx_" + strings.Join(colNames, "_")
+	if len(base) > mysql.MaxIndexIdentifierLen-4 {
+		base = base[:mysql.MaxIndexIdentifierLen-4]
+	}
+	name := base
+	for i := 1; ; i++ {
+		if _, ok := names[strings.ToLower(name)]; !ok {
+			break
+		}
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	names[strings.ToLower(name)] = struct{}{}
+	return name
+}
+
+// buildResult builds the advice of the indexes which are used by the final plans of the queries.
+func (a *indexAdvisor) buildResult(ctx context.Context, advised []*hypoIndex, chosen map[int64][]*model.IndexInfo) (*IndexAdvice, error) {
+	usedBy := make(map[*model.IndexInfo][]*adviseQuery)
+	for _, q := range a.queries {
+		if q.cost >= q.originalCost {
+			continue
+		}
+		p, _, err := a.optimize(ctx, q, chosen)
+		if err != nil {
+			return nil, err
+		}
+		used := make(map[*model.IndexInfo]struct{})
+		collectUsedIndexes(p, used)
+		for idx := range used {
+			usedBy[idx] = append(usedBy[idx], q)
+		}
+	}
+
+	sqlMode := a.sctx.GetSessionVars().SQLMode
+	result := newIndexAdvice(a.sctx)
+	for _, h := range advised {
+		queries := usedBy[h.info]
+		if len(queries) == 0 {
+			continue
+		}
+		createStmt := h.createStmt(sqlMode)
+		table := h.dbName.O + "." + h.tblInfo.Name.O
+		for _, q := range queries {
+			reduction := 0.0
+			if q.originalCost > 0 {
+				reduction = math.Round((q.originalCost-q.cost)/q.originalCost*10000) / 100
+			}
+			result.chk.AppendString(0, table)
+			result.chk.AppendString(1, createStmt)
+			result.chk.AppendString(2, q.sql)
+			result.chk.AppendFloat64(3, q.originalCost)
+			result.chk.AppendFloat64(4, q.cost)
+			result.chk.AppendFloat64(5, reduction)
+		}
+	}
+	return result, nil
+}
+
+func cloneHypoIndexes(hypoIndexes map[int64][]*model.IndexInfo) map[int64][]*model.IndexInfo {
+	cloned := make(map[int64][]*model.IndexInfo, len(hypoIndexes)+1)
+	for id, indexes := range hypoIndexes {
+		cloned[id] = append([]*model.IndexInfo(nil), indexes...)
+	}
+	return cloned
+}
+
+// collectUsedIndexes collects the indexes scanned by the physical plan.
+func collectUsedIndexes(p plannercore.PhysicalPlan, used map[*model.IndexInfo]struct{}) {
+	switch x := p.(type) {
+	case *plannercore.PhysicalIndexScan:
+		used[x.Index] = struct{}{}
+	case *plannercore.PhysicalIndexReader:
+		for _, child := range x.IndexPlans {
+			collectUsedIndexes(child, used)
+		}
+	case *plannercore.PhysicalIndexLookUpReader:
+		for _, child := range x.IndexPlans {
+			collectUsedIndexes(child, used)
+		}
+	case *plannercore.PhysicalIndexMergeReader:
+		for _, partialPlan := range x.PartialPlans {
+			for _, child := range partialPlan {
+				collectUsedIndexes(child, used)
+			}
+		}
+	}
+	for _, child := range p.Children() {
+		collectUsedIndexes(child, used)
+	}
+}
+
+// isCoveredByExistingIndex checks whether the columns are a prefix of an existing index of the table.
+func isCoveredByExistingIndex(tblInfo *model.TableInfo, cols []*model.ColumnInfo) bool {
+	if tblInfo.PKIsHandle && len(cols) == 1 && mysql.HasPriKeyFlag(cols[0].Flag) {
+		return true
+	}
+	for _, idx := range tblInfo.Indices {
+		if idx.State != model.StatePublic || len(idx.Columns) < len(cols) {
+			continue
+		}
+		covered := true
+		for i, col := range cols {
+			if idx.Columns[i].Name.L != col.Name.L || idx.Columns[i].Length != types.UnspecifiedLength {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeIndexColumns merges the column lists in order and removes the duplicated columns.
+func mergeIndexColumns(colsList ...[]*model.ColumnInfo) []*model.ColumnInfo {
+	var merged []*model.ColumnInfo
+	for _, cols := range colsList {
+		merged = appendIndexColumn(merged, cols...)
+	}
+	return merged
+}
+
+func appendIndexColumn(cols []*model.ColumnInfo, newCols ...*model.ColumnInfo) []*model.ColumnInfo {
+	for _, newCol := range newCols {
+		duplicated := false
+		for _, col := range cols {
+			if col == newCol {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			cols = append(cols, newCol)
+		}
+	}
+	return cols
+}
+
+// adviseTableSource is a table referenced by a query block, the candidate indexes are generated from the
+// columns which are used to filter, join or sort it.
+type adviseTableSource struct {
+	dbName  model.CIStr
+	tblInfo *model.TableInfo
+	alias   model.CIStr
+	// eqCols are the columns in equal, IN or IS NULL conditions, including the join keys.
+	eqCols []*model.ColumnInfo
+	// rangeCols are the columns in range conditions.
+	rangeCols []*model.ColumnInfo
+	// orderCols are the GROUP BY or ORDER BY columns.
+	orderCols []*model.ColumnInfo
+}
+
+// indexColumnCollector collects the indexable columns of every table source in a statement.
+type indexColumnCollector struct {
+	sources []*adviseTableSource
+}
+
+// Enter implements the ast.Visitor interface.
+func (c *indexColumnCollector) Enter(in ast.Node) (ast.Node, bool) {
+	if sel, ok := in.(*ast.SelectStmt); ok {
+		c.collectSelect(sel)
+	}
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (c *indexColumnCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (c *indexColumnCollector) collectSelect(sel *ast.SelectStmt) {
+	if sel.From == nil {
+		return
+	}
+	var (
+		sources []*adviseTableSource
+		conds   []ast.ExprNode
+		extract func(node ast.ResultSetNode, asName model.CIStr)
+	)
+	extract = func(node ast.ResultSetNode, asName model.CIStr) {
+		switch x := node.(type) {
+		case *ast.Join:
+			extract(x.Left, model.CIStr{})
+			if x.Right != nil {
+				extract(x.Right, model.CIStr{})
+			}
+			if x.On != nil {
+				conds = append(conds, x.On.Expr)
+			}
+		case *ast.TableSource:
+			extract(x.Source, x.AsName)
+		case *ast.TableName:
+			tblInfo := x.TableInfo
+			if tblInfo == nil || tblInfo.IsView() || tblInfo.IsSequence() || tblInfo.TempTableType != model.TempTableNone ||
+				util.IsMemOrSysDB(x.Schema.L) {
+				return
+			}
+			alias := asName
+			if alias.L == "" {
+				alias = x.Name
+			}
+			sources = append(sources, &adviseTableSource{dbName: x.Schema, tblInfo: tblInfo, alias: alias})
+		}
+	}
+	extract(sel.From.TableRefs, model.CIStr{})
+	if len(sources) == 0 {
+		return
+	}
+	if sel.Where != nil {
+		conds = append(conds, sel.Where)
+	}
+	for _, cond := range conds {
+		collectIndexColumnsFromCond(sources, cond)
+	}
+	if sel.GroupBy != nil {
+		collectIndexColumnsFromByItems(sources, sel.GroupBy.Items)
+	} else if sel.OrderBy != nil {
+		collectIndexColumnsFromByItems(sources, sel.OrderBy.Items)
+	}
+	c.sources = append(c.sources, sources...)
+}
+
+func collectIndexColumnsFromCond(sources []*adviseTableSource, cond ast.ExprNode) {
+	switch x := cond.(type) {
+	case *ast.ParenthesesExpr:
+		collectIndexColumnsFromCond(sources, x.Expr)
+	case *ast.BinaryOperationExpr:
+		switch x.Op {
+		case opcode.LogicAnd:
+			collectIndexColumnsFromCond(sources, x.L)
+			collectIndexColumnsFromCond(sources, x.R)
+		case opcode.EQ, opcode.NullEQ:
+			for _, expr := range []ast.ExprNode{x.L, x.R} {
+				if src, col := resolveIndexColumn(sources, expr); col != nil {
+					src.eqCols = appendIndexColumn(src.eqCols, col)
+				}
+			}
+		case opcode.LT, opcode.LE, opcode.GT, opcode.GE:
+			for _, expr := range []ast.ExprNode{x.L, x.R} {
+				if src, col := resolveIndexColumn(sources, expr); col != nil {
+					src.rangeCols = appendIndexColumn(src.rangeCols, col)
+				}
+			}
+		}
+	case *ast.PatternInExpr:
+		if src, col := resolveIndexColumn(sources, x.Expr); col != nil && !x.Not {
+			src.eqCols = appendIndexColumn(src.eqCols, col)
+		}
+	case *ast.IsNullExpr:
+		if src, col := resolveIndexColumn(sources, x.Expr); col != nil && !x.Not {
+			src.eqCols = appendIndexColumn(src.eqCols, col)
+		}
+	case *ast.BetweenExpr:
+		if src, col := resolveIndexColumn(sources, x.Expr); col != nil && !x.Not {
+			src.rangeCols = appendIndexColumn(src.rangeCols, col)
+		}
+	case *ast.PatternLikeExpr:
+		if src, col := resolveIndexColumn(sources, x.Expr); col != nil && !x.Not {
+			src.rangeCols = appendIndexColumn(src.rangeCols, col)
+		}
+	}
+}
+
+// collectIndexColumnsFromByItems collects the GROUP BY or ORDER BY columns, which can be used by
+// an index only if all of them belong to the same table.
+func collectIndexColumnsFromByItems(sources []*adviseTableSource, items []*ast.ByItem) {
+	var (
+		orderSrc  *adviseTableSource
+		orderCols []*model.ColumnInfo
+	)
+	for _, item := range items {
+		src, col := resolveIndexColumn(sources, item.Expr)
+		if col == nil || (orderSrc != nil && orderSrc != src) {
+			return
+		}
+		orderSrc = src
+		orderCols = appendIndexColumn(orderCols, col)
+	}
+	if orderSrc != nil {
+		orderSrc.orderCols = orderCols
+	}
+}
+
+// resolveIndexColumn finds the table source and the indexable column which the expression refers to.
+func resolveIndexColumn(sources []*adviseTableSource, expr ast.ExprNode) (*adviseTableSource, *model.ColumnInfo) {
+	for {
+		p, ok := expr.(*ast.ParenthesesExpr)
+		if !ok {
+			break
+		}
+		expr = p.Expr
+	}
+	colExpr, ok := expr.(*ast.ColumnNameExpr)
+	if !ok {
+		return nil, nil
+	}
+	name := colExpr.Name
+	var (
+		resSrc *adviseTableSource
+		resCol *model.ColumnInfo
+	)
+	for _, src := range sources {
+		if name.Table.L != "" && (name.Table.L != src.alias.L || (name.Schema.L != "" && name.Schema.L != src.dbName.L)) {
+			continue
+		}
+		col := model.FindColumnInfo(src.tblInfo.Columns, name.Name.L)
+		if col == nil || col.State != model.StatePublic {
+			continue
+		}
+		if resCol != nil {
+			// The column is ambiguous.
+			return nil, nil
+		}
+		resSrc, resCol = src, col
+	}
+	if resCol == nil || !isIndexableColumn(resCol) {
+		return nil, nil
+	}
+	return resSrc, resCol
+}
+
+// isIndexableColumn checks whether the column can be indexed without a prefix length.
+func isIndexableColumn(col *model.ColumnInfo) bool {
+	switch col.Tp {
+	case mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob, mysql.TypeJSON, mysql.TypeGeometry:
+		return false
+	}
+	return true
+}
+
+// IndexAdvice represents the index advice, every row is an advised index with a query it benefits.
+// It implements the sqlexec.RecordSet interface.
 type IndexAdvice struct {
-	// TODO: Define index advice data structure and implements the ResultSet interface. It will be done in another PR
+	fields       []*ast.ResultField
+	fieldTypes   []*types.FieldType
+	maxChunkSize int
+	chk          *chunk.Chunk
+	idx          int
+}
+
+var indexAdviceColumns = []struct {
+	name string
+	tp   byte
+}{
+	{"Table", mysql.TypeVarchar},
+	{"Index_Statement", mysql.TypeVarchar},
+	{"Query", mysql.TypeVarchar},
+	{"Original_Cost", mysql.TypeDouble},
+	{"Advised_Cost", mysql.TypeDouble},
+	{"Cost_Reduction", mysql.TypeDouble},
+}
+
+func newIndexAdvice(sctx sessionctx.Context) *IndexAdvice {
+	fields := make([]*ast.ResultField, 0, len(indexAdviceColumns))
+	fieldTypes := make([]*types.FieldType, 0, len(indexAdviceColumns))
+	for _, col := range indexAdviceColumns {
+		fieldType := types.NewFieldType(col.tp)
+		if col.tp == mysql.TypeVarchar {
+			fieldType.Flen = mysql.MaxBlobWidth
+		}
+		fieldTypes = append(fieldTypes, fieldType)
+		fields = append(fields, &ast.ResultField{
+			Column:       &model.ColumnInfo{Name: model.NewCIStr(col.name), FieldType: *fieldType},
+			ColumnAsName: model.NewCIStr(col.name),
+		})
+	}
+	maxChunkSize := sctx.GetSessionVars().MaxChunkSize
+	return &IndexAdvice{
+		fields:       fields,
+		fieldTypes:   fieldTypes,
+		maxChunkSize: maxChunkSize,
+		chk:          chunk.New(fieldTypes, maxChunkSize, maxChunkSize),
+	}
+}
+
+// Fields implements the sqlexec.RecordSet interface.
+func (a *IndexAdvice) Fields() []*ast.ResultField {
+	return a.fields
+}
+
+// Next implements the sqlexec.RecordSet interface.
+func (a *IndexAdvice) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for !req.IsFull() && a.idx < a.chk.NumRows() {
+		req.AppendRow(a.chk.GetRow(a.idx))
+		a.idx++
+	}
+	return nil
+}
+
+// NewChunk implements the sqlexec.RecordSet interface.
+func (a *IndexAdvice) NewChunk() *chunk.Chunk {
+	return chunk.New(a.fieldTypes, a.maxChunkSize, a.maxChunkSize)
+}
+
+// Close implements the sqlexec.RecordSet interface.
+func (a *IndexAdvice) Close() error {
+	return nil
 }
 
 // IndexAdviseVarKeyType is a dummy type to avoid naming collision in context.
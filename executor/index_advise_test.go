@@ -14,9 +14,11 @@
 package executor_test
 
 import (
+	"context"
 	"os"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/util/testkit"
@@ -65,3 +67,97 @@ func (s *testSuite1) TestIndexAdvise(c *C) {
 	c.Assert(ia.MaxIndexNum.PerDB, Equals, uint64(5))
 
 }
+
+func (s *testSuite1) TestIndexAdviseWhatIf(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (a int, b int, c int, d varchar(20), e blob, key idx_c (c))")
+	tk.MustExec("create table t2 (a int primary key, b int)")
+
+	workload := "select * from t1 where a = 1;\n" +
+		"select * from t1 where a = 1 and b > 10;\n" +
+		"select * from t1 where c = 1;\n" +
+		"select * from t1 where e = 'x';\n" +
+		"select * from t1, t2 where t1.b = t2.b and t2.a = 2;\n" +
+		"delete from t1 where d = 'x';\n" +
+		"select * from t_not_exist;\n"
+	tk.MustExec("index advise local infile '/tmp/index_advise.sql'")
+	ctx := tk.Se.(sessionctx.Context)
+	ia, ok := ctx.Value(executor.IndexAdviseVarKey).(*executor.IndexAdviseInfo)
+	c.Assert(ok, IsTrue)
+	ctx.SetValue(executor.IndexAdviseVarKey, nil)
+	c.Assert(ia.GetIndexAdvice(context.Background(), []byte(workload)), IsNil)
+	c.Assert(ctx.GetSessionVars().HypoIndexes, IsNil)
+	c.Assert(ctx.GetSessionVars().CurrentDB, Equals, "test")
+	warns := ctx.GetSessionVars().StmtCtx.GetWarnings()
+	c.Assert(warns, HasLen, 1)
+	c.Assert(warns[0].Err.Error(), Matches, "Index Advise: skip the query 'select \\* from t_not_exist'.*")
+
+	// The index on `c` already exists and the blob column `e` can't be indexed without a prefix.
+	rows := indexAdviceRows(c, ia.Result)
+	c.Assert(rows, HasLen, 4)
+	expected := [][]string{
+		{"CREATE INDEX `idx_a` ON `test`.`t1` (`a`)", "select * from t1 where a = 1"},
+		{"CREATE INDEX `idx_b` ON `test`.`t1` (`b`)", "select * from t1, t2 where t1.b = t2.b and t2.a = 2"},
+		{"CREATE INDEX `idx_d` ON `test`.`t1` (`d`)", "delete from t1 where d = 'x'"},
+		{"CREATE INDEX `idx_a_b` ON `test`.`t1` (`a`, `b`)", "select * from t1 where a = 1 and b > 10"},
+	}
+	for i, row := range rows {
+		c.Assert(row[0], Equals, "test.t1")
+		c.Assert(row[1], Equals, expected[i][0])
+		c.Assert(row[2], Equals, expected[i][1])
+		c.Assert(row[4].(float64), Less, row[3].(float64))
+		c.Assert(row[5].(float64), Greater, 0.0)
+	}
+
+	// The number of advised indexes is limited by MAX_IDXNUM.
+	tk.MustExec("index advise local infile '/tmp/index_advise.sql' max_idxnum per_table 1 per_db 2")
+	ia, ok = ctx.Value(executor.IndexAdviseVarKey).(*executor.IndexAdviseInfo)
+	c.Assert(ok, IsTrue)
+	ctx.SetValue(executor.IndexAdviseVarKey, nil)
+	c.Assert(ia.GetIndexAdvice(context.Background(), []byte(workload)), IsNil)
+	rows = indexAdviceRows(c, ia.Result)
+	c.Assert(rows, HasLen, 2)
+	c.Assert(rows[0][1], Equals, "CREATE INDEX `idx_a` ON `test`.`t1` (`a`)")
+	c.Assert(rows[0][2], Equals, "select * from t1 where a = 1")
+	c.Assert(rows[1][1], Equals, "CREATE INDEX `idx_a` ON `test`.`t1` (`a`)")
+	c.Assert(rows[1][2], Equals, "select * from t1 where a = 1 and b > 10")
+
+	// Take the workload from the statement summary.
+	tk.MustExec("set @@tidb_enable_stmt_summary = 1")
+	defer tk.MustExec("set @@tidb_enable_stmt_summary = default")
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil), IsTrue)
+	for i := 0; i < 3; i++ {
+		tk.MustQuery("select * from t1 where d = 'x' and a > 1")
+	}
+	tk.MustExec("index advise from statements_summary")
+	ia, ok = ctx.Value(executor.IndexAdviseVarKey).(*executor.IndexAdviseInfo)
+	c.Assert(ok, IsTrue)
+	ctx.SetValue(executor.IndexAdviseVarKey, nil)
+	c.Assert(ia.FromStmtSummary, IsTrue)
+	c.Assert(ia.GetIndexAdvice(context.Background(), nil), IsNil)
+	rows = indexAdviceRows(c, ia.Result)
+	c.Assert(rows, HasLen, 1)
+	c.Assert(rows[0][1], Equals, "CREATE INDEX `idx_d_a` ON `test`.`t1` (`d`, `a`)")
+	c.Assert(rows[0][2], Equals, "select * from t1 where d = 'x' and a > 1")
+
+	// A file named statements_summary is just a file.
+	_, err := tk.Exec("index advise infile 'statements_summary'")
+	c.Assert(err, ErrorMatches, ".*don't support load file without local field")
+}
+
+func indexAdviceRows(c *C, rs *executor.IndexAdvice) [][]interface{} {
+	var rows [][]interface{}
+	chk := rs.NewChunk()
+	for {
+		c.Assert(rs.Next(context.Background(), chk), IsNil)
+		if chk.NumRows() == 0 {
+			return rows
+		}
+		for i := 0; i < chk.NumRows(); i++ {
+			row := chk.GetRow(i)
+			rows = append(rows, []interface{}{row.GetString(0), row.GetString(1), row.GetString(2), row.GetFloat64(3), row.GetFloat64(4), row.GetFloat64(5)})
+		}
+	}
+}
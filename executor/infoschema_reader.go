@@ -123,6 +123,8 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			err = e.setDataForClusterProcessList(sctx)
 		case infoschema.TableUserPrivileges:
 			e.setDataFromUserPrivileges(sctx)
+		case infoschema.TableColumnPrivileges:
+			e.setDataFromColumnPrivileges(sctx)
 		case infoschema.TableTiKVRegionStatus:
 			err = e.setDataForTiKVRegionStatus(sctx)
 		case infoschema.TableTiKVRegionPeers:
@@ -1218,6 +1220,24 @@ func (e *memtableRetriever) setDataFromUserPrivileges(ctx sessionctx.Context) {
 	e.rows = pm.UserPrivilegesTable()
 }
 
+// setDataFromColumnPrivileges fills the column privileges, only the privileges of the current user are
+// visible unless the user has the SELECT privilege on the mysql schema.
+func (e *memtableRetriever) setDataFromColumnPrivileges(ctx sessionctx.Context) {
+	pm := privilege.GetPrivilegeManager(ctx)
+	rows := pm.ColumnPrivilegesTable()
+	sessVars := ctx.GetSessionVars()
+	if sessVars.User == nil || pm.RequestVerification(sessVars.ActiveRoles, mysql.SystemDB, "", "", mysql.SelectPriv) {
+		e.rows = rows
+		return
+	}
+	grantee := fmt.Sprintf("'%s'@'%s'", sessVars.User.AuthUsername, sessVars.User.AuthHostname)
+	for _, row := range rows {
+		if row[0].GetString() == grantee {
+			e.rows = append(e.rows, row)
+		}
+	}
+}
+
 func (e *memtableRetriever) setDataForMetricTables(ctx sessionctx.Context) {
 	tables := make([]string, 0, len(infoschema.MetricTableMap))
 	for name := range infoschema.MetricTableMap {
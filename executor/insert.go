@@ -87,7 +87,10 @@ func (e *InsertExec) exec(ctx context.Context, rows [][]types.Datum) error {
 		e.collectRuntimeStatsEnabled()
 		start := time.Now()
 		for i, row := range rows {
-			var err error
+			_, err := e.checkRowConstraints(ctx, row)
+			if err != nil {
+				return err
+			}
 			sizeHintStep := int(sessVars.ShardAllocateStep)
 			if i%sizeHintStep == 0 {
 				sizeHint := sizeHintStep
@@ -192,7 +195,7 @@ func (e *InsertExec) updateDupRow(ctx context.Context, idxInBatch int, txn kv.Tr
 	}
 
 	err = e.doDupRowUpdate(ctx, handle, oldRow, row.row, e.OnDuplicate)
-	if e.ctx.GetSessionVars().StmtCtx.DupKeyAsWarning && kv.ErrKeyExists.Equal(err) {
+	if e.ctx.GetSessionVars().StmtCtx.DupKeyAsWarning && (kv.ErrKeyExists.Equal(err) || table.ErrCheckConstraintViolated.Equal(err) || ErrNoReferencedRow2.Equal(err)) {
 		e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
 		return nil
 	}
@@ -279,7 +282,14 @@ func (e *InsertExec) batchUpdateDupRows(ctx context.Context, newRows [][]types.D
 		// and key-values should be filled back to dupOldRowValues for the further row check,
 		// due to there may be duplicate keys inside the insert statement.
 		if newRows[i] != nil {
-			err := e.addRecord(ctx, newRows[i])
+			skip, err := e.checkRowConstraints(ctx, newRows[i])
+			if err != nil {
+				return err
+			}
+			if skip {
+				continue
+			}
+			err = e.addRecord(ctx, newRows[i])
 			if err != nil {
 				return err
 			}
@@ -389,7 +399,7 @@ func (e *InsertExec) doDupRowUpdate(ctx context.Context, handle kv.Handle, oldRo
 	}
 
 	newData := e.row4Update[:len(oldRow)]
-	_, err := updateRecord(ctx, e.ctx, handle, oldRow, newData, assignFlag, e.Table, true, e.memTracker)
+	_, err := updateRecord(ctx, e.ctx, handle, oldRow, newData, assignFlag, e.Table, e.checkConstraints, e.fkChecker, e.rowPolicy, true, e.memTracker)
 	if err != nil {
 		return err
 	}
@@ -64,6 +64,13 @@ type InsertValues struct {
 
 	GenExprs []expression.Expression
 
+	// checkConstraints are the check constraints which should be satisfied by the written rows.
+	checkConstraints []*table.Constraint
+	// fkChecker checks the foreign keys of the written rows, it's nil if nothing needs to be checked.
+	fkChecker *foreignKeyChecker
+	// rowPolicy checks the row-level security policies of the written rows, it's nil if nothing needs to be checked.
+	rowPolicy *rowPolicyChecker
+
 	insertColumns []*table.Column
 
 	// colDefaultVals is used to store casted default value.
@@ -1066,7 +1073,13 @@ func (e *InsertValues) batchCheckAndInsert(ctx context.Context, rows [][]types.D
 		if r.ignored {
 			continue
 		}
-		skip := false
+		skip, err := e.checkRowConstraints(ctx, rows[i])
+		if err != nil {
+			return err
+		}
+		if skip {
+			continue
+		}
 		if r.handleKey != nil {
 			_, err := txn.Get(ctx, r.handleKey.newKey)
 			if err == nil {
@@ -1107,6 +1120,27 @@ func (e *InsertValues) batchCheckAndInsert(ctx context.Context, rows [][]types.D
 	return nil
 }
 
+// checkRowConstraints checks whether the row satisfies the check constraints, the foreign keys and the row-level
+// security policies. If errors are ignored, the violation is appended as a warning and the row should be skipped.
+func (e *InsertValues) checkRowConstraints(ctx context.Context, row []types.Datum) (skip bool, err error) {
+	err = table.CheckRowConstraints(e.ctx, e.checkConstraints, row)
+	if err == nil {
+		err = e.fkChecker.checkParentsExist(ctx, e.ctx, row, nil)
+	}
+	if err == nil {
+		err = e.rowPolicy.checkRow(e.ctx, row)
+	}
+	if err == nil {
+		return false, nil
+	}
+	sc := e.ctx.GetSessionVars().StmtCtx
+	if sc.DupKeyAsWarning && (table.ErrCheckConstraintViolated.Equal(err) || ErrNoReferencedRow2.Equal(err) || ErrRowPolicyViolated.Equal(err)) {
+		sc.AppendWarning(err)
+		return true, nil
+	}
+	return false, err
+}
+
 func (e *InsertValues) addRecord(ctx context.Context, row []types.Datum) error {
 	return e.addRecordWithAutoIDHint(ctx, row, 0)
 }
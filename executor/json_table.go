@@ -0,0 +1,261 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/expression"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
+)
+
+// jsonTableColumn is a column of JSON_TABLE with its offset in the output row.
+type jsonTableColumn struct {
+	*plannercore.JSONTableColumn
+
+	offset int
+	// nested are the columns of a NESTED PATH clause.
+	nested []*jsonTableColumn
+}
+
+// buildJSONTableColumns assigns the offsets to the columns in the depth-first order, which is the
+// order of the columns in the schema of JSON_TABLE.
+func buildJSONTableColumns(cols []*plannercore.JSONTableColumn, offset *int) []*jsonTableColumn {
+	result := make([]*jsonTableColumn, 0, len(cols))
+	for _, col := range cols {
+		c := &jsonTableColumn{JSONTableColumn: col}
+		if col.Tp == plannercore.JSONTableColumnNested {
+			c.nested = buildJSONTableColumns(col.Columns, offset)
+		} else {
+			c.offset = *offset
+			*offset++
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// JSONTableExec is the executor of the JSON_TABLE table function. It's usually the inner side of an
+// Apply and is re-opened for every outer row, so all the rows are generated when it's opened.
+type JSONTableExec struct {
+	baseExecutor
+
+	expr    expression.Expression
+	info    *plannercore.JSONTableInfo
+	columns []*jsonTableColumn
+
+	// sc is used to convert the JSON values to the types of the columns, it reports the truncation as
+	// an error so that the ON ERROR clause could take effect.
+	sc     *stmtctx.StatementContext
+	row    []types.Datum
+	result *chunk.Chunk
+	cursor int
+
+	memTracker *memory.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *JSONTableExec) Open(ctx context.Context) error {
+	if e.memTracker == nil {
+		e.memTracker = memory.NewTracker(e.id, -1)
+		e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	}
+	if e.sc == nil {
+		e.sc = &stmtctx.StatementContext{TimeZone: e.ctx.GetSessionVars().Location()}
+		e.row = make([]types.Datum, e.schema.Len())
+		e.result = newFirstChunk(e)
+	}
+	e.result.Reset()
+	e.cursor = 0
+	doc, isNull, err := e.expr.EvalJSON(e.ctx, chunk.Row{})
+	if err != nil {
+		return err
+	}
+	if !isNull {
+		for i, match := range doc.ExtractAll(e.info.Path) {
+			if err = e.fillColumns(e.columns, match, int64(i+1)); err != nil {
+				return err
+			}
+		}
+	}
+	e.memTracker.Consume(e.result.MemoryUsage() - e.memTracker.BytesConsumed())
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *JSONTableExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.cursor >= e.result.NumRows() {
+		return nil
+	}
+	end := e.cursor + req.RequiredRows()
+	if end > e.result.NumRows() {
+		end = e.result.NumRows()
+	}
+	req.Append(e.result, e.cursor, end)
+	e.cursor = end
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *JSONTableExec) Close() error {
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	}
+	return nil
+}
+
+// fillColumns fills the columns by the value matched by the path of their level, then emits the rows. The
+// sibling NESTED PATH clauses emit their rows in turn, the columns of the other siblings are NULL. If none of
+// them matches anything, a row whose nested columns are NULL is emitted.
+func (e *JSONTableExec) fillColumns(cols []*jsonTableColumn, match json.BinaryJSON, ordinality int64) error {
+	for _, col := range cols {
+		var err error
+		switch col.Tp {
+		case plannercore.JSONTableColumnNested:
+			e.setNull(col.nested)
+		case plannercore.JSONTableColumnOrdinality:
+			e.row[col.offset].SetInt64(ordinality)
+		case plannercore.JSONTableColumnExistsPath:
+			exists := types.NewIntDatum(0)
+			if len(match.ExtractAll(col.Path)) > 0 {
+				exists.SetInt64(1)
+			}
+			e.row[col.offset], err = exists.ConvertTo(e.sc, &col.FieldType)
+		default:
+			err = e.fillPathColumn(col, match)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	emitted := false
+	for _, col := range cols {
+		if col.Tp != plannercore.JSONTableColumnNested {
+			continue
+		}
+		for i, nestedMatch := range match.ExtractAll(col.Path) {
+			if err := e.fillColumns(col.nested, nestedMatch, int64(i+1)); err != nil {
+				return err
+			}
+			emitted = true
+		}
+		e.setNull(col.nested)
+	}
+	if !emitted {
+		for i := range e.row {
+			e.result.AppendDatum(i, &e.row[i])
+		}
+	}
+	return nil
+}
+
+func (e *JSONTableExec) setNull(cols []*jsonTableColumn) {
+	for _, col := range cols {
+		if col.Tp == plannercore.JSONTableColumnNested {
+			e.setNull(col.nested)
+		} else {
+			e.row[col.offset].SetNull()
+		}
+	}
+}
+
+// fillPathColumn fills the PATH column, the ON EMPTY clause takes effect if the path matches nothing, and the
+// ON ERROR clause takes effect if the matched value can't be stored in the column.
+func (e *JSONTableExec) fillPathColumn(col *jsonTableColumn, match json.BinaryJSON) error {
+	values := match.ExtractAll(col.Path)
+	if len(values) == 0 {
+		switch col.OnEmpty.Tp {
+		case plannercore.JSONTableOnResponseError:
+			return ErrMissingJSONTableValue.GenWithStackByArgs(col.Name.O)
+		case plannercore.JSONTableOnResponseDefault:
+			d, err := e.convertJSON(col, col.OnEmpty.Default)
+			e.row[col.offset] = d
+			return err
+		default:
+			e.row[col.offset].SetNull()
+			return nil
+		}
+	}
+
+	if len(values) > 1 && col.FieldType.Tp == mysql.TypeJSON {
+		// The multiple values are wrapped as an array, which is the same as JSON_EXTRACT.
+		wrapped, _ := match.Extract([]json.PathExpression{col.Path})
+		e.row[col.offset] = types.NewJSONDatum(wrapped)
+		return nil
+	}
+	var (
+		d   types.Datum
+		err error
+	)
+	if len(values) > 1 {
+		err = ErrWrongJSONTableValue.GenWithStackByArgs(col.Name.O, e.info.Name.O)
+	} else {
+		d, err = e.convertJSON(col, values[0])
+	}
+	if err == nil {
+		e.row[col.offset] = d
+		return nil
+	}
+	switch col.OnError.Tp {
+	case plannercore.JSONTableOnResponseError:
+		return err
+	case plannercore.JSONTableOnResponseDefault:
+		d, err = e.convertJSON(col, col.OnError.Default)
+		e.row[col.offset] = d
+		return err
+	default:
+		e.row[col.offset].SetNull()
+		return nil
+	}
+}
+
+// convertJSON converts the JSON value to the type of the column.
+func (e *JSONTableExec) convertJSON(col *jsonTableColumn, val json.BinaryJSON) (types.Datum, error) {
+	if col.FieldType.Tp == mysql.TypeJSON {
+		return types.NewJSONDatum(val), nil
+	}
+	var d types.Datum
+	switch val.TypeCode {
+	case json.TypeCodeObject, json.TypeCodeArray:
+		return d, ErrWrongJSONTableValue.GenWithStackByArgs(col.Name.O, e.info.Name.O)
+	case json.TypeCodeLiteral:
+		switch val.Value[0] {
+		case json.LiteralNil:
+			return d, nil
+		case json.LiteralTrue:
+			d.SetInt64(1)
+		default:
+			d.SetInt64(0)
+		}
+	case json.TypeCodeInt64:
+		d.SetInt64(val.GetInt64())
+	case json.TypeCodeUint64:
+		d.SetUint64(val.GetUint64())
+	case json.TypeCodeFloat64:
+		d.SetFloat64(val.GetFloat64())
+	case json.TypeCodeString:
+		d.SetString(string(val.GetString()), col.FieldType.Collate)
+	default:
+		return d, errors.Errorf("unsupported JSON type code %d", val.TypeCode)
+	}
+	return d.ConvertTo(e.sc, &col.FieldType)
+}
@@ -0,0 +1,188 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/expression"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/mock"
+)
+
+func buildJSONTableExecForTest(c *C, expr expression.Expression, info *plannercore.JSONTableInfo) *JSONTableExec {
+	sctx := mock.NewContext()
+	offset := 0
+	columns := buildJSONTableColumns(info.Columns, &offset)
+	schema := expression.NewSchema()
+	var appendCols func(cols []*plannercore.JSONTableColumn)
+	appendCols = func(cols []*plannercore.JSONTableColumn) {
+		for _, col := range cols {
+			if col.Tp == plannercore.JSONTableColumnNested {
+				appendCols(col.Columns)
+				continue
+			}
+			schema.Append(&expression.Column{Index: schema.Len(), RetType: col.FieldType.Clone()})
+		}
+	}
+	appendCols(info.Columns)
+	c.Assert(offset, Equals, schema.Len())
+	return &JSONTableExec{
+		baseExecutor: newBaseExecutor(sctx, schema, 0),
+		expr:         expr,
+		info:         info,
+		columns:      columns,
+	}
+}
+
+func fetchJSONTableRows(c *C, e *JSONTableExec) []string {
+	ctx := context.Background()
+	c.Assert(e.Open(ctx), IsNil)
+	var rows []string
+	chk := newFirstChunk(e)
+	for {
+		c.Assert(e.Next(ctx, chk), IsNil)
+		if chk.NumRows() == 0 {
+			break
+		}
+		it := chunk.NewIterator4Chunk(chk)
+		for row := it.Begin(); row != it.End(); row = it.Next() {
+			strs := make([]string, 0, chk.NumCols())
+			for _, d := range row.GetDatumRow(retTypes(e)) {
+				if d.IsNull() {
+					strs = append(strs, "<nil>")
+					continue
+				}
+				str, err := d.ToString()
+				c.Assert(err, IsNil)
+				strs = append(strs, str)
+			}
+			rows = append(rows, strings.Join(strs, " "))
+		}
+	}
+	c.Assert(e.Close(), IsNil)
+	return rows
+}
+
+func mustParseJSONPath(c *C, path string) json.PathExpression {
+	pathExpr, err := json.ParseJSONPathExpr(path)
+	c.Assert(err, IsNil)
+	return pathExpr
+}
+
+func (s *pkgTestSuite) TestJSONTable(c *C) {
+	doc, err := json.ParseBinaryFromString(`{"a": [{"x": 1, "y": [10, 20]}, {"x": "2", "z": true}, {"x": [1]}]}`)
+	c.Assert(err, IsNil)
+	zero, err := json.ParseBinaryFromString("0")
+	c.Assert(err, IsNil)
+	intType, jsonType := *types.NewFieldType(mysql.TypeLonglong), *types.NewFieldType(mysql.TypeJSON)
+	// JSON_TABLE(doc, '$.a[*]' COLUMNS (
+	//   id FOR ORDINALITY,
+	//   x INT PATH '$.x' DEFAULT '0' ON ERROR,
+	//   has_z INT EXISTS PATH '$.z',
+	//   NESTED PATH '$.y[*]' COLUMNS (y INT PATH '$', yid FOR ORDINALITY),
+	//   NESTED PATH '$.x' COLUMNS (xj JSON PATH '$'))) AS jt
+	info := &plannercore.JSONTableInfo{
+		Name: model.NewCIStr("jt"),
+		Path: mustParseJSONPath(c, "$.a[*]"),
+		Columns: []*plannercore.JSONTableColumn{
+			{Tp: plannercore.JSONTableColumnOrdinality, Name: model.NewCIStr("id"), FieldType: intType},
+			{Tp: plannercore.JSONTableColumnPath, Name: model.NewCIStr("x"), FieldType: intType, Path: mustParseJSONPath(c, "$.x"),
+				OnError: plannercore.JSONTableOnResponse{Tp: plannercore.JSONTableOnResponseDefault, Default: zero}},
+			{Tp: plannercore.JSONTableColumnExistsPath, Name: model.NewCIStr("has_z"), FieldType: intType, Path: mustParseJSONPath(c, "$.z")},
+			{Tp: plannercore.JSONTableColumnNested, Path: mustParseJSONPath(c, "$.y[*]"), Columns: []*plannercore.JSONTableColumn{
+				{Tp: plannercore.JSONTableColumnPath, Name: model.NewCIStr("y"), FieldType: intType, Path: mustParseJSONPath(c, "$")},
+				{Tp: plannercore.JSONTableColumnOrdinality, Name: model.NewCIStr("yid"), FieldType: intType},
+			}},
+			{Tp: plannercore.JSONTableColumnNested, Path: mustParseJSONPath(c, "$.x"), Columns: []*plannercore.JSONTableColumn{
+				{Tp: plannercore.JSONTableColumnPath, Name: model.NewCIStr("xj"), FieldType: jsonType, Path: mustParseJSONPath(c, "$")},
+			}},
+		},
+	}
+	e := buildJSONTableExecForTest(c, &expression.Constant{Value: types.NewJSONDatum(doc), RetType: &jsonType}, info)
+	c.Assert(fetchJSONTableRows(c, e), DeepEquals, []string{
+		"1 1 0 10 1 <nil>",
+		"1 1 0 20 2 <nil>",
+		"1 1 0 <nil> <nil> 1",
+		"2 2 1 <nil> <nil> \"2\"",
+		"3 0 0 <nil> <nil> [1]",
+	})
+
+	// The JSON_TABLE on the inner side of an Apply is re-opened with the different correlated values.
+	corCol := &expression.CorrelatedColumn{Column: expression.Column{RetType: &jsonType}, Data: new(types.Datum)}
+	e = buildJSONTableExecForTest(c, corCol, &plannercore.JSONTableInfo{
+		Name: model.NewCIStr("jt"),
+		Path: mustParseJSONPath(c, "$[*]"),
+		Columns: []*plannercore.JSONTableColumn{
+			{Tp: plannercore.JSONTableColumnPath, Name: model.NewCIStr("v"), FieldType: intType, Path: mustParseJSONPath(c, "$")},
+		},
+	})
+	for _, ca := range []struct {
+		doc  string
+		rows []string
+	}{
+		{`[1, 2, 3]`, []string{"1", "2", "3"}},
+		{`[]`, nil},
+		{`[4, "a", {"b": 1}]`, []string{"4", "<nil>", "<nil>"}},
+		{`5`, nil},
+	} {
+		bj, err := json.ParseBinaryFromString(ca.doc)
+		c.Assert(err, IsNil)
+		corCol.Data.SetMysqlJSON(bj)
+		c.Assert(fetchJSONTableRows(c, e), DeepEquals, ca.rows, Commentf("doc: %s", ca.doc))
+	}
+	corCol.Data.SetNull()
+	c.Assert(fetchJSONTableRows(c, e), IsNil)
+
+	// ON EMPTY and ON ERROR.
+	info = &plannercore.JSONTableInfo{
+		Name: model.NewCIStr("jt"),
+		Path: mustParseJSONPath(c, "$[*]"),
+		Columns: []*plannercore.JSONTableColumn{
+			{Tp: plannercore.JSONTableColumnPath, Name: model.NewCIStr("a"), FieldType: intType, Path: mustParseJSONPath(c, "$.a"),
+				OnEmpty: plannercore.JSONTableOnResponse{Tp: plannercore.JSONTableOnResponseError}},
+			{Tp: plannercore.JSONTableColumnPath, Name: model.NewCIStr("b"), FieldType: intType, Path: mustParseJSONPath(c, "$.b"),
+				OnEmpty: plannercore.JSONTableOnResponse{Tp: plannercore.JSONTableOnResponseDefault, Default: zero},
+				OnError: plannercore.JSONTableOnResponse{Tp: plannercore.JSONTableOnResponseError}},
+		},
+	}
+	for _, ca := range []struct {
+		doc  string
+		rows []string
+		err  *terror.Error
+	}{
+		{`[{"a": 1, "b": 2}, {"a": 3}]`, []string{"1 2", "3 0"}, nil},
+		{`[{"a": 1}, {"b": 2}]`, nil, ErrMissingJSONTableValue},
+		{`[{"a": 1, "b": [2]}]`, nil, ErrWrongJSONTableValue},
+		{`[{"a": 1, "b": "x"}]`, nil, types.ErrTruncatedWrongVal},
+	} {
+		bj, err := json.ParseBinaryFromString(ca.doc)
+		c.Assert(err, IsNil)
+		e = buildJSONTableExecForTest(c, &expression.Constant{Value: types.NewJSONDatum(bj), RetType: &jsonType}, info)
+		if ca.err == nil {
+			c.Assert(fetchJSONTableRows(c, e), DeepEquals, ca.rows, Commentf("doc: %s", ca.doc))
+			continue
+		}
+		err = e.Open(context.Background())
+		c.Assert(ca.err.Equal(err), IsTrue, Commentf("doc: %s, err: %v", ca.doc, err))
+	}
+}
@@ -19,9 +19,11 @@ import (
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/distsql"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	driver "github.com/pingcap/tidb/store/driver/txn"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
@@ -324,6 +326,18 @@ func iterTxnMemBuffer(ctx sessionctx.Context, kvRanges []kv.KeyRange, fn process
 	}
 	for _, rg := range kvRanges {
 		iter := txn.GetMemBuffer().SnapshotIter(rg.StartKey, rg.EndKey)
+		// The committed data of local temporary tables is kept in the session rather than TiKV,
+		// so it's merged with the transaction's mem-buffer here.
+		if data := getTemporaryTableData(ctx, rg.StartKey); data != nil {
+			snapIter, err := data.Iter(rg.StartKey, rg.EndKey)
+			if err != nil {
+				return err
+			}
+			iter, err = driver.NewUnionIter(iter, snapIter, false)
+			if err != nil {
+				return err
+			}
+		}
 		for ; iter.Valid(); err = iter.Next() {
 			if err != nil {
 				return err
@@ -341,6 +355,20 @@ func iterTxnMemBuffer(ctx sessionctx.Context, kvRanges []kv.KeyRange, fn process
 	return nil
 }
 
+// getTemporaryTableData returns the data of the local temporary tables in the session
+// if the key belongs to one of them, otherwise it returns nil.
+func getTemporaryTableData(ctx sessionctx.Context, key kv.Key) kv.MemBuffer {
+	sessVars := ctx.GetSessionVars()
+	localTempTables, ok := sessVars.LocalTemporaryTables.(*infoschema.LocalTemporaryTables)
+	if !ok || sessVars.TemporaryTableData == nil {
+		return nil
+	}
+	if _, ok := localTempTables.TableByID(tablecodec.DecodeTableID(key)); !ok {
+		return nil
+	}
+	return sessVars.TemporaryTableData
+}
+
 func reverseDatumSlice(rows [][]types.Datum) {
 	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
 		rows[i], rows[j] = rows[j], rows[i]
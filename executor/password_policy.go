@@ -0,0 +1,306 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+const (
+	// passwordHistoryTable stores the previous passwords of the accounts.
+	passwordHistoryTable = "password_history"
+	// loginFailuresTable stores the consecutive failed logins of the accounts.
+	loginFailuresTable = "login_failures"
+)
+
+// passwordOrLockSetting is the account setting specified by the password and lock options of
+// `CREATE USER` and `ALTER USER`, the last one wins if an option is specified more than once.
+type passwordOrLockSetting struct {
+	expireNow bool
+	// lifetimeSpecified indicates the password lifetime is specified, a nil lifetime means
+	// using the global default_password_lifetime and 0 means never expire.
+	lifetimeSpecified bool
+	lifetime          interface{}
+	// lockAccount is "Y" or "N" if the account is locked or unlocked.
+	lockAccount string
+	// reuseHistory and reuseTime are the password reuse policy of the account, nil means using
+	// the global password_history and password_reuse_interval.
+	reuseHistorySpecified bool
+	reuseHistory          interface{}
+	reuseTimeSpecified    bool
+	reuseTime             interface{}
+	// passwordLockTime is the days the account is blocked after failedLoginAttempts consecutive
+	// failed logins, -1 means the account is blocked until it's unlocked.
+	failedLoginAttemptsSpecified bool
+	failedLoginAttempts          int64
+	passwordLockTimeSpecified    bool
+	passwordLockTime             int64
+}
+
+func newPasswordOrLockSetting(opts []*ast.PasswordOrLockOption) (*passwordOrLockSetting, error) {
+	setting := &passwordOrLockSetting{}
+	for _, opt := range opts {
+		switch opt.Type {
+		case ast.PasswordExpire:
+			setting.expireNow = true
+		case ast.PasswordExpireDefault:
+			setting.lifetimeSpecified, setting.lifetime = true, nil
+		case ast.PasswordExpireNever:
+			setting.lifetimeSpecified, setting.lifetime = true, 0
+		case ast.PasswordExpireInterval:
+			if opt.Count <= 0 || opt.Count > math.MaxUint16 {
+				return nil, types.ErrWrongValue.GenWithStackByArgs("DAY", strconv.FormatInt(opt.Count, 10))
+			}
+			setting.lifetimeSpecified, setting.lifetime = true, opt.Count
+		case ast.Lock:
+			setting.lockAccount = "Y"
+		case ast.Unlock:
+			setting.lockAccount = "N"
+		case ast.PasswordHistoryDefault:
+			setting.reuseHistorySpecified, setting.reuseHistory = true, nil
+		case ast.PasswordHistory:
+			if opt.Count > math.MaxUint16 {
+				return nil, types.ErrWrongValue.GenWithStackByArgs("PASSWORD HISTORY", strconv.FormatInt(opt.Count, 10))
+			}
+			setting.reuseHistorySpecified, setting.reuseHistory = true, opt.Count
+		case ast.PasswordReuseDefault:
+			setting.reuseTimeSpecified, setting.reuseTime = true, nil
+		case ast.PasswordReuseInterval:
+			if opt.Count > math.MaxUint16 {
+				return nil, types.ErrWrongValue.GenWithStackByArgs("DAY", strconv.FormatInt(opt.Count, 10))
+			}
+			setting.reuseTimeSpecified, setting.reuseTime = true, opt.Count
+		case ast.FailedLoginAttempts:
+			if opt.Count > math.MaxInt16 {
+				return nil, types.ErrWrongValue.GenWithStackByArgs("FAILED_LOGIN_ATTEMPTS", strconv.FormatInt(opt.Count, 10))
+			}
+			setting.failedLoginAttemptsSpecified, setting.failedLoginAttempts = true, opt.Count
+		case ast.PasswordLockTime:
+			if opt.Count > math.MaxInt16 {
+				return nil, types.ErrWrongValue.GenWithStackByArgs("PASSWORD_LOCK_TIME", strconv.FormatInt(opt.Count, 10))
+			}
+			setting.passwordLockTimeSpecified, setting.passwordLockTime = true, opt.Count
+		case ast.PasswordLockTimeUnbounded:
+			setting.passwordLockTimeSpecified, setting.passwordLockTime = true, -1
+		}
+	}
+	return setting, nil
+}
+
+// formatAssignments appends the assignments of mysql.user columns for the setting to sql,
+// it returns whether anything is appended.
+func (s *passwordOrLockSetting) formatAssignments(sql *strings.Builder, credentialsChanged bool) bool {
+	var assignments []string
+	if s.expireNow {
+		assignments = append(assignments, sqlexec.MustEscapeSQL("Password_expired=%?", "Y"))
+	} else if credentialsChanged {
+		assignments = append(assignments, sqlexec.MustEscapeSQL("Password_expired=%?", "N"))
+	}
+	if credentialsChanged {
+		assignments = append(assignments, "Password_last_changed=CURRENT_TIMESTAMP()")
+	}
+	if s.lifetimeSpecified {
+		assignments = append(assignments, sqlexec.MustEscapeSQL("Password_lifetime=%?", s.lifetime))
+	}
+	if s.lockAccount != "" {
+		assignments = append(assignments, sqlexec.MustEscapeSQL("Account_locked=%?", s.lockAccount))
+	}
+	if s.reuseHistorySpecified {
+		assignments = append(assignments, sqlexec.MustEscapeSQL("Password_reuse_history=%?", s.reuseHistory))
+	}
+	if s.reuseTimeSpecified {
+		assignments = append(assignments, sqlexec.MustEscapeSQL("Password_reuse_time=%?", s.reuseTime))
+	}
+	if s.failedLoginAttemptsSpecified {
+		assignments = append(assignments, sqlexec.MustEscapeSQL("Failed_login_attempts=%?", s.failedLoginAttempts))
+	}
+	if s.passwordLockTimeSpecified {
+		assignments = append(assignments, sqlexec.MustEscapeSQL("Password_lock_time=%?", s.passwordLockTime))
+	}
+	sql.WriteString(strings.Join(assignments, ","))
+	return len(assignments) > 0
+}
+
+// passwordReusePolicy forbids reusing the latest history passwords and the passwords changed in
+// the latest interval days. The account's Password_reuse_history and Password_reuse_time in
+// mysql.user override the global password_history and password_reuse_interval.
+type passwordReusePolicy struct {
+	history  int64
+	interval int64
+}
+
+func (p passwordReusePolicy) enabled() bool {
+	return p.history > 0 || p.interval > 0
+}
+
+// reusePolicy returns the password reuse policy of a new account with the setting.
+func (s *passwordOrLockSetting) reusePolicy(global passwordReusePolicy) passwordReusePolicy {
+	policy := global
+	if history, ok := s.reuseHistory.(int64); ok {
+		policy.history = history
+	}
+	if interval, ok := s.reuseTime.(int64); ok {
+		policy.interval = interval
+	}
+	return policy
+}
+
+// resetsLoginFailures returns whether the setting resets the failed-login tracking of the account.
+func (s *passwordOrLockSetting) resetsLoginFailures() bool {
+	return s.lockAccount == "N" || s.failedLoginAttemptsSpecified || s.passwordLockTimeSpecified
+}
+
+func globalPasswordReusePolicy(ctx sessionctx.Context) (passwordReusePolicy, error) {
+	var policy passwordReusePolicy
+	for _, v := range []struct {
+		name  string
+		value *int64
+	}{
+		{variable.PasswordHistory, &policy.history},
+		{variable.PasswordReuseInterval, &policy.interval},
+	} {
+		val, err := variable.GetGlobalSystemVar(ctx.GetSessionVars(), v.name)
+		if err != nil {
+			return policy, err
+		}
+		if *v.value, err = strconv.ParseInt(val, 10, 64); err != nil {
+			return policy, err
+		}
+	}
+	return policy, nil
+}
+
+// userPasswordReusePolicy reads the policy of the account in the transaction of sctx, so it
+// sees the policy changed by the same statement.
+func userPasswordReusePolicy(sctx sessionctx.Context, name, host string) (passwordReusePolicy, error) {
+	policy, err := globalPasswordReusePolicy(sctx)
+	if err != nil {
+		return policy, err
+	}
+	rs, err := sctx.(sqlexec.SQLExecutor).ExecuteInternal(context.TODO(), `SELECT Password_reuse_history, Password_reuse_time FROM %n.%n WHERE User=%? AND Host=%?;`,
+		mysql.SystemDB, mysql.UserTable, name, host)
+	if err != nil {
+		return policy, err
+	}
+	rows, _, err := getRowsAndFields(sctx, rs)
+	if err != nil || len(rows) == 0 {
+		return policy, err
+	}
+	if !rows[0].IsNull(0) {
+		policy.history = rows[0].GetInt64(0)
+	}
+	if !rows[0].IsNull(1) {
+		policy.interval = rows[0].GetInt64(1)
+	}
+	return policy, nil
+}
+
+// checkAndRecordPasswordHistory checks the new password of the account against its password reuse policy,
+// records the password in mysql.password_history and removes the history which is no longer needed.
+// Empty passwords are not restricted. The plaintext is empty if the password is given by its hash.
+// The statements run in the transaction of sctx, so the history is updated together with the password.
+func checkAndRecordPasswordHistory(sctx sessionctx.Context, name, host, pwd, plaintext string) error {
+	policy, err := userPasswordReusePolicy(sctx, name, host)
+	if err != nil || !policy.enabled() || pwd == "" {
+		return err
+	}
+	exec := sctx.(sqlexec.SQLExecutor)
+	rs, err := exec.ExecuteInternal(context.TODO(), `SELECT Password, Password_timestamp, Password_timestamp > NOW(6) - INTERVAL %? DAY FROM %n.%n WHERE User=%? AND Host=%? ORDER BY Password_timestamp DESC;`,
+		policy.interval, mysql.SystemDB, passwordHistoryTable, name, host)
+	if err != nil {
+		return err
+	}
+	rows, _, err := getRowsAndFields(sctx, rs)
+	if err != nil {
+		return err
+	}
+	// The history is ordered from the latest, so the restricted passwords are a prefix of it.
+	for i, row := range rows {
+		if int64(i) >= policy.history && row.GetInt64(2) == 0 {
+			break
+		}
+		if isSamePassword(row.GetString(0), pwd, plaintext) {
+			return ErrCredentialsContradictToHistory.GenWithStackByArgs(name, host)
+		}
+	}
+
+	if _, err = exec.ExecuteInternal(context.TODO(), `INSERT INTO %n.%n (Host, User, Password) VALUES (%?, %?, %?);`,
+		mysql.SystemDB, passwordHistoryTable, host, name, pwd); err != nil {
+		return err
+	}
+	// The new password becomes the latest one, the history beyond the policy is useless from now on.
+	for i, row := range rows {
+		if int64(i+1) < policy.history || row.GetInt64(2) == 1 {
+			continue
+		}
+		_, err = exec.ExecuteInternal(context.TODO(), `DELETE FROM %n.%n WHERE User=%? AND Host=%? AND Password_timestamp <= %?;`,
+			mysql.SystemDB, passwordHistoryTable, name, host, row.GetTime(1).String())
+		return err
+	}
+	return nil
+}
+
+// changePassword runs updateSQL, which changes the password of the account in mysql.user, and records
+// the password history in one transaction, so the history never disagrees with the password. The password
+// is checked after updateSQL, so the reuse policy set by the same statement applies to it.
+func (e *SimpleExec) changePassword(name, host, pwd, plaintext, updateSQL string) error {
+	restrictedCtx, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(restrictedCtx)
+	sqlExecutor := restrictedCtx.(sqlexec.SQLExecutor)
+
+	if _, err := sqlExecutor.ExecuteInternal(context.TODO(), "begin"); err != nil {
+		return err
+	}
+	_, err = sqlExecutor.ExecuteInternal(context.TODO(), updateSQL)
+	if err == nil {
+		err = checkAndRecordPasswordHistory(restrictedCtx, name, host, pwd, plaintext)
+	}
+	if err != nil {
+		if _, rollbackErr := sqlExecutor.ExecuteInternal(context.TODO(), "rollback"); rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+	_, err = sqlExecutor.ExecuteInternal(context.TODO(), "commit")
+	return err
+}
+
+// isSamePassword returns whether the new password is the same as the history one. The caching_sha2_password
+// authentication strings are salted, so the plaintext is checked against them if it's known.
+func isSamePassword(history, pwd, plaintext string) bool {
+	if history == pwd {
+		return true
+	}
+	return plaintext != "" && len(history) == privileges.Sha2AuthStringLen && privileges.CheckSha2Password(history, plaintext)
+}
+
+// isCurrentUser returns whether the account is the one the session logins with.
+func isCurrentUser(ctx sessionctx.Context, name, host string) bool {
+	user := ctx.GetSessionVars().User
+	return user != nil && user.AuthUsername == name && user.AuthHostname == host
+}
@@ -373,6 +373,16 @@ func (e *PointGetExecutor) get(ctx context.Context, key kv.Key) ([]byte, error)
 	if e.tblInfo.TempTableType == model.TempTableGlobal {
 		return nil, nil
 	}
+	// The committed data of local temporary tables is kept in the session.
+	if e.tblInfo.TempTableType == model.TempTableLocal {
+		if data := e.ctx.GetSessionVars().TemporaryTableData; data != nil {
+			val, err = data.Get(ctx, key)
+			if !kv.IsErrNotFound(err) {
+				return val, err
+			}
+		}
+		return nil, nil
+	}
 	lock := e.tblInfo.Lock
 	if lock != nil && (lock.Tp == model.TableLockRead || lock.Tp == model.TableLockReadOnly) {
 		if e.ctx.GetSessionVars().EnablePointGetCache {
@@ -87,6 +87,9 @@ func (e *ReplaceExec) removeRow(ctx context.Context, txn kv.Transaction, handle
 	if err != nil {
 		return false, err
 	}
+	if err = e.fkChecker.onDelete(ctx, e.ctx, oldRow); err != nil {
+		return false, err
+	}
 	e.ctx.GetSessionVars().StmtCtx.AddAffectedRows(1)
 	return false, nil
 }
@@ -114,6 +117,10 @@ func (e *ReplaceExec) EqualDatumsAsBinary(sc *stmtctx.StatementContext, a []type
 
 // replaceRow removes all duplicate rows for one row, then inserts it.
 func (e *ReplaceExec) replaceRow(ctx context.Context, r toBeCheckedRow) error {
+	// Check the row before removing the duplicated rows, so that nothing is changed if it's violated.
+	if _, err := e.checkRowConstraints(ctx, r.row); err != nil {
+		return err
+	}
 	txn, err := e.ctx.Txn(true)
 	if err != nil {
 		return err
@@ -15,21 +15,30 @@ package executor
 
 import (
 	"context"
+	"math/rand"
 	"sort"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/distsql"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/store/tikv"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	decoder "github.com/pingcap/tidb/util/rowDecoder"
+	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/pingcap/tipb/go-tipb"
 )
 
 var _ Executor = &TableSampleExecutor{}
@@ -69,12 +78,13 @@ func (e *TableSampleExecutor) Next(ctx context.Context, req *chunk.Chunk) error
 
 // Close implements the Executor Close interface.
 func (e *TableSampleExecutor) Close() error {
-	return nil
+	return e.sampler.close()
 }
 
 type rowSampler interface {
 	writeChunk(req *chunk.Chunk) error
 	finished() bool
+	close() error
 }
 
 type tableRegionSampler struct {
@@ -153,31 +163,36 @@ func (s *tableRegionSampler) pickRanges(count int) ([]kv.KeyRange, error) {
 }
 
 func (s *tableRegionSampler) writeChunkFromRanges(ranges []kv.KeyRange, req *chunk.Chunk) error {
-	decLoc, sysLoc := s.ctx.GetSessionVars().TimeZone, time.UTC
 	cols, decColMap, err := s.buildSampleColAndDecodeColMap()
 	if err != nil {
 		return err
 	}
 	rowDecoder := decoder.NewRowDecoder(s.table, cols, decColMap)
 	err = s.scanFirstKVForEachRange(ranges, func(handle kv.Handle, value []byte) error {
-		_, err := rowDecoder.DecodeAndEvalRowWithMap(s.ctx, handle, value, decLoc, sysLoc, s.rowMap)
-		if err != nil {
-			return err
-		}
-		currentRow := rowDecoder.CurrentRowWithDefaultVal()
-		mutRow := chunk.MutRowFromTypes(s.retTypes)
-		for i, col := range s.schema.Columns {
-			offset := decColMap[col.ID].Col.Offset
-			target := currentRow.GetDatum(offset, s.retTypes[i])
-			mutRow.SetDatum(i, target)
-		}
-		req.AppendRow(mutRow.ToRow())
-		s.resetRowMap()
-		return nil
+		return s.appendRow(rowDecoder, decColMap, handle, value, req)
 	})
 	return err
 }
 
+func (s *tableRegionSampler) appendRow(rowDecoder *decoder.RowDecoder, decColMap map[int64]decoder.Column,
+	handle kv.Handle, value []byte, req *chunk.Chunk) error {
+	decLoc, sysLoc := s.ctx.GetSessionVars().TimeZone, time.UTC
+	_, err := rowDecoder.DecodeAndEvalRowWithMap(s.ctx, handle, value, decLoc, sysLoc, s.rowMap)
+	if err != nil {
+		return err
+	}
+	currentRow := rowDecoder.CurrentRowWithDefaultVal()
+	mutRow := chunk.MutRowFromTypes(s.retTypes)
+	for i, col := range s.schema.Columns {
+		offset := decColMap[col.ID].Col.Offset
+		target := currentRow.GetDatum(offset, s.retTypes[i])
+		mutRow.SetDatum(i, target)
+	}
+	req.AppendRow(mutRow.ToRow())
+	s.resetRowMap()
+	return nil
+}
+
 func (s *tableRegionSampler) splitTableRanges() ([]kv.KeyRange, error) {
 	if len(s.partTables) != 0 {
 		var ranges []kv.KeyRange
@@ -218,7 +233,8 @@ func splitIntoMultiRanges(store kv.Storage, startKey, endKey kv.Key) ([]kv.KeyRa
 		if kv.Key(start).Cmp(startKey) < 0 {
 			start = startKey
 		}
-		if kv.Key(end).Cmp(endKey) > 0 {
+		// The end key of the last region is empty, which means unbounded.
+		if len(end) == 0 || kv.Key(end).Cmp(endKey) > 0 {
 			end = endKey
 		}
 		ranges = append(ranges, kv.KeyRange{StartKey: start, EndKey: end})
@@ -323,6 +339,436 @@ func (s *tableRegionSampler) finished() bool {
 	return s.isFinished
 }
 
+func (s *tableRegionSampler) close() error {
+	return nil
+}
+
+func (s *tableRegionSampler) physicalTableIDs() []int64 {
+	if len(s.partTables) == 0 {
+		return []int64{s.table.Meta().ID}
+	}
+	var pids []int64
+	for _, t := range s.partTables {
+		pids = append(pids, t.GetAllPartitionIDs()...)
+	}
+	// Keep the physical tables in the order of their keys.
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+	return pids
+}
+
+// tableSystemSampler implements the SYSTEM sampling method. The regions are treated
+// as the sampling blocks, each region is chosen with the sampling percentage and all
+// the rows of the chosen regions are returned.
+type tableSystemSampler struct {
+	*tableRegionSampler
+	percent float64
+	rng     *rand.Rand
+
+	rowDecoder *decoder.RowDecoder
+	decColMap  map[int64]decoder.Column
+	snapshot   kv.Snapshot
+	curRange   kv.KeyRange
+	iter       kv.Iterator
+}
+
+func newTableSystemSampler(ctx sessionctx.Context, t table.Table, startTs uint64, partTables []table.PartitionedTable,
+	schema *expression.Schema, fullSchema *expression.Schema, retTypes []*types.FieldType, desc bool,
+	percent float64, seed int64) *tableSystemSampler {
+	return &tableSystemSampler{
+		tableRegionSampler: newTableRegionSampler(ctx, t, startTs, partTables, schema, fullSchema, retTypes, desc),
+		percent:            percent,
+		rng:                rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (s *tableSystemSampler) writeChunk(req *chunk.Chunk) error {
+	err := s.init()
+	if err != nil {
+		return err
+	}
+	for !req.IsFull() {
+		if s.iter == nil {
+			if len(s.restKVRanges) == 0 {
+				s.isFinished = true
+				return nil
+			}
+			if err = s.openNextRange(); err != nil {
+				return err
+			}
+		}
+		// The key of an exhausted iterator must not be read.
+		if !s.iter.Valid() || s.iter.Key().Cmp(s.curRange.StartKey) < 0 || s.iter.Key().Cmp(s.curRange.EndKey) >= 0 {
+			s.iter.Close()
+			s.iter = nil
+			continue
+		}
+		key := s.iter.Key()
+		if tablecodec.IsRecordKey(key) {
+			handle, err := tablecodec.DecodeRowKey(key)
+			if err != nil {
+				return err
+			}
+			if err = s.appendRow(s.rowDecoder, s.decColMap, handle, s.iter.Value(), req); err != nil {
+				return err
+			}
+		}
+		if err = s.iter.Next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// init chooses the regions to be sampled. The regions are always chosen in the ascending
+// order so that the same regions are chosen for the same seed no matter the scan direction.
+func (s *tableSystemSampler) init() error {
+	if s.restKVRanges != nil {
+		return nil
+	}
+	ranges, err := s.splitTableRanges()
+	if err != nil {
+		return err
+	}
+	sortRanges(ranges, false)
+	s.restKVRanges = make([]kv.KeyRange, 0, len(ranges))
+	for _, r := range ranges {
+		if s.rng.Float64()*100 < s.percent {
+			s.restKVRanges = append(s.restKVRanges, r)
+		}
+	}
+	sortRanges(s.restKVRanges, s.isDesc)
+
+	cols, decColMap, err := s.buildSampleColAndDecodeColMap()
+	if err != nil {
+		return err
+	}
+	s.rowDecoder = decoder.NewRowDecoder(s.table, cols, decColMap)
+	s.decColMap = decColMap
+	s.snapshot = s.ctx.GetStore().GetSnapshot(kv.Version{Ver: s.startTS})
+	return nil
+}
+
+func (s *tableSystemSampler) openNextRange() error {
+	s.curRange, s.restKVRanges = s.restKVRanges[0], s.restKVRanges[1:]
+	var err error
+	if s.isDesc {
+		s.iter, err = s.snapshot.IterReverse(s.curRange.EndKey)
+	} else {
+		s.iter, err = s.snapshot.Iter(s.curRange.StartKey, s.curRange.EndKey)
+	}
+	return err
+}
+
+func (s *tableSystemSampler) close() error {
+	if s.iter != nil {
+		s.iter.Close()
+		s.iter = nil
+	}
+	return nil
+}
+
+// tableBernoulliSampler implements the BERNOULLI sampling method. Each row is chosen
+// with the sampling percentage by the predicate
+// `crc32(concat_ws(',', handle columns..., seed)) < percent * 2^32 / 100`,
+// which is pushed down to the coprocessor if possible and evaluated in TiDB otherwise.
+type tableBernoulliSampler struct {
+	ctx     sessionctx.Context
+	table   table.Table
+	startTS uint64
+	schema  *expression.Schema
+	isDesc  bool
+
+	// scanSchema is the schema of the table scan. It starts with the columns of schema,
+	// followed by the handle columns and the columns that the virtual columns depend on.
+	scanSchema         *expression.Schema
+	scanTypes          []*types.FieldType
+	columns            []*model.ColumnInfo
+	virtualColumnIndex []int
+	virtualRetTypes    []*types.FieldType
+	outputColIdxs      []int
+	// conditions is the sampling predicate if it can't be pushed down.
+	conditions  []expression.Expression
+	dagPB       *tipb.DAGRequest
+	physicalIDs []int64
+
+	result     distsql.SelectResult
+	chk        *chunk.Chunk
+	cursor     int
+	selected   []bool
+	isFinished bool
+}
+
+func newTableBernoulliSampler(ctx sessionctx.Context, t table.Table, startTs uint64, partTables []table.PartitionedTable,
+	schema *expression.Schema, fullSchema *expression.Schema, desc bool, percent float64, seed int64) (*tableBernoulliSampler, error) {
+	s := &tableBernoulliSampler{
+		ctx:     ctx,
+		table:   t,
+		startTS: startTs,
+		schema:  schema,
+		isDesc:  desc,
+	}
+	s.physicalIDs = (&tableRegionSampler{table: t, partTables: partTables}).physicalTableIDs()
+	if desc {
+		for i, j := 0, len(s.physicalIDs)-1; i < j; i, j = i+1, j-1 {
+			s.physicalIDs[i], s.physicalIDs[j] = s.physicalIDs[j], s.physicalIDs[i]
+		}
+	}
+	handleCols, err := s.buildScanSchema(fullSchema)
+	if err != nil {
+		return nil, err
+	}
+	cond, err := buildBernoulliCondition(ctx, handleCols, percent, seed)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.buildDAGPB([]expression.Expression{cond}); err != nil {
+		return nil, err
+	}
+	s.chk = chunk.NewChunkWithCapacity(s.scanTypes, ctx.GetSessionVars().MaxChunkSize)
+	return s, nil
+}
+
+// buildScanSchema builds the schema of the table scan and returns the handle columns.
+func (s *tableBernoulliSampler) buildScanSchema(fullSchema *expression.Schema) ([]*expression.Column, error) {
+	tblInfo := s.table.Meta()
+	s.scanSchema = expression.NewSchema()
+	appendCol := func(col *expression.Column) error {
+		if s.scanSchema.Contains(col) {
+			return nil
+		}
+		var colInfo *model.ColumnInfo
+		if col.ID == model.ExtraHandleID {
+			colInfo = model.NewExtraHandleColInfo()
+		} else {
+			colInfo = plannercore.FindColumnInfoByID(tblInfo.Columns, col.ID)
+		}
+		if colInfo == nil {
+			return errors.Errorf("column %s not found in table %s", col, tblInfo.Name)
+		}
+		s.scanSchema.Append(col)
+		s.scanTypes = append(s.scanTypes, col.RetType)
+		s.columns = append(s.columns, colInfo)
+		return nil
+	}
+	findCol := func(id int64) (*expression.Column, error) {
+		for _, col := range fullSchema.Columns {
+			if col.ID == id {
+				return col, nil
+			}
+		}
+		return nil, errors.Errorf("handle column %d not found in table %s", id, tblInfo.Name)
+	}
+
+	for i, col := range s.schema.Columns {
+		if err := appendCol(col); err != nil {
+			return nil, err
+		}
+		s.outputColIdxs = append(s.outputColIdxs, i)
+	}
+	var handleColIDs []int64
+	switch {
+	case tblInfo.PKIsHandle:
+		handleColIDs = append(handleColIDs, tblInfo.GetPkColInfo().ID)
+	case tblInfo.IsCommonHandle:
+		for _, idxCol := range tables.FindPrimaryIndex(tblInfo).Columns {
+			handleColIDs = append(handleColIDs, tblInfo.Columns[idxCol.Offset].ID)
+		}
+	default:
+		handleColIDs = append(handleColIDs, model.ExtraHandleID)
+	}
+	handleCols := make([]*expression.Column, 0, len(handleColIDs))
+	for _, id := range handleColIDs {
+		col, err := findCol(id)
+		if err != nil {
+			return nil, err
+		}
+		if err = appendCol(col); err != nil {
+			return nil, err
+		}
+		handleCols = append(handleCols, col)
+	}
+	// The virtual columns are evaluated in TiDB, the columns they depend on must be scanned.
+	for i := 0; i < s.scanSchema.Len(); i++ {
+		col := s.scanSchema.Columns[i]
+		if col.VirtualExpr == nil {
+			continue
+		}
+		for _, dep := range expression.ExtractColumns(col.VirtualExpr) {
+			dep, err := findCol(dep.ID)
+			if err != nil {
+				return nil, err
+			}
+			if err = appendCol(dep); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, col := range s.scanSchema.Columns {
+		if col.VirtualExpr == nil {
+			continue
+		}
+		var err error
+		col.VirtualExpr, err = col.VirtualExpr.ResolveIndices(s.scanSchema)
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.virtualColumnIndex = buildVirtualColumnIndex(s.scanSchema, s.columns)
+	for _, idx := range s.virtualColumnIndex {
+		s.virtualRetTypes = append(s.virtualRetTypes, s.scanSchema.Columns[idx].RetType)
+	}
+	for i, col := range handleCols {
+		handleCols[i] = col.Clone().(*expression.Column)
+		handleCols[i].Index = s.scanSchema.ColumnIndex(col)
+	}
+	return handleCols, nil
+}
+
+// buildBernoulliCondition builds the predicate which chooses a row with the sampling percentage.
+// The predicate only depends on the handle and the seed, so the same rows are chosen for the same seed.
+func buildBernoulliCondition(ctx sessionctx.Context, handleCols []*expression.Column, percent float64, seed int64) (expression.Expression, error) {
+	args := make([]expression.Expression, 0, len(handleCols)+2)
+	args = append(args, &expression.Constant{Value: types.NewStringDatum(","), RetType: types.NewFieldType(mysql.TypeVarString)})
+	for _, col := range handleCols {
+		args = append(args, col)
+	}
+	args = append(args, &expression.Constant{Value: types.NewIntDatum(seed), RetType: types.NewFieldType(mysql.TypeLonglong)})
+	concat, err := expression.NewFunction(ctx, ast.ConcatWS, types.NewFieldType(mysql.TypeVarString), args...)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := expression.NewFunction(ctx, ast.CRC32, types.NewFieldType(mysql.TypeLonglong), concat)
+	if err != nil {
+		return nil, err
+	}
+	thresholdType := types.NewFieldType(mysql.TypeLonglong)
+	thresholdType.Flag |= mysql.UnsignedFlag
+	threshold := &expression.Constant{
+		Value:   types.NewUintDatum(uint64(percent / 100 * (1 << 32))),
+		RetType: thresholdType,
+	}
+	return expression.NewFunction(ctx, ast.LT, types.NewFieldType(mysql.TypeTiny), hash, threshold)
+}
+
+func (s *tableBernoulliSampler) buildDAGPB(conditions []expression.Expression) error {
+	dagReq := &tipb.DAGRequest{}
+	dagReq.TimeZoneName, dagReq.TimeZoneOffset = timeutil.Zone(s.ctx.GetSessionVars().Location())
+	sc := s.ctx.GetSessionVars().StmtCtx
+	dagReq.Flags = sc.PushDownFlags()
+	for i := range s.columns {
+		dagReq.OutputOffsets = append(dagReq.OutputOffsets, uint32(i))
+	}
+
+	tblScan := tables.BuildTableScanFromInfos(s.table.Meta(), s.columns)
+	tblScan.Desc = s.isDesc
+	if err := plannercore.SetPBColumnsDefaultValue(s.ctx, tblScan.Columns, s.columns); err != nil {
+		return err
+	}
+	dagReq.Executors = append(dagReq.Executors, &tipb.Executor{Tp: tipb.ExecType_TypeTableScan, TblScan: tblScan})
+
+	client := s.ctx.GetClient()
+	if expression.CanExprsPushDown(sc, conditions, client, kv.TiKV) {
+		pbConditions, err := expression.ExpressionsToPBList(sc, conditions, client)
+		if err != nil {
+			return err
+		}
+		selection := &tipb.Selection{Conditions: pbConditions}
+		dagReq.Executors = append(dagReq.Executors, &tipb.Executor{Tp: tipb.ExecType_TypeSelection, Selection: selection})
+	} else {
+		s.conditions = conditions
+	}
+	distsql.SetEncodeType(s.ctx, dagReq)
+	s.dagPB = dagReq
+	return nil
+}
+
+func (s *tableBernoulliSampler) writeChunk(req *chunk.Chunk) error {
+	ctx := context.Background()
+	for !req.IsFull() {
+		if s.result == nil {
+			if len(s.physicalIDs) == 0 {
+				s.isFinished = true
+				return nil
+			}
+			if err := s.openNextTable(ctx); err != nil {
+				return err
+			}
+		}
+		if s.cursor >= s.chk.NumRows() {
+			if err := s.fetchNextChunk(ctx); err != nil {
+				return err
+			}
+			if s.chk.NumRows() == 0 {
+				if err := s.result.Close(); err != nil {
+					return err
+				}
+				s.result = nil
+				continue
+			}
+		}
+		for ; s.cursor < s.chk.NumRows() && !req.IsFull(); s.cursor++ {
+			if len(s.conditions) > 0 && !s.selected[s.cursor] {
+				continue
+			}
+			req.AppendRowByColIdxs(s.chk.GetRow(s.cursor), s.outputColIdxs)
+		}
+	}
+	return nil
+}
+
+func (s *tableBernoulliSampler) openNextTable(ctx context.Context) error {
+	var pid int64
+	pid, s.physicalIDs = s.physicalIDs[0], s.physicalIDs[1:]
+	s.dagPB.Executors[0].TblScan.TableId = pid
+	start := tablecodec.GenTableRecordPrefix(pid)
+	var builder distsql.RequestBuilder
+	kvReq, err := builder.
+		SetKeyRanges([]kv.KeyRange{{StartKey: start, EndKey: start.PrefixNext()}}).
+		SetDAGRequest(s.dagPB).
+		SetStartTS(s.startTS).
+		SetDesc(s.isDesc).
+		SetKeepOrder(true).
+		SetFromSessionVars(s.ctx.GetSessionVars()).
+		SetFromInfoSchema(s.ctx.GetInfoSchema()).
+		Build()
+	if err != nil {
+		return err
+	}
+	s.result, err = distsql.Select(ctx, s.ctx, kvReq, s.scanTypes, statistics.NewQueryFeedback(0, nil, 0, false))
+	return err
+}
+
+func (s *tableBernoulliSampler) fetchNextChunk(ctx context.Context) error {
+	s.cursor = 0
+	if err := s.result.Next(ctx, s.chk); err != nil {
+		return err
+	}
+	if s.chk.NumRows() == 0 {
+		return nil
+	}
+	err := FillVirtualColumnValue(s.virtualRetTypes, s.virtualColumnIndex, s.scanSchema, s.columns, s.ctx, s.chk)
+	if err != nil {
+		return err
+	}
+	if len(s.conditions) > 0 {
+		s.selected, err = expression.VectorizedFilter(s.ctx, s.conditions, chunk.NewIterator4Chunk(s.chk), s.selected)
+	}
+	return err
+}
+
+func (s *tableBernoulliSampler) finished() bool {
+	return s.isFinished
+}
+
+func (s *tableBernoulliSampler) close() error {
+	if s.result != nil {
+		err := s.result.Close()
+		s.result = nil
+		return err
+	}
+	return nil
+}
+
 type sampleKV struct {
 	handle kv.Handle
 	value  []byte
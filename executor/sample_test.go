@@ -16,6 +16,7 @@ package executor_test
 import (
 	"flag"
 	"fmt"
+	"strings"
 	"sync/atomic"
 
 	. "github.com/pingcap/check"
@@ -171,11 +172,81 @@ func (s *testTableSampleSuite) TestTableSampleInvalid(c *C) {
 	tk.MustGetErrCode("select * from information_schema.tables tablesample regions();", errno.ErrInvalidTableSample)
 
 	tk.MustGetErrCode("select a from t tablesample system();", errno.ErrInvalidTableSample)
-	tk.MustGetErrCode("select a from t tablesample bernoulli(10 percent);", errno.ErrInvalidTableSample)
+	tk.MustGetErrCode("select a from t tablesample bernoulli();", errno.ErrInvalidTableSample)
+	tk.MustGetErrCode("select a from t tablesample system(10 rows);", errno.ErrInvalidTableSample)
+	tk.MustGetErrCode("select a from t tablesample bernoulli(101 percent);", errno.ErrInvalidTableSample)
+	tk.MustGetErrCode("select a from t tablesample bernoulli(-1);", errno.ErrInvalidTableSample)
+	tk.MustGetErrCode("select a from t tablesample bernoulli('10');", errno.ErrInvalidTableSample)
+	tk.MustGetErrCode("select a from t tablesample bernoulli(10) repeatable(1.5);", errno.ErrInvalidTableSample)
 	tk.MustGetErrCode("select a from t as t1 tablesample regions(), t as t2 tablesample system();", errno.ErrInvalidTableSample)
 	tk.MustGetErrCode("select a from t tablesample ();", errno.ErrInvalidTableSample)
 }
 
+func (s *testTableSampleSuite) TestTableSampleSystem(c *C) {
+	tk := s.initSampleTest(c)
+	tk.MustExec("create table t (a int primary key, b int as (a + 1));")
+	tk.MustQuery("split table t between (0) and (10000) regions 10;").Check(testkit.Rows("9 1"))
+	for i := 0; i < 10000; i += 100 {
+		tk.MustExec("insert into t(a) values (?);", i)
+	}
+	tk.MustQuery("select count(*) from t tablesample system(100);").Check(testkit.Rows("100"))
+	tk.MustQuery("select count(*) from t tablesample system(0 percent);").Check(testkit.Rows("0"))
+	c.Assert(tk.HasPlan("select * from t tablesample system(10);", "TableSample"), IsTrue)
+
+	// The regions are either fully chosen or not chosen at all.
+	rows := tk.MustQuery("select a, b from t tablesample system(50) repeatable(1);").Rows()
+	c.Assert(len(rows)%10, Equals, 0)
+	tk.MustQuery("select a, b from t tablesample system(50) repeatable(1);").Check(rows)
+	tk.MustQuery("select count(*) from t tablesample system(50) repeatable(1) where b != a + 1;").Check(testkit.Rows("0"))
+
+	// The same regions are chosen in the reversed order.
+	descRows := tk.MustQuery("select a, b from t tablesample system(50) repeatable(1) order by a desc;").Rows()
+	c.Assert(len(descRows), Equals, len(rows))
+	for i := range rows {
+		c.Assert(descRows[i], DeepEquals, rows[len(rows)-1-i])
+	}
+
+	// The scan of the empty regions at the end of the table exhausts the iterator.
+	tk.MustExec("delete from t where a >= 5000;")
+	tk.MustQuery("select count(*) from t tablesample system(100);").Check(testkit.Rows("50"))
+	tk.MustQuery("select count(*) from t tablesample system(100) order by a desc;").Check(testkit.Rows("50"))
+}
+
+func (s *testTableSampleSuite) TestTableSampleBernoulli(c *C) {
+	tk := s.initSampleTest(c)
+	tk.MustExec("create table t (a int, b int as (a * 2));")
+	tk.MustQuery("split table t by (250), (500), (750);").Check(testkit.Rows("3 1"))
+	for i := 0; i < 1000; i += 100 {
+		values := make([]string, 0, 100)
+		for j := i; j < i+100; j++ {
+			values = append(values, fmt.Sprintf("(%d)", j))
+		}
+		tk.MustExec("insert into t(a) values " + strings.Join(values, ",") + ";")
+	}
+	tk.MustQuery("select count(*) from t tablesample bernoulli(100);").Check(testkit.Rows("1000"))
+	tk.MustQuery("select count(*) from t tablesample bernoulli(0 percent);").Check(testkit.Rows("0"))
+
+	rows := tk.MustQuery("select a, b from t tablesample bernoulli(30) repeatable(42);").Rows()
+	c.Assert(len(rows), Greater, 200)
+	c.Assert(len(rows), Less, 400)
+	tk.MustQuery("select a, b from t tablesample bernoulli(30) repeatable(42);").Check(rows)
+	tk.MustQuery("select count(*) from t tablesample bernoulli(30) repeatable(42) where b != a * 2;").Check(testkit.Rows("0"))
+	tk.MustQuery("select a from t tablesample bernoulli(30) repeatable(42) order by _tidb_rowid desc limit 1;").Check(
+		testkit.Rows(fmt.Sprintf("%v", rows[len(rows)-1][0])))
+	otherRows := tk.MustQuery("select a, b from t tablesample bernoulli(30) repeatable(43);").Rows()
+	c.Assert(otherRows, Not(DeepEquals), rows)
+
+	// Clustered index and partition table.
+	tk.Se.GetSessionVars().EnableClusteredIndex = variable.ClusteredIndexDefModeOn
+	tk.MustExec("create table t2 (a varchar(10), b int, primary key (a, b)) partition by hash(b) partitions 2;")
+	tk.MustExec("insert into t2 select a, a from t;")
+	tk.MustQuery("select count(*) from t2 tablesample bernoulli(100);").Check(testkit.Rows("1000"))
+	rows = tk.MustQuery("select a from t2 tablesample bernoulli(50) repeatable(7);").Rows()
+	c.Assert(len(rows), Greater, 400)
+	c.Assert(len(rows), Less, 600)
+	tk.MustQuery("select a from t2 tablesample bernoulli(50) repeatable(7);").Check(rows)
+}
+
 func (s *testTableSampleSuite) TestTableSampleWithTiDBRowID(c *C) {
 	tk := s.initSampleTest(c)
 	tk.MustExec("create table t (a int, b varchar(255));")
@@ -1187,10 +1187,9 @@ func (s *testSuite5) TestEnableNoopFunctionsVar(c *C) {
 	tk.MustQuery(`select @@global.tidb_enable_noop_functions;`).Check(testkit.Rows("0"))
 	tk.MustQuery(`select @@tidb_enable_noop_functions;`).Check(testkit.Rows("0"))
 
-	_, err := tk.Exec(`select get_lock('lock1', 2);`)
-	c.Assert(terror.ErrorEqual(err, expression.ErrFunctionsNoopImpl), IsTrue, Commentf("err %v", err))
-	_, err = tk.Exec(`select release_lock('lock1');`)
-	c.Assert(terror.ErrorEqual(err, expression.ErrFunctionsNoopImpl), IsTrue, Commentf("err %v", err))
+	// get_lock() and release_lock() are no longer noop functions.
+	tk.MustQuery(`select get_lock('lock1', 2);`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select release_lock('lock1');`).Check(testkit.Rows("1"))
 
 	// change session var to 1
 	tk.MustExec(`set tidb_enable_noop_functions=1;`)
@@ -1204,13 +1203,8 @@ func (s *testSuite5) TestEnableNoopFunctionsVar(c *C) {
 	tk.MustQuery(`select @@tidb_enable_noop_functions;`).Check(testkit.Rows("0"))
 	tk.MustQuery(`select @@global.tidb_enable_noop_functions;`).Check(testkit.Rows("0"))
 
-	_, err = tk.Exec(`select get_lock('lock2', 10);`)
-	c.Assert(terror.ErrorEqual(err, expression.ErrFunctionsNoopImpl), IsTrue, Commentf("err %v", err))
-	_, err = tk.Exec(`select release_lock('lock2');`)
-	c.Assert(terror.ErrorEqual(err, expression.ErrFunctionsNoopImpl), IsTrue, Commentf("err %v", err))
-
 	// set test
-	_, err = tk.Exec(`set tidb_enable_noop_functions='abc'`)
+	_, err := tk.Exec(`set tidb_enable_noop_functions='abc'`)
 	c.Assert(err, NotNil)
 	_, err = tk.Exec(`set tidb_enable_noop_functions=11`)
 	c.Assert(err, NotNil)
@@ -779,6 +779,8 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 	switch tableInfo.TempTableType {
 	case model.TempTableGlobal:
 		fmt.Fprintf(buf, "CREATE GLOBAL TEMPORARY TABLE %s (\n", tableName)
+	case model.TempTableLocal:
+		fmt.Fprintf(buf, "CREATE TEMPORARY TABLE %s (\n", tableName)
 	default:
 		fmt.Fprintf(buf, "CREATE TABLE %s (\n", tableName)
 	}
@@ -956,6 +958,16 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 		}
 	}
 
+	for _, constr := range tableInfo.Constraints {
+		if constr.State != model.StatePublic {
+			continue
+		}
+		buf.WriteString(fmt.Sprintf(",\n  CONSTRAINT %s CHECK (%s)", stringutil.Escape(constr.Name.O, sqlMode), constr.ExprString))
+		if !constr.Enforced {
+			buf.WriteString(" /*!80016 NOT ENFORCED */")
+		}
+	}
+
 	buf.WriteString("\n")
 
 	switch tableInfo.TempTableType {
@@ -1028,7 +1040,7 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 	}
 
 	// add partition info here.
-	appendPartitionInfo(tableInfo.Partition, buf)
+	appendPartitionInfo(tableInfo.GetPartitionInfo(), buf)
 	return nil
 }
 
@@ -1331,7 +1343,7 @@ func (e *ShowExec) fetchShowCreateUser() error {
 
 	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
 
-	stmt, err := exec.ParseWithParams(context.TODO(), `SELECT * FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.UserTable, userName, hostName)
+	stmt, err := exec.ParseWithParams(context.TODO(), `SELECT Account_locked, Password_expired, Password_lifetime FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.UserTable, userName, hostName)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -1345,6 +1357,20 @@ func (e *ShowExec) fetchShowCreateUser() error {
 		return ErrCannotUser.GenWithStackByArgs("SHOW CREATE USER",
 			fmt.Sprintf("'%s'@'%s'", e.User.Username, e.User.Hostname))
 	}
+	accountLock := "UNLOCK"
+	if rows[0].GetEnum(0).String() == "Y" {
+		accountLock = "LOCK"
+	}
+	passwordExpire := "PASSWORD EXPIRE DEFAULT"
+	if rows[0].GetEnum(1).String() == "Y" {
+		passwordExpire = "PASSWORD EXPIRE"
+	} else if !rows[0].IsNull(2) {
+		if lifetime := rows[0].GetInt64(2); lifetime == 0 {
+			passwordExpire = "PASSWORD EXPIRE NEVER"
+		} else {
+			passwordExpire = fmt.Sprintf("PASSWORD EXPIRE INTERVAL %d DAY", lifetime)
+		}
+	}
 
 	stmt, err = exec.ParseWithParams(context.TODO(), `SELECT Priv FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.GlobalPrivTable, userName, hostName)
 	if err != nil {
@@ -1365,9 +1391,13 @@ func (e *ShowExec) fetchShowCreateUser() error {
 		}
 		require = privValue.RequireStr()
 	}
+	authPlugin := checker.GetAuthPlugin(e.User.Username, e.User.Hostname)
+	if authPlugin == "" {
+		authPlugin = mysql.AuthNativePassword
+	}
 	// FIXME: the returned string is not escaped safely
-	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH 'mysql_native_password' AS '%s' REQUIRE %s PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK",
-		e.User.Username, e.User.Hostname, checker.GetEncodedPassword(e.User.Username, e.User.Hostname), require)
+	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s' AS '%s' REQUIRE %s %s ACCOUNT %s",
+		e.User.Username, e.User.Hostname, authPlugin, checker.GetEncodedPassword(e.User.Username, e.User.Hostname), require, passwordExpire, accountLock)
 	e.appendRow([]interface{}{showStr})
 	return nil
 }
@@ -405,6 +405,16 @@ func (s *testSuite5) TestShowCreateUser(c *C) {
 	tk.MustQuery("show create user 'test_show_create_user'@'localhost';").
 		Check(testkit.Rows(`CREATE USER 'test_show_create_user'@'localhost' IDENTIFIED WITH 'mysql_native_password' AS '*94BDCEBE19083CE2A1F959FD02F964C7AF4CFC29' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK`))
 
+	tk.MustExec(`CREATE USER 'test_show_create_user2'@'%' PASSWORD EXPIRE INTERVAL 10 DAY ACCOUNT LOCK;`)
+	tk.MustQuery("show create user 'test_show_create_user2'@'%'").
+		Check(testkit.Rows(`CREATE USER 'test_show_create_user2'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE INTERVAL 10 DAY ACCOUNT LOCK`))
+	tk.MustExec(`ALTER USER 'test_show_create_user2'@'%' PASSWORD EXPIRE NEVER ACCOUNT UNLOCK;`)
+	tk.MustQuery("show create user 'test_show_create_user2'@'%'").
+		Check(testkit.Rows(`CREATE USER 'test_show_create_user2'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE NEVER ACCOUNT UNLOCK`))
+	tk.MustExec(`ALTER USER 'test_show_create_user2'@'%' PASSWORD EXPIRE;`)
+	tk.MustQuery("show create user 'test_show_create_user2'@'%'").
+		Check(testkit.Rows(`CREATE USER 'test_show_create_user2'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE ACCOUNT UNLOCK`))
+
 	// Case: the user exists but the host portion doesn't match
 	err := tk.QueryToErr("show create user 'test_show_create_user'@'asdf';")
 	c.Assert(err.Error(), Equals, executor.ErrCannotUser.GenWithStackByArgs("SHOW CREATE USER", "'test_show_create_user'@'asdf'").Error())
@@ -815,7 +825,7 @@ func (s *testSuite5) TestShowCreateTable(c *C) {
 			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
 	))
 
-	// TiDB defaults (and only supports) foreign_key_checks=0
+	// TiDB defaults to foreign_key_checks=0
 	// This means that the child table can be created before the parent table.
 	// This behavior is required for mysqldump restores.
 	tk.MustExec(`DROP TABLE IF EXISTS parent, child`)
@@ -1102,7 +1112,7 @@ func (s *testSuite5) TestShowBuiltin(c *C) {
 	res := tk.MustQuery("show builtins;")
 	c.Assert(res, NotNil)
 	rows := res.Rows()
-	const builtinFuncNum = 269
+	const builtinFuncNum = 275
 	c.Assert(builtinFuncNum, Equals, len(rows))
 	c.Assert("abs", Equals, rows[0][0].(string))
 	c.Assert("yearweek", Equals, rows[builtinFuncNum-1][0].(string))
@@ -130,6 +130,10 @@ func (e *SimpleExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		e.executeCommit(x)
 	case *ast.RollbackStmt:
 		err = e.executeRollback(x)
+	case *ast.SavepointStmt:
+		err = e.executeSavepoint(x)
+	case *ast.ReleaseSavepointStmt:
+		err = e.executeReleaseSavepoint(x)
 	case *ast.CreateUserStmt:
 		err = e.executeCreateUser(ctx, x)
 	case *ast.AlterUserStmt:
@@ -705,6 +709,13 @@ func (e *SimpleExec) executeCommit(s *ast.CommitStmt) {
 }
 
 func (e *SimpleExec) executeRollback(s *ast.RollbackStmt) error {
+	if s.SavepointName != "" {
+		sm, err := e.savepointManager()
+		if err != nil {
+			return err
+		}
+		return sm.RollbackToSavepoint(s.SavepointName)
+	}
 	sessVars := e.ctx.GetSessionVars()
 	logutil.BgLogger().Debug("execute rollback statement", zap.Uint64("conn", sessVars.ConnectionID))
 	sessVars.SetInTxn(false)
@@ -725,6 +736,38 @@ func (e *SimpleExec) executeRollback(s *ast.RollbackStmt) error {
 	return nil
 }
 
+// savepointManager manages the savepoints of the transaction, it's implemented by the session which owns the
+// buffers of the transaction.
+type savepointManager interface {
+	SetSavepoint(name string) error
+	RollbackToSavepoint(name string) error
+	ReleaseSavepoint(name string) error
+}
+
+func (e *SimpleExec) savepointManager() (savepointManager, error) {
+	sm, ok := e.ctx.(savepointManager)
+	if !ok {
+		return nil, errors.New("savepoints are not supported by the session")
+	}
+	return sm, nil
+}
+
+func (e *SimpleExec) executeSavepoint(s *ast.SavepointStmt) error {
+	sm, err := e.savepointManager()
+	if err != nil {
+		return err
+	}
+	return sm.SetSavepoint(s.Name)
+}
+
+func (e *SimpleExec) executeReleaseSavepoint(s *ast.ReleaseSavepointStmt) error {
+	sm, err := e.savepointManager()
+	if err != nil {
+		return err
+	}
+	return sm.ReleaseSavepoint(s.Name)
+}
+
 func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStmt) error {
 	// Check `CREATE USER` privilege.
 	if !config.GetGlobalConfig().Security.SkipGrantTable {
@@ -750,12 +793,29 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 	if err != nil {
 		return err
 	}
+	pwdSetting, err := newPasswordOrLockSetting(s.PasswordOrLockOptions)
+	if err != nil {
+		return err
+	}
+	accountLocked, passwordExpired := "N", "N"
+	if pwdSetting.lockAccount != "" {
+		accountLocked = pwdSetting.lockAccount
+	}
+	if pwdSetting.expireNow {
+		passwordExpired = "Y"
+	}
+	reusePolicy, err := globalPasswordReusePolicy(e.ctx)
+	if err != nil {
+		return err
+	}
+	reusePolicy = pwdSetting.reusePolicy(reusePolicy)
 
 	sql := new(strings.Builder)
+	historySQL := new(strings.Builder)
 	if s.IsCreateRole {
 		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, Account_locked) VALUES `, mysql.SystemDB, mysql.UserTable)
 	} else {
-		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string) VALUES `, mysql.SystemDB, mysql.UserTable)
+		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin, Account_locked, Password_expired, Password_lifetime, Password_reuse_history, Password_reuse_time, Failed_login_attempts, Password_lock_time) VALUES `, mysql.SystemDB, mysql.UserTable)
 	}
 
 	users := make([]*auth.UserIdentity, 0, len(s.Specs))
@@ -779,14 +839,24 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 			e.ctx.GetSessionVars().StmtCtx.AppendNote(err)
 			continue
 		}
-		pwd, ok := spec.EncodedPassword()
-		if !ok {
-			return errors.Trace(ErrPasswordFormat)
+		authPlugin := normalizeAuthPlugin(authPluginOfUserSpec(spec))
+		pwd, err := encodeAuthString(authPlugin, spec)
+		if err != nil {
+			return err
 		}
 		if s.IsCreateRole {
 			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd, "Y")
 		} else {
-			sqlexec.MustFormatSQL(sql, `(%?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd)
+			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd, authPlugin, accountLocked, passwordExpired, pwdSetting.lifetime,
+				pwdSetting.reuseHistory, pwdSetting.reuseTime, pwdSetting.failedLoginAttempts, pwdSetting.passwordLockTime)
+			if reusePolicy.enabled() && pwd != "" {
+				if historySQL.Len() == 0 {
+					sqlexec.MustFormatSQL(historySQL, `INSERT INTO %n.%n (Host, User, Password) VALUES `, mysql.SystemDB, passwordHistoryTable)
+				} else {
+					sqlexec.MustFormatSQL(historySQL, ",")
+				}
+				sqlexec.MustFormatSQL(historySQL, `(%?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd)
+			}
 		}
 		users = append(users, spec.User)
 	}
@@ -828,6 +898,15 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 			return err
 		}
 	}
+	if historySQL.Len() != 0 {
+		_, err = sqlExecutor.ExecuteInternal(context.TODO(), historySQL.String())
+		if err != nil {
+			if _, rollbackErr := sqlExecutor.ExecuteInternal(context.TODO(), "rollback"); rollbackErr != nil {
+				return rollbackErr
+			}
+			return err
+		}
+	}
 	if _, err := sqlExecutor.ExecuteInternal(context.TODO(), "commit"); err != nil {
 		return errors.Trace(err)
 	}
@@ -855,6 +934,10 @@ func (e *SimpleExec) executeAlterUser(s *ast.AlterUserStmt) error {
 	if err != nil {
 		return err
 	}
+	pwdSetting, err := newPasswordOrLockSetting(s.PasswordOrLockOptions)
+	if err != nil {
+		return err
+	}
 
 	failedUsers := make([]string, 0, len(s.Specs))
 	for _, spec := range s.Specs {
@@ -863,6 +946,11 @@ func (e *SimpleExec) executeAlterUser(s *ast.AlterUserStmt) error {
 			spec.User.Username = user.Username
 			spec.User.Hostname = user.AuthHostname
 		}
+		// The credentials are kept if neither the password nor the authentication plugin is specified.
+		credentialsChanged := spec.AuthOpt != nil
+		if e.ctx.GetSessionVars().InSandBoxMode && !(credentialsChanged && isCurrentUser(e.ctx, spec.User.Username, spec.User.Hostname)) {
+			return ErrMustChangePassword.GenWithStackByArgs()
+		}
 
 		exists, err := userExists(e.ctx, spec.User.Username, spec.User.Hostname)
 		if err != nil {
@@ -873,22 +961,63 @@ func (e *SimpleExec) executeAlterUser(s *ast.AlterUserStmt) error {
 			failedUsers = append(failedUsers, user)
 			continue
 		}
-		pwd, ok := spec.EncodedPassword()
-		if !ok {
-			return errors.Trace(ErrPasswordFormat)
+		sql := new(strings.Builder)
+		sqlexec.MustFormatSQL(sql, `UPDATE %n.%n SET `, mysql.SystemDB, mysql.UserTable)
+		if credentialsChanged {
+			authPlugin := authPluginOfUserSpec(spec)
+			if authPlugin == "" {
+				// Keep the authentication plugin of the user if it's not specified.
+				authPlugin, err = userAuthPlugin(e.ctx, spec.User.Username, spec.User.Hostname)
+				if err != nil {
+					return err
+				}
+			} else {
+				authPlugin = normalizeAuthPlugin(authPlugin)
+			}
+			pwd, err := encodeAuthString(authPlugin, spec)
+			if err != nil {
+				return err
+			}
+			sqlexec.MustFormatSQL(sql, `authentication_string=%?, plugin=%?, `, pwd, authPlugin)
+			pwdSetting.formatAssignments(sql, credentialsChanged)
+			sqlexec.MustFormatSQL(sql, ` WHERE Host=%? and User=%?;`, spec.User.Hostname, spec.User.Username)
+			var plaintext string
+			if spec.AuthOpt.ByAuthString {
+				plaintext = spec.AuthOpt.AuthString
+			}
+			if err = e.changePassword(spec.User.Username, spec.User.Hostname, pwd, plaintext, sql.String()); err != nil {
+				if ErrCredentialsContradictToHistory.Equal(err) {
+					return err
+				}
+				failedUsers = append(failedUsers, spec.User.String())
+			} else if !pwdSetting.expireNow && isCurrentUser(e.ctx, spec.User.Username, spec.User.Hostname) {
+				e.ctx.GetSessionVars().InSandBoxMode = false
+			}
 		}
 		exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
-		stmt, err := exec.ParseWithParams(context.TODO(), `UPDATE %n.%n SET authentication_string=%? WHERE Host=%? and User=%?;`, mysql.SystemDB, mysql.UserTable, pwd, spec.User.Hostname, spec.User.Username)
-		if err != nil {
-			return err
+		if !credentialsChanged && pwdSetting.formatAssignments(sql, credentialsChanged) {
+			sqlexec.MustFormatSQL(sql, ` WHERE Host=%? and User=%?;`, spec.User.Hostname, spec.User.Username)
+			stmt, err := exec.ParseWithParams(context.TODO(), sql.String())
+			if err != nil {
+				return err
+			}
+			if _, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt); err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
+			}
 		}
-		_, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
-		if err != nil {
-			failedUsers = append(failedUsers, spec.User.String())
+		if pwdSetting.resetsLoginFailures() {
+			// Unlocking the account or changing the failed-login tracking also unblocks the account.
+			stmt, err := exec.ParseWithParams(context.TODO(), `DELETE FROM %n.%n WHERE Host=%? and User=%?;`, mysql.SystemDB, loginFailuresTable, spec.User.Hostname, spec.User.Username)
+			if err != nil {
+				return err
+			}
+			if _, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt); err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
+			}
 		}
 
 		if len(privData) > 0 {
-			stmt, err = exec.ParseWithParams(context.TODO(), "INSERT INTO %n.%n (Host, User, Priv) VALUES (%?,%?,%?) ON DUPLICATE KEY UPDATE Priv = values(Priv)", mysql.SystemDB, mysql.GlobalPrivTable, spec.User.Hostname, spec.User.Username, string(hack.String(privData)))
+			stmt, err := exec.ParseWithParams(context.TODO(), "INSERT INTO %n.%n (Host, User, Priv) VALUES (%?,%?,%?) ON DUPLICATE KEY UPDATE Priv = values(Priv)", mysql.SystemDB, mysql.GlobalPrivTable, spec.User.Hostname, spec.User.Username, string(hack.String(privData)))
 			if err != nil {
 				return err
 			}
@@ -1069,6 +1198,16 @@ func (e *SimpleExec) executeRenameUser(s *ast.RenameUserStmt) error {
 			break
 		}
 
+		// rename the password history and failed logins.
+		if err = renameUserHostInSystemTable(sqlExecutor, passwordHistoryTable, "User", "Host", userToUser); err != nil {
+			failedUser = oldUser.String() + " TO " + newUser.String() + " mysql." + passwordHistoryTable + " error"
+			break
+		}
+		if err = renameUserHostInSystemTable(sqlExecutor, loginFailuresTable, "User", "Host", userToUser); err != nil {
+			failedUser = oldUser.String() + " TO " + newUser.String() + " mysql." + loginFailuresTable + " error"
+			break
+		}
+
 		//TODO: need update columns_priv once we implement columns_priv functionality.
 		// When that is added, please refactor both executeRenameUser and executeDropUser to use an array of tables
 		// to loop over, so it is easier to maintain.
@@ -1220,6 +1359,19 @@ func (e *SimpleExec) executeDropUser(s *ast.DropUserStmt) error {
 			break
 		}
 
+		// delete the password history and failed logins.
+		for _, table := range []string{passwordHistoryTable, loginFailuresTable} {
+			sql.Reset()
+			sqlexec.MustFormatSQL(sql, `DELETE FROM %n.%n WHERE Host = %? and User = %?;`, mysql.SystemDB, table, user.Hostname, user.Username)
+			if _, err = sqlExecutor.ExecuteInternal(context.TODO(), sql.String()); err != nil {
+				failedUsers = append(failedUsers, user.String())
+				break
+			}
+		}
+		if len(failedUsers) > 0 {
+			break
+		}
+
 		//TODO: need delete columns_priv once we implement columns_priv functionality.
 	}
 
@@ -1291,6 +1443,9 @@ func (e *SimpleExec) executeSetPwd(s *ast.SetPwdStmt) error {
 		u = s.User.Username
 		h = s.User.Hostname
 	}
+	if e.ctx.GetSessionVars().InSandBoxMode && !isCurrentUser(e.ctx, u, h) {
+		return ErrMustChangePassword.GenWithStackByArgs()
+	}
 	exists, err := userExists(e.ctx, u, h)
 	if err != nil {
 		return err
@@ -1299,13 +1454,22 @@ func (e *SimpleExec) executeSetPwd(s *ast.SetPwdStmt) error {
 		return errors.Trace(ErrPasswordNoMatch)
 	}
 
-	// update mysql.user
-	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
-	stmt, err := exec.ParseWithParams(context.TODO(), `UPDATE %n.%n SET authentication_string=%? WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, auth.EncodePassword(s.Password), u, h)
+	authPlugin, err := userAuthPlugin(e.ctx, u, h)
 	if err != nil {
 		return err
 	}
-	_, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
+	pwd, err := encodePasswordForUser(authPlugin, s.Password)
+	if err != nil {
+		return err
+	}
+
+	// update mysql.user
+	sql := new(strings.Builder)
+	sqlexec.MustFormatSQL(sql, `UPDATE %n.%n SET authentication_string=%?, Password_expired='N', Password_last_changed=CURRENT_TIMESTAMP() WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, pwd, u, h)
+	err = e.changePassword(u, h, pwd, s.Password, sql.String())
+	if err == nil && isCurrentUser(e.ctx, u, h) {
+		e.ctx.GetSessionVars().InSandBoxMode = false
+	}
 	domain.GetDomain(e.ctx).NotifyUpdatePrivilege(e.ctx)
 	return err
 }
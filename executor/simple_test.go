@@ -16,6 +16,8 @@ package executor_test
 import (
 	"context"
 	"strconv"
+	"strings"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/errors"
@@ -27,8 +29,11 @@ import (
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/plugin"
+	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/util"
@@ -512,6 +517,190 @@ func (s *testSuite3) TestSetPwd(c *C) {
 
 }
 
+func (s *testSuite3) TestPasswordReusePolicy(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`CREATE USER 'reuse'@'localhost' IDENTIFIED BY 'p0'`)
+	defer tk.MustExec(`DROP USER IF EXISTS 'reuse'@'localhost'`)
+	history := func() *testkit.Result {
+		return tk.MustQuery(`SELECT Password FROM mysql.password_history WHERE User = 'reuse' ORDER BY Password_timestamp`)
+	}
+	// The password history isn't recorded without the policy.
+	history().Check(testkit.Rows())
+
+	// ALTER USER without the credentials keeps the password.
+	tk.MustExec(`ALTER USER 'reuse'@'localhost' ACCOUNT LOCK`)
+	tk.MustQuery(`SELECT authentication_string, Account_locked FROM mysql.user WHERE User = 'reuse'`).Check(testkit.Rows(auth.EncodePassword("p0") + " Y"))
+
+	// The latest 2 passwords can't be reused.
+	tk.MustExec(`ALTER USER 'reuse'@'localhost' PASSWORD HISTORY 2`)
+	tk.MustQuery(`SELECT Password_reuse_history, Password_reuse_time FROM mysql.user WHERE User = 'reuse'`).Check(testkit.Rows("2 <nil>"))
+	tk.MustExec(`ALTER USER 'reuse'@'localhost' IDENTIFIED BY 'p1'`)
+	time.Sleep(time.Millisecond)
+	tk.MustExec(`SET PASSWORD FOR 'reuse'@'localhost' = 'p2'`)
+	err := tk.ExecToErr(`ALTER USER 'reuse'@'localhost' IDENTIFIED BY 'p1'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrCredentialsContradictToHistory), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr(`SET PASSWORD FOR 'reuse'@'localhost' = 'p2'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrCredentialsContradictToHistory), IsTrue, Commentf("err %v", err))
+	time.Sleep(time.Millisecond)
+	tk.MustExec(`ALTER USER 'reuse'@'localhost' IDENTIFIED BY 'p3'`)
+	history().Check(testkit.Rows(auth.EncodePassword("p2"), auth.EncodePassword("p3")))
+	time.Sleep(time.Millisecond)
+	tk.MustExec(`ALTER USER 'reuse'@'localhost' IDENTIFIED BY 'p1'`)
+	// The empty password isn't restricted.
+	tk.MustExec(`ALTER USER 'reuse'@'localhost' IDENTIFIED BY ''`)
+	tk.MustExec(`ALTER USER 'reuse'@'localhost' IDENTIFIED BY ''`)
+
+	// The passwords changed in the latest day can't be reused.
+	tk.MustExec(`ALTER USER 'reuse'@'localhost' PASSWORD HISTORY DEFAULT PASSWORD REUSE INTERVAL 1 DAY`)
+	tk.MustQuery(`SELECT Password_reuse_history, Password_reuse_time FROM mysql.user WHERE User = 'reuse'`).Check(testkit.Rows("<nil> 1"))
+	err = tk.ExecToErr(`ALTER USER 'reuse'@'localhost' IDENTIFIED BY 'p3'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrCredentialsContradictToHistory), IsTrue, Commentf("err %v", err))
+	tk.MustExec(`UPDATE mysql.password_history SET Password_timestamp = Password_timestamp - INTERVAL 2 DAY WHERE User = 'reuse'`)
+	tk.MustExec(`ALTER USER 'reuse'@'localhost' IDENTIFIED BY 'p3'`)
+	history().Check(testkit.Rows(auth.EncodePassword("p3")))
+
+	// The global policy applies to the accounts without their own.
+	tk.MustExec(`SET GLOBAL password_history = 1`)
+	defer tk.MustExec(`SET GLOBAL password_history = DEFAULT`)
+	tk.MustExec(`CREATE USER 'reuse2'@'localhost' IDENTIFIED BY 'p0'`)
+	err = tk.ExecToErr(`SET PASSWORD FOR 'reuse2'@'localhost' = 'p0'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrCredentialsContradictToHistory), IsTrue, Commentf("err %v", err))
+	tk.MustExec(`RENAME USER 'reuse2'@'localhost' TO 'reuse3'@'localhost'`)
+	tk.MustQuery(`SELECT User FROM mysql.password_history WHERE User LIKE 'reuse_'`).Check(testkit.Rows("reuse3"))
+
+	// The policy of the account applies to the password set by the same statement.
+	tk.MustExec(`CREATE USER 'reuse4'@'localhost' IDENTIFIED BY 'p0' PASSWORD HISTORY 0`)
+	tk.MustQuery(`SELECT COUNT(*) FROM mysql.password_history WHERE User = 'reuse4'`).Check(testkit.Rows("0"))
+	tk.MustExec(`SET PASSWORD FOR 'reuse4'@'localhost' = 'p0'`)
+	err = tk.ExecToErr(`ALTER USER 'reuse3'@'localhost' IDENTIFIED BY 'p0' PASSWORD HISTORY DEFAULT`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrCredentialsContradictToHistory), IsTrue, Commentf("err %v", err))
+	tk.MustExec(`ALTER USER 'reuse3'@'localhost' IDENTIFIED BY 'p0' PASSWORD HISTORY 0`)
+	tk.MustQuery(`SELECT Password_reuse_history FROM mysql.user WHERE User = 'reuse3'`).Check(testkit.Rows("0"))
+
+	tk.MustExec(`DROP USER 'reuse'@'localhost', 'reuse3'@'localhost', 'reuse4'@'localhost'`)
+	tk.MustQuery(`SELECT COUNT(*) FROM mysql.password_history WHERE User LIKE 'reuse%'`).Check(testkit.Rows("0"))
+}
+
+func (s *testSuite3) TestAuthenticationPlugin(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+
+	pluginName, pluginVersion := "tidb_test_auth", uint16(1)
+	plugin.SetTestHook(func(p *plugin.Plugin, dir string, pluginID plugin.ID) (manifest func() *plugin.Manifest, err error) {
+		return func() *plugin.Manifest {
+			m := &plugin.AuthenticationManifest{
+				Manifest: plugin.Manifest{
+					Kind:    plugin.Authentication,
+					Name:    pluginName,
+					Version: pluginVersion,
+					OnInit: func(ctx context.Context, manifest *plugin.Manifest) error {
+						return nil
+					},
+				},
+				ClientPluginName: "mysql_clear_password",
+				AuthenticateUser: func(authUser, authHost, authString string, authentication, salt []byte) error {
+					if authString != "token:"+string(authentication) {
+						return errors.New("invalid token")
+					}
+					return nil
+				},
+				GenerateAuthenticationString: func(password string) (string, error) {
+					return "token:" + password, nil
+				},
+				ValidateAuthenticationString: func(authString string) bool {
+					return strings.HasPrefix(authString, "token:")
+				},
+			}
+			return plugin.ExportManifest(m)
+		}, nil
+	})
+	cfg := plugin.Config{Plugins: []string{pluginName + "-" + strconv.Itoa(int(pluginVersion))}}
+	c.Assert(plugin.Load(context.Background(), cfg), IsNil)
+	c.Assert(plugin.Init(context.Background(), cfg), IsNil)
+	defer plugin.Shutdown(context.Background())
+
+	tk.MustExec(`CREATE USER 'tauth1'@'localhost' IDENTIFIED WITH tidb_test_auth BY 'abc', 'tauth2'@'localhost' IDENTIFIED BY 'abc', 'tauth3'@'localhost' IDENTIFIED WITH 'tidb_test_auth' AS 'token:xyz'`)
+	tk.MustQuery(`SELECT User, plugin, authentication_string FROM mysql.user WHERE User LIKE 'tauth%' ORDER BY User`).Check(testkit.Rows(
+		"tauth1 tidb_test_auth token:abc",
+		"tauth2 mysql_native_password "+auth.EncodePassword("abc"),
+		"tauth3 tidb_test_auth token:xyz",
+	))
+	tk.MustQuery(`SHOW CREATE USER 'tauth1'@'localhost'`).Check(testkit.Rows(
+		"CREATE USER 'tauth1'@'localhost' IDENTIFIED WITH 'tidb_test_auth' AS 'token:abc' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+	_, err := tk.Exec(`CREATE USER 'tauth4'@'localhost' IDENTIFIED WITH 'tidb_test_auth' AS 'xyz'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrPasswordFormat), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec(`CREATE USER 'tauth4'@'localhost' IDENTIFIED WITH 'auth_not_exists'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrPluginIsNotLoaded), IsTrue, Commentf("err %v", err))
+
+	// The plugin is kept if it's not specified.
+	tk.MustExec(`ALTER USER 'tauth1'@'localhost' IDENTIFIED BY 'def'`)
+	tk.MustExec(`SET PASSWORD FOR 'tauth3'@'localhost' = 'ghi'`)
+	tk.MustExec(`ALTER USER 'tauth2'@'localhost' IDENTIFIED WITH 'tidb_test_auth' BY 'abc'`)
+	tk.MustQuery(`SELECT User, plugin, authentication_string FROM mysql.user WHERE User LIKE 'tauth%' ORDER BY User`).Check(testkit.Rows(
+		"tauth1 tidb_test_auth token:def",
+		"tauth2 tidb_test_auth token:abc",
+		"tauth3 tidb_test_auth token:ghi",
+	))
+
+	// The connection is verified by the plugin.
+	se, err := session.CreateSession4Test(s.store)
+	c.Assert(err, IsNil)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "tauth1", Hostname: "localhost"}, []byte("def"), nil), IsTrue)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "tauth1", Hostname: "localhost"}, []byte("abc"), nil), IsFalse)
+
+	tk.MustExec(`ALTER USER 'tauth1'@'localhost' IDENTIFIED WITH 'mysql_native_password' BY 'abc'`)
+	tk.MustQuery(`SELECT plugin, authentication_string FROM mysql.user WHERE User = 'tauth1'`).Check(testkit.Rows(
+		"mysql_native_password " + auth.EncodePassword("abc")))
+
+	// The plugin is taken from the statement rather than its text, so comments don't matter.
+	tk.MustExec(`CREATE USER 'tauth4'@'localhost' /* IDENTIFIED WITH mysql_native_password */ IDENTIFIED WITH 'tidb_test_auth'`)
+	tk.MustQuery(`SELECT plugin, authentication_string FROM mysql.user WHERE User = 'tauth4'`).Check(testkit.Rows("tidb_test_auth "))
+	tk.MustExec(`DROP USER 'tauth1'@'localhost', 'tauth2'@'localhost', 'tauth3'@'localhost', 'tauth4'@'localhost'`)
+}
+
+func (s *testSerialSuite) TestCachingSha2Password(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`CREATE USER 'sha2user1'@'localhost' IDENTIFIED WITH caching_sha2_password BY 'abc', 'sha2user2'@'localhost' IDENTIFIED WITH 'caching_sha2_password'`)
+	defer tk.MustExec(`DROP USER IF EXISTS 'sha2user1'@'localhost', 'sha2user2'@'localhost', 'sha2user3'@'localhost'`)
+	authString := tk.MustQuery(`SELECT authentication_string FROM mysql.user WHERE User = 'sha2user1'`).Rows()[0][0].(string)
+	c.Assert(authString, HasLen, privileges.Sha2AuthStringLen)
+	c.Assert(privileges.CheckSha2Password(authString, "abc"), IsTrue)
+	tk.MustQuery(`SELECT User, plugin, authentication_string FROM mysql.user WHERE User = 'sha2user2'`).Check(testkit.Rows("sha2user2 caching_sha2_password "))
+	tk.MustQuery(`SHOW CREATE USER 'sha2user1'@'localhost'`).Check(testkit.Rows(
+		"CREATE USER 'sha2user1'@'localhost' IDENTIFIED WITH 'caching_sha2_password' AS '" + authString + "' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+
+	// The authentication string can be specified directly.
+	tk.MustExec(`ALTER USER 'sha2user2'@'localhost' IDENTIFIED WITH 'caching_sha2_password' AS '` + authString + `'`)
+	tk.MustQuery(`SELECT authentication_string FROM mysql.user WHERE User = 'sha2user2'`).Check(testkit.Rows(authString))
+	err := tk.ExecToErr(`ALTER USER 'sha2user2'@'localhost' IDENTIFIED WITH 'caching_sha2_password' AS '` + auth.EncodePassword("abc") + `'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrPasswordFormat), IsTrue, Commentf("err %v", err))
+
+	// The plugin is kept if it's not specified.
+	tk.MustExec(`SET PASSWORD FOR 'sha2user1'@'localhost' = 'def'`)
+	authString = tk.MustQuery(`SELECT authentication_string FROM mysql.user WHERE User = 'sha2user1'`).Rows()[0][0].(string)
+	c.Assert(privileges.CheckSha2Password(authString, "def"), IsTrue)
+
+	// The connection is verified by the plaintext password.
+	se, err := session.CreateSession4Test(s.store)
+	c.Assert(err, IsNil)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "sha2user1", Hostname: "localhost"}, []byte("def"), nil), IsTrue)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "sha2user1", Hostname: "localhost"}, []byte("abc"), nil), IsFalse)
+
+	// The salted passwords in the history are compared by the plaintext.
+	tk.MustExec(`UPDATE mysql.user SET Password_reuse_history = 1 WHERE User = 'sha2user1'`)
+	tk.MustExec(`ALTER USER 'sha2user1'@'localhost' IDENTIFIED BY 'ghi'`)
+	err = tk.ExecToErr(`ALTER USER 'sha2user1'@'localhost' IDENTIFIED BY 'ghi'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrCredentialsContradictToHistory), IsTrue, Commentf("err %v", err))
+
+	// The default authentication plugin applies to the accounts created without the plugin.
+	variable.SetSysVar(variable.DefaultAuthPlugin, mysql.AuthCachingSha2Password)
+	defer variable.SetSysVar(variable.DefaultAuthPlugin, mysql.AuthNativePassword)
+	tk.MustExec(`CREATE USER 'sha2user3'@'localhost' IDENTIFIED BY 'abc'`)
+	tk.MustQuery(`SELECT plugin FROM mysql.user WHERE User = 'sha2user3'`).Check(testkit.Rows(mysql.AuthCachingSha2Password))
+	tk.MustExec(`ALTER USER 'sha2user1'@'localhost' IDENTIFIED WITH 'mysql_native_password' BY 'abc'`)
+	tk.MustQuery(`SELECT plugin, authentication_string FROM mysql.user WHERE User = 'sha2user1'`).Check(testkit.Rows(
+		"mysql_native_password " + auth.EncodePassword("abc")))
+}
+
 func (s *testSuite3) TestKillStmt(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -41,6 +41,12 @@ type UpdateExec struct {
 	// The value is true if the row is changed, or false otherwise
 	updatedRowKeys map[int]*kv.HandleMap
 	tblID2table    map[int64]table.Table
+	// tblID2constraints stores the check constraints which should be checked for the updated rows of each table.
+	tblID2constraints map[int64][]*table.Constraint
+	// tblID2fkChecker stores the foreign key checkers for the updated rows of each table.
+	tblID2fkChecker map[int64]*foreignKeyChecker
+	// tblID2rowPolicy stores the row-level security policy checkers for the updated rows of each table.
+	tblID2rowPolicy map[int64]*rowPolicyChecker
 	// mergedRowData is a map for unique (Table, handle) pair.
 	// The value is cached table row
 	mergedRowData          map[int64]*kv.HandleMap
@@ -185,14 +191,16 @@ func (e *UpdateExec) exec(ctx context.Context, schema *expression.Schema, row, n
 		flags := bAssignFlag[content.Start:content.End]
 
 		// Update row
-		changed, err1 := updateRecord(ctx, e.ctx, handle, oldData, newTableData, flags, tbl, false, e.memTracker)
+		changed, err1 := updateRecord(ctx, e.ctx, handle, oldData, newTableData, flags, tbl, e.tblID2constraints[content.TblID], e.tblID2fkChecker[content.TblID],
+			e.tblID2rowPolicy[content.TblID], false, e.memTracker)
 		if err1 == nil {
 			e.updatedRowKeys[content.Start].Set(handle, changed)
 			continue
 		}
 
 		sc := e.ctx.GetSessionVars().StmtCtx
-		if kv.ErrKeyExists.Equal(err1) && sc.DupKeyAsWarning {
+		if (kv.ErrKeyExists.Equal(err1) || table.ErrCheckConstraintViolated.Equal(err1) || ErrNoReferencedRow2.Equal(err1) ||
+			ErrRowPolicyViolated.Equal(err1)) && sc.DupKeyAsWarning {
 			sc.AppendWarning(err1)
 			continue
 		}
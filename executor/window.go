@@ -18,12 +18,17 @@ import (
 
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/memory"
 )
 
 // WindowExec is the executor for window functions.
@@ -35,118 +40,156 @@ type WindowExec struct {
 	childResult *chunk.Chunk
 	// executed indicates the child executor is drained or something unexpected happened.
 	executed bool
-	// resultChunks stores the chunks to return
-	resultChunks []*chunk.Chunk
-	// remainingRowsInChunk indicates how many rows the resultChunks[i] is not prepared.
-	remainingRowsInChunk []int
+	// partition stores the rows of the current window partition, which may be spilled to disk.
+	partition *windowPartition
+	// outputIdx is the offset of the next row of the current partition to be returned.
+	outputIdx uint64
+	// childColIdxs are the indexes of the child columns returned by the executor.
+	childColIdxs []int
 
 	numWindowFuncs int
 	processor      windowProcessor
+
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *WindowExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.diskTracker = disk.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+
+	e.partition = newWindowPartition(retTypes(e.children[0]), e.maxChunkSize)
+	e.partition.rowContainer.GetMemTracker().AttachTo(e.memTracker)
+	e.partition.rowContainer.GetMemTracker().SetLabel(memory.LabelForRowContainer)
+	e.partition.rowContainer.GetDiskTracker().AttachTo(e.diskTracker)
+	e.partition.rowContainer.GetDiskTracker().SetLabel(memory.LabelForRowContainer)
+	if config.GetGlobalConfig().OOMUseTmpStorage {
+		actionSpill := e.partition.rowContainer.ActionSpill()
+		failpoint.Inject("testWindowRowContainerSpill", func(val failpoint.Value) {
+			if val.(bool) {
+				actionSpill = e.partition.rowContainer.ActionSpillForTest()
+			}
+		})
+		e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(actionSpill)
+	}
+
+	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
+	e.childColIdxs = make([]int, 0, len(columns))
+	for _, col := range columns {
+		e.childColIdxs = append(e.childColIdxs, col.Index)
+	}
+	e.childResult = nil
+	e.executed = false
+	e.outputIdx = 0
+	e.groupChecker.reset()
+	e.processor.resetPartialResult()
+	return nil
 }
 
 // Close implements the Executor Close interface.
 func (e *WindowExec) Close() error {
+	if e.partition != nil {
+		failpoint.Inject("testWindowRowContainerSpill", func(val failpoint.Value) {
+			if val.(bool) {
+				e.partition.rowContainer.ActionSpill().WaitForTest()
+			}
+		})
+		if err := e.partition.rowContainer.Close(); err != nil {
+			return err
+		}
+		e.partition = nil
+	}
+	e.childResult = nil
+	e.memTracker = nil
+	e.diskTracker = nil
 	return errors.Trace(e.baseExecutor.Close())
 }
 
 // Next implements the Executor Next interface.
 func (e *WindowExec) Next(ctx context.Context, chk *chunk.Chunk) error {
 	chk.Reset()
-	for !e.executed && !e.preparedChunkAvailable() {
-		err := e.consumeOneGroup(ctx)
-		if err != nil {
-			e.executed = true
+	for !chk.IsFull() {
+		if e.outputIdx == e.partition.NumRows() {
+			if e.executed {
+				return nil
+			}
+			if err := e.fetchNextPartition(ctx); err != nil {
+				e.executed = true
+				return err
+			}
+			continue
+		}
+		if err := e.appendPartitionResult(chk); err != nil {
 			return err
 		}
 	}
-	if len(e.resultChunks) > 0 {
-		chk.SwapColumns(e.resultChunks[0])
-		e.resultChunks[0] = nil // GC it. TODO: Reuse it.
-		e.resultChunks = e.resultChunks[1:]
-		e.remainingRowsInChunk = e.remainingRowsInChunk[1:]
-	}
 	return nil
 }
 
-func (e *WindowExec) preparedChunkAvailable() bool {
-	return len(e.resultChunks) > 0 && e.remainingRowsInChunk[0] == 0
-}
-
-func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
-	var groupRows []chunk.Row
-	if e.groupChecker.isExhausted() {
-		eof, err := e.fetchChild(ctx)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		if eof {
-			e.executed = true
-			return e.consumeGroupRows(groupRows)
-		}
-		_, err = e.groupChecker.splitIntoGroups(e.childResult)
-		if err != nil {
-			return errors.Trace(err)
-		}
+// fetchNextPartition reads the rows of the next partition from the child executor,
+// then lets the window functions consume them.
+func (e *WindowExec) fetchNextPartition(ctx context.Context) error {
+	e.outputIdx = 0
+	e.processor.resetPartialResult()
+	if err := e.partition.reset(); err != nil {
+		return err
 	}
-	begin, end := e.groupChecker.getNextGroup()
-	for i := begin; i < end; i++ {
-		groupRows = append(groupRows, e.childResult.GetRow(i))
-	}
-
-	for meetLastGroup := end == e.childResult.NumRows(); meetLastGroup; {
-		meetLastGroup = false
-		eof, err := e.fetchChild(ctx)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		if eof {
-			e.executed = true
-			return e.consumeGroupRows(groupRows)
+	for {
+		if e.groupChecker.isExhausted() {
+			eof, err := e.fetchChild(ctx)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if eof {
+				e.executed = true
+				break
+			}
+			isFirstGroupSameAsPrev, err := e.groupChecker.splitIntoGroups(e.childResult)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			// The partition ends at the end of the previous child chunk.
+			if e.partition.NumRows() > 0 && !isFirstGroupSameAsPrev {
+				break
+			}
 		}
-
-		isFirstGroupSameAsPrev, err := e.groupChecker.splitIntoGroups(e.childResult)
-		if err != nil {
-			return errors.Trace(err)
+		begin, end := e.groupChecker.getNextGroup()
+		if err := e.partition.append(e.childResult, begin, end); err != nil {
+			return err
 		}
-
-		if isFirstGroupSameAsPrev {
-			begin, end = e.groupChecker.getNextGroup()
-			for i := begin; i < end; i++ {
-				groupRows = append(groupRows, e.childResult.GetRow(i))
-			}
-			meetLastGroup = end == e.childResult.NumRows()
+		// The partition ends inside the child chunk.
+		if end < e.childResult.NumRows() {
+			break
 		}
 	}
-	return e.consumeGroupRows(groupRows)
-}
-
-func (e *WindowExec) consumeGroupRows(groupRows []chunk.Row) (err error) {
-	remainingRowsInGroup := len(groupRows)
-	if remainingRowsInGroup == 0 {
+	if err := e.partition.finish(); err != nil {
+		return err
+	}
+	if e.partition.NumRows() == 0 {
 		return nil
 	}
-	for i := 0; i < len(e.resultChunks); i++ {
-		remained := mathutil.Min(e.remainingRowsInChunk[i], remainingRowsInGroup)
-		e.remainingRowsInChunk[i] -= remained
-		remainingRowsInGroup -= remained
+	return errors.Trace(e.processor.consumeGroupRows(e.ctx, e.partition))
+}
 
-		// TODO: Combine these three methods.
-		// The old implementation needs the processor has these three methods
-		// but now it does not have to.
-		groupRows, err = e.processor.consumeGroupRows(e.ctx, groupRows)
+// appendPartitionResult appends the next rows of the current partition and their
+// window function results to chk.
+func (e *WindowExec) appendPartitionResult(chk *chunk.Chunk) error {
+	remained := mathutil.Min(chk.RequiredRows()-chk.NumRows(), int(e.partition.NumRows()-e.outputIdx))
+	for i := 0; i < remained; i++ {
+		row, err := e.partition.GetRow(e.outputIdx + uint64(i))
 		if err != nil {
-			return errors.Trace(err)
-		}
-		_, err = e.processor.appendResult2Chunk(e.ctx, groupRows, e.resultChunks[i], remained)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		if remainingRowsInGroup == 0 {
-			e.processor.resetPartialResult()
-			break
+			return err
 		}
+		chk.AppendPartialRowByColIdxs(0, row, e.childColIdxs)
 	}
-	return nil
+	e.outputIdx += uint64(remained)
+	return errors.Trace(e.processor.appendResult2Chunk(e.ctx, e.partition, chk, remained))
 }
 
 func (e *WindowExec) fetchChild(ctx context.Context) (EOF bool, err error) {
@@ -156,41 +199,99 @@ func (e *WindowExec) fetchChild(ctx context.Context) (EOF bool, err error) {
 		return false, errors.Trace(err)
 	}
 	// No more data.
-	numRows := childResult.NumRows()
-	if numRows == 0 {
+	if childResult.NumRows() == 0 {
 		return true, nil
 	}
+	e.childResult = childResult
+	return false, nil
+}
 
-	resultChk := chunk.New(e.retFieldTypes, 0, numRows)
-	err = e.copyChk(childResult, resultChk)
-	if err != nil {
-		return false, err
+// windowPartition keeps the rows of a window partition in a RowContainer, which
+// can be spilled to disk when the memory quota is exceeded. The rows are copied
+// into chunks of the same capacity, so they can be read back by their offsets.
+type windowPartition struct {
+	rowContainer *chunk.RowContainer
+	// staging is the chunk being filled, it's added into the rowContainer when
+	// it's full or the partition ends.
+	staging   *chunk.Chunk
+	chunkSize int
+	numRows   uint64
+}
+
+func newWindowPartition(fieldTypes []*types.FieldType, chunkSize int) *windowPartition {
+	return &windowPartition{
+		rowContainer: chunk.NewRowContainer(fieldTypes, chunkSize),
+		chunkSize:    chunkSize,
 	}
-	e.resultChunks = append(e.resultChunks, resultChk)
-	e.remainingRowsInChunk = append(e.remainingRowsInChunk, numRows)
+}
 
-	e.childResult = childResult
-	return false, nil
+func (p *windowPartition) reset() error {
+	p.staging = nil
+	p.numRows = 0
+	return p.rowContainer.Reset()
 }
 
-func (e *WindowExec) copyChk(src, dst *chunk.Chunk) error {
-	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
-	for i, col := range columns {
-		if err := dst.MakeRefTo(i, src, col.Index); err != nil {
-			return err
+// append copies the rows in [begin, end) of chk into the partition.
+func (p *windowPartition) append(chk *chunk.Chunk, begin, end int) error {
+	for begin < end {
+		if p.staging == nil {
+			p.staging = p.rowContainer.AllocChunk()
+		}
+		n := mathutil.Min(end-begin, p.chunkSize-p.staging.NumRows())
+		p.staging.Append(chk, begin, begin+n)
+		begin += n
+		p.numRows += uint64(n)
+		if p.staging.NumRows() == p.chunkSize {
+			if err := p.finish(); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// finish adds the staging chunk into the rowContainer.
+func (p *windowPartition) finish() error {
+	if p.staging == nil || p.staging.NumRows() == 0 {
+		return nil
+	}
+	err := p.rowContainer.Add(p.staging)
+	p.staging = nil
+	return err
+}
+
+// NumRows implements the aggfuncs.WindowRows interface.
+func (p *windowPartition) NumRows() uint64 {
+	return p.numRows
+}
+
+// GetRow implements the aggfuncs.WindowRows interface.
+func (p *windowPartition) GetRow(idx uint64) (chunk.Row, error) {
+	chunkSize := uint64(p.chunkSize)
+	return p.rowContainer.GetRow(chunk.RowPtr{ChkIdx: uint32(idx / chunkSize), RowIdx: uint32(idx % chunkSize)})
+}
+
+// fetchWindowRows reads the rows in [start, end) of the partition into buf.
+func fetchWindowRows(rows aggfuncs.WindowRows, start, end uint64, buf []chunk.Row) ([]chunk.Row, error) {
+	buf = buf[:0]
+	for i := start; i < end; i++ {
+		row, err := rows.GetRow(i)
+		if err != nil {
+			return buf, err
+		}
+		buf = append(buf, row)
+	}
+	return buf, nil
+}
+
 // windowProcessor is the interface for processing different kinds of windows.
 type windowProcessor interface {
 	// consumeGroupRows updates the result for an window function using the input rows
 	// which belong to the same partition.
-	consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error)
-	// appendResult2Chunk appends the final results to chunk.
-	// It is called when there are no more rows in current partition.
-	appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error)
+	consumeGroupRows(ctx sessionctx.Context, rows aggfuncs.WindowRows) error
+	// appendResult2Chunk appends the final results of the next `remained` rows of the
+	// partition to chunk.
+	appendResult2Chunk(ctx sessionctx.Context, rows aggfuncs.WindowRows, chk *chunk.Chunk, remained int) error
 	// resetPartialResult resets the partial result to the original state for a specific window function.
 	resetPartialResult()
 }
@@ -198,32 +299,55 @@ type windowProcessor interface {
 type aggWindowProcessor struct {
 	windowFuncs    []aggfuncs.AggFunc
 	partialResults []aggfuncs.PartialResult
+	// rowsBuffer is used to feed the rows of the partition to the window functions in batches.
+	rowsBuffer []chunk.Row
+	batchSize  int
 }
 
-func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
+func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows aggfuncs.WindowRows) error {
+	var batchFuncs []int
 	for i, windowFunc := range p.windowFuncs {
-		// @todo Add memory trace
-		_, err := windowFunc.UpdatePartialResult(ctx, rows, p.partialResults[i])
+		// The functions like LEAD and RANK read the rows back by index instead of buffering them.
+		if windowRowsFunc, ok := windowFunc.(aggfuncs.WindowRowsAggFunc); ok {
+			windowRowsFunc.SetWindowRows(rows, p.partialResults[i])
+			continue
+		}
+		batchFuncs = append(batchFuncs, i)
+	}
+	if len(batchFuncs) == 0 {
+		return nil
+	}
+	numRows := rows.NumRows()
+	for start := uint64(0); start < numRows; start += uint64(p.batchSize) {
+		end := mathutil.MinUint64(start+uint64(p.batchSize), numRows)
+		var err error
+		p.rowsBuffer, err = fetchWindowRows(rows, start, end, p.rowsBuffer)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		for _, i := range batchFuncs {
+			// @todo Add memory trace
+			_, err = p.windowFuncs[i].UpdatePartialResult(ctx, p.rowsBuffer, p.partialResults[i])
+			if err != nil {
+				return err
+			}
 		}
 	}
-	rows = rows[:0]
-	return rows, nil
+	return nil
 }
 
-func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows aggfuncs.WindowRows, chk *chunk.Chunk, remained int) error {
 	for remained > 0 {
 		for i, windowFunc := range p.windowFuncs {
 			// TODO: We can extend the agg func interface to avoid the `for` loop  here.
 			err := windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 		}
 		remained--
 	}
-	return rows, nil
+	return nil
 }
 
 func (p *aggWindowProcessor) resetPartialResult() {
@@ -238,6 +362,8 @@ type rowFrameWindowProcessor struct {
 	start          *core.FrameBound
 	end            *core.FrameBound
 	curRowIdx      uint64
+	// rowsBuffer holds the rows of the frame for the window functions which can't slide.
+	rowsBuffer []chunk.Row
 }
 
 func (p *rowFrameWindowProcessor) getStartOffset(numRows uint64) uint64 {
@@ -286,12 +412,12 @@ func (p *rowFrameWindowProcessor) getEndOffset(numRows uint64) uint64 {
 	return 0
 }
 
-func (p *rowFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+func (p *rowFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows aggfuncs.WindowRows) error {
+	return nil
 }
 
-func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
-	numRows := uint64(len(rows))
+func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows aggfuncs.WindowRows, chk *chunk.Chunk, remained int) error {
+	numRows := rows.NumRows()
 	var (
 		err                      error
 		initializedSlidingWindow bool
@@ -321,12 +447,12 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 				if slidingWindowAggFunc != nil && initializedSlidingWindow {
 					err = slidingWindowAggFunc.Slide(ctx, rows, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
 				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			}
 			continue
@@ -343,14 +469,17 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 					// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
 					minMaxSlidingWindowAggFunc.SetWindowStart(start)
 				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
+				p.rowsBuffer, err = fetchWindowRows(rows, start, end, p.rowsBuffer)
+				if err == nil {
+					_, err = windowFunc.UpdatePartialResult(ctx, p.rowsBuffer, p.partialResults[i])
+				}
 			}
 			if err != nil {
-				return nil, err
+				return err
 			}
 			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if slidingWindowAggFunc == nil {
 				windowFunc.ResetPartialResult(p.partialResults[i])
@@ -363,7 +492,7 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 	for i, windowFunc := range p.windowFuncs {
 		windowFunc.ResetPartialResult(p.partialResults[i])
 	}
-	return rows, nil
+	return nil
 }
 
 func (p *rowFrameWindowProcessor) resetPartialResult() {
@@ -381,18 +510,27 @@ type rangeFrameWindowProcessor struct {
 	orderByCols     []*expression.Column
 	// expectedCmpResult is used to decide if one value is included in the frame.
 	expectedCmpResult int64
+	// rowsBuffer holds the rows of the frame for the window functions which can't slide.
+	rowsBuffer []chunk.Row
 }
 
-func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
+func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows aggfuncs.WindowRows) (uint64, error) {
 	if p.start.UnBounded {
 		return 0, nil
 	}
-	numRows := uint64(len(rows))
+	numRows := rows.NumRows()
+	curRow, err := rows.GetRow(p.curRowIdx)
+	if err != nil {
+		return 0, err
+	}
 	for ; p.lastStartOffset < numRows; p.lastStartOffset++ {
+		row, err := rows.GetRow(p.lastStartOffset)
+		if err != nil {
+			return 0, err
+		}
 		var res int64
-		var err error
 		for i := range p.orderByCols {
-			res, _, err = p.start.CmpFuncs[i](ctx, p.orderByCols[i], p.start.CalcFuncs[i], rows[p.lastStartOffset], rows[p.curRowIdx])
+			res, _, err = p.start.CmpFuncs[i](ctx, p.orderByCols[i], p.start.CalcFuncs[i], row, curRow)
 			if err != nil {
 				return 0, err
 			}
@@ -409,16 +547,23 @@ func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows
 	return p.lastStartOffset, nil
 }
 
-func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
-	numRows := uint64(len(rows))
+func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows aggfuncs.WindowRows) (uint64, error) {
+	numRows := rows.NumRows()
 	if p.end.UnBounded {
 		return numRows, nil
 	}
+	curRow, err := rows.GetRow(p.curRowIdx)
+	if err != nil {
+		return 0, err
+	}
 	for ; p.lastEndOffset < numRows; p.lastEndOffset++ {
+		row, err := rows.GetRow(p.lastEndOffset)
+		if err != nil {
+			return 0, err
+		}
 		var res int64
-		var err error
 		for i := range p.orderByCols {
-			res, _, err = p.end.CmpFuncs[i](ctx, p.end.CalcFuncs[i], p.orderByCols[i], rows[p.curRowIdx], rows[p.lastEndOffset])
+			res, _, err = p.end.CmpFuncs[i](ctx, p.end.CalcFuncs[i], p.orderByCols[i], curRow, row)
 			if err != nil {
 				return 0, err
 			}
@@ -435,7 +580,7 @@ func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []
 	return p.lastEndOffset, nil
 }
 
-func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows aggfuncs.WindowRows, chk *chunk.Chunk, remained int) error {
 	var (
 		err                      error
 		initializedSlidingWindow bool
@@ -455,11 +600,11 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 	for ; remained > 0; lastStart, lastEnd = start, end {
 		start, err = p.getStartOffset(ctx, rows)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		end, err = p.getEndOffset(ctx, rows)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		p.curRowIdx++
 		remained--
@@ -471,12 +616,12 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 				if slidingWindowAggFunc != nil && initializedSlidingWindow {
 					err = slidingWindowAggFunc.Slide(ctx, rows, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
 				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			}
 			continue
@@ -490,14 +635,17 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 				if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
 					minMaxSlidingWindowAggFunc.SetWindowStart(start)
 				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
+				p.rowsBuffer, err = fetchWindowRows(rows, start, end, p.rowsBuffer)
+				if err == nil {
+					_, err = windowFunc.UpdatePartialResult(ctx, p.rowsBuffer, p.partialResults[i])
+				}
 			}
 			if err != nil {
-				return nil, err
+				return err
 			}
 			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if slidingWindowAggFunc == nil {
 				windowFunc.ResetPartialResult(p.partialResults[i])
@@ -510,11 +658,11 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 	for i, windowFunc := range p.windowFuncs {
 		windowFunc.ResetPartialResult(p.partialResults[i])
 	}
-	return rows, nil
+	return nil
 }
 
-func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows aggfuncs.WindowRows) error {
+	return nil
 }
 
 func (p *rangeFrameWindowProcessor) resetPartialResult() {
@@ -522,3 +670,185 @@ func (p *rangeFrameWindowProcessor) resetPartialResult() {
 	p.lastStartOffset = 0
 	p.lastEndOffset = 0
 }
+
+type groupsFrameWindowProcessor struct {
+	windowFuncs    []aggfuncs.AggFunc
+	partialResults []aggfuncs.PartialResult
+	start          *core.FrameBound
+	end            *core.FrameBound
+	orderByCols    []*expression.Column
+	// cmpFuncs are used to decide whether two rows are in the same peer group.
+	cmpFuncs []expression.CompareFunc
+	// groupStarts are the offsets of the first rows of the peer groups, the last
+	// element is the number of rows in the partition.
+	groupStarts []uint64
+	curRowIdx   uint64
+	curGroupIdx uint64
+	// rowsBuffer holds the rows of the frame for the window functions which can't slide.
+	rowsBuffer []chunk.Row
+}
+
+// consumeGroupRows splits the rows of the partition into peer groups, the rows in
+// a peer group have the same order by items.
+func (p *groupsFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows aggfuncs.WindowRows) error {
+	numRows := rows.NumRows()
+	p.groupStarts = append(p.groupStarts[:0], 0)
+	var prevRow chunk.Row
+	for i := uint64(0); i < numRows; i++ {
+		row, err := rows.GetRow(i)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			for j, col := range p.orderByCols {
+				res, _, err := p.cmpFuncs[j](ctx, col, col, prevRow, row)
+				if err != nil {
+					return err
+				}
+				if res != 0 {
+					p.groupStarts = append(p.groupStarts, i)
+					break
+				}
+			}
+		}
+		prevRow = row
+	}
+	p.groupStarts = append(p.groupStarts, numRows)
+	return nil
+}
+
+func (p *groupsFrameWindowProcessor) numGroups() uint64 {
+	return uint64(len(p.groupStarts) - 1)
+}
+
+func (p *groupsFrameWindowProcessor) getStartOffset(numRows uint64) uint64 {
+	if p.start.UnBounded {
+		return 0
+	}
+	switch p.start.Type {
+	case ast.Preceding:
+		if p.curGroupIdx >= p.start.Num {
+			return p.groupStarts[p.curGroupIdx-p.start.Num]
+		}
+		return 0
+	case ast.Following:
+		if p.start.Num < p.numGroups()-p.curGroupIdx {
+			return p.groupStarts[p.curGroupIdx+p.start.Num]
+		}
+		return numRows
+	case ast.CurrentRow:
+		return p.groupStarts[p.curGroupIdx]
+	}
+	// It will never reach here.
+	return 0
+}
+
+func (p *groupsFrameWindowProcessor) getEndOffset(numRows uint64) uint64 {
+	if p.end.UnBounded {
+		return numRows
+	}
+	switch p.end.Type {
+	case ast.Preceding:
+		if p.curGroupIdx >= p.end.Num {
+			return p.groupStarts[p.curGroupIdx-p.end.Num+1]
+		}
+		return 0
+	case ast.Following:
+		if p.end.Num < p.numGroups()-p.curGroupIdx {
+			return p.groupStarts[p.curGroupIdx+p.end.Num+1]
+		}
+		return numRows
+	case ast.CurrentRow:
+		return p.groupStarts[p.curGroupIdx+1]
+	}
+	// It will never reach here.
+	return 0
+}
+
+func (p *groupsFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows aggfuncs.WindowRows, chk *chunk.Chunk, remained int) error {
+	numRows := rows.NumRows()
+	var (
+		err                      error
+		initializedSlidingWindow bool
+		start                    uint64
+		end                      uint64
+		lastStart                uint64
+		lastEnd                  uint64
+		shiftStart               uint64
+		shiftEnd                 uint64
+	)
+	slidingWindowAggFuncs := make([]aggfuncs.SlidingWindowAggFunc, len(p.windowFuncs))
+	for i, windowFunc := range p.windowFuncs {
+		if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
+			slidingWindowAggFuncs[i] = slidingWindowAggFunc
+		}
+	}
+	for ; remained > 0; lastStart, lastEnd = start, end {
+		for p.curRowIdx >= p.groupStarts[p.curGroupIdx+1] {
+			p.curGroupIdx++
+		}
+		start = p.getStartOffset(numRows)
+		end = p.getEndOffset(numRows)
+		p.curRowIdx++
+		remained--
+		shiftStart = start - lastStart
+		shiftEnd = end - lastEnd
+		if start >= end {
+			for i, windowFunc := range p.windowFuncs {
+				slidingWindowAggFunc := slidingWindowAggFuncs[i]
+				if slidingWindowAggFunc != nil && initializedSlidingWindow {
+					err = slidingWindowAggFunc.Slide(ctx, rows, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+					if err != nil {
+						return err
+					}
+				}
+				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		for i, windowFunc := range p.windowFuncs {
+			slidingWindowAggFunc := slidingWindowAggFuncs[i]
+			if slidingWindowAggFunc != nil && initializedSlidingWindow {
+				err = slidingWindowAggFunc.Slide(ctx, rows, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+			} else {
+				// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of window, to compare
+				// whether elements inside deque are out of current window.
+				if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
+					// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
+					minMaxSlidingWindowAggFunc.SetWindowStart(start)
+				}
+				p.rowsBuffer, err = fetchWindowRows(rows, start, end, p.rowsBuffer)
+				if err == nil {
+					_, err = windowFunc.UpdatePartialResult(ctx, p.rowsBuffer, p.partialResults[i])
+				}
+			}
+			if err != nil {
+				return err
+			}
+			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
+			if err != nil {
+				return err
+			}
+			if slidingWindowAggFunc == nil {
+				windowFunc.ResetPartialResult(p.partialResults[i])
+			}
+		}
+		if !initializedSlidingWindow {
+			initializedSlidingWindow = true
+		}
+	}
+	for i, windowFunc := range p.windowFuncs {
+		windowFunc.ResetPartialResult(p.partialResults[i])
+	}
+	return nil
+}
+
+func (p *groupsFrameWindowProcessor) resetPartialResult() {
+	p.curRowIdx = 0
+	p.curGroupIdx = 0
+	p.groupStarts = p.groupStarts[:0]
+}
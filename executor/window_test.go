@@ -15,8 +15,12 @@ package executor_test
 
 import (
 	"fmt"
+	"strings"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/util/testkit"
 )
 
@@ -430,3 +434,146 @@ func (s *testSuite7) TestIssue24264(c *C) {
 		"8297270320597030697",
 		"<nil>"))
 }
+
+func (s *testSuite7) TestWindowGroupsFrame(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (1, 2), (1, 2), (1, 3), (2, 1), (2, 4)")
+	tk.MustQuery("select a, b, sum(b) over (partition by a order by b groups between 1 preceding and current row) from t").Sort().Check(testkit.Rows(
+		"1 1 1", "1 2 5", "1 2 5", "1 3 7", "2 1 1", "2 4 5"))
+	tk.MustQuery("select b, count(*) over (order by b groups between current row and 1 following) from t").Sort().Check(testkit.Rows(
+		"1 4", "1 4", "2 3", "2 3", "3 2", "4 1"))
+	tk.MustQuery("select b, count(*) over (order by b groups between 2 preceding and 1 preceding) from t").Sort().Check(testkit.Rows(
+		"1 0", "1 0", "2 2", "2 2", "3 4", "4 3"))
+	tk.MustQuery("select b, max(b) over (order by b desc groups between unbounded preceding and 1 following) from t").Sort().Check(testkit.Rows(
+		"1 4", "1 4", "2 4", "2 4", "3 4", "4 4"))
+	tk.MustQuery("select b, first_value(b) over (order by b desc groups 1 preceding) from t").Sort().Check(testkit.Rows(
+		"1 2", "1 2", "2 3", "2 3", "3 4", "4 4"))
+	tk.MustQuery("select b, count(*) over (groups between current row and current row) from t").Check(testkit.Rows(
+		"1 6", "2 6", "2 6", "3 6", "1 6", "4 6"))
+	c.Assert(strings.Contains(fmt.Sprintf("%v", tk.MustQuery("explain format = 'brief' select sum(b) over (order by b groups between 1 preceding and 1 following) from t").Rows()), "groups between 1 preceding and 1 following"), IsTrue)
+	tk.MustGetErrCode("select sum(b) over (order by b groups between 1.5 preceding and current row) from t", mysql.ErrWindowFrameIllegal)
+	tk.MustGetErrCode("select sum(b) over (order by b groups interval 1 day preceding) from t", mysql.ErrWindowRowsIntervalUse)
+}
+
+func (s *testSuite7) TestWindowNullTreatment(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, v int)")
+	tk.MustExec("insert into t values (1, null), (2, 10), (3, null), (4, null), (5, 20), (6, null), (7, 30)")
+	tk.MustQuery("select id, lead(v) ignore nulls over w, lag(v) ignore nulls over w, lead(v, 2, -1) ignore nulls over w, lag(v, 0) ignore nulls over w from t window w as (order by id)").Check(testkit.Rows(
+		"1 10 <nil> 20 <nil>",
+		"2 20 <nil> 30 10",
+		"3 20 10 30 <nil>",
+		"4 20 10 30 <nil>",
+		"5 30 10 -1 20",
+		"6 30 20 -1 <nil>",
+		"7 <nil> 20 -1 30",
+	))
+	tk.MustQuery("select id, first_value(v) ignore nulls over (order by id), last_value(v) ignore nulls over (order by id rows between unbounded preceding and 1 following), " +
+		"last_value(v) respect nulls over (order by id rows between unbounded preceding and 1 following) from t").Check(testkit.Rows(
+		"1 <nil> 10 10",
+		"2 10 10 <nil>",
+		"3 10 10 <nil>",
+		"4 10 20 20",
+		"5 10 20 <nil>",
+		"6 10 30 30",
+		"7 10 30 30",
+	))
+	tk.MustQuery("select id, nth_value(v, 2) from last over (), nth_value(v, 2) from last ignore nulls over (), " +
+		"nth_value(v, 2) from first ignore nulls over (order by id rows between 1 preceding and 2 following), " +
+		"nth_value(v, 1) from last over (order by id rows between 1 preceding and 1 following) from t").Check(testkit.Rows(
+		"1 <nil> 20 <nil> 10",
+		"2 <nil> 20 <nil> <nil>",
+		"3 <nil> 20 20 <nil>",
+		"4 <nil> 20 <nil> 20",
+		"5 <nil> 20 30 <nil>",
+		"6 <nil> 20 30 30",
+		"7 <nil> 20 <nil> 30",
+	))
+	c.Assert(strings.Contains(fmt.Sprintf("%v", tk.MustQuery("explain format = 'brief' select nth_value(v, 2) from last ignore nulls over (order by id) from t").Rows()), "nth_value(test.t.v, 2) from last ignore nulls"), IsTrue)
+}
+
+func (s *testSuite7) TestWindowDistinctAndGroupConcat(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, v int, s varchar(10) collate utf8mb4_general_ci)")
+	tk.MustExec("insert into t values (1, 1, 'a'), (2, 2, 'A'), (3, 1, 'b'), (4, 3, null), (5, 2, 'B'), (6, 2, 'c')")
+	tk.MustQuery("select id, sum(distinct v) over w, avg(distinct v) over w, sum(distinct v) over (order by id) " +
+		"from t window w as (order by id rows between 1 preceding and 1 following)").Check(testkit.Rows(
+		"1 3 1.5000 1",
+		"2 3 1.5000 3",
+		"3 6 2.0000 3",
+		"4 6 2.0000 6",
+		"5 5 2.5000 6",
+		"6 2 2.0000 6",
+	))
+	tk.MustQuery("select id, avg(distinct v) over (), max(distinct v) over (order by id rows 1 preceding), sum(distinct v) over (partition by v) from t").Sort().Check(testkit.Rows(
+		"1 2.0000 1 1",
+		"2 2.0000 2 2",
+		"3 2.0000 2 1",
+		"4 2.0000 3 3",
+		"5 2.0000 3 2",
+		"6 2.0000 2 2",
+	))
+	tk.MustQuery("select id, group_concat(v) over (order by id rows between 1 preceding and current row), group_concat(distinct v separator ';') over (order by id), group_concat(s) over () from t").Check(testkit.Rows(
+		"1 1 1 a,A,b,B,c",
+		"2 1,2 1;2 a,A,b,B,c",
+		"3 2,1 1;2 a,A,b,B,c",
+		"4 1,3 1;2;3 a,A,b,B,c",
+		"5 3,2 1;2;3 a,A,b,B,c",
+		"6 2,2 1;2;3 a,A,b,B,c",
+	))
+	c.Assert(strings.Contains(fmt.Sprintf("%v", tk.MustQuery("explain format = 'brief' select sum(distinct v) over () from t").Rows()), "sum(distinct cast(test.t.v, decimal(32,0) BINARY))"), IsTrue)
+}
+
+func (s *testSerialSuite1) TestWindowInDisk(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+	})
+
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/executor/testWindowRowContainerSpill", "return(true)"), IsNil)
+	defer func() {
+		c.Assert(failpoint.Disable("github.com/pingcap/tidb/executor/testWindowRowContainerSpill"), IsNil)
+	}()
+
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_window_concurrency = 1")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	values := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i%2, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+
+	tk.MustExec("set @@tidb_mem_quota_query = 1")
+	tk.MustQuery("select * from (select a, b, row_number() over w, lead(b, 2) over w, rank() over w, " +
+		"sum(b) over (partition by a order by b rows between 1 preceding and 1 following) " +
+		"from t window w as (partition by a order by b)) tt where b < 6").Check(testkit.Rows(
+		"0 0 1 4 1 2",
+		"0 2 2 6 2 6",
+		"0 4 3 8 3 12",
+		"1 1 1 5 1 4",
+		"1 3 2 7 2 9",
+		"1 5 3 9 3 15",
+	))
+	tk.MustQuery("select * from (select a, b, count(*) over (partition by a), " +
+		"sum(b) over (partition by a order by b range between 10 preceding and current row) from t) tt where b > 95").Check(testkit.Rows(
+		"0 96 50 546",
+		"0 98 50 558",
+		"1 97 50 552",
+		"1 99 50 564",
+	))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.MemTracker.BytesConsumed(), Equals, int64(0))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.MemTracker.MaxConsumed(), Greater, int64(0))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.BytesConsumed(), Equals, int64(0))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Greater, int64(0))
+}
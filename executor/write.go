@@ -21,16 +21,19 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/memory"
 )
 
@@ -49,7 +52,7 @@ var (
 //     1. changed (bool) : does the update really change the row values. e.g. update set i = 1 where i = 1;
 //     2. err (error) : error in the update.
 func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, oldData, newData []types.Datum, modified []bool, t table.Table,
-	onDup bool, memTracker *memory.Tracker) (bool, error) {
+	constraints []*table.Constraint, fkChecker *foreignKeyChecker, rowPolicy *rowPolicyChecker, onDup bool, memTracker *memory.Tracker) (bool, error) {
 	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
 		span1 := span.Tracer().StartSpan("executor.updateRecord", opentracing.ChildOf(span.Context()))
 		defer span1.Finish()
@@ -170,7 +173,19 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 		}
 	}
 
-	// 5. If handle changed, remove the old then add the new record, otherwise update the record.
+	// 5. Check whether the new row satisfies the check constraints and the row-level security policies.
+	if err = table.CheckRowConstraints(sctx, constraints, newData); err != nil {
+		return false, err
+	}
+	if err = rowPolicy.checkRow(sctx, newData); err != nil {
+		return false, err
+	}
+	// The referenced rows of the modified foreign key columns should exist.
+	if err = fkChecker.checkParentsExist(ctx, sctx, newData, modified); err != nil {
+		return false, err
+	}
+
+	// 6. If handle changed, remove the old then add the new record, otherwise update the record.
 	if handleChanged {
 		// For `UPDATE IGNORE`/`INSERT IGNORE ON DUPLICATE KEY UPDATE`
 		// we use the staging buffer so that we don't need to precheck the existence of handle or unique keys by sending
@@ -210,6 +225,10 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 		}
 
 	}
+	// 7. Run the referential actions of the foreign keys referring to the updated row.
+	if err = fkChecker.onUpdate(ctx, sctx, oldData, newData, modified); err != nil {
+		return false, err
+	}
 	if onDup {
 		sc.AddAffectedRows(2)
 	} else {
@@ -239,6 +258,50 @@ func rebaseAutoRandomValue(sctx sessionctx.Context, t table.Table, newData *type
 	return t.Allocators(sctx).Get(autoid.AutoRandomType).Rebase(tableInfo.ID, recordID, true)
 }
 
+// rowPolicyChecker checks whether the rows written to a table satisfy the row-level security policies of it.
+type rowPolicyChecker struct {
+	tblName string
+	cond    expression.Expression
+}
+
+// buildRowPolicyChecker builds the checker of the row-level security policies applied to the table for the current user,
+// a written row must satisfy the WITH CHECK predicate of any policy. It returns nil if nothing needs to be checked.
+func buildRowPolicyChecker(sctx sessionctx.Context, tblInfo *model.TableInfo) (*rowPolicyChecker, error) {
+	pm := privilege.GetPrivilegeManager(sctx)
+	if pm == nil {
+		return nil, nil
+	}
+	policies := pm.RowPolicies(sctx.GetSessionVars().ActiveRoles, tblInfo)
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	conds := make([]expression.Expression, 0, len(policies))
+	for _, policy := range policies {
+		cond, err := expression.ParseSimpleExprWithTableInfo(sctx, policy.WriteCheck(), tblInfo)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		conds = append(conds, cond)
+	}
+	return &rowPolicyChecker{tblName: tblInfo.Name.O, cond: expression.ComposeDNFCondition(sctx, conds...)}, nil
+}
+
+// checkRow checks the row whose columns are in the order of the table's Cols(),
+// the row is rejected unless the predicate is evaluated to TRUE.
+func (c *rowPolicyChecker) checkRow(sctx sessionctx.Context, row []types.Datum) error {
+	if c == nil {
+		return nil
+	}
+	ok, _, err := expression.EvalBool(sctx, expression.CNFExprs{c.cond}, chunk.MutRowFromDatums(row).ToRow())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrRowPolicyViolated.GenWithStackByArgs(c.tblName)
+	}
+	return nil
+}
+
 // resetErrDataTooLong reset ErrDataTooLong error msg.
 // types.ErrDataTooLong is produced in types.ProduceStrWithSpecifiedTp, there is no column info in there,
 // so we reset the error msg here, and wrap old err with errors.Wrap.
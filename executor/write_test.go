@@ -19,11 +19,14 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/planner/core"
@@ -4040,6 +4043,173 @@ func (s *testSuite) TestIssue21232(c *C) {
 	tk.MustQuery("select * from t").Check(testkit.Rows("a", "b"))
 }
 
+func (s *testSuite4) TestWriteWithCheckConstraint(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int check (b > 0), c int, check (b < c))")
+
+	// insert
+	tk.MustExec("insert into t values (1, 1, 2), (2, null, 2)")
+	tk.MustGetErrCode("insert into t values (3, 0, 2)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("insert into t values (3, 2, 2)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("insert ignore into t values (3, 0, 2), (4, 1, 2)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 3819 Check constraint 't_chk_1' is violated."))
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1 2", "2 <nil> 2", "4 1 2"))
+
+	// insert on duplicate key update
+	tk.MustGetErrCode("insert into t values (1, 1, 2) on duplicate key update c = 1", errno.ErrCheckConstraintViolated)
+	tk.MustExec("insert ignore into t values (1, 1, 2) on duplicate key update c = 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 3819 Check constraint 't_chk_2' is violated."))
+	tk.MustExec("insert into t values (1, 1, 2) on duplicate key update c = 3")
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 1 3"))
+
+	// replace
+	tk.MustGetErrCode("replace into t values (1, 0, 3)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("replace into t values (1, 2, 3)")
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 2 3"))
+
+	// update
+	tk.MustGetErrCode("update t set b = 0 where a = 1", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("update t set c = b", errno.ErrCheckConstraintViolated)
+	tk.MustExec("update ignore t set c = 2")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 3819 Check constraint 't_chk_2' is violated."))
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 2 3", "2 <nil> 2", "4 1 2"))
+
+	// The row which isn't changed isn't checked.
+	tk.MustExec("alter table t alter check t_chk_1 not enforced")
+	tk.MustExec("update t set b = 0 where a = 4")
+	tk.MustGetErrCode("alter table t alter check t_chk_1 enforced", errno.ErrCheckConstraintViolated)
+	tk.MustExec("update t set c = 2 where a = 4")
+	tk.MustExec("update t set b = 1 where a = 4")
+	tk.MustExec("alter table t alter check t_chk_1 enforced")
+
+	// load data
+	tk.MustExec("delete from t")
+	tk.MustExec("load data local infile '/tmp/nonexistence.csv' into table t")
+	ctx := tk.Se.(sessionctx.Context)
+	ld, ok := ctx.Value(executor.LoadDataVarKey).(*executor.LoadDataInfo)
+	c.Assert(ok, IsTrue)
+	defer ctx.SetValue(executor.LoadDataVarKey, nil)
+	c.Assert(ld, NotNil)
+	tests := []testCase{
+		{nil, []byte("1\t1\t2\n2\t0\t2\n3\t3\t2\n"), []string{"1|1|2"}, nil, "Records: 3  Deleted: 0  Skipped: 2  Warnings: 2"},
+	}
+	checkCases(tests, ld, c, tk, ctx, "select * from t", "delete from t")
+}
+
+func (s *testSuite4) TestWriteWithForeignKey(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists child, parent")
+	tk.MustExec("create table parent(id int primary key, code int, unique key(code))")
+	tk.MustExec("create table child(id int primary key, pid int, pcode int, key(pid), " +
+		"constraint fk_1 foreign key (pid) references parent(id) on delete cascade on update cascade, " +
+		"constraint fk_2 foreign key (pcode) references parent(code) on delete set null on update restrict)")
+	tk.MustExec("insert into parent values (1, 10), (2, 20), (3, 30)")
+
+	// The foreign keys aren't enforced by default.
+	tk.MustExec("insert into child values (1, 4, 40)")
+	tk.MustExec("delete from child")
+	tk.MustExec("set @@foreign_key_checks = 1")
+
+	// insert and update on the child table
+	tk.MustExec("insert into child values (1, 1, 10), (2, 2, null), (3, null, 30)")
+	tk.MustGetErrCode("insert into child values (4, 4, null)", errno.ErrNoReferencedRow2)
+	err := tk.ExecToErr("insert into child values (4, 1, 40)")
+	c.Assert(err.Error(), Equals, "[executor:1452]Cannot add or update a child row: a foreign key constraint fails "+
+		"(`test`.`child`, CONSTRAINT `fk_2` FOREIGN KEY (`pcode`) REFERENCES `parent` (`code`))")
+	tk.MustExec("insert ignore into child values (4, 4, null), (5, 3, 30)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1452 Cannot add or update a child row: a foreign key constraint fails " +
+		"(`test`.`child`, CONSTRAINT `fk_1` FOREIGN KEY (`pid`) REFERENCES `parent` (`id`))"))
+	tk.MustGetErrCode("update child set pid = 4 where id = 1", errno.ErrNoReferencedRow2)
+	tk.MustGetErrCode("replace into child values (1, 4, 10)", errno.ErrNoReferencedRow2)
+	tk.MustExec("update child set pid = 3 where id = 1")
+	tk.MustQuery("select * from child order by id").Check(testkit.Rows("1 3 10", "2 2 <nil>", "3 <nil> 30", "5 3 30"))
+
+	// restrict
+	tk.MustGetErrCode("update parent set code = 31 where id = 3", errno.ErrRowIsReferenced2)
+	tk.MustExec("update parent set code = 21 where id = 2")
+
+	// cascade and set null
+	tk.MustExec("update parent set id = 4 where id = 3")
+	tk.MustQuery("select * from child order by id").Check(testkit.Rows("1 4 10", "2 2 <nil>", "3 <nil> 30", "5 4 30"))
+	tk.MustExec("delete from parent where id = 1")
+	tk.MustQuery("select * from child order by id").Check(testkit.Rows("1 4 <nil>", "2 2 <nil>", "3 <nil> 30", "5 4 30"))
+	tk.MustExec("delete from parent where id = 4")
+	tk.MustQuery("select * from child order by id").Check(testkit.Rows("2 2 <nil>", "3 <nil> <nil>"))
+	tk.MustQuery("select * from parent order by id").Check(testkit.Rows("2 21"))
+
+	// The referenced rows are locked in the pessimistic transactions.
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use test")
+	tk1.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("insert into parent values (5, 50)")
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("delete from parent where id = 5")
+	ch := make(chan struct{})
+	go func() {
+		tk1.MustExec("begin pessimistic")
+		err := tk1.ExecToErr("insert into child values (6, 5, null)")
+		c.Check(terror.ErrorEqual(err, executor.ErrNoReferencedRow2), IsTrue, Commentf("err %v", err))
+		tk1.MustExec("rollback")
+		close(ch)
+	}()
+	select {
+	case <-ch:
+		c.Fatal("the referenced row should be locked")
+	case <-time.After(100 * time.Millisecond):
+	}
+	tk.MustExec("commit")
+	<-ch
+
+	// The latest rows are read by the non-unique index in the pessimistic transactions.
+	tk.MustExec("drop table if exists c2, p2")
+	tk.MustExec("create table p2(id int primary key, code int, key(code))")
+	tk.MustExec("create table c2(id int primary key, pcode int, constraint fk_1 foreign key (pcode) references p2(code))")
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select * from p2").Check(testkit.Rows())
+	tk1.MustExec("insert into p2 values (1, 10)")
+	tk.MustExec("insert into c2 values (1, 10)")
+	tk.MustExec("commit")
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select * from c2").Check(testkit.Rows("1 10"))
+	tk1.MustExec("insert into c2 values (2, 10)")
+	tk.MustExec("delete from c2 where id = 1")
+	tk.MustGetErrCode("delete from p2 where id = 1", errno.ErrRowIsReferenced2)
+	tk.MustExec("rollback")
+
+	// An index is needed on the columns of the foreign key to enforce it.
+	tk.MustQuery("show create table c2").Check(testkit.Rows("c2 CREATE TABLE `c2` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `pcode` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  KEY `fk_1` (`pcode`),\n" +
+		"  CONSTRAINT `fk_1` FOREIGN KEY (`pcode`) REFERENCES `p2` (`code`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustExec("alter table c2 drop index fk_1")
+	err = tk.ExecToErr("delete from p2")
+	c.Assert(err.Error(), Equals, "[executor:1821]Failed to add the foreign key constaint. Missing index for constraint 'fk_1' in the foreign table 'c2'")
+	tk.MustExec("alter table p2 drop index code")
+	err = tk.ExecToErr("insert into c2 values (3, 10)")
+	c.Assert(err.Error(), Equals, "[executor:1822]Failed to add the foreign key constaint. Missing index for constraint 'fk_1' in the referenced table 'p2'")
+
+	// The depth of the cascading actions is limited.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, pid int, key(pid), foreign key (pid) references t(id) on delete cascade)")
+	tk.MustExec("insert into t values (0, null)")
+	for i := 1; i <= 16; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i, i-1))
+	}
+	tk.MustGetErrCode("delete from t where id = 0", errno.ErrForeignKeyCascadeDepthExceeded)
+	tk.MustExec("delete from t where id = 1")
+	tk.MustQuery("select * from t").Check(testkit.Rows("0 <nil>"))
+
+	tk.MustExec("set @@foreign_key_checks = 0")
+	tk.MustExec("delete from parent")
+	tk.MustQuery("select count(*) from child").Check(testkit.Rows("2"))
+}
+
 func testEqualDatumsAsBinary(c *C, a []interface{}, b []interface{}, same bool) {
 	sc := new(stmtctx.StatementContext)
 	re := new(executor.ReplaceExec)
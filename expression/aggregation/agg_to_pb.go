@@ -59,6 +59,8 @@ func AggFuncToPBExpr(sc *stmtctx.StatementContext, client kv.Client, aggFunc *Ag
 		tp = tipb.ExprType_Agg_BitAnd
 	case ast.AggFuncVarPop:
 		tp = tipb.ExprType_VarPop
+	case ast.AggFuncJsonArrayagg:
+		tp = tipb.ExprType_JsonArrayAgg
 	case ast.AggFuncJsonObjectAgg:
 		tp = tipb.ExprType_JsonObjectAgg
 	case ast.AggFuncStddevPop:
@@ -109,6 +111,8 @@ func PBExprToAggFuncDesc(ctx sessionctx.Context, aggFunc *tipb.Expr, fieldTps []
 		name = ast.AggFuncBitXor
 	case tipb.ExprType_Agg_BitAnd:
 		name = ast.AggFuncBitAnd
+	case tipb.ExprType_JsonArrayAgg:
+		name = ast.AggFuncJsonArrayagg
 	default:
 		return nil, errors.Errorf("unknown aggregation function type: %v", aggFunc.Tp)
 	}
@@ -14,6 +14,7 @@
 package aggregation
 
 import (
+	"bytes"
 	"strings"
 
 	"github.com/pingcap/parser/ast"
@@ -24,6 +25,12 @@ import (
 // WindowFuncDesc describes a window function signature, only used in planner.
 type WindowFuncDesc struct {
 	baseFuncDesc
+	// HasDistinct indicates whether the aggregate function is evaluated on the distinct values, e.g. `sum(distinct a) over w`.
+	HasDistinct bool
+	// IgnoreNull indicates whether the null values are skipped, e.g. `first_value(a) ignore nulls over w`.
+	IgnoreNull bool
+	// FromLast indicates whether the rows of the frame are counted from the last one, e.g. `nth_value(a, 2) from last over w`.
+	FromLast bool
 }
 
 // NewWindowFuncDesc creates a window function signature descriptor.
@@ -54,7 +61,30 @@ func NewWindowFuncDesc(ctx sessionctx.Context, name string, args []expression.Ex
 	if err != nil {
 		return nil, err
 	}
-	return &WindowFuncDesc{base}, nil
+	return &WindowFuncDesc{baseFuncDesc: base}, nil
+}
+
+// String implements the fmt.Stringer interface.
+func (w *WindowFuncDesc) String() string {
+	buffer := bytes.NewBufferString(w.Name)
+	buffer.WriteString("(")
+	if w.HasDistinct {
+		buffer.WriteString("distinct ")
+	}
+	for i, arg := range w.Args {
+		buffer.WriteString(arg.String())
+		if i+1 != len(w.Args) {
+			buffer.WriteString(", ")
+		}
+	}
+	buffer.WriteString(")")
+	if w.FromLast {
+		buffer.WriteString(" from last")
+	}
+	if w.IgnoreNull {
+		buffer.WriteString(" ignore nulls")
+	}
+	return buffer.String()
 }
 
 // noFrameWindowFuncs is the functions that operate on the entire partition,
@@ -582,6 +582,20 @@ type functionClass interface {
 	verifyArgsByCount(l int) error
 }
 
+// The names of the builtin functions which are not defined by the parser yet.
+const (
+	// JSONOverlaps is the name of JSON_OVERLAPS.
+	JSONOverlaps = "json_overlaps"
+	// RegexpLike is the name of REGEXP_LIKE.
+	RegexpLike = "regexp_like"
+	// RegexpInStr is the name of REGEXP_INSTR.
+	RegexpInStr = "regexp_instr"
+	// RegexpSubstr is the name of REGEXP_SUBSTR.
+	RegexpSubstr = "regexp_substr"
+	// RegexpReplace is the name of REGEXP_REPLACE.
+	RegexpReplace = "regexp_replace"
+)
+
 // funcs holds all registered builtin functions. When new function is added,
 // check expression/function_traits.go to see if it should be appended to
 // any set there.
@@ -793,8 +807,6 @@ var funcs = map[string]functionClass{
 	ast.UUIDShort:       &uuidShortFunctionClass{baseFunctionClass{ast.UUIDShort, 0, 0}},
 	ast.VitessHash:      &vitessHashFunctionClass{baseFunctionClass{ast.VitessHash, 1, 1}},
 
-	// get_lock() and release_lock() are parsed but do nothing.
-	// It is used for preventing error in Ruby's activerecord migrations.
 	ast.GetLock:     &lockFunctionClass{baseFunctionClass{ast.GetLock, 2, 2}},
 	ast.ReleaseLock: &releaseLockFunctionClass{baseFunctionClass{ast.ReleaseLock, 1, 1}},
 
@@ -828,6 +840,10 @@ var funcs = map[string]functionClass{
 	ast.IsFalsity:          &isTrueOrFalseFunctionClass{baseFunctionClass{ast.IsFalsity, 1, 1}, opcode.IsFalsity, false},
 	ast.Like:               &likeFunctionClass{baseFunctionClass{ast.Like, 3, 3}},
 	ast.Regexp:             &regexpFunctionClass{baseFunctionClass{ast.Regexp, 2, 2}},
+	RegexpLike:             &regexpLikeFunctionClass{baseFunctionClass{RegexpLike, 2, 3}},
+	RegexpInStr:            &regexpInStrFunctionClass{baseFunctionClass{RegexpInStr, 2, 6}},
+	RegexpSubstr:           &regexpSubstrFunctionClass{baseFunctionClass{RegexpSubstr, 2, 5}},
+	RegexpReplace:          &regexpReplaceFunctionClass{baseFunctionClass{RegexpReplace, 3, 6}},
 	ast.Case:               &caseWhenFunctionClass{baseFunctionClass{ast.Case, 1, -1}},
 	ast.RowFunc:            &rowFunctionClass{baseFunctionClass{ast.RowFunc, 2, -1}},
 	ast.SetVar:             &setVarFunctionClass{baseFunctionClass{ast.SetVar, 2, 2}},
@@ -879,6 +895,8 @@ var funcs = map[string]functionClass{
 	ast.JSONDepth:         &jsonDepthFunctionClass{baseFunctionClass{ast.JSONDepth, 1, 1}},
 	ast.JSONKeys:          &jsonKeysFunctionClass{baseFunctionClass{ast.JSONKeys, 1, 2}},
 	ast.JSONLength:        &jsonLengthFunctionClass{baseFunctionClass{ast.JSONLength, 1, 2}},
+	ast.JSONMemberOf:      &jsonMemberOfFunctionClass{baseFunctionClass{ast.JSONMemberOf, 2, 2}},
+	JSONOverlaps:          &jsonOverlapsFunctionClass{baseFunctionClass{JSONOverlaps, 2, 2}},
 
 	// TiDB internal function.
 	ast.TiDBDecodeKey: &tidbDecodeKeyFunctionClass{baseFunctionClass{ast.TiDBDecodeKey, 1, 1}},
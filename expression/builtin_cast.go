@@ -25,6 +25,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/ast"
@@ -47,6 +48,7 @@ var (
 	_ functionClass = &castAsTimeFunctionClass{}
 	_ functionClass = &castAsDurationFunctionClass{}
 	_ functionClass = &castAsJSONFunctionClass{}
+	_ functionClass = &castJSONAsArrayFunctionClass{}
 )
 
 var (
@@ -105,6 +107,7 @@ var (
 	_ builtinFunc = &builtinCastJSONAsTimeSig{}
 	_ builtinFunc = &builtinCastJSONAsDurationSig{}
 	_ builtinFunc = &builtinCastJSONAsJSONSig{}
+	_ builtinFunc = &builtinCastJSONAsArraySig{}
 )
 
 type castAsIntFunctionClass struct {
@@ -449,6 +452,25 @@ func (c *castAsJSONFunctionClass) getFunction(ctx sessionctx.Context, args []Exp
 	return sig, nil
 }
 
+type castJSONAsArrayFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castJSONAsArrayFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, types.ETJson)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp = c.tp
+	sig = &builtinCastJSONAsArraySig{bf}
+	return sig, nil
+}
+
 type builtinCastIntAsIntSig struct {
 	baseBuiltinCastFunc
 }
@@ -1609,6 +1631,91 @@ func (b *builtinCastJSONAsJSONSig) evalJSON(row chunk.Row) (res json.BinaryJSON,
 	return b.args[0].EvalJSON(b.ctx, row)
 }
 
+type builtinCastJSONAsArraySig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsArraySig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsArraySig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals `CAST(... AS ... ARRAY)`. A scalar is converted to an array with one element.
+func (b *builtinCastJSONAsArraySig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	elems := []json.BinaryJSON{val}
+	if val.TypeCode == json.TypeCodeArray {
+		elems = make([]json.BinaryJSON, 0, val.GetElemCount())
+		for i := 0; i < val.GetElemCount(); i++ {
+			elems = append(elems, val.ArrayGetElem(i))
+		}
+	}
+	arr := make([]interface{}, 0, len(elems))
+	for _, elem := range elems {
+		v, err := convertJSON2ArrayElem(elem, b.tp)
+		if err != nil {
+			return res, false, err
+		}
+		arr = append(arr, v)
+	}
+	return json.CreateBinary(arr), false, nil
+}
+
+// convertJSON2ArrayElem converts an element of the JSON array to the element type of the array type tp.
+func convertJSON2ArrayElem(elem json.BinaryJSON, tp *types.FieldType) (interface{}, error) {
+	elemTp := tp.ArrayElem
+	switch elemTp.EvalType() {
+	case types.ETInt:
+		unsigned := mysql.HasUnsignedFlag(elemTp.Flag)
+		switch elem.TypeCode {
+		case json.TypeCodeInt64:
+			v := elem.GetInt64()
+			if !unsigned {
+				return v, nil
+			}
+			if v < 0 {
+				return nil, ErrDataOutOfRangeFuncIndex.GenWithStackByArgs(tp.String())
+			}
+			return uint64(v), nil
+		case json.TypeCodeUint64:
+			v := elem.GetUint64()
+			if unsigned {
+				return v, nil
+			}
+			if v > math.MaxInt64 {
+				return nil, ErrDataOutOfRangeFuncIndex.GenWithStackByArgs(tp.String())
+			}
+			return int64(v), nil
+		}
+	case types.ETReal:
+		switch elem.TypeCode {
+		case json.TypeCodeInt64:
+			return float64(elem.GetInt64()), nil
+		case json.TypeCodeUint64:
+			return float64(elem.GetUint64()), nil
+		case json.TypeCodeFloat64:
+			return elem.GetFloat64(), nil
+		}
+	case types.ETString:
+		if elem.TypeCode == json.TypeCodeString {
+			v := string(elem.GetString())
+			length := utf8.RuneCountInString(v)
+			if types.IsBinaryStr(elemTp) {
+				length = len(v)
+			}
+			if elemTp.Flen != types.UnspecifiedLength && length > elemTp.Flen {
+				return nil, ErrFuncIndexDataIsTooLong.GenWithStackByArgs(tp.String())
+			}
+			return v, nil
+		}
+	}
+	return nil, ErrInvalidJSONForFuncIndex.GenWithStackByArgs(tp.String())
+}
+
 type builtinCastJSONAsIntSig struct {
 	baseBuiltinCastFunc
 }
@@ -1818,7 +1925,11 @@ func BuildCastFunction(ctx sessionctx.Context, expr Expression, tp *types.FieldT
 	case types.ETDuration:
 		fc = &castAsDurationFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
 	case types.ETJson:
-		fc = &castAsJSONFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+		if tp.ArrayElem != nil {
+			fc = &castJSONAsArrayFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+		} else {
+			fc = &castAsJSONFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+		}
 	case types.ETString:
 		fc = &castAsStringFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
 	}
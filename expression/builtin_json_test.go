@@ -443,6 +443,93 @@ func (s *testEvaluatorSuite) TestJSONContains(c *C) {
 	}
 }
 
+func (s *testEvaluatorSuite) TestJSONMemberOf(c *C) {
+	fc := funcs[ast.JSONMemberOf]
+	tbl := []struct {
+		input    []interface{}
+		expected interface{}
+		err      error
+	}{
+		{[]interface{}{`1`, nil}, nil, nil},
+		{[]interface{}{nil, `[1]`}, nil, nil},
+		{[]interface{}{1, `[1, 2]`}, 1, nil},
+		{[]interface{}{3, `[1, 2]`}, 0, nil},
+		{[]interface{}{1.0, `[1, 2]`}, 1, nil},
+		{[]interface{}{"a", `["a", "b"]`}, 1, nil},
+		{[]interface{}{"1", `[1, 2]`}, 0, nil},
+		// The string is not parsed as JSON.
+		{[]interface{}{"[1]", `[[1], 2]`}, 0, nil},
+		{[]interface{}{json.CreateBinary([]interface{}{int64(1)}), `[[1], 2]`}, 1, nil},
+		{[]interface{}{json.CreateBinary(int64(1)), `[[1], 2]`}, 0, nil},
+		// The nonarray is treated as an array wrapping it.
+		{[]interface{}{1, `1`}, 1, nil},
+		{[]interface{}{json.CreateBinary(map[string]interface{}{"a": int64(1)}), `{"a": 1}`}, 1, nil},
+		{[]interface{}{1, `a:1`}, nil, json.ErrInvalidJSONText},
+	}
+	for _, t := range tbl {
+		args := types.MakeDatums(t.input...)
+		f, err := fc.getFunction(s.ctx, s.datumsToConstants(args))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		if t.err == nil {
+			c.Assert(err, IsNil)
+			if t.expected == nil {
+				c.Assert(d.IsNull(), IsTrue)
+			} else {
+				c.Assert(d.GetInt64(), Equals, int64(t.expected.(int)), Commentf("%v", t.input))
+			}
+		} else {
+			c.Assert(t.err.(*terror.Error).Equal(err), IsTrue)
+		}
+	}
+	_, err := fc.getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(1, 1)))
+	c.Assert(json.ErrInvalidJSONData.Equal(err), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestJSONOverlaps(c *C) {
+	fc := funcs[JSONOverlaps]
+	tbl := []struct {
+		input    []interface{}
+		expected interface{}
+		err      error
+	}{
+		{[]interface{}{nil, `1`}, nil, nil},
+		{[]interface{}{`1`, nil}, nil, nil},
+		{[]interface{}{`[1, 2]`, `[2, 3]`}, 1, nil},
+		{[]interface{}{`[1, 2]`, `[3, 4]`}, 0, nil},
+		{[]interface{}{`[1, [2, 3]]`, `[[2, 3], 4]`}, 1, nil},
+		{[]interface{}{`[1, [2, 3]]`, `[2, 3]`}, 0, nil},
+		{[]interface{}{`[1, 2]`, `2`}, 1, nil},
+		{[]interface{}{`"a"`, `["a"]`}, 1, nil},
+		{[]interface{}{`{"a": 1, "b": 2}`, `{"b": 2}`}, 1, nil},
+		{[]interface{}{`{"a": 1, "b": 2}`, `{"a": 2}`}, 0, nil},
+		{[]interface{}{`{"a": 1}`, `[{"a": 1}]`}, 1, nil},
+		{[]interface{}{`1`, `1`}, 1, nil},
+		{[]interface{}{`[]`, `[]`}, 0, nil},
+		{[]interface{}{`[1]`, `a:1`}, nil, json.ErrInvalidJSONText},
+	}
+	for _, t := range tbl {
+		args := types.MakeDatums(t.input...)
+		f, err := fc.getFunction(s.ctx, s.datumsToConstants(args))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		if t.err == nil {
+			c.Assert(err, IsNil)
+			if t.expected == nil {
+				c.Assert(d.IsNull(), IsTrue)
+			} else {
+				c.Assert(d.GetInt64(), Equals, int64(t.expected.(int)), Commentf("%v", t.input))
+			}
+		} else {
+			c.Assert(t.err.(*terror.Error).Equal(err), IsTrue)
+		}
+	}
+	for _, args := range [][]interface{}{{1, `[1]`}, {`[1]`, 0.5}} {
+		_, err := fc.getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(args...)))
+		c.Assert(json.ErrInvalidJSONData.Equal(err), IsTrue)
+	}
+}
+
 func (s *testEvaluatorSuite) TestJSONContainsPath(c *C) {
 	fc := funcs[ast.JSONContainsPath]
 	jsonString := `{"a": 1, "b": 2, "c": {"d": 4}}`
@@ -1214,3 +1214,51 @@ func (b *builtinJSONSPrettySig) vecEvalString(input *chunk.Chunk, result *chunk.
 	}
 	return nil
 }
+
+func (b *builtinJSONMemberOfSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONMemberOfSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalJSONPredicate(b.ctx, b.args, b.bufAllocator, input, result, json.MemberOfBinary)
+}
+
+func (b *builtinJSONOverlapsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONOverlapsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalJSONPredicate(b.ctx, b.args, b.bufAllocator, input, result, json.OverlapsBinary)
+}
+
+// vecEvalJSONPredicate evaluates the predicate on the two JSON arguments.
+func vecEvalJSONPredicate(ctx sessionctx.Context, args []Expression, bufAllocator columnBufferAllocator, input *chunk.Chunk, result *chunk.Column, pred func(left, right json.BinaryJSON) bool) error {
+	nr := input.NumRows()
+	leftCol, err := bufAllocator.get(types.ETJson, nr)
+	if err != nil {
+		return err
+	}
+	defer bufAllocator.put(leftCol)
+	if err := args[0].VecEvalJSON(ctx, input, leftCol); err != nil {
+		return err
+	}
+	rightCol, err := bufAllocator.get(types.ETJson, nr)
+	if err != nil {
+		return err
+	}
+	defer bufAllocator.put(rightCol)
+	if err := args[1].VecEvalJSON(ctx, input, rightCol); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(nr, false)
+	result.MergeNulls(leftCol, rightCol)
+	i64s := result.Int64s()
+	for i := 0; i < nr; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = boolToInt64(pred(leftCol.GetJSON(i), rightCol.GetJSON(i)))
+	}
+	return nil
+}
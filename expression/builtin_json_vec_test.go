@@ -138,6 +138,14 @@ var vecBuiltinJSONCases = map[string][]vecExprBenchCase{
 	ast.JSONQuote: {
 		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
 	},
+	ast.JSONMemberOf: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETJson, types.ETJson}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETJson, types.ETJson}, geners: []dataGenerator{nil, &constJSONGener{"[1, \"a\", null, [2, 3], {\"b\": 4}]"}}},
+	},
+	JSONOverlaps: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETJson, types.ETJson}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETJson, types.ETJson}, geners: []dataGenerator{nil, &constJSONGener{"[1, \"a\", null, [2, 3], {\"b\": 4}]"}}},
+	},
 }
 
 func (s *testVectorizeSuite2) TestVectorizedBuiltinJSONFunc(c *C) {
@@ -20,6 +20,7 @@ import (
 	"net"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/pingcap/parser/mysql"
@@ -59,6 +60,9 @@ var (
 	_ builtinFunc = &builtinSleepSig{}
 	_ builtinFunc = &builtinLockSig{}
 	_ builtinFunc = &builtinReleaseLockSig{}
+	_ builtinFunc = &builtinIsFreeLockSig{}
+	_ builtinFunc = &builtinIsUsedLockSig{}
+	_ builtinFunc = &builtinReleaseAllLocksSig{}
 	_ builtinFunc = &builtinDecimalAnyValueSig{}
 	_ builtinFunc = &builtinDurationAnyValueSig{}
 	_ builtinFunc = &builtinIntAnyValueSig{}
@@ -171,9 +175,55 @@ func (b *builtinLockSig) Clone() builtinFunc {
 
 // evalInt evals a builtinLockSig.
 // See https://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_get-lock
-// The lock function will do nothing.
-// Warning: get_lock() function is parsed but ignored.
-func (b *builtinLockSig) evalInt(_ chunk.Row) (int64, bool, error) {
+func (b *builtinLockSig) evalInt(row chunk.Row) (int64, bool, error) {
+	lockName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if err != nil {
+		return 0, true, err
+	}
+	timeout, isTimeoutNull, err := b.args[1].EvalInt(b.ctx, row)
+	if err != nil {
+		return 0, true, err
+	}
+	return getAdvisoryLock(b.ctx, lockName, isNull, timeout, isTimeoutNull)
+}
+
+const (
+	// maxUserLockNameLen is the max length of the user-level lock name.
+	maxUserLockNameLen = 64
+	// maxUserLockWaitTimeout is the max value of innodb_lock_wait_timeout in seconds.
+	maxUserLockWaitTimeout = 1073741824
+)
+
+// checkUserLockName validates the user-level lock name and returns the normalized name.
+// The lock names are case-insensitive.
+func checkUserLockName(lockName string, isNull bool) (string, error) {
+	if isNull {
+		return "", ErrUserLockWrongName.GenWithStackByArgs("NULL")
+	}
+	if len(lockName) == 0 || utf8.RuneCountInString(lockName) > maxUserLockNameLen {
+		return "", ErrUserLockWrongName.GenWithStackByArgs(lockName)
+	}
+	return strings.ToLower(lockName), nil
+}
+
+func getAdvisoryLock(ctx sessionctx.Context, lockName string, isNull bool, timeout int64, isTimeoutNull bool) (int64, bool, error) {
+	lockName, err := checkUserLockName(lockName, isNull)
+	if err != nil {
+		return 0, true, err
+	}
+	if isTimeoutNull {
+		timeout = 0
+	}
+	if timeout > maxUserLockWaitTimeout {
+		timeout = maxUserLockWaitTimeout
+	}
+	acquired, err := ctx.GetAdvisoryLock(lockName, timeout)
+	if err != nil {
+		return 0, true, err
+	}
+	if !acquired {
+		return 0, false, nil
+	}
 	return 1, false, nil
 }
 
@@ -206,10 +256,36 @@ func (b *builtinReleaseLockSig) Clone() builtinFunc {
 
 // evalInt evals a builtinReleaseLockSig.
 // See https://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_release-lock
-// The release lock function will do nothing.
-// Warning: release_lock() function is parsed but ignored.
-func (b *builtinReleaseLockSig) evalInt(_ chunk.Row) (int64, bool, error) {
-	return 1, false, nil
+func (b *builtinReleaseLockSig) evalInt(row chunk.Row) (int64, bool, error) {
+	lockName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if err != nil {
+		return 0, true, err
+	}
+	return releaseAdvisoryLock(b.ctx, lockName, isNull)
+}
+
+// releaseAdvisoryLock returns 1 if the lock is released, 0 if the lock is hold by
+// another session, and NULL if the lock does not exist.
+func releaseAdvisoryLock(ctx sessionctx.Context, lockName string, isNull bool) (int64, bool, error) {
+	lockName, err := checkUserLockName(lockName, isNull)
+	if err != nil {
+		return 0, true, err
+	}
+	released, err := ctx.ReleaseAdvisoryLock(lockName)
+	if err != nil {
+		return 0, true, err
+	}
+	if released {
+		return 1, false, nil
+	}
+	_, used, err := ctx.IsUsedAdvisoryLock(lockName)
+	if err != nil {
+		return 0, true, err
+	}
+	if !used {
+		return 0, true, nil
+	}
+	return 0, false, nil
 }
 
 type anyValueFunctionClass struct {
@@ -633,7 +709,51 @@ type isFreeLockFunctionClass struct {
 }
 
 func (c *isFreeLockFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
-	return nil, errFunctionNotExists.GenWithStackByArgs("FUNCTION", "IS_FREE_LOCK")
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinIsFreeLockSig{bf}
+	bf.tp.Flen = 1
+	return sig, nil
+}
+
+type builtinIsFreeLockSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIsFreeLockSig) Clone() builtinFunc {
+	newSig := &builtinIsFreeLockSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinIsFreeLockSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_is-free-lock
+func (b *builtinIsFreeLockSig) evalInt(row chunk.Row) (int64, bool, error) {
+	lockName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if err != nil {
+		return 0, true, err
+	}
+	return isFreeAdvisoryLock(b.ctx, lockName, isNull)
+}
+
+func isFreeAdvisoryLock(ctx sessionctx.Context, lockName string, isNull bool) (int64, bool, error) {
+	lockName, err := checkUserLockName(lockName, isNull)
+	if err != nil {
+		return 0, true, err
+	}
+	_, used, err := ctx.IsUsedAdvisoryLock(lockName)
+	if err != nil {
+		return 0, true, err
+	}
+	if used {
+		return 0, false, nil
+	}
+	return 1, false, nil
 }
 
 type isIPv4FunctionClass struct {
@@ -845,7 +965,52 @@ type isUsedLockFunctionClass struct {
 }
 
 func (c *isUsedLockFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
-	return nil, errFunctionNotExists.GenWithStackByArgs("FUNCTION", "IS_USED_LOCK")
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinIsUsedLockSig{bf}
+	bf.tp.Flag |= mysql.UnsignedFlag
+	return sig, nil
+}
+
+type builtinIsUsedLockSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIsUsedLockSig) Clone() builtinFunc {
+	newSig := &builtinIsUsedLockSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinIsUsedLockSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_is-used-lock
+func (b *builtinIsUsedLockSig) evalInt(row chunk.Row) (int64, bool, error) {
+	lockName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if err != nil {
+		return 0, true, err
+	}
+	return isUsedAdvisoryLock(b.ctx, lockName, isNull)
+}
+
+// isUsedAdvisoryLock returns the connection ID of the lock holder, and NULL if the lock is free.
+func isUsedAdvisoryLock(ctx sessionctx.Context, lockName string, isNull bool) (int64, bool, error) {
+	lockName, err := checkUserLockName(lockName, isNull)
+	if err != nil {
+		return 0, true, err
+	}
+	connID, used, err := ctx.IsUsedAdvisoryLock(lockName)
+	if err != nil {
+		return 0, true, err
+	}
+	if !used {
+		return 0, true, nil
+	}
+	return int64(connID), false, nil
 }
 
 type masterPosWaitFunctionClass struct {
@@ -998,7 +1163,32 @@ type releaseAllLocksFunctionClass struct {
 }
 
 func (c *releaseAllLocksFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
-	return nil, errFunctionNotExists.GenWithStackByArgs("FUNCTION", "RELEASE_ALL_LOCKS")
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinReleaseAllLocksSig{bf}
+	bf.tp.Flag |= mysql.UnsignedFlag
+	return sig, nil
+}
+
+type builtinReleaseAllLocksSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinReleaseAllLocksSig) Clone() builtinFunc {
+	newSig := &builtinReleaseAllLocksSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinReleaseAllLocksSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_release-all-locks
+func (b *builtinReleaseAllLocksSig) evalInt(_ chunk.Row) (int64, bool, error) {
+	return int64(b.ctx.ReleaseAllAdvisoryLocks()), false, nil
 }
 
 type uuidFunctionClass struct {
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -201,15 +202,35 @@ func (b *builtinLockSig) vectorized() bool {
 	return true
 }
 
-// See https://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_get-lock
-// The lock function will do nothing.
-// Warning: get_lock() function is parsed but ignored.
 func (b *builtinLockSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
+	nameBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(nameBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, nameBuf); err != nil {
+		return err
+	}
+	timeoutBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(timeoutBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, timeoutBuf); err != nil {
+		return err
+	}
+
 	result.ResizeInt64(n, false)
 	i64s := result.Int64s()
-	for i := range i64s {
-		i64s[i] = 1
+	timeouts := timeoutBuf.Int64s()
+	for i := 0; i < n; i++ {
+		res, isNull, err := getAdvisoryLock(b.ctx, nameBuf.GetString(i), nameBuf.IsNull(i), timeouts[i], timeoutBuf.IsNull(i))
+		if err != nil {
+			return err
+		}
+		result.SetNull(i, isNull)
+		i64s[i] = res
 	}
 	return nil
 }
@@ -606,15 +627,62 @@ func (b *builtinReleaseLockSig) vectorized() bool {
 	return true
 }
 
-// See https://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_release-lock
-// The release lock function will do nothing.
-// Warning: release_lock() function is parsed but ignored.
 func (b *builtinReleaseLockSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalUserLockInt(b.baseBuiltinFunc, input, result, releaseAdvisoryLock)
+}
+
+func (b *builtinIsFreeLockSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinIsFreeLockSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalUserLockInt(b.baseBuiltinFunc, input, result, isFreeAdvisoryLock)
+}
+
+func (b *builtinIsUsedLockSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinIsUsedLockSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalUserLockInt(b.baseBuiltinFunc, input, result, isUsedAdvisoryLock)
+}
+
+// vecEvalUserLockInt evaluates the user-level lock functions whose only argument is the lock name.
+func vecEvalUserLockInt(b baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column,
+	eval func(ctx sessionctx.Context, lockName string, isNull bool) (int64, bool, error)) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		res, isNull, err := eval(b.ctx, buf.GetString(i), buf.IsNull(i))
+		if err != nil {
+			return err
+		}
+		result.SetNull(i, isNull)
+		i64s[i] = res
+	}
+	return nil
+}
+
+func (b *builtinReleaseAllLocksSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinReleaseAllLocksSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
 	result.ResizeInt64(n, false)
 	i64s := result.Int64s()
 	for i := range i64s {
-		i64s[i] = 1
+		i64s[i] = int64(b.ctx.ReleaseAllAdvisoryLocks())
 	}
 	return nil
 }
@@ -0,0 +1,547 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &regexpLikeFunctionClass{}
+	_ functionClass = &regexpInStrFunctionClass{}
+	_ functionClass = &regexpSubstrFunctionClass{}
+	_ functionClass = &regexpReplaceFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinRegexpLikeFuncSig{}
+	_ builtinFunc = &builtinRegexpInStrFuncSig{}
+	_ builtinFunc = &builtinRegexpSubstrFuncSig{}
+	_ builtinFunc = &builtinRegexpReplaceFuncSig{}
+)
+
+// regexpBaseFuncSig is the shared part of the REGEXP_XXX functions. The pattern is always the second
+// argument, the regexp is compiled only once if both of the pattern and the match type are constant.
+type regexpBaseFuncSig struct {
+	baseBuiltinFunc
+	funcName string
+	// matchTypeIdx is the index of the match_type argument, it may be out of the range of the arguments.
+	matchTypeIdx    int
+	memorized       bool
+	memorizedRegexp *regexp.Regexp
+	memorizedErr    error
+}
+
+func newRegexpBaseFuncSig(bf baseBuiltinFunc, funcName string, matchTypeIdx int) regexpBaseFuncSig {
+	return regexpBaseFuncSig{baseBuiltinFunc: bf, funcName: funcName, matchTypeIdx: matchTypeIdx}
+}
+
+func (re *regexpBaseFuncSig) clone(from *regexpBaseFuncSig) {
+	re.cloneFrom(&from.baseBuiltinFunc)
+	re.funcName = from.funcName
+	re.matchTypeIdx = from.matchTypeIdx
+	re.memorized = from.memorized
+	if from.memorizedRegexp != nil {
+		re.memorizedRegexp = from.memorizedRegexp.Copy()
+	}
+	re.memorizedErr = from.memorizedErr
+}
+
+// isBinaryCollation returns whether the positions are counted in bytes rather than characters.
+func (re *regexpBaseFuncSig) isBinaryCollation() bool {
+	return re.collation == charset.CollationBin
+}
+
+func (re *regexpBaseFuncSig) canMemorize() bool {
+	sc := re.ctx.GetSessionVars().StmtCtx
+	return re.args[1].ConstItem(sc) && (re.matchTypeIdx >= len(re.args) || re.args[re.matchTypeIdx].ConstItem(sc))
+}
+
+// buildRegexp compiles the pattern with the flags of the match type. The case sensitivity follows the
+// collation by default, and the latter one of the 'c' and 'i' flags wins.
+func (re *regexpBaseFuncSig) buildRegexp(pat, matchType string) (*regexp.Regexp, error) {
+	ci, multiLine, dotAll := collate.IsCICollation(re.collation), false, false
+	for _, flag := range matchType {
+		switch flag {
+		case 'c':
+			ci = false
+		case 'i':
+			ci = true
+		case 'm':
+			multiLine = true
+		case 'n':
+			dotAll = true
+		case 'u':
+			// Only '\n' is recognized as the line terminator by the regexp package.
+		default:
+			return nil, errIncorrectArgs.GenWithStackByArgs(re.funcName)
+		}
+	}
+	var flags strings.Builder
+	if ci {
+		flags.WriteByte('i')
+	}
+	if multiLine {
+		flags.WriteByte('m')
+	}
+	if dotAll {
+		flags.WriteByte('s')
+	}
+	if flags.Len() > 0 {
+		pat = "(?" + flags.String() + ")" + pat
+	}
+	compiled, err := regexp.Compile(pat)
+	if err != nil {
+		return nil, ErrRegexp.GenWithStackByArgs(err.Error())
+	}
+	return compiled, nil
+}
+
+// getRegexp returns the memorized regexp if there is one, otherwise it compiles the pattern.
+func (re *regexpBaseFuncSig) getRegexp(pat, matchType string) (*regexp.Regexp, error) {
+	if re.memorized {
+		return re.memorizedRegexp, re.memorizedErr
+	}
+	compiled, err := re.buildRegexp(pat, matchType)
+	if re.canMemorize() {
+		re.memorized, re.memorizedRegexp, re.memorizedErr = true, compiled, err
+	}
+	return compiled, err
+}
+
+// evalMatchType evaluates the optional match_type argument.
+func (re *regexpBaseFuncSig) evalMatchType(row chunk.Row) (string, bool, error) {
+	if re.matchTypeIdx >= len(re.args) {
+		return "", false, nil
+	}
+	return re.args[re.matchTypeIdx].EvalString(re.ctx, row)
+}
+
+// evalOptionalInt evaluates the optional integer argument at idx, the default value is returned if it's absent.
+func (re *regexpBaseFuncSig) evalOptionalInt(row chunk.Row, idx int, defaultVal int64) (int64, bool, error) {
+	if idx >= len(re.args) {
+		return defaultVal, false, nil
+	}
+	return re.args[idx].EvalInt(re.ctx, row)
+}
+
+// findMatches finds the first n matches of the regexp in expr starting from the 1-based position pos, n < 0
+// means all the matches. The byte offset of the position is returned as well, the indexes of the matches
+// are relative to it.
+func (re *regexpBaseFuncSig) findMatches(compiled *regexp.Regexp, expr string, pos int64, n int) ([][]int, int, error) {
+	offset, ok := re.positionToOffset(expr, pos)
+	if !ok {
+		return nil, 0, ErrRegexpIndexOutOfBounds.GenWithStackByArgs()
+	}
+	return compiled.FindAllStringSubmatchIndex(expr[offset:], n), offset, nil
+}
+
+// positionToOffset converts the 1-based position to the byte offset, the position could be right after
+// the end of expr.
+func (re *regexpBaseFuncSig) positionToOffset(expr string, pos int64) (int, bool) {
+	if pos < 1 {
+		return 0, false
+	}
+	if re.isBinaryCollation() {
+		if pos > int64(len(expr))+1 {
+			return 0, false
+		}
+		return int(pos - 1), true
+	}
+	offset := 0
+	for i := int64(1); i < pos; i++ {
+		if offset >= len(expr) {
+			return 0, false
+		}
+		_, size := utf8.DecodeRuneInString(expr[offset:])
+		offset += size
+	}
+	return offset, true
+}
+
+// offsetToPosition converts the byte offset to the 1-based position.
+func (re *regexpBaseFuncSig) offsetToPosition(expr string, offset int) int64 {
+	if re.isBinaryCollation() {
+		return int64(offset) + 1
+	}
+	return int64(utf8.RuneCountInString(expr[:offset])) + 1
+}
+
+type regexpLikeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpLikeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETString}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, argTps[:len(args)]...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 1
+	sig := &builtinRegexpLikeFuncSig{newRegexpBaseFuncSig(bf, c.funcName, 2)}
+	if sig.isBinaryCollation() {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpLikeSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpLikeUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpLikeFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func (b *builtinRegexpLikeFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpLikeFuncSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalInt evals `REGEXP_LIKE(expr, pat[, match_type])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-like
+func (b *builtinRegexpLikeFuncSig) evalInt(row chunk.Row) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	matchType, isNull, err := b.evalMatchType(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	compiled, err := b.getRegexp(pat, matchType)
+	if err != nil {
+		return 0, true, err
+	}
+	return boolToInt64(compiled.MatchString(expr)), false, nil
+}
+
+type regexpInStrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpInStrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETInt, types.ETString}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, argTps[:len(args)]...)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinRegexpInStrFuncSig{newRegexpBaseFuncSig(bf, c.funcName, 5)}
+	if sig.isBinaryCollation() {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpInStrSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpInStrUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpInStrFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func (b *builtinRegexpInStrFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpInStrFuncSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalInt evals `REGEXP_INSTR(expr, pat[, pos[, occurrence[, return_option[, match_type]]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-instr
+func (b *builtinRegexpInStrFuncSig) evalInt(row chunk.Row) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pos, isNull, err := b.evalOptionalInt(row, 2, 1)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	occurrence, isNull, err := b.evalOptionalInt(row, 3, 1)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	returnOption, isNull, err := b.evalOptionalInt(row, 4, 0)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	matchType, isNull, err := b.evalMatchType(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	res, err := b.instr(expr, pat, matchType, pos, occurrence, returnOption)
+	if err != nil {
+		return 0, true, err
+	}
+	return res, false, nil
+}
+
+// instr returns the position of the occurrence-th match, or the position after it if the return option is 1.
+func (b *builtinRegexpInStrFuncSig) instr(expr, pat, matchType string, pos, occurrence, returnOption int64) (int64, error) {
+	if returnOption != 0 && returnOption != 1 {
+		return 0, errIncorrectArgs.GenWithStackByArgs(b.funcName)
+	}
+	compiled, err := b.getRegexp(pat, matchType)
+	if err != nil {
+		return 0, err
+	}
+	if occurrence < 1 {
+		occurrence = 1
+	}
+	matches, offset, err := b.findMatches(compiled, expr, pos, int(occurrence))
+	if err != nil || int64(len(matches)) < occurrence {
+		return 0, err
+	}
+	return b.offsetToPosition(expr, offset+matches[occurrence-1][returnOption]), nil
+}
+
+type regexpSubstrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpSubstrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps[:len(args)]...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = args[0].GetType().Flen
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	sig := &builtinRegexpSubstrFuncSig{newRegexpBaseFuncSig(bf, c.funcName, 4)}
+	if sig.isBinaryCollation() {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSubstrSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSubstrUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpSubstrFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func (b *builtinRegexpSubstrFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpSubstrFuncSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalString evals `REGEXP_SUBSTR(expr, pat[, pos[, occurrence[, match_type]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-substr
+func (b *builtinRegexpSubstrFuncSig) evalString(row chunk.Row) (string, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.evalOptionalInt(row, 2, 1)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	occurrence, isNull, err := b.evalOptionalInt(row, 3, 1)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	matchType, isNull, err := b.evalMatchType(row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return b.substr(expr, pat, matchType, pos, occurrence)
+}
+
+// substr returns the occurrence-th match, NULL is returned if there is no such match.
+func (b *builtinRegexpSubstrFuncSig) substr(expr, pat, matchType string, pos, occurrence int64) (string, bool, error) {
+	compiled, err := b.getRegexp(pat, matchType)
+	if err != nil {
+		return "", true, err
+	}
+	if occurrence < 1 {
+		occurrence = 1
+	}
+	matches, offset, err := b.findMatches(compiled, expr, pos, int(occurrence))
+	if err != nil || int64(len(matches)) < occurrence {
+		return "", true, err
+	}
+	match := matches[occurrence-1]
+	return expr[offset+match[0] : offset+match[1]], false, nil
+}
+
+type regexpReplaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpReplaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps[:len(args)]...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = mysql.MaxBlobWidth
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	sig := &builtinRegexpReplaceFuncSig{newRegexpBaseFuncSig(bf, c.funcName, 5)}
+	if sig.isBinaryCollation() {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpReplaceSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpReplaceUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpReplaceFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func (b *builtinRegexpReplaceFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpReplaceFuncSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalString evals `REGEXP_REPLACE(expr, pat, repl[, pos[, occurrence[, match_type]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-replace
+func (b *builtinRegexpReplaceFuncSig) evalString(row chunk.Row) (string, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	repl, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pos, isNull, err := b.evalOptionalInt(row, 3, 1)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	occurrence, isNull, err := b.evalOptionalInt(row, 4, 0)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	matchType, isNull, err := b.evalMatchType(row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	res, err := b.replace(expr, pat, repl, matchType, pos, occurrence)
+	if err != nil {
+		return "", true, err
+	}
+	return res, false, nil
+}
+
+// replace replaces the occurrence-th match with repl, all the matches are replaced if occurrence is 0.
+func (b *builtinRegexpReplaceFuncSig) replace(expr, pat, repl, matchType string, pos, occurrence int64) (string, error) {
+	compiled, err := b.getRegexp(pat, matchType)
+	if err != nil {
+		return "", err
+	}
+	n := -1
+	if occurrence > 0 {
+		n = int(occurrence)
+	}
+	matches, offset, err := b.findMatches(compiled, expr, pos, n)
+	if err != nil {
+		return "", err
+	}
+	if occurrence > 0 {
+		if int64(len(matches)) < occurrence {
+			return expr, nil
+		}
+		matches = matches[occurrence-1:]
+	}
+	if len(matches) == 0 {
+		return expr, nil
+	}
+	src := expr[offset:]
+	res := make([]byte, 0, len(expr))
+	res = append(res, expr[:offset]...)
+	last := 0
+	for _, match := range matches {
+		res = append(res, src[last:match[0]]...)
+		if res, err = expandRegexpReplacement(res, repl, src, match); err != nil {
+			return "", err
+		}
+		last = match[1]
+	}
+	return string(append(res, src[last:]...)), nil
+}
+
+// expandRegexpReplacement appends the replacement of the match to dst. It follows the syntax of the ICU
+// library used by MySQL: `$n` refers to the n-th capturing group, the digits are consumed as long as the
+// group exists, and `\` escapes the next character.
+func expandRegexpReplacement(dst []byte, repl, src string, match []int) ([]byte, error) {
+	numGroups := len(match)/2 - 1
+	for i := 0; i < len(repl); i++ {
+		ch := repl[i]
+		if ch == '\\' && i+1 < len(repl) {
+			i++
+			dst = append(dst, repl[i])
+			continue
+		}
+		if ch != '$' || i+1 >= len(repl) || repl[i+1] < '0' || repl[i+1] > '9' {
+			dst = append(dst, ch)
+			continue
+		}
+		group := int(repl[i+1] - '0')
+		if group > numGroups {
+			return nil, ErrRegexpIndexOutOfBounds.GenWithStackByArgs()
+		}
+		i++
+		for i+1 < len(repl) && repl[i+1] >= '0' && repl[i+1] <= '9' {
+			next := group*10 + int(repl[i+1]-'0')
+			if next > numGroups {
+				break
+			}
+			group = next
+			i++
+		}
+		if match[2*group] >= 0 {
+			dst = append(dst, src[match[2*group]:match[2*group+1]]...)
+		}
+	}
+	return dst, nil
+}
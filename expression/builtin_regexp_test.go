@@ -0,0 +1,145 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+type regexpTestCase struct {
+	args   []interface{}
+	expect interface{}
+	err    *terror.Error
+}
+
+func (s *testEvaluatorSuite) checkRegexpFunc(c *C, funcName string, tests []regexpTestCase) {
+	for _, tt := range tests {
+		commentf := Commentf("%s%v", funcName, tt.args)
+		f, err := funcs[funcName].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(tt.args...)))
+		c.Assert(err, IsNil, commentf)
+		res, err := evalBuiltinFunc(f, chunk.Row{})
+		if tt.err != nil {
+			c.Assert(tt.err.Equal(err), IsTrue, Commentf("%s%v, err: %v", funcName, tt.args, err))
+			continue
+		}
+		c.Assert(err, IsNil, commentf)
+		c.Assert(res, testutil.DatumEquals, types.NewDatum(tt.expect), commentf)
+	}
+}
+
+func (s *testEvaluatorSuite) TestRegexpLike(c *C) {
+	s.checkRegexpFunc(c, RegexpLike, []regexpTestCase{
+		{[]interface{}{"abc", "b"}, 1, nil},
+		{[]interface{}{"abc", "^b"}, 0, nil},
+		{[]interface{}{"abc", "B"}, 0, nil},
+		{[]interface{}{"abc", "B", "i"}, 1, nil},
+		{[]interface{}{"abc", "B", "ic"}, 0, nil},
+		{[]interface{}{"abc", "B", "ci"}, 1, nil},
+		{[]interface{}{"a\nb", "^b$"}, 0, nil},
+		{[]interface{}{"a\nb", "^b$", "m"}, 1, nil},
+		{[]interface{}{"a\nb", "a.b"}, 0, nil},
+		{[]interface{}{"a\nb", "a.b", "n"}, 1, nil},
+		{[]interface{}{"a\nb", "a.b", "un"}, 1, nil},
+		{[]interface{}{nil, "a"}, nil, nil},
+		{[]interface{}{"a", nil}, nil, nil},
+		{[]interface{}{"a", "a", nil}, nil, nil},
+		{[]interface{}{"a", "(", ""}, nil, ErrRegexp},
+		{[]interface{}{"a", "a", "x"}, nil, errIncorrectArgs},
+	})
+}
+
+func (s *testEvaluatorSuite) TestRegexpInStr(c *C) {
+	s.checkRegexpFunc(c, RegexpInStr, []regexpTestCase{
+		{[]interface{}{"dog cat dog", "dog"}, 1, nil},
+		{[]interface{}{"dog cat dog", "dog", 2}, 9, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 2}, 9, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 3}, 0, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 0}, 1, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 2, 1}, 12, nil},
+		{[]interface{}{"dog cat dog", "DOG", 1, 1, 0, "i"}, 1, nil},
+		{[]interface{}{"dog cat dog", "bird"}, 0, nil},
+		{[]interface{}{"你好世界你好", "你好", 2}, 5, nil},
+		{[]interface{}{"你好世界你好", "你好", 1, 2, 1}, 7, nil},
+		{[]interface{}{[]byte("你好世界"), []byte("世"), 1}, 7, nil},
+		{[]interface{}{"abc", "c", 4}, 0, nil},
+		{[]interface{}{"abc", "c", 5}, nil, ErrRegexpIndexOutOfBounds},
+		{[]interface{}{"abc", "c", 0}, nil, ErrRegexpIndexOutOfBounds},
+		{[]interface{}{"abc", "c", 1, 1, 2}, nil, errIncorrectArgs},
+		{[]interface{}{"abc", "c", nil}, nil, nil},
+		{[]interface{}{"abc", "c", 1, 1, 0, nil}, nil, nil},
+	})
+}
+
+func (s *testEvaluatorSuite) TestRegexpSubstr(c *C) {
+	s.checkRegexpFunc(c, RegexpSubstr, []regexpTestCase{
+		{[]interface{}{"abc def ghi", "[a-z]+"}, "abc", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 3}, "ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 2, 2}, "def", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 4}, nil, nil},
+		{[]interface{}{"abc def ghi", "[A-Z]+", 1, 1, "i"}, "abc", nil},
+		{[]interface{}{"abc def ghi", "[A-Z]+"}, nil, nil},
+		{[]interface{}{"你好世界", ".", 3}, "世", nil},
+		{[]interface{}{"abc", "x", 5}, nil, ErrRegexpIndexOutOfBounds},
+		{[]interface{}{nil, "a"}, nil, nil},
+	})
+}
+
+func (s *testEvaluatorSuite) TestRegexpReplace(c *C) {
+	s.checkRegexpFunc(c, RegexpReplace, []regexpTestCase{
+		{[]interface{}{"a b c", "b", "X"}, "a X c", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X"}, "X X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 3}, "abc def X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 2}, "aX X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 5, 1}, "abc X ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 4}, "abc def ghi", nil},
+		{[]interface{}{"abc def ghi", "[A-Z]+", "X", 1, 0, "i"}, "X X X", nil},
+		{[]interface{}{"abc def", "([a-z])([a-z]+)", "$2$1"}, "bca efd", nil},
+		{[]interface{}{"abc def", "([a-z])([a-z]+)", `\$1[$10]`}, "$1[a0] $1[d0]", nil},
+		{[]interface{}{"abc", "b", "$"}, "a$c", nil},
+		{[]interface{}{"abc", "(b)", "$2"}, nil, ErrRegexpIndexOutOfBounds},
+		{[]interface{}{"你好世界", ".", "X", 3, 1}, "你好X界", nil},
+		{[]interface{}{"abc", "b", nil}, nil, nil},
+	})
+}
+
+func (s *testEvaluatorSerialSuites) TestRegexpCICollation(c *C) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+
+	expr := types.NewCollationStringDatum("ABC", "utf8mb4_general_ci", 0)
+	pat := types.NewCollationStringDatum("b", "utf8mb4_general_ci", 0)
+	for _, ca := range []struct {
+		matchType interface{}
+		expect    int64
+	}{
+		{nil, 1},
+		{"i", 1},
+		{"c", 0},
+	} {
+		args := []types.Datum{expr, pat}
+		if ca.matchType != nil {
+			args = append(args, types.NewDatum(ca.matchType))
+		}
+		f, err := funcs[RegexpLike].getFunction(s.ctx, s.datumsToConstants(args))
+		c.Assert(err, IsNil)
+		res, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(res, testutil.DatumEquals, types.NewDatum(ca.expect), Commentf("match type: %v", ca.matchType))
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// vecEvalArgs evaluates all the arguments into the buffers, which should be released by putArgBufs.
+func (re *regexpBaseFuncSig) vecEvalArgs(input *chunk.Chunk) ([]*chunk.Column, error) {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, 0, len(re.args))
+	for _, arg := range re.args {
+		evalTp := arg.GetType().EvalType()
+		buf, err := re.bufAllocator.get(evalTp, n)
+		if err != nil {
+			re.putArgBufs(bufs)
+			return nil, err
+		}
+		bufs = append(bufs, buf)
+		if evalTp == types.ETInt {
+			err = arg.VecEvalInt(re.ctx, input, buf)
+		} else {
+			err = arg.VecEvalString(re.ctx, input, buf)
+		}
+		if err != nil {
+			re.putArgBufs(bufs)
+			return nil, err
+		}
+	}
+	return bufs, nil
+}
+
+func (re *regexpBaseFuncSig) putArgBufs(bufs []*chunk.Column) {
+	for _, buf := range bufs {
+		re.bufAllocator.put(buf)
+	}
+}
+
+// matchTypeOf returns the match type of the i-th row, the match type is empty if it's absent.
+func (re *regexpBaseFuncSig) matchTypeOf(bufs []*chunk.Column, i int) string {
+	if re.matchTypeIdx >= len(bufs) {
+		return ""
+	}
+	return bufs[re.matchTypeIdx].GetString(i)
+}
+
+// optionalIntOf returns the value of the optional integer argument at idx of the i-th row.
+func optionalIntOf(bufs []*chunk.Column, idx, i int, defaultVal int64) int64 {
+	if idx >= len(bufs) {
+		return defaultVal
+	}
+	return bufs[idx].GetInt64(i)
+}
+
+func hasNullArg(bufs []*chunk.Column, i int) bool {
+	for _, buf := range bufs {
+		if buf.IsNull(i) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *builtinRegexpLikeFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpLikeFuncSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	bufs, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putArgBufs(bufs)
+
+	n := input.NumRows()
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufs...)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		compiled, err := b.getRegexp(bufs[1].GetString(i), b.matchTypeOf(bufs, i))
+		if err != nil {
+			return err
+		}
+		i64s[i] = boolToInt64(compiled.MatchString(bufs[0].GetString(i)))
+	}
+	return nil
+}
+
+func (b *builtinRegexpInStrFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpInStrFuncSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	bufs, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putArgBufs(bufs)
+
+	n := input.NumRows()
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufs...)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		pos := optionalIntOf(bufs, 2, i, 1)
+		occurrence := optionalIntOf(bufs, 3, i, 1)
+		returnOption := optionalIntOf(bufs, 4, i, 0)
+		i64s[i], err = b.instr(bufs[0].GetString(i), bufs[1].GetString(i), b.matchTypeOf(bufs, i), pos, occurrence, returnOption)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinRegexpSubstrFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpSubstrFuncSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	bufs, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putArgBufs(bufs)
+
+	n := input.NumRows()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if hasNullArg(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		pos := optionalIntOf(bufs, 2, i, 1)
+		occurrence := optionalIntOf(bufs, 3, i, 1)
+		res, isNull, err := b.substr(bufs[0].GetString(i), bufs[1].GetString(i), b.matchTypeOf(bufs, i), pos, occurrence)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinRegexpReplaceFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpReplaceFuncSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	bufs, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer b.putArgBufs(bufs)
+
+	n := input.NumRows()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if hasNullArg(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		pos := optionalIntOf(bufs, 3, i, 1)
+		occurrence := optionalIntOf(bufs, 4, i, 0)
+		res, err := b.replace(bufs[0].GetString(i), bufs[1].GetString(i), bufs[2].GetString(i), b.matchTypeOf(bufs, i), pos, occurrence)
+		if err != nil {
+			return err
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
@@ -109,3 +109,48 @@ func BenchmarkVectorizedBuiltinRegexpForConstants(b *testing.B) {
 		}
 	})
 }
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinRegexpFuncsForConstants(c *C) {
+	const batchSz = 1024
+	ft := eType2FieldType(types.ETString)
+	input := chunk.New([]*types.FieldType{ft}, batchSz, batchSz)
+	fillColumnWithGener(types.ETString, input, 0, newRandLenStrGener(10, 20))
+	args := []Expression{
+		&Column{Index: 0, RetType: ft},
+		DatumToConstant(types.NewStringDatum(`[A-Z]{2}\d`), mysql.TypeString, 0),
+		DatumToConstant(types.NewIntDatum(1), mysql.TypeLonglong, 0),
+		DatumToConstant(types.NewIntDatum(1), mysql.TypeLonglong, 0),
+		DatumToConstant(types.NewStringDatum("i"), mysql.TypeString, 0),
+	}
+	bf, err := funcs[RegexpSubstr].getFunction(mock.NewContext(), args)
+	c.Assert(err, IsNil)
+	sig := bf.(*builtinRegexpSubstrFuncSig)
+	c.Assert(sig.memorized, IsFalse)
+
+	output := chunk.NewColumn(ft, batchSz)
+	c.Assert(bf.vecEvalString(input, output), IsNil)
+	// The pattern is compiled only once since both of the pattern and the match type are constant.
+	c.Assert(sig.memorized, IsTrue)
+	c.Assert(sig.memorizedErr, IsNil)
+	c.Assert(sig.Clone().(*builtinRegexpSubstrFuncSig).memorized, IsTrue)
+
+	it := chunk.NewIterator4Chunk(input)
+	i := 0
+	for row := it.Begin(); row != it.End(); row = it.Next() {
+		val, isNull, err := bf.evalString(row)
+		c.Assert(err, IsNil)
+		c.Assert(isNull, Equals, output.IsNull(i))
+		if !isNull {
+			c.Assert(val, Equals, output.GetString(i))
+		}
+		i++
+	}
+
+	// The regexp isn't memorized if the match type is not constant.
+	args[4] = &Column{Index: 0, RetType: ft}
+	bf, err = funcs[RegexpSubstr].getFunction(mock.NewContext(), args)
+	c.Assert(err, IsNil)
+	_, _, err = bf.evalString(chunk.MutRowFromDatums([]types.Datum{types.NewStringDatum("i")}).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(bf.(*builtinRegexpSubstrFuncSig).memorized, IsFalse)
+}
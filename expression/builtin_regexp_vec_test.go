@@ -0,0 +1,77 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+var (
+	regexpExprGener      = newRandLenStrGener(10, 20)
+	regexpPatternGener   = newSelectStringGener([]string{"[a-f]+", "[0-9]", "A.", "^[a-z]", "([a-z])([0-9])", ""})
+	regexpMatchTypeGener = newSelectStringGener([]string{"", "i", "c", "m", "n", "ic"})
+	regexpPatternConst   = &Constant{Value: types.NewStringDatum("([a-z])([0-9])"), RetType: types.NewFieldType(mysql.TypeString)}
+	regexpMatchTypeConst = &Constant{Value: types.NewStringDatum("i"), RetType: types.NewFieldType(mysql.TypeString)}
+)
+
+var vecBuiltinRegexpCases = map[string][]vecExprBenchCase{
+	RegexpLike: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener, regexpMatchTypeGener}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners:    []dataGenerator{regexpExprGener},
+			constants: []*Constant{nil, regexpPatternConst, regexpMatchTypeConst}},
+	},
+	RegexpInStr: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener, newRangeInt64Gener(1, 10), newRangeInt64Gener(0, 3), newRangeInt64Gener(0, 2), regexpMatchTypeGener}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			geners:    []dataGenerator{regexpExprGener, nil, newRangeInt64Gener(1, 10)},
+			constants: []*Constant{nil, regexpPatternConst}},
+	},
+	RegexpSubstr: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener, newRangeInt64Gener(1, 10), newRangeInt64Gener(0, 3), regexpMatchTypeGener}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString},
+			geners:    []dataGenerator{regexpExprGener, nil, newRangeInt64Gener(1, 10), newRangeInt64Gener(0, 3)},
+			constants: []*Constant{nil, regexpPatternConst, nil, nil, regexpMatchTypeConst}},
+	},
+	RegexpReplace: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener, newSelectStringGener([]string{"", "x", "$0", "[$0]"})}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener, newSelectStringGener([]string{"", "x", `\$`}), newRangeInt64Gener(1, 10), newRangeInt64Gener(0, 3), regexpMatchTypeGener}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners:    []dataGenerator{regexpExprGener, nil, newSelectStringGener([]string{"$2$1", "<$1>"})},
+			constants: []*Constant{nil, regexpPatternConst}},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinRegexpFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinRegexpCases)
+}
+
+func BenchmarkVectorizedBuiltinRegexpFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinRegexpCases)
+}
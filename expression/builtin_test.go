@@ -15,6 +15,7 @@ package expression
 
 import (
 	"reflect"
+	"strings"
 	"sync"
 
 	. "github.com/pingcap/check"
@@ -22,6 +23,7 @@ import (
 	"github.com/pingcap/parser/charset"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -138,16 +140,54 @@ func (s *testEvaluatorSuite) TestLock(c *C) {
 	lock := funcs[ast.GetLock]
 	f, err := lock.getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(nil, 1)))
 	c.Assert(err, IsNil)
+	_, err = evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(terror.ErrorEqual(err, ErrUserLockWrongName), IsTrue, Commentf("err %v", err))
+
+	f, err = lock.getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(strings.Repeat("a", 65), 1)))
+	c.Assert(err, IsNil)
+	_, err = evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(terror.ErrorEqual(err, ErrUserLockWrongName), IsTrue, Commentf("err %v", err))
+
+	// The length of the lock name is counted in characters.
+	f, err = lock.getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(strings.Repeat("锁", 64), 1)))
+	c.Assert(err, IsNil)
 	v, err := evalBuiltinFunc(f, chunk.Row{})
 	c.Assert(err, IsNil)
 	c.Assert(v.GetInt64(), Equals, int64(1))
 
+	f, err = lock.getFunction(s.ctx, s.datumsToConstants(types.MakeDatums("lock", 1)))
+	c.Assert(err, IsNil)
+	v, err = evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(v.GetInt64(), Equals, int64(1))
+
 	releaseLock := funcs[ast.ReleaseLock]
-	f, err = releaseLock.getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(1)))
+	f, err = releaseLock.getFunction(s.ctx, s.datumsToConstants(types.MakeDatums("lock")))
 	c.Assert(err, IsNil)
 	v, err = evalBuiltinFunc(f, chunk.Row{})
 	c.Assert(err, IsNil)
 	c.Assert(v.GetInt64(), Equals, int64(1))
+
+	isFreeLock := funcs[ast.IsFreeLock]
+	f, err = isFreeLock.getFunction(s.ctx, s.datumsToConstants(types.MakeDatums("lock")))
+	c.Assert(err, IsNil)
+	v, err = evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(v.GetInt64(), Equals, int64(1))
+
+	isUsedLock := funcs[ast.IsUsedLock]
+	f, err = isUsedLock.getFunction(s.ctx, s.datumsToConstants(types.MakeDatums("lock")))
+	c.Assert(err, IsNil)
+	v, err = evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(v.IsNull(), IsTrue)
+
+	releaseAllLocks := funcs[ast.ReleaseAllLocks]
+	f, err = releaseAllLocks.getFunction(s.ctx, nil)
+	c.Assert(err, IsNil)
+	v, err = evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(v.GetInt64(), Equals, int64(0))
 }
 
 // newFunctionForTest creates a new ScalarFunction using funcName and arguments,
@@ -33,6 +33,12 @@ var (
 	ErrInvalidArgumentForLogarithm = dbterror.ClassExpression.NewStd(mysql.ErrInvalidArgumentForLogarithm)
 	ErrIncorrectType               = dbterror.ClassExpression.NewStd(mysql.ErrIncorrectType)
 	ErrInvalidTableSample          = dbterror.ClassExpression.NewStd(mysql.ErrInvalidTableSample)
+	ErrUserLockWrongName           = dbterror.ClassExpression.NewStd(mysql.ErrUserLockWrongName)
+	ErrUserLockLost                = dbterror.ClassExpression.NewStd(mysql.ErrUserLockLost)
+	ErrRegexpIndexOutOfBounds      = dbterror.ClassExpression.NewStd(mysql.ErrRegexpIndexOutOfBounds)
+	ErrInvalidJSONForFuncIndex     = dbterror.ClassExpression.NewStd(mysql.ErrInvalidJSONValueForFuncIndex)
+	ErrDataOutOfRangeFuncIndex     = dbterror.ClassExpression.NewStd(mysql.ErrJSONValueOutOfRangeForFuncIndex)
+	ErrFuncIndexDataIsTooLong      = dbterror.ClassExpression.NewStd(mysql.ErrFunctionalIndexDataIsTooLong)
 
 	// All the un-exported errors are defined here:
 	errFunctionNotExists             = dbterror.ClassExpression.NewStd(mysql.ErrSpDoesNotExist)
@@ -4133,6 +4133,52 @@ func (s *testIntegrationSuite) TestAggregationBuiltinJSONObjectAgg(c *C) {
 	result.Check(testkit.Rows(`{"1": null}`))
 }
 
+func (s *testIntegrationSuite) TestAggregationBuiltinJSONArrayagg(c *C) {
+	defer s.cleanEnv(c)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+
+	tk.MustExec("drop table if exists t;")
+	tk.MustExec(`CREATE TABLE t (
+		a int(11),
+		b varchar(100),
+		c decimal(3,2),
+		d json,
+		e date,
+		f time,
+		g datetime DEFAULT '2012-01-01',
+		h enum('x', 'y'));`)
+
+	tk.MustQuery("select json_arrayagg(a) from t").Check(testkit.Rows("<nil>"))
+	tk.MustExec(`insert into t values(1, 'ab', 5.5, '{"id": 1}', '2020-01-10', '11:12:13', '2020-01-11', 'x');`)
+	tk.MustExec(`insert into t values(1, 'cd', null, '[1, 2]', '2020-01-11', '11:12:14', '2020-01-12', 'y');`)
+	tk.MustExec(`insert into t values(2, null, 1.25, null, null, null, null, null);`)
+
+	result := tk.MustQuery("select a, json_arrayagg(b) from t group by a order by a;")
+	result.Check(testkit.Rows(`1 ["ab", "cd"]`, `2 [null]`))
+	result = tk.MustQuery("select json_arrayagg(c) from (select * from t order by a, b) t1 group by a order by a;")
+	result.Check(testkit.Rows(`[5.5, null]`, `[1.25]`))
+	result = tk.MustQuery("select json_arrayagg(d) from (select * from t order by a, b) t1 where a = 1;")
+	result.Check(testkit.Rows(`[{"id": 1}, [1, 2]]`))
+	result = tk.MustQuery("select json_arrayagg(e), json_arrayagg(f), json_arrayagg(g), json_arrayagg(h) from (select * from t order by a, b) t1 where a = 1;")
+	result.Check(testkit.Rows(`["2020-01-10", "2020-01-11"] ["11:12:13", "11:12:14"] ["2020-01-11 00:00:00", "2020-01-12 00:00:00"] ["x", "y"]`))
+	result = tk.MustQuery("select json_arrayagg(a) from t where a > 10;")
+	result.Check(testkit.Rows("<nil>"))
+	result = tk.MustQuery("select /*+ stream_agg() */ a, json_arrayagg(b) from t group by a order by a;")
+	result.Check(testkit.Rows(`1 ["ab", "cd"]`, `2 [null]`))
+	tk.MustExec("set @@tidb_hashagg_partial_concurrency = 4, @@tidb_hashagg_final_concurrency = 4, @@tidb_init_chunk_size = 1, @@tidb_max_chunk_size = 32")
+	result = tk.MustQuery("select /*+ hash_agg() */ json_length(json_arrayagg(b)) from t group by a order by a;")
+	result.Check(testkit.Rows("2", "1"))
+
+	// json_arrayagg is also a window function.
+	result = tk.MustQuery("select a, b, json_arrayagg(b) over (partition by a order by b) from t order by a, b;")
+	result.Check(testkit.Rows(`1 ab ["ab"]`, `1 cd ["ab", "cd"]`, `2 <nil> [null]`))
+	result = tk.MustQuery("select b, json_arrayagg(b) over (order by b rows between 1 preceding and current row) from t where b is not null order by b;")
+	result.Check(testkit.Rows(`ab ["ab"]`, `cd ["ab", "cd"]`))
+	result = tk.MustQuery("select a, json_arrayagg(a) over (order by a rows between current row and 1 following) from t order by a;")
+	result.Check(testkit.Rows(`1 [1, 1]`, `1 [1, 2]`, `2 [2]`))
+}
+
 func (s *testIntegrationSuite2) TestOtherBuiltin(c *C) {
 	defer s.cleanEnv(c)
 	tk := testkit.NewTestKit(c, s.store)
@@ -4630,6 +4676,26 @@ func (s *testIntegrationSuite) TestFuncJSON(c *C) {
 	tk.MustQuery("select json_array(a+b) = json_array(c) from tx1").Check(testkit.Rows("0"))
 }
 
+func (s *testIntegrationSuite) TestFuncJSONMemberOfAndOverlaps(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	defer s.cleanEnv(c)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int, tags json, v varchar(10))")
+	tk.MustExec(`insert into t values (1, '["red", "blue"]', 'red'), (2, '["green", 3]', '3'), (3, '{"a": "red"}', 'red'), (4, null, null)`)
+
+	tk.MustQuery(`select id from t where 'red' member of (tags)`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select id, v member of (tags), 3 member of (tags) from t`).Check(testkit.Rows("1 1 0", "2 0 1", "3 0 0", "4 <nil> <nil>"))
+	tk.MustQuery(`select id from t where json_overlaps(tags, '["blue", 3]')`).Check(testkit.Rows("1", "2"))
+	tk.MustQuery(`select id from t where json_overlaps(tags, '{"a": "red", "b": 1}')`).Check(testkit.Rows("3"))
+	tk.MustQuery(`select json_overlaps('[1, 2]', '[2, 3]'), json_overlaps('[1, 2]', '[3]'), json_overlaps('1', '[1]'), json_overlaps(null, '1')`).Check(testkit.Rows("1 0 1 <nil>"))
+	tk.MustQuery(`select cast('[1]' as json) member of ('[[1], 2]'), '[1]' member of ('[[1], 2]')`).Check(testkit.Rows("1 0"))
+
+	tk.MustGetErrCode(`select json_overlaps(1, '[1]')`, mysql.ErrInvalidJSONData)
+	tk.MustGetErrCode(`select 1 member of (1)`, mysql.ErrInvalidJSONData)
+	tk.MustGetErrCode(`select json_memberof(1)`, mysql.ErrWrongParamcountToNativeFct)
+}
+
 func (s *testIntegrationSuite) TestColumnInfoModified(c *C) {
 	testKit := testkit.NewTestKit(c, s.store)
 	defer s.cleanEnv(c)
@@ -5097,7 +5163,10 @@ func (s *testIntegrationSuite) TestForeignKeyVar(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 
 	tk.MustExec("SET FOREIGN_KEY_CHECKS=1")
-	tk.MustQuery("SHOW WARNINGS").Check(testkit.Rows("Warning 8047 variable 'foreign_key_checks' does not yet support value: 1"))
+	tk.MustQuery("SHOW WARNINGS").Check(testkit.Rows())
+	tk.MustQuery("SELECT @@foreign_key_checks").Check(testkit.Rows("1"))
+	tk.MustExec("SET FOREIGN_KEY_CHECKS=0")
+	tk.MustQuery("SELECT @@foreign_key_checks").Check(testkit.Rows("0"))
 }
 
 func (s *testIntegrationSuite) TestUserVarMockWindFunc(c *C) {
@@ -6191,6 +6260,44 @@ func (s *testIntegrationSerialSuite) TestCacheRegexpr(c *C) {
 	tk.MustQuery("execute stmt1 using @a").Check(testkit.Rows("C1"))
 	tk.MustExec("set @a='^R.*'")
 	tk.MustQuery("execute stmt1 using @a").Check(testkit.Rows("R1"))
+
+	tk.MustExec("prepare stmt2 from 'select a, regexp_substr(a, ?), regexp_replace(a, ?, ?, 1, 0, ?) from t1 where regexp_like(a, ?)'")
+	tk.MustExec("set @p='[0-9]', @m='c', @r='x'")
+	tk.MustQuery("execute stmt2 using @p, @p, @r, @m, @a").Check(testkit.Rows("R1 1 Rx"))
+	tk.MustExec("set @p='[a-z]', @m='i', @a='^C'")
+	tk.MustQuery("execute stmt2 using @p, @p, @r, @m, @a").Check(testkit.Rows("C1 <nil> x1"))
+}
+
+func (s *testIntegrationSerialSuite) TestRegexpFunctions(c *C) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int, a varchar(20) collate utf8mb4_bin, b varchar(20) collate utf8mb4_general_ci, c varbinary(20))")
+	tk.MustExec("insert into t values (1, 'Abc aBC', 'Abc aBC', 'Abc aBC'), (2, '中文abc文', '中文ABC文', '中文abc文'), (3, null, null, null)")
+
+	tk.MustQuery("select id, regexp_like(a, 'abc'), regexp_like(b, 'abc'), regexp_like(a, 'abc', 'i'), regexp_like(b, 'abc', 'c') from t").Check(
+		testkit.Rows("1 0 1 1 0", "2 1 1 1 0", "3 <nil> <nil> <nil> <nil>"))
+	tk.MustQuery("select id, regexp_instr(a, '文'), regexp_instr(a, '文', 1, 2), regexp_instr(c, '文', 1, 2), regexp_instr(b, 'b', 1, 2, 1) from t").Check(
+		testkit.Rows("1 0 0 0 7", "2 2 6 10 0", "3 <nil> <nil> <nil> <nil>"))
+	tk.MustQuery("select id, regexp_substr(b, '[a-z]+', 1, 2), regexp_substr(a, '.', 2), regexp_substr(a, '[A-Z]+') from t").Check(
+		testkit.Rows("1 aBC b A", "2 <nil> 文 <nil>", "3 <nil> <nil> <nil>"))
+	tk.MustQuery("select id, regexp_replace(a, '[a-z]', '*'), regexp_replace(b, 'b', '-', 3), regexp_replace(a, '([a-z])([a-z])', '$2$1', 1, 1) from t").Check(
+		testkit.Rows("1 A** *BC Abc a-C Acb aBC", "2 中文***文 中文A-C文 中文bac文", "3 <nil> <nil> <nil>"))
+	tk.MustQuery("select id from t where regexp_like(b, '^abc')").Check(testkit.Rows("1"))
+	tk.MustQuery("select regexp_replace('a.b.c', '[.]', '', 1, 0, 'c'), regexp_instr('aXa', 'x', 1, 1, 0, 'ci'), regexp_substr(null, 'a')").Check(
+		testkit.Rows("abc 2 <nil>"))
+
+	err := tk.QueryToErr("select regexp_like('a', '(')")
+	c.Assert(expression.ErrRegexp.Equal(err), IsTrue, Commentf("err: %v", err))
+	err = tk.QueryToErr("select regexp_like('a', 'a', 'z')")
+	c.Assert(err.Error(), Equals, "[expression:1210]Incorrect arguments to regexp_like")
+	err = tk.QueryToErr("select regexp_instr('a', 'a', 1, 1, 2)")
+	c.Assert(err.Error(), Equals, "[expression:1210]Incorrect arguments to regexp_instr")
+	err = tk.QueryToErr("select regexp_instr('a', 'a', 3)")
+	c.Assert(err.Error(), Equals, "[expression:3686]Index out of bounds in regular expression search.")
+	tk.MustGetErrCode("select regexp_replace('a', 'a')", mysql.ErrWrongParamcountToNativeFct)
 }
 
 func (s *testIntegrationSerialSuite) TestCacheRefineArgs(c *C) {
@@ -0,0 +1,197 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/table"
+)
+
+// LocalTemporaryTables contains the local temporary tables created by a session.
+// They are only visible to the session, and disappear when the session is closed.
+type LocalTemporaryTables struct {
+	schemaMap map[string]*schemaTables
+	idx2table map[int64]table.Table
+}
+
+// NewLocalTemporaryTables creates a new, empty LocalTemporaryTables.
+func NewLocalTemporaryTables() *LocalTemporaryTables {
+	return &LocalTemporaryTables{
+		schemaMap: make(map[string]*schemaTables),
+		idx2table: make(map[int64]table.Table),
+	}
+}
+
+// TableByName gets a local temporary table by name.
+func (is *LocalTemporaryTables) TableByName(schema, tblName model.CIStr) (table.Table, bool) {
+	if tbNames, ok := is.schemaMap[schema.L]; ok {
+		if t, ok := tbNames.tables[tblName.L]; ok {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// TableExists checks if a local temporary table with the name exists.
+func (is *LocalTemporaryTables) TableExists(schema, tblName model.CIStr) bool {
+	_, ok := is.TableByName(schema, tblName)
+	return ok
+}
+
+// TableByID gets a local temporary table by ID.
+func (is *LocalTemporaryTables) TableByID(id int64) (table.Table, bool) {
+	tbl, ok := is.idx2table[id]
+	return tbl, ok
+}
+
+// SchemaByTable gets the schema of a local temporary table.
+func (is *LocalTemporaryTables) SchemaByTable(tableInfo *model.TableInfo) (*model.DBInfo, bool) {
+	if tableInfo == nil {
+		return nil, false
+	}
+	for _, tbNames := range is.schemaMap {
+		if t, ok := tbNames.tables[tableInfo.Name.L]; ok && t.Meta().ID == tableInfo.ID {
+			return tbNames.dbInfo, true
+		}
+	}
+	return nil, false
+}
+
+// AddTable adds a local temporary table to the schema.
+func (is *LocalTemporaryTables) AddTable(schema *model.DBInfo, tbl table.Table) error {
+	tbNames, ok := is.schemaMap[schema.Name.L]
+	if !ok {
+		tbNames = &schemaTables{
+			dbInfo: schema,
+			tables: make(map[string]table.Table),
+		}
+		is.schemaMap[schema.Name.L] = tbNames
+	}
+	tblMeta := tbl.Meta()
+	if _, ok := tbNames.tables[tblMeta.Name.L]; ok {
+		return ErrTableExists.GenWithStackByArgs(ast.Ident{Schema: schema.Name, Name: tblMeta.Name})
+	}
+	tbNames.tables[tblMeta.Name.L] = tbl
+	is.idx2table[tblMeta.ID] = tbl
+	return nil
+}
+
+// ReplaceTable replaces a local temporary table with a new table object of the same name.
+func (is *LocalTemporaryTables) ReplaceTable(schema model.CIStr, tbl table.Table) {
+	tblMeta := tbl.Meta()
+	old, ok := is.TableByName(schema, tblMeta.Name)
+	if !ok {
+		return
+	}
+	delete(is.idx2table, old.Meta().ID)
+	is.schemaMap[schema.L].tables[tblMeta.Name.L] = tbl
+	is.idx2table[tblMeta.ID] = tbl
+}
+
+// RemoveTable removes a local temporary table from the schema.
+func (is *LocalTemporaryTables) RemoveTable(schema, tblName model.CIStr) (table.Table, bool) {
+	tbNames, ok := is.schemaMap[schema.L]
+	if !ok {
+		return nil, false
+	}
+	tbl, ok := tbNames.tables[tblName.L]
+	if !ok {
+		return nil, false
+	}
+	delete(tbNames.tables, tblName.L)
+	if len(tbNames.tables) == 0 {
+		delete(is.schemaMap, schema.L)
+	}
+	delete(is.idx2table, tbl.Meta().ID)
+	return tbl, true
+}
+
+// TableIDs returns the IDs of all the local temporary tables.
+func (is *LocalTemporaryTables) TableIDs() []int64 {
+	ids := make([]int64, 0, len(is.idx2table))
+	for id := range is.idx2table {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Count returns the number of local temporary tables.
+func (is *LocalTemporaryTables) Count() int {
+	return len(is.idx2table)
+}
+
+// TemporaryTableAttachedInfoSchema is an InfoSchema with the local temporary tables of a session attached.
+// The local temporary tables take precedence over the normal tables with the same name.
+type TemporaryTableAttachedInfoSchema struct {
+	InfoSchema
+	LocalTemporaryTables *LocalTemporaryTables
+}
+
+// TableByName implements InfoSchema.TableByName.
+func (ts *TemporaryTableAttachedInfoSchema) TableByName(schema, tblName model.CIStr) (table.Table, error) {
+	if tbl, ok := ts.LocalTemporaryTables.TableByName(schema, tblName); ok {
+		return tbl, nil
+	}
+	return ts.InfoSchema.TableByName(schema, tblName)
+}
+
+// TableExists implements InfoSchema.TableExists.
+func (ts *TemporaryTableAttachedInfoSchema) TableExists(schema, tblName model.CIStr) bool {
+	return ts.LocalTemporaryTables.TableExists(schema, tblName) || ts.InfoSchema.TableExists(schema, tblName)
+}
+
+// TableByID implements InfoSchema.TableByID.
+func (ts *TemporaryTableAttachedInfoSchema) TableByID(id int64) (table.Table, bool) {
+	if tbl, ok := ts.LocalTemporaryTables.TableByID(id); ok {
+		return tbl, true
+	}
+	return ts.InfoSchema.TableByID(id)
+}
+
+// SchemaByTable implements InfoSchema.SchemaByTable.
+func (ts *TemporaryTableAttachedInfoSchema) SchemaByTable(tableInfo *model.TableInfo) (*model.DBInfo, bool) {
+	if db, ok := ts.LocalTemporaryTables.SchemaByTable(tableInfo); ok {
+		return db, true
+	}
+	return ts.InfoSchema.SchemaByTable(tableInfo)
+}
+
+// TableIsView implements InfoSchema.TableIsView.
+func (ts *TemporaryTableAttachedInfoSchema) TableIsView(schema, tblName model.CIStr) bool {
+	if ts.LocalTemporaryTables.TableExists(schema, tblName) {
+		return false
+	}
+	return ts.InfoSchema.TableIsView(schema, tblName)
+}
+
+// TableIsSequence implements InfoSchema.TableIsSequence.
+func (ts *TemporaryTableAttachedInfoSchema) TableIsSequence(schema, tblName model.CIStr) bool {
+	if ts.LocalTemporaryTables.TableExists(schema, tblName) {
+		return false
+	}
+	return ts.InfoSchema.TableIsSequence(schema, tblName)
+}
+
+// AttachLocalTemporaryTables attaches the local temporary tables to the InfoSchema.
+// The InfoSchema is returned as is if there is no local temporary table.
+func AttachLocalTemporaryTables(is InfoSchema, localTempTables *LocalTemporaryTables) InfoSchema {
+	if localTempTables == nil || localTempTables.Count() == 0 {
+		return is
+	}
+	if attached, ok := is.(*TemporaryTableAttachedInfoSchema); ok {
+		is = attached.InfoSchema
+	}
+	return &TemporaryTableAttachedInfoSchema{InfoSchema: is, LocalTemporaryTables: localTempTables}
+}
@@ -61,6 +61,8 @@ const (
 	MatchStoreLabels
 	// ResourceGroupTag indicates the resource group of the kv request.
 	ResourceGroupTag
+	// IgnoreMaxTxnTTL indicates whether the pessimistic locks are kept alive beyond max-txn-ttl.
+	IgnoreMaxTxnTTL
 )
 
 // ReplicaReadType is the type of replica to read data from
@@ -638,6 +638,10 @@ func (e *Execute) buildRangeForIndexScan(sctx sessionctx.Context, is *PhysicalIn
 	if len(is.IdxCols) == 0 {
 		return ranger.FullRange(), nil
 	}
+	// The ranges of the multi-valued index are only built from the immutable constants, see buildMVIndexPath.
+	if is.Index.MVIndex {
+		return is.Ranges, nil
+	}
 	res, err := ranger.DetachCondAndBuildRangeForIndex(sctx, is.AccessCondition, is.IdxCols, is.IdxColLens)
 	if err != nil {
 		return nil, err
@@ -897,11 +901,12 @@ type LoadStats struct {
 type IndexAdvise struct {
 	baseSchemaProducer
 
-	IsLocal     bool
-	Path        string
-	MaxMinutes  uint64
-	MaxIndexNum *ast.MaxIndexNumClause
-	LinesInfo   *ast.LinesClause
+	IsLocal         bool
+	Path            string
+	FromStmtSummary bool
+	MaxMinutes      uint64
+	MaxIndexNum     *ast.MaxIndexNumClause
+	LinesInfo       *ast.LinesClause
 }
 
 // SplitRegion represents a split regions plan.
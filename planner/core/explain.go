@@ -457,6 +457,11 @@ func (p *PhysicalTableDual) ExplainInfo() string {
 	return fmt.Sprintf("rows:%v", p.RowCount)
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalJSONTable) ExplainInfo() string {
+	return explainJSONTable(p.Expr, p.Info)
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalSort) ExplainInfo() string {
 	buffer := bytes.NewBufferString("")
@@ -744,9 +749,12 @@ func (p *PhysicalWindow) ExplainInfo() string {
 		if !isFirst {
 			buffer.WriteString(" ")
 		}
-		if p.Frame.Type == ast.Rows {
+		switch p.Frame.Type {
+		case ast.Rows:
 			buffer.WriteString("rows")
-		} else {
+		case ast.Groups:
+			buffer.WriteString("groups")
+		default:
 			buffer.WriteString("range")
 		}
 		buffer.WriteString(" between ")
@@ -841,6 +849,15 @@ func (p *LogicalTableDual) ExplainInfo() string {
 	return fmt.Sprintf("rowcount:%d", p.RowCount)
 }
 
+// ExplainInfo implements Plan interface.
+func (p *LogicalJSONTable) ExplainInfo() string {
+	return explainJSONTable(p.Expr, p.Info)
+}
+
+func explainJSONTable(expr expression.Expression, info *JSONTableInfo) string {
+	return fmt.Sprintf("expr:%s, path:%s", expr.ExplainInfo(), info.Path.String())
+}
+
 // ExplainInfo implements Plan interface.
 func (p *DataSource) ExplainInfo() string {
 	buffer := bytes.NewBufferString("")
@@ -75,6 +75,9 @@ func rewriteAstExpr(sctx sessionctx.Context, expr ast.ExprNode, schema *expressi
 		fakePlan.names = names
 	}
 	b.curClause = expressionClause
+	// The expressions which are stored in the schema, like the ones of the hidden columns of the
+	// multi-valued indexes, are also rewritten here.
+	b.allowBuildCastArray = true
 	newExpr, _, err := b.rewrite(context.TODO(), expr, fakePlan, nil, true)
 	if err != nil {
 		return nil, err
@@ -332,6 +335,18 @@ func (er *expressionRewriter) buildSubquery(ctx context.Context, subq *ast.Subqu
 	return np, nil
 }
 
+// buildEvaluableSubquery builds a subquery which may be evaluated during planning if it is uncorrelated.
+// Hypothetical indexes have no data, so they are made invisible to such subqueries.
+func (er *expressionRewriter) buildEvaluableSubquery(ctx context.Context, subq *ast.SubqueryExpr) (LogicalPlan, error) {
+	sessVars := er.sctx.GetSessionVars()
+	hypoIndexes := sessVars.HypoIndexes
+	sessVars.HypoIndexes = nil
+	defer func() {
+		sessVars.HypoIndexes = hypoIndexes
+	}()
+	return er.buildSubquery(ctx, subq)
+}
+
 // Enter implements Visitor interface.
 func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
 	switch v := inNode.(type) {
@@ -785,7 +800,7 @@ func (er *expressionRewriter) handleExistSubquery(ctx context.Context, v *ast.Ex
 		er.err = errors.Errorf("Unknown exists type %T.", v.Sel)
 		return v, true
 	}
-	np, err := er.buildSubquery(ctx, subq)
+	np, err := er.buildEvaluableSubquery(ctx, subq)
 	if err != nil {
 		er.err = err
 		return v, true
@@ -954,7 +969,7 @@ func (er *expressionRewriter) handleInSubquery(ctx context.Context, v *ast.Patte
 }
 
 func (er *expressionRewriter) handleScalarSubquery(ctx context.Context, v *ast.SubqueryExpr) (ast.Node, bool) {
-	np, err := er.buildSubquery(ctx, v)
+	np, err := er.buildEvaluableSubquery(ctx, v)
 	if err != nil {
 		er.err = err
 		return v, true
@@ -1089,6 +1104,11 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 			return retNode, false
 		}
 
+		if v.Tp.ArrayElem != nil && !er.b.allowBuildCastArray {
+			er.err = ErrNotSupportedYet.GenWithStackByArgs("Use of CAST( .. AS .. ARRAY) outside of functional index in CREATE(non-SELECT)/ALTER TABLE or in general expressions")
+			return retNode, false
+		}
+
 		if v.Tp.EvalType() == types.ETString {
 			arg.SetCoercibility(expression.CoercibilityImplicit)
 		}
@@ -1759,6 +1779,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 			er.err = ErrUnknownColumn.GenWithStackByArgs(v.Name, clauseMsg[er.b.curClause])
 			return
 		}
+		er.b.visitColumn(column)
 		er.ctxStackAppend(column, er.names[idx])
 		return
 	}
@@ -1767,6 +1788,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 		idx, err = expression.FindFieldName(outerName, v)
 		if idx >= 0 {
 			column := outerSchema.Columns[idx]
+			er.b.visitColumn(column)
 			er.ctxStackAppend(&expression.CorrelatedColumn{Column: *column, Data: new(types.Datum)}, outerName[idx])
 			return
 		}
@@ -1784,6 +1806,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 		er.err = err
 		return
 	} else if col != nil {
+		er.b.visitColumn(col)
 		er.ctxStackAppend(col, name)
 		return
 	}
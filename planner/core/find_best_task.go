@@ -148,6 +148,19 @@ func (p *LogicalTableDual) findBestTask(prop *property.PhysicalProperty, planCou
 	return &rootTask{p: dual}, 1, nil
 }
 
+func (p *LogicalJSONTable) findBestTask(prop *property.PhysicalProperty, planCounter *PlanCounterTp) (task, int64, error) {
+	if !prop.IsEmpty() || planCounter.Empty() {
+		return invalidTask, 0, nil
+	}
+	jt := PhysicalJSONTable{
+		Expr: p.Expr,
+		Info: p.Info,
+	}.Init(p.ctx, p.stats, p.blockOffset)
+	jt.SetSchema(p.schema)
+	planCounter.Dec(1)
+	return &rootTask{p: jt}, 1, nil
+}
+
 func (p *LogicalShow) findBestTask(prop *property.PhysicalProperty, planCounter *PlanCounterTp) (task, int64, error) {
 	if !prop.IsEmpty() || planCounter.Empty() {
 		return invalidTask, 0, nil
@@ -701,7 +714,8 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 		canConvertPointGet := len(path.Ranges) > 0 && path.StoreType == kv.TiKV
 		if canConvertPointGet && !path.IsIntHandlePath {
 			// We simply do not build [batch] point get for prefix indexes. This can be optimized.
-			canConvertPointGet = path.Index.Unique && !path.Index.HasPrefixIndex()
+			// The multi-valued index isn't supported by [batch] point get, whose keys are encoded from the column types.
+			canConvertPointGet = path.Index.Unique && !path.Index.HasPrefixIndex() && !path.Index.MVIndex
 			// If any range cannot cover all columns of the index, we cannot build [batch] point get.
 			idxColsLen := len(path.Index.Columns)
 			for _, ran := range path.Ranges {
@@ -838,7 +852,11 @@ func (ds *DataSource) convertToIndexMergeScan(prop *property.PhysicalProperty, c
 	if prop.ExpectedCnt < ds.stats.RowCount {
 		totalRowCount *= prop.ExpectedCnt / ds.stats.RowCount
 	}
-	ts, partialCost, err := ds.buildIndexMergeTableScan(prop, path.TableFilters, totalRowCount)
+	// The table filters which can't be pushed down, like the ones of the multi-valued index, are evaluated by TiDB.
+	tableFilters, rootTaskConds := SplitSelCondsWithVirtualColumn(append([]expression.Expression(nil), path.TableFilters...))
+	tableFilters, newRootConds := expression.PushDownExprs(ds.ctx.GetSessionVars().StmtCtx, tableFilters, ds.ctx.GetClient(), kv.TiKV)
+	cop.rootTaskConds = append(rootTaskConds, newRootConds...)
+	ts, partialCost, err := ds.buildIndexMergeTableScan(prop, tableFilters, totalRowCount)
 	if err != nil {
 		return nil, err
 	}
@@ -1458,6 +1476,9 @@ func getMostCorrCol4Index(path *util.AccessPath, histColl *statistics.Table, thr
 		colSet.Insert(col.UniqueID)
 		curCorr := float64(0)
 		for _, item := range histColl.ExtendedStats.Stats {
+			if item.Tp != ast.StatsTypeCorrelation {
+				continue
+			}
 			if (col.ID == item.ColIDs[0] && path.FullIdxCols[0].ID == item.ColIDs[1]) ||
 				(col.ID == item.ColIDs[1] && path.FullIdxCols[0].ID == item.ColIDs[0]) {
 				curCorr = item.ScalarVals
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/charset"
 	"github.com/pingcap/parser/format"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
@@ -47,6 +48,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	util2 "github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
@@ -322,6 +324,9 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 		case *ast.TableName:
 			p, err = b.buildDataSource(ctx, v, &x.AsName)
 			isTableName = true
+		case *ast.JSONTable:
+			p, err = b.buildJSONTable(ctx, nil, InnerJoin, v, x.AsName)
+			isTableName = true
 		default:
 			err = ErrUnsupportedType.GenWithStackByArgs(v)
 		}
@@ -663,6 +668,11 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 	if err != nil {
 		return nil, err
 	}
+	if ts, ok := joinNode.Right.(*ast.TableSource); ok {
+		if jt, ok := ts.Source.(*ast.JSONTable); ok {
+			return b.buildJoinWithJSONTable(ctx, joinNode, leftPlan, jt, ts.AsName)
+		}
+	}
 
 	rightPlan, err := b.buildResultSetNode(ctx, joinNode.Right)
 	if err != nil {
@@ -763,6 +773,38 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 	return joinPlan, nil
 }
 
+// buildJoinWithJSONTable builds the join whose right side is JSON_TABLE, the JSON_TABLE may refer to
+// the columns of the left side, so it's built as the inner side of an Apply.
+func (b *PlanBuilder) buildJoinWithJSONTable(ctx context.Context, joinNode *ast.Join, leftPlan LogicalPlan, jt *ast.JSONTable, asName model.CIStr) (LogicalPlan, error) {
+	if joinNode.NaturalJoin || joinNode.Using != nil || joinNode.Tp == ast.RightJoin {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("NATURAL JOIN, USING or RIGHT JOIN with JSON_TABLE")
+	}
+	joinType := InnerJoin
+	if joinNode.Tp == ast.LeftJoin {
+		joinType = LeftOuterJoin
+	}
+	p, err := b.buildJSONTable(ctx, leftPlan, joinType, jt, asName)
+	if err != nil || joinNode.On == nil {
+		return p, err
+	}
+	b.curClause = onClause
+	onExpr, newPlan, err := b.rewrite(ctx, joinNode.On.Expr, p, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	if newPlan != p {
+		return nil, errors.New("ON condition doesn't support subqueries yet")
+	}
+	onCondition := expression.SplitCNFItems(onExpr)
+	if joinType == InnerJoin {
+		sel := LogicalSelection{Conditions: onCondition}.Init(b.ctx, b.getSelectOffset())
+		sel.SetChildren(p)
+		return sel, nil
+	}
+	p.(*LogicalApply).AttachOnConds(onCondition)
+	return p, nil
+}
+
 // buildUsingClause eliminate the redundant columns and ordering columns based
 // on the "USING" clause.
 //
@@ -3520,6 +3562,178 @@ func (b *PlanBuilder) buildTableDual() *LogicalTableDual {
 	return LogicalTableDual{RowCount: 1}.Init(b.ctx, b.getSelectOffset())
 }
 
+// buildJSONTable builds the plan of `JSON_TABLE(expr, path COLUMNS (...))`. The expr may refer to the columns of
+// outer, which is the plan on the left side of the JSON_TABLE in the FROM clause, then an Apply of joinType is
+// built whose inner side is the JSON_TABLE.
+func (b *PlanBuilder) buildJSONTable(ctx context.Context, outer LogicalPlan, joinType JoinType, node *ast.JSONTable, asName model.CIStr) (LogicalPlan, error) {
+	info, err := buildJSONTableInfo(b.ctx, node, asName)
+	if err != nil {
+		return nil, err
+	}
+	if outer != nil {
+		b.outerSchemas = append(b.outerSchemas, outer.Schema())
+		b.outerNames = append(b.outerNames, outer.OutputNames())
+		defer func() {
+			b.outerSchemas = b.outerSchemas[0 : len(b.outerSchemas)-1]
+			b.outerNames = b.outerNames[0 : len(b.outerNames)-1]
+		}()
+	}
+	mockTablePlan := LogicalTableDual{}.Init(b.ctx, b.getSelectOffset())
+	mockTablePlan.SetSchema(expression.NewSchema())
+	expr, _, err := b.rewrite(ctx, node.Expr, mockTablePlan, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if expr.GetType().Tp != mysql.TypeJSON {
+		expr = expression.BuildCastFunction(b.ctx, expr, types.NewFieldType(mysql.TypeJSON))
+	}
+
+	jt := LogicalJSONTable{Expr: expr, Info: info}.Init(b.ctx, b.getSelectOffset())
+	schema := expression.NewSchema()
+	names := make([]*types.FieldName, 0, len(info.Columns))
+	if err := b.appendJSONTableColumns(info, info.Columns, schema, &names); err != nil {
+		return nil, err
+	}
+	jt.SetSchema(schema)
+	jt.names = names
+	if outer == nil {
+		b.handleHelper.pushMap(nil)
+		return jt, nil
+	}
+	ap := b.buildApplyWithJoinType(outer, jt, joinType)
+	copy(ap.OutputNames()[outer.Schema().Len():], names)
+	return ap, nil
+}
+
+// buildJSONTableInfo parses the paths and the default values of JSON_TABLE, and completes the types of its columns.
+func buildJSONTableInfo(sctx sessionctx.Context, node *ast.JSONTable, asName model.CIStr) (*JSONTableInfo, error) {
+	path, err := json.ParseJSONPathExpr(node.Path)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := buildJSONTableColumns(sctx, node.Columns)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONTableInfo{Name: asName, Path: path, Columns: cols}, nil
+}
+
+func buildJSONTableColumns(sctx sessionctx.Context, astCols []*ast.JSONTableColumn) ([]*JSONTableColumn, error) {
+	cols := make([]*JSONTableColumn, 0, len(astCols))
+	for _, astCol := range astCols {
+		col := &JSONTableColumn{Name: astCol.Name}
+		var err error
+		switch astCol.Tp {
+		case ast.JSONTableColumnOrdinality:
+			// The type of the FOR ORDINALITY column is INT UNSIGNED like MySQL.
+			col.Tp = JSONTableColumnOrdinality
+			col.FieldType = *types.NewFieldType(mysql.TypeLong)
+			col.FieldType.Flag |= mysql.UnsignedFlag
+		case ast.JSONTableColumnNested:
+			col.Tp = JSONTableColumnNested
+			if col.Columns, err = buildJSONTableColumns(sctx, astCol.Columns); err != nil {
+				return nil, err
+			}
+		case ast.JSONTableColumnExistsPath:
+			col.Tp = JSONTableColumnExistsPath
+			col.FieldType = *astCol.FieldType.Clone()
+		default:
+			col.Tp = JSONTableColumnPath
+			col.FieldType = *astCol.FieldType.Clone()
+			if col.OnEmpty, err = buildJSONTableOnResponse(astCol.OnEmpty); err != nil {
+				return nil, err
+			}
+			if col.OnError, err = buildJSONTableOnResponse(astCol.OnError); err != nil {
+				return nil, err
+			}
+		}
+		if col.Tp != JSONTableColumnOrdinality {
+			if col.Path, err = json.ParseJSONPathExpr(astCol.Path); err != nil {
+				return nil, err
+			}
+		}
+		if col.Tp != JSONTableColumnNested {
+			if err = setJSONTableColumnCharsetAndFlen(sctx, &col.FieldType); err != nil {
+				return nil, err
+			}
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+func buildJSONTableOnResponse(resp *ast.JSONTableOnResponse) (JSONTableOnResponse, error) {
+	if resp == nil {
+		return JSONTableOnResponse{Tp: JSONTableOnResponseNull}, nil
+	}
+	switch resp.Tp {
+	case ast.JSONTableOnResponseError:
+		return JSONTableOnResponse{Tp: JSONTableOnResponseError}, nil
+	case ast.JSONTableOnResponseDefault:
+		def, err := json.ParseBinaryFromString(resp.Default)
+		if err != nil {
+			return JSONTableOnResponse{}, err
+		}
+		return JSONTableOnResponse{Tp: JSONTableOnResponseDefault, Default: def}, nil
+	}
+	return JSONTableOnResponse{Tp: JSONTableOnResponseNull}, nil
+}
+
+// setJSONTableColumnCharsetAndFlen uses the connection charset for the string columns whose charset is not
+// specified, and the default length and decimal for the unspecified ones.
+func setJSONTableColumnCharsetAndFlen(sctx sessionctx.Context, tp *types.FieldType) error {
+	if tp.EvalType() == types.ETString {
+		if tp.Charset == "" {
+			tp.Charset, tp.Collate = sctx.GetSessionVars().GetCharsetInfo()
+		} else if tp.Collate == "" {
+			var err error
+			if tp.Collate, err = charset.GetDefaultCollation(tp.Charset); err != nil {
+				return err
+			}
+		}
+	} else {
+		types.SetBinChsClnFlag(tp)
+	}
+	defaultFlen, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimal(tp.Tp)
+	if tp.Flen == types.UnspecifiedLength {
+		tp.Flen = defaultFlen
+	}
+	if tp.Decimal == types.UnspecifiedLength {
+		tp.Decimal = defaultDecimal
+	}
+	return nil
+}
+
+// appendJSONTableColumns appends the columns of JSON_TABLE to the schema in the depth-first order, the
+// columns of a NESTED PATH clause are placed at the position of the clause.
+func (b *PlanBuilder) appendJSONTableColumns(info *JSONTableInfo, cols []*JSONTableColumn, schema *expression.Schema, names *[]*types.FieldName) error {
+	for _, col := range cols {
+		if col.Tp == JSONTableColumnNested {
+			if err := b.appendJSONTableColumns(info, col.Columns, schema, names); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, name := range *names {
+			if name.ColName.L == col.Name.L {
+				return ErrDupFieldName.GenWithStackByArgs(col.Name.O)
+			}
+		}
+		schema.Append(&expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  col.FieldType.Clone(),
+			OrigName: fmt.Sprintf("%v.%v", info.Name, col.Name),
+		})
+		*names = append(*names, &types.FieldName{
+			TblName:     info.Name,
+			OrigTblName: info.Name,
+			ColName:     col.Name,
+			OrigColName: col.Name,
+		})
+	}
+	return nil
+}
+
 func (ds *DataSource) newExtraHandleSchemaCol() *expression.Column {
 	tp := types.NewFieldType(mysql.TypeLonglong)
 	tp.Flag = mysql.NotNullFlag | mysql.PriKeyFlag
@@ -3589,7 +3803,7 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 	if sessionVars.User != nil {
 		authErr = ErrTableaccessDenied.FastGenByArgs("SELECT", sessionVars.User.AuthUsername, sessionVars.User.AuthHostname, tableInfo.Name.L)
 	}
-	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName.L, tableInfo.Name.L, "", authErr)
+	b.visitInfo = appendAnyColumnVisitInfo(b.visitInfo, mysql.SelectPriv, dbName.L, tableInfo.Name.L, authErr)
 
 	if tbl.Type().IsVirtualTable() {
 		if tn.TableSample != nil {
@@ -3652,6 +3866,7 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 			return nil, err
 		}
 	}
+	possiblePaths, mvIndexPaths := splitMVIndexPaths(possiblePaths, tableInfo)
 
 	// Try to substitute generate column only if there is an index on generate column.
 	for _, index := range tableInfo.Indices {
@@ -3737,6 +3952,7 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 		IndexHints:          b.TableHints().indexHintList,
 		indexMergeHints:     indexMergeHints,
 		possibleAccessPaths: possiblePaths,
+		mvIndexPaths:        mvIndexPaths,
 		Columns:             make([]*model.ColumnInfo, 0, len(columns)),
 		partitionNames:      tn.PartitionNames,
 		TblCols:             make([]*expression.Column, 0, len(columns)),
@@ -3798,7 +4014,10 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 	ds.SetSchema(schema)
 	ds.names = names
 	ds.setPreferredStoreType(b.TableHints())
-	ds.SampleInfo = NewTableSampleInfo(tn.TableSample, schema.Clone(), b.partitionedTable)
+	ds.SampleInfo, err = NewTableSampleInfo(tn.TableSample, schema.Clone(), b.partitionedTable)
+	if err != nil {
+		return nil, err
+	}
 	b.isSampling = ds.SampleInfo != nil
 
 	// Init commonHandleCols and commonHandleLens for data source.
@@ -3829,7 +4048,9 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 		if i < len(columns) {
 			if columns[i].IsGenerated() && !columns[i].GeneratedStored {
 				var err error
+				b.allowBuildCastArray = true
 				expr, _, err = b.rewrite(ctx, columns[i].GeneratedExpr, ds, nil, true)
+				b.allowBuildCastArray = false
 				if err != nil {
 					return nil, err
 				}
@@ -3837,10 +4058,99 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 			}
 		}
 	}
+	result, err = b.buildRowPolicyFilter(ctx, result, tableInfo)
+	if err != nil {
+		return nil, err
+	}
+	// The generated columns and the row policies are rewritten above, whose dependencies are not referenced by the statement.
+	b.registerColumnVisitInfo(dbName.L, tableInfo.Name.L, ds.Schema().Columns, names)
 
 	return result, nil
 }
 
+// buildRowPolicyFilter filters the rows of the table by the row-level security policies applied to the current user,
+// a row is visible if it satisfies the USING predicate of any policy.
+func (b *PlanBuilder) buildRowPolicyFilter(ctx context.Context, p LogicalPlan, tableInfo *model.TableInfo) (LogicalPlan, error) {
+	pm := privilege.GetPrivilegeManager(b.ctx)
+	if pm == nil || b.inRowPolicy {
+		return p, nil
+	}
+	policies := pm.RowPolicies(b.ctx.GetSessionVars().ActiveRoles, tableInfo)
+	if len(policies) == 0 {
+		return p, nil
+	}
+	// Whether the policies are applied depends on the current user and roles, so the plan can't be cached.
+	b.ctx.GetSessionVars().StmtCtx.OptimDependOnMutableConst = true
+	var cond ast.ExprNode
+	for _, policy := range policies {
+		expr, err := b.parseRowPolicyExpr(policy.Using)
+		if err != nil {
+			return nil, err
+		}
+		if cond == nil {
+			cond = expr
+		} else {
+			cond = &ast.BinaryOperationExpr{Op: opcode.LogicOr, L: cond, R: expr}
+		}
+	}
+	b.inRowPolicy = true
+	savedClause := b.curClause
+	defer func() {
+		b.inRowPolicy = false
+		b.curClause = savedClause
+	}()
+	oldLen := p.Schema().Len()
+	np, err := b.buildSelection(ctx, p, cond, nil)
+	if err != nil || np.Schema().Len() == oldLen {
+		return np, err
+	}
+	// The subqueries in the predicates may append auxiliary columns, which are pruned to keep the schema of the table.
+	proj := LogicalProjection{Exprs: expression.Column2Exprs(np.Schema().Columns[:oldLen])}.Init(b.ctx, b.getSelectOffset())
+	proj.SetChildren(np)
+	proj.SetSchema(p.Schema().Clone())
+	proj.names = p.OutputNames()
+	return proj, nil
+}
+
+func (b *PlanBuilder) parseRowPolicyExpr(exprStr string) (ast.ExprNode, error) {
+	charset, collation := b.ctx.GetSessionVars().GetCharsetInfo()
+	policyParser := parser.New()
+	policyParser.SetParserConfig(b.ctx.GetSessionVars().BuildParserConfig())
+	stmt, err := policyParser.ParseOneStmt("SELECT "+exprStr, charset, collation)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.(*ast.SelectStmt).Fields.Fields[0].Expr, nil
+}
+
+// registerColumnVisitInfo registers the column-level SELECT privilege check of the columns of the table or view,
+// which is appended to visitInfo once the column is referenced by the statement.
+func (b *PlanBuilder) registerColumnVisitInfo(db, tbl string, cols []*expression.Column, names types.NameSlice) {
+	if b.columnVisitInfo == nil {
+		b.columnVisitInfo = make(map[int64]visitInfo, len(cols))
+	}
+	user := b.ctx.GetSessionVars().User
+	for i, col := range cols {
+		name := names[i]
+		if name.Hidden || name.ColName.L == model.ExtraHandleName.L {
+			continue
+		}
+		var err error
+		if user != nil {
+			err = ErrColumnaccessDenied.FastGenByArgs("SELECT", user.AuthUsername, user.AuthHostname, name.ColName.O, tbl)
+		}
+		b.columnVisitInfo[col.UniqueID] = visitInfo{privilege: mysql.SelectPriv, db: db, table: tbl, column: name.ColName.L, err: err}
+	}
+}
+
+// visitColumn appends the column-level privilege check of the column to visitInfo if it's referenced the first time.
+func (b *PlanBuilder) visitColumn(col *expression.Column) {
+	if v, ok := b.columnVisitInfo[col.UniqueID]; ok {
+		b.visitInfo = append(b.visitInfo, v)
+		delete(b.columnVisitInfo, col.UniqueID)
+	}
+}
+
 func (b *PlanBuilder) timeRangeForSummaryTable() QueryTimeRange {
 	const defaultSummaryDuration = 30 * time.Minute
 	hints := b.TableHints()
@@ -3997,7 +4307,13 @@ func (b *PlanBuilder) BuildDataSourceFromView(ctx context.Context, dbName model.
 	if tableInfo.View.Security == model.SecurityDefiner {
 		if pm := privilege.GetPrivilegeManager(b.ctx); pm != nil {
 			for _, v := range b.visitInfo {
-				if !pm.RequestVerificationWithUser(v.db, v.table, v.column, v.privilege, tableInfo.View.Definer) {
+				var ok bool
+				if v.anyColumn {
+					ok = pm.RequestAnyColumnVerificationWithUser(v.db, v.table, v.privilege, tableInfo.View.Definer)
+				} else {
+					ok = pm.RequestVerificationWithUser(v.db, v.table, v.column, v.privilege, tableInfo.View.Definer)
+				}
+				if !ok {
 					return nil, ErrViewInvalid.GenWithStackByArgs(dbName.O, tableInfo.Name.O)
 				}
 			}
@@ -4014,7 +4330,12 @@ func (b *PlanBuilder) BuildDataSourceFromView(ctx context.Context, dbName model.
 		return nil, ErrViewInvalid.GenWithStackByArgs(dbName.O, tableInfo.Name.O)
 	}
 
-	return b.buildProjUponView(ctx, dbName, tableInfo, selectLogicalPlan)
+	projUponView, err := b.buildProjUponView(ctx, dbName, tableInfo, selectLogicalPlan)
+	if err != nil {
+		return nil, err
+	}
+	b.registerColumnVisitInfo(dbName.L, tableInfo.Name.L, projUponView.Schema().Columns, projUponView.OutputNames())
+	return projUponView, nil
 }
 
 func (b *PlanBuilder) buildProjUponView(ctx context.Context, dbName model.CIStr, tableInfo *model.TableInfo, selectLogicalPlan Plan) (LogicalPlan, error) {
@@ -4267,7 +4588,7 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 		if dbName == "" {
 			dbName = b.ctx.GetSessionVars().CurrentDB
 		}
-		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName, t.Name.L, "", nil)
+		b.visitInfo = appendAnyColumnVisitInfo(b.visitInfo, mysql.SelectPriv, dbName, t.Name.L, nil)
 	}
 
 	oldSchemaLen := p.Schema().Len()
@@ -4536,7 +4857,14 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 					return expr
 				}
 			}
+			// The columns referenced by the generation expression are not referenced by the statement,
+			// so they are not checked against the column-level privileges.
+			columnVisitInfo := b.columnVisitInfo
+			b.columnVisitInfo = nil
+			b.allowBuildCastArray = true
 			newExpr, np, err = b.rewriteWithPreprocess(ctx, assign.Expr, p, nil, nil, false, rewritePreprocess)
+			b.allowBuildCastArray = false
+			b.columnVisitInfo = columnVisitInfo
 			if err != nil {
 				return nil, nil, false, err
 			}
@@ -4567,7 +4895,15 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 		if dbName == "" {
 			dbName = b.ctx.GetSessionVars().CurrentDB
 		}
-		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.UpdatePriv, dbName, name.OrigTblName.L, "", nil)
+		// The generated columns are updated along with the columns they depend on.
+		if i < len(list) {
+			var authErr error
+			if user := b.ctx.GetSessionVars().User; user != nil {
+				authErr = ErrColumnaccessDenied.FastGenByArgs("UPDATE", user.AuthUsername, user.AuthHostname, name.OrigColName.O, name.OrigTblName.O)
+			}
+			b.visitInfo = appendAnyColumnVisitInfo(b.visitInfo, mysql.UpdatePriv, dbName, name.OrigTblName.L, nil)
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.UpdatePriv, dbName, name.OrigTblName.L, name.OrigColName.L, authErr)
+		}
 	}
 	return newList, p, allAssignmentsAreConstant, nil
 }
@@ -4945,7 +5281,7 @@ func (b *PlanBuilder) buildByItemsForWindow(
 }
 
 // buildWindowFunctionFrameBound builds the bounds of window function frames.
-// For type `Rows`, the bound expr must be an unsigned integer.
+// For type `Rows` and `Groups`, the bound expr must be an unsigned integer.
 // For type `Range`, the bound expr must be temporal or numeric types.
 func (b *PlanBuilder) buildWindowFunctionFrameBound(ctx context.Context, spec *ast.WindowSpec, orderByItems []property.SortItem, boundClause *ast.FrameBound) (*FrameBound, error) {
 	frameType := spec.Frame.Type
@@ -4954,7 +5290,8 @@ func (b *PlanBuilder) buildWindowFunctionFrameBound(ctx context.Context, spec *a
 		return bound, nil
 	}
 
-	if frameType == ast.Rows {
+	// The offsets of `Groups` frames are the numbers of peer groups.
+	if frameType == ast.Rows || frameType == ast.Groups {
 		if bound.Type == ast.CurrentRow {
 			return bound, nil
 		}
@@ -5074,9 +5411,6 @@ func (b *PlanBuilder) buildWindowFunctionFrame(ctx context.Context, spec *ast.Wi
 
 func (b *PlanBuilder) checkWindowFuncArgs(ctx context.Context, p LogicalPlan, windowFuncExprs []*ast.WindowFuncExpr, windowAggMap map[*ast.AggregateFuncExpr]int) error {
 	for _, windowFuncExpr := range windowFuncExprs {
-		if strings.ToLower(windowFuncExpr.F) == ast.AggFuncGroupConcat {
-			return ErrNotSupportedYet.GenWithStackByArgs("group_concat as window function")
-		}
 		args, err := b.buildArgs4WindowFunc(ctx, p, windowFuncExpr.Args, windowAggMap)
 		if err != nil {
 			return err
@@ -5200,6 +5534,10 @@ func (b *PlanBuilder) buildWindowFunctions(ctx context.Context, p LogicalPlan, g
 				return nil, nil, ErrWrongArguments.GenWithStackByArgs(strings.ToLower(windowFunc.F))
 			}
 			preArgs += len(windowFunc.Args)
+			desc.HasDistinct = windowFunc.Distinct
+			desc.IgnoreNull = windowFunc.IgnoreNull
+			desc.FromLast = windowFunc.FromLast
+			desc.WrapCastAsDecimalForAggArgs(b.ctx)
 			desc.WrapCastForAggArgs(b.ctx)
 			descs = append(descs, desc)
 			windowMap[windowFunc] = schema.Len()
@@ -5221,15 +5559,6 @@ func (b *PlanBuilder) buildWindowFunctions(ctx context.Context, p LogicalPlan, g
 // Because the grouped specification is different from them, we should especially check them before build window frame.
 func (b *PlanBuilder) checkOriginWindowFuncs(funcs []*ast.WindowFuncExpr, orderByItems []property.SortItem) error {
 	for _, f := range funcs {
-		if f.IgnoreNull {
-			return ErrNotSupportedYet.GenWithStackByArgs("IGNORE NULLS")
-		}
-		if f.Distinct {
-			return ErrNotSupportedYet.GenWithStackByArgs("<window function>(DISTINCT ..)")
-		}
-		if f.FromLast {
-			return ErrNotSupportedYet.GenWithStackByArgs("FROM LAST")
-		}
 		spec := &f.Spec
 		if f.Spec.Name.L != "" {
 			spec = b.windowSpecs[f.Spec.Name.L]
@@ -5246,9 +5575,6 @@ func (b *PlanBuilder) checkOriginWindowSpec(spec *ast.WindowSpec, orderByItems [
 	if spec.Frame == nil {
 		return nil
 	}
-	if spec.Frame.Type == ast.Groups {
-		return ErrNotSupportedYet.GenWithStackByArgs("GROUPS")
-	}
 	start, end := spec.Frame.Extent.Start, spec.Frame.Extent.End
 	if start.Type == ast.Following && start.UnBounded {
 		return ErrWindowFrameStartIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
@@ -5280,7 +5606,7 @@ func (b *PlanBuilder) checkOriginWindowFrameBound(bound *ast.FrameBound, spec *a
 	}
 
 	frameType := spec.Frame.Type
-	if frameType == ast.Rows {
+	if frameType == ast.Rows || frameType == ast.Groups {
 		if bound.Unit != ast.TimeUnitInvalid {
 			return ErrWindowRowsIntervalUse.GenWithStackByArgs(getWindowName(spec.Name.O))
 		}
@@ -5577,6 +5903,18 @@ func appendVisitInfo(vi []visitInfo, priv mysql.PrivilegeType, db, tbl, col stri
 	})
 }
 
+// appendAnyColumnVisitInfo appends the privilege check on the table which passes with the privilege on any column
+// of the table, it's used along with the column-level checks of the columns referenced.
+func appendAnyColumnVisitInfo(vi []visitInfo, priv mysql.PrivilegeType, db, tbl string, err error) []visitInfo {
+	return append(vi, visitInfo{
+		privilege: priv,
+		db:        db,
+		table:     tbl,
+		err:       err,
+		anyColumn: true,
+	})
+}
+
 func getInnerFromParenthesesAndUnaryPlus(expr ast.ExprNode) ast.ExprNode {
 	if pexpr, ok := expr.(*ast.ParenthesesExpr); ok {
 		return getInnerFromParenthesesAndUnaryPlus(pexpr.Expr)
@@ -945,254 +945,300 @@ func (s *testPlanSuite) TestVisitInfo(c *C) {
 		{
 			sql: "insert into t (a) values (1)",
 			ans: []visitInfo{
-				{mysql.InsertPriv, "test", "t", "", nil, false, "", false},
+				{mysql.InsertPriv, "test", "t", "", nil, false, "", false, true},
+				{mysql.InsertPriv, "test", "t", "a", nil, false, "", false, false},
+			},
+		},
+		{
+			sql: "insert into t (a) values (1) on duplicate key update b = 2",
+			ans: []visitInfo{
+				{mysql.InsertPriv, "test", "t", "", nil, false, "", false, true},
+				{mysql.InsertPriv, "test", "t", "a", nil, false, "", false, false},
+				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false, true},
+				{mysql.UpdatePriv, "test", "t", "b", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "delete from t where a = 1",
 			ans: []visitInfo{
-				{mysql.DeletePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.DeletePriv, "test", "t", "", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, true},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "delete from t order by a",
 			ans: []visitInfo{
-				{mysql.DeletePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.DeletePriv, "test", "t", "", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, true},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "delete from t",
 			ans: []visitInfo{
-				{mysql.DeletePriv, "test", "t", "", nil, false, "", false},
+				{mysql.DeletePriv, "test", "t", "", nil, false, "", false, false},
 			},
 		},
 		/* Not currently supported. See https://github.com/pingcap/tidb/issues/23644
 		{
 			sql: "delete from t where 1=1",
 			ans: []visitInfo{
-				{mysql.DeletePriv, "test", "t", "", nil, false, "", false},
+				{mysql.DeletePriv, "test", "t", "", nil, false, "", false, false},
 			},
 		},
 		*/
 		{
 			sql: "delete from a1 using t as a1 inner join t as a2 where a1.a = a2.a",
 			ans: []visitInfo{
-				{mysql.DeletePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.DeletePriv, "test", "t", "", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, true},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "update t set a = 7 where a = 1",
 			ans: []visitInfo{
-				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false, true},
+				{mysql.UpdatePriv, "test", "t", "a", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, true},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "update t, (select * from t) a1 set t.a = a1.a;",
 			ans: []visitInfo{
-				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false, true},
+				{mysql.UpdatePriv, "test", "t", "a", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, true},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "b", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "c", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "d", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "e", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "c_str", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "d_str", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "e_str", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "f", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "g", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "h", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "i_date", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "update t a1 set a1.a = a1.a + 1",
 			ans: []visitInfo{
-				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.UpdatePriv, "test", "t", "", nil, false, "", false, true},
+				{mysql.UpdatePriv, "test", "t", "a", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, true},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "select a, sum(e) from t group by a",
 			ans: []visitInfo{
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, true},
+				{mysql.SelectPriv, "test", "t", "a", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "e", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "truncate table t",
 			ans: []visitInfo{
-				{mysql.DropPriv, "test", "t", "", nil, false, "", false},
+				{mysql.DropPriv, "test", "t", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "drop table t",
 			ans: []visitInfo{
-				{mysql.DropPriv, "test", "t", "", nil, false, "", false},
+				{mysql.DropPriv, "test", "t", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "create table t (a int)",
 			ans: []visitInfo{
-				{mysql.CreatePriv, "test", "t", "", nil, false, "", false},
+				{mysql.CreatePriv, "test", "t", "", nil, false, "", false, false},
+			},
+		},
+		{
+			sql: "create table t1 (a int, foreign key (a) references t (a))",
+			ans: []visitInfo{
+				{mysql.CreatePriv, "test", "t1", "", nil, false, "", false, false},
+				{mysql.ReferencesPriv, "test", "t", "a", nil, false, "", false, false},
+			},
+		},
+		{
+			sql: "alter table t add constraint fk foreign key (b) references t (a)",
+			ans: []visitInfo{
+				{mysql.AlterPriv, "test", "t", "", nil, false, "", false, false},
+				{mysql.ReferencesPriv, "test", "t", "a", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "create table t1 like t",
 			ans: []visitInfo{
-				{mysql.CreatePriv, "test", "t1", "", nil, false, "", false},
-				{mysql.SelectPriv, "test", "t", "", nil, false, "", false},
+				{mysql.CreatePriv, "test", "t1", "", nil, false, "", false, false},
+				{mysql.SelectPriv, "test", "t", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "create database test",
 			ans: []visitInfo{
-				{mysql.CreatePriv, "test", "", "", nil, false, "", false},
+				{mysql.CreatePriv, "test", "", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "drop database test",
 			ans: []visitInfo{
-				{mysql.DropPriv, "test", "", "", nil, false, "", false},
+				{mysql.DropPriv, "test", "", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "create index t_1 on t (a)",
 			ans: []visitInfo{
-				{mysql.IndexPriv, "test", "t", "", nil, false, "", false},
+				{mysql.IndexPriv, "test", "t", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "drop index e on t",
 			ans: []visitInfo{
-				{mysql.IndexPriv, "test", "t", "", nil, false, "", false},
+				{mysql.IndexPriv, "test", "t", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: `grant all privileges on test.* to 'test'@'%'`,
 			ans: []visitInfo{
-				{mysql.SelectPriv, "test", "", "", nil, false, "", false},
-				{mysql.InsertPriv, "test", "", "", nil, false, "", false},
-				{mysql.UpdatePriv, "test", "", "", nil, false, "", false},
-				{mysql.DeletePriv, "test", "", "", nil, false, "", false},
-				{mysql.CreatePriv, "test", "", "", nil, false, "", false},
-				{mysql.DropPriv, "test", "", "", nil, false, "", false},
-				{mysql.GrantPriv, "test", "", "", nil, false, "", false},
-				{mysql.AlterPriv, "test", "", "", nil, false, "", false},
-				{mysql.ExecutePriv, "test", "", "", nil, false, "", false},
-				{mysql.IndexPriv, "test", "", "", nil, false, "", false},
-				{mysql.CreateViewPriv, "test", "", "", nil, false, "", false},
-				{mysql.ShowViewPriv, "test", "", "", nil, false, "", false},
+				{mysql.SelectPriv, "test", "", "", nil, false, "", false, false},
+				{mysql.InsertPriv, "test", "", "", nil, false, "", false, false},
+				{mysql.UpdatePriv, "test", "", "", nil, false, "", false, false},
+				{mysql.DeletePriv, "test", "", "", nil, false, "", false, false},
+				{mysql.CreatePriv, "test", "", "", nil, false, "", false, false},
+				{mysql.DropPriv, "test", "", "", nil, false, "", false, false},
+				{mysql.GrantPriv, "test", "", "", nil, false, "", false, false},
+				{mysql.AlterPriv, "test", "", "", nil, false, "", false, false},
+				{mysql.ExecutePriv, "test", "", "", nil, false, "", false, false},
+				{mysql.IndexPriv, "test", "", "", nil, false, "", false, false},
+				{mysql.CreateViewPriv, "test", "", "", nil, false, "", false, false},
+				{mysql.ShowViewPriv, "test", "", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: `grant all privileges on *.* to 'test'@'%'`,
 			ans: []visitInfo{
-				{mysql.SelectPriv, "", "", "", nil, false, "", false},
-				{mysql.InsertPriv, "", "", "", nil, false, "", false},
-				{mysql.UpdatePriv, "", "", "", nil, false, "", false},
-				{mysql.DeletePriv, "", "", "", nil, false, "", false},
-				{mysql.CreatePriv, "", "", "", nil, false, "", false},
-				{mysql.DropPriv, "", "", "", nil, false, "", false},
-				{mysql.ProcessPriv, "", "", "", nil, false, "", false},
-				{mysql.ReferencesPriv, "", "", "", nil, false, "", false},
-				{mysql.AlterPriv, "", "", "", nil, false, "", false},
-				{mysql.ShowDBPriv, "", "", "", nil, false, "", false},
-				{mysql.SuperPriv, "", "", "", nil, false, "", false},
-				{mysql.ExecutePriv, "", "", "", nil, false, "", false},
-				{mysql.IndexPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateUserPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateTablespacePriv, "", "", "", nil, false, "", false},
-				{mysql.TriggerPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateViewPriv, "", "", "", nil, false, "", false},
-				{mysql.ShowViewPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateRolePriv, "", "", "", nil, false, "", false},
-				{mysql.DropRolePriv, "", "", "", nil, false, "", false},
-				{mysql.CreateTMPTablePriv, "", "", "", nil, false, "", false},
-				{mysql.LockTablesPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateRoutinePriv, "", "", "", nil, false, "", false},
-				{mysql.AlterRoutinePriv, "", "", "", nil, false, "", false},
-				{mysql.EventPriv, "", "", "", nil, false, "", false},
-				{mysql.ShutdownPriv, "", "", "", nil, false, "", false},
-				{mysql.ReloadPriv, "", "", "", nil, false, "", false},
-				{mysql.FilePriv, "", "", "", nil, false, "", false},
-				{mysql.ConfigPriv, "", "", "", nil, false, "", false},
-				{mysql.ReplicationClientPriv, "", "", "", nil, false, "", false},
-				{mysql.ReplicationSlavePriv, "", "", "", nil, false, "", false},
-				{mysql.GrantPriv, "", "", "", nil, false, "", false},
+				{mysql.SelectPriv, "", "", "", nil, false, "", false, false},
+				{mysql.InsertPriv, "", "", "", nil, false, "", false, false},
+				{mysql.UpdatePriv, "", "", "", nil, false, "", false, false},
+				{mysql.DeletePriv, "", "", "", nil, false, "", false, false},
+				{mysql.CreatePriv, "", "", "", nil, false, "", false, false},
+				{mysql.DropPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ProcessPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ReferencesPriv, "", "", "", nil, false, "", false, false},
+				{mysql.AlterPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ShowDBPriv, "", "", "", nil, false, "", false, false},
+				{mysql.SuperPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ExecutePriv, "", "", "", nil, false, "", false, false},
+				{mysql.IndexPriv, "", "", "", nil, false, "", false, false},
+				{mysql.CreateUserPriv, "", "", "", nil, false, "", false, false},
+				{mysql.CreateTablespacePriv, "", "", "", nil, false, "", false, false},
+				{mysql.TriggerPriv, "", "", "", nil, false, "", false, false},
+				{mysql.CreateViewPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ShowViewPriv, "", "", "", nil, false, "", false, false},
+				{mysql.CreateRolePriv, "", "", "", nil, false, "", false, false},
+				{mysql.DropRolePriv, "", "", "", nil, false, "", false, false},
+				{mysql.CreateTMPTablePriv, "", "", "", nil, false, "", false, false},
+				{mysql.LockTablesPriv, "", "", "", nil, false, "", false, false},
+				{mysql.CreateRoutinePriv, "", "", "", nil, false, "", false, false},
+				{mysql.AlterRoutinePriv, "", "", "", nil, false, "", false, false},
+				{mysql.EventPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ShutdownPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ReloadPriv, "", "", "", nil, false, "", false, false},
+				{mysql.FilePriv, "", "", "", nil, false, "", false, false},
+				{mysql.ConfigPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ReplicationClientPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ReplicationSlavePriv, "", "", "", nil, false, "", false, false},
+				{mysql.GrantPriv, "", "", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: `grant select on test.ttt to 'test'@'%'`,
 			ans: []visitInfo{
-				{mysql.SelectPriv, "test", "ttt", "", nil, false, "", false},
-				{mysql.GrantPriv, "test", "ttt", "", nil, false, "", false},
+				{mysql.SelectPriv, "test", "ttt", "", nil, false, "", false, false},
+				{mysql.GrantPriv, "test", "ttt", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: `grant select on ttt to 'test'@'%'`,
 			ans: []visitInfo{
-				{mysql.SelectPriv, "test", "ttt", "", nil, false, "", false},
-				{mysql.GrantPriv, "test", "ttt", "", nil, false, "", false},
+				{mysql.SelectPriv, "test", "ttt", "", nil, false, "", false, false},
+				{mysql.GrantPriv, "test", "ttt", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: `revoke all privileges on test.* from 'test'@'%'`,
 			ans: []visitInfo{
-				{mysql.SelectPriv, "test", "", "", nil, false, "", false},
-				{mysql.InsertPriv, "test", "", "", nil, false, "", false},
-				{mysql.UpdatePriv, "test", "", "", nil, false, "", false},
-				{mysql.DeletePriv, "test", "", "", nil, false, "", false},
-				{mysql.CreatePriv, "test", "", "", nil, false, "", false},
-				{mysql.DropPriv, "test", "", "", nil, false, "", false},
-				{mysql.GrantPriv, "test", "", "", nil, false, "", false},
-				{mysql.AlterPriv, "test", "", "", nil, false, "", false},
-				{mysql.ExecutePriv, "test", "", "", nil, false, "", false},
-				{mysql.IndexPriv, "test", "", "", nil, false, "", false},
-				{mysql.CreateViewPriv, "test", "", "", nil, false, "", false},
-				{mysql.ShowViewPriv, "test", "", "", nil, false, "", false},
+				{mysql.SelectPriv, "test", "", "", nil, false, "", false, false},
+				{mysql.InsertPriv, "test", "", "", nil, false, "", false, false},
+				{mysql.UpdatePriv, "test", "", "", nil, false, "", false, false},
+				{mysql.DeletePriv, "test", "", "", nil, false, "", false, false},
+				{mysql.CreatePriv, "test", "", "", nil, false, "", false, false},
+				{mysql.DropPriv, "test", "", "", nil, false, "", false, false},
+				{mysql.GrantPriv, "test", "", "", nil, false, "", false, false},
+				{mysql.AlterPriv, "test", "", "", nil, false, "", false, false},
+				{mysql.ExecutePriv, "test", "", "", nil, false, "", false, false},
+				{mysql.IndexPriv, "test", "", "", nil, false, "", false, false},
+				{mysql.CreateViewPriv, "test", "", "", nil, false, "", false, false},
+				{mysql.ShowViewPriv, "test", "", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: `revoke connection_admin on *.* from u1`,
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", nil, false, "CONNECTION_ADMIN", true},
+				{mysql.ExtendedPriv, "", "", "", nil, false, "CONNECTION_ADMIN", true, false},
 			},
 		},
 		{
 			sql: `revoke connection_admin, select on *.* from u1`,
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", nil, false, "CONNECTION_ADMIN", true},
-				{mysql.SelectPriv, "", "", "", nil, false, "", false},
-				{mysql.GrantPriv, "", "", "", nil, false, "", false},
+				{mysql.ExtendedPriv, "", "", "", nil, false, "CONNECTION_ADMIN", true, false},
+				{mysql.SelectPriv, "", "", "", nil, false, "", false, false},
+				{mysql.GrantPriv, "", "", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: `revoke all privileges on *.* FROM u1`,
 			ans: []visitInfo{
-				{mysql.SelectPriv, "", "", "", nil, false, "", false},
-				{mysql.InsertPriv, "", "", "", nil, false, "", false},
-				{mysql.UpdatePriv, "", "", "", nil, false, "", false},
-				{mysql.DeletePriv, "", "", "", nil, false, "", false},
-				{mysql.CreatePriv, "", "", "", nil, false, "", false},
-				{mysql.DropPriv, "", "", "", nil, false, "", false},
-				{mysql.ProcessPriv, "", "", "", nil, false, "", false},
-				{mysql.ReferencesPriv, "", "", "", nil, false, "", false},
-				{mysql.AlterPriv, "", "", "", nil, false, "", false},
-				{mysql.ShowDBPriv, "", "", "", nil, false, "", false},
-				{mysql.SuperPriv, "", "", "", nil, false, "", false},
-				{mysql.ExecutePriv, "", "", "", nil, false, "", false},
-				{mysql.IndexPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateUserPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateTablespacePriv, "", "", "", nil, false, "", false},
-				{mysql.TriggerPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateViewPriv, "", "", "", nil, false, "", false},
-				{mysql.ShowViewPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateRolePriv, "", "", "", nil, false, "", false},
-				{mysql.DropRolePriv, "", "", "", nil, false, "", false},
-				{mysql.CreateTMPTablePriv, "", "", "", nil, false, "", false},
-				{mysql.LockTablesPriv, "", "", "", nil, false, "", false},
-				{mysql.CreateRoutinePriv, "", "", "", nil, false, "", false},
-				{mysql.AlterRoutinePriv, "", "", "", nil, false, "", false},
-				{mysql.EventPriv, "", "", "", nil, false, "", false},
-				{mysql.ShutdownPriv, "", "", "", nil, false, "", false},
-				{mysql.ReloadPriv, "", "", "", nil, false, "", false},
-				{mysql.FilePriv, "", "", "", nil, false, "", false},
-				{mysql.ConfigPriv, "", "", "", nil, false, "", false},
-				{mysql.ReplicationClientPriv, "", "", "", nil, false, "", false},
-				{mysql.ReplicationSlavePriv, "", "", "", nil, false, "", false},
-				{mysql.GrantPriv, "", "", "", nil, false, "", false},
+				{mysql.SelectPriv, "", "", "", nil, false, "", false, false},
+				{mysql.InsertPriv, "", "", "", nil, false, "", false, false},
+				{mysql.UpdatePriv, "", "", "", nil, false, "", false, false},
+				{mysql.DeletePriv, "", "", "", nil, false, "", false, false},
+				{mysql.CreatePriv, "", "", "", nil, false, "", false, false},
+				{mysql.DropPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ProcessPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ReferencesPriv, "", "", "", nil, false, "", false, false},
+				{mysql.AlterPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ShowDBPriv, "", "", "", nil, false, "", false, false},
+				{mysql.SuperPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ExecutePriv, "", "", "", nil, false, "", false, false},
+				{mysql.IndexPriv, "", "", "", nil, false, "", false, false},
+				{mysql.CreateUserPriv, "", "", "", nil, false, "", false, false},
+				{mysql.CreateTablespacePriv, "", "", "", nil, false, "", false, false},
+				{mysql.TriggerPriv, "", "", "", nil, false, "", false, false},
+				{mysql.CreateViewPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ShowViewPriv, "", "", "", nil, false, "", false, false},
+				{mysql.CreateRolePriv, "", "", "", nil, false, "", false, false},
+				{mysql.DropRolePriv, "", "", "", nil, false, "", false, false},
+				{mysql.CreateTMPTablePriv, "", "", "", nil, false, "", false, false},
+				{mysql.LockTablesPriv, "", "", "", nil, false, "", false, false},
+				{mysql.CreateRoutinePriv, "", "", "", nil, false, "", false, false},
+				{mysql.AlterRoutinePriv, "", "", "", nil, false, "", false, false},
+				{mysql.EventPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ShutdownPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ReloadPriv, "", "", "", nil, false, "", false, false},
+				{mysql.FilePriv, "", "", "", nil, false, "", false, false},
+				{mysql.ConfigPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ReplicationClientPriv, "", "", "", nil, false, "", false, false},
+				{mysql.ReplicationSlavePriv, "", "", "", nil, false, "", false, false},
+				{mysql.GrantPriv, "", "", "", nil, false, "", false, false},
 			},
 		},
 		{
@@ -1202,104 +1248,104 @@ func (s *testPlanSuite) TestVisitInfo(c *C) {
 		{
 			sql: `show create table test.ttt`,
 			ans: []visitInfo{
-				{mysql.AllPrivMask, "test", "ttt", "", nil, false, "", false},
+				{mysql.AllPrivMask, "test", "ttt", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "alter table t add column a int(4)",
 			ans: []visitInfo{
-				{mysql.AlterPriv, "test", "t", "", nil, false, "", false},
+				{mysql.AlterPriv, "test", "t", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "rename table t_old to t_new",
 			ans: []visitInfo{
-				{mysql.AlterPriv, "test", "t_old", "", nil, false, "", false},
-				{mysql.DropPriv, "test", "t_old", "", nil, false, "", false},
-				{mysql.CreatePriv, "test", "t_new", "", nil, false, "", false},
-				{mysql.InsertPriv, "test", "t_new", "", nil, false, "", false},
+				{mysql.AlterPriv, "test", "t_old", "", nil, false, "", false, false},
+				{mysql.DropPriv, "test", "t_old", "", nil, false, "", false, false},
+				{mysql.CreatePriv, "test", "t_new", "", nil, false, "", false, false},
+				{mysql.InsertPriv, "test", "t_new", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "alter table t_old rename to t_new",
 			ans: []visitInfo{
-				{mysql.AlterPriv, "test", "t_old", "", nil, false, "", false},
-				{mysql.DropPriv, "test", "t_old", "", nil, false, "", false},
-				{mysql.CreatePriv, "test", "t_new", "", nil, false, "", false},
-				{mysql.InsertPriv, "test", "t_new", "", nil, false, "", false},
+				{mysql.AlterPriv, "test", "t_old", "", nil, false, "", false, false},
+				{mysql.DropPriv, "test", "t_old", "", nil, false, "", false, false},
+				{mysql.CreatePriv, "test", "t_new", "", nil, false, "", false, false},
+				{mysql.InsertPriv, "test", "t_new", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "alter table t drop partition p0;",
 			ans: []visitInfo{
-				{mysql.AlterPriv, "test", "t", "", nil, false, "", false},
-				{mysql.DropPriv, "test", "t", "", nil, false, "", false},
+				{mysql.AlterPriv, "test", "t", "", nil, false, "", false, false},
+				{mysql.DropPriv, "test", "t", "", nil, false, "", false, false},
 			},
 		},
 		{
 			sql: "flush privileges",
 			ans: []visitInfo{
-				{mysql.ReloadPriv, "", "", "", ErrSpecificAccessDenied, false, "", false},
+				{mysql.ReloadPriv, "", "", "", ErrSpecificAccessDenied, false, "", false, false},
 			},
 		},
 		{
 			sql: "SET GLOBAL wait_timeout=12345",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "SYSTEM_VARIABLES_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "SYSTEM_VARIABLES_ADMIN", false, false},
 			},
 		},
 		{
 			sql: "BACKUP DATABASE test TO 'local:///tmp/a'",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", false, false},
 			},
 		},
 		{
 			sql: "RESTORE DATABASE test FROM 'local:///tmp/a'",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", false, false},
 			},
 		},
 		{
 			sql: "SHOW BACKUPS",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", false, false},
 			},
 		},
 		{
 			sql: "SHOW RESTORES",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", false, false},
 			},
 		},
 		{
 			sql: "GRANT rolename TO user1",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "ROLE_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "ROLE_ADMIN", false, false},
 			},
 		},
 		{
 			sql: "REVOKE rolename FROM user1",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "ROLE_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "ROLE_ADMIN", false, false},
 			},
 		},
 		{
 			sql: "GRANT BACKUP_ADMIN ON *.* TO user1",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", true},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", true, false},
 			},
 		},
 		{
 			sql: "GRANT BACKUP_ADMIN ON *.* TO user1 WITH GRANT OPTION",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", true},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", true, false},
 			},
 		},
 		{
 			sql: "RENAME USER user1 to user1_tmp",
 			ans: []visitInfo{
-				{mysql.CreateUserPriv, "", "", "", ErrSpecificAccessDenied, false, "", false},
+				{mysql.CreateUserPriv, "", "", "", ErrSpecificAccessDenied, false, "", false, false},
 			},
 		},
 	}
@@ -1326,20 +1372,19 @@ func (v visitInfoArray) Len() int {
 }
 
 func (v visitInfoArray) Less(i, j int) bool {
-	if v[i].privilege < v[j].privilege {
-		return true
+	if v[i].privilege != v[j].privilege {
+		return v[i].privilege < v[j].privilege
 	}
-	if v[i].db < v[j].db {
-		return true
+	if v[i].db != v[j].db {
+		return v[i].db < v[j].db
 	}
-	if v[i].table < v[j].table {
-		return true
+	if v[i].table != v[j].table {
+		return v[i].table < v[j].table
 	}
-	if v[i].column < v[j].column {
-		return true
+	if v[i].column != v[j].column {
+		return v[i].column < v[j].column
 	}
-
-	return false
+	return !v[i].anyColumn && v[j].anyColumn
 }
 
 func (v visitInfoArray) Swap(i, j int) {
@@ -1959,3 +2004,67 @@ func (s *testPlanSuite) TestWindowLogicalPlanAmbiguous(c *C) {
 		}
 	}
 }
+
+func (s *testPlanSuite) TestJSONTable(c *C) {
+	defer testleak.AfterTest(c)()
+	ctx := context.Background()
+	tests := []struct {
+		sql     string
+		logical string
+		best    string
+	}{
+		{
+			sql:     "select * from json_table('[1, 2]', '$[*]' columns (id for ordinality, nested path '$' columns (v int path '$'))) as jt",
+			logical: "JSONTable->Projection",
+			best:    "JSONTable->Projection",
+		},
+		{
+			sql:     "select * from t, json_table(json_array(t.a, t.b), '$[*]' columns (id for ordinality, v int path '$')) as jt",
+			logical: "Apply{DataScan(t)->JSONTable}->Projection",
+			best:    "Apply{TableReader(Table(t))->JSONTable}->Projection",
+		},
+		{
+			// The uncorrelated JSON_TABLE is decorrelated into a join.
+			sql:     "select * from t join json_table('[1, 2]', '$[*]' columns (v int path '$')) as jt on t.a = jt.v",
+			logical: "Join{JSONTable->Sel([not(isnull(jt.v))])->DataScan(t)}(jt.v,test.t.a)->Projection->Projection",
+			best:    "IndexJoin{JSONTable->Sel([not(isnull(jt.v))])->TableReader(Table(t))}(jt.v,test.t.a)->Projection->Projection",
+		},
+		{
+			sql:     "select * from t left join json_table(json_array(t.a), '$[*]' columns (v int path '$')) as jt on jt.v > 1",
+			logical: "Apply{DataScan(t)->JSONTable->Sel([gt(jt.v, 1)])}->Projection",
+			best:    "Apply{TableReader(Table(t))->JSONTable->Sel([gt(jt.v, 1)])}->Projection",
+		},
+	}
+	for _, tt := range tests {
+		comment := Commentf("for %s", tt.sql)
+		stmt, err := s.ParseOneStmt(tt.sql, "", "")
+		c.Assert(err, IsNil, comment)
+		c.Assert(Preprocess(s.ctx, stmt, s.is), IsNil, comment)
+		builder, _ := NewPlanBuilder(MockContext(), s.is, &hint.BlockHintProcessor{})
+		p, err := builder.Build(ctx, stmt)
+		c.Assert(err, IsNil, comment)
+		p, err = logicalOptimize(ctx, builder.optFlag, p.(LogicalPlan))
+		c.Assert(err, IsNil, comment)
+		c.Assert(ToString(p), Equals, tt.logical, comment)
+		best, _, err := physicalOptimize(p.(LogicalPlan), &PlanCounterDisabled)
+		c.Assert(err, IsNil, comment)
+		c.Assert(ToString(best), Equals, tt.best, comment)
+	}
+
+	errTests := []struct {
+		sql string
+		err *terror.Error
+	}{
+		{"select * from json_table('[1]', '$' columns (v int path '$', V int path '$')) as jt", ErrDupFieldName},
+		{"select * from t right join json_table(json_array(t.a), '$' columns (v int path '$')) as jt on true", ErrNotSupportedYet},
+		{"select * from t natural join json_table('[1]', '$' columns (a int path '$')) as jt", ErrNotSupportedYet},
+	}
+	for _, tt := range errTests {
+		comment := Commentf("for %s", tt.sql)
+		stmt, err := s.ParseOneStmt(tt.sql, "", "")
+		c.Assert(err, IsNil, comment)
+		builder, _ := NewPlanBuilder(MockContext(), s.is, &hint.BlockHintProcessor{})
+		_, err = builder.Build(ctx, stmt)
+		c.Assert(tt.err.Equal(err), IsTrue, comment)
+	}
+}
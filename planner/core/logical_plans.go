@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"go.uber.org/zap"
@@ -50,6 +51,7 @@ var (
 	_ LogicalPlan = &LogicalLock{}
 	_ LogicalPlan = &LogicalLimit{}
 	_ LogicalPlan = &LogicalWindow{}
+	_ LogicalPlan = &LogicalJSONTable{}
 )
 
 // JoinType contains CrossJoin, InnerJoin, LeftOuterJoin, RightOuterJoin, FullOuterJoin, SemiJoin.
@@ -436,6 +438,75 @@ type LogicalTableDual struct {
 	RowCount int
 }
 
+// JSONTableColumnType is the type of a column of JSON_TABLE.
+type JSONTableColumnType byte
+
+const (
+	// JSONTableColumnPath is a column whose value is extracted by a path, e.g. `col INT PATH '$.a'`.
+	JSONTableColumnPath JSONTableColumnType = iota
+	// JSONTableColumnOrdinality is a `col FOR ORDINALITY` column which enumerates the rows.
+	JSONTableColumnOrdinality
+	// JSONTableColumnExistsPath is a `col INT EXISTS PATH '$.a'` column which is 1 if the path matches anything.
+	JSONTableColumnExistsPath
+	// JSONTableColumnNested is a `NESTED PATH '$.a' COLUMNS (...)` clause which has its own columns.
+	JSONTableColumnNested
+)
+
+// JSONTableOnResponseType is the action of the ON EMPTY and ON ERROR clauses of JSON_TABLE.
+type JSONTableOnResponseType byte
+
+const (
+	// JSONTableOnResponseNull is `NULL ON EMPTY/ERROR`, it's the default action.
+	JSONTableOnResponseNull JSONTableOnResponseType = iota
+	// JSONTableOnResponseError is `ERROR ON EMPTY/ERROR`.
+	JSONTableOnResponseError
+	// JSONTableOnResponseDefault is `DEFAULT 'json_string' ON EMPTY/ERROR`.
+	JSONTableOnResponseDefault
+)
+
+// JSONTableOnResponse is the ON EMPTY or ON ERROR clause of a column of JSON_TABLE.
+type JSONTableOnResponse struct {
+	Tp JSONTableOnResponseType
+	// Default is the value used by JSONTableOnResponseDefault.
+	Default json.BinaryJSON
+}
+
+// JSONTableColumn is a column or a NESTED PATH clause of JSON_TABLE.
+type JSONTableColumn struct {
+	Tp        JSONTableColumnType
+	Name      model.CIStr
+	FieldType types.FieldType
+	// Path is the path of the PATH, EXISTS PATH and NESTED PATH columns.
+	Path json.PathExpression
+	// OnEmpty and OnError are only used by the PATH columns.
+	OnEmpty JSONTableOnResponse
+	OnError JSONTableOnResponse
+	// Columns are the columns of the NESTED PATH clause.
+	Columns []*JSONTableColumn
+}
+
+// JSONTableInfo describes `JSON_TABLE(expr, path COLUMNS (...)) AS alias`.
+type JSONTableInfo struct {
+	Name    model.CIStr
+	Path    json.PathExpression
+	Columns []*JSONTableColumn
+}
+
+// LogicalJSONTable is the logical operator of the JSON_TABLE table function, it turns the JSON document
+// evaluated by Expr into rows. Expr may refer to the columns of the outer plan, then the JSON_TABLE
+// is the inner side of an Apply.
+type LogicalJSONTable struct {
+	logicalSchemaProducer
+
+	Expr expression.Expression
+	Info *JSONTableInfo
+}
+
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalJSONTable) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	return expression.ExtractCorColumns(p.Expr)
+}
+
 // LogicalMemTable represents a memory table or virtual table
 // Some memory tables wants to take the ownership of some predications
 // e.g
@@ -493,6 +564,9 @@ type DataSource struct {
 
 	// possibleAccessPaths stores all the possible access path for physical plan, including table scan.
 	possibleAccessPaths []*util.AccessPath
+	// mvIndexPaths stores the paths of the multi-valued indexes, which are turned into the possible access paths by
+	// the conditions on their arrays when deriving the stats.
+	mvIndexPaths []*util.AccessPath
 
 	// The data source may be a partition, rather than a real table.
 	isPartition     bool
@@ -849,6 +923,10 @@ func (ds *DataSource) fillIndexPath(path *util.AccessPath, conds []expression.Ex
 		path.EqCondCount = res.EqCondCount
 		path.EqOrInCondCount = res.EqOrInCount
 		path.IsDNFCond = res.IsDNFCond
+		if path.IsHypothetical {
+			path.CountAfterAccess = ds.getHypoIndexRowCount(path.AccessConds)
+			return nil
+		}
 		path.CountAfterAccess, err = ds.tableStats.HistColl.GetRowCountByIndexRanges(sc, path.Index.ID, path.Ranges)
 		if err != nil {
 			return err
@@ -859,6 +937,20 @@ func (ds *DataSource) fillIndexPath(path *util.AccessPath, conds []expression.Ex
 	return nil
 }
 
+// getHypoIndexRowCount estimates the row count after accessing a hypothetical index. There are no statistics
+// for the hypothetical index, so the access conditions are estimated by the statistics of the columns.
+func (ds *DataSource) getHypoIndexRowCount(accessConds []expression.Expression) float64 {
+	if len(accessConds) == 0 {
+		return float64(ds.statisticTable.Count)
+	}
+	selectivity, _, err := ds.tableStats.HistColl.Selectivity(ds.ctx, accessConds, nil)
+	if err != nil {
+		logutil.BgLogger().Debug("calculate selectivity failed, use selection factor", zap.Error(err))
+		selectivity = SelectionFactor
+	}
+	return selectivity * float64(ds.statisticTable.Count)
+}
+
 // deriveIndexPathStats will fulfill the information that the AccessPath need.
 // And it will check whether this index is full matched by point query. We will use this check to
 // determine whether we remove other paths or not.
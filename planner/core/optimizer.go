@@ -107,6 +107,13 @@ func CheckPrivilege(activeRoles []*auth.RoleIdentity, pm privilege.Manager, vs [
 				}
 				return v.err
 			}
+		} else if v.anyColumn {
+			if !pm.RequestAnyColumnVerification(activeRoles, v.db, v.table, v.privilege) {
+				if v.err == nil {
+					return ErrPrivilegeCheckFail.GenWithStackByArgs(v.privilege.String())
+				}
+				return v.err
+			}
 		} else if !pm.RequestVerification(activeRoles, v.db, v.table, v.column, v.privilege) {
 			if v.err == nil {
 				return ErrPrivilegeCheckFail.GenWithStackByArgs(v.privilege.String())
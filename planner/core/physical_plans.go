@@ -1203,6 +1203,19 @@ func (p *PhysicalTableDual) SetOutputNames(names types.NameSlice) {
 	p.names = names
 }
 
+// PhysicalJSONTable is the physical operator of the JSON_TABLE table function.
+type PhysicalJSONTable struct {
+	physicalSchemaProducer
+
+	Expr expression.Expression
+	Info *JSONTableInfo
+}
+
+// ExtractCorrelatedCols implements PhysicalPlan interface.
+func (p *PhysicalJSONTable) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	return expression.ExtractCorColumns(p.Expr)
+}
+
 // PhysicalWindow is the physical operator of window function.
 type PhysicalWindow struct {
 	physicalSchemaProducer
@@ -1346,16 +1359,29 @@ type TableSampleInfo struct {
 	AstNode    *ast.TableSample
 	FullSchema *expression.Schema
 	Partitions []table.PartitionedTable
+	// Percent is the sampling percentage of the SYSTEM and BERNOULLI sampling methods.
+	Percent float64
+	// Seed is the seed specified by REPEATABLE, it's only valid when Repeatable is true.
+	Seed       int64
+	Repeatable bool
 }
 
 // NewTableSampleInfo creates a new TableSampleInfo.
-func NewTableSampleInfo(node *ast.TableSample, fullSchema *expression.Schema, pt []table.PartitionedTable) *TableSampleInfo {
+func NewTableSampleInfo(node *ast.TableSample, fullSchema *expression.Schema, pt []table.PartitionedTable) (*TableSampleInfo, error) {
 	if node == nil {
-		return nil
+		return nil, nil
 	}
-	return &TableSampleInfo{
+	info := &TableSampleInfo{
 		AstNode:    node,
 		FullSchema: fullSchema,
 		Partitions: pt,
 	}
+	if node.SampleMethod == ast.SampleMethodTypeSystem || node.SampleMethod == ast.SampleMethodTypeBernoulli {
+		var err error
+		info.Percent, info.Seed, info.Repeatable, err = extractTableSampleArgs(node)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return info, nil
 }
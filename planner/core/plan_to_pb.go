@@ -306,7 +306,13 @@ func (p *PhysicalIndexScan) ToPB(ctx sessionctx.Context, _ kv.StoreType) (*tipb.
 		} else if col.ID == model.ExtraPidColID {
 			columns = append(columns, model.NewExtraPartitionIDColInfo())
 		} else {
-			columns = append(columns, findColumnInfoByID(tableColumns, col.ID))
+			colInfo := findColumnInfoByID(tableColumns, col.ID)
+			if colInfo.FieldType.ArrayElem != nil {
+				// The entries of the multi-valued index store the array elements.
+				colInfo = colInfo.Clone()
+				colInfo.FieldType = *colInfo.FieldType.ArrayElem.Clone()
+			}
+			columns = append(columns, colInfo)
 		}
 	}
 	var pkColIds []int64
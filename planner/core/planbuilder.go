@@ -71,6 +71,9 @@ type visitInfo struct {
 	alterWritable    bool
 	dynamicPriv      string
 	dynamicWithGrant bool
+	// anyColumn indicates the privilege on any column of the table is enough, the columns referenced are checked
+	// separately by the column-level visitInfo.
+	anyColumn bool
 }
 
 type indexNestedLoopJoinTables struct {
@@ -421,7 +424,13 @@ type PlanBuilder struct {
 	// colMapper stores the column that must be pre-resolved.
 	colMapper map[*ast.ColumnNameExpr]int
 	// visitInfo is used for privilege check.
-	visitInfo     []visitInfo
+	visitInfo []visitInfo
+	// columnVisitInfo maps the UniqueID of the columns of the tables and views to their column-level SELECT
+	// privilege check, which is appended to visitInfo once the column is referenced.
+	columnVisitInfo map[int64]visitInfo
+	// inRowPolicy indicates that the predicates of the row-level security policies are being built,
+	// the policies of the tables referenced by the predicates are not applied.
+	inRowPolicy   bool
 	tableHintInfo []tableHintInfo
 	// optFlag indicates the flags of the optimizer rules.
 	optFlag uint64
@@ -477,6 +486,10 @@ type PlanBuilder struct {
 	// 1. use `inside insert`, `update`, `delete` or `select for update` statement
 	// 2. isolation level is RC
 	isForUpdateRead bool
+
+	// allowBuildCastArray indicates whether `CAST(... AS ... ARRAY)` can be built, which is only allowed in the
+	// expressions of multi-valued indexes.
+	allowBuildCastArray bool
 }
 
 type handleColHelper struct {
@@ -643,7 +656,7 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 		*ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, *ast.CreateUserStmt, *ast.SetPwdStmt, *ast.AlterInstanceStmt,
 		*ast.GrantStmt, *ast.DropUserStmt, *ast.AlterUserStmt, *ast.RevokeStmt, *ast.KillStmt, *ast.DropStatsStmt,
 		*ast.GrantRoleStmt, *ast.RevokeRoleStmt, *ast.SetRoleStmt, *ast.SetDefaultRoleStmt, *ast.ShutdownStmt,
-		*ast.RenameUserStmt:
+		*ast.RenameUserStmt, *ast.SavepointStmt, *ast.ReleaseSavepointStmt:
 		return b.buildSimple(ctx, node.(ast.StmtNode))
 	case ast.DDLNode:
 		return b.buildDDL(ctx, x)
@@ -978,6 +991,9 @@ func getPossibleAccessPaths(ctx sessionctx.Context, tableHints *tableHintInfo, i
 			publicPaths = append(publicPaths, &util.AccessPath{Index: index})
 		}
 	}
+	for _, index := range ctx.GetSessionVars().HypoIndexes[tblInfo.ID] {
+		publicPaths = append(publicPaths, &util.AccessPath{Index: index, IsHypothetical: true})
+	}
 
 	hasScanHint, hasUseOrForce := false, false
 	available := make([]*util.AccessPath, 0, len(publicPaths))
@@ -1103,6 +1119,26 @@ func removeIgnoredPaths(paths, ignoredPaths []*util.AccessPath, tblInfo *model.T
 	return remainedPaths
 }
 
+// splitMVIndexPaths splits the paths of the multi-valued indexes from the access paths. The multi-valued index is
+// only accessed by the conditions on its array, so its paths are built from the conditions in DeriveStats, and the
+// table path is added if there is no other path, like the multi-valued index is forced by hints.
+func splitMVIndexPaths(paths []*util.AccessPath, tblInfo *model.TableInfo) (regularPaths, mvIndexPaths []*util.AccessPath) {
+	regularPaths = make([]*util.AccessPath, 0, len(paths))
+	for _, path := range paths {
+		if !path.IsTablePath() && path.Index.MVIndex {
+			mvIndexPaths = append(mvIndexPaths, path)
+			continue
+		}
+		regularPaths = append(regularPaths, path)
+	}
+	if len(regularPaths) == 0 {
+		tablePath := &util.AccessPath{StoreType: kv.TiKV}
+		fillContentForTablePath(tablePath, tblInfo)
+		regularPaths = append(regularPaths, tablePath)
+	}
+	return regularPaths, mvIndexPaths
+}
+
 func (b *PlanBuilder) buildSelectLock(src LogicalPlan, lock *ast.SelectLockInfo) *LogicalLock {
 	selectLock := LogicalLock{
 		Lock:             lock,
@@ -1134,6 +1170,16 @@ func (b *PlanBuilder) buildPrepare(x *ast.PrepareStmt) Plan {
 	return p
 }
 
+// checkNotMVIndex checks that the index of the admin statement isn't a multi-valued index, whose entries don't
+// match the rows one by one.
+func checkNotMVIndex(as *ast.AdminStmt, stmt string) error {
+	idx := as.Tables[0].TableInfo.FindIndexByName(strings.ToLower(as.Index))
+	if idx != nil && idx.MVIndex {
+		return ErrNotSupportedYet.GenWithStackByArgs(stmt + " on multi-valued index")
+	}
+	return nil
+}
+
 func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan, error) {
 	var ret Plan
 	var err error
@@ -1144,10 +1190,16 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan,
 			return ret, err
 		}
 	case ast.AdminRecoverIndex:
+		if err = checkNotMVIndex(as, "ADMIN RECOVER INDEX"); err != nil {
+			return nil, err
+		}
 		p := &RecoverIndex{Table: as.Tables[0], IndexName: as.Index}
 		p.setSchemaAndNames(buildRecoverIndexFields())
 		ret = p
 	case ast.AdminCleanupIndex:
+		if err = checkNotMVIndex(as, "ADMIN CLEANUP INDEX"); err != nil {
+			return nil, err
+		}
 		p := &CleanupIndex{Table: as.Tables[0], IndexName: as.Index}
 		p.setSchemaAndNames(buildCleanupIndexFields())
 		ret = p
@@ -1411,6 +1463,10 @@ func (b *PlanBuilder) buildPhysicalIndexLookUpReaders(ctx context.Context, dbNam
 			// Skip checking clustered index.
 			continue
 		}
+		if idxInfo.MVIndex {
+			// Skip checking multi-valued index, whose entries don't match the rows one by one.
+			continue
+		}
 		if idxInfo.State != model.StatePublic {
 			logutil.Logger(ctx).Info("build physical index lookup reader, the index isn't public",
 				zap.String("index", idxInfo.Name.O),
@@ -1494,6 +1550,9 @@ func (b *PlanBuilder) buildAdminCheckTable(ctx context.Context, as *ast.AdminStm
 		if idx.Meta().State != model.StatePublic {
 			return nil, errors.Errorf("index %s state %s isn't public", as.Index, idx.Meta().State)
 		}
+		if err = checkNotMVIndex(as, "ADMIN CHECK INDEX"); err != nil {
+			return nil, err
+		}
 		p.CheckIndex = true
 		readerPlans, indexInfos, err = b.buildPhysicalIndexLookUpReaders(ctx, tblName.Schema, tbl, []table.Index{idx})
 	} else {
@@ -1564,7 +1623,8 @@ func getColsInfo(tn *ast.TableName) (indicesInfo []*model.IndexInfo, colsInfo []
 		colsInfo = append(colsInfo, col)
 	}
 	for _, idx := range tn.TableInfo.Indices {
-		if idx.State == model.StatePublic {
+		// The entries of the multi-valued index are the array elements, which aren't analyzed yet.
+		if idx.State == model.StatePublic && !idx.MVIndex {
 			indicesInfo = append(indicesInfo, idx)
 		}
 	}
@@ -1651,7 +1711,7 @@ func (b *PlanBuilder) buildAnalyzeFullSamplingTask(
 ) []AnalyzeColumnsTask {
 	idxInfos := make([]*model.IndexInfo, 0, len(tbl.TableInfo.Indices))
 	for _, idx := range tbl.TableInfo.Indices {
-		if idx.State != model.StatePublic {
+		if idx.State != model.StatePublic || idx.MVIndex {
 			continue
 		}
 		idxInfos = append(idxInfos, idx)
@@ -1816,6 +1876,10 @@ func (b *PlanBuilder) buildAnalyzeIndex(as *ast.AnalyzeTableStmt, opts map[ast.A
 		if idx == nil || idx.State != model.StatePublic {
 			return nil, ErrAnalyzeMissIndex.GenWithStackByArgs(idxName.O, tblInfo.Name.O)
 		}
+		if idx.MVIndex {
+			b.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrNotSupportedYet.GenWithStackByArgs("ANALYZE INDEX on multi-valued index"))
+			continue
+		}
 		for i, id := range physicalIDs {
 			if id == tblInfo.ID {
 				id = -1
@@ -1857,7 +1921,7 @@ func (b *PlanBuilder) buildAnalyzeAllIndex(as *ast.AnalyzeTableStmt, opts map[as
 		return b.buildAnalyzeTable(as, opts, version)
 	}
 	for _, idx := range tblInfo.Indices {
-		if idx.State == model.StatePublic {
+		if idx.State == model.StatePublic && !idx.MVIndex {
 			for i, id := range physicalIDs {
 				if id == tblInfo.ID {
 					id = -1
@@ -2531,7 +2595,9 @@ func (b *PlanBuilder) resolveGeneratedColumns(ctx context.Context, columns []*ta
 		}
 		colExpr := mockPlan.Schema().Columns[idx]
 
+		b.allowBuildCastArray = true
 		expr, _, err := b.rewrite(ctx, column.GeneratedExpr, mockPlan, nil, true)
+		b.allowBuildCastArray = false
 		if err != nil {
 			return igc, err
 		}
@@ -2616,8 +2682,18 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		authErr = ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, tableInfo.Name.L)
 	}
 
-	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, tn.DBInfo.Name.L,
-		tableInfo.Name.L, "", authErr)
+	// The INSERT privilege on the columns is enough if the columns to insert are specified.
+	insertCols := insert.Columns
+	for _, assign := range insert.Setlist {
+		insertCols = append(insertCols, assign.Column)
+	}
+	if len(insertCols) > 0 {
+		b.visitInfo = appendAnyColumnVisitInfo(b.visitInfo, mysql.InsertPriv, tn.DBInfo.Name.L, tableInfo.Name.L, authErr)
+		b.appendColumnVisitInfo(mysql.InsertPriv, tn.DBInfo.Name.L, tableInfo.Name.L, insertCols)
+	} else {
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, tn.DBInfo.Name.L,
+			tableInfo.Name.L, "", authErr)
+	}
 
 	// `REPLACE INTO` requires both INSERT + DELETE privilege
 	// `ON DUPLICATE KEY UPDATE` requires both INSERT + UPDATE privilege
@@ -2632,7 +2708,17 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 			cmd := strings.ToUpper(mysql.Priv2Str[extraPriv])
 			authErr = ErrTableaccessDenied.GenWithStackByArgs(cmd, user.AuthUsername, user.AuthHostname, tableInfo.Name.L)
 		}
-		b.visitInfo = appendVisitInfo(b.visitInfo, extraPriv, tn.DBInfo.Name.L, tableInfo.Name.L, "", authErr)
+		if extraPriv == mysql.UpdatePriv {
+			// The UPDATE privilege on the columns assigned is enough.
+			b.visitInfo = appendAnyColumnVisitInfo(b.visitInfo, extraPriv, tn.DBInfo.Name.L, tableInfo.Name.L, authErr)
+			updateCols := make([]*ast.ColumnName, 0, len(insert.OnDuplicate))
+			for _, assign := range insert.OnDuplicate {
+				updateCols = append(updateCols, assign.Column)
+			}
+			b.appendColumnVisitInfo(extraPriv, tn.DBInfo.Name.L, tableInfo.Name.L, updateCols)
+		} else {
+			b.visitInfo = appendVisitInfo(b.visitInfo, extraPriv, tn.DBInfo.Name.L, tableInfo.Name.L, "", authErr)
+		}
 	}
 
 	mockTablePlan := LogicalTableDual{}.Init(b.ctx, b.getSelectOffset())
@@ -2692,6 +2778,38 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 	return insertPlan, err
 }
 
+// appendColumnVisitInfo appends the column-level privilege checks of the columns written by the statement.
+func (b *PlanBuilder) appendColumnVisitInfo(priv mysql.PrivilegeType, db, tbl string, cols []*ast.ColumnName) {
+	user := b.ctx.GetSessionVars().User
+	cmd := strings.ToUpper(mysql.Priv2Str[priv])
+	for _, col := range cols {
+		var authErr error
+		if user != nil {
+			authErr = ErrColumnaccessDenied.GenWithStackByArgs(cmd, user.AuthUsername, user.AuthHostname, col.Name.O, tbl)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, priv, db, tbl, col.Name.L, authErr)
+	}
+}
+
+// appendReferencesVisitInfo appends the REFERENCES privilege checks of the columns referenced by the foreign key.
+func (b *PlanBuilder) appendReferencesVisitInfo(constraint *ast.Constraint) {
+	if constraint == nil || constraint.Tp != ast.ConstraintForeignKey || constraint.Refer == nil {
+		return
+	}
+	refer := constraint.Refer
+	db := refer.Table.Schema.L
+	if db == "" {
+		db = b.ctx.GetSessionVars().CurrentDB
+	}
+	cols := make([]*ast.ColumnName, 0, len(refer.IndexPartSpecifications))
+	for _, spec := range refer.IndexPartSpecifications {
+		if spec.Column != nil {
+			cols = append(cols, spec.Column)
+		}
+	}
+	b.appendColumnVisitInfo(mysql.ReferencesPriv, db, refer.Table.Name.L, cols)
+}
+
 func (p *Insert) resolveOnDuplicate(onDup []*ast.Assignment, tblInfo *model.TableInfo, yield func(ast.ExprNode) (expression.Expression, error)) (map[string]struct{}, error) {
 	onDupColSet := make(map[string]struct{}, len(onDup))
 	colMap := make(map[string]*table.Column, len(p.Table.Cols()))
@@ -3105,11 +3223,12 @@ func (b *PlanBuilder) buildLoadStats(ld *ast.LoadStatsStmt) Plan {
 
 func (b *PlanBuilder) buildIndexAdvise(node *ast.IndexAdviseStmt) Plan {
 	p := &IndexAdvise{
-		IsLocal:     node.IsLocal,
-		Path:        node.Path,
-		MaxMinutes:  node.MaxMinutes,
-		MaxIndexNum: node.MaxIndexNum,
-		LinesInfo:   node.LinesInfo,
+		IsLocal:         node.IsLocal,
+		Path:            node.Path,
+		FromStmtSummary: node.FromStmtSummary,
+		MaxMinutes:      node.MaxMinutes,
+		MaxIndexNum:     node.MaxIndexNum,
+		LinesInfo:       node.LinesInfo,
 	}
 	return p
 }
@@ -3118,7 +3237,7 @@ func (b *PlanBuilder) buildSplitRegion(node *ast.SplitRegionStmt) (Plan, error)
 	if node.Table.TableInfo.TempTableType != model.TempTableNone {
 		return nil, ErrOptOnTemporaryTable.GenWithStackByArgs("split table")
 	}
-	if node.SplitSyntaxOpt != nil && node.SplitSyntaxOpt.HasPartition && node.Table.TableInfo.Partition == nil {
+	if node.SplitSyntaxOpt != nil && node.SplitSyntaxOpt.HasPartition && node.Table.TableInfo.GetPartitionInfo() == nil {
 		return nil, ErrPartitionClauseOnNonpartitioned
 	}
 	if len(node.IndexName.L) != 0 {
@@ -3397,6 +3516,8 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 				}
 				b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, v.Table.Schema.L,
 					v.Table.Name.L, "", authErr)
+			} else if spec.Tp == ast.AlterTableAddConstraint {
+				b.appendReferencesVisitInfo(spec.Constraint)
 			} else if spec.Tp == ast.AlterTableWriteable {
 				b.visitInfo[0].alterWritable = true
 			} else if spec.Tp == ast.AlterTableAddStatistics {
@@ -3458,6 +3579,9 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, v.ReferTable.Schema.L,
 				v.ReferTable.Name.L, "", authErr)
 		}
+		for _, constraint := range v.Constraints {
+			b.appendReferencesVisitInfo(constraint)
+		}
 	case *ast.CreateViewStmt:
 		b.capFlag |= canExpandAST | renameView
 		b.renamingViewName = v.ViewName.Schema.L + "." + v.ViewName.Name.L
@@ -3509,6 +3633,25 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 		}
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, v.Name,
 			"", "", authErr)
+	case *ast.CreatePolicyStmt:
+		if b.ctx.GetSessionVars().User != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("ALTER", b.ctx.GetSessionVars().User.AuthUsername,
+				b.ctx.GetSessionVars().User.AuthHostname, v.Table.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.AlterPriv, v.Table.Schema.L,
+			v.Table.Name.L, "", authErr)
+		// The users bound by the policies must not be able to change them.
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("ROW_POLICY_ADMIN")
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "ROW_POLICY_ADMIN", false, err)
+	case *ast.DropPolicyStmt:
+		if b.ctx.GetSessionVars().User != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("ALTER", b.ctx.GetSessionVars().User.AuthUsername,
+				b.ctx.GetSessionVars().User.AuthHostname, v.Table.Name.L)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.AlterPriv, v.Table.Schema.L,
+			v.Table.Name.L, "", authErr)
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("ROW_POLICY_ADMIN")
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "ROW_POLICY_ADMIN", false, err)
 	case *ast.DropIndexStmt:
 		if b.ctx.GetSessionVars().User != nil {
 			authErr = ErrTableaccessDenied.GenWithStackByArgs("INDEx", b.ctx.GetSessionVars().User.AuthUsername,
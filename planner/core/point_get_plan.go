@@ -457,7 +457,7 @@ func TryFastPlan(ctx sessionctx.Context, node ast.Node) (p Plan) {
 		// Try to convert the `SELECT a, b, c FROM t WHERE (a, b, c) in ((1, 2, 4), (1, 3, 5))` to
 		// `PhysicalUnionAll` which children are `PointGet` if exists an unique key (a, b, c) in table `t`
 		if fp := tryWhereIn2BatchPointGet(ctx, x); fp != nil {
-			if checkFastPlanPrivilege(ctx, fp.dbName, fp.TblInfo.Name.L, mysql.SelectPriv) != nil {
+			if checkFastPlanPrivilege(ctx, fp.dbName, fp.TblInfo.Name.L, mysql.SelectPriv) != nil || hasRowPolicies(ctx, fp.TblInfo) {
 				return
 			}
 			if tidbutil.IsMemDB(fp.dbName) {
@@ -468,7 +468,7 @@ func TryFastPlan(ctx sessionctx.Context, node ast.Node) (p Plan) {
 			return
 		}
 		if fp := tryPointGetPlan(ctx, x, isForUpdateReadSelectLock(x.LockInfo)); fp != nil {
-			if checkFastPlanPrivilege(ctx, fp.dbName, fp.TblInfo.Name.L, mysql.SelectPriv) != nil {
+			if checkFastPlanPrivilege(ctx, fp.dbName, fp.TblInfo.Name.L, mysql.SelectPriv) != nil || hasRowPolicies(ctx, fp.TblInfo) {
 				return nil
 			}
 			if tidbutil.IsMemDB(fp.dbName) {
@@ -1015,6 +1015,13 @@ func checkFastPlanPrivilege(ctx sessionctx.Context, dbName, tableName string, ch
 	return CheckTableLock(ctx, infoSchema, visitInfos)
 }
 
+// hasRowPolicies checks whether the row-level security policies are applied to the table for the current user.
+// The fast plans can't be used for such tables, because the policies are applied as the filters on DataSource.
+func hasRowPolicies(ctx sessionctx.Context, tblInfo *model.TableInfo) bool {
+	pm := privilege.GetPrivilegeManager(ctx)
+	return pm != nil && len(pm.RowPolicies(ctx.GetSessionVars().ActiveRoles, tblInfo)) > 0
+}
+
 func buildSchemaFromFields(
 	dbName model.CIStr,
 	tbl *model.TableInfo,
@@ -1294,7 +1301,7 @@ func tryUpdatePointPlan(ctx sessionctx.Context, updateStmt *ast.UpdateStmt) Plan
 }
 
 func buildPointUpdatePlan(ctx sessionctx.Context, pointPlan PhysicalPlan, dbName string, tbl *model.TableInfo, updateStmt *ast.UpdateStmt) Plan {
-	if checkFastPlanPrivilege(ctx, dbName, tbl.Name.L, mysql.SelectPriv, mysql.UpdatePriv) != nil {
+	if checkFastPlanPrivilege(ctx, dbName, tbl.Name.L, mysql.SelectPriv, mysql.UpdatePriv) != nil || hasRowPolicies(ctx, tbl) {
 		return nil
 	}
 	orderedList, allAssignmentsAreConstant := buildOrderedList(ctx, pointPlan, updateStmt.List)
@@ -1410,7 +1417,7 @@ func tryDeletePointPlan(ctx sessionctx.Context, delStmt *ast.DeleteStmt) Plan {
 }
 
 func buildPointDeletePlan(ctx sessionctx.Context, pointPlan PhysicalPlan, dbName string, tbl *model.TableInfo) Plan {
-	if checkFastPlanPrivilege(ctx, dbName, tbl.Name.L, mysql.SelectPriv, mysql.DeletePriv) != nil {
+	if checkFastPlanPrivilege(ctx, dbName, tbl.Name.L, mysql.SelectPriv, mysql.DeletePriv) != nil || hasRowPolicies(ctx, tbl) {
 		return nil
 	}
 	handleCols := buildHandleCols(ctx, tbl, pointPlan.Schema())
@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	"github.com/pingcap/tidb/util"
@@ -156,6 +157,10 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 	case *ast.CreateIndexStmt:
 		p.stmtTp = TypeCreate
 		p.checkCreateIndexGrammar(node)
+	case *ast.CreatePolicyStmt:
+		p.stmtTp = TypeCreate
+	case *ast.DropPolicyStmt:
+		p.stmtTp = TypeDrop
 	case *ast.AlterTableStmt:
 		p.stmtTp = TypeAlter
 		p.resolveAlterTableStmt(node)
@@ -229,8 +234,10 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 		if v, ok := node.Source.(*ast.TableName); ok && v.TableSample != nil {
 			switch v.TableSample.SampleMethod {
 			case ast.SampleMethodTypeTiDBRegion:
+			case ast.SampleMethodTypeSystem, ast.SampleMethodTypeBernoulli:
+				_, _, _, p.err = extractTableSampleArgs(v.TableSample)
 			default:
-				p.err = expression.ErrInvalidTableSample.GenWithStackByArgs("Only supports REGIONS sampling method")
+				p.err = expression.ErrInvalidTableSample.GenWithStackByArgs("Only supports REGIONS, SYSTEM and BERNOULLI sampling method")
 			}
 		}
 	case *ast.GroupByClause:
@@ -241,6 +248,48 @@ func (p *preprocessor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
 	return in, p.err != nil
 }
 
+// extractTableSampleArgs extracts the sampling percentage and the REPEATABLE seed
+// of the SYSTEM and BERNOULLI sampling methods.
+func extractTableSampleArgs(ts *ast.TableSample) (percent float64, seed int64, repeatable bool, err error) {
+	if ts.Expr == nil {
+		return 0, 0, false, expression.ErrInvalidTableSample.GenWithStackByArgs("The sampling percentage is required")
+	}
+	if ts.SampleClauseUnit == ast.SampleClauseUnitTypeRow {
+		return 0, 0, false, expression.ErrInvalidTableSample.GenWithStackByArgs("Only supports PERCENT unit for SYSTEM and BERNOULLI sampling method")
+	}
+	percentErr := expression.ErrInvalidTableSample.GenWithStackByArgs("The sampling percentage must be a number between 0 and 100")
+	v, ok := ts.Expr.(*driver.ValueExpr)
+	if !ok {
+		return 0, 0, false, percentErr
+	}
+	switch v.Kind() {
+	case types.KindInt64, types.KindUint64, types.KindFloat32, types.KindFloat64, types.KindMysqlDecimal:
+		percent, err = v.ToFloat64(&stmtctx.StatementContext{})
+		if err != nil || percent < 0 || percent > 100 {
+			return 0, 0, false, percentErr
+		}
+	default:
+		return 0, 0, false, percentErr
+	}
+	if ts.RepeatableSeed == nil {
+		return percent, 0, false, nil
+	}
+	seedErr := expression.ErrInvalidTableSample.GenWithStackByArgs("The REPEATABLE seed must be an integer")
+	v, ok = ts.RepeatableSeed.(*driver.ValueExpr)
+	if !ok {
+		return 0, 0, false, seedErr
+	}
+	switch v.Kind() {
+	case types.KindInt64:
+		seed = v.GetInt64()
+	case types.KindUint64:
+		seed = int64(v.GetUint64())
+	default:
+		return 0, 0, false, seedErr
+	}
+	return percent, seed, true, nil
+}
+
 // EraseLastSemicolon removes last semicolon of sql.
 func EraseLastSemicolon(stmt ast.StmtNode) {
 	sql := stmt.Text()
@@ -605,11 +654,6 @@ func (p *preprocessor) checkCreateTableGrammar(stmt *ast.CreateTableStmt) {
 		p.err = ddl.ErrWrongTableName.GenWithStackByArgs(tName)
 		return
 	}
-	enableNoopFuncs := p.ctx.GetSessionVars().EnableNoopFuncs
-	if stmt.TemporaryKeyword == ast.TemporaryLocal && !enableNoopFuncs {
-		p.err = expression.ErrFunctionsNoopImpl.GenWithStackByArgs("CREATE TEMPORARY TABLE")
-		return
-	}
 	countPrimaryKey := 0
 	for _, colDef := range stmt.Cols {
 		if err := checkColumn(colDef); err != nil {
@@ -717,11 +761,6 @@ func (p *preprocessor) checkDropSequenceGrammar(stmt *ast.DropSequenceStmt) {
 
 func (p *preprocessor) checkDropTableGrammar(stmt *ast.DropTableStmt) {
 	p.checkDropTableNames(stmt.Tables)
-	enableNoopFuncs := p.ctx.GetSessionVars().EnableNoopFuncs
-	if stmt.TemporaryKeyword == ast.TemporaryLocal && !enableNoopFuncs {
-		p.err = expression.ErrFunctionsNoopImpl.GenWithStackByArgs("DROP TEMPORARY TABLE")
-		return
-	}
 }
 
 func (p *preprocessor) checkDropTableNames(tables []*ast.TableName) {
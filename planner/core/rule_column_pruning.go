@@ -277,6 +277,12 @@ func (p *LogicalTableDual) PruneColumns(parentUsedCols []*expression.Column) err
 	return nil
 }
 
+// PruneColumns implements LogicalPlan interface.
+// The columns of JSON_TABLE are generated together, so all of them are kept.
+func (p *LogicalJSONTable) PruneColumns(parentUsedCols []*expression.Column) error {
+	return nil
+}
+
 func (p *LogicalJoin) extractUsedCols(parentUsedCols []*expression.Column) (leftCols []*expression.Column, rightCols []*expression.Column) {
 	for _, eqCond := range p.EqualConditions {
 		parentUsedCols = append(parentUsedCols, expression.ExtractColumns(eqCond)...)
@@ -1261,7 +1261,7 @@ func (s *partitionProcessor) resolveAccessPaths(ds *DataSource) error {
 	if err != nil {
 		return err
 	}
-	ds.possibleAccessPaths = possiblePaths
+	ds.possibleAccessPaths, ds.mvIndexPaths = splitMVIndexPaths(possiblePaths, ds.tableInfo)
 	return nil
 }
 
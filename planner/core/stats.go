@@ -19,12 +19,16 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"go.uber.org/zap"
@@ -50,6 +54,25 @@ func (p *LogicalTableDual) DeriveStats(childStats []*property.StatsInfo, selfSch
 	return p.stats, nil
 }
 
+// jsonTableRowCount is the estimated row count of a JSON_TABLE since we know nothing about the JSON document.
+const jsonTableRowCount = 100
+
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalJSONTable) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
+	if p.stats != nil {
+		return p.stats, nil
+	}
+	profile := &property.StatsInfo{
+		RowCount:    jsonTableRowCount,
+		Cardinality: make(map[int64]float64, selfSchema.Len()),
+	}
+	for _, col := range selfSchema.Columns {
+		profile.Cardinality[col.UniqueID] = jsonTableRowCount
+	}
+	p.stats = profile
+	return p.stats, nil
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (p *LogicalMemTable) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
 	if p.stats != nil {
@@ -275,6 +298,7 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 		}
 	}
 	ds.stats = ds.deriveStatsByFilter(ds.pushedDownConds, ds.possibleAccessPaths)
+	pruned := false
 	for _, path := range ds.possibleAccessPaths {
 		if path.IsTablePath() {
 			noIntervalRanges, err := ds.deriveTablePathStats(path, ds.pushedDownConds, false)
@@ -286,6 +310,7 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 				ds.possibleAccessPaths[0] = path
 				ds.possibleAccessPaths = ds.possibleAccessPaths[:1]
 				ds.ctx.GetSessionVars().StmtCtx.OptimDependOnMutableConst = true
+				pruned = true
 				break
 			}
 			continue
@@ -296,6 +321,7 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 			ds.possibleAccessPaths[0] = path
 			ds.possibleAccessPaths = ds.possibleAccessPaths[:1]
 			ds.ctx.GetSessionVars().StmtCtx.OptimDependOnMutableConst = true
+			pruned = true
 			break
 		}
 	}
@@ -331,6 +357,12 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 		ds.indexMergeHints = nil
 		ds.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("IndexMerge is inapplicable or disabled"))
 	}
+	if len(ds.mvIndexPaths) > 0 && !pruned {
+		err := ds.generateMVIndexPaths(isReadOnlyTxn)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return ds.stats, nil
 }
 
@@ -596,6 +628,190 @@ func (ds *DataSource) buildIndexMergeOrPath(partialPaths []*util.AccessPath, cur
 	return indexMergePath
 }
 
+// generateMVIndexPaths generates the access paths of the multi-valued indexes. The multi-valued index has an entry
+// for each element of the array, so it's accessed by the conditions which check whether the array has some values,
+// i.e. `value MEMBER OF(array)`, `JSON_CONTAINS(array, values)` and `JSON_OVERLAPS(array, values)`.
+func (ds *DataSource) generateMVIndexPaths(isReadOnlyTxn bool) error {
+	for _, mvIndexPath := range ds.mvIndexPaths {
+		mvPos, arrayCol := ds.findMVIndexArrayColumn(mvIndexPath.Index)
+		if arrayCol == nil {
+			continue
+		}
+		castArray, ok := arrayCol.VirtualExpr.(*expression.ScalarFunction)
+		if !ok || castArray.FuncName.L != ast.Cast {
+			continue
+		}
+		// The conditions on the array usually can't be pushed down, so they are looked for in all the conditions.
+		for i, cond := range ds.allConds {
+			values := ds.extractMVIndexValues(cond, castArray.GetArgs()[0], arrayCol.RetType)
+			// The handles of several values are unioned by IndexMerge, which doesn't read the dirty rows yet.
+			if len(values) == 0 || (len(values) > 1 && !isReadOnlyTxn) {
+				continue
+			}
+			path, err := ds.buildMVIndexPath(mvIndexPath, mvPos, arrayCol, i, values)
+			if err != nil {
+				return err
+			}
+			if path != nil {
+				ds.possibleAccessPaths = append(ds.possibleAccessPaths, path)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// findMVIndexArrayColumn finds the column of the array of the multi-valued index, and its offset in the index.
+func (ds *DataSource) findMVIndexArrayColumn(idx *model.IndexInfo) (int, *expression.Column) {
+	for i, idxCol := range idx.Columns {
+		colInfo := ds.tableInfo.Columns[idxCol.Offset]
+		if colInfo.FieldType.ArrayElem == nil {
+			continue
+		}
+		for _, col := range ds.TblCols {
+			if col.ID == colInfo.ID && col.VirtualExpr != nil {
+				return i, col
+			}
+		}
+		break
+	}
+	return -1, nil
+}
+
+// extractMVIndexValues extracts the values which access the multi-valued index from the condition. The values are
+// converted like the elements of the array, so the condition doesn't access the index if a value can't be converted.
+func (ds *DataSource) extractMVIndexValues(cond, arrayExpr expression.Expression, arrayTp *types.FieldType) []types.Datum {
+	sf, ok := cond.(*expression.ScalarFunction)
+	if !ok {
+		return nil
+	}
+	args := sf.GetArgs()
+	var valueExpr expression.Expression
+	switch sf.FuncName.L {
+	case ast.JSONMemberOf:
+		if args[1].Equal(ds.ctx, arrayExpr) {
+			valueExpr = args[0]
+		}
+	case ast.JSONContains:
+		if len(args) == 2 && args[0].Equal(ds.ctx, arrayExpr) {
+			valueExpr = args[1]
+		}
+	case expression.JSONOverlaps:
+		if args[0].Equal(ds.ctx, arrayExpr) {
+			valueExpr = args[1]
+		} else if args[1].Equal(ds.ctx, arrayExpr) {
+			valueExpr = args[0]
+		}
+	}
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	if valueExpr == nil || !valueExpr.ConstItem(sc) {
+		return nil
+	}
+	value, isNull, err := valueExpr.EvalJSON(ds.ctx, chunk.Row{})
+	if err != nil || isNull {
+		return nil
+	}
+	// MEMBER OF looks for the value itself in the array, so an array or an object is never in the index.
+	if sf.FuncName.L == ast.JSONMemberOf && (value.TypeCode == json.TypeCodeArray || value.TypeCode == json.TypeCodeObject) {
+		return nil
+	}
+	valueConst := &expression.Constant{Value: types.NewJSONDatum(value), RetType: types.NewFieldType(mysql.TypeJSON)}
+	arr, isNull, err := expression.BuildCastFunction(ds.ctx, valueConst, arrayTp).EvalJSON(ds.ctx, chunk.Row{})
+	if err != nil || isNull {
+		return nil
+	}
+	values, err := tables.ArrayElems2Datums(sc, arr, arrayTp.ArrayElem)
+	if err != nil {
+		return nil
+	}
+	// JSON_CONTAINS needs all the values in the array, so the rows are found by any of them.
+	if sf.FuncName.L == ast.JSONContains && len(values) > 1 {
+		values = values[:1]
+	}
+	return values
+}
+
+// buildMVIndexPath builds the path which accesses the multi-valued index by the values of ds.allConds[condIdx].
+// The key parts before the array are accessed by the points of the other conditions. A single value is accessed by
+// an index path, and several values are accessed by an IndexMerge path, which unions the handles of the values since
+// a row may have several of them. The conditions are all kept as the filters.
+func (ds *DataSource) buildMVIndexPath(mvIndexPath *util.AccessPath, mvPos int, arrayCol *expression.Column, condIdx int, values []types.Datum) (*util.AccessPath, error) {
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	idx := mvIndexPath.Index
+	idxCols, idxColLens := expression.IndexInfo2PrefixCols(ds.Columns, ds.schema.Columns, idx)
+	if len(idxCols) < mvPos {
+		return nil, nil
+	}
+	idxCols, idxColLens = idxCols[:mvPos:mvPos], idxColLens[:mvPos:mvPos]
+	prefixRanges := []*ranger.Range{{}}
+	var accessConds []expression.Expression
+	if mvPos > 0 {
+		otherConds := make([]expression.Expression, 0, len(ds.allConds))
+		otherConds = append(otherConds, ds.allConds[:condIdx]...)
+		otherConds = append(otherConds, ds.allConds[condIdx+1:]...)
+		res, err := ranger.DetachCondAndBuildRangeForIndex(ds.ctx, otherConds, idxCols, idxColLens)
+		if err != nil {
+			return nil, err
+		}
+		// The ranges aren't rebuilt for the plan cache, so they can't depend on the parameters.
+		if sc.UseCache && expression.ContainMutableConst(ds.ctx, res.AccessConds) {
+			return nil, nil
+		}
+		for _, ran := range res.Ranges {
+			if !ran.IsPoint(sc) || len(ran.LowVal) != mvPos {
+				return nil, nil
+			}
+		}
+		prefixRanges, accessConds = res.Ranges, res.AccessConds
+	}
+	accessConds = append(accessConds, ds.allConds[condIdx])
+	idxCols = append(idxCols, arrayCol)
+	idxColLens = append(idxColLens, types.UnspecifiedLength)
+	// The array column is never covered by the index, whose entries have the elements.
+	fullIdxCols, fullIdxColLens := expression.IndexInfo2Cols(ds.Columns, ds.schema.Columns, idx)
+	fullIdxCols[mvPos] = nil
+
+	partialPaths := make([]*util.AccessPath, 0, len(values))
+	var totalCount float64
+	for _, value := range values {
+		ranges := make([]*ranger.Range, 0, len(prefixRanges))
+		for _, prefix := range prefixRanges {
+			lowVal := append(append(make([]types.Datum, 0, mvPos+1), prefix.LowVal...), value)
+			highVal := append(append(make([]types.Datum, 0, mvPos+1), prefix.HighVal...), value)
+			ranges = append(ranges, &ranger.Range{LowVal: lowVal, HighVal: highVal})
+		}
+		count, err := ds.tableStats.HistColl.GetRowCountByIndexRanges(sc, idx.ID, ranges)
+		if err != nil {
+			return nil, err
+		}
+		totalCount += count
+		partialPaths = append(partialPaths, &util.AccessPath{
+			Index:            idx,
+			Forced:           mvIndexPath.Forced,
+			IdxCols:          idxCols,
+			IdxColLens:       idxColLens,
+			FullIdxCols:      fullIdxCols,
+			FullIdxColLens:   fullIdxColLens,
+			Ranges:           ranges,
+			AccessConds:      accessConds,
+			EqCondCount:      len(idxCols),
+			EqOrInCondCount:  len(idxCols),
+			CountAfterAccess: count,
+		})
+	}
+	tableFilters := make([]expression.Expression, len(ds.pushedDownConds))
+	copy(tableFilters, ds.pushedDownConds)
+	if len(partialPaths) == 1 {
+		partialPaths[0].TableFilters = tableFilters
+		return partialPaths[0], nil
+	}
+	return &util.AccessPath{
+		PartialIndexPaths: partialPaths,
+		TableFilters:      tableFilters,
+		CountAfterAccess:  math.Min(totalCount, float64(ds.statisticTable.Count)),
+	}, nil
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (p *LogicalSelection) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
 	if p.stats != nil {
@@ -295,6 +295,10 @@ func extractStringFromStringSet(set set.StringSet) string {
 }
 
 func tableHasDirtyContent(ctx sessionctx.Context, tableInfo *model.TableInfo) bool {
+	// The data of local temporary tables is kept in the session, it's always read by UnionScan.
+	if tableInfo.TempTableType == model.TempTableLocal {
+		return true
+	}
 	pi := tableInfo.GetPartitionInfo()
 	if pi == nil {
 		return ctx.HasDirtyContent(tableInfo.ID)
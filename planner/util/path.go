@@ -59,6 +59,9 @@ type AccessPath struct {
 	IsCommonHandlePath bool
 	// Forced means this path is generated by `use/force index()`.
 	Forced bool
+	// IsHypothetical indicates whether this path is generated by a hypothetical index, which only
+	// exists for the optimizer and has no data or statistics.
+	IsHypothetical bool
 }
 
 // IsTablePath returns true if it's IntHandlePath or CommonHandlePath.
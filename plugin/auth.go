@@ -0,0 +1,45 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"sync/atomic"
+
+	"github.com/pingcap/tidb/privilege/privileges"
+)
+
+func init() {
+	privileges.AuthenticateWithPlugin = authenticateWithPlugin
+}
+
+// GetAuthentication finds a ready and enabled authentication plugin by name,
+// it returns nil if there is no such plugin.
+func GetAuthentication(name string) *AuthenticationManifest {
+	p := Get(Authentication, name)
+	if p == nil || p.State != Ready || atomic.LoadUint32(&p.Disabled) == 1 {
+		return nil
+	}
+	return DeclareAuthenticationManifest(p.Manifest)
+}
+
+func authenticateWithPlugin(pluginName, authUser, authHost, authString string, authentication, salt []byte) error {
+	authPlugin := GetAuthentication(pluginName)
+	if authPlugin == nil {
+		return errPluginIsNotLoaded.GenWithStackByArgs(pluginName)
+	}
+	if authPlugin.AuthenticateUser == nil {
+		return errInvalidPluginManifest.GenWithStackByArgs(pluginName)
+	}
+	return authPlugin.AuthenticateUser(authUser, authHost, authString, authentication, salt)
+}
@@ -26,4 +26,5 @@ var (
 	errDuplicatePlugin         = dbterror.ClassPlugin.NewStd(errno.ErrDuplicatePlugin)
 	errInvalidPluginSysVarName = dbterror.ClassPlugin.NewStd(errno.ErrInvalidPluginSysVarName)
 	errRequireVersionCheckFail = dbterror.ClassPlugin.NewStd(errno.ErrRequireVersionCheckFail)
+	errPluginIsNotLoaded       = dbterror.ClassPlugin.NewStd(errno.ErrPluginIsNotLoaded)
 )
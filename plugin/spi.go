@@ -64,13 +64,24 @@ func ExportManifest(m interface{}) *Manifest {
 	return (*Manifest)(unsafe.Pointer(v.Pointer()))
 }
 
-// AuthenticationManifest presents a sub-manifest that every audit plugin must provide.
+// AuthenticationManifest presents a sub-manifest that every authentication plugin must provide.
+// Accounts are bound to an authentication plugin by the `plugin` column of mysql.user,
+// which is set by `CREATE USER ... IDENTIFIED WITH <plugin>`.
 type AuthenticationManifest struct {
 	Manifest
-	AuthenticateUser             func()
-	GenerateAuthenticationString func()
-	ValidateAuthenticationString func()
-	SetSalt                      func()
+	// ClientPluginName is the client side plugin requested in the auth-switch request during handshake,
+	// e.g. "mysql_clear_password" for plugins that need the plain text password.
+	// mysql_native_password will be used if it's empty.
+	ClientPluginName string
+	// AuthenticateUser verifies the authentication data sent by client against the authentication string
+	// stored in mysql.user. salt is the one sent to client in the auth-switch request.
+	// return error will reject the connection.
+	AuthenticateUser func(authUser, authHost, authString string, authentication, salt []byte) error
+	// GenerateAuthenticationString generates the authentication string stored in mysql.user
+	// for `IDENTIFIED WITH <plugin> BY 'password'`.
+	GenerateAuthenticationString func(password string) (string, error)
+	// ValidateAuthenticationString checks the authentication string in `IDENTIFIED WITH <plugin> AS 'auth_string'`.
+	ValidateAuthenticationString func(authString string) bool
 }
 
 // SchemaManifest presents a sub-manifest that every schema plugins must provide.
@@ -15,8 +15,10 @@ package privilege
 
 import (
 	"crypto/tls"
+	"time"
 
 	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
@@ -36,9 +38,13 @@ type Manager interface {
 	// GetEncodedPassword shows the encoded password for user.
 	GetEncodedPassword(user, host string) string
 
+	// GetAuthPlugin gets the authentication plugin for user, it returns "" if the user doesn't exist.
+	GetAuthPlugin(user, host string) string
+
 	// RequestVerification verifies user privilege for the request.
 	// If table is "", only check global/db scope privileges.
 	// If table is not "", check global/db/table scope privileges.
+	// If column is not "", check global/db/table/column scope privileges.
 	// priv should be a defined constant like CreatePriv, if pass AllPrivMask to priv,
 	// this means any privilege would be OK.
 	RequestVerification(activeRole []*auth.RoleIdentity, db, table, column string, priv mysql.PrivilegeType) bool
@@ -46,6 +52,12 @@ type Manager interface {
 	// RequestVerificationWithUser verifies specific user privilege for the request.
 	RequestVerificationWithUser(db, table, column string, priv mysql.PrivilegeType, user *auth.UserIdentity) bool
 
+	// RequestAnyColumnVerification verifies user privilege on the table or on any column of it.
+	RequestAnyColumnVerification(activeRole []*auth.RoleIdentity, db, table string, priv mysql.PrivilegeType) bool
+
+	// RequestAnyColumnVerificationWithUser verifies specific user privilege on the table or on any column of it.
+	RequestAnyColumnVerificationWithUser(db, table string, priv mysql.PrivilegeType, user *auth.UserIdentity) bool
+
 	// RequestDynamicVerification verifies user privilege for a DYNAMIC privilege.
 	// Dynamic privileges are only assignable globally, and have their own grantable attribute.
 	RequestDynamicVerification(activeRoles []*auth.RoleIdentity, privName string, grantable bool) bool
@@ -54,7 +66,12 @@ type Manager interface {
 	RequestDynamicVerificationWithUser(privName string, grantable bool, user *auth.UserIdentity) bool
 
 	// ConnectionVerification verifies user privilege for connection.
-	ConnectionVerification(user, host string, auth, salt []byte, tlsState *tls.ConnectionState) (string, string, bool)
+	// The password policy of the matched account is returned even if the verification fails.
+	ConnectionVerification(user, host string, auth, salt []byte, tlsState *tls.ConnectionState) (string, string, VerificationInfo, bool)
+
+	// CachingSha2FastAuth verifies the caching_sha2_password scramble against the password digest cached by
+	// the previous full authentication of the user, the full authentication is required if it fails.
+	CachingSha2FastAuth(user, host string, scramble, salt []byte) bool
 
 	// GetAuthWithoutVerification uses to get auth name without verification.
 	GetAuthWithoutVerification(user, host string) (string, string, bool)
@@ -65,6 +82,9 @@ type Manager interface {
 	// UserPrivilegesTable provide data for INFORMATION_SCHEMA.USER_PRIVILEGES table.
 	UserPrivilegesTable() [][]types.Datum
 
+	// ColumnPrivilegesTable provide data for INFORMATION_SCHEMA.COLUMN_PRIVILEGES table.
+	ColumnPrivilegesTable() [][]types.Datum
+
 	// ActiveRoles active roles for current session.
 	// The first illegal role will be returned.
 	ActiveRoles(ctx sessionctx.Context, roleList []*auth.RoleIdentity) (bool, string)
@@ -80,6 +100,25 @@ type Manager interface {
 
 	// IsDynamicPrivilege returns if a privilege is in the list of privileges.
 	IsDynamicPrivilege(privNameInUpper string) bool
+
+	// RowPolicies returns the row-level security policies of the table applied to the current user.
+	// It returns nil if the table has no policy or the user has the ROW_POLICY_ADMIN privilege.
+	RowPolicies(activeRoles []*auth.RoleIdentity, tblInfo *model.TableInfo) []*model.RowPolicyInfo
+}
+
+// VerificationInfo records the password policy of the account matched by ConnectionVerification.
+type VerificationInfo struct {
+	// FailedDueToWrongPassword indicates that the verification failed because of a wrong password,
+	// which is counted as a failed login attempt.
+	FailedDueToWrongPassword bool
+	PasswordExpired          bool
+	PasswordLastChanged      time.Time
+	// PasswordLifeTime is the password lifetime in days, -1 means using the global default_password_lifetime.
+	PasswordLifeTime int64
+	// FailedLoginAttempts and PasswordLockTime are the failed-login tracking policy, the account is blocked
+	// for PasswordLockTime days after FailedLoginAttempts consecutive failed logins, -1 means unbounded.
+	FailedLoginAttempts int64
+	PasswordLockTime    int64
 }
 
 const key keyType = 0
@@ -63,7 +63,7 @@ const (
 	References_priv,Alter_priv,Execute_priv,Index_priv,Create_view_priv,Show_view_priv,
 	Create_role_priv,Drop_role_priv,Create_tmp_table_priv,Lock_tables_priv,Create_routine_priv,
 	Alter_routine_priv,Event_priv,Shutdown_priv,Reload_priv,File_priv,Config_priv,Repl_client_priv,Repl_slave_priv,
-	account_locked FROM mysql.user`
+	account_locked,plugin,Password_expired,Password_last_changed,Password_lifetime,Failed_login_attempts,Password_lock_time FROM mysql.user`
 	sqlLoadGlobalGrantsTable = `SELECT HIGH_PRIORITY Host,User,Priv,With_Grant_Option FROM mysql.global_grants`
 )
 
@@ -94,8 +94,23 @@ type UserRecord struct {
 	baseRecord
 
 	AuthenticationString string
+	AuthPlugin           string
 	Privileges           mysql.PrivilegeType
 	AccountLocked        bool // A role record when this field is true
+
+	PasswordExpired     bool
+	PasswordLastChanged time.Time
+	// PasswordLifeTime is the password lifetime in days, -1 means using the global default_password_lifetime.
+	PasswordLifeTime int64
+	// FailedLoginAttempts and PasswordLockTime are the failed-login tracking policy, the account is blocked
+	// for PasswordLockTime days after FailedLoginAttempts consecutive failed logins, -1 means unbounded.
+	FailedLoginAttempts int64
+	PasswordLockTime    int64
+}
+
+// isExternalAuth returns whether the user is authenticated by an authentication plugin.
+func (record *UserRecord) isExternalAuth() bool {
+	return record.AuthPlugin != "" && record.AuthPlugin != mysql.AuthNativePassword && record.AuthPlugin != mysql.AuthCachingSha2Password
 }
 
 // NewUserRecord return a UserRecord, only use for unit test.
@@ -632,6 +647,27 @@ func (p *MySQLPrivilege) decodeUserTableRow(row chunk.Row, fs []*ast.ResultField
 			if row.GetEnum(i).String() == "Y" {
 				value.AccountLocked = true
 			}
+		case f.ColumnAsName.L == "plugin":
+			value.AuthPlugin = row.GetString(i)
+		case f.ColumnAsName.L == "password_expired":
+			value.PasswordExpired = row.GetEnum(i).String() == "Y"
+		case f.ColumnAsName.L == "password_last_changed":
+			if !row.IsNull(i) {
+				lastChanged, err := row.GetTime(i).GoTime(time.Local)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				value.PasswordLastChanged = lastChanged
+			}
+		case f.ColumnAsName.L == "password_lifetime":
+			value.PasswordLifeTime = -1
+			if !row.IsNull(i) {
+				value.PasswordLifeTime = row.GetInt64(i)
+			}
+		case f.ColumnAsName.L == "failed_login_attempts":
+			value.FailedLoginAttempts = row.GetInt64(i)
+		case f.ColumnAsName.L == "password_lock_time":
+			value.PasswordLockTime = row.GetInt64(i)
 		case f.Column.Tp == mysql.TypeEnum:
 			if row.GetEnum(i).String() != "Y" {
 				continue
@@ -817,12 +853,29 @@ func (p *MySQLPrivilege) decodeColumnsPrivTableRow(row chunk.Row, fs []*ast.Resu
 	return nil
 }
 
+// referencesSetStr is the string of REFERENCES in the SET columns of mysql.tables_priv and mysql.columns_priv,
+// which is missing in mysql.Priv2SetStr and mysql.SetStr2Priv.
+const referencesSetStr = "References"
+
+// PrivToSetStr returns the string of the privilege in the SET columns of mysql.tables_priv and mysql.columns_priv.
+func PrivToSetStr(priv mysql.PrivilegeType) (string, bool) {
+	if priv == mysql.ReferencesPriv {
+		return referencesSetStr, true
+	}
+	str, ok := mysql.Priv2SetStr[priv]
+	return str, ok
+}
+
 func decodeSetToPrivilege(s types.Set) mysql.PrivilegeType {
 	var ret mysql.PrivilegeType
 	if s.Name == "" {
 		return ret
 	}
 	for _, str := range strings.Split(s.Name, ",") {
+		if str == referencesSetStr {
+			ret |= mysql.ReferencesPriv
+			continue
+		}
 		priv, ok := mysql.SetStr2Priv[str]
 		if !ok {
 			logutil.BgLogger().Warn("unsupported privilege", zap.String("type", str))
@@ -1026,16 +1079,14 @@ func (p *MySQLPrivilege) RequestVerification(activeRoles []*auth.RoleIdentity, u
 		tableRecord := p.matchTables(r.Username, r.Hostname, db, table)
 		if tableRecord != nil {
 			tablePriv |= tableRecord.TablePriv
-			if column != "" {
-				columnPriv |= tableRecord.ColumnPriv
-			}
 		}
 	}
-	if tablePriv&priv > 0 || columnPriv&priv > 0 {
+	if tablePriv&priv > 0 {
 		return true
 	}
 
-	columnPriv = 0
+	// The Column_priv of mysql.tables_priv is the union of the privileges on all columns of the table,
+	// so the privilege on the specific column can only be found in mysql.columns_priv.
 	for _, r := range roleList {
 		columnRecord := p.matchColumns(r.Username, r.Hostname, db, table, column)
 		if columnRecord != nil {
@@ -1049,6 +1100,29 @@ func (p *MySQLPrivilege) RequestVerification(activeRoles []*auth.RoleIdentity, u
 	return priv == 0
 }
 
+// RequestAnyColumnVerification checks whether the user has the privilege on the table or on any column of it.
+// It's used for the statements reading the table, whose columns are checked one by one separately.
+func (p *MySQLPrivilege) RequestAnyColumnVerification(activeRoles []*auth.RoleIdentity, user, host, db, table string, priv mysql.PrivilegeType) bool {
+	if p.RequestVerification(activeRoles, user, host, db, table, "", priv) {
+		return true
+	}
+
+	roleList := p.FindAllRole(activeRoles)
+	roleList = append(roleList, &auth.RoleIdentity{Username: user, Hostname: host})
+	for _, r := range roleList {
+		for i := 0; i < len(p.ColumnsPriv); i++ {
+			record := &p.ColumnsPriv[i]
+			if record.baseRecord.match(r.Username, r.Hostname) &&
+				strings.EqualFold(record.DB, db) &&
+				strings.EqualFold(record.TableName, table) &&
+				record.ColumnPriv&priv > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // DBIsVisible checks whether the user can see the db.
 func (p *MySQLPrivilege) DBIsVisible(user, host, db string) bool {
 	if record := p.matchUser(user, host); record != nil {
@@ -1279,8 +1353,13 @@ func (p *MySQLPrivilege) showGrants(user, host string, roles []*auth.RoleIdentit
 			}
 		}
 	}
-	for k, v := range columnPrivTable {
-		privCols := privOnColumnsToString(v)
+	columnPrivKeys := make([]string, 0, len(columnPrivTable))
+	for k := range columnPrivTable {
+		columnPrivKeys = append(columnPrivKeys, k)
+	}
+	sort.Strings(columnPrivKeys)
+	for _, k := range columnPrivKeys {
+		privCols := privOnColumnsToString(columnPrivTable[k])
 		s := fmt.Sprintf(`GRANT %s ON %s TO '%s'@'%s'`, privCols, k, user, host)
 		gs = append(gs, s)
 	}
@@ -1335,10 +1414,13 @@ type columnStr = string
 type columnStrs = []columnStr
 type privOnColumns = map[mysql.PrivilegeType]columnStrs
 
+// ColumnPrivs is all the privileges in column scope.
+var ColumnPrivs = mysql.Privileges{mysql.SelectPriv, mysql.InsertPriv, mysql.UpdatePriv, mysql.ReferencesPriv}
+
 func privOnColumnsToString(p privOnColumns) string {
 	var buf bytes.Buffer
 	idx := 0
-	for _, priv := range mysql.AllColumnPrivs {
+	for _, priv := range ColumnPrivs {
 		v, ok := p[priv]
 		if !ok || len(v) == 0 {
 			continue
@@ -1368,7 +1450,7 @@ func collectColumnGrant(record *columnsPrivRecord, user, host string, columnPriv
 			privColumns = make(map[mysql.PrivilegeType]columnStrs)
 		}
 
-		for _, priv := range mysql.AllColumnPrivs {
+		for _, priv := range ColumnPrivs {
 			if priv&record.ColumnPriv > 0 {
 				old := privColumns[priv]
 				privColumns[priv] = append(old, record.ColumnName)
@@ -1465,6 +1547,33 @@ func appendUserPrivilegesTableRow(rows [][]types.Datum, user UserRecord) [][]typ
 	return rows
 }
 
+// ColumnPrivilegesTable provide data for INFORMATION_SCHEMA.COLUMN_PRIVILEGES table.
+func (p *MySQLPrivilege) ColumnPrivilegesTable() [][]types.Datum {
+	var rows [][]types.Datum
+	for i := range p.ColumnsPriv {
+		record := &p.ColumnsPriv[i]
+		isGrantable := "NO"
+		for j := range p.TablesPriv {
+			tableRecord := &p.TablesPriv[j]
+			if tableRecord.User == record.User && tableRecord.Host == record.Host &&
+				strings.EqualFold(tableRecord.DB, record.DB) && strings.EqualFold(tableRecord.TableName, record.TableName) {
+				if tableRecord.TablePriv&mysql.GrantPriv > 0 {
+					isGrantable = "YES"
+				}
+				break
+			}
+		}
+		grantee := fmt.Sprintf("'%s'@'%s'", record.User, record.Host)
+		for _, priv := range ColumnPrivs {
+			if record.ColumnPriv&priv > 0 {
+				rows = append(rows, types.MakeDatums(grantee, "def", record.DB, record.TableName, record.ColumnName,
+					mysql.Priv2Str[priv], isGrantable))
+			}
+		}
+	}
+	return rows
+}
+
 func (p *MySQLPrivilege) getDefaultRoles(user, host string) []*auth.RoleIdentity {
 	ret := make([]*auth.RoleIdentity, 0)
 	for _, r := range p.DefaultRoles {
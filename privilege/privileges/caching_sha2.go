@@ -0,0 +1,213 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The authentication string of caching_sha2_password is compatible with MySQL, which is
+// "$A$<iterations>$<salt><digest>". The iteration count is stored in thousands as 3 hex digits,
+// the digest is computed by the SHA-256 crypt of Ulrich Drepper and encoded in 43 characters.
+const (
+	sha2AuthStringPrefix = "$A$"
+	sha2SaltLen          = 20
+	sha2DigestLen        = 43
+	sha2Iterations       = 5000
+	sha2IterationsUnit   = 1000
+	// Sha2AuthStringLen is the length of the authentication string of caching_sha2_password.
+	Sha2AuthStringLen = len(sha2AuthStringPrefix) + 3 + 1 + sha2SaltLen + sha2DigestLen
+)
+
+const cryptAlphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// NewSha2Password generates the caching_sha2_password authentication string of the password
+// with a random salt, the empty password is kept empty.
+func NewSha2Password(pwd string) (string, error) {
+	if len(pwd) == 0 {
+		return "", nil
+	}
+	salt := make([]byte, sha2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	// Keep the salt printable so that the authentication string can be shown by SHOW CREATE USER.
+	for i := range salt {
+		salt[i] = cryptAlphabet[int(salt[i])%len(cryptAlphabet)]
+	}
+	return fmt.Sprintf("%s%03X$%s%s", sha2AuthStringPrefix, sha2Iterations/sha2IterationsUnit, salt,
+		sha256Crypt([]byte(pwd), salt, sha2Iterations)), nil
+}
+
+// IsValidSha2Password returns whether the string is a valid caching_sha2_password authentication string.
+func IsValidSha2Password(authString string) bool {
+	_, _, _, ok := parseSha2Password(authString)
+	return ok || len(authString) == 0
+}
+
+// CheckSha2Password checks the password against the caching_sha2_password authentication string.
+func CheckSha2Password(authString, pwd string) bool {
+	if len(authString) == 0 {
+		return len(pwd) == 0
+	}
+	iterations, salt, digest, ok := parseSha2Password(authString)
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(sha256Crypt([]byte(pwd), []byte(salt), iterations)), []byte(digest)) == 1
+}
+
+func parseSha2Password(authString string) (iterations int, salt, digest string, ok bool) {
+	if len(authString) != Sha2AuthStringLen || !strings.HasPrefix(authString, sha2AuthStringPrefix) {
+		return 0, "", "", false
+	}
+	rest := authString[len(sha2AuthStringPrefix):]
+	if rest[3] != '$' {
+		return 0, "", "", false
+	}
+	n, err := strconv.ParseUint(rest[:3], 16, 16)
+	if err != nil || n == 0 {
+		return 0, "", "", false
+	}
+	return int(n) * sha2IterationsUnit, rest[4 : 4+sha2SaltLen], rest[4+sha2SaltLen:], true
+}
+
+// sha256Crypt computes the digest of the SHA-256 crypt, see https://www.akkadia.org/drepper/SHA-crypt.txt.
+func sha256Crypt(plaintext, salt []byte, iterations int) string {
+	digestB := sha256.New()
+	digestB.Write(plaintext)
+	digestB.Write(salt)
+	digestB.Write(plaintext)
+	sumB := digestB.Sum(nil)
+
+	digestA := sha256.New()
+	digestA.Write(plaintext)
+	digestA.Write(salt)
+	digestA.Write(repeatBytes(sumB, len(plaintext)))
+	for i := len(plaintext); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			digestA.Write(sumB)
+		} else {
+			digestA.Write(plaintext)
+		}
+	}
+	sumA := digestA.Sum(nil)
+
+	digestDP := sha256.New()
+	for range plaintext {
+		digestDP.Write(plaintext)
+	}
+	seqP := repeatBytes(digestDP.Sum(nil), len(plaintext))
+
+	digestDS := sha256.New()
+	for i := 0; i < 16+int(sumA[0]); i++ {
+		digestDS.Write(salt)
+	}
+	seqS := repeatBytes(digestDS.Sum(nil), len(salt))
+
+	sumC := sumA
+	for i := 0; i < iterations; i++ {
+		digestC := sha256.New()
+		if i&1 != 0 {
+			digestC.Write(seqP)
+		} else {
+			digestC.Write(sumC)
+		}
+		if i%3 != 0 {
+			digestC.Write(seqS)
+		}
+		if i%7 != 0 {
+			digestC.Write(seqP)
+		}
+		if i&1 != 0 {
+			digestC.Write(sumC)
+		} else {
+			digestC.Write(seqP)
+		}
+		sumC = digestC.Sum(nil)
+	}
+
+	var buf strings.Builder
+	encode := func(b2, b1, b0 byte, n int) {
+		w := uint(b2)<<16 | uint(b1)<<8 | uint(b0)
+		for ; n > 0; n-- {
+			buf.WriteByte(cryptAlphabet[w&0x3f])
+			w >>= 6
+		}
+	}
+	for i := 0; i < 10; i++ {
+		// The bytes are permuted as (0, 10, 20), (21, 1, 11), (12, 22, 2), ...
+		j, k, l := (i*21)%30, (i*21+10)%30, (i*21+20)%30
+		encode(sumC[j], sumC[k], sumC[l], 4)
+	}
+	encode(0, sumC[31], sumC[30], 3)
+	return buf.String()
+}
+
+// repeatBytes returns the first n bytes of b repeated.
+func repeatBytes(b []byte, n int) []byte {
+	return bytes.Repeat(b, n/len(b)+1)[:n]
+}
+
+// sha2DigestCache caches SHA256(SHA256(password)) of the accounts authenticated by caching_sha2_password after
+// the full authentication, which allows the later connections to be authenticated by the scramble only.
+// An entry is stale once the authentication string of the account changes.
+var sha2DigestCache = struct {
+	sync.RWMutex
+	entries map[string]sha2DigestCacheEntry
+}{entries: make(map[string]sha2DigestCacheEntry)}
+
+type sha2DigestCacheEntry struct {
+	authString string
+	digest     []byte
+}
+
+func sha2DigestCacheKey(user, host string) string {
+	return user + "@" + host
+}
+
+func cacheSha2Digest(user, host, authString, pwd string) {
+	stage1 := sha256.Sum256([]byte(pwd))
+	stage2 := sha256.Sum256(stage1[:])
+	sha2DigestCache.Lock()
+	sha2DigestCache.entries[sha2DigestCacheKey(user, host)] = sha2DigestCacheEntry{authString: authString, digest: stage2[:]}
+	sha2DigestCache.Unlock()
+}
+
+// checkSha2Scramble checks the scramble XOR(SHA256(password), SHA256(SHA256(SHA256(password)), salt))
+// sent by the client against the cached digest of the account.
+func checkSha2Scramble(user, host, authString string, scramble, salt []byte) bool {
+	sha2DigestCache.RLock()
+	entry, ok := sha2DigestCache.entries[sha2DigestCacheKey(user, host)]
+	sha2DigestCache.RUnlock()
+	if !ok || entry.authString != authString || len(scramble) != sha256.Size {
+		return false
+	}
+	h := sha256.New()
+	h.Write(entry.digest)
+	h.Write(salt)
+	stage1 := h.Sum(nil)
+	for i := range stage1 {
+		stage1[i] ^= scramble[i]
+	}
+	stage2 := sha256.Sum256(stage1)
+	return subtle.ConstantTimeCompare(stage2[:], entry.digest) == 1
+}
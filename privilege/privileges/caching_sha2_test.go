@@ -0,0 +1,83 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"crypto/sha256"
+
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testCachingSha2Suite{})
+
+type testCachingSha2Suite struct{}
+
+func (s *testCachingSha2Suite) TestSha256Crypt(c *C) {
+	// The same as crypt(3) of glibc with "$5$saltstring" and "$5$rounds=10000$saltstringsaltst".
+	c.Assert(sha256Crypt([]byte("Hello world!"), []byte("saltstring"), 5000), Equals, "5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5")
+	c.Assert(sha256Crypt([]byte("Hello world!"), []byte("saltstringsaltst"), 10000), Equals, "3xv.VbSHBb41AL9AvLeujZkZRBAwqFMz2.opqey6IcA")
+}
+
+func (s *testCachingSha2Suite) TestSha2Password(c *C) {
+	authString, err := NewSha2Password("abc")
+	c.Assert(err, IsNil)
+	c.Assert(authString, HasLen, Sha2AuthStringLen)
+	c.Assert(authString[:7], Equals, "$A$005$")
+	c.Assert(IsValidSha2Password(authString), IsTrue)
+	c.Assert(CheckSha2Password(authString, "abc"), IsTrue)
+	c.Assert(CheckSha2Password(authString, "abd"), IsFalse)
+	c.Assert(CheckSha2Password(authString, ""), IsFalse)
+
+	// The salt is random.
+	authString2, err := NewSha2Password("abc")
+	c.Assert(err, IsNil)
+	c.Assert(authString2, Not(Equals), authString)
+
+	authString, err = NewSha2Password("")
+	c.Assert(err, IsNil)
+	c.Assert(authString, Equals, "")
+	c.Assert(CheckSha2Password("", ""), IsTrue)
+	c.Assert(CheckSha2Password("", "abc"), IsFalse)
+
+	c.Assert(IsValidSha2Password("*0D3CED9BEC10A777AEC23CCC353A8C08A633045E"), IsFalse)
+	c.Assert(IsValidSha2Password("$A$000$"+authString2[7:]), IsFalse)
+}
+
+func (s *testCachingSha2Suite) TestSha2Scramble(c *C) {
+	authString, err := NewSha2Password("abc")
+	c.Assert(err, IsNil)
+	salt := []byte{85, 92, 45, 22, 58, 79, 107, 6, 122, 125, 58, 80, 12, 90, 103, 32, 90, 10, 74, 82}
+	// XOR(SHA256(password), SHA256(SHA256(SHA256(password)), salt))
+	stage1 := sha256.Sum256([]byte("abc"))
+	stage2 := sha256.Sum256(stage1[:])
+	h := sha256.New()
+	h.Write(stage2[:])
+	h.Write(salt)
+	scramble := h.Sum(nil)
+	for i := range scramble {
+		scramble[i] ^= stage1[i]
+	}
+
+	c.Assert(checkSha2Scramble("u1", "%", authString, scramble, salt), IsFalse)
+	cacheSha2Digest("u1", "%", authString, "abc")
+	c.Assert(checkSha2Scramble("u1", "%", authString, scramble, salt), IsTrue)
+	c.Assert(checkSha2Scramble("u1", "localhost", authString, scramble, salt), IsFalse)
+	c.Assert(checkSha2Scramble("u1", "%", authString, scramble, make([]byte, 20)), IsFalse)
+	c.Assert(checkSha2Scramble("u1", "%", authString, scramble[1:], salt), IsFalse)
+
+	// The cached digest is stale after the password changes.
+	newAuthString, err := NewSha2Password("abc")
+	c.Assert(err, IsNil)
+	c.Assert(checkSha2Scramble("u1", "%", newAuthString, scramble, salt), IsFalse)
+}
@@ -22,6 +22,7 @@ import (
 	"sync"
 
 	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/infoschema/perfschema"
@@ -37,6 +38,10 @@ import (
 // SkipWithGrant causes the server to start without using the privilege system at all.
 var SkipWithGrant = false
 
+// AuthenticateWithPlugin authenticates the accounts whose authentication plugin is not mysql_native_password.
+// It's set by the plugin package, so the privilege package doesn't need to depend on it.
+var AuthenticateWithPlugin func(pluginName, authUser, authHost, authString string, authentication, salt []byte) error
+
 var _ privilege.Manager = (*UserPrivileges)(nil)
 var dynamicPrivs = []string{
 	"BACKUP_ADMIN",
@@ -47,6 +52,7 @@ var dynamicPrivs = []string{
 	"RESTRICTED_STATUS_ADMIN",    // Can see all status vars when SEM is enabled.
 	"RESTRICTED_VARIABLES_ADMIN", // Can see all variables when SEM is enabled
 	"RESTRICTED_USER_ADMIN",      // User can not have their access revoked by SUPER users.
+	"ROW_POLICY_ADMIN",           // Row-level security policies are not applied to the user.
 }
 var dynamicPrivLock sync.Mutex
 
@@ -161,6 +167,34 @@ func (p *UserPrivileges) RequestVerificationWithUser(db, table, column string, p
 	return mysqlPriv.RequestVerification(roles, user.Username, user.Hostname, db, table, column, priv)
 }
 
+// RequestAnyColumnVerification implements the Manager interface.
+func (p *UserPrivileges) RequestAnyColumnVerification(activeRoles []*auth.RoleIdentity, db, table string, priv mysql.PrivilegeType) bool {
+	if p.RequestVerification(activeRoles, db, table, "", priv) {
+		return true
+	}
+	if sem.IsEnabled() && !p.RequestDynamicVerification(activeRoles, "RESTRICTED_TABLES_ADMIN", false) &&
+		sem.IsInvisibleTable(strings.ToLower(db), strings.ToLower(table)) {
+		return false
+	}
+
+	mysqlPriv := p.Handle.Get()
+	return mysqlPriv.RequestAnyColumnVerification(activeRoles, p.user, p.host, db, table, priv)
+}
+
+// RequestAnyColumnVerificationWithUser implements the Manager interface.
+func (p *UserPrivileges) RequestAnyColumnVerificationWithUser(db, table string, priv mysql.PrivilegeType, user *auth.UserIdentity) bool {
+	if p.RequestVerificationWithUser(db, table, "", priv, user) {
+		return true
+	}
+	if user == nil {
+		return false
+	}
+
+	mysqlPriv := p.Handle.Get()
+	roles := mysqlPriv.getDefaultRoles(user.Username, user.Hostname)
+	return mysqlPriv.RequestAnyColumnVerification(roles, user.Username, user.Hostname, db, table, priv)
+}
+
 // GetEncodedPassword implements the Manager interface.
 func (p *UserPrivileges) GetEncodedPassword(user, host string) string {
 	mysqlPriv := p.Handle.Get()
@@ -171,13 +205,49 @@ func (p *UserPrivileges) GetEncodedPassword(user, host string) string {
 		return ""
 	}
 	pwd := record.AuthenticationString
-	if len(pwd) != 0 && len(pwd) != mysql.PWDHashLen+1 {
+	if record.AuthPlugin == mysql.AuthCachingSha2Password {
+		if !IsValidSha2Password(pwd) {
+			logutil.BgLogger().Error("user password from system DB not like caching_sha2_password", zap.String("user", user))
+			return ""
+		}
+		return pwd
+	}
+	if !record.isExternalAuth() && len(pwd) != 0 && len(pwd) != mysql.PWDHashLen+1 {
 		logutil.BgLogger().Error("user password from system DB not like sha1sum", zap.String("user", user))
 		return ""
 	}
 	return pwd
 }
 
+// GetAuthPlugin implements the Manager interface.
+func (p *UserPrivileges) GetAuthPlugin(user, host string) string {
+	if SkipWithGrant {
+		return mysql.AuthNativePassword
+	}
+	mysqlPriv := p.Handle.Get()
+	record := mysqlPriv.connectionVerification(user, host)
+	if record == nil {
+		return ""
+	}
+	if record.AuthPlugin == "" {
+		return mysql.AuthNativePassword
+	}
+	return record.AuthPlugin
+}
+
+// CachingSha2FastAuth implements the Manager interface.
+func (p *UserPrivileges) CachingSha2FastAuth(user, host string, scramble, salt []byte) bool {
+	if SkipWithGrant {
+		return true
+	}
+	mysqlPriv := p.Handle.Get()
+	record := mysqlPriv.connectionVerification(user, host)
+	if record == nil || record.AuthPlugin != mysql.AuthCachingSha2Password {
+		return false
+	}
+	return checkSha2Scramble(record.User, record.Host, record.AuthenticationString, scramble, salt)
+}
+
 // GetAuthWithoutVerification implements the Manager interface.
 func (p *UserPrivileges) GetAuthWithoutVerification(user, host string) (u string, h string, success bool) {
 	if SkipWithGrant {
@@ -204,7 +274,7 @@ func (p *UserPrivileges) GetAuthWithoutVerification(user, host string) (u string
 }
 
 // ConnectionVerification implements the Manager interface.
-func (p *UserPrivileges) ConnectionVerification(user, host string, authentication, salt []byte, tlsState *tls.ConnectionState) (u string, h string, success bool) {
+func (p *UserPrivileges) ConnectionVerification(user, host string, authentication, salt []byte, tlsState *tls.ConnectionState) (u string, h string, info privilege.VerificationInfo, success bool) {
 	if SkipWithGrant {
 		p.user = user
 		p.host = host
@@ -222,6 +292,13 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 
 	u = record.User
 	h = record.Host
+	info = privilege.VerificationInfo{
+		PasswordExpired:     record.PasswordExpired,
+		PasswordLastChanged: record.PasswordLastChanged,
+		PasswordLifeTime:    record.PasswordLifeTime,
+		FailedLoginAttempts: record.FailedLoginAttempts,
+		PasswordLockTime:    record.PasswordLockTime,
+	}
 
 	globalPriv := mysqlPriv.matchGlobalPriv(user, host)
 	if globalPriv != nil {
@@ -243,6 +320,41 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 	}
 
 	pwd := record.AuthenticationString
+	if record.isExternalAuth() {
+		if AuthenticateWithPlugin == nil {
+			logutil.BgLogger().Error("authentication plugin is not supported", zap.String("user", user), zap.String("plugin", record.AuthPlugin))
+			return
+		}
+		if err := AuthenticateWithPlugin(record.AuthPlugin, u, h, pwd, authentication, salt); err != nil {
+			logutil.BgLogger().Error("authenticate user with plugin fail", zap.String("user", user),
+				zap.String("plugin", record.AuthPlugin), zap.Error(err))
+			info.FailedDueToWrongPassword = true
+			return
+		}
+		p.user = user
+		p.host = h
+		success = true
+		return
+	}
+
+	if record.AuthPlugin == mysql.AuthCachingSha2Password {
+		// The authentication is either the scramble verified by the fast authentication,
+		// or the password got by the full authentication.
+		if !checkSha2Scramble(u, h, pwd, authentication, salt) {
+			if !CheckSha2Password(pwd, string(authentication)) {
+				info.FailedDueToWrongPassword = true
+				return
+			}
+			if len(pwd) != 0 {
+				cacheSha2Digest(u, h, pwd, string(authentication))
+			}
+		}
+		p.user = user
+		p.host = h
+		success = true
+		return
+	}
+
 	if len(pwd) != 0 && len(pwd) != mysql.PWDHashLen+1 {
 		logutil.BgLogger().Error("user password from system DB not like sha1sum", zap.String("user", user))
 		return
@@ -257,6 +369,7 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 	}
 
 	if len(pwd) == 0 || len(authentication) == 0 {
+		info.FailedDueToWrongPassword = true
 		return
 	}
 
@@ -267,6 +380,7 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 	}
 
 	if !auth.CheckScrambledPassword(salt, hpwd, authentication) {
+		info.FailedDueToWrongPassword = true
 		return
 	}
 
@@ -457,6 +571,12 @@ func (p *UserPrivileges) UserPrivilegesTable() [][]types.Datum {
 	return mysqlPriv.UserPrivilegesTable()
 }
 
+// ColumnPrivilegesTable implements the Manager interface.
+func (p *UserPrivileges) ColumnPrivilegesTable() [][]types.Datum {
+	mysqlPriv := p.Handle.Get()
+	return mysqlPriv.ColumnPrivilegesTable()
+}
+
 // ShowGrants implements privilege.Manager ShowGrants interface.
 func (p *UserPrivileges) ShowGrants(ctx sessionctx.Context, user *auth.UserIdentity, roles []*auth.RoleIdentity) (grants []string, err error) {
 	if SkipWithGrant {
@@ -541,6 +661,22 @@ func (p *UserPrivileges) IsDynamicPrivilege(privName string) bool {
 	return false
 }
 
+// RowPolicies implements the Manager interface.
+func (p *UserPrivileges) RowPolicies(activeRoles []*auth.RoleIdentity, tblInfo *model.TableInfo) []*model.RowPolicyInfo {
+	if SkipWithGrant || len(tblInfo.RowPolicies) == 0 {
+		return nil
+	}
+	if p.user == "" && p.host == "" {
+		return nil
+	}
+
+	mysqlPriv := p.Handle.Get()
+	if mysqlPriv.RequestDynamicVerification(activeRoles, p.user, p.host, "ROW_POLICY_ADMIN", false) {
+		return nil
+	}
+	return tblInfo.RowPolicies
+}
+
 // RegisterDynamicPrivilege is used by plugins to add new privileges to TiDB
 func RegisterDynamicPrivilege(privName string) error {
 	privNameInUpper := strings.ToUpper(privName)
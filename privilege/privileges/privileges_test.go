@@ -23,6 +23,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 
 	. "github.com/pingcap/check"
@@ -30,6 +31,7 @@ import (
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/privilege"
@@ -425,6 +427,190 @@ func (s *testPrivilegeSuite) TestShowColumnGrants(c *C) {
 	c.Assert(strings.Join(gs, " "), Equals, "GRANT USAGE ON *.* TO 'column'@'%' GRANT Select(a), Insert(c), Update(a, b) ON test.column_table TO 'column'@'%'")
 }
 
+func (s *testPrivilegeSuite) TestColumnPrivileges(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.Se = newSession(c, s.store, s.dbName)
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "root", Hostname: "localhost"}, nil, nil), IsTrue)
+	tk.MustExec("CREATE DATABASE colpriv")
+	tk.MustExec("USE colpriv")
+	tk.MustExec("CREATE TABLE t (id int primary key, name varchar(20), ssn varchar(20), v int)")
+	tk.MustExec("INSERT INTO t VALUES (1, 'a', '111', 1), (2, 'b', '222', 2)")
+	tk.MustExec("CREATE DEFINER = 'root'@'%' SQL SECURITY DEFINER VIEW v AS SELECT id, ssn FROM t")
+	tk.MustExec("CREATE USER analyst")
+	tk.MustExec("GRANT SELECT(id, name), INSERT(id, name), UPDATE(name) ON colpriv.t TO analyst")
+	tk.MustExec("GRANT SELECT(id) ON colpriv.v TO analyst")
+
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.Se = newSession(c, s.store, s.dbName)
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{Username: "analyst", Hostname: "localhost"}, nil, nil), IsTrue)
+	tk1.MustExec("USE colpriv")
+	tk1.MustQuery("SELECT id, name FROM t ORDER BY id").Check(testkit.Rows("1 a", "2 b"))
+	tk1.MustQuery("SELECT count(*) FROM t").Check(testkit.Rows("2"))
+	tk1.MustQuery("SELECT t1.name FROM t t1 JOIN t t2 USING (id) WHERE t2.id = 1").Check(testkit.Rows("a"))
+	tk1.MustQuery("SELECT id FROM v ORDER BY id").Check(testkit.Rows("1", "2"))
+	for _, sql := range []string{
+		"SELECT ssn FROM t",
+		"SELECT * FROM t",
+		"SELECT id FROM t WHERE ssn = '111'",
+		"SELECT id FROM t ORDER BY ssn",
+		"SELECT id, (SELECT max(ssn) FROM t) FROM t",
+		"SELECT count(ssn) FROM t",
+		"UPDATE t SET name = ssn",
+	} {
+		err := tk1.ExecToErr(sql)
+		c.Assert(terror.ErrorEqual(err, core.ErrColumnaccessDenied), IsTrue, Commentf("%s", sql))
+		c.Assert(err.Error(), Equals, "[planner:1143]SELECT command denied to user 'analyst'@'%' for column 'ssn' in table 't'", Commentf("%s", sql))
+	}
+	err := tk1.ExecToErr("SELECT ssn FROM v")
+	c.Assert(err.Error(), Equals, "[planner:1143]SELECT command denied to user 'analyst'@'%' for column 'ssn' in table 'v'")
+
+	tk1.MustExec("INSERT INTO t (id, name) VALUES (3, 'c')")
+	tk1.MustExec("INSERT INTO t SET id = 4")
+	err = tk1.ExecToErr("INSERT INTO t (id, ssn) VALUES (5, '555')")
+	c.Assert(err.Error(), Equals, "[planner:1143]INSERT command denied to user 'analyst'@'%' for column 'ssn' in table 't'")
+	err = tk1.ExecToErr("INSERT INTO t VALUES (5, 'e', '555', 5)")
+	c.Assert(err.Error(), Equals, "[planner:1142]INSERT command denied to user 'analyst'@'%' for table 't'")
+	tk1.MustExec("INSERT INTO t (id, name) VALUES (4, 'd') ON DUPLICATE KEY UPDATE name = 'd'")
+	err = tk1.ExecToErr("INSERT INTO t (id) VALUES (4) ON DUPLICATE KEY UPDATE v = 4")
+	c.Assert(err.Error(), Equals, "[planner:1143]UPDATE command denied to user 'analyst'@'%' for column 'v' in table 't'")
+	tk1.MustExec("UPDATE t SET name = concat(name, id) WHERE id = 3")
+	err = tk1.ExecToErr("UPDATE t SET v = 3 WHERE id = 3")
+	c.Assert(err.Error(), Equals, "[planner:1143]UPDATE command denied to user 'analyst'@'%' for column 'v' in table 't'")
+	tk.MustQuery("SELECT * FROM t WHERE id > 2 ORDER BY id").Check(testkit.Rows("3 c3 <nil> <nil>", "4 d <nil> <nil>"))
+
+	// The foreign key requires the REFERENCES privilege on the referenced columns.
+	tk.MustExec("GRANT CREATE ON colpriv.* TO analyst")
+	err = tk1.ExecToErr("CREATE TABLE child (pid int, FOREIGN KEY (pid) REFERENCES t (id))")
+	c.Assert(err.Error(), Equals, "[planner:1143]REFERENCES command denied to user 'analyst'@'%' for column 'id' in table 't'")
+	tk.MustExec("GRANT REFERENCES(id) ON colpriv.t TO analyst")
+	tk1.MustExec("CREATE TABLE child (pid int, FOREIGN KEY (pid) REFERENCES t (id))")
+
+	tk.MustQuery("SHOW GRANTS FOR analyst").Check(testkit.Rows(
+		"GRANT USAGE ON *.* TO 'analyst'@'%'",
+		"GRANT Create ON colpriv.* TO 'analyst'@'%'",
+		"GRANT Select(id, name), Insert(id, name), Update(name), References(id) ON colpriv.t TO 'analyst'@'%'",
+		"GRANT Select(id) ON colpriv.v TO 'analyst'@'%'",
+	))
+	tk1.MustQuery("SELECT * FROM information_schema.column_privileges ORDER BY table_name, column_name, privilege_type").Check(testkit.Rows(
+		"'analyst'@'%' def colpriv t id Insert NO",
+		"'analyst'@'%' def colpriv t id References NO",
+		"'analyst'@'%' def colpriv t id Select NO",
+		"'analyst'@'%' def colpriv t name Insert NO",
+		"'analyst'@'%' def colpriv t name Select NO",
+		"'analyst'@'%' def colpriv t name Update NO",
+		"'analyst'@'%' def colpriv v id Select NO",
+	))
+
+	tk.MustExec("REVOKE SELECT(name) ON colpriv.t FROM analyst")
+	err = tk1.ExecToErr("SELECT name FROM t")
+	c.Assert(err.Error(), Equals, "[planner:1143]SELECT command denied to user 'analyst'@'%' for column 'name' in table 't'")
+	tk1.MustQuery("SELECT id FROM t WHERE id = 1").Check(testkit.Rows("1"))
+
+	// Only SELECT, INSERT, UPDATE and REFERENCES can be granted in column scope.
+	err = tk.ExecToErr("GRANT DELETE(id) ON colpriv.t TO analyst")
+	c.Assert(terror.ErrorEqual(err, executor.ErrIllegalGrantForTable), IsTrue)
+	err = tk.ExecToErr("REVOKE DELETE(id) ON colpriv.t FROM analyst")
+	c.Assert(terror.ErrorEqual(err, executor.ErrIllegalGrantForTable), IsTrue)
+}
+
+func (s *testPrivilegeSuite) TestRowPolicies(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.Se = newSession(c, s.store, s.dbName)
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "root", Hostname: "localhost"}, nil, nil), IsTrue)
+	tk.MustExec("CREATE DATABASE rls")
+	tk.MustExec("USE rls")
+	tk.MustExec("CREATE TABLE t (id int primary key, tenant varchar(20), v int)")
+	tk.MustExec("INSERT INTO t VALUES (1, 'alice', 1), (2, 'bob', 2), (3, 'alice', 3)")
+	tk.MustExec("CREATE TABLE t2 (id int primary key, tenant varchar(20))")
+	tk.MustExec("INSERT INTO t2 VALUES (1, 'alice'), (2, 'bob')")
+	tk.MustExec("CREATE USER alice, rlsadmin")
+	tk.MustExec("GRANT ALL ON rls.* TO alice, rlsadmin")
+	tk.MustExec("SET tidb_enable_dynamic_privileges=1")
+	tk.MustExec("GRANT ROW_POLICY_ADMIN ON *.* TO rlsadmin")
+	tk.MustExec("CREATE POLICY tenant_isolation ON t USING (tenant = substring_index(current_user(), '@', 1))")
+	tk.MustExec("CREATE POLICY by_variable ON t2 USING (tenant = @tenant) WITH CHECK (id > 10)")
+
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.Se = newSession(c, s.store, s.dbName)
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{Username: "alice", Hostname: "localhost"}, nil, nil), IsTrue)
+	tk1.MustExec("USE rls")
+	// The users bound by the policies can't change them.
+	for _, sql := range []string{
+		"CREATE POLICY p ON t USING (true)",
+		"DROP POLICY tenant_isolation ON t",
+	} {
+		err := tk1.ExecToErr(sql)
+		c.Assert(terror.ErrorEqual(err, core.ErrSpecificAccessDenied), IsTrue, Commentf("%s", sql))
+	}
+	tk1.MustQuery("SELECT id, v FROM t ORDER BY id").Check(testkit.Rows("1 1", "3 3"))
+	tk1.MustQuery("SELECT count(*) FROM t").Check(testkit.Rows("2"))
+	tk1.MustQuery("SELECT * FROM t WHERE id = 2").Check(testkit.Rows())
+	tk1.MustQuery("SELECT * FROM t WHERE id IN (1, 2)").Check(testkit.Rows("1 alice 1"))
+	tk1.MustQuery("SELECT t1.id FROM t t1 JOIN t t2 ON t1.id = t2.id + 2").Check(testkit.Rows("3"))
+	tk1.MustExec("PREPARE stmt FROM 'SELECT id FROM t WHERE id = ?'")
+	tk1.MustExec("SET @id = 2")
+	tk1.MustQuery("EXECUTE stmt USING @id").Check(testkit.Rows())
+	tk1.MustQuery("EXECUTE stmt USING @id").Check(testkit.Rows())
+
+	// UPDATE and DELETE only see the visible rows, and the written rows must satisfy the policies.
+	tk1.MustExec("UPDATE t SET v = v + 10")
+	c.Assert(tk1.Se.AffectedRows(), Equals, uint64(2))
+	tk1.MustExec("DELETE FROM t WHERE id = 2")
+	c.Assert(tk1.Se.AffectedRows(), Equals, uint64(0))
+	tk1.MustExec("INSERT INTO t VALUES (4, 'alice', 4)")
+	for _, sql := range []string{
+		"INSERT INTO t VALUES (5, 'bob', 5)",
+		"INSERT INTO t VALUES (5, NULL, 5)",
+		"UPDATE t SET tenant = 'bob' WHERE id = 1",
+		"INSERT INTO t VALUES (1, 'alice', 1) ON DUPLICATE KEY UPDATE tenant = 'bob'",
+		"REPLACE INTO t VALUES (1, 'bob', 1)",
+	} {
+		err := tk1.ExecToErr(sql)
+		c.Assert(terror.ErrorEqual(err, executor.ErrRowPolicyViolated), IsTrue, Commentf("%s", sql))
+		c.Assert(err.Error(), Equals, "[executor:8239]New row violates row-level security policy for table 't'", Commentf("%s", sql))
+	}
+	tk1.MustExec("INSERT IGNORE INTO t VALUES (5, 'bob', 5)")
+	tk1.MustQuery("SHOW WARNINGS").Check(testkit.Rows("Warning 8239 New row violates row-level security policy for table 't'"))
+	tk.MustQuery("SELECT * FROM t ORDER BY id").Check(testkit.Rows("1 alice 11", "2 bob 2", "3 alice 13", "4 alice 4"))
+
+	// The predicates can reference the session variables, and WITH CHECK is checked instead of USING if it's set.
+	tk1.MustQuery("SELECT * FROM t2").Check(testkit.Rows())
+	tk1.MustExec("SET @tenant = 'bob'")
+	tk1.MustQuery("SELECT * FROM t2").Check(testkit.Rows("2 bob"))
+	tk1.MustExec("INSERT INTO t2 VALUES (11, 'carol')")
+	err := tk1.ExecToErr("INSERT INTO t2 VALUES (3, 'bob')")
+	c.Assert(err.Error(), Equals, "[executor:8239]New row violates row-level security policy for table 't2'")
+	// A row is visible if it satisfies any policy, and the predicates can contain subqueries.
+	tk.MustExec("CREATE POLICY by_subquery ON t2 USING (id IN (SELECT id + 10 FROM rls.t WHERE tenant = 'alice'))")
+	tk1.MustQuery("SELECT * FROM t2 ORDER BY id").Check(testkit.Rows("2 bob", "11 carol"))
+	tk1.MustExec("UPDATE t2 SET tenant = 'dave' WHERE id > 2")
+	c.Assert(tk1.Se.AffectedRows(), Equals, uint64(1))
+
+	// The users with ROW_POLICY_ADMIN or SUPER are exempted.
+	tk2 := testkit.NewTestKit(c, s.store)
+	tk2.Se = newSession(c, s.store, s.dbName)
+	c.Assert(tk2.Se.Auth(&auth.UserIdentity{Username: "rlsadmin", Hostname: "localhost"}, nil, nil), IsTrue)
+	tk2.MustQuery("SELECT count(*) FROM rls.t").Check(testkit.Rows("4"))
+	tk2.MustQuery("SELECT * FROM rls.t WHERE id = 2").Check(testkit.Rows("2 bob 2"))
+	tk2.MustExec("INSERT INTO rls.t VALUES (5, 'bob', 5)")
+	tk.MustQuery("SELECT * FROM rls.t2 ORDER BY id").Check(testkit.Rows("1 alice", "2 bob", "11 dave"))
+
+	// The policies belong to the table, they are kept by RENAME TABLE and not inherited by a new table with the same name.
+	tk.MustExec("RENAME TABLE t TO t3")
+	tk1.MustQuery("SELECT count(*) FROM t3").Check(testkit.Rows("3"))
+	tk.MustExec("CREATE TABLE t (id int primary key, tenant varchar(20), v int)")
+	tk.MustExec("INSERT INTO t VALUES (1, 'bob', 1)")
+	tk1.MustQuery("SELECT count(*) FROM t").Check(testkit.Rows("1"))
+	tk.MustExec("DROP TABLE t3")
+	tk.MustExec("CREATE TABLE t3 (id int primary key, tenant varchar(20), v int)")
+	tk.MustExec("INSERT INTO t3 VALUES (1, 'bob', 1)")
+	tk1.MustQuery("SELECT count(*) FROM t3").Check(testkit.Rows("1"))
+
+	tk.MustExec("DROP POLICY by_variable ON t2")
+	tk.MustExec("DROP POLICY by_subquery ON t2")
+	tk1.MustQuery("SELECT count(*) FROM t2").Check(testkit.Rows("3"))
+}
+
 func (s *testPrivilegeSuite) TestDropTablePriv(c *C) {
 	se := newSession(c, s.store, s.dbName)
 	ctx, _ := se.(sessionctx.Context)
@@ -784,6 +970,130 @@ func (s *testPrivilegeSuite) TestCheckAuthenticate(c *C) {
 	mustExec(c, se1, "drop user 'r3@example.com'@'localhost'")
 }
 
+func (s *testPrivilegeSuite) TestPasswordExpiration(c *C) {
+	rootTk := testkit.NewTestKit(c, s.store)
+	rootTk.MustExec(`CREATE USER 'expired'@'localhost' PASSWORD EXPIRE`)
+	rootTk.MustExec(`CREATE USER 'lifetime'@'localhost' PASSWORD EXPIRE INTERVAL 5 DAY`)
+	rootTk.MustExec(`CREATE USER 'lifetime2'@'localhost'`)
+	rootTk.MustExec(`GRANT CREATE USER ON *.* TO 'expired'@'localhost'`)
+	defer rootTk.MustExec(`DROP USER 'expired'@'localhost', 'lifetime'@'localhost', 'lifetime2'@'localhost'`)
+
+	// The session enters the sandbox mode, only the password can be reset.
+	tk := testkit.NewTestKit(c, s.store)
+	tk.Se = newSession(c, s.store, s.dbName)
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "expired", Hostname: "localhost"}, nil, nil), IsTrue)
+	c.Assert(tk.Se.GetSessionVars().InSandBoxMode, IsTrue)
+	_, err := tk.Exec("SELECT 1")
+	c.Assert(executor.ErrMustChangePassword.Equal(err), IsTrue, Commentf("err: %v", err))
+	_, _, _, err = tk.Se.PrepareStmt("SELECT 1")
+	c.Assert(executor.ErrMustChangePassword.Equal(err), IsTrue, Commentf("err: %v", err))
+	tk.MustExec("SET @a = 1")
+	_, err = tk.Exec("ALTER USER 'lifetime'@'localhost' IDENTIFIED BY 'abc'")
+	c.Assert(executor.ErrMustChangePassword.Equal(err), IsTrue, Commentf("err: %v", err))
+	tk.MustExec("ALTER USER USER() IDENTIFIED BY 'abc'")
+	c.Assert(tk.Se.GetSessionVars().InSandBoxMode, IsFalse)
+	tk.MustQuery("SELECT 1").Check(testkit.Rows("1"))
+	rootTk.MustQuery("SELECT Password_expired FROM mysql.user WHERE User = 'expired'").Check(testkit.Rows("N"))
+
+	// The password expires after its lifetime.
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "lifetime", Hostname: "localhost"}, nil, nil), IsTrue)
+	c.Assert(tk.Se.GetSessionVars().InSandBoxMode, IsFalse)
+	rootTk.MustExec("UPDATE mysql.user SET Password_last_changed = NOW() - INTERVAL 6 DAY WHERE User LIKE 'lifetime%'")
+	rootTk.MustExec("FLUSH PRIVILEGES")
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "lifetime", Hostname: "localhost"}, nil, nil), IsTrue)
+	c.Assert(tk.Se.GetSessionVars().InSandBoxMode, IsTrue)
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "lifetime2", Hostname: "localhost"}, nil, nil), IsTrue)
+	c.Assert(tk.Se.GetSessionVars().InSandBoxMode, IsFalse)
+
+	// The accounts without their own lifetime follow default_password_lifetime.
+	rootTk.MustExec("SET GLOBAL default_password_lifetime = 5")
+	defer rootTk.MustExec("SET GLOBAL default_password_lifetime = DEFAULT")
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "lifetime2", Hostname: "localhost"}, nil, nil), IsTrue)
+	c.Assert(tk.Se.GetSessionVars().InSandBoxMode, IsTrue)
+	rootTk.MustExec("ALTER USER 'lifetime2'@'localhost' PASSWORD EXPIRE NEVER")
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "lifetime2", Hostname: "localhost"}, nil, nil), IsTrue)
+	c.Assert(tk.Se.GetSessionVars().InSandBoxMode, IsFalse)
+
+	_, err = rootTk.Exec("ALTER USER 'lifetime2'@'localhost' PASSWORD EXPIRE INTERVAL 0 DAY")
+	c.Assert(err, NotNil)
+}
+
+func (s *testPrivilegeSuite) TestFailedLoginTracking(c *C) {
+	rootTk := testkit.NewTestKit(c, s.store)
+	rootTk.MustExec(`CREATE USER 'tracked'@'localhost' IDENTIFIED BY 'abc' FAILED_LOGIN_ATTEMPTS 2 PASSWORD_LOCK_TIME 1`)
+	defer rootTk.MustExec(`DROP USER 'tracked'@'localhost'`)
+	rootTk.MustQuery("SELECT Failed_login_attempts, Password_lock_time FROM mysql.user WHERE User = 'tracked'").Check(testkit.Rows("2 1"))
+
+	salt := []byte{85, 92, 45, 22, 58, 79, 107, 6, 122, 125, 58, 80, 12, 90, 103, 32, 90, 10, 74, 82}
+	authentication := []byte{24, 180, 183, 225, 166, 6, 81, 102, 70, 248, 199, 143, 91, 204, 169, 9, 161, 171, 203, 33}
+	wrongAuthentication := make([]byte, len(authentication))
+	tk := testkit.NewTestKit(c, s.store)
+	tk.Se = newSession(c, s.store, s.dbName)
+	login := func(authentication []byte) bool {
+		return tk.Se.Auth(&auth.UserIdentity{Username: "tracked", Hostname: "localhost"}, authentication, salt)
+	}
+	failures := func() *testkit.Result {
+		return rootTk.MustQuery("SELECT Failed_count, Locked_time IS NOT NULL FROM mysql.login_failures WHERE User = 'tracked'")
+	}
+
+	// A successful login resets the consecutive failed logins.
+	c.Assert(login(wrongAuthentication), IsFalse)
+	failures().Check(testkit.Rows("1 0"))
+	c.Assert(login(authentication), IsTrue)
+	failures().Check(testkit.Rows())
+
+	// The account is blocked even with the right password.
+	c.Assert(login(wrongAuthentication), IsFalse)
+	c.Assert(login(wrongAuthentication), IsFalse)
+	failures().Check(testkit.Rows("2 1"))
+	c.Assert(login(authentication), IsFalse)
+
+	// The account is unblocked after the lock time.
+	rootTk.MustExec("UPDATE mysql.login_failures SET Locked_time = NOW() - INTERVAL 2 DAY WHERE User = 'tracked'")
+	c.Assert(login(authentication), IsTrue)
+	failures().Check(testkit.Rows())
+
+	// An unbounded lock is only removed by unlocking the account.
+	rootTk.MustExec("ALTER USER 'tracked'@'localhost' PASSWORD_LOCK_TIME UNBOUNDED")
+	rootTk.MustQuery("SELECT Password_lock_time FROM mysql.user WHERE User = 'tracked'").Check(testkit.Rows("-1"))
+	c.Assert(login(wrongAuthentication), IsFalse)
+	c.Assert(login(wrongAuthentication), IsFalse)
+	rootTk.MustExec("UPDATE mysql.login_failures SET Locked_time = NOW() - INTERVAL 2 DAY WHERE User = 'tracked'")
+	c.Assert(login(authentication), IsFalse)
+	rootTk.MustExec("ALTER USER 'tracked'@'localhost' ACCOUNT UNLOCK")
+	failures().Check(testkit.Rows())
+	c.Assert(login(authentication), IsTrue)
+
+	// The concurrent failed logins are all counted.
+	rootTk.MustExec("ALTER USER 'tracked'@'localhost' FAILED_LOGIN_ATTEMPTS 100 PASSWORD_LOCK_TIME 1")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		se := newSession(c, s.store, s.dbName)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			se.Auth(&auth.UserIdentity{Username: "tracked", Hostname: "localhost"}, wrongAuthentication, salt)
+		}()
+	}
+	wg.Wait()
+	failures().Check(testkit.Rows("10 0"))
+	c.Assert(login(authentication), IsTrue)
+
+	// The failed logins are not tracked if the policy is disabled.
+	c.Assert(login(wrongAuthentication), IsFalse)
+	failures().Check(testkit.Rows("1 0"))
+	// Changing the policy resets the failed logins.
+	rootTk.MustExec("ALTER USER 'tracked'@'localhost' FAILED_LOGIN_ATTEMPTS 0")
+	failures().Check(testkit.Rows())
+	c.Assert(login(wrongAuthentication), IsFalse)
+	failures().Check(testkit.Rows())
+
+	_, err := rootTk.Exec("ALTER USER 'tracked'@'localhost' FAILED_LOGIN_ATTEMPTS 32768")
+	c.Assert(err, NotNil)
+	_, err = rootTk.Exec("ALTER USER 'tracked'@'localhost' PASSWORD_LOCK_TIME 32768")
+	c.Assert(err, NotNil)
+}
+
 func (s *testPrivilegeSuite) TestUseDB(c *C) {
 
 	se := newSession(c, s.store, s.dbName)
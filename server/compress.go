@@ -0,0 +1,295 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/config"
+)
+
+// clientZstdCompressionAlgorithm is the `CLIENT_ZSTD_COMPRESSION_ALGORITHM` capability flag,
+// the client sends the zstd compression level in the handshake response if it's set.
+const clientZstdCompressionAlgorithm uint32 = 1 << 26
+
+// The compression algorithms negotiated with the client.
+const (
+	compressionNone = iota
+	compressionZlib
+	compressionZstd
+)
+
+const (
+	// compressedHeaderSize is the size of the header of a compressed packet, it's made of
+	// the 3 bytes compressed length, the 1 byte sequence and the 3 bytes uncompressed length.
+	compressedHeaderSize = 7
+	// minCompressLength is the min length of the payload to be compressed, the smaller
+	// payloads are sent as is. It's the same as MySQL.
+	minCompressLength = 50
+)
+
+var (
+	zstdDecoderOnce sync.Once
+	zstdDecoder     *zstd.Decoder
+
+	zstdEncodersMu sync.Mutex
+	zstdEncoders   = make(map[zstd.EncoderLevel]*zstd.Encoder)
+)
+
+// getZstdDecoder returns the decoder shared by all the connections, it's safe to
+// call DecodeAll concurrently.
+// The uncompressed length of a packet is at most MaxPayloadLen, so the decoder rejects the
+// frames which are larger than it instead of allocating the memory for them. It's not
+// limited to the uncompressed length in the header because the window of a frame may be
+// larger than its content.
+func getZstdDecoder() *zstd.Decoder {
+	zstdDecoderOnce.Do(func() {
+		// It never fails with the valid options.
+		zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(mysql.MaxPayloadLen))
+	})
+	return zstdDecoder
+}
+
+// getZstdEncoder returns the encoder of the level shared by all the connections,
+// it's safe to call EncodeAll concurrently.
+func getZstdEncoder(level zstd.EncoderLevel) (*zstd.Encoder, error) {
+	zstdEncodersMu.Lock()
+	defer zstdEncodersMu.Unlock()
+	if encoder, ok := zstdEncoders[level]; ok {
+		return encoder, nil
+	}
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(level))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	zstdEncoders[level] = encoder
+	return encoder, nil
+}
+
+// negotiateCompression decides the compression algorithm and level of the connection
+// by the capability of the connection and the zstd level requested by the client.
+func negotiateCompression(capability uint32, zstdLevel int, cfg *config.ProtocolCompression) (algorithm int, level int) {
+	switch {
+	case capability&clientZstdCompressionAlgorithm > 0:
+		if zstdLevel < 1 || zstdLevel > 22 {
+			zstdLevel = cfg.ZstdLevel
+		}
+		return compressionZstd, zstdLevel
+	case capability&mysql.ClientCompress > 0:
+		return compressionZlib, cfg.ZlibLevel
+	}
+	return compressionNone, 0
+}
+
+// compressionCapability returns the capability flags of the allowed compression algorithms.
+func compressionCapability(cfg *config.ProtocolCompression) uint32 {
+	var capability uint32
+	for _, algorithm := range cfg.Algorithms {
+		switch algorithm {
+		case config.CompressionZlib:
+			capability |= mysql.ClientCompress
+		case config.CompressionZstd:
+			capability |= clientZstdCompressionAlgorithm
+		}
+	}
+	return capability
+}
+
+// compressedWriter wraps the plain packets into compressed packets.
+// See https://dev.mysql.com/doc/internals/en/compressed-packet-header.html
+type compressedWriter struct {
+	w         *bufio.Writer
+	algorithm int
+	sequence  *uint8
+
+	buf         bytes.Buffer
+	zlibWriter  *zlib.Writer
+	zstdEncoder *zstd.Encoder
+}
+
+func newCompressedWriter(w *bufio.Writer, algorithm int, level int, sequence *uint8) (*compressedWriter, error) {
+	cw := &compressedWriter{w: w, algorithm: algorithm, sequence: sequence}
+	var err error
+	switch algorithm {
+	case compressionZlib:
+		cw.zlibWriter, err = zlib.NewWriterLevel(&cw.buf, level)
+	case compressionZstd:
+		cw.zstdEncoder, err = getZstdEncoder(zstd.EncoderLevelFromZstd(level))
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cw, nil
+}
+
+// Write implements the io.Writer interface. The data is split into the compressed packets
+// whose payloads are at most MaxPayloadLen bytes before compression.
+func (cw *compressedWriter) Write(data []byte) (n int, err error) {
+	for len(data) > 0 {
+		length := len(data)
+		if length > mysql.MaxPayloadLen {
+			length = mysql.MaxPayloadLen
+		}
+		if err = cw.writeCompressedPacket(data[:length]); err != nil {
+			return n, err
+		}
+		n += length
+		data = data[length:]
+	}
+	return n, nil
+}
+
+func (cw *compressedWriter) writeCompressedPacket(payload []byte) error {
+	var header [compressedHeaderSize]byte
+	cw.buf.Reset()
+	uncompressedLength := 0
+	if len(payload) >= minCompressLength {
+		if err := cw.compress(payload); err != nil {
+			return err
+		}
+		uncompressedLength = len(payload)
+	}
+	// Send the payload as is if it's not compressed or the compressed one is not smaller.
+	compressed := cw.buf.Bytes()
+	if uncompressedLength == 0 || len(compressed) >= len(payload) {
+		compressed = payload
+		uncompressedLength = 0
+	}
+
+	header[0] = byte(len(compressed))
+	header[1] = byte(len(compressed) >> 8)
+	header[2] = byte(len(compressed) >> 16)
+	header[3] = *cw.sequence
+	header[4] = byte(uncompressedLength)
+	header[5] = byte(uncompressedLength >> 8)
+	header[6] = byte(uncompressedLength >> 16)
+	if _, err := cw.w.Write(header[:]); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := cw.w.Write(compressed); err != nil {
+		return errors.Trace(err)
+	}
+	*cw.sequence++
+	return nil
+}
+
+func (cw *compressedWriter) compress(payload []byte) error {
+	switch cw.algorithm {
+	case compressionZlib:
+		cw.zlibWriter.Reset(&cw.buf)
+		if _, err := cw.zlibWriter.Write(payload); err != nil {
+			return errors.Trace(err)
+		}
+		return errors.Trace(cw.zlibWriter.Close())
+	case compressionZstd:
+		cw.buf.Write(cw.zstdEncoder.EncodeAll(payload, nil))
+	}
+	return nil
+}
+
+func (cw *compressedWriter) flush() error {
+	return errors.Trace(cw.w.Flush())
+}
+
+// compressedReader unwraps the plain packets from the compressed packets.
+type compressedReader struct {
+	r         io.Reader
+	algorithm int
+	sequence  *uint8
+
+	// data is the uncompressed payload which is not read yet.
+	data       []byte
+	zlibReader io.ReadCloser
+}
+
+func newCompressedReader(r io.Reader, algorithm int, sequence *uint8) *compressedReader {
+	return &compressedReader{r: r, algorithm: algorithm, sequence: sequence}
+}
+
+// Read implements the io.Reader interface.
+func (cr *compressedReader) Read(buf []byte) (int, error) {
+	for len(cr.data) == 0 {
+		if err := cr.readCompressedPacket(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(buf, cr.data)
+	cr.data = cr.data[n:]
+	return n, nil
+}
+
+func (cr *compressedReader) readCompressedPacket() error {
+	var header [compressedHeaderSize]byte
+	if _, err := io.ReadFull(cr.r, header[:]); err != nil {
+		return errors.Trace(err)
+	}
+	sequence := header[3]
+	if sequence != *cr.sequence {
+		return errInvalidSequence.GenWithStack("invalid compressed sequence %d != %d", sequence, *cr.sequence)
+	}
+	*cr.sequence++
+
+	compressedLength := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	uncompressedLength := int(uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16)
+	payload := make([]byte, compressedLength)
+	if _, err := io.ReadFull(cr.r, payload); err != nil {
+		return errors.Trace(err)
+	}
+	// The payload is not compressed if the uncompressed length is 0.
+	if uncompressedLength == 0 {
+		cr.data = payload
+		return nil
+	}
+	data, err := cr.uncompress(payload, uncompressedLength)
+	if err != nil || len(data) != uncompressedLength {
+		return errNetUncompress.GenWithStackByArgs()
+	}
+	cr.data = data
+	return nil
+}
+
+func (cr *compressedReader) uncompress(payload []byte, uncompressedLength int) ([]byte, error) {
+	switch cr.algorithm {
+	case compressionZlib:
+		var err error
+		if cr.zlibReader == nil {
+			cr.zlibReader, err = zlib.NewReader(bytes.NewReader(payload))
+		} else {
+			err = cr.zlibReader.(zlib.Resetter).Reset(bytes.NewReader(payload), nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Read one more byte to check the payload doesn't decompress to more data than the header says.
+		data := make([]byte, uncompressedLength+1)
+		n, err := io.ReadFull(cr.zlibReader, data)
+		if err == nil {
+			return nil, errors.Errorf("the uncompressed payload is longer than %d bytes", uncompressedLength)
+		}
+		if err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, err
+		}
+		return data[:n], nil
+	case compressionZstd:
+		return getZstdDecoder().DecodeAll(payload, make([]byte, 0, uncompressedLength))
+	}
+	return nil, errors.Errorf("unknown compression algorithm %d", cr.algorithm)
+}
@@ -37,8 +37,13 @@ package server
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/binary"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"net"
@@ -96,6 +101,19 @@ const (
 	connStatusWaitShutdown // Notified by server to close.
 )
 
+// authClearPassword is the client side plugin which sends the password in plain text.
+const authClearPassword = "mysql_clear_password"
+
+// authMoreData is the header of the extra data packets of the authentication exchange.
+const authMoreData byte = 0x01
+
+// The packets of the caching_sha2_password authentication exchange after the scramble.
+const (
+	cachingSha2RequestPublicKey byte = 0x02
+	cachingSha2FastAuthSuccess  byte = 0x03
+	cachingSha2PerformFullAuth  byte = 0x04
+)
+
 var (
 	queryTotalCountOk = [...]prometheus.Counter{
 		mysql.ComSleep:            metrics.QueryTotalCounter.WithLabelValues("Sleep", "OK"),
@@ -183,6 +201,7 @@ type clientConn struct {
 	status       int32             // dispatching/reading/shutdown/waitshutdown
 	lastCode     uint16            // last error code
 	collation    uint8             // collation used by client, may be different from the collation used by database.
+	zstdLevel    int               // zstd compression level requested by client.
 	lastActive   time.Time
 
 	// mu is used for cancelling the execution of current transaction.
@@ -200,14 +219,15 @@ func (cc *clientConn) String() string {
 }
 
 // authSwitchRequest is used when the client asked to speak something
-// other than mysql_native_password. The server is allowed to ask
-// the client to switch, so lets ask for mysql_native_password
+// other than the plugin we need. The server is allowed to ask
+// the client to switch, so lets ask for mysql_native_password, or the
+// client side plugin required by the authentication plugin of the user.
 // https://dev.mysql.com/doc/internals/en/connection-phase-packets.html#packet-Protocol::AuthSwitchRequest
-func (cc *clientConn) authSwitchRequest(ctx context.Context) ([]byte, error) {
-	enclen := 1 + len(mysql.AuthNativePassword) + 1 + len(cc.salt) + 1
+func (cc *clientConn) authSwitchRequest(ctx context.Context, authPlugin string) ([]byte, error) {
+	enclen := 1 + len(authPlugin) + 1 + len(cc.salt) + 1
 	data := cc.alloc.AllocWithLen(4, enclen)
 	data = append(data, mysql.AuthSwitchRequest) // switch request
-	data = append(data, []byte(mysql.AuthNativePassword)...)
+	data = append(data, []byte(authPlugin)...)
 	data = append(data, byte(0x00)) // requires null
 	data = append(data, cc.salt...)
 	data = append(data, 0)
@@ -287,7 +307,10 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 		logutil.Logger(ctx).Debug("flush response to client failed", zap.Error(err))
 		return err
 	}
-	return err
+
+	// The packets following the handshake are compressed if the compression is negotiated.
+	algorithm, level := negotiateCompression(cc.capability, cc.zstdLevel, &config.GetGlobalConfig().ProtocolCompression)
+	return cc.pkt.setCompression(algorithm, level)
 }
 
 func (cc *clientConn) Close() error {
@@ -349,7 +372,7 @@ func (cc *clientConn) writeInitialHandshake(ctx context.Context) error {
 	data = append(data, cc.salt[8:]...)
 	data = append(data, 0)
 	// auth-plugin name
-	data = append(data, []byte(mysql.AuthNativePassword)...)
+	data = append(data, []byte(variable.GetSysVar(variable.DefaultAuthPlugin).Value)...)
 	data = append(data, 0)
 	err := cc.writePacket(data)
 	if err != nil {
@@ -394,6 +417,7 @@ type handshakeResponse41 struct {
 	Auth       []byte
 	AuthPlugin string
 	Attrs      map[string]string
+	ZstdLevel  int
 }
 
 // parseOldHandshakeResponseHeader parses the old version handshake header HandshakeResponse320
@@ -536,15 +560,20 @@ func parseHandshakeResponseBody(ctx context.Context, packet *handshakeResponse41
 		if num, null, off := parseLengthEncodedInt(data[offset:]); !null {
 			offset += off
 			row := data[offset : offset+int(num)]
+			offset += int(num)
 			attrs, err := parseAttrs(row)
 			if err != nil {
 				logutil.Logger(ctx).Warn("parse attrs failed", zap.Error(err))
-				return nil
+			} else {
+				packet.Attrs = attrs
 			}
-			packet.Attrs = attrs
 		}
 	}
 
+	if packet.Capability&clientZstdCompressionAlgorithm > 0 && len(data[offset:]) > 0 {
+		packet.ZstdLevel = int(data[offset])
+	}
+
 	return nil
 }
 
@@ -644,21 +673,14 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 		return err
 	}
 
-	// switching from other methods should work, but not tested
-	if resp.AuthPlugin == mysql.AuthCachingSha2Password {
-		resp.Auth, err = cc.authSwitchRequest(ctx)
-		if err != nil {
-			logutil.Logger(ctx).Warn("attempt to send auth switch request packet failed", zap.Error(err))
-			return err
-		}
-	}
 	cc.capability = resp.Capability & cc.server.capability
 	cc.user = resp.User
 	cc.dbname = resp.DBName
 	cc.collation = resp.Collation
 	cc.attrs = resp.Attrs
+	cc.zstdLevel = resp.ZstdLevel
 
-	err = cc.openSessionAndDoAuth(resp.Auth)
+	err = cc.openSessionAndDoAuth(ctx, resp.Auth, resp.AuthPlugin)
 	if err != nil {
 		logutil.Logger(ctx).Warn("open new session failure", zap.Error(err))
 	}
@@ -679,7 +701,7 @@ func (cc *clientConn) SessionStatusToString() string {
 	)
 }
 
-func (cc *clientConn) openSessionAndDoAuth(authData []byte) error {
+func (cc *clientConn) openSessionAndDoAuth(ctx context.Context, authData []byte, authPlugin string) error {
 	var tlsStatePtr *tls.ConnectionState
 	if cc.tlsConn != nil {
 		tlsState := cc.tlsConn.ConnectionState()
@@ -702,9 +724,19 @@ func (cc *clientConn) openSessionAndDoAuth(authData []byte) error {
 	if err != nil {
 		return err
 	}
+	authData, err = cc.switchToUserAuthPlugin(ctx, authData, authPlugin, host)
+	if err != nil {
+		return err
+	}
 	if !cc.ctx.Auth(&auth.UserIdentity{Username: cc.user, Hostname: host}, authData, cc.salt) {
 		return errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
 	}
+	// The session enters the sandbox mode if the password has expired, the clients which can't handle
+	// it are disconnected unless disconnect_on_expired_password is disabled.
+	if cc.ctx.GetSessionVars().InSandBoxMode && cc.capability&clientCanHandleExpiredPasswords == 0 &&
+		variable.TiDBOptOn(variable.GetSysVar(variable.DisconnectOnExpiredPassword).Value) {
+		return errMustChangePasswordLogin
+	}
 	cc.ctx.SetPort(port)
 	if cc.dbname != "" {
 		err = cc.useDB(context.Background(), cc.dbname)
@@ -716,6 +748,124 @@ func (cc *clientConn) openSessionAndDoAuth(authData []byte) error {
 	return nil
 }
 
+// switchToUserAuthPlugin sends an auth-switch request if the user is authenticated by an authentication
+// plugin which requires a different client side plugin, and returns the authentication data for it.
+func (cc *clientConn) switchToUserAuthPlugin(ctx context.Context, authData []byte, clientPlugin, host string) ([]byte, error) {
+	pm := privilege.GetPrivilegeManager(cc.ctx.Session)
+	if pm == nil {
+		return authData, nil
+	}
+	requiredPlugin := mysql.AuthNativePassword
+	switch userPlugin := pm.GetAuthPlugin(cc.user, host); userPlugin {
+	case "", mysql.AuthNativePassword, mysql.AuthCachingSha2Password:
+		// The users who don't exist are authenticated by mysql_native_password and rejected.
+		if userPlugin != "" {
+			requiredPlugin = userPlugin
+		}
+	default:
+		authManifest := plugin.GetAuthentication(userPlugin)
+		if authManifest == nil {
+			// The connection will be rejected during auth.
+			return authData, nil
+		}
+		if authManifest.ClientPluginName != "" {
+			requiredPlugin = authManifest.ClientPluginName
+		}
+	}
+	if requiredPlugin == authClearPassword && cc.tlsConn == nil && !cc.server.isUnixSocket() {
+		// Never let the client send the password in plain text over an insecure connection.
+		logutil.Logger(ctx).Warn("refuse to authenticate by mysql_clear_password over an insecure connection",
+			zap.String("user", cc.user), zap.String("host", host))
+		return nil, errAccessDenied.FastGenByArgs(cc.user, host, "YES")
+	}
+	if clientPlugin == "" {
+		clientPlugin = mysql.AuthNativePassword
+	}
+	if clientPlugin != requiredPlugin {
+		resp, err := cc.authSwitchRequest(ctx, requiredPlugin)
+		if err != nil {
+			logutil.Logger(ctx).Warn("attempt to send auth switch request packet failed", zap.Error(err))
+			return nil, err
+		}
+		authData = resp
+		if requiredPlugin == authClearPassword {
+			// mysql_clear_password sends the password as a null terminated string.
+			authData = bytes.TrimSuffix(authData, []byte{0})
+		}
+	}
+	if requiredPlugin == mysql.AuthCachingSha2Password {
+		return cc.authCachingSha2(ctx, pm, authData, host)
+	}
+	return authData, nil
+}
+
+// authCachingSha2 finishes the caching_sha2_password authentication exchange after the client sends the scramble.
+// The fast authentication succeeds if the scramble matches the password digest cached by the previous full
+// authentication, otherwise the client sends the password over the secure connection, or encrypted by the RSA
+// public key of the server. It returns the scramble or the password to be verified.
+// See https://dev.mysql.com/doc/dev/mysql-server/latest/page_caching_sha2_authentication_exchanges.html
+func (cc *clientConn) authCachingSha2(ctx context.Context, pm privilege.Manager, scramble []byte, host string) ([]byte, error) {
+	if len(scramble) == 0 {
+		// The client sends nothing for the empty password.
+		return scramble, nil
+	}
+	if pm.CachingSha2FastAuth(cc.user, host, scramble, cc.salt) {
+		if err := cc.writeAuthMoreData(ctx, []byte{cachingSha2FastAuthSuccess}); err != nil {
+			return nil, err
+		}
+		return scramble, nil
+	}
+	if err := cc.writeAuthMoreData(ctx, []byte{cachingSha2PerformFullAuth}); err != nil {
+		return nil, err
+	}
+	resp, err := cc.readPacket()
+	if err != nil {
+		return nil, err
+	}
+	if cc.tlsConn != nil || cc.server.isUnixSocket() {
+		// The password is sent in plain text over the secure connection.
+		return bytes.TrimSuffix(resp, []byte{0}), nil
+	}
+	key, err := cc.server.cachingSha2RSAKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) == 1 && resp[0] == cachingSha2RequestPublicKey {
+		pubKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		if err = cc.writeAuthMoreData(ctx, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubKey})); err != nil {
+			return nil, err
+		}
+		if resp, err = cc.readPacket(); err != nil {
+			return nil, err
+		}
+	}
+	// The password is null terminated and XORed with the salt before encryption.
+	pwd, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, key, resp, nil)
+	if err != nil {
+		logutil.Logger(ctx).Warn("decrypt caching_sha2_password password failed", zap.Error(err))
+		return nil, errAccessDenied.FastGenByArgs(cc.user, host, "YES")
+	}
+	for i := range pwd {
+		pwd[i] ^= cc.salt[i%len(cc.salt)]
+	}
+	return bytes.TrimSuffix(pwd, []byte{0}), nil
+}
+
+// writeAuthMoreData writes the extra data of the authentication exchange to the client.
+func (cc *clientConn) writeAuthMoreData(ctx context.Context, moreData []byte) error {
+	data := cc.alloc.AllocWithLen(4, 4+1+len(moreData))
+	data = append(data, authMoreData)
+	data = append(data, moreData...)
+	if err := cc.writePacket(data); err != nil {
+		logutil.Logger(ctx).Debug("write response to client failed", zap.Error(err))
+		return err
+	}
+	return cc.flush(ctx)
+}
+
 func (cc *clientConn) PeerHost(hasPassword string) (host, port string, err error) {
 	if len(cc.peerHost) > 0 {
 		return cc.peerHost, "", nil
@@ -888,6 +1038,7 @@ func (cc *clientConn) Run(ctx context.Context) {
 		}
 		cc.addMetrics(data[0], startTime, err)
 		cc.pkt.sequence = 0
+		cc.pkt.compressedSequence = 0
 	}
 }
 
@@ -1481,28 +1632,34 @@ func (cc *clientConn) handleLoadStats(ctx context.Context, loadStatsInfo *execut
 }
 
 // handleIndexAdvise does the index advise work and returns the advise result for index.
-func (cc *clientConn) handleIndexAdvise(ctx context.Context, indexAdviseInfo *executor.IndexAdviseInfo) error {
-	if cc.capability&mysql.ClientLocalFiles == 0 {
-		return errNotAllowedCommand
-	}
+func (cc *clientConn) handleIndexAdvise(ctx context.Context, indexAdviseInfo *executor.IndexAdviseInfo, status uint16) error {
 	if indexAdviseInfo == nil {
 		return errors.New("Index Advise: info is empty")
 	}
 
-	data, err := cc.getDataFromPath(ctx, indexAdviseInfo.Path)
-	if err != nil {
-		return err
-	}
-	if len(data) == 0 {
-		return errors.New("Index Advise: infile is empty")
+	var data []byte
+	if !indexAdviseInfo.FromStmtSummary {
+		if cc.capability&mysql.ClientLocalFiles == 0 {
+			return errNotAllowedCommand
+		}
+		var err error
+		data, err = cc.getDataFromPath(ctx, indexAdviseInfo.Path)
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return errors.New("Index Advise: infile is empty")
+		}
 	}
 
 	if err := indexAdviseInfo.GetIndexAdvice(ctx, data); err != nil {
 		return err
 	}
 
-	// TODO: Write the rss []ResultSet. It will be done in another PR.
-	return nil
+	rs := &tidbResultSet{recordSet: indexAdviseInfo.Result}
+	defer terror.Call(rs.Close)
+	_, err := cc.writeResultset(ctx, rs, false, status, 0)
+	return err
 }
 
 // handleQuery executes the sql query string and writes result set or result ok to the client.
@@ -1761,11 +1918,9 @@ func (cc *clientConn) handleQuerySpecial(ctx context.Context, status uint16) (bo
 
 	indexAdvise := cc.ctx.Value(executor.IndexAdviseVarKey)
 	if indexAdvise != nil {
-		handled = true
+		// The index advice is written as a result set instead of an OK packet.
 		defer cc.ctx.SetValue(executor.IndexAdviseVarKey, nil)
-		if err := cc.handleIndexAdvise(ctx, indexAdvise.(*executor.IndexAdviseInfo)); err != nil {
-			return handled, err
-		}
+		return true, cc.handleIndexAdvise(ctx, indexAdvise.(*executor.IndexAdviseInfo), status)
 	}
 	return handled, cc.writeOkWith(ctx, cc.ctx.LastMessage(), cc.ctx.AffectedRows(), cc.ctx.LastInsertID(), status, cc.ctx.WarningCount())
 }
@@ -2037,7 +2192,7 @@ func (cc *clientConn) handleChangeUser(ctx context.Context, data []byte) error {
 	if err != nil {
 		logutil.Logger(ctx).Debug("close old context failed", zap.Error(err))
 	}
-	err = cc.openSessionAndDoAuth(pass)
+	err = cc.openSessionAndDoAuth(ctx, pass, "")
 	if err != nil {
 		return err
 	}
@@ -20,6 +20,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"strconv"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
@@ -29,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -202,6 +204,69 @@ func (ts *ConnTestSuite) TestAuthSwitchRequest(c *C) {
 	c.Assert(resp.AuthPlugin == "caching_sha2_password", IsTrue)
 }
 
+func (ts *ConnTestSuite) TestClearPasswordAuthSwitch(c *C) {
+	pluginName, pluginVersion := "tidb_test_clear_auth", uint16(1)
+	plugin.SetTestHook(func(p *plugin.Plugin, dir string, pluginID plugin.ID) (manifest func() *plugin.Manifest, err error) {
+		return func() *plugin.Manifest {
+			m := &plugin.AuthenticationManifest{
+				Manifest: plugin.Manifest{
+					Kind:    plugin.Authentication,
+					Name:    pluginName,
+					Version: pluginVersion,
+					OnInit: func(ctx context.Context, manifest *plugin.Manifest) error {
+						return nil
+					},
+				},
+				ClientPluginName: authClearPassword,
+				GenerateAuthenticationString: func(password string) (string, error) {
+					return password, nil
+				},
+			}
+			return plugin.ExportManifest(m)
+		}, nil
+	})
+	pluginCfg := plugin.Config{Plugins: []string{pluginName + "-" + strconv.Itoa(int(pluginVersion))}}
+	c.Assert(plugin.Load(context.Background(), pluginCfg), IsNil)
+	c.Assert(plugin.Init(context.Background(), pluginCfg), IsNil)
+	defer plugin.Shutdown(context.Background())
+
+	se, err := session.CreateSession4Test(ts.store)
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKitWithSession(c, ts.store, se)
+	tk.MustExec("CREATE USER 'clear_auth'@'%' IDENTIFIED WITH 'tidb_test_clear_auth' BY 'abc'")
+	defer tk.MustExec("DROP USER 'clear_auth'@'%'")
+
+	cfg := newTestConfig()
+	var inBuffer, outBuffer bytes.Buffer
+	cc := &clientConn{
+		connectionID: 1,
+		salt:         []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x10, 0x11, 0x12, 0x13, 0x14},
+		server:       &Server{cfg: cfg, capability: defaultCapability},
+		pkt: &packetIO{
+			bufReadConn: newBufferedReadConn(&bytesConn{inBuffer}),
+			bufWriter:   bufio.NewWriter(&outBuffer),
+		},
+		alloc: arena.NewAllocator(512),
+		ctx:   &TiDBContext{Session: se, stmts: make(map[int]*TiDBStatement)},
+		user:  "clear_auth",
+	}
+
+	// The password is never asked for in plain text over TCP without TLS.
+	_, err = cc.switchToUserAuthPlugin(context.Background(), []byte{0x01}, mysql.AuthNativePassword, "127.0.0.1")
+	c.Assert(errAccessDenied.Equal(err), IsTrue, Commentf("err %v", err))
+	c.Assert(outBuffer.Len(), Equals, 0)
+
+	// The auth switch request is sent over the unix socket.
+	cfg.Socket, cfg.Port = "/tmp/tidb-test.sock", 0
+	inBuffer.Write([]byte{0x04, 0x00, 0x00, 0x01, 'a', 'b', 'c', 0x00})
+	cc.pkt.bufReadConn = newBufferedReadConn(&bytesConn{inBuffer})
+	authData, err := cc.switchToUserAuthPlugin(context.Background(), []byte{0x01}, mysql.AuthNativePassword, "127.0.0.1")
+	c.Assert(err, IsNil)
+	c.Assert(authData, DeepEquals, []byte("abc"))
+	c.Assert(outBuffer.Bytes()[4], Equals, mysql.AuthSwitchRequest)
+	c.Assert(bytes.HasPrefix(outBuffer.Bytes()[5:], []byte(authClearPassword+"\x00")), IsTrue)
+}
+
 func (ts *ConnTestSuite) TestInitialHandshake(c *C) {
 	c.Parallel()
 	var outBuffer bytes.Buffer
@@ -58,6 +58,12 @@ type packetIO struct {
 	bufWriter   *bufio.Writer
 	sequence    uint8
 	readTimeout time.Duration
+
+	// compressedSequence is the sequence of the compressed packets, the sequence of the
+	// packets wrapped in them is not checked as MySQL does.
+	compressedSequence uint8
+	compressedReader   *compressedReader
+	compressedWriter   *compressedWriter
 }
 
 func newPacketIO(bufReadConn *bufferedReadConn) *packetIO {
@@ -75,23 +81,43 @@ func (p *packetIO) setReadTimeout(timeout time.Duration) {
 	p.readTimeout = timeout
 }
 
+// setCompression enables the compressed protocol with the algorithm and level,
+// the pending data must be flushed before calling it.
+func (p *packetIO) setCompression(algorithm int, level int) error {
+	if algorithm == compressionNone {
+		return nil
+	}
+	compressedWriter, err := newCompressedWriter(p.bufWriter, algorithm, level, &p.compressedSequence)
+	if err != nil {
+		return err
+	}
+	p.compressedWriter = compressedWriter
+	p.compressedReader = newCompressedReader(p.bufReadConn, algorithm, &p.compressedSequence)
+	p.bufWriter = bufio.NewWriterSize(compressedWriter, defaultWriterSize)
+	return nil
+}
+
 func (p *packetIO) readOnePacket() ([]byte, error) {
 	var header [4]byte
+	var r io.Reader = p.bufReadConn
+	if p.compressedReader != nil {
+		r = p.compressedReader
+	}
 	if p.readTimeout > 0 {
 		if err := p.bufReadConn.SetReadDeadline(time.Now().Add(p.readTimeout)); err != nil {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, header[:]); err != nil {
+	if _, err := io.ReadFull(r, header[:]); err != nil {
 		return nil, errors.Trace(err)
 	}
 
 	sequence := header[3]
-	if sequence != p.sequence {
+	if sequence != p.sequence && p.compressedReader == nil {
 		return nil, errInvalidSequence.GenWithStack("invalid sequence %d != %d", sequence, p.sequence)
 	}
 
-	p.sequence++
+	p.sequence = sequence + 1
 
 	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
 
@@ -101,7 +127,7 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, data); err != nil {
+	if _, err := io.ReadFull(r, data); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return data, nil
@@ -185,5 +211,12 @@ func (p *packetIO) flush() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if p.compressedWriter != nil {
+		if err = p.compressedWriter.flush(); err != nil {
+			return errors.Trace(err)
+		}
+		// MySQL syncs the sequence with the compressed one after flushing.
+		p.sequence = p.compressedSequence
+	}
 	return err
 }
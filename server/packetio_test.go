@@ -19,6 +19,7 @@ import (
 	"net"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/parser/mysql"
 )
@@ -89,6 +90,68 @@ func (s *PacketIOTestSuite) TestRead(c *C) {
 	c.Assert(bytes[mysql.MaxPayloadLen], DeepEquals, byte(0x0a))
 }
 
+func (s *PacketIOTestSuite) TestCompressedReadWrite(c *C) {
+	for _, algorithm := range []int{compressionZlib, compressionZstd} {
+		var outBuffer bytes.Buffer
+		pkt := &packetIO{bufWriter: bufio.NewWriter(&outBuffer)}
+		c.Assert(pkt.setCompression(algorithm, 3), IsNil)
+		// A small packet which is not compressed.
+		c.Assert(pkt.writePacket([]byte{0x00, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03}), IsNil)
+		// A compressible packet larger than MaxPayloadLen.
+		largeInput := bytes.Repeat([]byte{0x0a}, mysql.MaxPayloadLen+100)
+		c.Assert(pkt.writePacket(append(make([]byte, 4), largeInput...)), IsNil)
+		c.Assert(pkt.flush(), IsNil)
+		c.Assert(pkt.compressedSequence, Equals, uint8(3))
+		c.Assert(pkt.sequence, Equals, uint8(3))
+		c.Assert(outBuffer.Len() < len(largeInput), IsTrue)
+
+		brc := newBufferedReadConn(&bytesConn{outBuffer})
+		pkt = newPacketIO(brc)
+		c.Assert(pkt.setCompression(algorithm, 3), IsNil)
+		data, err := pkt.readPacket()
+		c.Assert(err, IsNil)
+		c.Assert(data, DeepEquals, []byte{0x01, 0x02, 0x03})
+		data, err = pkt.readPacket()
+		c.Assert(err, IsNil)
+		c.Assert(data, DeepEquals, largeInput)
+		c.Assert(pkt.compressedSequence, Equals, uint8(3))
+
+		// The sequence of the compressed packets is checked.
+		outBuffer.Reset()
+		pkt = &packetIO{bufWriter: bufio.NewWriter(&outBuffer)}
+		c.Assert(pkt.setCompression(algorithm, 3), IsNil)
+		pkt.compressedSequence = 1
+		c.Assert(pkt.writePacket([]byte{0x00, 0x00, 0x00, 0x00, 0x01}), IsNil)
+		c.Assert(pkt.flush(), IsNil)
+		pkt = newPacketIO(newBufferedReadConn(&bytesConn{outBuffer}))
+		c.Assert(pkt.setCompression(algorithm, 3), IsNil)
+		_, err = pkt.readPacket()
+		c.Assert(errInvalidSequence.Equal(err), IsTrue)
+
+		// The payload can't be uncompressed to more data than the header says.
+		outBuffer.Reset()
+		pkt = &packetIO{bufWriter: bufio.NewWriter(&outBuffer)}
+		c.Assert(pkt.setCompression(algorithm, 3), IsNil)
+		c.Assert(pkt.writePacket(make([]byte, 4+1000)), IsNil)
+		c.Assert(pkt.flush(), IsNil)
+		compressed := outBuffer.Bytes()
+		c.Assert(int(compressed[4])|int(compressed[5])<<8|int(compressed[6])<<16, Equals, 4+1000)
+		compressed[4], compressed[5], compressed[6] = 100, 0, 0
+		pkt = newPacketIO(newBufferedReadConn(&bytesConn{outBuffer}))
+		c.Assert(pkt.setCompression(algorithm, 3), IsNil)
+		_, err = pkt.readPacket()
+		c.Assert(errNetUncompress.Equal(err), IsTrue)
+	}
+
+	// A zstd frame larger than any packet is rejected without being uncompressed.
+	encoder, err := getZstdEncoder(zstd.SpeedDefault)
+	c.Assert(err, IsNil)
+	payload := encoder.EncodeAll(make([]byte, mysql.MaxPayloadLen+1), nil)
+	cr := newCompressedReader(nil, compressionZstd, nil)
+	_, err = cr.uncompress(payload, mysql.MaxPayloadLen)
+	c.Assert(err, Equals, zstd.ErrDecoderSizeExceeded)
+}
+
 type bytesConn struct {
 	b bytes.Buffer
 }
@@ -30,7 +30,11 @@ package server
 
 import (
 	"context"
+	crand "crypto/rand"
+	"crypto/rsa"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"flag"
 	"fmt"
 	"io"
@@ -101,15 +105,22 @@ var (
 	errSecureTransportRequired = dbterror.ClassServer.NewStd(errno.ErrSecureTransportRequired)
 	errMultiStatementDisabled  = dbterror.ClassServer.NewStd(errno.ErrMultiStatementDisabled)
 	errNewAbortingConnection   = dbterror.ClassServer.NewStd(errno.ErrNewAbortingConnection)
+	errNetUncompress           = dbterror.ClassServer.NewStd(errno.ErrNetUncompress)
+	errMustChangePasswordLogin = dbterror.ClassServer.NewStd(errno.ErrMustChangePasswordLogin)
 )
 
+// clientCanHandleExpiredPasswords is the capability flag of the clients which can handle the sandbox mode
+// of expired passwords, it's not defined by the parser.
+const clientCanHandleExpiredPasswords uint32 = 1 << 22
+
 // DefaultCapability is the capability of the server when it is created using the default configuration.
 // When server is configured with SSL, the server will have extra capabilities compared to DefaultCapability.
 const defaultCapability = mysql.ClientLongPassword | mysql.ClientLongFlag |
 	mysql.ClientConnectWithDB | mysql.ClientProtocol41 |
 	mysql.ClientTransactions | mysql.ClientSecureConnection | mysql.ClientFoundRows |
 	mysql.ClientMultiStatements | mysql.ClientMultiResults | mysql.ClientLocalFiles |
-	mysql.ClientConnectAtts | mysql.ClientPluginAuth | mysql.ClientInteractive
+	mysql.ClientConnectAtts | mysql.ClientPluginAuth | mysql.ClientInteractive |
+	clientCanHandleExpiredPasswords
 
 // Server is the MySQL protocol server
 type Server struct {
@@ -125,6 +136,10 @@ type Server struct {
 	dom               *domain.Domain
 	globalConnID      util.GlobalConnID
 
+	rsaKeyOnce sync.Once
+	rsaKey     *rsa.PrivateKey
+	rsaKeyErr  error
+
 	statusAddr     string
 	statusListener net.Listener
 	statusServer   *http.Server
@@ -246,7 +261,7 @@ func NewServer(cfg *config.Config, driver IDriver) (*Server, error) {
 
 	setSystemTimeZoneVariable()
 
-	s.capability = defaultCapability
+	s.capability = defaultCapability | compressionCapability(&s.cfg.ProtocolCompression)
 	if s.tlsConfig != nil {
 		s.capability |= mysql.ClientSSL
 	}
@@ -614,6 +629,47 @@ func (s *Server) getTLSConfig() *tls.Config {
 	return (*tls.Config)(atomic.LoadPointer(&s.tlsConfig))
 }
 
+// cachingSha2RSAKey returns the RSA key pair used by caching_sha2_password to exchange the password over
+// insecure connections. A temporary key is generated if [security]caching-sha2-password-private-key is not set.
+func (s *Server) cachingSha2RSAKey() (*rsa.PrivateKey, error) {
+	s.rsaKeyOnce.Do(func() {
+		keyPath := s.cfg.Security.CachingSha2PrivateKey
+		if keyPath == "" {
+			s.rsaKey, s.rsaKeyErr = rsa.GenerateKey(crand.Reader, 2048)
+			return
+		}
+		s.rsaKey, s.rsaKeyErr = loadRSAPrivateKey(keyPath)
+		if s.rsaKeyErr != nil {
+			logutil.BgLogger().Error("load caching_sha2_password private key fail", zap.String("path", keyPath), zap.Error(s.rsaKeyErr))
+		}
+	})
+	return s.rsaKey, s.rsaKeyErr
+}
+
+// loadRSAPrivateKey loads the RSA private key from the PEM file in PKCS #1 or PKCS #8 form.
+func loadRSAPrivateKey(keyPath string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("no PEM data is found in %s", keyPath)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("the private key in %s is not a RSA key", keyPath)
+	}
+	return rsaKey, nil
+}
+
 func killConn(conn *clientConn) {
 	sessVars := conn.ctx.GetSessionVars()
 	atomic.StoreUint32(&sessVars.Killed, 1)
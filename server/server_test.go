@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -1605,6 +1606,40 @@ func (cli *testServerClient) runTestAuth(c *C) {
 	})
 }
 
+func (cli *testServerClient) runTestExpiredPassword(c *C) {
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec(`CREATE USER 'expiredtest'@'%' IDENTIFIED BY '123' PASSWORD EXPIRE;`)
+	})
+	db, err := sql.Open("mysql", cli.getDSN(func(config *mysql.Config) {
+		config.User = "expiredtest"
+		config.Passwd = "123"
+	}))
+	c.Assert(err, IsNil)
+	defer func() {
+		err := db.Close()
+		c.Assert(err, IsNil)
+	}()
+
+	// The client doesn't support expired passwords, so it's disconnected.
+	err = db.Ping()
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "Error 1862: Your password has expired. To log in you must change it using a client that supports expired passwords.")
+}
+
+func (cli *testServerClient) runTestCachingSha2Password(c *C, user, passwd string, overriders ...configOverrider) error {
+	db, err := sql.Open("mysql", cli.getDSN(append([]configOverrider{func(config *mysql.Config) {
+		config.User = user
+		config.Passwd = passwd
+		config.DBName = ""
+	}}, overriders...)...))
+	c.Assert(err, IsNil)
+	defer func() {
+		err := db.Close()
+		c.Assert(err, IsNil)
+	}()
+	return db.Ping()
+}
+
 func (cli *testServerClient) runTestIssue3662(c *C) {
 	db, err := sql.Open("mysql", cli.getDSN(func(config *mysql.Config) {
 		config.DBName = "non_existing_schema"
@@ -2079,3 +2114,91 @@ func (cli *testServerClient) runTestInfoschemaClientErrors(t *C) {
 
 	})
 }
+
+// runTestCompressedProtocol connects to the server with a raw client which negotiates
+// the compression by the capability, and reads a large result set through the compressed packets.
+func (cli *testServerClient) runTestCompressedProtocol(c *C, capability uint32) {
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec("drop table if exists t_compress")
+		dbt.mustExec("create table t_compress(a int primary key)")
+		for i := 0; i < 200; i++ {
+			dbt.mustExec(fmt.Sprintf("insert into t_compress values (%d)", i))
+		}
+	})
+
+	conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", cli.port))
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	pkt := newPacketIO(newBufferedReadConn(conn))
+
+	// Read the initial handshake and reply the handshake response.
+	_, err = pkt.readPacket()
+	c.Assert(err, IsNil)
+	capability |= tmysql.ClientProtocol41 | tmysql.ClientSecureConnection | tmysql.ClientPluginAuth | tmysql.ClientLongPassword | tmysql.ClientTransactions
+	data := make([]byte, 4, 64)
+	data = append(data, byte(capability), byte(capability>>8), byte(capability>>16), byte(capability>>24))
+	data = append(data, 0, 0, 0, 0)
+	data = append(data, tmysql.DefaultCollationID)
+	data = append(data, make([]byte, 23)...)
+	data = append(data, "root"...)
+	data = append(data, 0, 0)
+	data = append(data, tmysql.AuthNativePassword...)
+	data = append(data, 0)
+	if capability&clientZstdCompressionAlgorithm > 0 {
+		data = append(data, 5)
+	}
+	c.Assert(pkt.writePacket(data), IsNil)
+	c.Assert(pkt.flush(), IsNil)
+	data, err = pkt.readPacket()
+	c.Assert(err, IsNil)
+	c.Assert(data[0], Equals, tmysql.OKHeader)
+
+	algorithm, _ := negotiateCompression(capability, 5, &config.GetGlobalConfig().ProtocolCompression)
+	c.Assert(pkt.setCompression(algorithm, 1), IsNil)
+
+	query := func(sql string) [][]byte {
+		pkt.sequence, pkt.compressedSequence = 0, 0
+		data := append(make([]byte, 4), tmysql.ComQuery)
+		data = append(data, sql...)
+		c.Assert(pkt.writePacket(data), IsNil)
+		c.Assert(pkt.flush(), IsNil)
+
+		data, err := pkt.readPacket()
+		c.Assert(err, IsNil)
+		columns, _, _ := parseLengthEncodedInt(data)
+		// The column definitions and the EOF packet.
+		for i := uint64(0); i <= columns; i++ {
+			_, err = pkt.readPacket()
+			c.Assert(err, IsNil)
+		}
+		var values [][]byte
+		for {
+			data, err = pkt.readPacket()
+			c.Assert(err, IsNil)
+			if data[0] == tmysql.EOFHeader && len(data) < 9 {
+				return values
+			}
+			for pos := 0; pos < len(data); {
+				value, _, n, err := parseLengthEncodedBytes(data[pos:])
+				c.Assert(err, IsNil)
+				values = append(values, value)
+				pos += n
+			}
+		}
+	}
+
+	values := query("select a, repeat(char(97 + a % 26), 10000) from test.t_compress order by a")
+	c.Assert(values, HasLen, 400)
+	for i := 0; i < 200; i++ {
+		c.Assert(string(values[2*i]), Equals, strconv.Itoa(i))
+		c.Assert(string(values[2*i+1]), Equals, strings.Repeat(string(rune('a'+i%26)), 10000))
+	}
+	// The packet larger than MaxPayloadLen is split into multiple compressed packets.
+	values = query("select concat(repeat('a', 10 << 20), repeat('a', 10 << 20))")
+	c.Assert(values, HasLen, 1)
+	c.Assert(len(values[0]), Equals, 20<<20)
+	c.Assert(bytes.Count(values[0], []byte{'a'}), Equals, 20<<20)
+	values = query("select 1")
+	c.Assert(values, HasLen, 1)
+	c.Assert(string(values[0]), Equals, "1")
+}
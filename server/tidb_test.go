@@ -196,6 +196,7 @@ func (ts *tidbTestSuite) TestAuth(c *C) {
 	c.Parallel()
 	ts.runTestAuth(c)
 	ts.runTestIssue3682(c)
+	ts.runTestExpiredPassword(c)
 }
 
 func (ts *tidbTestSuite) TestIssues(c *C) {
@@ -654,6 +655,71 @@ func (ts *tidbTestSerialSuite) TestTLS(c *C) {
 	c.Assert(err, NotNil)
 }
 
+func (ts *tidbTestSerialSuite) TestCachingSha2Password(c *C) {
+	// Start the server with the generated RSA key, the full authentication without TLS exchanges the password
+	// by the RSA public key.
+	cli := newTestServerClient()
+	cfg := newTestConfig()
+	cfg.Port = cli.port
+	cfg.Status.ReportStatus = false
+	server, err := NewServer(cfg, ts.tidbdrv)
+	c.Assert(err, IsNil)
+	cli.port = getPortFromTCPAddr(server.listener.Addr())
+	go func() {
+		err := server.Run()
+		c.Assert(err, IsNil)
+	}()
+	time.Sleep(time.Millisecond * 100)
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec(`CREATE USER 'sha2test'@'%' IDENTIFIED WITH caching_sha2_password BY '123';`)
+		dbt.mustExec(`CREATE USER 'sha2empty'@'%' IDENTIFIED WITH caching_sha2_password;`)
+		dbt.mustExec(`CREATE USER 'nativetest'@'%' IDENTIFIED BY '123';`)
+	})
+	c.Assert(cli.runTestCachingSha2Password(c, "sha2test", "123"), IsNil)
+	err = cli.runTestCachingSha2Password(c, "sha2test", "456")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "Error 1045: Access denied for user 'sha2test'@'127.0.0.1' (using password: YES)")
+	c.Assert(cli.runTestCachingSha2Password(c, "sha2empty", ""), IsNil)
+	c.Assert(cli.runTestCachingSha2Password(c, "nativetest", "123"), IsNil)
+	server.Close()
+
+	// Start the server whose RSA key can't be loaded, so only the fast authentication by the cached digest
+	// succeeds without TLS.
+	variable.SetSysVar(variable.DefaultAuthPlugin, tmysql.AuthCachingSha2Password)
+	defer variable.SetSysVar(variable.DefaultAuthPlugin, tmysql.AuthNativePassword)
+	cli = newTestServerClient()
+	cfg = newTestConfig()
+	cfg.Port = cli.port
+	cfg.Status.ReportStatus = false
+	cfg.Security.CachingSha2PrivateKey = "/tmp/non-existing-private-key.pem"
+	server, err = NewServer(cfg, ts.tidbdrv)
+	c.Assert(err, IsNil)
+	defer server.Close()
+	cli.port = getPortFromTCPAddr(server.listener.Addr())
+	go func() {
+		err := server.Run()
+		c.Assert(err, IsNil)
+	}()
+	time.Sleep(time.Millisecond * 100)
+	// The client encrypts the password by a known public key instead of requesting it from the server.
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, IsNil)
+	mysql.RegisterServerPubKey("caching-sha2-test", &rsaKey.PublicKey)
+	defer mysql.DeregisterServerPubKey("caching-sha2-test")
+	pubKeyOverrider := func(config *mysql.Config) {
+		config.ServerPubKey = "caching-sha2-test"
+	}
+	c.Assert(cli.runTestCachingSha2Password(c, "sha2test", "123"), IsNil)
+	c.Assert(cli.runTestCachingSha2Password(c, "sha2test", "456", pubKeyOverrider), NotNil)
+	c.Assert(cli.runTestCachingSha2Password(c, "nativetest", "123"), IsNil)
+
+	// The cached digest is stale after the password is reset, even to the same one.
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec(`ALTER USER 'sha2test'@'%' IDENTIFIED BY '123';`)
+	})
+	c.Assert(cli.runTestCachingSha2Password(c, "sha2test", "123", pubKeyOverrider), NotNil)
+}
+
 func (ts *tidbTestSerialSuite) TestReloadTLS(c *C) {
 	// Generate valid TLS certificates.
 	caCert, caKey, err := generateCert(0, "TiDB CA", nil, nil, "/tmp/ca-key-reload.pem", "/tmp/ca-cert-reload.pem")
@@ -1015,6 +1081,11 @@ func (ts *tidbTestSuite) TestSumAvg(c *C) {
 	ts.runTestSumAvg(c)
 }
 
+func (ts *tidbTestSuite) TestCompressedProtocol(c *C) {
+	ts.runTestCompressedProtocol(c, tmysql.ClientCompress)
+	ts.runTestCompressedProtocol(c, clientZstdCompressionAlgorithm)
+}
+
 func (ts *tidbTestSuite) TestNullFlag(c *C) {
 	// issue #9689
 	qctx, err := ts.tidbdrv.OpenCtx(uint64(0), 0, uint8(tmysql.DefaultCollationID), "test", nil)
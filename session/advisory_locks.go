@@ -0,0 +1,292 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	storeerr "github.com/pingcap/tidb/store/driver/error"
+	"github.com/pingcap/tidb/store/tikv"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+const (
+	advisoryLockMinBackoff = 10 * time.Millisecond
+	advisoryLockMaxBackoff = 500 * time.Millisecond
+)
+
+// advisoryLock is a named lock acquired by GET_LOCK().
+//
+// Every advisory lock owns an internal session which starts a pessimistic
+// transaction and inserts the lock name into mysql.advisory_locks. The
+// transaction is never committed, so the pessimistic lock on the inserted key
+// is what makes the lock exclusive across all the TiDB instances. Releasing
+// the lock simply rolls the transaction back. If the TiDB instance crashes,
+// the pessimistic lock expires after its TTL since nobody keeps it alive.
+//
+// The transaction ignores `max-txn-ttl`, so the pessimistic lock is kept alive
+// as long as the lock is held. If the TTL can't be extended, the lock is lost
+// and the holder gets ErrUserLockLost when it uses the lock again.
+type advisoryLock struct {
+	ctx     context.Context
+	name    string
+	session *session
+	// referenceCount is the number of times the lock is acquired by the
+	// session, RELEASE_LOCK() has to be called as many times to release it.
+	referenceCount int
+}
+
+func newAdvisoryLock(store kv.Storage, lockName string) (*advisoryLock, error) {
+	se, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	return &advisoryLock{ctx: context.Background(), name: lockName, session: se}, nil
+}
+
+// getLock tries to acquire the lock in timeout seconds. A negative timeout
+// means waiting infinitely. It returns false if the lock is held by another
+// session until timeout.
+//
+// The key is locked with NOWAIT and retried with backoff instead of waiting
+// for the lock in TiKV, so the waiting can be interrupted by killing the query
+// of the session which calls GET_LOCK().
+func (a *advisoryLock) getLock(timeout int64, killed *uint32) (bool, error) {
+	se := a.session
+	if err := beginAdvisoryLockTxn(a.ctx, se); err != nil {
+		return false, err
+	}
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+	backoff := advisoryLockMinBackoff
+	for {
+		err := insertAdvisoryLock(a.ctx, se, a.name)
+		if err == nil {
+			a.referenceCount++
+			return true, nil
+		}
+		if !terror.ErrorEqual(err, storeerr.ErrLockAcquireFailAndNoWaitSet) {
+			return false, err
+		}
+		sleep := backoff
+		if timeout >= 0 {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return false, nil
+			}
+			if sleep > remaining {
+				sleep = remaining
+			}
+		}
+		if atomic.LoadUint32(killed) == 1 {
+			return false, executor.ErrQueryInterrupted
+		}
+		time.Sleep(sleep)
+		if backoff *= 2; backoff > advisoryLockMaxBackoff {
+			backoff = advisoryLockMaxBackoff
+		}
+	}
+}
+
+// beginAdvisoryLockTxn starts the pessimistic transaction which holds the lock.
+func beginAdvisoryLockTxn(ctx context.Context, se *session) error {
+	if _, err := se.ExecuteInternal(ctx, "BEGIN PESSIMISTIC"); err != nil {
+		return err
+	}
+	txn, err := se.Txn(true)
+	if err != nil {
+		return err
+	}
+	txn.SetOption(kv.IgnoreMaxTxnTTL, true)
+	// The lock wait timeout is set after the first statement, otherwise it
+	// would be overwritten by the global variables loaded by the session.
+	se.GetSessionVars().LockWaitTimeout = tikv.LockNoWait
+	return nil
+}
+
+func insertAdvisoryLock(ctx context.Context, se *session, lockName string) error {
+	_, err := se.ExecuteInternal(ctx, "INSERT INTO mysql.advisory_locks (lock_name) VALUES (%?)", lockName)
+	return err
+}
+
+// isLost returns whether the pessimistic lock may have expired because its
+// TTL couldn't be extended.
+func (a *advisoryLock) isLost() bool {
+	return atomic.LoadUint32(&a.session.GetSessionVars().TxnCtx.LockExpire) > 0
+}
+
+// close releases the lock and the internal session.
+func (a *advisoryLock) close() {
+	_, err := a.session.ExecuteInternal(a.ctx, "ROLLBACK")
+	terror.Log(err)
+	a.session.Close()
+}
+
+// GetAdvisoryLock implements the sessionctx.Context interface.
+func (s *session) GetAdvisoryLock(lockName string, timeout int64) (bool, error) {
+	if lock, ok := s.advisoryLocks[lockName]; ok {
+		if err := s.checkAdvisoryLockLost(lockName, lock); err != nil {
+			return false, err
+		}
+		lock.referenceCount++
+		return true, nil
+	}
+	lock, err := newAdvisoryLock(s.store, lockName)
+	if err != nil {
+		return false, err
+	}
+	acquired, err := lock.getLock(timeout, &s.sessionVars.Killed)
+	if err != nil || !acquired {
+		lock.close()
+		return false, err
+	}
+	s.advisoryLocks[lockName] = lock
+	// The owner is recorded after the lock is acquired, it is only used by
+	// IS_USED_LOCK() to report which connection holds the lock.
+	err = s.execWithSysSession(func(ctx context.Context, se *session) error {
+		_, err := execAndDrain(ctx, se, "REPLACE INTO mysql.advisory_lock_owners (lock_name, conn_id) VALUES (%?, %?)",
+			lockName, s.sessionVars.ConnectionID)
+		return err
+	})
+	if err != nil {
+		logutil.BgLogger().Warn("record advisory lock owner failed", zap.String("lock", lockName), zap.Error(err))
+	}
+	return true, nil
+}
+
+// ReleaseAdvisoryLock implements the sessionctx.Context interface.
+func (s *session) ReleaseAdvisoryLock(lockName string) (bool, error) {
+	lock, ok := s.advisoryLocks[lockName]
+	if !ok {
+		return false, nil
+	}
+	if err := s.checkAdvisoryLockLost(lockName, lock); err != nil {
+		return false, err
+	}
+	lock.referenceCount--
+	if lock.referenceCount <= 0 {
+		s.releaseAdvisoryLock(lockName, lock)
+	}
+	return true, nil
+}
+
+// ReleaseAllAdvisoryLocks implements the sessionctx.Context interface.
+func (s *session) ReleaseAllAdvisoryLocks() int {
+	var count int
+	for lockName, lock := range s.advisoryLocks {
+		if lock.isLost() {
+			s.sessionVars.StmtCtx.AppendWarning(expression.ErrUserLockLost.GenWithStackByArgs(lockName))
+		} else {
+			count += lock.referenceCount
+		}
+		s.releaseAdvisoryLock(lockName, lock)
+	}
+	return count
+}
+
+// checkAdvisoryLockLost returns ErrUserLockLost if the lock held by the session
+// has been lost, the lost lock is forgotten by the session.
+func (s *session) checkAdvisoryLockLost(lockName string, lock *advisoryLock) error {
+	if !lock.isLost() {
+		return nil
+	}
+	s.releaseAdvisoryLock(lockName, lock)
+	return expression.ErrUserLockLost.GenWithStackByArgs(lockName)
+}
+
+func (s *session) releaseAdvisoryLock(lockName string, lock *advisoryLock) {
+	// Remove the owner before releasing the lock, so a new owner can't be overwritten.
+	err := s.execWithSysSession(func(ctx context.Context, se *session) error {
+		_, err := execAndDrain(ctx, se, "DELETE FROM mysql.advisory_lock_owners WHERE lock_name = %? AND conn_id = %?",
+			lockName, s.sessionVars.ConnectionID)
+		return err
+	})
+	if err != nil {
+		logutil.BgLogger().Warn("remove advisory lock owner failed", zap.String("lock", lockName), zap.Error(err))
+	}
+	lock.close()
+	delete(s.advisoryLocks, lockName)
+}
+
+// IsUsedAdvisoryLock implements the sessionctx.Context interface.
+func (s *session) IsUsedAdvisoryLock(lockName string) (uint64, bool, error) {
+	if lock, ok := s.advisoryLocks[lockName]; ok {
+		if err := s.checkAdvisoryLockLost(lockName, lock); err != nil {
+			return 0, false, err
+		}
+		return s.sessionVars.ConnectionID, true, nil
+	}
+	var (
+		connID uint64
+		used   bool
+	)
+	err := s.execWithSysSession(func(ctx context.Context, se *session) error {
+		// Probe the lock by trying to lock the key without waiting.
+		if _, err := se.ExecuteInternal(ctx, "BEGIN PESSIMISTIC"); err != nil {
+			return err
+		}
+		_, err := execAndDrain(ctx, se, "SELECT lock_name FROM mysql.advisory_locks WHERE lock_name = %? FOR UPDATE NOWAIT", lockName)
+		if _, rollbackErr := se.ExecuteInternal(ctx, "ROLLBACK"); rollbackErr != nil {
+			return rollbackErr
+		}
+		if err != nil {
+			if !terror.ErrorEqual(err, storeerr.ErrLockAcquireFailAndNoWaitSet) {
+				return err
+			}
+			used = true
+		}
+		if !used {
+			return nil
+		}
+		// The owner record may be missing if the holder is just acquiring the
+		// lock, the connection ID is reported as 0 in this case.
+		rows, err := execAndDrain(ctx, se, "SELECT conn_id FROM mysql.advisory_lock_owners WHERE lock_name = %?", lockName)
+		if err != nil {
+			return err
+		}
+		if len(rows) > 0 {
+			connID = rows[0].GetUint64(0)
+		}
+		return nil
+	})
+	return connID, used, err
+}
+
+// execWithSysSession runs fn with a session from the system session pool, so
+// the statements do not interfere with the transaction of the current session.
+func (s *session) execWithSysSession(fn func(ctx context.Context, se *session) error) error {
+	tmp, err := s.sysSessionPool().Get()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer s.sysSessionPool().Put(tmp)
+	return fn(context.Background(), tmp.(*session))
+}
+
+func execAndDrain(ctx context.Context, se *session, sql string, args ...interface{}) ([]chunk.Row, error) {
+	rs, err := se.ExecuteInternal(ctx, sql, args...)
+	if err != nil || rs == nil {
+		return nil, err
+	}
+	defer terror.Call(rs.Close)
+	return drainRecordSet(ctx, se, rs)
+}
@@ -88,6 +88,14 @@ const (
 		Create_Tablespace_Priv  ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_slave_priv	    	ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_client_priv		ENUM('N','Y') NOT NULL DEFAULT 'N',
+		plugin					CHAR(64) NOT NULL DEFAULT 'mysql_native_password',
+		Password_reuse_history	SMALLINT UNSIGNED DEFAULT NULL,
+		Password_reuse_time		SMALLINT UNSIGNED DEFAULT NULL,
+		Password_expired		ENUM('N','Y') NOT NULL DEFAULT 'N',
+		Password_last_changed	TIMESTAMP DEFAULT CURRENT_TIMESTAMP(),
+		Password_lifetime		SMALLINT UNSIGNED DEFAULT NULL,
+		Failed_login_attempts	SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+		Password_lock_time		SMALLINT NOT NULL DEFAULT 0,
 		PRIMARY KEY (Host, User));`
 	// CreateGlobalPrivTable is the SQL statement creates Global scope privilege table in system db.
 	CreateGlobalPrivTable = "CREATE TABLE IF NOT EXISTS mysql.global_priv (" +
@@ -130,7 +138,7 @@ const (
 		Grantor		CHAR(77),
 		Timestamp	TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 		Table_priv	SET('Select','Insert','Update','Delete','Create','Drop','Grant','Index','Alter','Create View','Show View','Trigger','References'),
-		Column_priv	SET('Select','Insert','Update'),
+		Column_priv	SET('Select','Insert','Update','References'),
 		PRIMARY KEY (Host, DB, User, Table_name));`
 	// CreateColumnPrivTable is the SQL statement creates column scope privilege table in system db.
 	CreateColumnPrivTable = `CREATE TABLE IF NOT EXISTS mysql.columns_priv(
@@ -140,7 +148,7 @@ const (
 		Table_name	CHAR(64),
 		Column_name	CHAR(64),
 		Timestamp	TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		Column_priv	SET('Select','Insert','Update'),
+		Column_priv	SET('Select','Insert','Update','References'),
 		PRIMARY KEY (Host, DB, User, Table_name, Column_name));`
 	// CreateGlobalVariablesTable is the SQL statement creates global variable table in system db.
 	// TODO: MySQL puts GLOBAL_VARIABLES table in INFORMATION_SCHEMA db.
@@ -337,6 +345,31 @@ const (
 		WITH_GRANT_OPTION enum('N','Y') NOT NULL DEFAULT 'N',
 		PRIMARY KEY (USER,HOST,PRIV)
 	  );`
+	// CreateAdvisoryLocksTable stores the names of advisory locks, the rows are
+	// never committed, the pessimistic locks on them are the advisory locks.
+	CreateAdvisoryLocksTable = `CREATE TABLE IF NOT EXISTS mysql.advisory_locks (
+		lock_name VARCHAR(64) NOT NULL PRIMARY KEY
+	);`
+	// CreateAdvisoryLockOwnersTable stores the connections holding advisory locks.
+	CreateAdvisoryLockOwnersTable = `CREATE TABLE IF NOT EXISTS mysql.advisory_lock_owners (
+		lock_name VARCHAR(64) NOT NULL PRIMARY KEY,
+		conn_id BIGINT(20) UNSIGNED NOT NULL
+	);`
+	// CreatePasswordHistoryTable stores the previous passwords of the accounts for the password reuse policy.
+	CreatePasswordHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.password_history (
+		Host				CHAR(64) NOT NULL DEFAULT '',
+		User				CHAR(32) NOT NULL DEFAULT '',
+		Password_timestamp	TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		Password			TEXT,
+		PRIMARY KEY (Host, User, Password_timestamp));`
+	// CreateLoginFailuresTable stores the consecutive failed logins of the accounts for the failed-login
+	// tracking, Locked_time is set when the account is blocked.
+	CreateLoginFailuresTable = `CREATE TABLE IF NOT EXISTS mysql.login_failures (
+		Host			CHAR(64) NOT NULL DEFAULT '',
+		User			CHAR(32) NOT NULL DEFAULT '',
+		Failed_count	SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+		Locked_time		TIMESTAMP NULL DEFAULT NULL,
+		PRIMARY KEY (Host, User));`
 )
 
 // bootstrap initiates system DB for a store.
@@ -489,11 +522,19 @@ const (
 	version68 = 68
 	// version69 adds mysql.global_grants for DYNAMIC privileges
 	version69 = 69
+	// version70 adds mysql.advisory_locks and mysql.advisory_lock_owners for GET_LOCK()
+	version70 = 70
+	// version71 adds the plugin column to mysql.user for pluggable authentication.
+	version71 = 71
+	// version72 adds the password policy columns to mysql.user, and adds mysql.password_history and mysql.login_failures.
+	version72 = 72
+	// version73 adds the REFERENCES privilege to the Column_priv of mysql.tables_priv and mysql.columns_priv.
+	version73 = 73
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version69
+var currentBootstrapVersion int64 = version73
 
 var (
 	bootstrapVersion = []func(Session, int64){
@@ -566,6 +607,10 @@ var (
 		upgradeToVer67,
 		upgradeToVer68,
 		upgradeToVer69,
+		upgradeToVer70,
+		upgradeToVer71,
+		upgradeToVer72,
+		upgradeToVer73,
 	}
 )
 
@@ -1489,6 +1534,44 @@ func upgradeToVer69(s Session, ver int64) {
 	doReentrantDDL(s, CreateGlobalGrantsTable)
 }
 
+func upgradeToVer70(s Session, ver int64) {
+	if ver >= version70 {
+		return
+	}
+	doReentrantDDL(s, CreateAdvisoryLocksTable)
+	doReentrantDDL(s, CreateAdvisoryLockOwnersTable)
+}
+
+func upgradeToVer71(s Session, ver int64) {
+	if ver >= version71 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `plugin` CHAR(64) NOT NULL DEFAULT 'mysql_native_password' AFTER `Repl_client_priv`", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer72(s Session, ver int64) {
+	if ver >= version72 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_reuse_history` SMALLINT UNSIGNED DEFAULT NULL AFTER `plugin`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_reuse_time` SMALLINT UNSIGNED DEFAULT NULL AFTER `Password_reuse_history`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_expired` ENUM('N','Y') NOT NULL DEFAULT 'N' AFTER `Password_reuse_time`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_last_changed` TIMESTAMP DEFAULT CURRENT_TIMESTAMP() AFTER `Password_expired`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_lifetime` SMALLINT UNSIGNED DEFAULT NULL AFTER `Password_last_changed`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Failed_login_attempts` SMALLINT UNSIGNED NOT NULL DEFAULT 0 AFTER `Password_lifetime`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_lock_time` SMALLINT NOT NULL DEFAULT 0 AFTER `Failed_login_attempts`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, CreatePasswordHistoryTable)
+	doReentrantDDL(s, CreateLoginFailuresTable)
+}
+
+func upgradeToVer73(s Session, ver int64) {
+	if ver >= version73 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.tables_priv MODIFY COLUMN `Column_priv` SET('Select','Insert','Update','References')")
+	doReentrantDDL(s, "ALTER TABLE mysql.columns_priv MODIFY COLUMN `Column_priv` SET('Select','Insert','Update','References')")
+}
+
 func writeOOMAction(s Session) {
 	comment := "oom-action is `log` by default in v3.0.x, `cancel` by default in v4.0.11+"
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE VARIABLE_VALUE= %?`,
@@ -1567,6 +1650,12 @@ func doDDLWorks(s Session) {
 	mustExecute(s, CreateStatsFMSketchTable)
 	// Create global_grants
 	mustExecute(s, CreateGlobalGrantsTable)
+	// Create advisory_locks and advisory_lock_owners tables.
+	mustExecute(s, CreateAdvisoryLocksTable)
+	mustExecute(s, CreateAdvisoryLockOwnersTable)
+	// Create password_history and login_failures tables.
+	mustExecute(s, CreatePasswordHistoryTable)
+	mustExecute(s, CreateLoginFailuresTable)
 }
 
 // doDMLWorks executes DML statements in bootstrap stage.
@@ -1577,7 +1666,7 @@ func doDMLWorks(s Session) {
 
 	// Insert a default user with empty password.
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO mysql.user VALUES
-		("%", "root", "", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`)
+		("%", "root", "", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "mysql_native_password", NULL, NULL, "N", CURRENT_TIMESTAMP(), NULL, 0, 0)`)
 
 	// Init global system variables table.
 	values := make([]string, 0, len(variable.GetSysVars()))
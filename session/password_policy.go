@@ -0,0 +1,145 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// loginFailuresTable records the consecutive failed logins of the accounts.
+const loginFailuresTable = "login_failures"
+
+// applyPasswordPolicy applies the password lifecycle policies of the account matched by the connection
+// verification, it returns whether the login is allowed. The account is blocked after too many consecutive
+// failed logins, and the session enters the sandbox mode if the password has expired.
+func (s *session) applyPasswordPolicy(user, host string, info privilege.VerificationInfo, verified bool) bool {
+	if info.FailedLoginAttempts > 0 && info.PasswordLockTime != 0 && (verified || info.FailedDueToWrongPassword) {
+		allowed, err := s.trackFailedLogin(user, host, info, verified)
+		if err != nil {
+			logutil.BgLogger().Error("track failed login fail", zap.String("user", user), zap.String("host", host), zap.Error(err))
+			return false
+		}
+		if !allowed {
+			return false
+		}
+	}
+	if !verified {
+		return false
+	}
+	s.sessionVars.InSandBoxMode = s.isPasswordExpired(info)
+	return true
+}
+
+// trackFailedLogin updates the consecutive failed logins of the account in mysql.login_failures,
+// it returns whether the login is allowed. A blocked account can't login even with the right password.
+func (s *session) trackFailedLogin(user, host string, info privilege.VerificationInfo, verified bool) (bool, error) {
+	ctx := context.TODO()
+	stmt, err := s.ParseWithParams(ctx, "SELECT Failed_count, TIMESTAMPDIFF(SECOND, Locked_time, NOW()) FROM %n.%n WHERE Host=%? AND User=%?",
+		mysql.SystemDB, loginFailuresTable, host, user)
+	if err != nil {
+		return false, err
+	}
+	rows, _, err := s.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return false, err
+	}
+	var failedCount int64
+	if len(rows) > 0 {
+		failedCount = rows[0].GetInt64(0)
+		if !rows[0].IsNull(1) {
+			lockedSeconds := rows[0].GetInt64(1)
+			if info.PasswordLockTime < 0 || lockedSeconds < info.PasswordLockTime*int64(24*time.Hour/time.Second) {
+				logutil.BgLogger().Error("try to login an account blocked by consecutive failed logins",
+					zap.String("user", user), zap.String("host", host), zap.Int64("failed count", failedCount))
+				return false, nil
+			}
+			// The account is unblocked when the lock time passes, start counting from zero again.
+			failedCount = 0
+		}
+	}
+
+	if verified {
+		if len(rows) == 0 {
+			return true, nil
+		}
+		stmt, err = s.ParseWithParams(ctx, "DELETE FROM %n.%n WHERE Host=%? AND User=%?", mysql.SystemDB, loginFailuresTable, host, user)
+		if err != nil {
+			return false, err
+		}
+		_, _, err = s.ExecRestrictedStmt(ctx, stmt)
+		return err == nil, err
+	}
+
+	// Count the failure in one statement, so the concurrent failed logins are all counted.
+	// The assignments are evaluated in order, so Locked_time sees the new Failed_count.
+	stmt, err = s.ParseWithParams(ctx, `INSERT INTO %n.%n (Host, User, Failed_count, Locked_time) VALUES (%?, %?, 1, IF(1 >= %?, NOW(), NULL))
+		ON DUPLICATE KEY UPDATE Failed_count = IF(Locked_time IS NULL, Failed_count + 1, 1), Locked_time = IF(Failed_count >= %?, NOW(), NULL)`,
+		mysql.SystemDB, loginFailuresTable, host, user, info.FailedLoginAttempts, info.FailedLoginAttempts)
+	if err != nil {
+		return false, err
+	}
+	if _, _, err = s.ExecRestrictedStmt(ctx, stmt); err != nil {
+		return false, err
+	}
+	if failedCount+1 >= info.FailedLoginAttempts {
+		logutil.BgLogger().Warn("account is blocked by consecutive failed logins",
+			zap.String("user", user), zap.String("host", host), zap.Int64("failed count", failedCount+1))
+	}
+	return false, nil
+}
+
+// isPasswordExpired checks whether the password of the account is marked expired or exceeds its lifetime.
+func (s *session) isPasswordExpired(info privilege.VerificationInfo) bool {
+	if info.PasswordExpired {
+		return true
+	}
+	lifetime := info.PasswordLifeTime
+	if lifetime < 0 {
+		val, err := s.GetGlobalSysVar(variable.DefaultPasswordLifetime)
+		if err != nil {
+			logutil.BgLogger().Warn("get default_password_lifetime fail", zap.Error(err))
+			return false
+		}
+		if lifetime, err = strconv.ParseInt(val, 10, 64); err != nil {
+			return false
+		}
+	}
+	if lifetime <= 0 || info.PasswordLastChanged.IsZero() {
+		return false
+	}
+	return time.Since(info.PasswordLastChanged) > time.Duration(lifetime)*24*time.Hour
+}
+
+// validateStatementInSandBoxMode only allows the statements which reset the password and set variables
+// if the password has expired.
+func (s *session) validateStatementInSandBoxMode(stmtNode ast.StmtNode) error {
+	if !s.sessionVars.InSandBoxMode || s.sessionVars.InRestrictedSQL {
+		return nil
+	}
+	switch stmtNode.(type) {
+	case *ast.SetPwdStmt, *ast.AlterUserStmt, *ast.SetStmt:
+		return nil
+	}
+	return executor.ErrMustChangePassword.GenWithStackByArgs()
+}
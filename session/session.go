@@ -209,6 +209,8 @@ type session struct {
 	ddlOwnerChecker owner.DDLOwnerChecker
 	// lockedTables use to record the table locks hold by the session.
 	lockedTables map[int64]model.TableLockTpInfo
+	// advisoryLocks records the advisory locks acquired by GET_LOCK().
+	advisoryLocks map[string]*advisoryLock
 
 	// client shared coprocessor client per session
 	client kv.Client
@@ -457,7 +459,7 @@ func (s *session) TxnInfo() *txninfo.TxnInfo {
 	return txnInfo
 }
 
-func (s *session) doCommit(ctx context.Context) error {
+func (s *session) doCommit(ctx context.Context) (err error) {
 	if !s.txn.Valid() {
 		return nil
 	}
@@ -465,13 +467,27 @@ func (s *session) doCommit(ctx context.Context) error {
 		s.txn.changeToInvalid()
 		s.sessionVars.SetInTxn(false)
 	}()
+	// All the changes are published to the root buffer before being committed.
+	s.txn.releaseSavepoints(0)
 	if s.txn.IsReadOnly() {
 		return nil
 	}
-	err := s.checkPlacementPolicyBeforeCommit()
+	err = s.checkPlacementPolicyBeforeCommit()
 	if err != nil {
 		return err
 	}
+	// The data of the local temporary tables is moved to the session in a staging buffer,
+	// which is published only if the transaction commits successfully.
+	if tempTableData := s.sessionVars.TemporaryTableData; tempTableData != nil {
+		stagingHandle := tempTableData.Staging()
+		defer func() {
+			if err == nil {
+				tempTableData.Release(stagingHandle)
+			} else {
+				tempTableData.Cleanup(stagingHandle)
+			}
+		}()
+	}
 	if err = s.removeTempTableFromBuffer(); err != nil {
 		return err
 	}
@@ -506,12 +522,19 @@ func (s *session) doCommit(ctx context.Context) error {
 	relatedPhysicalTables := s.GetSessionVars().TxnCtx.TableDeltaMap
 	// Get accessed global temporary tables in the transaction.
 	temporaryTables := s.GetSessionVars().TxnCtx.GlobalTemporaryTables
+	localTempTables, _ := s.sessionVars.LocalTemporaryTables.(*infoschema.LocalTemporaryTables)
 	physicalTableIDs := make([]int64, 0, len(relatedPhysicalTables))
 	for id := range relatedPhysicalTables {
 		// Schema change on global temporary tables doesn't affect transactions.
 		if _, ok := temporaryTables[id]; ok {
 			continue
 		}
+		// Local temporary tables are not in the schema at all.
+		if localTempTables != nil {
+			if _, ok := localTempTables.TableByID(id); ok {
+				continue
+			}
+		}
 		physicalTableIDs = append(physicalTableIDs, id)
 	}
 	// Set this option for 2 phase commit to validate schema lease.
@@ -538,17 +561,29 @@ func (s *session) doCommit(ctx context.Context) error {
 }
 
 // removeTempTableFromBuffer filters out the temporary table key-values.
+// The key-values of the local temporary tables are saved to the session before being filtered out.
 func (s *session) removeTempTableFromBuffer() error {
 	tables := s.GetSessionVars().TxnCtx.GlobalTemporaryTables
-	if len(tables) == 0 {
+	localTempTables, _ := s.sessionVars.LocalTemporaryTables.(*infoschema.LocalTemporaryTables)
+	if len(tables) == 0 && (localTempTables == nil || localTempTables.Count() == 0) {
 		return nil
 	}
+	tids := make([]int64, 0, len(tables))
+	for tid := range tables {
+		tids = append(tids, tid)
+	}
+	var localTempTableIDs []int64
+	if localTempTables != nil {
+		localTempTableIDs = localTempTables.TableIDs()
+	}
+	tempTableData := s.sessionVars.TemporaryTableData
 	memBuffer := s.txn.GetMemBuffer()
 	// Reset and new an empty stage buffer.
 	defer func() {
 		s.txn.cleanup()
 	}()
-	for tid := range tables {
+	for i, tid := range append(tids, localTempTableIDs...) {
+		isLocal := i >= len(tids)
 		seekKey := tablecodec.EncodeTablePrefix(tid)
 		endKey := tablecodec.EncodeTablePrefix(tid + 1)
 		iter, err := memBuffer.Iter(seekKey, endKey)
@@ -556,6 +591,11 @@ func (s *session) removeTempTableFromBuffer() error {
 			return err
 		}
 		for iter.Valid() && iter.Key().HasPrefix(seekKey) {
+			if isLocal {
+				if err = saveTemporaryTableKV(tempTableData, iter.Key(), iter.Value()); err != nil {
+					return err
+				}
+			}
 			if err = memBuffer.Delete(iter.Key()); err != nil {
 				return err
 			}
@@ -570,6 +610,15 @@ func (s *session) removeTempTableFromBuffer() error {
 	return nil
 }
 
+// saveTemporaryTableKV saves a key-value of a local temporary table to the session, an empty value means
+// the key is deleted.
+func saveTemporaryTableKV(tempTableData kv.MemBuffer, key kv.Key, value []byte) error {
+	if len(value) == 0 {
+		return tempTableData.Delete(key)
+	}
+	return tempTableData.Set(key, value)
+}
+
 // errIsNoisy is used to filter DUPLCATE KEY errors.
 // These can observed by users in INFORMATION_SCHEMA.CLIENT_ERRORS_SUMMARY_GLOBAL instead.
 //
@@ -762,7 +811,7 @@ func (s *session) checkTxnAborted(stmt sqlexec.Statement) error {
 	if _, ok := stmt.(*executor.ExecStmt).StmtNode.(*ast.CommitStmt); ok {
 		return nil
 	}
-	if _, ok := stmt.(*executor.ExecStmt).StmtNode.(*ast.RollbackStmt); ok {
+	if rs, ok := stmt.(*executor.ExecStmt).StmtNode.(*ast.RollbackStmt); ok && rs.SavepointName == "" {
 		return nil
 	}
 	return err
@@ -1498,6 +1547,9 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 	if err := s.validateStatementReadOnlyInStaleness(stmtNode); err != nil {
 		return nil, err
 	}
+	if err := s.validateStatementInSandBoxMode(stmtNode); err != nil {
+		return nil, err
+	}
 
 	// Uncorrelated subqueries will execute once when building plan, so we reset process info before building plan.
 	cmd32 := atomic.LoadUint32(&s.GetSessionVars().CommandValue)
@@ -1691,6 +1743,10 @@ func (s *session) rollbackOnError(ctx context.Context) {
 
 // PrepareStmt is used for executing prepare statement in binary protocol
 func (s *session) PrepareStmt(sql string) (stmtID uint32, paramCount int, fields []*ast.ResultField, err error) {
+	if s.sessionVars.InSandBoxMode {
+		err = executor.ErrMustChangePassword.GenWithStackByArgs()
+		return
+	}
 	if s.sessionVars.TxnCtx.InfoSchema == nil {
 		// We don't need to create a transaction for prepare statement, just get information schema will do.
 		s.sessionVars.TxnCtx.InfoSchema = domain.GetDomain(s).InfoSchema()
@@ -2054,7 +2110,7 @@ func (s *session) ClearValue(key fmt.Stringer) {
 type inCloseSession struct{}
 
 // Close function does some clean work when session end.
-// Close should release the table locks which hold by the session.
+// Close should release the table locks and advisory locks which hold by the session.
 func (s *session) Close() {
 	// TODO: do clean table locks when session exited without execute Close.
 	// TODO: do clean table locks when tidb-server was `kill -9`.
@@ -2068,6 +2124,7 @@ func (s *session) Close() {
 			logutil.BgLogger().Error("release table lock failed", zap.Uint64("conn", s.sessionVars.ConnectionID))
 		}
 	}
+	s.ReleaseAllAdvisoryLocks()
 	if s.statsCollector != nil {
 		s.statsCollector.Delete()
 	}
@@ -2082,6 +2139,9 @@ func (s *session) Close() {
 	s.RollbackTxn(ctx)
 	if s.sessionVars != nil {
 		s.sessionVars.WithdrawAllPreparedStmt()
+		// The local temporary tables are dropped along with the session.
+		s.sessionVars.LocalTemporaryTables = nil
+		s.sessionVars.TemporaryTableData = nil
 	}
 }
 
@@ -2094,20 +2154,30 @@ func (s *session) Auth(user *auth.UserIdentity, authentication []byte, salt []by
 	pm := privilege.GetPrivilegeManager(s)
 
 	// Check IP or localhost.
-	var success bool
-	user.AuthUsername, user.AuthHostname, success = pm.ConnectionVerification(user.Username, user.Hostname, authentication, salt, s.sessionVars.TLSConnectionState)
+	var (
+		info    privilege.VerificationInfo
+		success bool
+	)
+	user.AuthUsername, user.AuthHostname, info, success = pm.ConnectionVerification(user.Username, user.Hostname, authentication, salt, s.sessionVars.TLSConnectionState)
 	if success {
+		if !s.applyPasswordPolicy(user.AuthUsername, user.AuthHostname, info, true) {
+			return false
+		}
 		s.sessionVars.User = user
 		s.sessionVars.ActiveRoles = pm.GetDefaultRoles(user.AuthUsername, user.AuthHostname)
 		return true
 	} else if user.Hostname == variable.DefHostname {
+		s.applyPasswordPolicy(user.AuthUsername, user.AuthHostname, info, false)
 		return false
 	}
 
 	// Check Hostname.
 	for _, addr := range getHostByIP(user.Hostname) {
-		u, h, success := pm.ConnectionVerification(user.Username, addr, authentication, salt, s.sessionVars.TLSConnectionState)
+		u, h, hostInfo, success := pm.ConnectionVerification(user.Username, addr, authentication, salt, s.sessionVars.TLSConnectionState)
 		if success {
+			if !s.applyPasswordPolicy(u, h, hostInfo, true) {
+				return false
+			}
 			s.sessionVars.User = &auth.UserIdentity{
 				Username:     user.Username,
 				Hostname:     addr,
@@ -2118,6 +2188,8 @@ func (s *session) Auth(user *auth.UserIdentity, authentication []byte, salt []by
 			return true
 		}
 	}
+	// The hostnames may match the same account, so only the first failure is counted.
+	s.applyPasswordPolicy(user.AuthUsername, user.AuthHostname, info, false)
 	return false
 }
 
@@ -2479,11 +2551,13 @@ func createSessionWithOpt(store kv.Storage, opt *Opt) (*session, error) {
 	}
 	s.mu.values = make(map[fmt.Stringer]interface{})
 	s.lockedTables = make(map[int64]model.TableLockTpInfo)
+	s.advisoryLocks = make(map[string]*advisoryLock)
 	domain.BindDomain(s, dom)
 	// session implements variable.GlobalVarAccessor. Bind it to ctx.
 	s.sessionVars.GlobalVarsAccessor = s
 	s.sessionVars.BinlogClient = binloginfo.GetPumpsClient()
 	s.txn.init()
+	s.txn.sessionVars = s.sessionVars
 
 	sessionBindHandle := bindinfo.NewSessionBindHandle(parser.New())
 	s.SetValue(bindinfo.SessionBindInfoKeyType, sessionBindHandle)
@@ -2508,10 +2582,12 @@ func CreateSessionWithDomain(store kv.Storage, dom *domain.Domain) (*session, er
 	}
 	s.mu.values = make(map[fmt.Stringer]interface{})
 	s.lockedTables = make(map[int64]model.TableLockTpInfo)
+	s.advisoryLocks = make(map[string]*advisoryLock)
 	domain.BindDomain(s, dom)
 	// session implements variable.GlobalVarAccessor. Bind it to ctx.
 	s.sessionVars.GlobalVarsAccessor = s
 	s.txn.init()
+	s.txn.sessionVars = s.sessionVars
 	return s, nil
 }
 
@@ -2702,7 +2778,7 @@ func logStmt(execStmt *executor.ExecStmt, s *session) {
 	case *ast.CreateUserStmt, *ast.DropUserStmt, *ast.AlterUserStmt, *ast.SetPwdStmt, *ast.GrantStmt,
 		*ast.RevokeStmt, *ast.AlterTableStmt, *ast.CreateDatabaseStmt, *ast.CreateIndexStmt, *ast.CreateTableStmt,
 		*ast.DropDatabaseStmt, *ast.DropIndexStmt, *ast.DropTableStmt, *ast.RenameTableStmt, *ast.TruncateTableStmt,
-		*ast.RenameUserStmt:
+		*ast.RenameUserStmt, *ast.CreatePolicyStmt, *ast.DropPolicyStmt:
 		user := vars.User
 		schemaVersion := s.GetInfoSchema().SchemaMetaVersion()
 		if ss, ok := execStmt.StmtNode.(ast.SensitiveStmtNode); ok {
@@ -2851,10 +2927,11 @@ func (s *session) GetInfoSchema() sessionctx.InfoschemaMetaVersion {
 		logutil.BgLogger().Info("use snapshot schema", zap.Uint64("conn", vars.ConnectionID), zap.Int64("schemaVersion", snap.SchemaMetaVersion()))
 		return snap
 	}
+	localTempTables, _ := vars.LocalTemporaryTables.(*infoschema.LocalTemporaryTables)
 	if vars.TxnCtx != nil && vars.InTxn() {
 		if is, ok := vars.TxnCtx.InfoSchema.(infoschema.InfoSchema); ok {
-			return is
+			return infoschema.AttachLocalTemporaryTables(is, localTempTables)
 		}
 	}
-	return domain.GetDomain(s).InfoSchema()
+	return infoschema.AttachLocalTemporaryTables(domain.GetDomain(s).InfoSchema(), localTempTables)
 }
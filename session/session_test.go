@@ -37,6 +37,7 @@ import (
 	"github.com/pingcap/tidb/ddl/placement"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
 	plannercore "github.com/pingcap/tidb/planner/core"
@@ -4178,6 +4179,61 @@ func (s *testSessionSuite3) TestGlobalTemporaryTable(c *C) {
 	tk.MustQuery("select * from g_tmp").Check(testkit.Rows())
 }
 
+func (s *testSessionSuite3) TestAdvisoryLock(c *C) {
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk1.Se.SetConnectionID(1)
+	tk2 := testkit.NewTestKitWithInit(c, s.store)
+	tk2.Se.SetConnectionID(2)
+
+	tk1.MustQuery("select is_free_lock('l1'), is_used_lock('l1')").Check(testkit.Rows("1 <nil>"))
+	tk1.MustQuery("select release_lock('l1')").Check(testkit.Rows("<nil>"))
+	tk1.MustQuery("select get_lock('l1', 1)").Check(testkit.Rows("1"))
+	// The lock names are case-insensitive, and a session can acquire its lock again.
+	tk1.MustQuery("select get_lock('L1', 1)").Check(testkit.Rows("1"))
+	tk1.MustQuery("select is_free_lock('l1'), is_used_lock('l1')").Check(testkit.Rows("0 1"))
+
+	// The lock is visible to the other sessions.
+	tk2.MustQuery("select is_free_lock('l1'), is_used_lock('l1')").Check(testkit.Rows("0 1"))
+	tk2.MustQuery("select get_lock('l1', 0)").Check(testkit.Rows("0"))
+	tk2.MustQuery("select get_lock('l1', 1)").Check(testkit.Rows("0"))
+	tk2.MustQuery("select release_lock('l1')").Check(testkit.Rows("0"))
+
+	// The lock is released after it's released as many times as it's acquired.
+	tk1.MustQuery("select release_lock('l1')").Check(testkit.Rows("1"))
+	tk2.MustQuery("select get_lock('l1', 0)").Check(testkit.Rows("0"))
+	tk1.MustQuery("select release_lock('l1')").Check(testkit.Rows("1"))
+	tk1.MustQuery("select release_lock('l1')").Check(testkit.Rows("<nil>"))
+
+	// The waiting session gets the lock once it's released.
+	tk1.MustQuery("select get_lock('l2', 1)").Check(testkit.Rows("1"))
+	ch := make(chan struct{})
+	go func() {
+		tk2.MustQuery("select get_lock('l2', 10)").Check(testkit.Rows("1"))
+		close(ch)
+	}()
+	time.Sleep(200 * time.Millisecond)
+	tk1.MustQuery("select release_all_locks()").Check(testkit.Rows("1"))
+	<-ch
+	tk1.MustQuery("select is_used_lock('l2')").Check(testkit.Rows("2"))
+
+	// The advisory locks are not affected by the transactions of the session.
+	tk2.MustExec("begin")
+	tk2.MustQuery("select get_lock('l3', 1)").Check(testkit.Rows("1"))
+	tk2.MustExec("rollback")
+	tk1.MustQuery("select is_free_lock('l3')").Check(testkit.Rows("0"))
+
+	// The locks are released when the session is closed.
+	tk2.Se.Close()
+	tk1.MustQuery("select is_free_lock('l2'), is_free_lock('l3')").Check(testkit.Rows("1 1"))
+	tk1.MustQuery("select get_lock('l2', 0), get_lock('l3', 0)").Check(testkit.Rows("1 1"))
+	tk1.MustQuery("select release_all_locks()").Check(testkit.Rows("2"))
+
+	err := tk1.QueryToErr("select get_lock('', 1)")
+	c.Assert(terror.ErrorEqual(err, expression.ErrUserLockWrongName), IsTrue, Commentf("err %v", err))
+	err = tk1.QueryToErr("select is_free_lock(null)")
+	c.Assert(terror.ErrorEqual(err, expression.ErrUserLockWrongName), IsTrue, Commentf("err %v", err))
+}
+
 type testTxnStateSuite struct {
 	testSessionSuiteBase
 }
@@ -353,4 +353,5 @@ func ResultSetToStringSlice(ctx context.Context, s Session, rs sqlexec.RecordSet
 // Session errors.
 var (
 	ErrForUpdateCantRetry = dbterror.ClassSession.NewStd(errno.ErrForUpdateCantRetry)
+	ErrSavepointNotExists = dbterror.ClassSession.NewStd(errno.ErrSpDoesNotExist)
 )
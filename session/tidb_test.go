@@ -27,14 +27,19 @@ import (
 	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx/binloginfo"
 	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/store/tikv"
+	tikvcfg "github.com/pingcap/tidb/store/tikv/config"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/testleak"
+	"github.com/pingcap/tipb/go-binlog"
 )
 
 func TestT(t *testing.T) {
@@ -239,3 +244,137 @@ func (s *testMainSuite) TestIndexUsageSyncLease(c *C) {
 	err = store.Close()
 	c.Assert(err, IsNil)
 }
+
+func (s *testMainSuite) TestSavepoint(c *C) {
+	ctx := context.Background()
+	dbName := "test_savepoint"
+	se := newSession(c, s.store, dbName).(*session)
+	mustExecSQL(c, se, "drop table if exists t")
+	mustExecSQL(c, se, "create table t(a int primary key, b int)")
+	checkRows := func(se *session, sql string, expected ...string) {
+		rs := mustExecSQL(c, se, sql)
+		rows, err := ResultSetToStringSlice(ctx, se, rs)
+		c.Assert(err, IsNil)
+		result := make([]string, 0, len(rows))
+		for _, row := range rows {
+			result = append(result, fmt.Sprintf("%v", row))
+		}
+		c.Assert(result, DeepEquals, expected, Commentf("sql: %s", sql))
+	}
+
+	mustExecSQL(c, se, "begin")
+	mustExecSQL(c, se, "insert into t values (1, 1)")
+	mustExecSQL(c, se, "savepoint s1")
+	mustExecSQL(c, se, "insert into t values (2, 2)")
+	// The changes after the savepoint are visible to the point get.
+	checkRows(se, "select b from t where a = 2", "[2]")
+	mustExecSQL(c, se, "savepoint s2")
+	mustExecSQL(c, se, "update t set b = 10 where a = 1")
+	mustExecSQL(c, se, "rollback to savepoint S1")
+	checkRows(se, "select * from t", "[1 1]")
+	// The savepoints set after the one rolled back to are deleted.
+	_, err := exec(se, "rollback to s2")
+	c.Assert(ErrSavepointNotExists.Equal(err), IsTrue, Commentf("err: %v", err))
+	c.Assert(se.sessionVars.TxnCtx.CouldRetry, IsFalse)
+	mustExecSQL(c, se, "insert into t values (3, 3)")
+	mustExecSQL(c, se, "rollback to savepoint s1")
+	mustExecSQL(c, se, "insert into t values (4, 4)")
+	mustExecSQL(c, se, "release savepoint s1")
+	_, err = exec(se, "release savepoint s1")
+	c.Assert(ErrSavepointNotExists.Equal(err), IsTrue, Commentf("err: %v", err))
+	mustExecSQL(c, se, "commit")
+	checkRows(se, "select * from t", "[1 1]", "[4 4]")
+
+	// The savepoint with the same name is replaced, and all the savepoints are released on committing.
+	mustExecSQL(c, se, "begin")
+	mustExecSQL(c, se, "savepoint s1")
+	mustExecSQL(c, se, "insert into t values (5, 5)")
+	mustExecSQL(c, se, "savepoint s2")
+	mustExecSQL(c, se, "insert into t values (6, 6)")
+	mustExecSQL(c, se, "savepoint s1")
+	mustExecSQL(c, se, "insert into t values (7, 7)")
+	mustExecSQL(c, se, "rollback to savepoint s2")
+	_, err = exec(se, "rollback to s1")
+	c.Assert(ErrSavepointNotExists.Equal(err), IsTrue, Commentf("err: %v", err))
+	mustExecSQL(c, se, "commit")
+	checkRows(se, "select * from t", "[1 1]", "[4 4]", "[5 5]")
+	_, err = exec(se, "rollback to s2")
+	c.Assert(ErrSavepointNotExists.Equal(err), IsTrue, Commentf("err: %v", err))
+
+	// The pessimistic locks acquired after the savepoint are kept until the transaction ends.
+	se2 := newSession(c, s.store, dbName).(*session)
+	mustExecSQL(c, se, "begin pessimistic")
+	mustExecSQL(c, se, "savepoint s1")
+	mustExecSQL(c, se, "update t set b = 10 where a = 1")
+	mustExecSQL(c, se, "rollback to savepoint s1")
+	checkRows(se, "select * from t where a = 1", "[1 1]")
+	mustExecSQL(c, se2, "begin pessimistic")
+	rs, err := exec(se2, "select * from t where a = 1 for update nowait")
+	if err == nil {
+		_, err = ResultSetToStringSlice(ctx, se2, rs)
+	}
+	c.Assert(err, ErrorMatches, ".*lock\\(s\\) could not be acquired immediately and NOWAIT is set.*")
+	mustExecSQL(c, se2, "rollback")
+	mustExecSQL(c, se, "commit")
+	mustExecSQL(c, se2, "begin pessimistic")
+	checkRows(se2, "select * from t where a = 1 for update nowait", "[1 1]")
+	mustExecSQL(c, se2, "rollback")
+
+	// The binlog mutations after the savepoint are discarded.
+	mustExecSQL(c, se, "begin")
+	mustExecSQL(c, se, "insert into t values (8, 8)")
+	bin := binloginfo.GetPrewriteValue(se, true)
+	bin.Mutations = append(bin.Mutations, binlog.TableMutation{TableId: 1, InsertedRows: [][]byte{{1}}})
+	mustExecSQL(c, se, "savepoint s1")
+	bin.Mutations[0].InsertedRows = append(bin.Mutations[0].InsertedRows, []byte{2})
+	bin.Mutations = append(bin.Mutations, binlog.TableMutation{TableId: 2})
+	mustExecSQL(c, se, "rollback to savepoint s1")
+	c.Assert(bin.Mutations, HasLen, 1)
+	c.Assert(bin.Mutations[0].InsertedRows, DeepEquals, [][]byte{{1}})
+	mustExecSQL(c, se, "rollback")
+	checkRows(se, "select count(*) from t", "[3]")
+
+	// The savepoint set outside of a transaction ends with the statement.
+	mustExecSQL(c, se, "savepoint s1")
+	_, err = exec(se, "release savepoint s1")
+	c.Assert(ErrSavepointNotExists.Equal(err), IsTrue, Commentf("err: %v", err))
+}
+
+func (s *testMainSuite) TestAdvisoryLockKeepAlive(c *C) {
+	defer func(ttl uint64) { atomic.StoreUint64(&tikv.ManagedLockTTL, ttl) }(atomic.LoadUint64(&tikv.ManagedLockTTL))
+	atomic.StoreUint64(&tikv.ManagedLockTTL, 100)
+	defer tikvcfg.UpdateGlobal(func(conf *tikvcfg.Config) {
+		conf.MaxTxnTTL = 100
+	})()
+	se1 := newSession(c, s.store, "test_advisory_lock").(*session)
+	se2 := newSession(c, s.store, "test_advisory_lock").(*session)
+	defer se1.Close()
+	defer se2.Close()
+
+	acquired, err := se1.GetAdvisoryLock("refresh", 0)
+	c.Assert(err, IsNil)
+	c.Assert(acquired, IsTrue)
+	// The pessimistic lock is kept alive beyond max-txn-ttl.
+	time.Sleep(500 * time.Millisecond)
+	lock := se1.advisoryLocks["refresh"]
+	c.Assert(lock.isLost(), IsFalse)
+	acquired, err = se2.GetAdvisoryLock("refresh", 0)
+	c.Assert(err, IsNil)
+	c.Assert(acquired, IsFalse)
+	released, err := se1.ReleaseAdvisoryLock("refresh")
+	c.Assert(err, IsNil)
+	c.Assert(released, IsTrue)
+	acquired, err = se2.GetAdvisoryLock("refresh", 0)
+	c.Assert(err, IsNil)
+	c.Assert(acquired, IsTrue)
+
+	// Using a lost lock reports an error and the lock is forgotten by the session.
+	lock = se2.advisoryLocks["refresh"]
+	atomic.StoreUint32(&lock.session.GetSessionVars().TxnCtx.LockExpire, 1)
+	_, err = se2.ReleaseAdvisoryLock("refresh")
+	c.Assert(expression.ErrUserLockLost.Equal(err), IsTrue, Commentf("err: %v", err))
+	c.Assert(se2.advisoryLocks, HasLen, 0)
+	released, err = se2.ReleaseAdvisoryLock("refresh")
+	c.Assert(err, IsNil)
+	c.Assert(released, IsFalse)
+}
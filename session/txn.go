@@ -29,10 +29,12 @@ import (
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/session/txninfo"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/binloginfo"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/tikv"
 	"github.com/pingcap/tidb/store/tikv/oracle"
 	"github.com/pingcap/tidb/tablecodec"
@@ -58,6 +60,9 @@ type LazyTxn struct {
 	stagingHandle kv.StagingHandle
 	mutations     map[int64]*binlog.TableMutation
 	writeSLI      sli.TxnWriteThroughputSLI
+	// savepoints are the savepoints of the transaction in the order they are set, the staging buffer of
+	// the statement is always above all of their staging buffers.
+	savepoints []savepoint
 
 	// following atomic fields are used for filling TxnInfo
 	// we need these fields because kv.Transaction provides no thread safety promise
@@ -72,6 +77,9 @@ type LazyTxn struct {
 	EntriesCount uint64
 	// how many memory space do the entries in the memBuffer take, should be equal to self.(kv.Transaction).Size()
 	EntriesSize uint64
+
+	// sessionVars is used to read the data of the local temporary tables, which is kept in the session.
+	sessionVars *variable.SessionVars
 }
 
 // GetTableInfo returns the cached index name.
@@ -225,6 +233,7 @@ func (txn *LazyTxn) changeToInvalid() {
 		txn.Transaction.GetMemBuffer().Cleanup(txn.stagingHandle)
 	}
 	txn.stagingHandle = kv.InvalidStagingHandle
+	txn.savepoints = nil
 	txn.Transaction = nil
 	txn.txnFuture = nil
 	atomic.StoreUint64(&txn.infoStartTS, 0)
@@ -307,6 +316,100 @@ func (txn *LazyTxn) Rollback() error {
 	return txn.Transaction.Rollback()
 }
 
+// IsReadOnly overrides the Transaction interface.
+// The changes made after a savepoint are not published to the root buffer until the savepoint is released,
+// so the transaction is regarded as dirty as long as its buffer is not empty.
+func (txn *LazyTxn) IsReadOnly() bool {
+	if len(txn.savepoints) > 0 && txn.Transaction.Len() > 0 {
+		return false
+	}
+	return txn.Transaction.IsReadOnly()
+}
+
+// savepoint is a named savepoint of the transaction. The changes made after it are kept in its staging
+// buffer, so they could be discarded without affecting the changes made before it.
+type savepoint struct {
+	name          string
+	stagingHandle kv.StagingHandle
+	// binlogMutations are the binlog mutations of the transaction when the savepoint is set.
+	binlogMutations []binlog.TableMutation
+}
+
+func (txn *LazyTxn) findSavepoint(name string) int {
+	for i := len(txn.savepoints) - 1; i >= 0; i-- {
+		if strings.EqualFold(txn.savepoints[i].name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// setSavepoint sets a savepoint between the statements. The old savepoint with the same name is deleted, but its
+// staging buffer is kept so that the savepoints set after it are still valid.
+func (txn *LazyTxn) setSavepoint(name string, bin *binlog.PrewriteValue) {
+	if idx := txn.findSavepoint(name); idx >= 0 {
+		txn.savepoints[idx].name = ""
+	}
+	// The staging buffer of the statement must be the latest one, so it's re-created above the savepoint.
+	txn.cleanupStmtBuf()
+	sp := savepoint{name: name, stagingHandle: txn.Transaction.GetMemBuffer().Staging()}
+	if bin != nil {
+		sp.binlogMutations = append([]binlog.TableMutation(nil), bin.Mutations...)
+	}
+	txn.savepoints = append(txn.savepoints, sp)
+	txn.initStmtBuf()
+}
+
+// rollbackToSavepoint discards the changes and the binlog mutations after the savepoint, the savepoints set after
+// it are deleted. The pessimistic locks acquired after the savepoint are kept like MySQL does, their keys are still
+// in the buffer with the locked flag, so they are released when the transaction ends.
+func (txn *LazyTxn) rollbackToSavepoint(name string, bin *binlog.PrewriteValue) error {
+	idx := txn.findSavepoint(name)
+	if idx < 0 {
+		return ErrSavepointNotExists.GenWithStackByArgs("SAVEPOINT", name)
+	}
+	buf := txn.Transaction.GetMemBuffer()
+	txn.cleanupStmtBuf()
+	for i := len(txn.savepoints) - 1; i >= idx; i-- {
+		buf.Cleanup(txn.savepoints[i].stagingHandle)
+	}
+	sp := txn.savepoints[idx]
+	if bin != nil {
+		bin.Mutations = append(bin.Mutations[:0], sp.binlogMutations...)
+	}
+	// The savepoint itself is kept, so its staging buffer is re-created.
+	sp.stagingHandle = buf.Staging()
+	txn.savepoints = append(txn.savepoints[:idx], sp)
+	txn.initStmtBuf()
+	atomic.StoreUint64(&txn.EntriesCount, uint64(txn.Transaction.Len()))
+	atomic.StoreUint64(&txn.EntriesSize, uint64(txn.Transaction.Size()))
+	return nil
+}
+
+// releaseSavepoint deletes the savepoint and the savepoints set after it, their changes are kept.
+func (txn *LazyTxn) releaseSavepoint(name string) error {
+	idx := txn.findSavepoint(name)
+	if idx < 0 {
+		return ErrSavepointNotExists.GenWithStackByArgs("SAVEPOINT", name)
+	}
+	txn.releaseSavepoints(idx)
+	return nil
+}
+
+// releaseSavepoints publishes the changes of the savepoints starting from idx to the upper level buffer.
+func (txn *LazyTxn) releaseSavepoints(idx int) {
+	if idx >= len(txn.savepoints) {
+		return
+	}
+	buf := txn.Transaction.GetMemBuffer()
+	txn.cleanupStmtBuf()
+	for i := len(txn.savepoints) - 1; i >= idx; i-- {
+		buf.Release(txn.savepoints[i].stagingHandle)
+	}
+	txn.savepoints = txn.savepoints[:idx]
+	txn.initStmtBuf()
+}
+
 // LockKeys Wrap the inner transaction's `LockKeys` to record the status
 func (txn *LazyTxn) LockKeys(ctx context.Context, lockCtx *kv.LockCtx, keys ...kv.Key) error {
 	originState := atomic.LoadInt32(&txn.State)
@@ -321,6 +424,74 @@ func (txn *LazyTxn) LockKeys(ctx context.Context, lockCtx *kv.LockCtx, keys ...k
 	return err
 }
 
+// temporaryTableData returns the data of the local temporary tables in the session if the key belongs to
+// one of them, otherwise it returns nil.
+func (txn *LazyTxn) temporaryTableData(k kv.Key) kv.Retriever {
+	if txn.sessionVars == nil || txn.sessionVars.TemporaryTableData == nil {
+		return nil
+	}
+	localTempTables, ok := txn.sessionVars.LocalTemporaryTables.(*infoschema.LocalTemporaryTables)
+	if !ok {
+		return nil
+	}
+	if _, ok := localTempTables.TableByID(tablecodec.DecodeTableID(k)); !ok {
+		return nil
+	}
+	return txn.sessionVars.TemporaryTableData
+}
+
+// Get overrides the Transaction interface.
+// The keys of the local temporary tables are read from the session instead of the snapshot.
+func (txn *LazyTxn) Get(ctx context.Context, k kv.Key) ([]byte, error) {
+	data := txn.temporaryTableData(k)
+	if data == nil {
+		return txn.Transaction.Get(ctx, k)
+	}
+	val, err := txn.GetMemBuffer().Get(ctx, k)
+	if kv.IsErrNotFound(err) {
+		val, err = data.Get(ctx, k)
+	}
+	if err == nil && len(val) == 0 {
+		return nil, kv.ErrNotExist
+	}
+	return val, err
+}
+
+// BatchGet overrides the Transaction interface.
+// The keys of the local temporary tables are read from the session instead of the snapshot.
+func (txn *LazyTxn) BatchGet(ctx context.Context, keys []kv.Key) (map[string][]byte, error) {
+	if txn.sessionVars == nil || txn.sessionVars.TemporaryTableData == nil {
+		return txn.Transaction.BatchGet(ctx, keys)
+	}
+	result := make(map[string][]byte)
+	snapshotKeys := make([]kv.Key, 0, len(keys))
+	for _, k := range keys {
+		if txn.temporaryTableData(k) == nil {
+			snapshotKeys = append(snapshotKeys, k)
+			continue
+		}
+		val, err := txn.Get(ctx, k)
+		if kv.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result[string(k)] = val
+	}
+	if len(snapshotKeys) == 0 {
+		return result, nil
+	}
+	snapshotResult, err := txn.Transaction.BatchGet(ctx, snapshotKeys)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range snapshotResult {
+		result[k] = v
+	}
+	return result, nil
+}
+
 func (txn *LazyTxn) reset() {
 	txn.cleanup()
 	txn.changeToInvalid()
@@ -479,6 +650,36 @@ func (s *session) HasDirtyContent(tid int64) bool {
 	return it.Valid() && bytes.HasPrefix(it.Key(), seekKey)
 }
 
+// SetSavepoint implements `SAVEPOINT name`.
+func (s *session) SetSavepoint(name string) error {
+	if _, err := s.Txn(true); err != nil {
+		return err
+	}
+	s.txn.setSavepoint(name, binloginfo.GetPrewriteValue(s, false))
+	return nil
+}
+
+// RollbackToSavepoint implements `ROLLBACK TO SAVEPOINT name`.
+func (s *session) RollbackToSavepoint(name string) error {
+	if !s.txn.Valid() {
+		return ErrSavepointNotExists.GenWithStackByArgs("SAVEPOINT", name)
+	}
+	if err := s.txn.rollbackToSavepoint(name, binloginfo.GetPrewriteValue(s, false)); err != nil {
+		return err
+	}
+	// The statements rolled back are still in the history, so the transaction can't be retried by replaying it.
+	s.sessionVars.TxnCtx.CouldRetry = false
+	return nil
+}
+
+// ReleaseSavepoint implements `RELEASE SAVEPOINT name`.
+func (s *session) ReleaseSavepoint(name string) error {
+	if !s.txn.Valid() {
+		return ErrSavepointNotExists.GenWithStackByArgs("SAVEPOINT", name)
+	}
+	return s.txn.releaseSavepoint(name)
+}
+
 // StmtCommit implements the sessionctx.Context interface.
 func (s *session) StmtCommit() {
 	defer func() {
@@ -118,6 +118,16 @@ type Context interface {
 	ReleaseAllTableLocks()
 	// HasLockedTables uses to check whether this session locked any tables.
 	HasLockedTables() bool
+	// GetAdvisoryLock acquires the advisory lock in timeout seconds, a negative timeout means
+	// waiting infinitely. It returns false if the lock is held by another session until timeout.
+	GetAdvisoryLock(lockName string, timeout int64) (bool, error)
+	// ReleaseAdvisoryLock releases the advisory lock once, it returns false if the lock is not hold by the session.
+	// An error is returned if the lock has been lost after the session acquired it.
+	ReleaseAdvisoryLock(lockName string) (bool, error)
+	// ReleaseAllAdvisoryLocks releases all advisory locks hold by the session and returns the number of released locks.
+	ReleaseAllAdvisoryLocks() int
+	// IsUsedAdvisoryLock checks whether the advisory lock is hold by any session and returns the connection ID of the holder.
+	IsUsedAdvisoryLock(lockName string) (uint64, bool, error)
 	// PrepareTSFuture uses to prepare timestamp by future.
 	PrepareTSFuture(ctx context.Context)
 	// StoreIndexUsage stores the index usage information.
@@ -440,6 +440,10 @@ type SessionVars struct {
 	// User is the user identity with which the session login.
 	User *auth.UserIdentity
 
+	// InSandBoxMode indicates that the password of the user has expired, only the statements
+	// which reset the password are allowed until the password is changed.
+	InSandBoxMode bool
+
 	// Port is the port of the connected socket
 	Port string
 
@@ -469,6 +473,14 @@ type SessionVars struct {
 	// version, we load an old version schema for query.
 	SnapshotInfoschema interface{}
 
+	// LocalTemporaryTables is *infoschema.LocalTemporaryTables, use interface to avoid circle dependency.
+	// It holds the local temporary tables created by the session.
+	LocalTemporaryTables interface{}
+
+	// TemporaryTableData stores the committed data of the local temporary tables.
+	// It's nil before the first local temporary table is created.
+	TemporaryTableData kv.MemBuffer
+
 	// BinlogClient is used to write binlog.
 	BinlogClient *pumpcli.PumpsClient
 
@@ -611,6 +623,9 @@ type SessionVars struct {
 	// EnableChangeMultiSchema is used to control whether to enable the multi schema change.
 	EnableChangeMultiSchema bool
 
+	// ForeignKeyChecks indicates whether the foreign key constraints are enforced when writing rows.
+	ForeignKeyChecks bool
+
 	// EnablePointGetCache is used to cache value for point get for read only scenario.
 	EnablePointGetCache bool
 
@@ -762,6 +777,10 @@ type SessionVars struct {
 	// OptimizerUseInvisibleIndexes indicates whether optimizer can use invisible index
 	OptimizerUseInvisibleIndexes bool
 
+	// HypoIndexes are the hypothetical indexes which are only visible to the optimizer, keyed by table ID.
+	// They are used by the index advisor to estimate the cost of the candidate indexes without creating them.
+	HypoIndexes map[int64][]*model.IndexInfo
+
 	// SelectLimit limits the max counts of select statement's output
 	SelectLimit uint64
 
@@ -1018,6 +1037,7 @@ func NewSessionVars() *SessionVars {
 		ShardAllocateStep:           DefTiDBShardAllocateStep,
 		EnableChangeColumnType:      DefTiDBChangeColumnType,
 		EnableChangeMultiSchema:     DefTiDBChangeMultiSchema,
+		ForeignKeyChecks:            DefForeignKeyChecks,
 		EnablePointGetCache:         DefTiDBPointGetCache,
 		EnableAlterPlacement:        DefTiDBEnableAlterPlacement,
 		EnableAmendPessimisticTxn:   DefTiDBEnableAmendPessimisticTxn,
@@ -370,11 +370,13 @@ func (*testSysVarSuite) TestForeignKeyChecks(c *C) {
 
 	val, err := sv.Validate(vars, "on", ScopeSession)
 	c.Assert(err, IsNil)
-	c.Assert(val, Equals, "OFF") // warns and refuses to set ON.
-
-	warn := vars.StmtCtx.GetWarnings()[0].Err
-	c.Assert(warn.Error(), Equals, "[variable:8047]variable 'foreign_key_checks' does not yet support value: on")
+	c.Assert(val, Equals, "ON")
+	c.Assert(vars.StmtCtx.GetWarnings(), HasLen, 0)
 
+	c.Assert(sv.SetSessionFromHook(vars, val), IsNil)
+	c.Assert(vars.ForeignKeyChecks, IsTrue)
+	c.Assert(sv.SetSessionFromHook(vars, "OFF"), IsNil)
+	c.Assert(vars.ForeignKeyChecks, IsFalse)
 }
 
 func (*testSysVarSuite) TestTxnIsolation(c *C) {
@@ -553,6 +553,7 @@ const (
 // Default TiDB system variable values.
 const (
 	DefHostname                        = "localhost"
+	DefForeignKeyChecks                = false
 	DefIndexLookupConcurrency          = ConcurrencyUnset
 	DefIndexLookupJoinConcurrency      = ConcurrencyUnset
 	DefIndexSerialScanConcurrency      = 1
@@ -32,6 +32,12 @@ func calculateEstimateNDV(h *topNHelper, rowCount uint64) (ndv uint64, scaleRati
 		// Nothing to do, no change with scale ratio
 		return sampleNDV, scaleRatio
 	}
+	return EstimateNDVByGEE(sampleSize, sampleNDV, onlyOnceItems, rowCount), scaleRatio
+}
+
+// EstimateNDVByGEE estimates the NDV of rowCount rows by the sampleNDV distinct values of sampleSize sampled rows,
+// onlyOnceItems of which occur only once in the samples.
+func EstimateNDVByGEE(sampleSize, sampleNDV, onlyOnceItems, rowCount uint64) uint64 {
 	// Charikar, Moses, et al. "Towards estimation error guarantees for distinct values."
 	// Proceedings of the nineteenth ACM SIGMOD-SIGACT-SIGART symposium on Principles of database systems. ACM, 2000.
 	// This is GEE in that paper.
@@ -43,8 +49,8 @@ func calculateEstimateNDV(h *topNHelper, rowCount uint64) (ndv uint64, scaleRati
 	N := float64(rowCount)
 	d := float64(sampleNDV)
 
-	ndv = uint64(math.Sqrt(N/n)*f1 + d - f1 + 0.5)
+	ndv := uint64(math.Sqrt(N/n)*f1 + d - f1 + 0.5)
 	ndv = mathutil.MaxUint64(ndv, sampleNDV)
 	ndv = mathutil.MinUint64(ndv, rowCount)
-	return ndv, scaleRatio
+	return ndv
 }
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -40,6 +41,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/sqlexec"
@@ -1414,14 +1416,145 @@ func (h *Handle) BuildExtendedStats(tableID int64, cols []*model.ColumnInfo, col
 
 func (h *Handle) fillExtendedStatsItemVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
 	switch item.Tp {
-	case ast.StatsTypeCardinality, ast.StatsTypeDependency:
-		return nil
+	case ast.StatsTypeCardinality:
+		return h.fillExtStatsCardinalityVals(item, cols, collectors)
+	case ast.StatsTypeDependency:
+		return h.fillExtStatsDependencyVals(item, cols, collectors)
 	case ast.StatsTypeCorrelation:
 		return h.fillExtStatsCorrVals(item, cols, collectors)
 	}
 	return nil
 }
 
+// extStatsSampleRows returns the encoded values of the columns of the extended stats item
+// for each sampled row. The samples of the columns are aligned by SampleItem.Ordinal, and
+// the rows which contain NULL are skipped. It also returns the number of the sampled rows and
+// the estimated number of rows of the table.
+func (h *Handle) extStatsSampleRows(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) (rows [][]string, sampleCount int, rowCount int64, ok bool) {
+	colOffsets := make([]int, 0, len(item.ColIDs))
+	for _, id := range item.ColIDs {
+		for i, col := range cols {
+			if col.ID == id {
+				colOffsets = append(colOffsets, i)
+				break
+			}
+		}
+	}
+	if len(colOffsets) != len(item.ColIDs) || len(colOffsets) < 2 {
+		return nil, 0, 0, false
+	}
+	h.mu.Lock()
+	sc := h.mu.ctx.GetSessionVars().StmtCtx
+	h.mu.Unlock()
+	rowsByOrdinal := make(map[int][]string)
+	for i, offset := range colOffsets {
+		collector := collectors[offset]
+		rowCount = mathutil.MaxInt64(rowCount, collector.Count+collector.NullCount)
+		for _, sample := range collector.Samples {
+			sampleCount = mathutil.Max(sampleCount, sample.Ordinal+1)
+			row, exists := rowsByOrdinal[sample.Ordinal]
+			if i > 0 && (!exists || len(row) != i) {
+				continue
+			}
+			val, err := codec.EncodeKey(sc, nil, sample.Value)
+			if err != nil {
+				return nil, 0, 0, false
+			}
+			rowsByOrdinal[sample.Ordinal] = append(row, string(val))
+		}
+	}
+	rows = make([][]string, 0, len(rowsByOrdinal))
+	for _, row := range rowsByOrdinal {
+		if len(row) == len(colOffsets) {
+			rows = append(rows, row)
+		}
+	}
+	return rows, sampleCount, rowCount, true
+}
+
+// fillExtStatsCardinalityVals estimates the number of distinct values of the column group.
+func (h *Handle) fillExtStatsCardinalityVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
+	rows, sampleCount, rowCount, ok := h.extStatsSampleRows(item, cols, collectors)
+	if !ok {
+		return nil
+	}
+	if len(rows) == 0 {
+		item.ScalarVals = 0
+		return item
+	}
+	valCounts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		valCounts[strings.Join(row, "")]++
+	}
+	onlyOnceItems := 0
+	for _, cnt := range valCounts {
+		if cnt == 1 {
+			onlyOnceItems++
+		}
+	}
+	// Scale the row count by the fraction of the sampled rows which contain no NULL.
+	groupRowCount := uint64(float64(rowCount) * float64(len(rows)) / float64(sampleCount))
+	if groupRowCount < uint64(len(rows)) {
+		groupRowCount = uint64(len(rows))
+	}
+	if onlyOnceItems == len(rows) {
+		// Assume the column group is unique like what we do for the single column.
+		item.ScalarVals = float64(groupRowCount)
+		return item
+	}
+	ndv := statistics.EstimateNDVByGEE(uint64(len(rows)), uint64(len(valCounts)), uint64(onlyOnceItems), groupRowCount)
+	item.ScalarVals = float64(ndv)
+	return item
+}
+
+// fillExtStatsDependencyVals computes the degrees of the functional dependencies between the 2 columns.
+// Like PostgreSQL, the degree of `a => b` is the fraction of rows whose `a` value always comes with the
+// same `b` value in the samples.
+func (h *Handle) fillExtStatsDependencyVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
+	if len(item.ColIDs) != 2 {
+		return nil
+	}
+	rows, _, _, ok := h.extStatsSampleRows(item, cols, collectors)
+	if !ok {
+		return nil
+	}
+	forward, backward := dependencyDegree(rows, 0, 1), dependencyDegree(rows, 1, 0)
+	if err := item.EncodeDependencyDegrees(forward, backward); err != nil {
+		return nil
+	}
+	return item
+}
+
+func dependencyDegree(rows [][]string, from, to int) float64 {
+	if len(rows) == 0 {
+		return 0
+	}
+	type group struct {
+		val        string
+		count      int
+		determined bool
+	}
+	groups := make(map[string]*group)
+	for _, row := range rows {
+		g, ok := groups[row[from]]
+		if !ok {
+			groups[row[from]] = &group{val: row[to], count: 1, determined: true}
+			continue
+		}
+		g.count++
+		if g.val != row[to] {
+			g.determined = false
+		}
+	}
+	supported := 0
+	for _, g := range groups {
+		if g.determined {
+			supported += g.count
+		}
+	}
+	return float64(supported) / float64(len(rows))
+}
+
 func (h *Handle) fillExtStatsCorrVals(item *statistics.ExtendedStatsItem, cols []*model.ColumnInfo, collectors []*statistics.SampleCollector) *statistics.ExtendedStatsItem {
 	colOffsets := make([]int, 0, 2)
 	for _, id := range item.ColIDs {
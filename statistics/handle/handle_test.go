@@ -1949,6 +1949,73 @@ func (s *testStatsSuite) TestCorrelationStatsCompute(c *C) {
 	))
 }
 
+func (s *testStatsSuite) TestCardinalityAndDependencyStatsCompute(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int, d int)")
+	err := tk.ExecToErr("alter table t add stats_extended s1 cardinality(a)")
+	c.Assert(err.Error(), Equals, "Only support Cardinality statistics type on at least 2 columns")
+	err = tk.ExecToErr("alter table t add stats_extended s1 dependency(a,b,c)")
+	c.Assert(err.Error(), Equals, "Only support Correlation and Dependency statistics types on 2 columns")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%d, %d, %d, %d)", i%10, i%10, i, i%5))
+	}
+	tk.MustExec("insert into t values(null, null, null, null)")
+	tk.MustExec("alter table t add stats_extended s1 cardinality(a,b)")
+	tk.MustExec("alter table t add stats_extended s2 dependency(a,c)")
+	tk.MustExec("alter table t add stats_extended s3 dependency(a,d)")
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+		"0 [1,2] <nil> 0",
+		"1 [1,3] <nil> 0",
+		"1 [1,4] <nil> 0",
+	))
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+		"0 [1,2] 10.000000 1",
+		"1 [1,3] [0,1] 1",
+		"1 [1,4] [1,0] 1",
+	))
+	tk.MustExec("set @@session.tidb_analyze_version=3")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select type, column_ids, stats, status from mysql.stats_extended").Sort().Check(testkit.Rows(
+		"0 [1,2] 10.000000 1",
+		"1 [1,3] [0,1] 1",
+		"1 [1,4] [1,0] 1",
+	))
+	tk.MustQuery("show stats_extended where stats_name = 's1'").CheckAt([]int{2, 3, 4, 5}, testkit.Rows(
+		"s1 [a,b] cardinality 10.000000",
+	))
+
+	do := s.do
+	is := do.InfoSchema()
+	err = do.StatsHandle().Update(is)
+	c.Assert(err, IsNil)
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	statsTbl := do.StatsHandle().GetTableStats(tbl.Meta())
+	c.Assert(statsTbl.ExtendedStats, NotNil)
+	c.Assert(len(statsTbl.ExtendedStats.Stats), Equals, 3)
+	forward, backward, err := statsTbl.ExtendedStats.Stats["s2"].DependencyDegrees()
+	c.Assert(err, IsNil)
+	c.Assert(forward, Equals, float64(0))
+	c.Assert(backward, Equals, float64(1))
+
+	// The extended stats correct the selectivity of the equal conditions on the correlated columns.
+	checkEstRows := func(sql string, estRows string) {
+		rows := tk.MustQuery("explain format = 'brief' " + sql).Rows()
+		c.Assert(rows[0][1], Equals, estRows, Commentf("sql: %s", sql))
+	}
+	tk.MustExec("set session tidb_enable_extended_stats = off")
+	checkEstRows("select * from t where a = 1 and b = 1", "0.99")
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	checkEstRows("select * from t where a = 1 and b = 1", "10.00")
+	checkEstRows("select * from t where a = 1 and c = 1", "1.00")
+	checkEstRows("select * from t where a = 1 and d = 1", "10.00")
+	checkEstRows("select * from t where a = 1 and b > 1", "7.92")
+}
+
 func (s *testStatsSuite) TestSyncStatsExtendedRemoval(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	tk := testkit.NewTestKit(c, s.store)
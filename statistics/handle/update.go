@@ -585,7 +585,7 @@ OUTER:
 			if !ok {
 				continue
 			}
-			if table.Meta().Partition != nil {
+			if table.Meta().GetPartitionInfo() != nil {
 				// If the table is partition table, the feedback will not work.
 				continue
 			}
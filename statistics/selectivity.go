@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tidb/expression"
 	planutil "github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
@@ -273,6 +274,9 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 		}
 	}
 	usedSets := GetUsableSetsByGreedy(nodes)
+	if ctx.GetSessionVars().EnableExtendedStats {
+		usedSets = coll.mergeNodesByExtendedStats(sc, usedSets)
+	}
 	// Initialize the mask with the full set.
 	mask := (int64(1) << uint(len(remainedExprs))) - 1
 	for _, set := range usedSets {
@@ -395,6 +399,104 @@ func getMaskAndRanges(ctx sessionctx.Context, exprs []expression.Expression, ran
 	return mask, ranges, false, nil
 }
 
+// mergeNodesByExtendedStats uses the cardinality and dependency extended stats to correct the selectivity
+// of the equal conditions on the columns of the same column group, which would be underestimated by the
+// independence assumption. The column nodes covered by an extended stats item are replaced by a merged node.
+func (coll *HistColl) mergeNodesByExtendedStats(sc *stmtctx.StatementContext, usedSets []*StatsNode) []*StatsNode {
+	if coll.ExtendedStats == nil || len(coll.ExtendedStats.Stats) == 0 {
+		return usedSets
+	}
+	// colID2Node maps the column ID to the column node whose conditions are all point equal conditions.
+	colID2Node := make(map[int64]*StatsNode)
+	for _, set := range usedSets {
+		if set.Tp != ColType || set.partCover || len(set.Ranges) != 1 || !set.Ranges[0].IsPoint(sc) {
+			continue
+		}
+		if col, ok := coll.Columns[set.ID]; ok && col.Info != nil {
+			colID2Node[col.Info.ID] = set
+		}
+	}
+	if len(colID2Node) < 2 {
+		return usedSets
+	}
+	names := make([]string, 0, len(coll.ExtendedStats.Stats))
+	for name := range coll.ExtendedStats.Stats {
+		names = append(names, name)
+	}
+	// Prefer the item on more columns, and sort the items by name to make the result stable.
+	sort.Slice(names, func(i, j int) bool {
+		li, lj := len(coll.ExtendedStats.Stats[names[i]].ColIDs), len(coll.ExtendedStats.Stats[names[j]].ColIDs)
+		if li != lj {
+			return li > lj
+		}
+		return names[i] < names[j]
+	})
+	merged := make(map[*StatsNode]struct{})
+	for _, name := range names {
+		item := coll.ExtendedStats.Stats[name]
+		if item.Tp != ast.StatsTypeCardinality && item.Tp != ast.StatsTypeDependency {
+			continue
+		}
+		covered := make([]*StatsNode, 0, len(item.ColIDs))
+		for _, colID := range item.ColIDs {
+			node, ok := colID2Node[colID]
+			if !ok {
+				break
+			}
+			if _, ok = merged[node]; ok {
+				break
+			}
+			covered = append(covered, node)
+		}
+		if len(covered) != len(item.ColIDs) {
+			continue
+		}
+		var sel float64
+		switch item.Tp {
+		case ast.StatsTypeCardinality:
+			if item.ScalarVals < 1 {
+				continue
+			}
+			// The selectivity of the equal conditions on all the columns is at least 1/NDV of the column
+			// group, and it should not be larger than the selectivity of any single column.
+			sel = 1
+			minSel := 1.0
+			for _, node := range covered {
+				sel *= node.Selectivity
+				minSel = math.Min(minSel, node.Selectivity)
+			}
+			sel = math.Min(minSel, math.Max(sel, 1/item.ScalarVals))
+		case ast.StatsTypeDependency:
+			forward, backward, err := item.DependencyDegrees()
+			if err != nil {
+				continue
+			}
+			from, to, degree := covered[0], covered[1], forward
+			if backward > forward {
+				from, to, degree = covered[1], covered[0], backward
+			}
+			// P(a = x, b = y) = P(a = x) * (d + (1 - d) * P(b = y)), where d is the degree of `a => b`.
+			sel = from.Selectivity * (degree + (1-degree)*to.Selectivity)
+		}
+		mergedNode := &StatsNode{Tp: ColType, Selectivity: sel, numCols: len(covered)}
+		for _, node := range covered {
+			mergedNode.mask |= node.mask
+			merged[node] = struct{}{}
+		}
+		usedSets = append(usedSets, mergedNode)
+	}
+	if len(merged) == 0 {
+		return usedSets
+	}
+	newSets := make([]*StatsNode, 0, len(usedSets)-len(merged))
+	for _, set := range usedSets {
+		if _, ok := merged[set]; !ok {
+			newSets = append(newSets, set)
+		}
+	}
+	return newSets
+}
+
 // GetUsableSetsByGreedy will select the indices and pk used for calculate selectivity by greedy algorithm.
 func GetUsableSetsByGreedy(nodes []*StatsNode) (newBlocks []*StatsNode) {
 	sort.Slice(nodes, func(i int, j int) bool {
@@ -14,6 +14,7 @@
 package statistics
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"sort"
@@ -59,9 +60,8 @@ const (
 // Table represents statistics for a table.
 type Table struct {
 	HistColl
-	Version       uint64
-	Name          string
-	ExtendedStats *ExtendedStatsColl
+	Version uint64
+	Name    string
 	// TblInfoUpdateTS is the UpdateTS of the TableInfo used when filling this struct.
 	// It is the schema version of the corresponding table. It is used to skip redundant
 	// loading of stats, i.e, if the cached stats is already update-to-date with mysql.stats_xxx tables,
@@ -78,6 +78,30 @@ type ExtendedStatsItem struct {
 	StringVals string
 }
 
+// DependencyDegrees returns the degrees of the functional dependencies `ColIDs[0] => ColIDs[1]`
+// and `ColIDs[1] => ColIDs[0]` of a dependency extended stats item. The degree is the fraction
+// of rows in which the value of the first column determines the value of the second one.
+func (item *ExtendedStatsItem) DependencyDegrees() (float64, float64, error) {
+	var degrees []float64
+	if err := json.Unmarshal([]byte(item.StringVals), &degrees); err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	if len(degrees) != 2 {
+		return 0, 0, errors.Errorf("invalid dependency degrees %s", item.StringVals)
+	}
+	return degrees[0], degrees[1], nil
+}
+
+// EncodeDependencyDegrees encodes the degrees of the functional dependencies into StringVals.
+func (item *ExtendedStatsItem) EncodeDependencyDegrees(forward, backward float64) error {
+	bytes, err := json.Marshal([]float64{forward, backward})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	item.StringVals = string(bytes)
+	return nil
+}
+
 // ExtendedStatsColl is a collection of cached items for mysql.stats_extended records.
 type ExtendedStatsColl struct {
 	Stats             map[string]*ExtendedStatsItem
@@ -105,6 +129,9 @@ type HistColl struct {
 	// The physical id is used when try to load column stats from storage.
 	HavePhysicalID bool
 	Pseudo         bool
+	// ExtendedStats is the extended statistics of the table, it's used to estimate the
+	// selectivity of the conditions on the correlated columns.
+	ExtendedStats *ExtendedStatsColl
 }
 
 // MemoryUsage returns the total memory usage of this Table.
@@ -444,6 +471,7 @@ func (coll *HistColl) GenerateHistCollFromColumnInfo(infos []*model.ColumnInfo,
 		Indices:        newIdxHistMap,
 		ColID2IdxID:    colID2IdxID,
 		Idx2ColumnIDs:  idx2Columns,
+		ExtendedStats:  coll.ExtendedStats,
 	}
 	return newColl
 }
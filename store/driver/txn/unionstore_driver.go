@@ -34,6 +34,11 @@ func newMemBuffer(m *unionstore.MemDB) kv.MemBuffer {
 	return &memBuffer{MemDB: m}
 }
 
+// NewMemBuffer creates a standalone MemBuffer which is not bound to any transaction.
+func NewMemBuffer() kv.MemBuffer {
+	return newMemBuffer(unionstore.NewUnionStore(nil).GetMemBuffer())
+}
+
 func (m *memBuffer) Delete(k kv.Key) error {
 	return m.MemDB.Delete(k)
 }
@@ -140,6 +145,25 @@ func (it *tikvIterator) Key() kv.Key {
 	return kv.Key(it.Iterator.Key())
 }
 
+// NewUnionIter returns an iterator over dirtyIt stacked on snapshotIt. The entries of dirtyIt take precedence,
+// and an empty value in it means the key is deleted.
+func NewUnionIter(dirtyIt, snapshotIt kv.Iterator, reverse bool) (kv.Iterator, error) {
+	it, err := unionstore.NewUnionIter(&kvIterator{Iterator: dirtyIt}, &kvIterator{Iterator: snapshotIt}, reverse)
+	if err != nil {
+		return nil, derr.ToTiDBErr(err)
+	}
+	return &tikvIterator{Iterator: it}, nil
+}
+
+// kvIterator wraps kv.Iterator as unionstore.Iterator
+type kvIterator struct {
+	kv.Iterator
+}
+
+func (it *kvIterator) Key() []byte {
+	return it.Iterator.Key()
+}
+
 func getTiDBKeyFlags(flag tikvstore.KeyFlags) kv.KeyFlags {
 	var v kv.KeyFlags
 	if flag.HasPresumeKeyNotExists() {
@@ -705,8 +705,8 @@ func (h *Helper) GetRegionsTableInfo(regionsInfo *RegionsInfo, schemas []*model.
 	tables := []tableInfoWithKeyRange{}
 	for _, db := range schemas {
 		for _, table := range db.Tables {
-			if table.Partition != nil {
-				for _, partition := range table.Partition.Definitions {
+			if pi := table.GetPartitionInfo(); pi != nil {
+				for _, partition := range pi.Definitions {
 					tables = append(tables, newPartitionTableWithKeyRange(db, table, partition.ID))
 				}
 			} else {
@@ -747,11 +747,12 @@ func (tm *ttlManager) keepAlive(c *twoPhaseCommitter) {
 			if err != nil {
 				logutil.Logger(bo.GetCtx()).Warn("keepAlive get tso fail",
 					zap.Error(err))
+				tm.setLockExpiredIfNoLifetime(c)
 				return
 			}
 
 			uptime := uint64(oracle.ExtractPhysical(now) - oracle.ExtractPhysical(c.startTS))
-			if uptime > config.GetGlobalConfig().MaxTxnTTL {
+			if uptime > config.GetGlobalConfig().MaxTxnTTL && !c.txn.ignoreMaxTxnTTL {
 				// Checks maximum lifetime for the ttlManager, so when something goes wrong
 				// the key will not be locked forever.
 				logutil.Logger(bo.GetCtx()).Info("ttlManager live up to its lifetime",
@@ -761,9 +762,7 @@ func (tm *ttlManager) keepAlive(c *twoPhaseCommitter) {
 				metrics.TiKVTTLLifeTimeReachCounter.Inc()
 				// the pessimistic locks may expire if the ttl manager has timed out, set `LockExpired` flag
 				// so that this transaction could only commit or rollback with no more statement executions
-				if c.isPessimistic && tm.lockCtx != nil && tm.lockCtx.LockExpired != nil {
-					atomic.StoreUint32(tm.lockCtx.LockExpired, 1)
-				}
+				tm.setLockExpired(c)
 				return
 			}
 
@@ -777,6 +776,7 @@ func (tm *ttlManager) keepAlive(c *twoPhaseCommitter) {
 				logutil.Logger(bo.GetCtx()).Warn("send TxnHeartBeat failed",
 					zap.Error(err),
 					zap.Uint64("txnStartTS", c.startTS))
+				tm.setLockExpiredIfNoLifetime(c)
 				return
 			}
 			metrics.TxnHeartBeatHistogramOK.Observe(time.Since(startTime).Seconds())
@@ -784,6 +784,21 @@ func (tm *ttlManager) keepAlive(c *twoPhaseCommitter) {
 	}
 }
 
+func (tm *ttlManager) setLockExpired(c *twoPhaseCommitter) {
+	if c.isPessimistic && tm.lockCtx != nil && tm.lockCtx.LockExpired != nil {
+		atomic.StoreUint32(tm.lockCtx.LockExpired, 1)
+	}
+}
+
+// setLockExpiredIfNoLifetime sets the `LockExpired` flag when the ttlManager
+// exits early for a transaction which ignores `max-txn-ttl`. Such a transaction
+// relies on its locks being held, so it must learn that they may be gone.
+func (tm *ttlManager) setLockExpiredIfNoLifetime(c *twoPhaseCommitter) {
+	if c.txn.ignoreMaxTxnTTL {
+		tm.setLockExpired(c)
+	}
+}
+
 func sendTxnHeartBeat(bo *Backoffer, store *KVStore, primary []byte, startTS, ttl uint64) (uint64, error) {
 	req := tikvrpc.NewRequest(tikvrpc.CmdTxnHeartBeat, &pb.TxnHeartBeatRequest{
 		PrimaryLock:   primary,
@@ -108,6 +108,7 @@ type KVTxn struct {
 	enableAsyncCommit  bool
 	enable1PC          bool
 	causalConsistency  bool
+	ignoreMaxTxnTTL    bool
 	scope              string
 	kvFilter           KVFilter
 	resourceGroupTag   []byte
@@ -266,6 +267,13 @@ func (txn *KVTxn) SetCausalConsistency(b bool) {
 	txn.causalConsistency = b
 }
 
+// SetIgnoreMaxTxnTTL indicates if the pessimistic locks of the transaction
+// are kept alive beyond `max-txn-ttl`, until the transaction ends or the
+// TiDB instance crashes.
+func (txn *KVTxn) SetIgnoreMaxTxnTTL(b bool) {
+	txn.ignoreMaxTxnTTL = b
+}
+
 // SetScope sets the geographical scope of the transaction.
 func (txn *KVTxn) SetScope(scope string) {
 	txn.scope = scope
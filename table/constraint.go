@@ -0,0 +1,76 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// Constraint provides meta and the expression of a check constraint.
+type Constraint struct {
+	*model.ConstraintInfo
+	ConstraintExpr expression.Expression
+}
+
+// BuildWritableConstraints builds the check constraints which should be checked when writing rows,
+// they are the enforced constraints in the write-only or public state.
+// The expressions are evaluated on the rows whose columns are in the order of tblInfo.Cols().
+func BuildWritableConstraints(ctx sessionctx.Context, tblInfo *model.TableInfo) ([]*Constraint, error) {
+	if len(tblInfo.Constraints) == 0 {
+		return nil, nil
+	}
+	constraints := make([]*Constraint, 0, len(tblInfo.Constraints))
+	for _, constrInfo := range tblInfo.Constraints {
+		if !constrInfo.Enforced || (constrInfo.State != model.StateWriteOnly && constrInfo.State != model.StatePublic) {
+			continue
+		}
+		expr, err := expression.ParseSimpleExprWithTableInfo(ctx, constrInfo.ExprString, tblInfo)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		constraints = append(constraints, &Constraint{ConstraintInfo: constrInfo, ConstraintExpr: expr})
+	}
+	return constraints, nil
+}
+
+// CheckRowConstraints checks whether the row satisfies the check constraints.
+// A constraint is violated only if its expression is evaluated to FALSE, NULL is regarded as satisfied.
+func CheckRowConstraints(ctx sessionctx.Context, constraints []*Constraint, row []types.Datum) error {
+	if len(constraints) == 0 {
+		return nil
+	}
+	r := chunk.MutRowFromDatums(row).ToRow()
+	for _, constraint := range constraints {
+		val, err := constraint.ConstraintExpr.Eval(r)
+		if err != nil {
+			return err
+		}
+		if val.IsNull() {
+			continue
+		}
+		b, err := val.ToBool(ctx.GetSessionVars().StmtCtx)
+		if err != nil {
+			return err
+		}
+		if b == 0 {
+			return ErrCheckConstraintViolated.GenWithStackByArgs(constraint.Name.O)
+		}
+	}
+	return nil
+}
@@ -29,6 +29,8 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/rowcodec"
 )
 
@@ -139,10 +141,113 @@ func (c *index) GenIndexKey(sc *stmtctx.StatementContext, indexedValues []types.
 	return tablecodec.GenIndexKey(sc, c.tblInfo, c.idxInfo, idxTblID, indexedValues, h, buf)
 }
 
+// SplitIndexValues splits the indexed values of a multi-valued index into the ones of its entries. There is an
+// entry for each distinct element of the array, and no entry for an empty array. The indexed values of the other
+// indexes are returned as they are.
+func SplitIndexValues(sc *stmtctx.StatementContext, tblInfo *model.TableInfo, idxInfo *model.IndexInfo, indexedValues []types.Datum) ([][]types.Datum, error) {
+	if !idxInfo.MVIndex {
+		return [][]types.Datum{indexedValues}, nil
+	}
+	pos := -1
+	var elemTp *types.FieldType
+	for i, idxCol := range idxInfo.Columns {
+		if tblInfo.Columns[idxCol.Offset].FieldType.ArrayElem != nil {
+			pos, elemTp = i, tblInfo.Columns[idxCol.Offset].FieldType.ArrayElem
+			break
+		}
+	}
+	if pos < 0 || indexedValues[pos].IsNull() {
+		return [][]types.Datum{indexedValues}, nil
+	}
+	arr := indexedValues[pos].GetMysqlJSON()
+	if arr.TypeCode != json.TypeCodeArray {
+		return nil, errors.Errorf("the value of the multi-valued index %s isn't an array", idxInfo.Name)
+	}
+	elems, err := ArrayElems2Datums(sc, arr, elemTp)
+	if err != nil {
+		return nil, err
+	}
+	splitValues := make([][]types.Datum, 0, len(elems))
+	for _, elem := range elems {
+		vals := make([]types.Datum, len(indexedValues))
+		copy(vals, indexedValues)
+		vals[pos] = elem
+		splitValues = append(splitValues, vals)
+	}
+	return splitValues, nil
+}
+
+// ArrayElems2Datums converts the distinct elements of an array, which has been converted by
+// `CAST(... AS ... ARRAY)`, to the values of the multi-valued index entries.
+func ArrayElems2Datums(sc *stmtctx.StatementContext, arr json.BinaryJSON, elemTp *types.FieldType) ([]types.Datum, error) {
+	elems := make([]types.Datum, 0, arr.GetElemCount())
+	encodedElems := make(map[string]struct{}, arr.GetElemCount())
+	for i := 0; i < arr.GetElemCount(); i++ {
+		elem := jsonArrayElem2Datum(arr.ArrayGetElem(i), elemTp)
+		encoded, err := codec.EncodeKey(sc, nil, elem)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := encodedElems[string(encoded)]; ok {
+			continue
+		}
+		encodedElems[string(encoded)] = struct{}{}
+		elems = append(elems, elem)
+	}
+	return elems, nil
+}
+
+// jsonArrayElem2Datum converts an element of the array of a multi-valued index, which has been converted to
+// the element type by `CAST(... AS ... ARRAY)`, to a datum.
+func jsonArrayElem2Datum(elem json.BinaryJSON, elemTp *types.FieldType) types.Datum {
+	var d types.Datum
+	switch elem.TypeCode {
+	case json.TypeCodeInt64:
+		d.SetInt64(elem.GetInt64())
+	case json.TypeCodeUint64:
+		d.SetUint64(elem.GetUint64())
+	case json.TypeCodeFloat64:
+		d.SetFloat64(elem.GetFloat64())
+	case json.TypeCodeString:
+		d.SetString(string(elem.GetString()), elemTp.Collate)
+	}
+	return d
+}
+
 // Create creates a new entry in the kvIndex data.
 // If the index is unique and there is an existing entry with the same key,
 // Create will return the existing entry's handle as the first return value, ErrKeyExists as the second return value.
+// For a multi-valued index, an entry is created for each element of the array.
 func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) (kv.Handle, error) {
+	if !c.idxInfo.MVIndex {
+		return c.create(sctx, txn, indexedValues, h, handleRestoreData, opts...)
+	}
+	splitValues, err := SplitIndexValues(sctx.GetSessionVars().StmtCtx, c.tblInfo, c.idxInfo, indexedValues)
+	if err != nil {
+		return nil, err
+	}
+	for _, vals := range splitValues {
+		dupHandle, err := c.create(sctx, txn, vals, h, handleRestoreData, opts...)
+		if err != nil {
+			if kv.ErrKeyExists.Equal(err) && h.Equal(dupHandle) {
+				// The entry has been created for the row, like the ones which are backfilled.
+				continue
+			}
+			if kv.ErrKeyExists.Equal(err) {
+				// The duplicate entry is the element rather than the whole array.
+				entryKey, err1 := genIndexKeyStr(vals)
+				if err1 != nil {
+					return nil, err1
+				}
+				err = kv.ErrKeyExists.FastGenByArgs(entryKey, c.idxInfo.Name.String())
+			}
+			return dupHandle, err
+		}
+	}
+	return nil, nil
+}
+
+func (c *index) create(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) (kv.Handle, error) {
 	if c.Meta().Unique {
 		txn.CacheTableInfo(c.phyTblID, c.tblInfo)
 	}
@@ -199,7 +304,9 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 	}
 
 	var value []byte
-	if sctx.GetSessionVars().LazyCheckKeyNotExists() {
+	// The data of local temporary tables is never committed to TiKV, so the keys can't be checked lazily.
+	lazyCheck := sctx.GetSessionVars().LazyCheckKeyNotExists() && c.tblInfo.TempTableType != model.TempTableLocal
+	if lazyCheck {
 		value, err = txn.GetMemBuffer().Get(ctx, key)
 	} else {
 		value, err = txn.Get(ctx, key)
@@ -208,7 +315,7 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 		return nil, err
 	}
 	if err != nil || len(value) == 0 {
-		if sctx.GetSessionVars().LazyCheckKeyNotExists() && err != nil {
+		if lazyCheck && err != nil {
 			err = txn.GetMemBuffer().SetWithFlags(key, idxVal, kv.SetPresumeKeyNotExists)
 		} else {
 			err = txn.GetMemBuffer().Set(key, idxVal)
@@ -224,17 +331,27 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 }
 
 // Delete removes the entry for handle h and indexedValues from KV index.
+// For a multi-valued index, the entries of all the elements of the array are removed.
 func (c *index) Delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) error {
-	key, distinct, err := c.GenIndexKey(sc, indexedValues, h, nil)
+	splitValues, err := SplitIndexValues(sc, c.tblInfo, c.idxInfo, indexedValues)
 	if err != nil {
 		return err
 	}
-	if distinct {
-		err = txn.GetMemBuffer().DeleteWithFlags(key, kv.SetNeedLocked)
-	} else {
-		err = txn.GetMemBuffer().Delete(key)
+	for _, vals := range splitValues {
+		key, distinct, err := c.GenIndexKey(sc, vals, h, nil)
+		if err != nil {
+			return err
+		}
+		if distinct {
+			err = txn.GetMemBuffer().DeleteWithFlags(key, kv.SetNeedLocked)
+		} else {
+			err = txn.GetMemBuffer().Delete(key)
+		}
+		if err != nil {
+			return err
+		}
 	}
-	return err
+	return nil
 }
 
 // Drop removes the KV index from store.
@@ -296,7 +413,23 @@ func (c *index) SeekFirst(r kv.Retriever) (iter table.IndexIterator, err error)
 	return &indexIter{it: it, idx: c, prefix: c.prefix, colInfos: colInfos, tps: tps}, nil
 }
 
+// Exist checks whether the entry for handle h and indexedValues exists in KV index.
+// For a multi-valued index, it checks the entries of all the elements of the array.
 func (c *index) Exist(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) (bool, kv.Handle, error) {
+	splitValues, err := SplitIndexValues(sc, c.tblInfo, c.idxInfo, indexedValues)
+	if err != nil {
+		return false, nil, err
+	}
+	for _, vals := range splitValues {
+		exist, handle, err := c.exist(sc, txn, vals, h)
+		if !exist || err != nil {
+			return exist, handle, err
+		}
+	}
+	return true, h, nil
+}
+
+func (c *index) exist(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) (bool, kv.Handle, error) {
 	key, distinct, err := c.GenIndexKey(sc, indexedValues, h, nil)
 	if err != nil {
 		return false, nil, err
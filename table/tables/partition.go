@@ -80,6 +80,9 @@ type partitionedTable struct {
 	partitions      map[int64]*partition
 	evalBufferTypes []*types.FieldType
 	evalBufferPool  sync.Pool
+
+	// reorgTable is the table on the other side of a partition reorganization, the writes are also applied to it.
+	reorgTable *reorgTable
 }
 
 func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Table, error) {
@@ -109,9 +112,257 @@ func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Tabl
 		partitions[p.ID] = &t
 	}
 	ret.partitions = partitions
+	ret.reorgTable, err = newReorgTable(tbl, tblInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return ret, nil
 }
 
+// reorgTable is the table with the partition layout on the other side of a partition reorganization.
+// The reorganization records the states of the physical tables in PartitionInfo.States:
+//   1. Before the new partitions are public, they are in AddingDefinitions with the delete-only,
+//      write-only or write-reorganization state.
+//   2. After the new partitions are public, they are marked as public and the old partitions
+//      are in DroppingDefinitions with the delete-reorganization state.
+// When a table is partitioned or its partitioning is removed, the table itself is the only
+// physical table on the non-partitioned side, and PartitionInfo.Enable is false when that side
+// is the current one.
+type reorgTable struct {
+	// partitioned is the table on the other side if it's partitioned, otherwise it's tbl.
+	partitioned *partitionedTable
+	tbl         *TableCommon
+	// states are the states of the physical tables which are being reorganized.
+	states map[int64]model.SchemaState
+	// mustLocate indicates the written rows must be located on the other side. It's true before
+	// the other side replaces the current one, otherwise the rows would be lost after that.
+	mustLocate bool
+}
+
+// newReorgTable returns the table on the other side of the partition reorganization,
+// or nil if the table isn't being reorganized.
+func newReorgTable(tbl *TableCommon, tblInfo *model.TableInfo) (*reorgTable, error) {
+	pi := tblInfo.Partition
+	if pi == nil || len(pi.States) == 0 {
+		return nil, nil
+	}
+	states := make(map[int64]model.SchemaState, len(pi.States))
+	for _, st := range pi.States {
+		states[st.ID] = st.State
+	}
+	var removed, added []model.PartitionDefinition
+	if len(pi.AddingDefinitions) > 0 {
+		removed, added = pi.DroppingDefinitions, pi.AddingDefinitions
+	} else {
+		for _, def := range pi.Definitions {
+			if states[def.ID] == model.StatePublic {
+				removed = append(removed, def)
+			}
+		}
+		added = pi.DroppingDefinitions
+	}
+	reorgStates := make(map[int64]model.SchemaState, len(added))
+	for _, def := range added {
+		if st, ok := states[def.ID]; ok {
+			reorgStates[def.ID] = st
+		}
+	}
+	if len(reorgStates) == 0 {
+		return nil, nil
+	}
+
+	reorgTblInfo := reorganizedTableInfo(tblInfo, removed, added)
+	common := *tbl
+	common.meta = reorgTblInfo
+	ret := &reorgTable{states: reorgStates, mustLocate: len(pi.AddingDefinitions) > 0}
+	if reorgTblInfo.Partition == nil {
+		if err := initTableIndices(&common); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ret.tbl = &common
+		return ret, nil
+	}
+	partitioned, err := newPartitionedTable(&common, reorgTblInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ret.partitioned = partitioned.(*partitionedTable)
+	return ret, nil
+}
+
+// ReorganizedTableInfo returns the table info after the partitions which are being reorganized
+// are replaced by the new ones.
+func ReorganizedTableInfo(tblInfo *model.TableInfo) *model.TableInfo {
+	pi := tblInfo.Partition
+	return reorganizedTableInfo(tblInfo, pi.DroppingDefinitions, pi.AddingDefinitions)
+}
+
+func reorganizedTableInfo(tblInfo *model.TableInfo, removed, added []model.PartitionDefinition) *model.TableInfo {
+	pi := *tblInfo.Partition
+	var defs []model.PartitionDefinition
+	if pi.Enable {
+		defs = pi.Definitions
+	}
+	pi.Definitions = ReplacePartitionDefinitions(defs, removed, added)
+	pi.AddingDefinitions, pi.DroppingDefinitions, pi.States = nil, nil, nil
+	pi.Enable = true
+	newTblInfo := tblInfo.Clone()
+	if len(pi.Definitions) == 1 && pi.Definitions[0].ID == tblInfo.ID {
+		// The table itself is the only physical table, so it isn't partitioned.
+		newTblInfo.Partition = nil
+		return newTblInfo
+	}
+	if pi.Type == model.PartitionTypeHash {
+		pi.Num = uint64(len(pi.Definitions))
+	}
+	newTblInfo.Partition = &pi
+	return newTblInfo
+}
+
+// physicalTableByRow returns the physical table which the row should be written to,
+// or nil if there is no such table.
+func (t *reorgTable) physicalTableByRow(ctx sessionctx.Context, r []types.Datum, forDelete bool) (table.PhysicalTable, error) {
+	var p table.PhysicalTable = t.tbl
+	if t.partitioned != nil {
+		pid, err := t.partitioned.locatePartition(ctx, t.partitioned.meta.GetPartitionInfo(), r)
+		if err != nil {
+			// A row which can't be located on the other side can't be written before
+			// that side replaces the current one, otherwise it would be lost.
+			if !forDelete && t.mustLocate {
+				return nil, errors.Trace(err)
+			}
+			return nil, nil
+		}
+		p = t.partitioned.partitions[pid]
+	}
+	state, ok := t.states[p.GetPhysicalID()]
+	if !ok || (!forDelete && state == model.StateDeleteOnly) {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// addRecord adds the record to the physical table, the record uses the same handle as the
+// one on the current side.
+func (t *reorgTable) addRecord(ctx sessionctx.Context, p table.PhysicalTable, h kv.Handle, r []types.Datum, opts []table.AddRecordOption) error {
+	meta := p.Meta()
+	if !meta.PKIsHandle && !meta.IsCommonHandle {
+		cols := p.Cols()
+		row := make([]types.Datum, 0, len(cols)+1)
+		row = append(row, r[:len(cols)]...)
+		r = append(row, types.NewIntDatum(h.IntValue()))
+		reorgOpts := make([]table.AddRecordOption, 0, len(opts))
+		for _, opt := range opts {
+			// The handle is given, it mustn't be allocated again.
+			if opt != table.IsUpdate {
+				reorgOpts = append(reorgOpts, opt)
+			}
+		}
+		opts = reorgOpts
+	}
+	_, err := p.AddRecord(ctx, r, opts...)
+	return errors.Trace(err)
+}
+
+// AddRecord applies the added record to the other side.
+func (t *reorgTable) AddRecord(ctx sessionctx.Context, h kv.Handle, r []types.Datum, opts []table.AddRecordOption) error {
+	p, err := t.physicalTableByRow(ctx, r, false)
+	if err != nil || p == nil {
+		return errors.Trace(err)
+	}
+	return t.addRecord(ctx, p, h, r, opts)
+}
+
+// RemoveRecord applies the removed record to the other side.
+func (t *reorgTable) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r []types.Datum) error {
+	p, err := t.physicalTableByRow(ctx, r, true)
+	if err != nil || p == nil {
+		return errors.Trace(err)
+	}
+	return p.RemoveRecord(ctx, h, r)
+}
+
+// UpdateRecord applies the update to the other side.
+func (t *reorgTable) UpdateRecord(gctx context.Context, ctx sessionctx.Context, h, newHandle kv.Handle, currData, newData []types.Datum, touched []bool) error {
+	from, err := t.physicalTableByRow(ctx, currData, true)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	to, err := t.physicalTableByRow(ctx, newData, false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if from != nil && from == to && h.Equal(newHandle) {
+		return from.UpdateRecord(gctx, ctx, h, currData, newData, touched)
+	}
+	if to != nil {
+		if err = t.addRecord(ctx, to, newHandle, newData, nil); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if from != nil {
+		return from.RemoveRecord(ctx, h, currData)
+	}
+	return nil
+}
+
+// nonPartitionedReorgTable is a non-partitioned table which is being partitioned, or whose partitioning
+// is being removed. The writes are also applied to the partitioned table on the other side.
+type nonPartitionedReorgTable struct {
+	TableCommon
+	reorgTable *reorgTable
+}
+
+// AddRecord implements table.Table AddRecord interface.
+func (t *nonPartitionedReorgTable) AddRecord(ctx sessionctx.Context, r []types.Datum, opts ...table.AddRecordOption) (kv.Handle, error) {
+	h, err := t.TableCommon.AddRecord(ctx, r, opts...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return h, t.reorgTable.AddRecord(ctx, h, r, opts)
+}
+
+// RemoveRecord implements table.Table RemoveRecord interface.
+func (t *nonPartitionedReorgTable) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r []types.Datum) error {
+	if err := t.TableCommon.RemoveRecord(ctx, h, r); err != nil {
+		return errors.Trace(err)
+	}
+	return t.reorgTable.RemoveRecord(ctx, h, r)
+}
+
+// UpdateRecord implements table.Table UpdateRecord interface.
+func (t *nonPartitionedReorgTable) UpdateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, currData, newData []types.Datum, touched []bool) error {
+	if err := t.TableCommon.UpdateRecord(ctx, sctx, h, currData, newData, touched); err != nil {
+		return errors.Trace(err)
+	}
+	return t.reorgTable.UpdateRecord(ctx, sctx, h, h, currData, newData, touched)
+}
+
+// ReplacePartitionDefinitions replaces the removed definitions in defs with the added ones,
+// the added definitions are put at the position of the first removed one.
+func ReplacePartitionDefinitions(defs, removed, added []model.PartitionDefinition) []model.PartitionDefinition {
+	removedIDs := make(map[int64]struct{}, len(removed))
+	for _, def := range removed {
+		removedIDs[def.ID] = struct{}{}
+	}
+	newDefs := make([]model.PartitionDefinition, 0, len(defs)-len(removed)+len(added))
+	replaced := false
+	for _, def := range defs {
+		if _, ok := removedIDs[def.ID]; !ok {
+			newDefs = append(newDefs, def)
+			continue
+		}
+		if !replaced {
+			newDefs = append(newDefs, added...)
+			replaced = true
+		}
+	}
+	if !replaced {
+		newDefs = append(newDefs, added...)
+	}
+	return newDefs
+}
+
 func newPartitionExpr(tblInfo *model.TableInfo) (*PartitionExpr, error) {
 	ctx := mock.NewContext()
 	dbName := model.NewCIStr(ctx.GetSessionVars().CurrentDB)
@@ -1111,7 +1362,14 @@ func partitionedTableAddRecord(ctx sessionctx.Context, t *partitionedTable, r []
 		}
 	}
 	tbl := t.GetPartition(pid)
-	return tbl.AddRecord(ctx, r, opts...)
+	recordID, err = tbl.AddRecord(ctx, r, opts...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if t.reorgTable != nil {
+		err = t.reorgTable.AddRecord(ctx, recordID, r, opts)
+	}
+	return recordID, errors.Trace(err)
 }
 
 // partitionTableWithGivenSets is used for this kind of grammar: partition (p0,p1)
@@ -1155,7 +1413,14 @@ func (t *partitionedTable) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r [
 	}
 
 	tbl := t.GetPartition(pid)
-	return tbl.RemoveRecord(ctx, h, r)
+	err = tbl.RemoveRecord(ctx, h, r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if t.reorgTable != nil {
+		err = t.reorgTable.RemoveRecord(ctx, h, r)
+	}
+	return errors.Trace(err)
 }
 
 func (t *partitionedTable) GetAllPartitionIDs() []int64 {
@@ -1200,7 +1465,7 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 	// The old and new data locate in different partitions.
 	// Remove record from old partition and add record to new partition.
 	if from != to {
-		_, err = t.GetPartition(to).AddRecord(ctx, newData)
+		newHandle, err := t.GetPartition(to).AddRecord(ctx, newData)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -1215,11 +1480,21 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 			logutil.BgLogger().Error("update partition record fails", zap.String("message", "new record inserted while old record is not removed"), zap.Error(err))
 			return errors.Trace(err)
 		}
-		return nil
+		if t.reorgTable != nil {
+			err = t.reorgTable.UpdateRecord(gctx, ctx, h, newHandle, currData, newData, touched)
+		}
+		return errors.Trace(err)
 	}
 
 	tbl := t.GetPartition(to)
-	return tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched)
+	err = tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if t.reorgTable != nil {
+		err = t.reorgTable.UpdateRecord(gctx, ctx, h, h, currData, newData, touched)
+	}
+	return errors.Trace(err)
 }
 
 // FindPartitionByName finds partition in table meta by name.
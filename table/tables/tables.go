@@ -144,6 +144,13 @@ func TableFromMeta(allocs autoid.Allocators, tblInfo *model.TableInfo) (table.Ta
 		if err := initTableIndices(&t); err != nil {
 			return nil, err
 		}
+		reorgTbl, err := newReorgTable(&t, tblInfo)
+		if err != nil {
+			return nil, err
+		}
+		if reorgTbl != nil {
+			return &nonPartitionedReorgTable{TableCommon: t, reorgTable: reorgTbl}, nil
+		}
 		return &t, nil
 	}
 
@@ -748,7 +755,8 @@ func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts .
 	var setPresume bool
 	skipCheck := sctx.GetSessionVars().StmtCtx.BatchCheck
 	if (t.meta.IsCommonHandle || t.meta.PKIsHandle) && !skipCheck && !opt.SkipHandleCheck {
-		if sctx.GetSessionVars().LazyCheckKeyNotExists() {
+		// The data of local temporary tables is never committed to TiKV, so the keys can't be checked lazily.
+		if sctx.GetSessionVars().LazyCheckKeyNotExists() && t.meta.TempTableType != model.TempTableLocal {
 			var v []byte
 			v, err = txn.GetMemBuffer().Get(ctx, key)
 			if err != nil {
@@ -853,7 +861,8 @@ func (t *TableCommon) addIndices(sctx sessionctx.Context, recordID kv.Handle, r
 			return nil, err
 		}
 		var dupErr error
-		if !skipCheck && v.Meta().Unique {
+		// The multi-valued index generates the error of its duplicate element.
+		if !skipCheck && v.Meta().Unique && !v.Meta().MVIndex {
 			entryKey, err := genIndexKeyStr(indexVals)
 			if err != nil {
 				return nil, err
@@ -864,6 +873,9 @@ func (t *TableCommon) addIndices(sctx sessionctx.Context, recordID kv.Handle, r
 		rsData := TryGetHandleRestoredDataWrapper(t, r, nil, v.Meta())
 		if dupHandle, err := v.Create(sctx, txn, indexVals, recordID, rsData, opts...); err != nil {
 			if kv.ErrKeyExists.Equal(err) {
+				if !skipCheck && v.Meta().MVIndex {
+					return dupHandle, err
+				}
 				return dupHandle, dupErr
 			}
 			return nil, err
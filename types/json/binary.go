@@ -210,6 +210,11 @@ func (bj BinaryJSON) arrayGetElem(idx int) BinaryJSON {
 	return bj.valEntryGet(headerSize + idx*valEntrySize)
 }
 
+// ArrayGetElem gets the idx-th element of the Array.
+func (bj BinaryJSON) ArrayGetElem(idx int) BinaryJSON {
+	return bj.arrayGetElem(idx)
+}
+
 func (bj BinaryJSON) objectGetKey(i int) []byte {
 	keyOff := int(endian.Uint32(bj.Value[headerSize+i*keyEntrySize:]))
 	keyLen := int(endian.Uint16(bj.Value[headerSize+i*keyEntrySize+keyLenOff:]))
@@ -227,6 +227,11 @@ func (bj BinaryJSON) Extract(pathExprList []PathExpression) (ret BinaryJSON, fou
 	return
 }
 
+// ExtractAll returns all the values matched by pathExpr in bj, without wrapping them into an array.
+func (bj BinaryJSON) ExtractAll(pathExpr PathExpression) []BinaryJSON {
+	return bj.extractTo(nil, pathExpr)
+}
+
 func (bj BinaryJSON) extractTo(buf []BinaryJSON, pathExpr PathExpression) []BinaryJSON {
 	if len(pathExpr.legs) == 0 {
 		return append(buf, bj)
@@ -930,6 +935,51 @@ func ContainsBinary(obj, target BinaryJSON) bool {
 	}
 }
 
+// OverlapsBinary checks whether two JSON documents have any key-value pairs or array elements in common:
+// 1) two objects overlap if and only if they have a key in common and the values associated with the key are equal;
+// 2) two arrays overlap if and only if they have an element in common;
+// 3) a nonarray is treated as an array wrapping it when it's compared with an array;
+// 4) two scalars overlap if and only if they are comparable and are equal;
+func OverlapsBinary(left, right BinaryJSON) bool {
+	if left.TypeCode == TypeCodeObject && right.TypeCode == TypeCodeObject {
+		len := right.GetElemCount()
+		for i := 0; i < len; i++ {
+			if val, exists := left.objectSearchKey(right.objectGetKey(i)); exists && CompareBinary(val, right.objectGetVal(i)) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+	if left.TypeCode != TypeCodeArray {
+		left, right = right, left
+	}
+	if left.TypeCode != TypeCodeArray {
+		return CompareBinary(left, right) == 0
+	}
+	len := left.GetElemCount()
+	for i := 0; i < len; i++ {
+		if MemberOfBinary(left.arrayGetElem(i), right) {
+			return true
+		}
+	}
+	return false
+}
+
+// MemberOfBinary checks whether target is an element of the JSON array obj, a nonarray obj is treated as
+// an array wrapping it.
+func MemberOfBinary(target, obj BinaryJSON) bool {
+	if obj.TypeCode != TypeCodeArray {
+		return CompareBinary(obj, target) == 0
+	}
+	len := obj.GetElemCount()
+	for i := 0; i < len; i++ {
+		if CompareBinary(obj.arrayGetElem(i), target) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // GetElemDepth for JSON_DEPTH
 // Returns the maximum depth of a JSON document
 // rules referenced by MySQL JSON_DEPTH function
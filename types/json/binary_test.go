@@ -388,6 +388,40 @@ func (s *testJSONSuite) TestBinaryJSONContains(c *C) {
 	}
 }
 
+func (s *testJSONSuite) TestBinaryJSONOverlapsAndMemberOf(c *C) {
+	c.Parallel()
+	var tests = []struct {
+		left     string
+		right    string
+		overlaps bool
+		memberOf bool
+	}{
+		{`[1,2]`, `[2,3]`, true, false},
+		{`[1,2]`, `[3,4]`, false, false},
+		{`[1,[2,3]]`, `[[2,3]]`, true, false},
+		{`[2,3]`, `[1,[2,3]]`, false, true},
+		{`[1,2]`, `2`, true, false},
+		{`2`, `[1,2]`, true, true},
+		{`"2"`, `[1,2]`, false, false},
+		{`1`, `1`, true, true},
+		{`1`, `1.0`, true, true},
+		{`{"a":1}`, `[{"a":1}]`, true, true},
+		{`{"a":1,"b":2}`, `{"b":2,"c":3}`, true, false},
+		{`{"a":1,"b":2}`, `{"b":3,"c":2}`, false, false},
+		{`{"a":1}`, `{"a":1}`, true, true},
+		{`{"a":[1]}`, `{"a":1}`, false, false},
+		{`[]`, `[]`, false, false},
+	}
+
+	for _, tt := range tests {
+		left := mustParseBinaryFromString(c, tt.left)
+		right := mustParseBinaryFromString(c, tt.right)
+		c.Assert(OverlapsBinary(left, right), Equals, tt.overlaps, Commentf("%s %s", tt.left, tt.right))
+		c.Assert(OverlapsBinary(right, left), Equals, tt.overlaps, Commentf("%s %s", tt.left, tt.right))
+		c.Assert(MemberOfBinary(left, right), Equals, tt.memberOf, Commentf("%s %s", tt.left, tt.right))
+	}
+}
+
 func (s *testJSONSuite) TestBinaryJSONCopy(c *C) {
 	c.Parallel()
 	strs := []string{
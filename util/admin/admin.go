@@ -117,10 +117,28 @@ func IsJobRollbackable(job *model.Job) bool {
 		model.ActionModifyTableCharsetAndCollate, model.ActionTruncateTablePartition,
 		model.ActionModifySchemaCharsetAndCollate, model.ActionRepairTable, model.ActionModifyTableAutoIdCache:
 		return job.SchemaState == model.StateNone
+	case model.ActionMultiSchemaChange:
+		// The dropping sub-jobs run after the adding ones are published, then the job can't be rolled back.
+		return isMultiSchemaChangeRevertible(job)
 	}
 	return true
 }
 
+// isMultiSchemaChangeRevertible decodes the revertible flag of the multi-schema change job's argument.
+func isMultiSchemaChangeRevertible(job *model.Job) bool {
+	var rawArgs []json.RawMessage
+	if err := json.Unmarshal(job.RawArgs, &rawArgs); err != nil || len(rawArgs) == 0 {
+		return true
+	}
+	var info struct {
+		Revertible bool `json:"revertible"`
+	}
+	if err := json.Unmarshal(rawArgs[0], &info); err != nil {
+		return true
+	}
+	return info.Revertible
+}
+
 // CancelJobs cancels the DDL jobs.
 func CancelJobs(txn kv.Transaction, ids []int64) ([]error, error) {
 	if len(ids) == 0 {
@@ -491,4 +491,6 @@ const (
 	LabelForApplyCache int = -17
 	// LabelForSimpleTask represents the label of the simple task
 	LabelForSimpleTask int = -18
+	// LabelForHashAggWorker represents the label of the hash aggregation worker
+	LabelForHashAggWorker int = -19
 )
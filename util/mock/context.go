@@ -311,6 +311,26 @@ func (c *Context) HasLockedTables() bool {
 	return false
 }
 
+// GetAdvisoryLock implements the sessionctx.Context interface.
+func (c *Context) GetAdvisoryLock(_ string, _ int64) (bool, error) {
+	return true, nil
+}
+
+// ReleaseAdvisoryLock implements the sessionctx.Context interface.
+func (c *Context) ReleaseAdvisoryLock(_ string) (bool, error) {
+	return true, nil
+}
+
+// ReleaseAllAdvisoryLocks implements the sessionctx.Context interface.
+func (c *Context) ReleaseAllAdvisoryLocks() int {
+	return 0
+}
+
+// IsUsedAdvisoryLock implements the sessionctx.Context interface.
+func (c *Context) IsUsedAdvisoryLock(_ string) (uint64, bool, error) {
+	return 0, false, nil
+}
+
 // PrepareTSFuture implements the sessionctx.Context interface.
 func (c *Context) PrepareTSFuture(ctx context.Context) {
 }
@@ -120,6 +120,8 @@ const (
 	TypeIndexFullScan = "IndexFullScan"
 	// TypeIndexRangeScan is the type of IndexRangeScan.
 	TypeIndexRangeScan = "IndexRangeScan"
+	// TypeJSONTable is the type of JSONTable.
+	TypeJSONTable = "JSONTable"
 )
 
 // plan id.
@@ -174,6 +176,7 @@ const (
 	typeIndexRangeScan        int = 47
 	typeExchangeReceiver      int = 48
 	typeExchangeSender        int = 49
+	typeJSONTableID           int = 50
 )
 
 // TypeStringToPhysicalID converts the plan type string to plan id.
@@ -277,6 +280,8 @@ func TypeStringToPhysicalID(tp string) int {
 		return typeExchangeReceiver
 	case TypeExchangeSender:
 		return typeExchangeSender
+	case TypeJSONTable:
+		return typeJSONTableID
 	}
 	// Should never reach here.
 	return 0
@@ -381,6 +386,8 @@ func PhysicalIDToTypeString(id int) string {
 		return TypeExchangeReceiver
 	case typeExchangeSender:
 		return TypeExchangeSender
+	case typeJSONTableID:
+		return TypeJSONTable
 	}
 
 	// Should never reach here.
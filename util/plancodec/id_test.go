@@ -79,4 +79,5 @@ func (s *testPlanIDSuite) TestPlanIDChanged(c *C) {
 	c.Assert(typeIndexRangeScan, Equals, 47)
 	c.Assert(typeExchangeReceiver, Equals, 48)
 	c.Assert(typeExchangeSender, Equals, 49)
+	c.Assert(typeJSONTableID, Equals, 50)
 }
@@ -406,6 +406,67 @@ func (ssMap *stmtSummaryByDigestMap) GetMoreThanOnceBindableStmt() []*BindableSt
 	return stmts
 }
 
+// AdvisableStmt is a wrapper struct for a statement that is extracted from statements_summary and can be
+// used as the workload of the index advisor.
+type AdvisableStmt struct {
+	Schema     string
+	Digest     string
+	Query      string
+	ExecCount  int64
+	SumLatency time.Duration
+}
+
+// GetTopLatencyAdvisableStmt gets at most `limit` users' select/update/delete SQLs in the current interval,
+// which are sorted by the sum latency in descending order.
+// Prepared statements are skipped because their sample SQLs can't be optimized without the parameters.
+func (ssMap *stmtSummaryByDigestMap) GetTopLatencyAdvisableStmt(limit int, user *auth.UserIdentity, isSuper bool) []*AdvisableStmt {
+	ssMap.Lock()
+	values := ssMap.summaryMap.Values()
+	beginTime := ssMap.beginTimeForCurInterval
+	ssMap.Unlock()
+
+	stmts := make([]*AdvisableStmt, 0, len(values))
+	for _, value := range values {
+		ssbd := value.(*stmtSummaryByDigest)
+		if ssbd.stmtType != "Select" && ssbd.stmtType != "Delete" && ssbd.stmtType != "Update" {
+			continue
+		}
+		var ssElement *stmtSummaryByDigestElement
+		ssbd.Lock()
+		if ssbd.initialized && ssbd.history.Len() > 0 {
+			ssElement = ssbd.history.Back().Value.(*stmtSummaryByDigestElement)
+		}
+		ssbd.Unlock()
+		if ssElement == nil || ssElement.beginTime < beginTime {
+			continue
+		}
+
+		ssElement.Lock()
+		isAuthed := true
+		if user != nil && !isSuper {
+			_, isAuthed = ssElement.authUsers[user.Username]
+		}
+		// Empty auth users means that it is an internal queries.
+		if isAuthed && len(ssElement.authUsers) > 0 && !ssElement.prepared {
+			stmts = append(stmts, &AdvisableStmt{
+				Schema:     ssbd.schemaName,
+				Digest:     ssbd.digest,
+				Query:      ssElement.sampleSQL,
+				ExecCount:  ssElement.execCount,
+				SumLatency: ssElement.sumLatency,
+			})
+		}
+		ssElement.Unlock()
+	}
+	sort.Slice(stmts, func(i, j int) bool {
+		return stmts[i].SumLatency > stmts[j].SumLatency
+	})
+	if limit >= 0 && len(stmts) > limit {
+		stmts = stmts[:limit]
+	}
+	return stmts
+}
+
 // SetEnabled enables or disables statement summary in global(cluster) or session(server) scope.
 func (ssMap *stmtSummaryByDigestMap) SetEnabled(value string, inSession bool) error {
 	if err := ssMap.sysVars.setVariable(typeEnable, value, inSession); err != nil {
@@ -958,6 +958,53 @@ func (s *testStmtSummarySuite) TestGetMoreThanOnceBindableStmt(c *C) {
 	c.Assert(len(stmts), Equals, 1)
 }
 
+// Test GetTopLatencyAdvisableStmt.
+func (s *testStmtSummarySuite) TestGetTopLatencyAdvisableStmt(c *C) {
+	s.ssMap.Clear()
+	now := time.Now().Unix()
+	s.ssMap.beginTimeForCurInterval = now + 60
+
+	stmtExecInfo1 := generateAnyExecInfo()
+	stmtExecInfo1.StmtCtx.StmtType = "Insert"
+	s.ssMap.AddStatement(stmtExecInfo1)
+	stmts := s.ssMap.GetTopLatencyAdvisableStmt(10, nil, true)
+	c.Assert(len(stmts), Equals, 0)
+
+	stmtExecInfo2 := generateAnyExecInfo()
+	stmtExecInfo2.OriginalSQL = "select * from t where a = 1"
+	stmtExecInfo2.Digest = "digest2"
+	stmtExecInfo2.StmtCtx.StmtType = "Select"
+	stmtExecInfo2.TotalLatency = 1000
+	s.ssMap.AddStatement(stmtExecInfo2)
+	stmtExecInfo3 := generateAnyExecInfo()
+	stmtExecInfo3.OriginalSQL = "delete from t where b = 1"
+	stmtExecInfo3.Digest = "digest3"
+	stmtExecInfo3.StmtCtx.StmtType = "Delete"
+	stmtExecInfo3.TotalLatency = 2000
+	s.ssMap.AddStatement(stmtExecInfo3)
+	stmtExecInfo4 := generateAnyExecInfo()
+	stmtExecInfo4.Digest = "digest4"
+	stmtExecInfo4.StmtCtx.StmtType = "Select"
+	stmtExecInfo4.Prepared = true
+	s.ssMap.AddStatement(stmtExecInfo4)
+
+	stmts = s.ssMap.GetTopLatencyAdvisableStmt(10, nil, true)
+	c.Assert(len(stmts), Equals, 2)
+	c.Assert(stmts[0].Digest, Equals, "digest3")
+	c.Assert(stmts[0].Query, Equals, "delete from t where b = 1")
+	c.Assert(stmts[0].ExecCount, Equals, int64(1))
+	c.Assert(stmts[1].Digest, Equals, "digest2")
+	stmts = s.ssMap.GetTopLatencyAdvisableStmt(1, nil, true)
+	c.Assert(len(stmts), Equals, 1)
+	c.Assert(stmts[0].Digest, Equals, "digest3")
+
+	// The statements of other users are invisible.
+	stmts = s.ssMap.GetTopLatencyAdvisableStmt(10, &auth.UserIdentity{Username: "other"}, false)
+	c.Assert(len(stmts), Equals, 0)
+	stmts = s.ssMap.GetTopLatencyAdvisableStmt(10, &auth.UserIdentity{Username: "user"}, false)
+	c.Assert(len(stmts), Equals, 2)
+}
+
 // Test `formatBackoffTypes`.
 func (s *testStmtSummarySuite) TestFormatBackoffTypes(c *C) {
 	backoffMap := make(map[string]int)